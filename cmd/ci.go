@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/ci"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/spf13/cobra"
+)
+
+func createCICmd(execer fakeruntime.Execer) (cmd *cobra.Command) {
+	opt := &ciOption{runOption: newDefaultRunOption()}
+	opt.execer = execer
+	cmd = &cobra.Command{
+		Use:   "ci",
+		Short: "Run the test suites in CI pipelines, and gate the pipeline with the results",
+		Long: `Run the test suites in CI pipelines, and gate the pipeline with the results.
+It writes a summary file, and the outputs of the CI provider. The exit codes are:
+  0 all the gates are passed
+  1 some of the test cases are failed
+  2 some of the APIs breach the SLO
+  3 the API coverage is lower than expected`,
+		Example:      `atest ci -p sample.yaml --max-latency 500ms --swagger-url https://foo/swagger.json --min-coverage 80`,
+		SilenceUsage: true,
+		PreRunE:      opt.preRunE,
+		RunE:         opt.runE,
+	}
+
+	opt.addFlags(cmd)
+	registerSuiteCompletion(cmd)
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.summaryFile, "summary-file", "", "atest-summary.json", "The file path of the summary")
+	flags.StringVarP(&opt.provider, "provider", "", ci.ProviderAuto, "The CI provider. Supported: auto, github, gitlab, jenkins, none")
+	flags.StringVarP(&opt.outputDir, "output-dir", "", ".", "The directory of the provider-specific outputs, such as the JUnit report")
+	flags.DurationVarP(&opt.gate.MaxLatency, "max-latency", "", 0, "The max duration of a single request")
+	flags.DurationVarP(&opt.gate.MaxAverage, "max-average", "", 0, "The max average duration of an API")
+	flags.Float64VarP(&opt.gate.MaxErrorRate, "max-error-rate", "", 0, "The max error rate of an API, from 0 to 1")
+	return
+}
+
+type ciOption struct {
+	*runOption
+	summaryFile string
+	provider    string
+	outputDir   string
+	gate        ci.Gate
+
+	ciProvider ci.Provider
+}
+
+func (o *ciOption) preRunE(cmd *cobra.Command, args []string) (err error) {
+	if o.ciProvider, err = ci.NewProvider(o.provider, o.outputDir, cmd.OutOrStdout()); err == nil {
+		err = o.runOption.preRunE(cmd, args)
+	}
+	return
+}
+
+func (o *ciOption) runE(cmd *cobra.Command, args []string) (err error) {
+	// run all the test cases to get the full summary
+	o.requestIgnoreError = true
+	// the coverage is a gate of the summary instead of an error of the run
+	o.gate.MinCoverage, o.minCoverage = o.minCoverage, 0
+	if err = o.runOption.runE(cmd, args); err != nil {
+		return
+	}
+
+	var results runner.ReportResultSlice
+	if results, err = o.reporter.ExportAllReportResults(); err != nil {
+		return
+	}
+
+	summary := ci.NewSummary(results, o.apiConverage, o.gate, time.Since(o.startTime))
+	if err = summary.WriteFile(o.summaryFile); err != nil {
+		return
+	}
+	if err = o.ciProvider.Write(summary); err != nil {
+		return
+	}
+
+	cmd.Printf("status: %s, passed: %d, failed: %d, breaches: %d\n",
+		summary.Status, summary.Passed, summary.Failed, len(summary.Breaches))
+	err = summary.Err()
+	return
+}
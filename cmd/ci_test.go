@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/ci"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCICmd(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		prepare  func()
+		exitCode int
+		hasErr   bool
+	}{{
+		name: "passed",
+		args: []string{"-p", simpleSuite},
+		prepare: func() {
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON("{}")
+		},
+	}, {
+		name: "failed",
+		args: []string{"-p", simpleSuite},
+		prepare: func() {
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusBadRequest).JSON("{}")
+		},
+		hasErr:   true,
+		exitCode: ci.ExitCodeFailed,
+	}, {
+		name: "SLO breached",
+		args: []string{"-p", simpleSuite, "--max-latency", "1ns"},
+		prepare: func() {
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON("{}")
+		},
+		hasErr:   true,
+		exitCode: ci.ExitCodeSLOBreached,
+	}, {
+		name:   "not supported provider",
+		args:   []string{"-p", simpleSuite, "--provider", "fake"},
+		hasErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Clean()
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+			summaryFile := path.Join(t.TempDir(), "summary.json")
+
+			root := &cobra.Command{Use: "root"}
+			root.SetOut(new(bytes.Buffer))
+			root.AddCommand(createCICmd(fakeruntime.FakeExecer{}))
+			root.SetArgs(append([]string{"ci", "--provider", "none", "--summary-file", summaryFile}, tt.args...))
+
+			err := root.Execute()
+			assert.Equal(t, tt.hasErr, err != nil, err)
+
+			var gateErr *ci.GateError
+			if errors.As(err, &gateErr) {
+				assert.Equal(t, tt.exitCode, gateErr.ExitCode())
+			}
+
+			if tt.prepare != nil {
+				data, readErr := os.ReadFile(summaryFile)
+				assert.Nil(t, readErr)
+
+				summary := &ci.Summary{}
+				assert.Nil(t, json.Unmarshal(data, summary))
+				assert.Equal(t, tt.exitCode, summary.ExitCode)
+				assert.Equal(t, 1, summary.Total)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/spf13/cobra"
+)
+
+// registerSuiteCompletion completes the test suite files of the pattern flag, and the test case names of the arguments
+func registerSuiteCompletion(cmd *cobra.Command) {
+	cmd.ValidArgsFunction = completeCaseNames
+	_ = cmd.RegisterFlagCompletionFunc("pattern", completeSuiteFiles)
+}
+
+// completeSuiteFiles returns the YAML files which are test suites, and the directories
+func completeSuiteFiles(cmd *cobra.Command, args []string, toComplete string) (suggestions []string, directive cobra.ShellCompDirective) {
+	directive = cobra.ShellCompDirectiveNoFileComp
+
+	dir, prefix := filepath.Split(toComplete)
+	entries, err := os.ReadDir(emptyThenDefault(dir, "."))
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		if entry.IsDir() {
+			suggestions = append(suggestions, dir+name+"/")
+			directive |= cobra.ShellCompDirectiveNoSpace
+		} else if ext := filepath.Ext(name); ext == ".yaml" || ext == ".yml" {
+			if data, readErr := os.ReadFile(filepath.Join(dir, name)); readErr == nil {
+				if suiteName, _, parseErr := testing.ParseNames(data); parseErr == nil && suiteName != "" {
+					suggestions = append(suggestions, dir+name+"\t"+suiteName)
+				}
+			}
+		}
+	}
+	return
+}
+
+// completeCaseNames returns the test case names of the suites which match the pattern flag
+func completeCaseNames(cmd *cobra.Command, args []string, toComplete string) (suggestions []string, directive cobra.ShellCompDirective) {
+	directive = cobra.ShellCompDirectiveNoFileComp
+
+	pattern, err := cmd.Flags().GetString("pattern")
+	if err != nil {
+		return
+	}
+
+	loader := testing.NewFileLoader()
+	if err = loader.Put(pattern); err != nil {
+		return
+	}
+
+	existing := map[string]struct{}{}
+	for _, arg := range args {
+		existing[arg] = struct{}{}
+	}
+
+	for loader.HasMore() {
+		data, loadErr := loader.Load()
+		if loadErr != nil {
+			continue
+		}
+
+		suiteName, caseNames, parseErr := testing.ParseNames(data)
+		if parseErr != nil {
+			continue
+		}
+
+		for _, name := range caseNames {
+			if _, ok := existing[name]; ok || !strings.HasPrefix(name, toComplete) {
+				continue
+			}
+			existing[name] = struct{}{}
+			suggestions = append(suggestions, name+"\t"+suiteName)
+		}
+	}
+	return
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuiteCompletion(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expect   []string
+		unexpect []string
+	}{{
+		name:   "case names",
+		args:   []string{"run", "-p", simpleSuite, ""},
+		expect: []string{"bar\tSimple"},
+	}, {
+		name:     "case names with existing args",
+		args:     []string{"run", "-p", simpleSuite, "bar", ""},
+		unexpect: []string{"bar"},
+	}, {
+		name:     "case names with prefix",
+		args:     []string{"healthcheck", "-p", simpleSuite, "fake"},
+		unexpect: []string{"bar"},
+	}, {
+		name:   "suite files",
+		args:   []string{"run", "-p", "testdata/"},
+		expect: []string{"testdata/simple-suite.yaml\tSimple"},
+	}, {
+		name:   "directories",
+		args:   []string{"ci", "-p", "test"},
+		expect: []string{"testdata/"},
+	}, {
+		name:     "not existing directory",
+		args:     []string{"console", "-p", "fake/"},
+		unexpect: []string{"fake"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			root := NewRootCmd(fakeruntime.FakeExecer{}, NewFakeGRPCServer())
+			root.SetOut(buf)
+			root.SetArgs(append([]string{"__complete"}, tt.args...))
+
+			err := root.Execute()
+			assert.Nil(t, err)
+			for _, item := range tt.expect {
+				assert.Contains(t, buf.String(), item)
+			}
+			for _, item := range tt.unexpect {
+				assert.NotContains(t, buf.String(), item)
+			}
+		})
+	}
+}
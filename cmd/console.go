@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/render"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/spf13/cobra"
+)
+
+type consoleOption struct {
+	suiteFile      string
+	level          string
+	requestTimeout time.Duration
+
+	suite       *testing.TestSuite
+	contextDir  string
+	dataContext map[string]interface{}
+	reporter    runner.TestReporter
+}
+
+func createConsoleCmd() (c *cobra.Command) {
+	opt := &consoleOption{}
+	c = &cobra.Command{
+		Use:     "console",
+		Aliases: []string{"repl"},
+		Short:   "Start an interactive console to send the requests of a test suite",
+		Example: `atest console -p sample/testsuite-gitlab.yaml
+> run projects
+> show`,
+		RunE: opt.runE,
+	}
+	flags := c.Flags()
+	flags.StringVarP(&opt.suiteFile, "pattern", "p", "", "The test suite file to load")
+	flags.StringVarP(&opt.level, "level", "l", "info", "Set the output log level, such as: trace, debug, info, warn, error. The components runner, prepare and reporter could have their own levels, and \"json\" is the format, such as: info,prepare=debug,json")
+	flags.DurationVarP(&opt.requestTimeout, "request-timeout", "", time.Minute, "Timeout for per request")
+	_ = c.RegisterFlagCompletionFunc("pattern", completeSuiteFiles)
+	return
+}
+
+func (o *consoleOption) runE(cmd *cobra.Command, args []string) (err error) {
+	o.dataContext = getDefaultContext()
+	o.reporter = runner.NewMemoryTestReporter()
+	if o.suiteFile != "" {
+		if err = o.load(o.suiteFile); err != nil {
+			return
+		}
+		cmd.Printf("loaded suite: %s, %d test cases\n", o.suite.Name, len(o.suite.Items))
+	}
+
+	scanner := bufio.NewScanner(cmd.InOrStdin())
+	for {
+		cmd.Print("> ")
+		if !scanner.Scan() {
+			break
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "exit" || fields[0] == "quit" {
+			break
+		}
+
+		if cmdErr := o.execute(cmd, fields[0], fields[1:]); cmdErr != nil {
+			cmd.Println("error:", cmdErr)
+		}
+	}
+	err = scanner.Err()
+	return
+}
+
+func (o *consoleOption) execute(cmd *cobra.Command, action string, args []string) (err error) {
+	switch action {
+	case "help":
+		cmd.Println(consoleHelp)
+	case "load":
+		if len(args) != 1 {
+			err = fmt.Errorf("usage: load <file>")
+		} else if err = o.load(args[0]); err == nil {
+			cmd.Printf("loaded suite: %s, %d test cases\n", o.suite.Name, len(o.suite.Items))
+		}
+	case "list", "ls":
+		if err = o.checkSuite(); err == nil {
+			for _, item := range o.suite.Items {
+				cmd.Printf("%s\t%s %s\n", item.Name, testing.EmptyThenDefault(item.Request.Method, "GET"), item.Request.API)
+			}
+		}
+	case "run":
+		if len(args) != 1 {
+			err = fmt.Errorf("usage: run <case name>")
+		} else {
+			err = o.run(cmd, args[0])
+		}
+	case "set":
+		if len(args) < 2 {
+			err = fmt.Errorf("usage: set <key> <value>")
+		} else {
+			o.dataContext[args[0]] = strings.Join(args[1:], " ")
+		}
+	case "unset":
+		for _, key := range args {
+			delete(o.dataContext, key)
+		}
+	case "vars":
+		var keys []string
+		for key := range o.dataContext {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			cmd.Printf("%s: %v\n", key, o.dataContext[key])
+		}
+	case "show":
+		records := o.reporter.GetAllRecords()
+		if len(records) == 0 {
+			err = fmt.Errorf("no response yet")
+		} else {
+			last := records[len(records)-1]
+			cmd.Printf("%s %s, consume: %s\n", last.Method, last.API, last.Duration())
+			cmd.Println(prettyJSON(last.Body))
+		}
+	default:
+		err = fmt.Errorf("unknown command '%s', type help to see all the commands", action)
+	}
+	return
+}
+
+func (o *consoleOption) load(file string) (err error) {
+	var data []byte
+	if data, err = os.ReadFile(file); err != nil {
+		return
+	}
+
+	var suite *testing.TestSuite
+	if suite, err = testing.Parse(data); err != nil {
+		return
+	}
+
+	var result string
+	if result, err = render.Render("base api", suite.API, o.dataContext); err == nil {
+		suite.API = strings.TrimSuffix(result, "/")
+		o.suite = suite
+		o.contextDir = path.Dir(file)
+	}
+	return
+}
+
+func (o *consoleOption) run(cmd *cobra.Command, name string) (err error) {
+	if err = o.checkSuite(); err != nil {
+		return
+	}
+
+	var testCase *testing.TestCase
+	for i := range o.suite.Items {
+		if o.suite.Items[i].Name == name {
+			item := o.suite.Items[i]
+			testCase = &item
+			break
+		}
+	}
+	if testCase == nil {
+		err = fmt.Errorf("cannot find test case '%s'", name)
+		return
+	}
+
+	o.suite.Inherit(testCase)
+
+	ctx := runner.WithRequestTimeout(cmd.Context(), o.requestTimeout)
+	ctx = context.WithValue(ctx, runner.NewContextKeyBuilder().ParentDir(), o.contextDir)
+
+	simpleRunner := runner.NewSimpleTestCaseRunner()
+	simpleRunner.WithOutputWriter(cmd.OutOrStdout())
+	simpleRunner.WithWriteLevel(o.level)
+	simpleRunner.WithTestReporter(o.reporter)
+
+	var output interface{}
+	if output, err = simpleRunner.RunTestCase(testCase, o.dataContext, ctx); err == nil {
+		o.dataContext[testCase.Name] = output
+		cmd.Println("passed")
+	}
+	return
+}
+
+func (o *consoleOption) checkSuite() (err error) {
+	if o.suite == nil {
+		err = fmt.Errorf("no test suite loaded, please load it via: load <file>")
+	}
+	return
+}
+
+func prettyJSON(text string) string {
+	var data interface{}
+	if err := json.Unmarshal([]byte(text), &data); err == nil {
+		if result, err := json.MarshalIndent(data, "", "  "); err == nil {
+			return string(result)
+		}
+	}
+	return text
+}
+
+const consoleHelp = `Available commands:
+  load <file>         Load a test suite file
+  list                List all the test cases of the suite
+  run <case>          Run a test case, the output will be put into the context with the case name
+  set <key> <value>   Set a variable into the context
+  unset <key>         Remove variables from the context
+  vars                Print all the variables of the context
+  show                Show the last response
+  exit                Exit the console`
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/h2non/gock"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsole(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		input   string
+		prepare func()
+		verify  func(*testing.T, string, error)
+	}{{
+		name:  "no suite loaded",
+		args:  []string{"console"},
+		input: "list\nrun bar\nshow\nfake\n\nhelp\nexit\n",
+		verify: func(t *testing.T, output string, err error) {
+			assert.Nil(t, err)
+			assert.Contains(t, output, "no test suite loaded")
+			assert.Contains(t, output, "no response yet")
+			assert.Contains(t, output, "unknown command 'fake'")
+			assert.Contains(t, output, "Available commands")
+		},
+	}, {
+		name:  "run a test case",
+		args:  []string{"console", "-p", simpleSuite},
+		input: "list\nset name foo bar\nvars\nrun bar\nshow\nrun fake\nunset name\n",
+		prepare: func() {
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON(`{"name":"linuxsuren"}`)
+		},
+		verify: func(t *testing.T, output string, err error) {
+			assert.Nil(t, err)
+			assert.Contains(t, output, "bar\tGET /bar")
+			assert.Contains(t, output, "name: foo bar")
+			assert.Contains(t, output, "passed")
+			assert.Contains(t, output, `"name": "linuxsuren"`)
+			assert.Contains(t, output, "cannot find test case 'fake'")
+		},
+	}, {
+		name:  "load a suite",
+		args:  []string{"console"},
+		input: "load\nload fake.yaml\nload " + simpleSuite + "\n",
+		verify: func(t *testing.T, output string, err error) {
+			assert.Nil(t, err)
+			assert.Contains(t, output, "usage: load <file>")
+			assert.Contains(t, output, "loaded suite: Simple")
+		},
+	}, {
+		name: "suite file not found",
+		args: []string{"console", "-p", "fake.yaml"},
+		verify: func(t *testing.T, output string, err error) {
+			assert.NotNil(t, err)
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Clean()
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+
+			buf := new(bytes.Buffer)
+			root := NewRootCmd(fakeruntime.FakeExecer{ExpectOS: "linux"}, NewFakeGRPCServer())
+			root.SetOut(buf)
+			root.SetIn(strings.NewReader(tt.input))
+			root.SetArgs(tt.args)
+			err := root.Execute()
+			tt.verify(t, buf.String(), err)
+		})
+	}
+}
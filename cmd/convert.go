@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/converter"
+	"github.com/spf13/cobra"
+)
+
+func createConvertCmd() (c *cobra.Command) {
+	opt := &convertOption{registry: converter.NewDefaultRegistry()}
+	formats := strings.Join(opt.registry.Names(), ", ")
+	c = &cobra.Command{
+		Use:   "convert [file]",
+		Short: "Convert the test suites between different formats",
+		Long: fmt.Sprintf(`Convert the test suites between different formats, read from the stdin if the file is absent or '-'.
+Supported formats: %s`, formats),
+		Example: `atest convert --from postman collection.json -o suite.yaml
+atest convert --to curl suite.yaml
+cat sample.har | atest convert --to jmeter`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: opt.runE,
+	}
+	flags := c.Flags()
+	flags.StringVarP(&opt.from, "from", "f", "", "The format of the source, detect it from the content if it's empty. Supported: "+formats)
+	flags.StringVarP(&opt.to, "to", "t", "yaml", "The format of the target. Supported: "+formats)
+	flags.StringVarP(&opt.output, "output", "o", "", "The output file, print it if it's empty")
+
+	completeFormats := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return opt.registry.Names(), cobra.ShellCompDirectiveNoFileComp
+	}
+	_ = c.RegisterFlagCompletionFunc("from", completeFormats)
+	_ = c.RegisterFlagCompletionFunc("to", completeFormats)
+	return
+}
+
+type convertOption struct {
+	from     string
+	to       string
+	output   string
+	registry converter.Registry
+}
+
+func (o *convertOption) runE(cmd *cobra.Command, args []string) (err error) {
+	var data []byte
+	if len(args) == 0 || args[0] == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return
+	}
+
+	var result []byte
+	if result, err = o.registry.Convert(o.from, o.to, data); err != nil {
+		return
+	}
+
+	if o.output == "" {
+		_, err = cmd.OutOrStdout().Write(result)
+	} else {
+		err = os.WriteFile(o.output, result, 0644)
+	}
+	return
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertCmd(t *testing.T) {
+	c := createConvertCmd()
+	buf := new(bytes.Buffer)
+	c.SetOut(buf)
+	c.SetArgs([]string{"--to", "curl", simpleSuite})
+	err := c.Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, "# bar\ncurl -X GET 'http://foo/bar'\n", buf.String())
+
+	// read from stdin, and detect the format
+	output := path.Join(t.TempDir(), "suite.yaml")
+	c = createConvertCmd()
+	c.SetIn(strings.NewReader("curl http://foo/bar"))
+	c.SetArgs([]string{"-o", output})
+	err = c.Execute()
+	assert.Nil(t, err)
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "api: http://foo/bar")
+
+	c = createConvertCmd()
+	c.SetOut(new(bytes.Buffer))
+	c.SetArgs([]string{"--to", "fake", simpleSuite})
+	err = c.Execute()
+	assert.NotNil(t, err)
+
+	c = createConvertCmd()
+	c.SetOut(new(bytes.Buffer))
+	c.SetArgs([]string{"fake.yaml"})
+	err = c.Execute()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,8 @@
+package cmd
+
+// the drivers of the databases, they're used by the SQL steps of the prepare and clean, the SQL verification and
+// the database store. The SQLite driver requires cgo, see drivers_sqlite.go
+import (
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
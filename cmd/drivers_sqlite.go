@@ -0,0 +1,6 @@
+//go:build sqlite
+
+package cmd
+
+// the SQLite driver requires cgo, build the binary with it via: make build-sqlite
+import _ "github.com/mattn/go-sqlite3"
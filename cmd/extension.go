@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/extension"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/linuxsuren/api-testing/pkg/store"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+)
+
+const extensionStorePrefix = "ext://"
+
+// getStore returns the store by the URI, the store comes from an extension if the URI starts with ext://
+func getStore(uri string, execer fakeruntime.Execer, extensions extension.Manager) (s store.Store, err error) {
+	if strings.HasPrefix(uri, extensionStorePrefix) {
+		name := strings.TrimPrefix(uri, extensionStorePrefix)
+		ok := false
+		if extensions != nil {
+			s, ok = extensions.GetStore(name)
+		}
+		if !ok {
+			err = fmt.Errorf("cannot find the store extension '%s'", name)
+		}
+		return
+	}
+	return store.NewStore(uri, execer)
+}
+
+// getTestCaseRunner returns the runner from the extensions if the protocol of the API is supported by one of them
+func getTestCaseRunner(testCase *testing.TestCase, extensions extension.Manager) (caseRunner runner.TestCaseRunner) {
+	if extensions != nil {
+		if api, err := url.Parse(testCase.Request.API); err == nil && api.Scheme != "" {
+			caseRunner, _ = extensions.GetRunner(api.Scheme)
+		}
+	}
+
+	if caseRunner == nil {
+		caseRunner = runner.NewSimpleTestCaseRunner()
+	}
+	return
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/extension"
+	"github.com/linuxsuren/api-testing/pkg/extension/extensiontest"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStoreAndRunner(t *testing.T) {
+	client, closer := extensiontest.NewFakeClient(context.Background(), extensiontest.NewFakeExtension(&extension.Info{
+		Name:      "fake",
+		Kinds:     []string{extension.KindStore, extension.KindRunner},
+		Protocols: []string{"mqtt"},
+	}))
+	defer closer()
+
+	extensions := extension.NewManager()
+	err := extensions.Register(client)
+	assert.Nil(t, err)
+
+	s, err := getStore("ext://fake", fakeruntime.FakeExecer{}, extensions)
+	assert.Nil(t, err)
+	assert.NotNil(t, s)
+
+	_, err = getStore("ext://fake", fakeruntime.FakeExecer{}, nil)
+	assert.NotNil(t, err)
+
+	_, err = getStore("ext://not-exist", fakeruntime.FakeExecer{}, extensions)
+	assert.NotNil(t, err)
+
+	s, err = getStore("testdata", fakeruntime.FakeExecer{}, extensions)
+	assert.Nil(t, err)
+	assert.NotNil(t, s)
+
+	mqttCase := &atest.TestCase{Request: atest.Request{API: "mqtt://localhost"}}
+	httpCase := &atest.TestCase{Request: atest.Request{API: "http://localhost"}}
+	assert.NotEqual(t, getTestCaseRunner(mqttCase, extensions), getTestCaseRunner(httpCase, extensions))
+	assert.NotNil(t, getTestCaseRunner(mqttCase, nil))
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/linuxsuren/api-testing/pkg/converter"
+	"github.com/linuxsuren/api-testing/pkg/fuzz"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/spf13/cobra"
+)
+
+func createFuzzCmd() (c *cobra.Command) {
+	opt := &fuzzOption{}
+	c = &cobra.Command{
+		Use:   "fuzz",
+		Short: "Fuzz the APIs of an OpenAPI document, and assert the server never returns 5xx",
+		Long: `Fuzz the APIs of an OpenAPI document with the inputs which are generated from the types of the parameters and the request bodies,
+such as the boundary values, the invalid types, and the oversized strings. The server errors (5xx) and the requests without response are failed.
+The inputs are the same for the same document, and the failing ones could be written as the curl commands to reproduce them.`,
+		Example: `atest fuzz --spec openapi.yaml --base-url http://localhost:8080
+atest fuzz --spec http://localhost:8080/openapi.json --base-url http://localhost:8080 -H 'Authorization: Bearer token' --include '^POST /users' --output failures.sh`,
+		SilenceUsage: true,
+		PreRunE:      opt.preRunE,
+		RunE:         opt.runE,
+	}
+	flags := c.Flags()
+	flags.StringVarP(&opt.spec, "spec", "s", "", "The file or the URL of the OpenAPI v3 or Swagger v2 document")
+	flags.StringVarP(&opt.baseURL, "base-url", "", "", "The base URL of the APIs, such as: http://localhost:8080")
+	flags.StringArrayVarP(&opt.headers, "header", "H", nil, "The headers of all the requests, such as: 'Authorization: Bearer token'")
+	flags.StringVarP(&opt.include, "include", "", "", "The regular expression of the operations which are fuzzed, such as: ^POST /users")
+	flags.StringVarP(&opt.output, "output", "o", "", "The file which the failing inputs are written into as the curl commands")
+	flags.DurationVarP(&opt.timeout, "timeout", "", 30*time.Second, "The timeout of each request")
+	_ = c.MarkFlagRequired("spec")
+	_ = c.MarkFlagRequired("base-url")
+	return
+}
+
+type fuzzOption struct {
+	spec    string
+	baseURL string
+	headers []string
+	include string
+	output  string
+	timeout time.Duration
+
+	includeReg *regexp.Regexp
+	header     map[string]string
+}
+
+func (o *fuzzOption) preRunE(cmd *cobra.Command, args []string) (err error) {
+	if o.include != "" {
+		if o.includeReg, err = regexp.Compile(o.include); err != nil {
+			err = fmt.Errorf("invalid include pattern %q: %v", o.include, err)
+			return
+		}
+	}
+
+	o.header = map[string]string{}
+	for _, header := range o.headers {
+		pair := strings.SplitN(header, ":", 2)
+		if len(pair) != 2 || strings.TrimSpace(pair[0]) == "" {
+			err = fmt.Errorf("invalid header %q, it should be like 'key: value'", header)
+			return
+		}
+		o.header[strings.TrimSpace(pair[0])] = strings.TrimSpace(pair[1])
+	}
+	return
+}
+
+func (o *fuzzOption) runE(cmd *cobra.Command, args []string) (err error) {
+	var spec *apispec.OpenAPI
+	if spec, err = apispec.LoadOpenAPI(o.spec, ""); err != nil {
+		return
+	}
+
+	client := &http.Client{Timeout: o.timeout}
+	failures := &testing.TestSuite{Name: "fuzz", API: strings.TrimSuffix(o.baseURL, "/")}
+	var total int
+	for _, operation := range spec.Operations() {
+		if o.includeReg != nil && !o.includeReg.MatchString(operation.Method+" "+operation.Path) {
+			continue
+		}
+
+		for _, input := range fuzz.NewInputs(operation) {
+			total++
+			for key, val := range o.header {
+				input.Header[key] = val
+			}
+
+			var result string
+			if result, err = o.send(cmd.Context(), client, input); err != nil {
+				return
+			} else if result != "" {
+				name := fmt.Sprintf("%s %s: %s, %s", input.Method, input.Path, input.Description, result)
+				cmd.Println(name)
+				failures.Items = append(failures.Items, testing.TestCase{
+					Name: name,
+					Request: testing.Request{
+						API:    input.Path,
+						Method: input.Method,
+						Query:  input.Query,
+						Header: input.Header,
+						Body:   input.Body,
+					},
+				})
+			}
+		}
+	}
+
+	if len(failures.Items) > 0 && o.output != "" {
+		var data []byte
+		if data, err = converter.NewCurlConverter().Export(failures); err == nil {
+			err = os.WriteFile(o.output, data, 0644)
+		}
+		if err != nil {
+			return
+		}
+		cmd.Println("the failing inputs are written into", o.output)
+	}
+
+	if len(failures.Items) > 0 {
+		err = fmt.Errorf("fuzz: %d of %d inputs are failed", len(failures.Items), total)
+	} else {
+		cmd.Printf("fuzz: %d inputs are passed\n", total)
+	}
+	return
+}
+
+// send sends the input, it returns the reason if the response is a server error or there is no response
+func (o *fuzzOption) send(ctx context.Context, client *http.Client, input fuzz.Input) (result string, err error) {
+	api := strings.TrimSuffix(o.baseURL, "/") + input.Path
+	if len(input.Query) > 0 {
+		values := url.Values{}
+		for key, val := range input.Query {
+			values.Set(key, val)
+		}
+		api += "?" + values.Encode()
+	}
+
+	var request *http.Request
+	if request, err = http.NewRequestWithContext(ctx, input.Method, api, strings.NewReader(input.Body)); err != nil {
+		return
+	}
+	for key, val := range input.Header {
+		request.Header.Set(key, val)
+	}
+
+	resp, reqErr := client.Do(request)
+	if reqErr != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		} else {
+			result = fmt.Sprintf("no response: %v", reqErr)
+		}
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		result = fmt.Sprintf("status code %d", resp.StatusCode)
+	}
+	return
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzCmd(t *testing.T) {
+	const spec = "testdata/fuzz-openapi.yaml"
+	fuzz := func(args ...string) (output string, err error) {
+		c := createFuzzCmd()
+		buf := new(bytes.Buffer)
+		c.SetOut(buf)
+		c.SetErr(buf)
+		c.SetArgs(args)
+		err = c.Execute()
+		output = buf.String()
+		return
+	}
+
+	t.Run("the server errors are failed", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Post("/users").Persist().
+			AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+				data, err := io.ReadAll(req.Body)
+				req.Body = io.NopCloser(bytes.NewReader(data))
+				return strings.Contains(string(data), "DROP TABLE"), err
+			}).
+			Reply(http.StatusInternalServerError)
+		gock.New(urlFoo).Post("/users").MatchHeader("Authorization", "Bearer token").Persist().Reply(http.StatusBadRequest)
+
+		file := filepath.Join(t.TempDir(), "failures.sh")
+		output, err := fuzz("--spec", spec, "--base-url", urlFoo+"/", "-H", "Authorization: Bearer token",
+			"--include", "^POST", "--output", file)
+		assert.ErrorContains(t, err, "fuzz: 1 of ")
+		assert.Contains(t, output, `POST /users: injection "\"; DROP TABLE users; --" at $.name of the body, status code 500`)
+
+		data, readErr := os.ReadFile(file)
+		assert.NoError(t, readErr)
+		assert.Contains(t, string(data), "curl -X POST 'http://foo/users'")
+		assert.Contains(t, string(data), "-H 'Authorization: Bearer token'")
+		assert.Contains(t, string(data), "DROP TABLE users")
+	})
+
+	t.Run("no server errors", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Post("/users").Persist().Reply(http.StatusBadRequest)
+
+		output, err := fuzz("--spec", spec, "--base-url", urlFoo, "--include", "^POST /users$")
+		assert.NoError(t, err)
+		assert.Regexp(t, `fuzz: \d+ inputs are passed`, output)
+	})
+
+	t.Run("no operations are included", func(t *testing.T) {
+		output, err := fuzz("--spec", spec, "--base-url", urlFoo, "--include", "^PUT")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "fuzz: 0 inputs are passed")
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Post("/users").Persist().ReplyError(io.ErrUnexpectedEOF)
+
+		output, err := fuzz("--spec", spec, "--base-url", urlFoo, "--include", "^POST")
+		assert.Error(t, err)
+		assert.Contains(t, output, "no response: ")
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		_, err := fuzz("--spec", spec, "--base-url", urlFoo, "--include", "(")
+		assert.ErrorContains(t, err, "invalid include pattern")
+
+		_, err = fuzz("--spec", spec, "--base-url", urlFoo, "-H", "fake")
+		assert.ErrorContains(t, err, "invalid header")
+
+		_, err = fuzz("--spec", "testdata/fake.yaml", "--base-url", urlFoo)
+		assert.Error(t, err)
+
+		_, err = fuzz("--spec", spec)
+		assert.Error(t, err, "the base URL is required")
+	})
+}
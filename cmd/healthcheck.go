@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/render"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/spf13/cobra"
+)
+
+func createHealthCheckCmd() (c *cobra.Command) {
+	opt := &healthCheckOption{}
+	c = &cobra.Command{
+		Use:     "healthcheck [case...]",
+		Aliases: []string{"probe"},
+		Short:   "Run a subset of the test cases as a health check or readiness gate",
+		Long: `Run a subset of the test cases with a strict time budget and terse output.
+It's designed for the Docker HEALTHCHECK or the Kubernetes initContainers, exit with non-zero if any case fails.`,
+		Example: `atest healthcheck -p sample.yaml --timeout 5s ping
+atest healthcheck -p sample.yaml --wait --timeout 2m ping`,
+		SilenceUsage: true,
+		RunE:         opt.runE,
+	}
+	flags := c.Flags()
+	flags.StringVarP(&opt.pattern, "pattern", "p", "test-suite-*.yaml", "The file pattern of the test suites")
+	flags.DurationVarP(&opt.timeout, "timeout", "", 10*time.Second, "The time budget of the whole check")
+	flags.BoolVarP(&opt.wait, "wait", "", false, "Retry until all the cases pass or the time budget is exhausted, it's useful for initContainers")
+	flags.DurationVarP(&opt.interval, "interval", "", 2*time.Second, "The interval between the retries")
+	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Print nothing if all the cases pass")
+	registerSuiteCompletion(c)
+	return
+}
+
+type healthCheckOption struct {
+	pattern  string
+	timeout  time.Duration
+	wait     bool
+	interval time.Duration
+	quiet    bool
+}
+
+func (o *healthCheckOption) runE(cmd *cobra.Command, args []string) (err error) {
+	ctx, cancel := context.WithTimeout(cmd.Context(), o.timeout)
+	defer cancel()
+
+	begin := time.Now()
+	var count int
+	count, err = o.check(ctx, args)
+	for err != nil && o.wait && o.sleep(ctx) {
+		count, err = o.check(ctx, args)
+	}
+
+	if err != nil {
+		cmd.Println("FAIL", err)
+	} else if !o.quiet {
+		cmd.Printf("OK %d cases in %s\n", count, time.Since(begin).Round(time.Millisecond))
+	}
+	return
+}
+
+// sleep waits for the interval, returns false if the time budget is exhausted
+func (o *healthCheckOption) sleep(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(o.interval):
+		return true
+	}
+}
+
+// check runs the cases which are in scope, returns the first error
+func (o *healthCheckOption) check(ctx context.Context, caseItems []string) (count int, err error) {
+	loader := testing.NewFileLoader()
+	if err = loader.Put(o.pattern); err != nil {
+		return
+	}
+	if loader.GetCount() == 0 {
+		err = fmt.Errorf("no test suites found by '%s'", o.pattern)
+		return
+	}
+
+	for loader.HasMore() {
+		var data []byte
+		if data, err = loader.Load(); err != nil {
+			return
+		}
+
+		var suite *testing.TestSuite
+		if suite, err = testing.Parse(data); err != nil {
+			return
+		}
+
+		dataContext := getDefaultContext()
+		if suite.API, err = render.Render("base api", suite.API, dataContext); err != nil {
+			return
+		}
+		suite.API = strings.TrimSuffix(suite.API, "/")
+
+		for _, testCase := range suite.Items {
+			if !testCase.InScope(caseItems) {
+				continue
+			}
+
+			suite.Inherit(&testCase)
+
+			caseCtx := context.WithValue(ctx, runner.ContextKey("").ParentDir(), loader.GetContext())
+			var output interface{}
+			if output, err = runner.NewSimpleTestCaseRunner().RunTestCase(&testCase, dataContext, caseCtx); err != nil {
+				err = fmt.Errorf("%s/%s: %v", suite.Name, testCase.Name, err)
+				return
+			}
+			dataContext[testCase.Name] = output
+			count++
+		}
+	}
+
+	if count == 0 {
+		err = fmt.Errorf("no test cases found by %v", caseItems)
+	}
+	return
+}
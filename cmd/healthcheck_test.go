@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheckCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		prepare func()
+		expect  string
+		hasErr  bool
+	}{{
+		name: "passed",
+		args: []string{"-p", simpleSuite, "bar"},
+		prepare: func() {
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON("{}")
+		},
+		expect: "OK 1 cases in",
+	}, {
+		name: "quiet",
+		args: []string{"-p", simpleSuite, "-q"},
+		prepare: func() {
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON("{}")
+		},
+	}, {
+		name: "failed",
+		args: []string{"-p", simpleSuite},
+		prepare: func() {
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusBadRequest).JSON("{}")
+		},
+		expect: "FAIL Simple/bar:",
+		hasErr: true,
+	}, {
+		name: "pass after retry",
+		args: []string{"-p", simpleSuite, "--wait", "--interval", "1ms"},
+		prepare: func() {
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusBadRequest).JSON("{}")
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON("{}")
+		},
+		expect: "OK 1 cases in",
+	}, {
+		name:   "time budget exhausted",
+		args:   []string{"-p", simpleSuite, "--wait", "--timeout", "10ms", "--interval", "1ms"},
+		expect: "FAIL Simple/bar:",
+		hasErr: true,
+	}, {
+		name:   "no test cases",
+		args:   []string{"-p", simpleSuite, "fake"},
+		expect: "FAIL no test cases found by [fake]",
+		hasErr: true,
+	}, {
+		name:   "no test suites",
+		args:   []string{"-p", "testdata/fake-*.yaml"},
+		expect: "FAIL no test suites found",
+		hasErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Clean()
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+
+			buf := new(bytes.Buffer)
+			c := createHealthCheckCmd()
+			c.SetOut(buf)
+			c.SetErr(new(bytes.Buffer))
+			c.SetArgs(tt.args)
+
+			err := c.Execute()
+			assert.Equal(t, tt.hasErr, err != nil, err)
+			if tt.expect == "" {
+				assert.Empty(t, buf.String())
+			} else {
+				assert.Contains(t, buf.String(), tt.expect)
+			}
+		})
+	}
+}
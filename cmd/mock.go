@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/converter"
+	"github.com/linuxsuren/api-testing/pkg/mock"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/spf13/cobra"
+)
+
+func createServeMockCmd() (c *cobra.Command) {
+	opt := &serveMockOption{}
+	c = &cobra.Command{
+		Use:   "serve-mock",
+		Short: "Run a stub server which responds with the expectations of the test cases",
+		Long: `Run a stub server which responds with the expectations of the test cases.
+The files could be the test suites, or the recorded cassettes in the formats which are supported by the convert command, such as HAR.`,
+		Example: `atest serve-mock -p sample/testsuite-gitlab.yaml --port 6060
+atest serve-mock -p 'recorded/*.har' --latency 200ms --jitter 100ms --fault-rate 0.1 --cors`,
+		RunE: opt.runE,
+	}
+	flags := c.Flags()
+	flags.StringVarP(&opt.pattern, "pattern", "p", "test-suite-*.yaml", "The file pattern of the test suites or the cassettes")
+	flags.IntVarP(&opt.port, "port", "", 6060, "The port of the mock server")
+	flags.DurationVarP(&opt.option.Latency, "latency", "", 0, "The delay of each response")
+	flags.DurationVarP(&opt.option.Jitter, "jitter", "", 0, "The max random delay which is added to the latency")
+	flags.Float64VarP(&opt.option.FaultRate, "fault-rate", "", 0, "The probability of responding with the fault status, from 0 to 1")
+	flags.IntVarP(&opt.option.FaultStatus, "fault-status", "", http.StatusInternalServerError, "The status code of the faults")
+	flags.BoolVarP(&opt.option.CORS, "cors", "", false, "Allow the cross-origin requests from all the origins")
+	_ = c.RegisterFlagCompletionFunc("pattern", completeSuiteFiles)
+	return
+}
+
+type serveMockOption struct {
+	pattern string
+	port    int
+	option  mock.Option
+}
+
+func (o *serveMockOption) runE(cmd *cobra.Command, args []string) (err error) {
+	var mockServer mock.Server
+	if mockServer, err = o.createServer(); err != nil {
+		return
+	}
+	for _, route := range mockServer.Routes() {
+		cmd.Printf("%s %s -> %s\n", route.Method, route.Path, route.Name)
+	}
+
+	var lis net.Listener
+	if lis, err = net.Listen("tcp", fmt.Sprintf(":%d", o.port)); err != nil {
+		return
+	}
+	cmd.Println("mock server listening at", lis.Addr())
+
+	server := &http.Server{Handler: mockServer, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-cmd.Context().Done()
+		_ = server.Close()
+	}()
+	if err = server.Serve(lis); errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	return
+}
+
+// createServer loads the files, and detects the format of each one
+func (o *serveMockOption) createServer() (mockServer mock.Server, err error) {
+	loader := testing.NewFileLoader()
+	if err = loader.Put(o.pattern); err != nil {
+		return
+	}
+	if loader.GetCount() == 0 {
+		err = fmt.Errorf("no files found by '%s'", o.pattern)
+		return
+	}
+
+	registry := converter.NewDefaultRegistry()
+	var suites []*testing.TestSuite
+	for loader.HasMore() {
+		var data []byte
+		if data, err = loader.Load(); err != nil {
+			return
+		}
+
+		format, ok := registry.Detect(data)
+		if !ok {
+			err = fmt.Errorf("cannot detect the format of the file in '%s'", o.pattern)
+			return
+		}
+
+		var suite *testing.TestSuite
+		if suite, err = format.Import(data); err != nil {
+			return
+		}
+		suites = append(suites, suite)
+	}
+	mockServer = mock.NewServer(suites, o.option)
+	return
+}
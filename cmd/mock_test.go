@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMockCmd(t *testing.T) {
+	c := createServeMockCmd()
+	buf := new(bytes.Buffer)
+	c.SetOut(buf)
+	c.SetArgs([]string{"-p", simpleSuite, "--port", "0"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := c.ExecuteContext(ctx)
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "GET /bar -> bar")
+	assert.Contains(t, buf.String(), "mock server listening at")
+
+	c = createServeMockCmd()
+	c.SetOut(new(bytes.Buffer))
+	c.SetArgs([]string{"-p", "testdata/fake-*.yaml"})
+	err = c.Execute()
+	assert.NotNil(t, err)
+
+	c = createServeMockCmd()
+	c.SetOut(new(bytes.Buffer))
+	c.SetArgs([]string{"-p", "testdata/invalid-schema.yaml"})
+	err = c.Execute()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/limit"
+	"github.com/linuxsuren/api-testing/pkg/operator"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/spf13/cobra"
+)
+
+func createOperatorCmd() (c *cobra.Command) {
+	opt := &operatorOption{}
+	c = &cobra.Command{
+		Use:   "operator",
+		Short: "Run as a Kubernetes operator which reconciles the ATestSuite resources",
+		RunE:  opt.runE,
+	}
+	flags := c.Flags()
+	flags.StringVarP(&opt.namespace, "namespace", "n", "", "The namespace to watch, watch all namespaces if it is empty")
+	flags.DurationVarP(&opt.resync, "resync", "", 30*time.Second, "The interval of the reconciling")
+	flags.IntVarP(&opt.metricsPort, "metrics-port", "", 8080, "The port of the metrics endpoint, disable it if it is zero")
+	flags.DurationVarP(&opt.requestTimeout, "request-timeout", "", time.Minute, "Timeout for per request")
+	return
+}
+
+type operatorOption struct {
+	namespace      string
+	resync         time.Duration
+	metricsPort    int
+	requestTimeout time.Duration
+	limiter        limit.RateLimiter
+}
+
+func (o *operatorOption) runE(cmd *cobra.Command, args []string) (err error) {
+	var client operator.Client
+	if client, err = operator.NewClientFromEnv(); err != nil {
+		return
+	}
+
+	o.limiter = limit.NewDefaultRateLimiter(0, 0)
+	defer o.limiter.Stop()
+
+	controller := operator.NewController(client, o.namespace, o.resync, o.runSuite)
+	if o.metricsPort > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", controller.Metrics())
+		go func() {
+			log.Printf("metrics server listening at :%d", o.metricsPort)
+			if serveErr := http.ListenAndServe(fmt.Sprintf(":%d", o.metricsPort), mux); serveErr != nil {
+				log.Printf("failed to serve the metrics: %v", serveErr)
+			}
+		}()
+	}
+	err = controller.Run(cmd.Context())
+	return
+}
+
+// runSuite runs the test suite of a resource in the same way as the run command, including the prepare
+// and clean steps, the dependencies and the timeouts
+func (o *operatorOption) runSuite(ctx context.Context, suite []byte, cases []string, reporter runner.TestReporter) error {
+	opt := newDiscardRunOption()
+	opt.reporter = reporter
+	opt.requestTimeout = o.requestTimeout
+	opt.caseItems = cases
+	opt.limiter = o.limiter
+	opt.execer = fakeruntime.DefaultExecer{}
+	return opt.runSuite(&suiteLoader{data: suite}, getDefaultContext(), ctx, nil)
+}
+
+// suiteLoader loads the test suite of a resource, the relative paths in it are based on the working directory
+type suiteLoader struct {
+	data []byte
+	read bool
+}
+
+// HasMore returns true until the test suite is loaded
+func (l *suiteLoader) HasMore() (more bool) {
+	more, l.read = !l.read, true
+	return
+}
+
+// Load returns the content of the test suite
+func (l *suiteLoader) Load() ([]byte, error) {
+	return l.data, nil
+}
+
+// Put does nothing, there is only one test suite
+func (l *suiteLoader) Put(string) error {
+	return nil
+}
+
+// GetContext returns the working directory
+func (l *suiteLoader) GetContext() string {
+	return "."
+}
+
+// GetCount returns the count of the test suites
+func (l *suiteLoader) GetCount() int {
+	return 1
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/limit"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperatorCmd(t *testing.T) {
+	c := createOperatorCmd()
+	assert.Equal(t, "operator", c.Use)
+	assert.Equal(t, "30s", c.Flags().Lookup("resync").DefValue)
+
+	os.Setenv("KUBERNETES_SERVER", "")
+	os.Setenv("KUBERNETES_SERVICE_HOST", "")
+	c.SetArgs([]string{"--metrics-port", "0"})
+	err := c.Execute()
+	assert.NotNil(t, err)
+}
+
+func TestOperatorRunSuite(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlFoo).Post("/login").Reply(http.StatusInternalServerError)
+
+	opt := &operatorOption{requestTimeout: time.Minute, limiter: limit.NewDefaultRateLimiter(0, 0)}
+	defer opt.limiter.Stop()
+
+	reporter := runner.NewMemoryTestReporter()
+	err := opt.runSuite(context.Background(), []byte(`name: operator
+api: http://foo
+items:
+- name: login
+  request:
+    api: /login
+    method: POST
+- name: user
+  dependsOn: [login]
+  request:
+    api: /user
+`), nil, reporter)
+	assert.ErrorContains(t, err, "failed to run 'login'")
+
+	records := reporter.GetAllRecords()
+	if assert.Equal(t, 2, len(records)) {
+		assert.Equal(t, "login", records[0].Name)
+		assert.Equal(t, "user", records[1].Name)
+		assert.ErrorContains(t, records[1].Error, "skipped due to the failed dependency 'login'")
+	}
+}
@@ -17,9 +17,13 @@ func NewRootCmd(execer fakeruntime.Execer, gRPCServer gRPCServer) (c *cobra.Comm
 	c.SetOut(os.Stdout)
 	c.Version = version.GetVersion()
 	c.AddCommand(createInitCommand(execer),
-		createRunCommand(), createSampleCmd(),
-		createServerCmd(gRPCServer), createJSONSchemaCmd(),
-		createServiceCommand(execer), createFunctionCmd())
+		createRunCommand(execer), createSampleCmd(),
+		createServerCmd(execer, gRPCServer), createJSONSchemaCmd(),
+		createServiceCommand(execer), createFunctionCmd(),
+		createConsoleCmd(), createOperatorCmd(),
+		createCICmd(execer), createHealthCheckCmd(),
+		createConvertCmd(), createServeMockCmd(),
+		createFuzzCmd())
 	return
 }
 
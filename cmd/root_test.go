@@ -9,13 +9,13 @@ import (
 )
 
 func TestCreateRunCommand(t *testing.T) {
-	cmd := createRunCommand()
+	cmd := createRunCommand(fakeruntime.FakeExecer{})
 	assert.Equal(t, "run", cmd.Use)
 
 	init := createInitCommand(fakeruntime.FakeExecer{})
 	assert.Equal(t, "init", init.Use)
 
-	server := createServerCmd(&fakeGRPCServer{})
+	server := createServerCmd(fakeruntime.FakeExecer{}, &fakeGRPCServer{})
 	assert.NotNil(t, server)
 	assert.Equal(t, "server", server.Use)
 
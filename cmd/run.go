@@ -4,18 +4,27 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"net/http/pprof"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/linuxsuren/api-testing/pkg/extension"
 	"github.com/linuxsuren/api-testing/pkg/limit"
 	"github.com/linuxsuren/api-testing/pkg/render"
 	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/linuxsuren/api-testing/pkg/secret"
+	"github.com/linuxsuren/api-testing/pkg/store"
 	"github.com/linuxsuren/api-testing/pkg/testing"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/semaphore"
+	"golang.org/x/sync/errgroup"
 )
 
 type runOption struct {
@@ -24,22 +33,62 @@ type runOption struct {
 	requestTimeout     time.Duration
 	requestIgnoreError bool
 	thread             int64
+	iterations         int
+	rampUp             time.Duration
+	warmUpIterations   int
+	warmUp             time.Duration
+	concurrency        int
 	context            context.Context
 	qps                int32
 	burst              int32
+	targetQPS          int32
 	limiter            limit.RateLimiter
 	startTime          time.Time
 	reporter           runner.TestReporter
 	reportFile         string
 	reportWriter       runner.ReportResultWriter
+	metrics            runner.MetricsTestReporter
 	report             string
 	reportIgnore       bool
 	swaggerURL         string
+	apiConverage       apispec.APIConverage
+	minCoverage        float64
 	level              string
 	caseItems          []string
+	store              string
+	extensionDirs      []string
+	pprof              string
+	resourceUsage      bool
+	env                string
+	envFiles           []string
+	secrets            []string
+	redact             secret.Rules
+	metricsAddress     string
+	pushgateway        string
+	pushgatewayJob     string
+	otlpEndpoint       string
+	otlpServiceName    string
+	stream             string
+	cassetteMode       string
+	dumpFile           string
+	dumpMaxSize        int64
+	dumpMaxBackups     int
+	cassetteDir        string
+	pactDir            string
+	pactConsumer       string
+	drifts             []string
+	driftsLock         sync.Mutex
+	// rateLimiters are shared by the runs of the same test suite, such as the virtual users of the load test
+	rateLimiters     map[string]limit.RateLimiter
+	rateLimitersLock sync.Mutex
+	// pacts are the contracts of the test suites, they're saved once all the runs are done
+	pacts     map[string]runner.Pact
+	pactsLock sync.Mutex
 
 	// for internal use
-	loader testing.Loader
+	loader     testing.Loader
+	execer     fakeruntime.Execer
+	extensions extension.Manager
 }
 
 func newDefaultRunOption() *runOption {
@@ -58,8 +107,9 @@ func newDiscardRunOption() *runOption {
 }
 
 // createRunCommand returns the run command
-func createRunCommand() (cmd *cobra.Command) {
+func createRunCommand(execer fakeruntime.Execer) (cmd *cobra.Command) {
 	opt := newDefaultRunOption()
+	opt.execer = execer
 	cmd = &cobra.Command{
 		Use:     "run",
 		Aliases: []string{"r"},
@@ -70,26 +120,81 @@ See also https://github.com/LinuxSuRen/api-testing/tree/master/sample`,
 		RunE:    opt.runE,
 	}
 
-	// set flags
+	opt.addFlags(cmd)
+	registerSuiteCompletion(cmd)
+	return
+}
+
+// addFlags adds the flags which are shared by the commands which run test suites
+func (o *runOption) addFlags(cmd *cobra.Command) {
 	flags := cmd.Flags()
-	flags.StringVarP(&opt.pattern, "pattern", "p", "test-suite-*.yaml",
+	flags.StringVarP(&o.pattern, "pattern", "p", "test-suite-*.yaml",
 		"The file pattern which try to execute the test cases. Brace expansion is supported, such as: test-suite-{1,2}.yaml")
-	flags.StringVarP(&opt.level, "level", "l", "info", "Set the output log level")
-	flags.DurationVarP(&opt.duration, "duration", "", 0, "Running duration")
-	flags.DurationVarP(&opt.requestTimeout, "request-timeout", "", time.Minute, "Timeout for per request")
-	flags.BoolVarP(&opt.requestIgnoreError, "request-ignore-error", "", false, "Indicate if ignore the request error")
-	flags.StringVarP(&opt.report, "report", "", "", "The type of target report. Supported: markdown, md, html, discard, std")
-	flags.StringVarP(&opt.reportFile, "report-file", "", "", "The file path of the report")
-	flags.BoolVarP(&opt.reportIgnore, "report-ignore", "", false, "Indicate if ignore the report output")
-	flags.StringVarP(&opt.swaggerURL, "swagger-url", "", "", "The URL of swagger")
-	flags.Int64VarP(&opt.thread, "thread", "", 1, "Threads of the execution")
-	flags.Int32VarP(&opt.qps, "qps", "", 5, "QPS")
-	flags.Int32VarP(&opt.burst, "burst", "", 5, "burst")
-	return
+	flags.StringVarP(&o.store, "store", "", "", "The store of the test suites, the pattern will be used to match the suite names. Such as: git+https://xxx.git#branch, s3://bucket/prefix, configmap://namespace/name")
+	flags.StringSliceVarP(&o.extensionDirs, "extension-dir", "", []string{extension.DefaultDir()}, "The directories of the extensions")
+	flags.StringVarP(&o.level, "level", "l", "info", "Set the output log level, such as: trace, debug, info, warn, error. The components runner, prepare and reporter could have their own levels, and \"json\" is the format, such as: info,prepare=debug,json")
+	flags.DurationVarP(&o.duration, "duration", "", 0, "The duration of running the test suites repeatedly")
+	flags.DurationVarP(&o.requestTimeout, "request-timeout", "", time.Minute, "Timeout for per request")
+	flags.BoolVarP(&o.requestIgnoreError, "request-ignore-error", "", false, "Indicate if ignore the request error")
+	flags.StringVarP(&o.report, "report", "", "", "The type of target report. Supported: markdown, md, html, json, tap, console, discard, std")
+	flags.StringVarP(&o.reportFile, "report-file", "", "", "The file path of the report")
+	flags.BoolVarP(&o.reportIgnore, "report-ignore", "", false, "Indicate if ignore the report output")
+	flags.BoolVarP(&o.resourceUsage, "report-resource-usage", "", false, "Indicate if put the CPU, memory and GC stats of the runner into the report")
+	flags.StringVarP(&o.pprof, "pprof", "", "", "The address of the pprof endpoints, such as: localhost:6060")
+	flags.StringVarP(&o.metricsAddress, "metrics-address", "", "", "The address of the Prometheus metrics endpoint /metrics during the run, such as: localhost:9090")
+	flags.StringVarP(&o.pushgateway, "pushgateway", "", "", "The URL of the Prometheus Pushgateway which the metrics are pushed to after the run")
+	flags.StringVarP(&o.pushgatewayJob, "pushgateway-job", "", "atest", "The job name of the metrics in the Pushgateway")
+	flags.StringVarP(&o.otlpEndpoint, "otlp-endpoint", "", "", "The OTLP/HTTP endpoint which the spans of the test suites, the test cases and the requests are exported to, such as: http://localhost:4318/v1/traces")
+	flags.StringVarP(&o.otlpServiceName, "otlp-service-name", "", "atest", "The service name of the exported spans")
+	flags.StringVarP(&o.stream, "stream", "", "", "Write the result of each test case to stderr once it's completed. Supported: progress, ndjson")
+	flags.StringVarP(&o.cassetteMode, "cassette", "", "", "Record the HTTP responses of each test suite into a cassette file, or replay them without sending the requests. Supported: record, replay")
+	flags.StringVarP(&o.cassetteDir, "cassette-dir", "", "cassettes", "The directory of the cassette files, the file name is the test suite name")
+	flags.StringVarP(&o.pactDir, "pact-dir", "", "", "The directory which the Pact contracts of the passed test cases are written into, the provider is the test suite name")
+	flags.StringVarP(&o.pactConsumer, "pact-consumer", "", "atest", "The consumer name of the Pact contracts")
+	flags.StringVarP(&o.dumpFile, "dump-file", "", "", "The file which the headers and the bodies of the HTTP requests and the responses are dumped into as JSON lines")
+	flags.Int64VarP(&o.dumpMaxSize, "dump-max-size", "", 10, "The max size in megabytes of the dump file before it's rotated")
+	flags.IntVarP(&o.dumpMaxBackups, "dump-max-backups", "", 3, "The max count of the rotated dump files")
+	flags.StringVarP(&o.swaggerURL, "swagger-url", "", "", "The URL of swagger")
+	flags.Float64VarP(&o.minCoverage, "min-coverage", "", 0, "The min API coverage in percent, works with --swagger-url")
+	flags.Int64VarP(&o.thread, "thread", "", 1, "The count of the virtual users which run the test suites repeatedly in parallel")
+	flags.IntVarP(&o.iterations, "iterations", "", 0, "The total count of running the test suites of all the threads, the test suites run once if both it and the duration are zero")
+	flags.DurationVarP(&o.rampUp, "ramp-up", "", 0, "The duration of starting the threads evenly")
+	flags.IntVarP(&o.warmUpIterations, "warm-up-iterations", "", 0, "The count of the first iterations which are excluded from the report, they're in addition to the iterations")
+	flags.DurationVarP(&o.warmUp, "warm-up", "", 0, "The duration of the warm-up, the iterations which start during it are excluded from the report")
+	flags.IntVarP(&o.concurrency, "concurrency", "", 0, "The count of the test cases of a suite which run in parallel, the concurrency of the suite is used if it's zero")
+	flags.StringVarP(&o.env, "env", "", "", "The name of the environment, the values of env/<name>.yaml next to the test suite are referenced as {{.env.<key>}}")
+	flags.StringSliceVarP(&o.envFiles, "env-file", "", nil, "The environment files which override the values of the environment")
+	flags.StringSliceVarP(&o.secrets, "secret", "", nil, "The providers of the secrets which are referenced as {{secret \"name\"}}, the environment variables by default. Such as: env://PREFIX_, file://secrets.yaml, vault://mount/path")
+	flags.StringSliceVarP(&o.redact.Headers, "redact-header", "", nil, "The headers which are redacted from the logs and the reports besides the default ones, such as: Authorization, Cookie")
+	flags.StringSliceVarP(&o.redact.Fields, "redact-field", "", nil, "The JSON fields which are redacted from the logs and the reports besides the default ones, such as: password, user.token, items[*].secret")
+	flags.StringSliceVarP(&o.redact.Patterns, "redact-pattern", "", nil, "The regular expressions of the text which is redacted from the logs and the reports")
+	flags.Int32VarP(&o.qps, "qps", "", 5, "QPS")
+	flags.Int32VarP(&o.burst, "burst", "", 5, "burst")
+	flags.Int32VarP(&o.targetQPS, "target-qps", "", 0, "Issue the requests at the target QPS instead of the --qps, and report the achieved throughput and the latency distribution of all the requests")
 }
 
 func (o *runOption) preRunE(cmd *cobra.Command, args []string) (err error) {
 	writer := cmd.OutOrStdout()
+	if o.minCoverage > 0 && o.swaggerURL == "" {
+		err = fmt.Errorf("--min-coverage works with --swagger-url")
+		return
+	}
+	switch o.cassetteMode {
+	case "", runner.CassetteRecord, runner.CassetteReplay:
+	default:
+		err = fmt.Errorf("not supported cassette mode: '%s'", o.cassetteMode)
+		return
+	}
+
+	o.extensions = extension.NewManager()
+	if err = o.extensions.Discover(o.extensionDirs...); err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			o.extensions.Stop()
+		}
+	}()
 
 	if o.reportFile != "" {
 		var reportFile *os.File
@@ -107,19 +212,68 @@ func (o *runOption) preRunE(cmd *cobra.Command, args []string) (err error) {
 		o.reportWriter = runner.NewHTMLResultWriter(writer)
 	case "json":
 		o.reportWriter = runner.NewJSONResultWriter(writer)
+	case "tap":
+		o.reportWriter = runner.NewTAPResultWriter(writer)
+	case "console":
+		o.reportWriter = runner.NewConsoleResultWriter(writer, isTerminal(writer) && os.Getenv("NO_COLOR") == "")
 	case "discard":
 		o.reportWriter = runner.NewDiscardResultWriter()
 	case "", "std":
 		o.reportWriter = runner.NewResultWriter(writer)
 	default:
-		err = fmt.Errorf("not supported report type: '%s'", o.report)
+		var ok bool
+		if o.reportWriter, ok = o.extensions.GetReportWriter(o.report); !ok {
+			err = fmt.Errorf("not supported report type: '%s'", o.report)
+		}
+	}
+
+	if err == nil && o.store != "" {
+		var suiteStore store.Store
+		if suiteStore, err = getStore(o.store, o.execer, o.extensions); err == nil {
+			o.loader = store.NewStoreLoader(suiteStore)
+		}
+	}
+
+	switch o.stream {
+	case "":
+	case "progress":
+		o.reporter = runner.NewProgressTestReporter(o.reporter, cmd.ErrOrStderr())
+	case "ndjson":
+		o.reporter = runner.NewNDJSONTestReporter(o.reporter, cmd.ErrOrStderr())
+	default:
+		err = fmt.Errorf("not supported stream type: '%s'", o.stream)
+		return
+	}
+
+	if o.metricsAddress != "" || o.pushgateway != "" {
+		o.metrics = runner.NewPrometheusTestReporter(o.reporter)
+		o.reporter = o.metrics
+	}
+
+	if err == nil {
+		rules := secret.DefaultRules()
+		rules.Headers = append(rules.Headers, o.redact.Headers...)
+		rules.Fields = append(rules.Fields, o.redact.Fields...)
+		rules.Patterns = append(rules.Patterns, o.redact.Patterns...)
+		if err = secret.SetRules(rules); err != nil {
+			return
+		}
+	}
+
+	if err == nil && len(o.secrets) > 0 {
+		providers := make([]secret.Provider, len(o.secrets))
+		for i, uri := range o.secrets {
+			if providers[i], err = secret.NewProvider(uri); err != nil {
+				return
+			}
+		}
+		secret.SetProviders(providers...)
 	}
 
 	if err == nil {
-		var swaggerAPI apispec.APIConverage
 		if o.swaggerURL != "" {
-			if swaggerAPI, err = apispec.ParseURLToSwagger(o.swaggerURL); err == nil {
-				o.reportWriter.WithAPIConverage(swaggerAPI)
+			if o.apiConverage, err = apispec.ParseURLToSwagger(o.swaggerURL); err == nil {
+				o.reportWriter.WithAPIConverage(o.apiConverage)
 			}
 		}
 	}
@@ -131,10 +285,61 @@ func (o *runOption) preRunE(cmd *cobra.Command, args []string) (err error) {
 func (o *runOption) runE(cmd *cobra.Command, args []string) (err error) {
 	o.startTime = time.Now()
 	o.context = cmd.Context()
-	o.limiter = limit.NewDefaultRateLimiter(o.qps, o.burst)
+	if o.otlpEndpoint != "" {
+		tracer := runner.NewOTLPTracer(o.otlpEndpoint, o.otlpServiceName)
+		o.context = runner.WithTracer(o.context, tracer)
+		defer func() {
+			flushErr := tracer.Flush()
+			println(cmd, flushErr, "failed to export the spans", flushErr)
+		}()
+	}
+	if o.dumpFile != "" {
+		var dumpWriter io.WriteCloser
+		if dumpWriter, err = runner.NewRotateWriter(o.dumpFile, o.dumpMaxSize*1024*1024, o.dumpMaxBackups); err != nil {
+			return
+		}
+		o.context = runner.WithDumpWriter(o.context, dumpWriter)
+		defer func() {
+			_ = dumpWriter.Close()
+		}()
+	}
+	if o.pprof != "" {
+		var pprofServer *http.Server
+		if pprofServer, err = startPprof(o.pprof); err != nil {
+			return
+		}
+		cmd.Println("pprof endpoints are serving at", o.pprof)
+		defer func() {
+			_ = pprofServer.Close()
+		}()
+	}
+	if o.metricsAddress != "" {
+		var metricsServer *http.Server
+		if metricsServer, err = startMetrics(o.metricsAddress, o.metrics); err != nil {
+			return
+		}
+		cmd.Println("metrics are serving at", o.metricsAddress)
+		defer func() {
+			_ = metricsServer.Close()
+		}()
+	}
+
+	qps := o.qps
+	if o.targetQPS > 0 {
+		qps = o.targetQPS
+	}
+	o.limiter = limit.NewDefaultRateLimiter(qps, o.burst)
+	var monitor runner.ResourceMonitor
+	if o.resourceUsage {
+		monitor = runner.NewResourceMonitor(time.Second)
+	}
+
 	defer func() {
 		cmd.Printf("consume: %s\n", time.Since(o.startTime).String())
 		o.limiter.Stop()
+		if o.extensions != nil {
+			o.extensions.Stop()
+		}
 	}()
 
 	if err = o.loader.Put(o.pattern); err != nil {
@@ -148,6 +353,35 @@ func (o *runOption) runE(cmd *cobra.Command, args []string) (err error) {
 		}
 	}
 
+	if monitor != nil {
+		o.reportWriter.WithResourceUsage(monitor.Stop())
+	}
+
+	if saveErr := o.savePacts(); err == nil {
+		err = saveErr
+	}
+
+	if o.warmUpIterations > 0 || o.warmUp > 0 {
+		cmd.Println("warm-up:", countWarmUp(o.reporter.GetAllRecords()), "requests are excluded from the report")
+	}
+
+	for _, drift := range o.drifts {
+		cmd.Println("contract drift:", drift)
+	}
+
+	if o.targetQPS > 0 {
+		cmd.Println("benchmark:", runner.NewBenchmark(o.reporter.GetAllRecords(), float64(o.targetQPS)))
+	}
+
+	if o.pushgateway != "" {
+		pushErr := o.metrics.Push(o.pushgateway, o.pushgatewayJob)
+		println(cmd, pushErr, "failed to push the metrics", pushErr)
+	}
+
+	if err == nil && o.minCoverage > 0 {
+		err = o.checkCoverage()
+	}
+
 	if o.reportIgnore {
 		return
 	}
@@ -163,62 +397,63 @@ func (o *runOption) runE(cmd *cobra.Command, args []string) (err error) {
 	return
 }
 
-func (o *runOption) runSuiteWithDuration(loader testing.Loader) (err error) {
-	sem := semaphore.NewWeighted(o.thread)
-	stop := false
-	var timeout *time.Ticker
-	if o.duration > 0 {
-		timeout = time.NewTicker(o.duration)
-	} else {
-		// make sure having a valid timer
-		timeout = time.NewTicker(time.Second)
-	}
-	errChannel := make(chan error, 10*o.thread)
-	stopSingal := make(chan struct{}, 1)
-	var wait sync.WaitGroup
+var cassetteNameReg = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
 
-	for !stop {
-		select {
-		case <-timeout.C:
-			stop = true
-			stopSingal <- struct{}{}
-		case err = <-errChannel:
-			if err != nil {
-				stop = true
-			}
-		default:
-			if err := sem.Acquire(o.context, 1); err != nil {
-				continue
-			}
-			wait.Add(1)
-
-			go func(ch chan error, sem *semaphore.Weighted) {
-				now := time.Now()
-				defer sem.Release(1)
-				defer wait.Done()
-				defer func() {
-					fmt.Println("routing end with", time.Since(now))
-				}()
-
-				dataContext := getDefaultContext()
-				ch <- o.runSuite(loader, dataContext, o.context, stopSingal)
-			}(errChannel, sem)
-			if o.duration <= 0 {
-				stop = true
-			}
+// cassetteFile returns the path of the cassette of a test suite
+func cassetteFile(dir, suite string) string {
+	name := strings.Trim(cassetteNameReg.ReplaceAllString(suite, "-"), "-")
+	return filepath.Join(dir, testing.EmptyThenDefault(name, "default")+".yaml")
+}
+
+// pactFile returns the path of the contract between the consumer and the provider, it's named as the Pact tools do
+func pactFile(dir, consumer, provider string) string {
+	name := fmt.Sprintf("%s-%s", cassetteNameReg.ReplaceAllString(consumer, "-"), cassetteNameReg.ReplaceAllString(provider, "-"))
+	return filepath.Join(dir, strings.Trim(name, "-")+".json")
+}
+
+// countWarmUp returns the count of the warm-up records
+func countWarmUp(records []*runner.ReportRecord) (count int) {
+	for _, record := range records {
+		if record.WarmUp {
+			count++
 		}
 	}
+	return
+}
 
-	select {
-	case err = <-errChannel:
-	case <-stopSingal:
+// checkCoverage returns an error if the API coverage is lower than the expected one
+func (o *runOption) checkCoverage() (err error) {
+	var results runner.ReportResultSlice
+	if results, err = o.reporter.ExportAllReportResults(); err == nil {
+		if coverage := runner.NewAPICoverage(results, o.apiConverage); coverage != nil && coverage.Percent < o.minCoverage {
+			err = fmt.Errorf("the API coverage %s is lower than %v%%", coverage.Summary(), o.minCoverage)
+		}
 	}
+	return
+}
 
-	wait.Wait()
+// runSuiteWithDuration runs the test suite repeatedly with the threads as the virtual users,
+// until the duration is over or the iterations are done. It runs once by default
+func (o *runOption) runSuiteWithDuration(loader testing.Loader) (err error) {
+	load := runner.Load{
+		VirtualUsers:     int(o.thread),
+		Duration:         o.duration,
+		Iterations:       o.iterations,
+		RampUp:           o.rampUp,
+		WarmUpIterations: o.warmUpIterations,
+		WarmUp:           o.warmUp,
+	}
+	err = load.Run(o.context, func(stop <-chan struct{}, warmUp bool) error {
+		ctx := o.context
+		if warmUp {
+			ctx = runner.WithWarmUp(ctx)
+		}
+		return o.runSuite(loader, getDefaultContext(), ctx, stop)
+	})
 	return
 }
 
-func (o *runOption) runSuite(loader testing.Loader, dataContext map[string]interface{}, ctx context.Context, stopSingal chan struct{}) (err error) {
+func (o *runOption) runSuite(loader testing.Loader, dataContext map[string]interface{}, ctx context.Context, stopSingal <-chan struct{}) (err error) {
 	var data []byte
 	if data, err = loader.Load(); err != nil {
 		return
@@ -229,6 +464,10 @@ func (o *runOption) runSuite(loader testing.Loader, dataContext map[string]inter
 		return
 	}
 
+	if err = o.loadEnvironment(loader.GetContext(), dataContext); err != nil {
+		return
+	}
+
 	var result string
 	if result, err = render.Render("base api", testSuite.API, dataContext); err == nil {
 		testSuite.API = result
@@ -237,40 +476,307 @@ func (o *runOption) runSuite(loader testing.Loader, dataContext map[string]inter
 		return
 	}
 
-	for _, testCase := range testSuite.Items {
-		if !testCase.InScope(o.caseItems) {
+	var span *runner.Span
+	ctx, span = runner.StartSpan(ctx, testSuite.Name, runner.SpanKindInternal)
+	defer func() {
+		span.End(err)
+	}()
+
+	if testSuite.OpenAPI != "" {
+		var spec *apispec.OpenAPI
+		if spec, err = apispec.LoadOpenAPI(testSuite.OpenAPI, loader.GetContext()); err != nil {
+			return
+		}
+		ctx = runner.WithOpenAPI(ctx, spec)
+	}
+
+	if o.cassetteMode != "" {
+		var cassette runner.Cassette
+		if cassette, err = runner.NewCassette(cassetteFile(o.cassetteDir, testSuite.Name), o.cassetteMode); err != nil {
+			return
+		}
+		ctx = runner.WithCassette(ctx, cassette)
+		defer func() {
+			if saveErr := cassette.Save(); err == nil {
+				err = saveErr
+			}
+			o.driftsLock.Lock()
+			o.drifts = append(o.drifts, cassette.Drifts()...)
+			o.driftsLock.Unlock()
+		}()
+	}
+
+	if o.pactDir != "" {
+		ctx = runner.WithPact(ctx, o.getPact(testSuite))
+	}
+
+	// the cookies are shared by the test cases of the suite
+	ctx = runner.WithCookieJar(ctx)
+	if testSuite.RateLimit > 0 {
+		ctx = runner.WithRateLimiter(ctx, o.getRateLimiter(testSuite))
+	}
+	suiteCtx := context.WithValue(ctx, runner.ContextKey("").ParentDir(), loader.GetContext())
+	suiteRunner := runner.NewSuiteRunner(io.Discard, o.level, o.execer).WithTestReporter(o.reporter)
+	defer func() {
+		if cleanErr := suiteRunner.Clean(suiteCtx, testSuite, dataContext, err != nil); err == nil {
+			err = cleanErr
+		}
+	}()
+	if err = suiteRunner.Prepare(suiteCtx, testSuite, dataContext); err != nil {
+		return
+	}
+
+	concurrency := o.concurrency
+	if concurrency <= 0 {
+		concurrency = testSuite.Concurrency
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var items []testing.TestCase
+	if items, err = testing.SortByDependencies(testSuite.Items); err != nil {
+		return
+	}
+	caseItems := testing.ExpandDependencies(items, o.caseItems)
+
+	// the cases are dispatched in the order of the dependencies, the outputs of the finished ones are in the context
+	// of the next one. A failed case stops the dispatching, or only skips the dependents of it if there are dependencies.
+	explicit := testing.HasDependencies(items)
+	var lock sync.Mutex
+	var caseErr error
+	failed := map[string]bool{}
+	finished := map[string]chan struct{}{}
+	cases := new(errgroup.Group)
+	cases.SetLimit(concurrency)
+	for _, testCase := range items {
+		testCase := testCase
+		if !testCase.InScope(caseItems) {
 			continue
 		}
 
-		// reuse the API prefix
-		if strings.HasPrefix(testCase.Request.API, "/") {
-			testCase.Request.API = fmt.Sprintf("%s%s", testSuite.API, testCase.Request.API)
+		testSuite.Inherit(&testCase)
+		var thinkTime runner.ThinkTime
+		if thinkTime, err = runner.ParseThinkTime(testCase.ThinkTime); err != nil {
+			_ = cases.Wait()
+			return
 		}
 
-		var output interface{}
 		select {
 		case <-stopSingal:
+			_ = cases.Wait()
+			err = caseErr
 			return
 		default:
-			o.limiter.Accept()
+		}
 
-			ctxWithTimeout, _ := context.WithTimeout(ctx, o.requestTimeout)
-			ctxWithTimeout = context.WithValue(ctxWithTimeout, runner.ContextKey("").ParentDir(), loader.GetContext())
+		dependencies := make([]chan struct{}, 0, len(testCase.DependsOn))
+		for _, dependency := range testCase.DependsOn {
+			if wait, ok := finished[dependency]; ok {
+				dependencies = append(dependencies, wait)
+			}
+		}
+		done := make(chan struct{})
+		finished[testCase.Name] = done
 
-			simpleRunner := runner.NewSimpleTestCaseRunner()
-			simpleRunner.WithTestReporter(o.reporter)
-			if output, err = simpleRunner.RunTestCase(&testCase, dataContext, ctxWithTimeout); err != nil && !o.requestIgnoreError {
-				err = fmt.Errorf("failed to run '%s', %v", testCase.Name, err)
-				return
-			} else {
-				err = nil
+		cases.Go(func() error {
+			defer close(done)
+			for _, dependency := range dependencies {
+				<-dependency
 			}
+
+			lock.Lock()
+			if caseErr != nil && !explicit {
+				lock.Unlock()
+				return nil
+			}
+			for _, dependency := range testCase.DependsOn {
+				if failed[dependency] {
+					failed[testCase.Name] = true
+					lock.Unlock()
+					o.skipCase(ctx, &testCase, dependency)
+					return nil
+				}
+			}
+			caseContext := make(map[string]interface{}, len(dataContext))
+			for key, val := range dataContext {
+				caseContext[key] = val
+			}
+			// the exported values are copied since the case puts its own ones into them
+			caseContext[runner.ExportKey] = copyValues(dataContext[runner.ExportKey])
+			lock.Unlock()
+
+			output, runErr := o.runCase(ctx, loader, &testCase, caseContext)
+			// the dependents and the next case of the worker wait for the think time
+			defer thinkTime.Pause(ctx, stopSingal)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if runErr != nil {
+				failed[testCase.Name] = true
+				if caseErr == nil {
+					caseErr = runErr
+				}
+				return nil
+			}
+			dataContext[testCase.Name] = output
+			if values, ok := caseContext[runner.ExportKey].(map[string]interface{})[testCase.Name]; ok {
+				exported := copyValues(dataContext[runner.ExportKey])
+				exported[testCase.Name] = values
+				dataContext[runner.ExportKey] = exported
+			}
+			return nil
+		})
+	}
+	_ = cases.Wait()
+	err = caseErr
+	return
+}
+
+// getRateLimiter returns the rate limiter of the test suite, it's created once
+func (o *runOption) getRateLimiter(testSuite *testing.TestSuite) limit.RateLimiter {
+	o.rateLimitersLock.Lock()
+	defer o.rateLimitersLock.Unlock()
+	if o.rateLimiters == nil {
+		o.rateLimiters = map[string]limit.RateLimiter{}
+	}
+	key := fmt.Sprintf("%s/%d", testSuite.Name, testSuite.RateLimit)
+	limiter, ok := o.rateLimiters[key]
+	if !ok {
+		limiter = limit.NewDefaultRateLimiter(int32(testSuite.RateLimit), 1)
+		o.rateLimiters[key] = limiter
+	}
+	return limiter
+}
+
+// getPact returns the contract of the test suite, it's shared by the runs of the same test suite
+func (o *runOption) getPact(testSuite *testing.TestSuite) runner.Pact {
+	o.pactsLock.Lock()
+	defer o.pactsLock.Unlock()
+	if o.pacts == nil {
+		o.pacts = map[string]runner.Pact{}
+	}
+	pact, ok := o.pacts[testSuite.Name]
+	if !ok {
+		pact = runner.NewPact(pactFile(o.pactDir, o.pactConsumer, testSuite.Name), o.pactConsumer, testSuite.Name)
+		o.pacts[testSuite.Name] = pact
+	}
+	return pact
+}
+
+// savePacts writes the contracts of the test suites into the files
+func (o *runOption) savePacts() (err error) {
+	o.pactsLock.Lock()
+	defer o.pactsLock.Unlock()
+	for _, pact := range o.pacts {
+		if err = pact.Save(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// loadEnvironment puts the values of the named environment and the environment files into the data context
+func (o *runOption) loadEnvironment(dir string, dataContext map[string]interface{}) (err error) {
+	var files []string
+	if o.env != "" {
+		files = append(files, testing.EnvironmentFile(dir, o.env))
+	}
+	files = append(files, o.envFiles...)
+	if len(files) == 0 {
+		return
+	}
+
+	var env map[string]interface{}
+	if env, err = testing.LoadEnvironment(files...); err == nil {
+		dataContext[testing.EnvKey] = env
+	}
+	return
+}
+
+// copyValues returns a shallow copy of the map, it's empty if the value is not a map
+func copyValues(values interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	if valueMap, ok := values.(map[string]interface{}); ok {
+		for key, val := range valueMap {
+			result[key] = val
 		}
-		dataContext[testCase.Name] = output
+	}
+	return result
+}
+
+// skipCase puts a failed record of the test case which is skipped due to the failed dependency
+func (o *runOption) skipCase(ctx context.Context, testCase *testing.TestCase, dependency string) {
+	record := runner.NewReportRecord()
+	record.WarmUp = runner.IsWarmUp(ctx)
+	record.Name = testCase.Name
+	record.Method = testing.EmptyThenDefault(testCase.Request.Method, http.MethodGet)
+	record.API = testCase.Request.API
+	record.Error = fmt.Errorf("skipped due to the failed dependency '%s'", dependency)
+	record.Body = record.Error.Error()
+	record.EndTime = record.BeginTime
+	o.reporter.PutRecord(record)
+}
+
+// runCase runs a test case with the timeout of the request, the error is ignored if the requestIgnoreError is true.
+// The timeout applies to the request only, the prepare steps, the locks and the waitFor have their own timeouts
+func (o *runOption) runCase(ctx context.Context, loader testing.Loader, testCase *testing.TestCase,
+	dataContext map[string]interface{}) (output interface{}, err error) {
+	o.limiter.Accept()
+
+	caseCtx := runner.WithRequestTimeout(ctx, o.requestTimeout)
+	caseCtx = context.WithValue(caseCtx, runner.ContextKey("").ParentDir(), loader.GetContext())
+
+	caseRunner := getTestCaseRunner(testCase, o.extensions)
+	caseRunner.WithTestReporter(o.reporter)
+	if output, err = caseRunner.RunTestCase(testCase, dataContext, caseCtx); err != nil && !o.requestIgnoreError {
+		err = fmt.Errorf("failed to run '%s', %v", testCase.Name, err)
+	} else {
+		err = nil
 	}
 	return
 }
 
+// startPprof serves the pprof endpoints in the background
+func startPprof(address string) (server *http.Server, err error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return serve(address, mux)
+}
+
+// startMetrics serves the Prometheus metrics at /metrics
+func startMetrics(address string, metrics http.Handler) (server *http.Server, err error) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	return serve(address, mux)
+}
+
+// serve listens the address, then serves the handler in the background
+func serve(address string, handler http.Handler) (server *http.Server, err error) {
+	var listener net.Listener
+	if listener, err = net.Listen("tcp", address); err == nil {
+		server = &http.Server{Handler: handler}
+		go func() {
+			_ = server.Serve(listener)
+		}()
+	}
+	return
+}
+
+// isTerminal returns true if the writer is a terminal, the report file or the pipes are not
+func isTerminal(writer io.Writer) bool {
+	if file, ok := writer.(*os.File); ok {
+		if info, err := file.Stat(); err == nil {
+			return info.Mode()&os.ModeCharDevice != 0
+		}
+	}
+	return false
+}
+
 func getDefaultContext() map[string]interface{} {
 	return map[string]interface{}{}
 }
@@ -7,13 +7,17 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/h2non/gock"
 	"github.com/linuxsuren/api-testing/pkg/limit"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/linuxsuren/api-testing/pkg/secret"
 	atest "github.com/linuxsuren/api-testing/pkg/testing"
 	"github.com/linuxsuren/api-testing/pkg/util"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 )
@@ -43,6 +47,56 @@ func TestRunSuite(t *testing.T) {
 				Reply(http.StatusOK)
 		},
 		hasError: true,
+	}, {
+		name:      "parallel",
+		suiteFile: "testdata/parallel-suite.yaml",
+		prepare: func() {
+			for _, api := range []string{"/bar", "/baz", "/qux"} {
+				gock.New(urlFoo).Get(api).Reply(http.StatusOK).JSON("{}")
+			}
+		},
+	}, {
+		name:      "one of the parallel cases is failed",
+		suiteFile: "testdata/parallel-suite.yaml",
+		prepare: func() {
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON("{}")
+			gock.New(urlFoo).Get("/baz").Reply(http.StatusInternalServerError)
+			gock.New(urlFoo).Get("/qux").Reply(http.StatusOK).JSON("{}")
+		},
+		hasError: true,
+	}, {
+		name:      "dependencies",
+		suiteFile: "testdata/dependency-suite.yaml",
+		prepare: func() {
+			gock.New(urlFoo).Get("/login").Reply(http.StatusOK).JSON("{}")
+			gock.New(urlFoo).Get("/order").Reply(http.StatusOK).JSON("{}")
+			gock.New(urlFoo).Get("/health").Reply(http.StatusOK).JSON("{}")
+		},
+	}, {
+		name:      "the dependents of the failed case are skipped",
+		suiteFile: "testdata/dependency-suite.yaml",
+		prepare: func() {
+			gock.New(urlFoo).Get("/login").Reply(http.StatusUnauthorized)
+			gock.New(urlFoo).Get("/health").Reply(http.StatusOK).JSON("{}")
+		},
+		hasError: true,
+	}, {
+		name:      "export the values of the response",
+		suiteFile: "testdata/export-suite.yaml",
+		prepare: func() {
+			gock.New(urlFoo).Get("/login").Reply(http.StatusOK).
+				SetHeader("X-Session", "abc").JSON(`{"token": "123"}`)
+			gock.New(urlFoo).Get("/profile").
+				MatchHeader("Authorization", "Bearer 123").MatchHeader("X-Session", "abc").
+				Reply(http.StatusOK).JSON("{}")
+		},
+	}, {
+		name:      "think time",
+		suiteFile: "testdata/think-time-suite.yaml",
+		prepare: func() {
+			gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON("{}")
+			gock.New(urlFoo).Get("/baz").Reply(http.StatusOK).JSON("{}")
+		},
 	}, {
 		name:      "not found file",
 		suiteFile: "testdata/fake.yaml",
@@ -69,6 +123,87 @@ func TestRunSuite(t *testing.T) {
 	}
 }
 
+func TestRunSuiteWithFailedDependency(t *testing.T) {
+	defer gock.Clean()
+	gock.New(urlFoo).Get("/login").Reply(http.StatusUnauthorized)
+	gock.New(urlFoo).Get("/health").Reply(http.StatusOK).JSON("{}")
+
+	opt := newDiscardRunOption()
+	opt.reporter = runner.NewMemoryTestReporter()
+	opt.requestTimeout = 30 * time.Second
+	opt.limiter = limit.NewDefaultRateLimiter(0, 0)
+
+	loader := atest.NewFileLoader()
+	assert.NoError(t, loader.Put("testdata/dependency-suite.yaml"))
+	assert.True(t, loader.HasMore())
+	err := opt.runSuite(loader, getDefaultContext(), context.TODO(), make(chan struct{}, 1))
+	assert.ErrorContains(t, err, "failed to run 'login'")
+	// the independent case keeps running
+	assert.True(t, gock.IsDone())
+
+	records := opt.reporter.GetAllRecords()
+	if assert.Equal(t, 3, len(records)) {
+		assert.Equal(t, "http://foo/order", records[2].API)
+		assert.ErrorContains(t, records[2].Error, "skipped due to the failed dependency 'login'")
+	}
+}
+
+func TestRunSuiteStopped(t *testing.T) {
+	defer gock.Clean()
+	stopSingal := make(chan struct{}, 1)
+	// the run is stopped after the first case is failed
+	gock.New(urlFoo).Get("/bar").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			select {
+			case stopSingal <- struct{}{}:
+			default:
+			}
+			return true, nil
+		}).
+		Reply(http.StatusInternalServerError)
+
+	opt := newDiscardRunOption()
+	opt.requestTimeout = 30 * time.Second
+	opt.limiter = limit.NewDefaultRateLimiter(0, 0)
+	opt.concurrency = 1
+
+	loader := atest.NewFileLoader()
+	assert.NoError(t, loader.Put("testdata/parallel-suite.yaml"))
+	assert.True(t, loader.HasMore())
+	err := opt.runSuite(loader, getDefaultContext(), context.TODO(), stopSingal)
+	assert.ErrorContains(t, err, "failed to run 'bar'")
+}
+
+func TestRunSuiteWithEnvironment(t *testing.T) {
+	defer gock.Clean()
+
+	opt := newDiscardRunOption()
+	opt.requestTimeout = 30 * time.Second
+	opt.limiter = limit.NewDefaultRateLimiter(0, 0)
+	runSuite := func() error {
+		loader := atest.NewFileLoader()
+		assert.NoError(t, loader.Put("testdata/env-suite.yaml"))
+		assert.True(t, loader.HasMore())
+		return opt.runSuite(loader, getDefaultContext(), context.TODO(), make(chan struct{}, 1))
+	}
+
+	gock.New(urlFoo).Get("/user").MatchHeader("Authorization", "Bearer dev-token").Reply(http.StatusOK).JSON("{}")
+	opt.env = "dev"
+	assert.Nil(t, runSuite())
+	assert.True(t, gock.IsDone())
+
+	// the environment file overrides the named environment
+	envFile := filepath.Join(t.TempDir(), "env.yaml")
+	assert.Nil(t, os.WriteFile(envFile, []byte("token: fake"), 0644))
+	gock.New(urlFoo).Get("/user").MatchHeader("Authorization", "Bearer fake").Reply(http.StatusOK).JSON("{}")
+	opt.envFiles = []string{envFile}
+	assert.Nil(t, runSuite())
+	assert.True(t, gock.IsDone())
+
+	opt.env = "fake"
+	assert.ErrorContains(t, runSuite(), "failed to read the environment file")
+}
+
 func TestRunCommand(t *testing.T) {
 	fooPrepare := func() {
 		gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON("{}")
@@ -107,6 +242,14 @@ func TestRunCommand(t *testing.T) {
 	}, {
 		name: "specify a test case",
 		args: []string{"-p", simpleSuite, "fake"},
+	}, {
+		name: "iterations",
+		args: []string{"-p", simpleSuite, "--thread", "2", "--iterations", "3", "--ramp-up", "10ms"},
+		prepare: func() {
+			fooPrepare()
+			fooPrepare()
+			fooPrepare()
+		},
 	}, {
 		name:   "invalid api",
 		args:   []string{"-p", "testdata/invalid-api.yaml"},
@@ -128,11 +271,50 @@ func TestRunCommand(t *testing.T) {
 		},
 		args:   []string{"-p", simpleSuite, "--swagger-url", urlFoo + "/bar"},
 		hasErr: false,
+	}, {
+		name: "API coverage is lower than expected",
+		prepare: func() {
+			gock.New(urlFoo).Get("/swagger").Reply(http.StatusOK).JSON(`{"paths":{"/bar":{"get":{}},"/baz":{"get":{}}}}`)
+			fooPrepare()
+		},
+		args:   []string{"-p", simpleSuite, "--swagger-url", urlFoo + "/swagger", "--min-coverage", "80"},
+		hasErr: true,
+	}, {
+		name: "API coverage is as expected",
+		prepare: func() {
+			gock.New(urlFoo).Get("/swagger").Reply(http.StatusOK).JSON(`{"paths":{"/bar":{"get":{}},"/baz":{"get":{}}}}`)
+			fooPrepare()
+		},
+		args: []string{"-p", simpleSuite, "--swagger-url", urlFoo + "/swagger", "--min-coverage", "50", "--report-ignore"},
+	}, {
+		name:   "min coverage without swagger URL",
+		args:   []string{"-p", simpleSuite, "--min-coverage", "50"},
+		hasErr: true,
+	}, {
+		name:    "dump the requests",
+		prepare: fooPrepare,
+		args:    []string{"-p", simpleSuite, "--dump-file", filepath.Join(t.TempDir(), "dump.log")},
+	}, {
+		name:   "invalid dump file",
+		args:   []string{"-p", simpleSuite, "--dump-file", path.Join(tmpFile.Name(), "fake")},
+		hasErr: true,
+	}, {
+		name:   "invalid redact pattern",
+		args:   []string{"-p", simpleSuite, "--redact-pattern", "("},
+		hasErr: true,
 	}, {
 		name:    "report file with error",
 		prepare: fooPrepare,
 		args:    []string{"-p", simpleSuite, "--report", "md", "--report-file", path.Join(tmpFile.Name(), "fake")},
 		hasErr:  true,
+	}, {
+		name:    "resource usage and pprof",
+		prepare: fooPrepare,
+		args:    []string{"-p", simpleSuite, "--report-resource-usage", "--pprof", "127.0.0.1:0"},
+	}, {
+		name:   "invalid pprof address",
+		args:   []string{"-p", simpleSuite, "--pprof", "fake"},
+		hasErr: true,
 	}, {
 		name:    "malformed report file path",
 		prepare: fooPrepare,
@@ -146,7 +328,7 @@ func TestRunCommand(t *testing.T) {
 			util.MakeSureNotNil(tt.prepare)()
 			root := &cobra.Command{Use: "root"}
 			root.SetOut(buf)
-			root.AddCommand(createRunCommand())
+			root.AddCommand(createRunCommand(fakeruntime.FakeExecer{}))
 
 			root.SetArgs(append([]string{"run"}, tt.args...))
 
@@ -156,6 +338,78 @@ func TestRunCommand(t *testing.T) {
 	}
 }
 
+func TestRunWithCassette(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) (output string, err error) {
+		buf := new(bytes.Buffer)
+		root := &cobra.Command{Use: "root"}
+		root.SetOut(buf)
+		root.AddCommand(createRunCommand(fakeruntime.FakeExecer{}))
+		root.SetArgs(append([]string{"run", "-p", simpleSuite, "--cassette-dir", dir}, args...))
+		err = root.Execute()
+		output = buf.String()
+		return
+	}
+
+	_, err := run("--cassette", "replay")
+	assert.Error(t, err, "the cassette is not recorded")
+
+	gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON(`{"name":"linuxsuren"}`)
+	_, err = run("--cassette", "record")
+	gock.Off()
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "Simple.yaml"))
+
+	// the requests are not sent in the replay mode
+	_, err = run("--cassette", "replay")
+	assert.NoError(t, err)
+
+	gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON(`{"name":1}`)
+	output, err := run("--cassette", "record")
+	gock.Off()
+	assert.NoError(t, err)
+	assert.Contains(t, output, "contract drift: GET http://foo/bar: field $.name string -> number")
+
+	_, err = run("--cassette", "fake")
+	assert.Error(t, err)
+
+	assert.Equal(t, filepath.Join("dir", "a-b.yaml"), cassetteFile("dir", "a/b"))
+	assert.Equal(t, filepath.Join("dir", "default.yaml"), cassetteFile("dir", "/"))
+}
+
+func TestRunWithWarmUp(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlFoo).Get("/bar").Times(3).Reply(http.StatusOK).JSON(`{}`)
+
+	buf := new(bytes.Buffer)
+	root := &cobra.Command{Use: "root"}
+	root.SetOut(buf)
+	root.AddCommand(createRunCommand(fakeruntime.FakeExecer{}))
+	root.SetArgs([]string{"run", "-p", simpleSuite, "--iterations", "2", "--warm-up-iterations", "1", "--report", "json"})
+	assert.NoError(t, root.Execute())
+	assert.True(t, gock.IsDone())
+	assert.Contains(t, buf.String(), "warm-up: 1 requests are excluded from the report")
+	assert.Contains(t, buf.String(), `"Count":2`)
+}
+
+func TestRunWithPact(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON(`{"name":"linuxsuren"}`)
+
+	dir := t.TempDir()
+	root := &cobra.Command{Use: "root"}
+	root.SetOut(new(bytes.Buffer))
+	root.AddCommand(createRunCommand(fakeruntime.FakeExecer{}))
+	root.SetArgs([]string{"run", "-p", simpleSuite, "--pact-dir", dir, "--pact-consumer", "web"})
+	assert.NoError(t, root.Execute())
+
+	data, err := os.ReadFile(filepath.Join(dir, "web-Simple.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"description": "bar"`)
+
+	assert.Equal(t, filepath.Join("dir", "a-b-c.json"), pactFile("dir", "a", "b/c"))
+}
+
 func TestPreRunE(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -206,6 +460,24 @@ func TestPreRunE(t *testing.T) {
 			assert.Nil(t, err)
 			assert.NotNil(t, ro.reportWriter)
 		},
+	}, {
+		name: "tap report",
+		opt: &runOption{
+			report: "tap",
+		},
+		verify: func(t *testing.T, ro *runOption, err error) {
+			assert.Nil(t, err)
+			assert.NotNil(t, ro.reportWriter)
+		},
+	}, {
+		name: "console report",
+		opt: &runOption{
+			report: "console",
+		},
+		verify: func(t *testing.T, ro *runOption, err error) {
+			assert.Nil(t, err)
+			assert.NotNil(t, ro.reportWriter)
+		},
 	}, {
 		name: "empty report",
 		opt: &runOption{
@@ -224,6 +496,54 @@ func TestPreRunE(t *testing.T) {
 			assert.NotNil(t, err)
 			assert.Nil(t, ro.reportWriter)
 		},
+	}, {
+		name: "metrics",
+		opt: &runOption{
+			reporter:    runner.NewMemoryTestReporter(),
+			pushgateway: "http://localhost:9091",
+		},
+		verify: func(t *testing.T, ro *runOption, err error) {
+			assert.Nil(t, err)
+			assert.NotNil(t, ro.metrics)
+			assert.Equal(t, ro.metrics, ro.reporter)
+		},
+	}, {
+		name: "stream",
+		opt: &runOption{
+			reporter: runner.NewMemoryTestReporter(),
+			stream:   "ndjson",
+		},
+		verify: func(t *testing.T, ro *runOption, err error) {
+			assert.Nil(t, err)
+			assert.NotEqual(t, runner.NewMemoryTestReporter(), ro.reporter)
+		},
+	}, {
+		name: "invalid stream",
+		opt: &runOption{
+			stream: "fake",
+		},
+		verify: func(t *testing.T, ro *runOption, err error) {
+			assert.ErrorContains(t, err, "not supported stream type: 'fake'")
+		},
+	}, {
+		name: "secret providers",
+		opt: &runOption{
+			secrets: []string{"env://ATEST_", "file://testdata/fake.yaml"},
+		},
+		verify: func(t *testing.T, ro *runOption, err error) {
+			defer secret.SetProviders(secret.NewEnvProvider(""))
+			assert.Nil(t, err)
+			_, err = secret.Get("fake")
+			assert.ErrorContains(t, err, "failed to get secret")
+		},
+	}, {
+		name: "invalid secret provider",
+		opt: &runOption{
+			secrets: []string{"fake://foo"},
+		},
+		verify: func(t *testing.T, ro *runOption, err error) {
+			assert.ErrorContains(t, err, "not supported secret provider")
+		},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -248,3 +568,72 @@ func TestPrinter(t *testing.T) {
 
 const urlFoo = "http://foo"
 const simpleSuite = "testdata/simple-suite.yaml"
+
+func TestIsTerminal(t *testing.T) {
+	assert.False(t, isTerminal(new(bytes.Buffer)))
+
+	file, err := os.CreateTemp(t.TempDir(), "report")
+	if assert.Nil(t, err) {
+		defer file.Close()
+		assert.False(t, isTerminal(file))
+	}
+}
+
+func TestRunWithTargetQPS(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlFoo).Get("/bar").Times(4).Reply(http.StatusOK).JSON("{}")
+
+	buf := new(bytes.Buffer)
+	root := &cobra.Command{Use: "root"}
+	root.SetOut(buf)
+	root.AddCommand(createRunCommand(fakeruntime.FakeExecer{}))
+	root.SetArgs([]string{"run", "-p", simpleSuite, "--iterations", "4", "--target-qps", "20", "--burst", "1"})
+
+	begin := time.Now()
+	assert.NoError(t, root.Execute())
+	// the first token is in the bucket, the other three are refilled at 20 QPS
+	assert.GreaterOrEqual(t, time.Since(begin), 100*time.Millisecond)
+	assert.Contains(t, buf.String(), "benchmark: target QPS: 20.00, achieved QPS: ")
+	assert.Contains(t, buf.String(), "requests: 4, errors: 0, latency p50: ")
+}
+
+func TestRunSuiteWithThinkTime(t *testing.T) {
+	defer gock.Clean()
+	gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON("{}")
+	gock.New(urlFoo).Get("/baz").Reply(http.StatusOK).JSON("{}")
+
+	opt := newDiscardRunOption()
+	opt.requestTimeout = 30 * time.Second
+	opt.limiter = limit.NewDefaultRateLimiter(0, 0)
+
+	loader := atest.NewFileLoader()
+	assert.NoError(t, loader.Put("testdata/think-time-suite.yaml"))
+	begin := time.Now()
+	assert.NoError(t, opt.runSuite(loader, getDefaultContext(), context.TODO(), make(chan struct{})))
+	// the think time of the suite is after the first case, the second case has no think time
+	assert.GreaterOrEqual(t, time.Since(begin), 100*time.Millisecond)
+	assert.Less(t, time.Since(begin), time.Second)
+	assert.True(t, gock.IsDone())
+}
+
+func TestRunSuiteWithRateLimit(t *testing.T) {
+	defer gock.Clean()
+	for _, api := range []string{"/bar", "/baz", "/qux"} {
+		gock.New(urlFoo).Get(api).Reply(http.StatusOK).JSON("{}")
+	}
+
+	opt := newDiscardRunOption()
+	opt.requestTimeout = 30 * time.Second
+	opt.limiter = limit.NewDefaultRateLimiter(100, 100)
+
+	loader := atest.NewFileLoader()
+	assert.NoError(t, loader.Put("testdata/rate-limit-suite.yaml"))
+	begin := time.Now()
+	assert.NoError(t, opt.runSuite(loader, getDefaultContext(), context.TODO(), make(chan struct{})))
+	// the parallel cases share the rate limit of the suite
+	assert.GreaterOrEqual(t, time.Since(begin), 150*time.Millisecond)
+	assert.True(t, gock.IsDone())
+
+	suite := &atest.TestSuite{Name: "RateLimit", RateLimit: 10}
+	assert.Same(t, opt.getRateLimiter(suite), opt.getRateLimiter(suite), "the rate limiter is shared by the runs")
+}
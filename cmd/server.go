@@ -6,13 +6,17 @@ import (
 	"log"
 	"net"
 
+	"github.com/linuxsuren/api-testing/pkg/auth"
+	"github.com/linuxsuren/api-testing/pkg/extension"
 	"github.com/linuxsuren/api-testing/pkg/server"
+	"github.com/linuxsuren/api-testing/pkg/store"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 )
 
-func createServerCmd(gRPCServer gRPCServer) (c *cobra.Command) {
-	opt := &serverOption{gRPCServer: gRPCServer}
+func createServerCmd(execer fakeruntime.Execer, gRPCServer gRPCServer) (c *cobra.Command) {
+	opt := &serverOption{gRPCServer: gRPCServer, execer: execer}
 	c = &cobra.Command{
 		Use:   "server",
 		Short: "Run as a server mode",
@@ -21,13 +25,21 @@ func createServerCmd(gRPCServer gRPCServer) (c *cobra.Command) {
 	flags := c.Flags()
 	flags.IntVarP(&opt.port, "port", "p", 7070, "The RPC server port")
 	flags.BoolVarP(&opt.printProto, "print-proto", "", false, "Print the proto content and exit")
+	flags.StringVarP(&opt.store, "store", "", ".", "The store of the test suites, such as: a local directory, git+https://xxx.git#branch, s3://bucket/prefix, configmap://namespace/name, ext://name")
+	flags.StringSliceVarP(&opt.extensionDirs, "extension-dir", "", []string{extension.DefaultDir()}, "The directories of the extensions")
+	flags.StringVarP(&opt.authConfig, "auth-config", "", "", "The authentication config file, enable the multi-tenant mode if it's not empty")
 	return
 }
 
 type serverOption struct {
 	gRPCServer gRPCServer
+	execer     fakeruntime.Execer
 	port       int
 	printProto bool
+	store      string
+	authConfig string
+
+	extensionDirs []string
 }
 
 func (o *serverOption) runE(cmd *cobra.Command, args []string) (err error) {
@@ -38,6 +50,26 @@ func (o *serverOption) runE(cmd *cobra.Command, args []string) (err error) {
 		return
 	}
 
+	extensions := extension.NewManager()
+	if err = extensions.Discover(o.extensionDirs...); err != nil {
+		return
+	}
+	defer extensions.Stop()
+
+	var suiteStore store.Store
+	if suiteStore, err = getStore(o.store, o.execer, extensions); err != nil {
+		return
+	}
+
+	var authenticator auth.Authenticator
+	if o.authConfig != "" {
+		var authConfig *auth.Config
+		if authConfig, err = auth.LoadConfig(o.authConfig); err != nil {
+			return
+		}
+		authenticator = auth.NewAuthenticator(authConfig)
+	}
+
 	var lis net.Listener
 	lis, err = net.Listen("tcp", fmt.Sprintf(":%d", o.port))
 	if err != nil {
@@ -45,7 +77,7 @@ func (o *serverOption) runE(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	s := o.gRPCServer
-	server.RegisterRunnerServer(s, server.NewRemoteServer())
+	server.RegisterRunnerServer(s, server.NewRemoteServerWithAuth(suiteStore, authenticator))
 	log.Printf("server listening at %v", lis.Addr())
 	s.Serve(lis)
 	return
@@ -33,6 +33,18 @@ func TestPrintProto(t *testing.T) {
 		verify: func(t *testing.T, buf *bytes.Buffer, err error) {
 			assert.Nil(t, err)
 		},
+	}, {
+		name: "with auth config",
+		args: []string{"server", "-p=0", "--auth-config", "../sample/auth.yaml"},
+		verify: func(t *testing.T, buf *bytes.Buffer, err error) {
+			assert.Nil(t, err)
+		},
+	}, {
+		name: "auth config not found",
+		args: []string{"server", "-p=0", "--auth-config", "testdata/fake.yaml"},
+		verify: func(t *testing.T, buf *bytes.Buffer, err error) {
+			assert.NotNil(t, err)
+		},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/linuxsuren/api-testing/cmd"
@@ -12,6 +13,10 @@ func main() {
 	gRPCServer := grpc.NewServer()
 	c := cmd.NewRootCmd(exec.DefaultExecer{}, gRPCServer)
 	if err := c.Execute(); err != nil {
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
 		os.Exit(1)
 	}
 }
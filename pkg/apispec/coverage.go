@@ -0,0 +1,106 @@
+package apispec
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// API is an operation of the spec, or a request which is sent by the test cases
+type API struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// String returns the API in the format of "METHOD path"
+func (a API) String() string {
+	return strings.ToUpper(a.Method) + " " + a.Path
+}
+
+// ParseAPI parses the API of the report results which is in the format of "METHOD url", the method is GET if it's missing
+func ParseAPI(api string) (result API) {
+	result = API{Method: "GET", Path: api}
+	if items := strings.SplitN(api, " ", 2); len(items) == 2 {
+		if items[0] != "" {
+			result.Method = strings.ToUpper(items[0])
+		}
+		result.Path = items[1]
+	}
+
+	if u, err := url.Parse(result.Path); err == nil && u.Path != "" {
+		result.Path = u.Path
+	}
+	return
+}
+
+// Coverage is the operations of the spec which are exercised or not by the requests
+type Coverage struct {
+	Covered []API   `json:"covered"`
+	Missed  []API   `json:"missed"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+}
+
+// Summary returns the count and the percent of the covered operations, such as: 3/4 (75%)
+func (c *Coverage) Summary() string {
+	return fmt.Sprintf("%d/%d (%s%%)", len(c.Covered), c.Total, strconv.FormatFloat(math.Round(c.Percent*100)/100, 'f', -1, 64))
+}
+
+// NewCoverage checks which operations of the spec are exercised by the requests. A request exercises an operation
+// if the method is the same, and the path ends with the path of the operation, the path parameters match any segment
+func NewCoverage(spec APIConverage, requests []API) (coverage *Coverage) {
+	coverage = &Coverage{Covered: []API{}, Missed: []API{}}
+	for _, api := range spec.APIs() {
+		covered := false
+		for _, request := range requests {
+			if strings.EqualFold(api.Method, request.Method) && MatchPath(api.Path, request.Path) {
+				covered = true
+				break
+			}
+		}
+
+		api.Method = strings.ToUpper(api.Method)
+		if covered {
+			coverage.Covered = append(coverage.Covered, api)
+		} else {
+			coverage.Missed = append(coverage.Missed, api)
+		}
+	}
+
+	coverage.Total = len(coverage.Covered) + len(coverage.Missed)
+	if coverage.Total > 0 {
+		coverage.Percent = float64(len(coverage.Covered)) * 100 / float64(coverage.Total)
+	}
+	return
+}
+
+// MatchPath checks if the path matches the template, such as: /api/v1/users/linuxsuren matches /users/{name}.
+// The leading segments of the path are ignored, they're the base path of the spec
+func MatchPath(template, path string) bool {
+	templates, segments := splitPath(template), splitPath(path)
+	if len(segments) < len(templates) {
+		return false
+	}
+
+	segments = segments[len(segments)-len(templates):]
+	for i, segment := range templates {
+		if segments[i] != segment && !(isPathParameter(segment) && segments[i] != "") {
+			return false
+		}
+	}
+	return true
+}
+
+// sortAPIs sorts the APIs by the path, then the method
+func sortAPIs(apis []API) []API {
+	sort.Slice(apis, func(i, j int) bool {
+		if apis[i].Path == apis[j].Path {
+			return apis[i].Method < apis[j].Method
+		}
+		return apis[i].Path < apis[j].Path
+	})
+	return apis
+}
@@ -0,0 +1,92 @@
+package apispec_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAPI(t *testing.T) {
+	tests := []struct {
+		name   string
+		api    string
+		expect apispec.API
+	}{{
+		name:   "only path",
+		api:    "/api",
+		expect: apispec.API{Method: "GET", Path: "/api"},
+	}, {
+		name:   "with method and URL",
+		api:    "post http://localhost:8080/api/users?page=1",
+		expect: apispec.API{Method: "POST", Path: "/api/users"},
+	}, {
+		name:   "empty method",
+		api:    " /api",
+		expect: apispec.API{Method: "GET", Path: "/api"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, apispec.ParseAPI(tt.api))
+		})
+	}
+	assert.Equal(t, "DELETE /api", apispec.API{Method: "delete", Path: "/api"}.String())
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		template, path string
+		expect         bool
+	}{
+		{template: "/users", path: "/users", expect: true},
+		{template: "/users", path: "/api/v1/users", expect: true},
+		{template: "/users/{name}", path: "/api/v1/users/linuxsuren", expect: true},
+		{template: "/users/{name}", path: "/users/", expect: false},
+		{template: "/users/{name}", path: "/users", expect: false},
+		{template: "/users", path: "/groups", expect: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expect, apispec.MatchPath(tt.template, tt.path))
+		})
+	}
+}
+
+func TestNewCoverage(t *testing.T) {
+	spec := apispec.NewFakeAPISpec([][]string{{"/users", "get"}, {"/users", "post"}, {"/users/{name}", "delete"}})
+
+	t.Run("partial", func(t *testing.T) {
+		coverage := apispec.NewCoverage(spec, []apispec.API{
+			{Method: "GET", Path: "/api/users"},
+			{Method: "delete", Path: "/api/users/linuxsuren"},
+		})
+		assert.Equal(t, []apispec.API{{Method: "GET", Path: "/users"}, {Method: "DELETE", Path: "/users/{name}"}}, coverage.Covered)
+		assert.Equal(t, []apispec.API{{Method: "POST", Path: "/users"}}, coverage.Missed)
+		assert.Equal(t, 3, coverage.Total)
+		assert.Equal(t, "2/3 (66.67%)", coverage.Summary())
+	})
+
+	t.Run("no requests", func(t *testing.T) {
+		coverage := apispec.NewCoverage(spec, nil)
+		assert.Empty(t, coverage.Covered)
+		assert.Equal(t, "0/3 (0%)", coverage.Summary())
+	})
+
+	t.Run("empty spec", func(t *testing.T) {
+		coverage := apispec.NewCoverage(apispec.NewFakeAPISpec(nil), nil)
+		assert.Equal(t, float64(0), coverage.Percent)
+		assert.Equal(t, "0/0 (0%)", coverage.Summary())
+	})
+}
+
+func TestSwaggerAPIs(t *testing.T) {
+	swagger, err := apispec.ParseToSwagger([]byte(testdataSwaggerJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, []apispec.API{
+		{Method: "GET", Path: "/api/v1/users"},
+		{Method: "POST", Path: "/api/v1/users"},
+		{Method: "DELETE", Path: "/api/v1/users/{user}"},
+		{Method: "GET", Path: "/api/v1/users/{user}"},
+		{Method: "PUT", Path: "/api/v1/users/{user}"},
+	}, swagger.APIs())
+}
@@ -25,3 +25,13 @@ func (f *fakeAPISpec) APICount() (count int) {
 	count = len(f.apis)
 	return
 }
+
+// APIs is fake method
+func (f *fakeAPISpec) APIs() (apis []API) {
+	for _, item := range f.apis {
+		if len(item) >= 2 {
+			apis = append(apis, API{Method: item[1], Path: item[0]})
+		}
+	}
+	return
+}
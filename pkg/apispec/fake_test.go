@@ -38,6 +38,7 @@ func TestFakeAPISpec(t *testing.T) {
 			count := coverage.APICount()
 			assert.Equal(t, tt.expectExist, exist)
 			assert.Equal(t, tt.expectCount, count)
+			assert.Equal(t, tt.expectCount, len(coverage.APIs()))
 		})
 	}
 }
@@ -0,0 +1,480 @@
+package apispec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// draft2020 makes the schemas of OpenAPI 3.1 are converted by the JSON schema validation
+const draft2020 = "https://json-schema.org/draft/2020-12/schema"
+
+// OpenAPI is the OpenAPI v3 or Swagger v2 document, it finds the declared responses of the operations
+type OpenAPI struct {
+	doc        map[string]interface{}
+	templates  []openAPITemplate
+	swagger    bool
+	jsonSchema string
+}
+
+type openAPITemplate struct {
+	segments  []string
+	literals  int
+	path      string
+	operation map[string]interface{}
+	method    string
+}
+
+// LoadOpenAPI loads the document from the URL, or the file which is relative to the directory
+func LoadOpenAPI(source, dir string) (spec *OpenAPI, err error) {
+	var data []byte
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		var resp *http.Response
+		if resp, err = http.Get(source); err != nil {
+			return
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("failed to get the OpenAPI document from %s, status code: %d", source, resp.StatusCode)
+			return
+		}
+		data, err = io.ReadAll(resp.Body)
+	} else {
+		if !filepath.IsAbs(source) && dir != "" {
+			source = filepath.Join(dir, source)
+		}
+		data, err = os.ReadFile(source)
+	}
+
+	if err == nil {
+		spec, err = ParseOpenAPI(data)
+	}
+	return
+}
+
+// ParseOpenAPI parses the document in JSON or YAML
+func ParseOpenAPI(data []byte) (spec *OpenAPI, err error) {
+	doc := map[string]interface{}{}
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		err = fmt.Errorf("invalid OpenAPI document: %v", err)
+		return
+	}
+
+	openapi, _ := doc["openapi"].(string)
+	swagger, _ := doc["swagger"].(string)
+	if openapi == "" && swagger == "" {
+		err = fmt.Errorf("invalid OpenAPI document: no openapi or swagger version")
+		return
+	}
+
+	spec = &OpenAPI{doc: doc, swagger: swagger != ""}
+	if strings.HasPrefix(openapi, "3.1") {
+		spec.jsonSchema = draft2020
+	}
+
+	basePaths := []string{""}
+	if basePath, _ := doc["basePath"].(string); basePath != "" {
+		basePaths = append(basePaths, strings.TrimSuffix(basePath, "/"))
+	}
+	servers, _ := doc["servers"].([]interface{})
+	for _, server := range servers {
+		if item, ok := server.(map[string]interface{}); ok {
+			serverURL, _ := item["url"].(string)
+			if basePath := serverPath(serverURL); basePath != "" {
+				basePaths = append(basePaths, basePath)
+			}
+		}
+	}
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for path, item := range paths {
+		operations, _ := item.(map[string]interface{})
+		for method, operation := range operations {
+			operationMap, ok := operation.(map[string]interface{})
+			if !ok || !isHTTPMethod(method) {
+				continue
+			}
+			for _, basePath := range basePaths {
+				spec.templates = append(spec.templates, newOpenAPITemplate(basePath+path, path, method, operationMap))
+			}
+		}
+	}
+	// the templates with more literal segments take precedence, such as: /users/me over /users/{id}
+	sort.SliceStable(spec.templates, func(i, j int) bool {
+		if spec.templates[i].literals == spec.templates[j].literals {
+			return spec.templates[i].path < spec.templates[j].path
+		}
+		return spec.templates[i].literals > spec.templates[j].literals
+	})
+	return
+}
+
+// ResponseSchema finds the operation of the request, then returns the JSON schema of the declared response.
+// The operation is empty if it's not declared, and the schema is empty if the response is not JSON or has no schema.
+// It returns an error if the status code or the content type is not declared by the operation
+func (o *OpenAPI) ResponseSchema(method, path string, status int, contentType string) (operation, schema string, err error) {
+	template := o.match(method, path)
+	if template == nil {
+		return
+	}
+	operation = fmt.Sprintf("%s %s", strings.ToUpper(template.method), template.path)
+
+	responses, _ := template.operation["responses"].(map[string]interface{})
+	response := o.resolve(findResponse(responses, status))
+	if response == nil {
+		err = fmt.Errorf("the status code %d is not declared", status)
+		return
+	}
+
+	var declared interface{}
+	if o.swagger {
+		declared = response["schema"]
+	} else if content, ok := response["content"].(map[string]interface{}); ok && len(content) > 0 {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		media, key := findMediaType(content, mediaType)
+		if media == nil {
+			err = fmt.Errorf("the content type %q is not declared", contentType)
+			return
+		}
+		if isJSONMediaType(key) || isJSONMediaType(mediaType) {
+			declared = media["schema"]
+		}
+	}
+	if declared == nil {
+		return
+	}
+
+	// the references of the schema are resolved in the document
+	root := map[string]interface{}{
+		"allOf": []interface{}{declared},
+	}
+	for _, key := range []string{"components", "definitions"} {
+		if val, ok := o.doc[key]; ok {
+			root[key] = val
+		}
+	}
+	if o.jsonSchema != "" {
+		root["$schema"] = o.jsonSchema
+	} else {
+		convertNullable(root)
+	}
+
+	var data []byte
+	if data, err = json.Marshal(root); err == nil {
+		schema = string(data)
+	}
+	return
+}
+
+func (o *OpenAPI) match(method, path string) *openAPITemplate {
+	method = strings.ToLower(method)
+	segments := splitPath(path)
+	for i := range o.templates {
+		if template := &o.templates[i]; template.method == method && template.match(segments) {
+			return template
+		}
+	}
+	return nil
+}
+
+// resolve returns the referenced object of the document, such as: #/components/responses/NotFound
+func (o *OpenAPI) resolve(val interface{}) map[string]interface{} {
+	item, _ := val.(map[string]interface{})
+	for i := 0; item != nil && i < 10; i++ {
+		ref, ok := item["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			break
+		}
+
+		var target interface{} = o.doc
+		for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			key = strings.NewReplacer("~1", "/", "~0", "~").Replace(key)
+			parent, _ := target.(map[string]interface{})
+			target = parent[key]
+		}
+		item, _ = target.(map[string]interface{})
+	}
+	return item
+}
+
+func newOpenAPITemplate(fullPath, path, method string, operation map[string]interface{}) (template openAPITemplate) {
+	template = openAPITemplate{
+		segments:  splitPath(fullPath),
+		path:      path,
+		method:    strings.ToLower(method),
+		operation: operation,
+	}
+	for _, segment := range template.segments {
+		if !isPathParameter(segment) {
+			template.literals++
+		}
+	}
+	return
+}
+
+func (t *openAPITemplate) match(segments []string) bool {
+	if len(segments) != len(t.segments) {
+		return false
+	}
+	for i, segment := range t.segments {
+		if segments[i] != segment && !(isPathParameter(segment) && segments[i] != "") {
+			return false
+		}
+	}
+	return true
+}
+
+// findResponse returns the response of the status code, the range of it, such as: 2XX, or the default one
+func findResponse(responses map[string]interface{}, status int) interface{} {
+	code := strconv.Itoa(status)
+	if response, ok := responses[code]; ok {
+		return response
+	}
+	for key, response := range responses {
+		if strings.EqualFold(key, code[:1]+"XX") {
+			return response
+		}
+	}
+	return responses["default"]
+}
+
+// findMediaType returns the media type which matches exactly, then the wildcards, such as: application/* and */*
+func findMediaType(content map[string]interface{}, mediaType string) (media map[string]interface{}, key string) {
+	candidates := []string{mediaType}
+	if index := strings.Index(mediaType, "/"); index > 0 {
+		candidates = append(candidates, mediaType[:index]+"/*")
+	}
+	candidates = append(candidates, "*/*")
+
+	for _, candidate := range candidates {
+		for key, val := range content {
+			if declared, _, err := mime.ParseMediaType(key); err == nil && strings.EqualFold(declared, candidate) {
+				media, _ = val.(map[string]interface{})
+				if media == nil {
+					media = map[string]interface{}{}
+				}
+				return media, key
+			}
+		}
+	}
+	return
+}
+
+// convertNullable converts the nullable of OpenAPI 3.0 to the null type of the JSON schema
+func convertNullable(schema map[string]interface{}) {
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		if schemaType, ok := schema["type"].(string); ok {
+			schema["type"] = []interface{}{schemaType, "null"}
+		}
+	}
+	for key, val := range schema {
+		switch key {
+		case "example", "examples", "enum", "default", "const":
+			continue
+		}
+		switch sub := val.(type) {
+		case map[string]interface{}:
+			convertNullable(sub)
+		case []interface{}:
+			for _, item := range sub {
+				if itemMap, ok := item.(map[string]interface{}); ok {
+					convertNullable(itemMap)
+				}
+			}
+		}
+	}
+}
+
+// serverPath returns the path of the server URL, the variables of the URL are kept as they are
+func serverPath(serverURL string) string {
+	if index := strings.Index(serverURL, "://"); index >= 0 {
+		serverURL = serverURL[index+3:]
+		if index = strings.Index(serverURL, "/"); index < 0 {
+			return ""
+		}
+		serverURL = serverURL[index:]
+	}
+	return strings.TrimSuffix(serverURL, "/")
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func isPathParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func isHTTPMethod(method string) bool {
+	switch strings.ToLower(method) {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+		return true
+	}
+	return false
+}
+
+func isJSONMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// Operation is an operation of the document, the references of the parameters and the body are resolved
+type Operation struct {
+	Method     string
+	Path       string
+	Parameters []Parameter
+	// Body is the schema of the JSON request body, it's nil if there is no such body
+	Body map[string]interface{}
+}
+
+// Parameter is a path, query or header parameter of an operation
+type Parameter struct {
+	Name     string
+	In       string
+	Required bool
+	Schema   map[string]interface{}
+}
+
+// Operations returns all the operations which are sorted by the path and the method
+func (o *OpenAPI) Operations() (operations []Operation) {
+	paths, _ := o.doc["paths"].(map[string]interface{})
+	for path, item := range paths {
+		pathItem, _ := item.(map[string]interface{})
+		for method, val := range pathItem {
+			operation, ok := val.(map[string]interface{})
+			if !ok || !isHTTPMethod(method) {
+				continue
+			}
+
+			result := Operation{Method: strings.ToUpper(method), Path: path}
+			// the parameters of the operation override the ones of the path item
+			parameters := map[string]int{}
+			for _, list := range []interface{}{pathItem["parameters"], operation["parameters"]} {
+				items, _ := list.([]interface{})
+				for _, param := range items {
+					parameter, body := o.parameter(param)
+					if body != nil {
+						result.Body = body
+						continue
+					}
+					if parameter == nil {
+						continue
+					}
+					key := parameter.In + "/" + parameter.Name
+					if index, ok := parameters[key]; ok {
+						result.Parameters[index] = *parameter
+					} else {
+						parameters[key] = len(result.Parameters)
+						result.Parameters = append(result.Parameters, *parameter)
+					}
+				}
+			}
+			if body := o.requestBody(operation); body != nil {
+				result.Body = body
+			}
+			operations = append(operations, result)
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Path == operations[j].Path {
+			return operations[i].Method < operations[j].Method
+		}
+		return operations[i].Path < operations[j].Path
+	})
+	return
+}
+
+// parameter returns the path, query or header parameter, or the body schema of the Swagger v2 body parameter
+func (o *OpenAPI) parameter(val interface{}) (parameter *Parameter, body map[string]interface{}) {
+	item := o.resolve(val)
+	if item == nil {
+		return
+	}
+	name, _ := item["name"].(string)
+	in, _ := item["in"].(string)
+	switch in {
+	case "body":
+		body = o.resolveSchema(item["schema"], 0)
+		return
+	case "path", "query", "header":
+	default:
+		return
+	}
+
+	required, _ := item["required"].(bool)
+	parameter = &Parameter{Name: name, In: in, Required: required || in == "path"}
+	if schema, ok := item["schema"]; ok {
+		parameter.Schema = o.resolveSchema(schema, 0)
+	} else {
+		// the type of the Swagger v2 parameter is declared by itself
+		parameter.Schema = o.resolveSchema(item, 0)
+	}
+	return
+}
+
+// requestBody returns the schema of the JSON request body of the OpenAPI v3 operation
+func (o *OpenAPI) requestBody(operation map[string]interface{}) (body map[string]interface{}) {
+	requestBody := o.resolve(operation["requestBody"])
+	content, _ := requestBody["content"].(map[string]interface{})
+	for mediaType, val := range content {
+		if media, ok := val.(map[string]interface{}); ok && isJSONMediaType(strings.Split(mediaType, ";")[0]) {
+			body = o.resolveSchema(media["schema"], 0)
+			break
+		}
+	}
+	return
+}
+
+// resolveSchema returns a copy of the schema which has no references, the recursive references are cut off
+func (o *OpenAPI) resolveSchema(val interface{}, depth int) (schema map[string]interface{}) {
+	item := o.resolve(val)
+	if item == nil || depth > 10 {
+		return
+	}
+
+	schema = map[string]interface{}{}
+	for key, sub := range item {
+		switch key {
+		case "$ref":
+			continue
+		case "properties":
+			properties := map[string]interface{}{}
+			subMap, _ := sub.(map[string]interface{})
+			for name, property := range subMap {
+				if resolved := o.resolveSchema(property, depth+1); resolved != nil {
+					properties[name] = resolved
+				}
+			}
+			schema[key] = properties
+		case "items", "additionalProperties":
+			if resolved := o.resolveSchema(sub, depth+1); resolved != nil {
+				schema[key] = resolved
+			} else {
+				schema[key] = sub
+			}
+		case "allOf", "oneOf", "anyOf":
+			var schemas []interface{}
+			list, _ := sub.([]interface{})
+			for _, element := range list {
+				if resolved := o.resolveSchema(element, depth+1); resolved != nil {
+					schemas = append(schemas, resolved)
+				}
+			}
+			schema[key] = schemas
+		default:
+			schema[key] = sub
+		}
+	}
+	return
+}
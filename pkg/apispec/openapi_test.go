@@ -0,0 +1,242 @@
+package apispec_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIResponseSchema(t *testing.T) {
+	spec, err := apispec.LoadOpenAPI("openapi.yaml", "testdata")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		contentType string
+		operation   string
+		schema      bool
+		err         string
+	}{{
+		name:        "with the path of the server",
+		method:      http.MethodGet,
+		path:        "/api/v1/users/1",
+		status:      http.StatusOK,
+		contentType: "application/json; charset=utf-8",
+		operation:   "GET /users/{id}",
+		schema:      true,
+	}, {
+		name:        "without the path of the server",
+		method:      http.MethodGet,
+		path:        "/users/1",
+		status:      http.StatusOK,
+		contentType: "application/json",
+		operation:   "GET /users/{id}",
+		schema:      true,
+	}, {
+		name:        "the literal path takes precedence",
+		method:      http.MethodGet,
+		path:        "/api/v1/users/me",
+		status:      http.StatusAccepted,
+		contentType: "application/json",
+		operation:   "GET /users/me",
+		schema:      true,
+	}, {
+		name:        "referenced response",
+		method:      http.MethodGet,
+		path:        "/users/1",
+		status:      http.StatusNotFound,
+		contentType: "application/problem+json",
+		operation:   "GET /users/{id}",
+		schema:      true,
+	}, {
+		name:      "no content",
+		method:    http.MethodPost,
+		path:      "/users",
+		status:    http.StatusCreated,
+		operation: "POST /users",
+	}, {
+		name:        "default response which is not JSON",
+		method:      http.MethodPost,
+		path:        "/users",
+		status:      http.StatusBadRequest,
+		contentType: "text/plain",
+		operation:   "POST /users",
+	}, {
+		name:   "not declared operation",
+		method: http.MethodDelete,
+		path:   "/users/1",
+		status: http.StatusOK,
+	}, {
+		name:        "not declared status code",
+		method:      http.MethodGet,
+		path:        "/users/1",
+		status:      http.StatusInternalServerError,
+		contentType: "application/json",
+		operation:   "GET /users/{id}",
+		err:         "the status code 500 is not declared",
+	}, {
+		name:        "not declared content type",
+		method:      http.MethodGet,
+		path:        "/users/1",
+		status:      http.StatusOK,
+		contentType: "text/html",
+		operation:   "GET /users/{id}",
+		err:         `the content type "text/html" is not declared`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, schema, err := spec.ResponseSchema(tt.method, tt.path, tt.status, tt.contentType)
+			assert.Equal(t, tt.operation, operation)
+			assert.Equal(t, tt.schema, schema != "")
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestOpenAPINullable(t *testing.T) {
+	spec, err := apispec.LoadOpenAPI("testdata/openapi.yaml", "")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	_, schema, err := spec.ResponseSchema(http.MethodGet, "/users/1", http.StatusOK, "application/json")
+	assert.Nil(t, err)
+
+	root := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(schema), &root))
+	assert.Equal(t, []interface{}{map[string]interface{}{"$ref": "#/components/schemas/User"}}, root["allOf"])
+	assert.NotContains(t, root, "$schema")
+
+	user := root["components"].(map[string]interface{})["schemas"].(map[string]interface{})["User"].(map[string]interface{})
+	properties := user["properties"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"string", "null"}, properties["email"].(map[string]interface{})["type"])
+	assert.Equal(t, "string", properties["name"].(map[string]interface{})["type"])
+}
+
+func TestParseOpenAPI(t *testing.T) {
+	t.Run("swagger", func(t *testing.T) {
+		spec, err := apispec.ParseOpenAPI([]byte(`{"swagger":"2.0","basePath":"/api/","paths":{"/users":{"get":{"responses":{
+			"200":{"schema":{"type":"array","items":{"$ref":"#/definitions/User"}}}}}}},"definitions":{"User":{"type":"object"}}}`))
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		operation, schema, err := spec.ResponseSchema(http.MethodGet, "/api/users", http.StatusOK, "")
+		assert.Nil(t, err)
+		assert.Equal(t, "GET /users", operation)
+		assert.Contains(t, schema, `"definitions":{"User":{"type":"object"}}`)
+	})
+
+	t.Run("OpenAPI 3.1", func(t *testing.T) {
+		spec, err := apispec.ParseOpenAPI([]byte(`{"openapi":"3.1.0","servers":[{"url":"{scheme}://{host}/v2/"}],"paths":{"/":{"get":{
+			"responses":{"200":{"content":{"*/*":{"schema":{"type":["string","null"]}}}}}}}}}`))
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		operation, schema, err := spec.ResponseSchema(http.MethodGet, "/v2", http.StatusOK, "application/json")
+		assert.Nil(t, err)
+		assert.Equal(t, "GET /", operation)
+		assert.Contains(t, schema, `"$schema":"https://json-schema.org/draft/2020-12/schema"`)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := apispec.ParseOpenAPI([]byte(`{"info":{}}`))
+		assert.ErrorContains(t, err, "no openapi or swagger version")
+
+		_, err = apispec.ParseOpenAPI([]byte(`[`))
+		assert.ErrorContains(t, err, "invalid OpenAPI document")
+
+		_, err = apispec.LoadOpenAPI("fake.yaml", "testdata")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("from URL", func(t *testing.T) {
+		defer gock.Off()
+		gock.New("http://foo").Get("/openapi.json").Reply(http.StatusOK).BodyString(`{"openapi":"3.0.0","paths":{}}`)
+		gock.New("http://foo").Get("/fake.json").Reply(http.StatusNotFound)
+
+		spec, err := apispec.LoadOpenAPI("http://foo/openapi.json", "")
+		assert.Nil(t, err)
+		assert.NotNil(t, spec)
+
+		_, err = apispec.LoadOpenAPI("http://foo/fake.json", "")
+		assert.ErrorContains(t, err, "status code: 404")
+	})
+}
+
+func TestOpenAPIOperations(t *testing.T) {
+	spec, err := apispec.LoadOpenAPI("openapi.yaml", "testdata")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	operations := spec.Operations()
+	if !assert.Equal(t, 3, len(operations)) {
+		return
+	}
+	assert.Equal(t, "POST", operations[0].Method)
+	assert.Equal(t, "/users", operations[0].Path)
+	assert.Equal(t, []apispec.Parameter{{
+		Name: "dryRun", In: "query", Schema: map[string]interface{}{"type": "boolean"},
+	}}, operations[0].Parameters)
+	assert.Equal(t, "object", operations[0].Body["type"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, operations[0].Body["properties"].(map[string]interface{})["name"])
+
+	assert.Equal(t, "/users/me", operations[1].Path)
+	assert.Empty(t, operations[1].Parameters)
+	assert.Nil(t, operations[1].Body)
+
+	assert.Equal(t, "/users/{id}", operations[2].Path)
+	assert.Equal(t, []apispec.Parameter{{
+		Name: "id", In: "path", Required: true, Schema: map[string]interface{}{"type": "integer", "minimum": float64(1)},
+	}}, operations[2].Parameters)
+
+	t.Run("swagger", func(t *testing.T) {
+		spec, err := apispec.ParseOpenAPI([]byte(`swagger: "2.0"
+paths:
+  /users:
+    post:
+      parameters:
+        - name: limit
+          in: query
+          type: integer
+          maximum: 100
+        - name: user
+          in: body
+          schema:
+            $ref: "#/definitions/User"
+definitions:
+  User:
+    type: object
+    properties:
+      friends:
+        type: array
+        items:
+          $ref: "#/definitions/User"
+`))
+		if !assert.Nil(t, err) {
+			return
+		}
+		operations := spec.Operations()
+		if assert.Equal(t, 1, len(operations)) {
+			assert.Equal(t, "limit", operations[0].Parameters[0].Name)
+			assert.Equal(t, "integer", operations[0].Parameters[0].Schema["type"])
+			assert.Equal(t, float64(100), operations[0].Parameters[0].Schema["maximum"])
+			assert.Equal(t, "object", operations[0].Body["type"])
+		}
+	})
+}
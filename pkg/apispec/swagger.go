@@ -28,6 +28,8 @@ type SwaggerInfo struct {
 type APIConverage interface {
 	HaveAPI(path, method string) (exist bool)
 	APICount() (count int)
+	// APIs returns all the operations of the spec
+	APIs() []API
 }
 
 // HaveAPI check if the swagger has the API.
@@ -75,6 +77,16 @@ func (s *Swagger) APICount() (count int) {
 	return
 }
 
+// APIs returns all the operations which are sorted by the path and the method
+func (s *Swagger) APIs() (apis []API) {
+	for path := range s.Paths {
+		for method := range s.Paths[path] {
+			apis = append(apis, API{Method: strings.ToUpper(method), Path: path})
+		}
+	}
+	return sortAPIs(apis)
+}
+
 func ParseToSwagger(data []byte) (swagger *Swagger, err error) {
 	swagger = &Swagger{}
 	err = json.Unmarshal(data, swagger)
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Role represents a set of permissions in a team
+type Role string
+
+const (
+	// RoleViewer could read the test suites and results
+	RoleViewer Role = "viewer"
+	// RoleRunner could run the test suites besides the viewer
+	RoleRunner Role = "runner"
+	// RoleEditor could create, update and delete the test suites besides the runner
+	RoleEditor Role = "editor"
+	// RoleAdmin has all the permissions
+	RoleAdmin Role = "admin"
+)
+
+// Permission represents an action on the test suites
+type Permission string
+
+const (
+	// PermissionRead allows to list and get the test suites and results
+	PermissionRead Permission = "read"
+	// PermissionRun allows to run the test suites
+	PermissionRun Permission = "run"
+	// PermissionEdit allows to save and delete the test suites
+	PermissionEdit Permission = "edit"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: {PermissionRead},
+	RoleRunner: {PermissionRead, PermissionRun},
+	RoleEditor: {PermissionRead, PermissionRun, PermissionEdit},
+	RoleAdmin:  {PermissionRead, PermissionRun, PermissionEdit},
+}
+
+// Valid checks if it's a known role
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Has checks if the role has the permission
+func (r Role) Has(perm Permission) bool {
+	for _, item := range rolePermissions[r] {
+		if item == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// User is an authenticated user, the key of Teams is the team name
+type User struct {
+	Name  string
+	Teams map[string]Role
+}
+
+// Can checks if the user has the permission in the team
+func (u *User) Can(team string, perm Permission) bool {
+	role, ok := u.Teams[team]
+	return ok && role.Has(perm)
+}
+
+// Team returns the requested team if the user belongs to it,
+// or the only team of the user if the requested one is empty
+func (u *User) Team(requested string) (team string, err error) {
+	if requested != "" {
+		if _, ok := u.Teams[requested]; !ok {
+			err = fmt.Errorf("user '%s' is not a member of team '%s'", u.Name, requested)
+		}
+		team = requested
+		return
+	}
+
+	switch len(u.Teams) {
+	case 0:
+		err = fmt.Errorf("user '%s' does not belong to any team", u.Name)
+	case 1:
+		team = u.TeamNames()[0]
+	default:
+		err = fmt.Errorf("user '%s' belongs to teams %v, please specify one of them", u.Name, u.TeamNames())
+	}
+	return
+}
+
+// TeamNames returns the sorted team names of the user
+func (u *User) TeamNames() (names []string) {
+	for name := range u.Teams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// Authenticator verifies a bearer token and returns the user of it
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*User, error)
+}
+
+// ErrInvalidToken indicates the token cannot be verified by any authenticator
+var ErrInvalidToken = fmt.Errorf("invalid token")
+
+type chainAuthenticator struct {
+	authenticators []Authenticator
+}
+
+// NewChainAuthenticator creates an authenticator which tries the authenticators one by one
+func NewChainAuthenticator(authenticators ...Authenticator) Authenticator {
+	return &chainAuthenticator{authenticators: authenticators}
+}
+
+// Authenticate returns the user from the first authenticator which accepts the token
+func (a *chainAuthenticator) Authenticate(ctx context.Context, token string) (user *User, err error) {
+	err = ErrInvalidToken
+	for _, authenticator := range a.authenticators {
+		if user, err = authenticator.Authenticate(ctx, token); err == nil {
+			return
+		}
+	}
+	return
+}
+
+type userContextKey struct{}
+
+// WithUser returns a context which carries the user and the current team
+func WithUser(ctx context.Context, user *User, team string) context.Context {
+	return context.WithValue(ctx, userContextKey{}, &Identity{User: user, Team: team})
+}
+
+// Identity is the user and the current team of a request
+type Identity struct {
+	User *User
+	Team string
+}
+
+// FromContext returns the identity of the request, it's nil if the authentication is disabled
+func FromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(userContextKey{}).(*Identity)
+	return identity
+}
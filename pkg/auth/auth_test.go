@@ -0,0 +1,77 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole(t *testing.T) {
+	assert.True(t, auth.RoleViewer.Has(auth.PermissionRead))
+	assert.False(t, auth.RoleViewer.Has(auth.PermissionRun))
+	assert.True(t, auth.RoleRunner.Has(auth.PermissionRun))
+	assert.False(t, auth.RoleRunner.Has(auth.PermissionEdit))
+	assert.True(t, auth.RoleEditor.Has(auth.PermissionEdit))
+	assert.True(t, auth.RoleAdmin.Has(auth.PermissionEdit))
+	assert.False(t, auth.Role("fake").Valid())
+}
+
+func TestUser(t *testing.T) {
+	user := &auth.User{Name: "alice", Teams: map[string]auth.Role{
+		"team-b": auth.RoleViewer,
+		"team-a": auth.RoleEditor,
+	}}
+	assert.True(t, user.Can("team-a", auth.PermissionEdit))
+	assert.False(t, user.Can("team-b", auth.PermissionRun))
+	assert.False(t, user.Can("team-c", auth.PermissionRead))
+	assert.Equal(t, []string{"team-a", "team-b"}, user.TeamNames())
+
+	team, err := user.Team("team-b")
+	assert.Nil(t, err)
+	assert.Equal(t, "team-b", team)
+
+	_, err = user.Team("team-c")
+	assert.NotNil(t, err)
+
+	_, err = user.Team("")
+	assert.NotNil(t, err)
+
+	team, err = (&auth.User{Teams: map[string]auth.Role{"team-a": auth.RoleViewer}}).Team("")
+	assert.Nil(t, err)
+	assert.Equal(t, "team-a", team)
+
+	_, err = (&auth.User{}).Team("")
+	assert.NotNil(t, err)
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	authenticator := auth.NewChainAuthenticator(auth.NewTokenAuthenticator([]auth.TokenConfig{{
+		Token: "token",
+		User:  "robot",
+		Teams: map[string]auth.Role{"team-a": auth.RoleRunner},
+	}}))
+
+	user, err := authenticator.Authenticate(context.TODO(), "token")
+	assert.Nil(t, err)
+	assert.Equal(t, "robot", user.Name)
+	assert.Equal(t, auth.RoleRunner, user.Teams["team-a"])
+
+	_, err = authenticator.Authenticate(context.TODO(), "fake")
+	assert.Equal(t, auth.ErrInvalidToken, err)
+
+	_, err = auth.NewChainAuthenticator().Authenticate(context.TODO(), "token")
+	assert.Equal(t, auth.ErrInvalidToken, err)
+}
+
+func TestContext(t *testing.T) {
+	assert.Nil(t, auth.FromContext(context.TODO()))
+
+	user := &auth.User{Name: "alice"}
+	identity := auth.FromContext(auth.WithUser(context.TODO(), user, "team-a"))
+	if assert.NotNil(t, identity) {
+		assert.Equal(t, user, identity.User)
+		assert.Equal(t, "team-a", identity.Team)
+	}
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config is the authentication config of the server mode
+type Config struct {
+	// Tokens are the static tokens, it's useful for the robot accounts
+	Tokens []TokenConfig `json:"tokens,omitempty"`
+	// OIDC verifies the ID tokens which are issued by an OpenID Connect provider
+	OIDC *OIDCConfig `json:"oidc,omitempty"`
+}
+
+// TokenConfig is a static token and the roles of its user in the teams
+type TokenConfig struct {
+	Token string          `json:"token"`
+	User  string          `json:"user"`
+	Teams map[string]Role `json:"teams"`
+}
+
+// OIDCConfig is the OpenID Connect provider config
+type OIDCConfig struct {
+	Issuer   string `json:"issuer"`
+	ClientID string `json:"clientID"`
+	// UserClaim is the claim of the username, default is email
+	UserClaim string `json:"userClaim,omitempty"`
+	// TeamsClaim is the claim of the teams (groups) of the user, default is groups
+	TeamsClaim string `json:"teamsClaim,omitempty"`
+	// Roles maps the team to a role, default is DefaultRole
+	Roles map[string]Role `json:"roles,omitempty"`
+	// DefaultRole is the role of the teams which are absent in Roles, default is viewer
+	DefaultRole Role `json:"defaultRole,omitempty"`
+}
+
+// LoadConfig reads the config from a YAML file
+func LoadConfig(file string) (config *Config, err error) {
+	var data []byte
+	if data, err = os.ReadFile(file); err == nil {
+		config = &Config{}
+		if err = yaml.Unmarshal(data, config); err == nil {
+			err = config.Validate()
+		}
+	}
+	return
+}
+
+// Validate checks the required fields and the roles
+func (c *Config) Validate() (err error) {
+	for i, token := range c.Tokens {
+		if token.Token == "" || token.User == "" {
+			return fmt.Errorf("token and user are required in tokens[%d]", i)
+		}
+		if err = validateRoles(token.Teams); err != nil {
+			return
+		}
+	}
+
+	if c.OIDC != nil {
+		if c.OIDC.Issuer == "" || c.OIDC.ClientID == "" {
+			return fmt.Errorf("issuer and clientID are required in oidc")
+		}
+		if c.OIDC.DefaultRole != "" && !c.OIDC.DefaultRole.Valid() {
+			return fmt.Errorf("unknown role '%s'", c.OIDC.DefaultRole)
+		}
+		err = validateRoles(c.OIDC.Roles)
+	}
+	return
+}
+
+// NewAuthenticator creates an authenticator which accepts the static tokens and the OIDC tokens
+func NewAuthenticator(config *Config) Authenticator {
+	var authenticators []Authenticator
+	if len(config.Tokens) > 0 {
+		authenticators = append(authenticators, NewTokenAuthenticator(config.Tokens))
+	}
+	if config.OIDC != nil {
+		authenticators = append(authenticators, NewOIDCAuthenticator(*config.OIDC))
+	}
+	return NewChainAuthenticator(authenticators...)
+}
+
+func validateRoles(roles map[string]Role) error {
+	for team, role := range roles {
+		if !role.Valid() {
+			return fmt.Errorf("unknown role '%s' of team '%s'", role, team)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	config, err := auth.LoadConfig("testdata/config.yaml")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "robot", config.Tokens[0].User)
+		assert.Equal(t, auth.RoleRunner, config.Tokens[0].Teams["team-a"])
+		assert.Equal(t, "atest", config.OIDC.ClientID)
+		assert.Equal(t, auth.RoleEditor, config.OIDC.Roles["team-a"])
+
+		user, err := auth.NewAuthenticator(config).Authenticate(context.TODO(), "robot-token")
+		assert.Nil(t, err)
+		assert.Equal(t, "robot", user.Name)
+	}
+
+	_, err = auth.LoadConfig("testdata/invalid-role.yaml")
+	assert.NotNil(t, err)
+
+	_, err = auth.LoadConfig("testdata/fake.yaml")
+	assert.NotNil(t, err)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config auth.Config
+		hasErr bool
+	}{{
+		name: "empty",
+	}, {
+		name:   "token without user",
+		config: auth.Config{Tokens: []auth.TokenConfig{{Token: "token"}}},
+		hasErr: true,
+	}, {
+		name:   "oidc without client",
+		config: auth.Config{OIDC: &auth.OIDCConfig{Issuer: "http://foo"}},
+		hasErr: true,
+	}, {
+		name:   "unknown default role",
+		config: auth.Config{OIDC: &auth.OIDCConfig{Issuer: "http://foo", ClientID: "atest", DefaultRole: "fake"}},
+		hasErr: true,
+	}, {
+		name: "oidc",
+		config: auth.Config{OIDC: &auth.OIDCConfig{Issuer: "http://foo", ClientID: "atest",
+			Roles: map[string]auth.Role{"team-a": auth.RoleAdmin}}},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			assert.Equal(t, tt.hasErr, err != nil, err)
+		})
+	}
+}
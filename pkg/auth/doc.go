@@ -0,0 +1,2 @@
+// Package auth provides the authentication and role-based access control of the server mode
+package auth
@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type oidcAuthenticator struct {
+	config OIDCConfig
+	client *http.Client
+	now    func() time.Time
+
+	lock sync.Mutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewOIDCAuthenticator creates an authenticator which verifies the RS256 ID tokens of an OpenID Connect provider
+func NewOIDCAuthenticator(config OIDCConfig) Authenticator {
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	if config.UserClaim == "" {
+		config.UserClaim = "email"
+	}
+	if config.TeamsClaim == "" {
+		config.TeamsClaim = "groups"
+	}
+	if config.DefaultRole == "" {
+		config.DefaultRole = RoleViewer
+	}
+	return &oidcAuthenticator{
+		config: config,
+		client: http.DefaultClient,
+		now:    time.Now,
+	}
+}
+
+// Authenticate verifies the signature and the standard claims of the ID token
+func (a *oidcAuthenticator) Authenticate(ctx context.Context, token string) (user *User, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		err = ErrInvalidToken
+		return
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err = decodeSegment(parts[0], &header); err != nil {
+		return
+	} else if header.Alg != "RS256" {
+		err = fmt.Errorf("not supported algorithm '%s'", header.Alg)
+		return
+	}
+
+	var key *rsa.PublicKey
+	if key, err = a.getKey(ctx, header.Kid); err != nil {
+		return
+	}
+
+	var signature []byte
+	if signature, err = base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return
+	}
+	hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature); err != nil {
+		err = ErrInvalidToken
+		return
+	}
+
+	claims := map[string]interface{}{}
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return
+	}
+	if err = a.verifyClaims(claims); err == nil {
+		user = a.getUser(claims)
+	}
+	return
+}
+
+func (a *oidcAuthenticator) verifyClaims(claims map[string]interface{}) (err error) {
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != a.config.Issuer {
+		return fmt.Errorf("unexpected issuer '%s'", iss)
+	}
+	if !containsString(claims["aud"], a.config.ClientID) {
+		return fmt.Errorf("the token is not issued for '%s'", a.config.ClientID)
+	}
+
+	now := a.now().Unix()
+	if exp, ok := claims["exp"].(float64); !ok || int64(exp) < now {
+		return fmt.Errorf("the token is expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && int64(nbf) > now {
+		return fmt.Errorf("the token is not valid yet")
+	}
+	return
+}
+
+func (a *oidcAuthenticator) getUser(claims map[string]interface{}) (user *User) {
+	user = &User{Teams: map[string]Role{}}
+	if user.Name, _ = claims[a.config.UserClaim].(string); user.Name == "" {
+		user.Name, _ = claims["sub"].(string)
+	}
+
+	for _, team := range toStrings(claims[a.config.TeamsClaim]) {
+		if role, ok := a.config.Roles[team]; ok {
+			user.Teams[team] = role
+		} else {
+			user.Teams[team] = a.config.DefaultRole
+		}
+	}
+	return
+}
+
+// getKey returns the public key by id, the keys will be refreshed if the id is unknown
+func (a *oidcAuthenticator) getKey(ctx context.Context, kid string) (key *rsa.PublicKey, err error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if key = a.keys[kid]; key != nil {
+		return
+	}
+
+	if a.keys, err = a.fetchKeys(ctx); err == nil {
+		if key = a.keys[kid]; key == nil {
+			err = fmt.Errorf("cannot find the key '%s'", kid)
+		}
+	}
+	return
+}
+
+func (a *oidcAuthenticator) fetchKeys(ctx context.Context) (keys map[string]*rsa.PublicKey, err error) {
+	discovery := struct {
+		JWKSURI string `json:"jwks_uri"`
+	}{}
+	if err = a.getJSON(ctx, a.config.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return
+	}
+
+	jwks := struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}{}
+	if err = a.getJSON(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return
+	}
+
+	keys = map[string]*rsa.PublicKey{}
+	for _, item := range jwks.Keys {
+		if item.Kty != "RSA" {
+			continue
+		}
+
+		var n, e []byte
+		if n, err = base64.RawURLEncoding.DecodeString(item.N); err != nil {
+			return
+		}
+		if e, err = base64.RawURLEncoding.DecodeString(item.E); err != nil {
+			return
+		}
+		keys[item.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return
+}
+
+func (a *oidcAuthenticator) getJSON(ctx context.Context, api string, obj interface{}) (err error) {
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, api, nil); err != nil {
+		return
+	}
+
+	var resp *http.Response
+	if resp, err = a.client.Do(req); err == nil {
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("failed to request %s, status code: %d", api, resp.StatusCode)
+		} else {
+			err = json.NewDecoder(resp.Body).Decode(obj)
+		}
+	}
+	return
+}
+
+func decodeSegment(segment string, obj interface{}) (err error) {
+	var data []byte
+	if data, err = base64.RawURLEncoding.DecodeString(segment); err == nil {
+		err = json.Unmarshal(data, obj)
+	}
+	return
+}
+
+func containsString(val interface{}, target string) bool {
+	for _, item := range toStrings(val) {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
+
+// toStrings converts a string or an array claim to a string slice
+func toStrings(val interface{}) (result []string) {
+	switch v := val.(type) {
+	case string:
+		result = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,178 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDCAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":    urlFoo,
+			"aud":    []string{"atest"},
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"email":  "alice@foo.com",
+			"groups": []string{"team-a", "team-b"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		token  func() string
+		verify func(*testing.T, *auth.User, error)
+	}{{
+		name: "normal",
+		token: func() string {
+			return signToken(key, "RS256", validClaims())
+		},
+		verify: func(t *testing.T, user *auth.User, err error) {
+			if assert.Nil(t, err) {
+				assert.Equal(t, "alice@foo.com", user.Name)
+				assert.Equal(t, map[string]auth.Role{
+					"team-a": auth.RoleEditor,
+					"team-b": auth.RoleViewer,
+				}, user.Teams)
+			}
+		},
+	}, {
+		name: "without email",
+		token: func() string {
+			claims := validClaims()
+			delete(claims, "email")
+			claims["sub"] = "alice"
+			return signToken(key, "RS256", claims)
+		},
+		verify: func(t *testing.T, user *auth.User, err error) {
+			if assert.Nil(t, err) {
+				assert.Equal(t, "alice", user.Name)
+			}
+		},
+	}, {
+		name: "expired",
+		token: func() string {
+			claims := validClaims()
+			claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			return signToken(key, "RS256", claims)
+		},
+		verify: expectError,
+	}, {
+		name: "not valid yet",
+		token: func() string {
+			claims := validClaims()
+			claims["nbf"] = time.Now().Add(time.Hour).Unix()
+			return signToken(key, "RS256", claims)
+		},
+		verify: expectError,
+	}, {
+		name: "another issuer",
+		token: func() string {
+			claims := validClaims()
+			claims["iss"] = "http://bar"
+			return signToken(key, "RS256", claims)
+		},
+		verify: expectError,
+	}, {
+		name: "another audience",
+		token: func() string {
+			claims := validClaims()
+			claims["aud"] = "fake"
+			return signToken(key, "RS256", claims)
+		},
+		verify: expectError,
+	}, {
+		name: "not supported algorithm",
+		token: func() string {
+			return signToken(key, "HS256", validClaims())
+		},
+		verify: expectError,
+	}, {
+		name: "invalid signature",
+		token: func() string {
+			return signToken(key, "RS256", validClaims()) + "fake"
+		},
+		verify: expectError,
+	}, {
+		name: "not a JWT",
+		token: func() string {
+			return "fake"
+		},
+		verify: expectError,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			mockJWKS(key)
+
+			authenticator := auth.NewOIDCAuthenticator(auth.OIDCConfig{
+				Issuer:   urlFoo + "/",
+				ClientID: "atest",
+				Roles:    map[string]auth.Role{"team-a": auth.RoleEditor},
+			})
+			user, err := authenticator.Authenticate(context.TODO(), tt.token())
+			tt.verify(t, user, err)
+		})
+	}
+}
+
+func TestOIDCDiscoveryError(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlFoo).Get("/.well-known/openid-configuration").Reply(http.StatusNotFound)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if assert.Nil(t, err) {
+		authenticator := auth.NewOIDCAuthenticator(auth.OIDCConfig{Issuer: urlFoo, ClientID: "atest"})
+		_, err = authenticator.Authenticate(context.TODO(), signToken(key, "RS256", map[string]interface{}{}))
+		assert.NotNil(t, err)
+	}
+}
+
+func expectError(t *testing.T, user *auth.User, err error) {
+	assert.NotNil(t, err)
+	assert.Nil(t, user)
+}
+
+func mockJWKS(key *rsa.PrivateKey) {
+	gock.New(urlFoo).Get("/.well-known/openid-configuration").Reply(http.StatusOK).
+		JSON(map[string]string{"jwks_uri": urlFoo + "/keys"})
+	gock.New(urlFoo).Get("/keys").Reply(http.StatusOK).JSON(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": "fake",
+			"kty": "EC",
+		}, {
+			"kid": "key",
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+}
+
+func signToken(key *rsa.PrivateKey, alg string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": "key"})
+	payload, _ := json.Marshal(claims)
+	content := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	hashed := sha256.Sum256([]byte(content))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	return content + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+const urlFoo = "http://foo"
@@ -0,0 +1,2 @@
+// Package ci provides the summary and the gating of the test results in CI pipelines
+package ci
@@ -0,0 +1,268 @@
+package ci
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// ProviderAuto detects the provider from the environment variables
+	ProviderAuto = "auto"
+	// ProviderGitHub is GitHub Actions
+	ProviderGitHub = "github"
+	// ProviderGitLab is GitLab CI
+	ProviderGitLab = "gitlab"
+	// ProviderJenkins is Jenkins
+	ProviderJenkins = "jenkins"
+	// ProviderNone writes nothing
+	ProviderNone = "none"
+)
+
+const (
+	// JUnitFile is the file name of the JUnit report
+	JUnitFile = "atest-junit.xml"
+	// DotenvFile is the file name of the GitLab dotenv report
+	DotenvFile = "atest.env"
+)
+
+// Provider writes the outputs which are specific to a CI provider
+type Provider interface {
+	Name() string
+	Write(summary *Summary) error
+}
+
+// Detect returns the provider name of the current environment
+func Detect() (name string) {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		name = ProviderGitHub
+	case os.Getenv("GITLAB_CI") == "true":
+		name = ProviderGitLab
+	case os.Getenv("JENKINS_URL") != "":
+		name = ProviderJenkins
+	default:
+		name = ProviderNone
+	}
+	return
+}
+
+// NewProvider creates a provider by name, the files will be written into the output directory
+func NewProvider(name, outputDir string, writer io.Writer) (provider Provider, err error) {
+	if name == "" || name == ProviderAuto {
+		name = Detect()
+	}
+
+	switch name {
+	case ProviderGitHub:
+		provider = &githubProvider{writer: writer}
+	case ProviderGitLab:
+		provider = &gitlabProvider{outputDir: outputDir}
+	case ProviderJenkins:
+		provider = &jenkinsProvider{outputDir: outputDir}
+	case ProviderNone:
+		provider = &noneProvider{}
+	default:
+		err = fmt.Errorf("not supported CI provider: '%s'", name)
+	}
+	return
+}
+
+type githubProvider struct {
+	writer io.Writer
+}
+
+// Name returns the name of the provider
+func (p *githubProvider) Name() string {
+	return ProviderGitHub
+}
+
+// Write writes the step summary, the step outputs, and the annotations
+func (p *githubProvider) Write(summary *Summary) (err error) {
+	for _, api := range summary.APIs {
+		if api.Failed > 0 {
+			fmt.Fprintf(p.writer, "::error title=%s::%s\n", escapeProperty(api.API), escapeAnnotation(api.Error))
+		}
+	}
+	for _, breach := range summary.Breaches {
+		fmt.Fprintf(p.writer, "::warning title=SLO breach::%s %s expected %s, actual %s\n",
+			escapeAnnotation(breach.API), breach.Rule, breach.Expected, breach.Actual)
+	}
+
+	if file := os.Getenv("GITHUB_OUTPUT"); file != "" {
+		if err = appendFile(file, outputLines(summary, "")); err != nil {
+			return
+		}
+	}
+	if file := os.Getenv("GITHUB_STEP_SUMMARY"); file != "" {
+		err = appendFile(file, markdown(summary))
+	}
+	return
+}
+
+type gitlabProvider struct {
+	outputDir string
+}
+
+// Name returns the name of the provider
+func (p *gitlabProvider) Name() string {
+	return ProviderGitLab
+}
+
+// Write writes the JUnit report and the dotenv report
+func (p *gitlabProvider) Write(summary *Summary) (err error) {
+	if err = writeJUnit(filepath.Join(p.outputDir, JUnitFile), summary); err == nil {
+		err = os.WriteFile(filepath.Join(p.outputDir, DotenvFile), []byte(outputLines(summary, "ATEST_")), 0644)
+	}
+	return
+}
+
+type jenkinsProvider struct {
+	outputDir string
+}
+
+// Name returns the name of the provider
+func (p *jenkinsProvider) Name() string {
+	return ProviderJenkins
+}
+
+// Write writes the JUnit report
+func (p *jenkinsProvider) Write(summary *Summary) error {
+	return writeJUnit(filepath.Join(p.outputDir, JUnitFile), summary)
+}
+
+type noneProvider struct{}
+
+// Name returns the name of the provider
+func (p *noneProvider) Name() string {
+	return ProviderNone
+}
+
+// Write does nothing
+func (p *noneProvider) Write(summary *Summary) error {
+	return nil
+}
+
+func outputLines(summary *Summary, prefix string) string {
+	lines := []string{
+		fmt.Sprintf("%sstatus=%s", prefix, summary.Status),
+		fmt.Sprintf("%stotal=%d", prefix, summary.Total),
+		fmt.Sprintf("%spassed=%d", prefix, summary.Passed),
+		fmt.Sprintf("%sfailed=%d", prefix, summary.Failed),
+		fmt.Sprintf("%sbreaches=%d", prefix, len(summary.Breaches)),
+	}
+	if summary.Coverage != nil {
+		lines = append(lines, fmt.Sprintf("%scoverage=%s", prefix, formatFloat(summary.Coverage.Percent)))
+	}
+	if prefix != "" {
+		for i := range lines {
+			key, val, _ := strings.Cut(lines[i], "=")
+			lines[i] = strings.ToUpper(key) + "=" + val
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func markdown(summary *Summary) string {
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, "## API Testing: %s\n\n", summary.Status)
+	fmt.Fprintf(buf, "| Total | Passed | Failed | Duration |\n|---|---|---|---|\n| %d | %d | %d | %s |\n\n",
+		summary.Total, summary.Passed, summary.Failed, summary.Duration)
+	if summary.Coverage != nil {
+		fmt.Fprintf(buf, "API Coverage: %d/%d (%s%%)\n\n", summary.Coverage.Covered, summary.Coverage.Total,
+			formatFloat(summary.Coverage.Percent))
+	}
+	if len(summary.Breaches) > 0 {
+		fmt.Fprintf(buf, "| API | Rule | Expected | Actual |\n|---|---|---|---|\n")
+		for _, breach := range summary.Breaches {
+			fmt.Fprintf(buf, "| %s | %s | %s | %s |\n", breach.API, breach.Rule, breach.Expected, breach.Actual)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Time    string        `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnit(filename string, summary *Summary) (err error) {
+	suite := junitTestSuite{Name: "atest", Time: durationSeconds(summary.Duration)}
+	for _, api := range summary.APIs {
+		testCase := junitTestCase{Name: api.API, Time: durationSeconds(api.Average)}
+		if api.Failed > 0 {
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d of %d requests failed", api.Failed, api.Count),
+				Text:    api.Error,
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	for _, breach := range summary.Breaches {
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name: fmt.Sprintf("SLO %s %s", breach.Rule, breach.API),
+			Failure: &junitFailure{
+				Message: fmt.Sprintf("expected %s, actual %s", breach.Expected, breach.Actual),
+			},
+		})
+		suite.Failures++
+	}
+	suite.Tests = len(suite.Cases)
+
+	var data []byte
+	if data, err = xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  "); err == nil {
+		err = os.WriteFile(filename, append([]byte(xml.Header), data...), 0644)
+	}
+	return
+}
+
+func durationSeconds(text string) string {
+	duration, _ := time.ParseDuration(text)
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
+
+func escapeAnnotation(text string) string {
+	text = strings.ReplaceAll(text, "%", "%25")
+	text = strings.ReplaceAll(text, "\r", "%0D")
+	return strings.ReplaceAll(text, "\n", "%0A")
+}
+
+func escapeProperty(text string) string {
+	text = escapeAnnotation(text)
+	text = strings.ReplaceAll(text, ":", "%3A")
+	return strings.ReplaceAll(text, ",", "%2C")
+}
+
+func appendFile(filename, content string) (err error) {
+	var f *os.File
+	if f, err = os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+		defer f.Close()
+		_, err = f.WriteString(content)
+	}
+	return
+}
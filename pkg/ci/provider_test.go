@@ -0,0 +1,109 @@
+package ci_test
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/ci"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	for _, key := range []string{"GITHUB_ACTIONS", "GITLAB_CI", "JENKINS_URL"} {
+		defer os.Setenv(key, os.Getenv(key))
+		os.Unsetenv(key)
+	}
+	assert.Equal(t, ci.ProviderNone, ci.Detect())
+
+	os.Setenv("JENKINS_URL", "http://jenkins")
+	assert.Equal(t, ci.ProviderJenkins, ci.Detect())
+
+	os.Setenv("GITLAB_CI", "true")
+	assert.Equal(t, ci.ProviderGitLab, ci.Detect())
+
+	os.Setenv("GITHUB_ACTIONS", "true")
+	assert.Equal(t, ci.ProviderGitHub, ci.Detect())
+
+	provider, err := ci.NewProvider(ci.ProviderAuto, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ci.ProviderGitHub, provider.Name())
+
+	_, err = ci.NewProvider("fake", "", nil)
+	assert.NotNil(t, err)
+}
+
+func TestProviders(t *testing.T) {
+	summary := ci.NewSummary([]runner.ReportResult{{
+		API: "GET http://foo/bar", Count: 2, Error: 1, LastErrorMessage: "error",
+	}, {
+		API: "GET http://foo/slow", Count: 1, Max: 2 * time.Second,
+	}}, nil, ci.Gate{MaxLatency: time.Second}, time.Second)
+
+	t.Run("github", func(t *testing.T) {
+		dir := t.TempDir()
+		defer os.Setenv("GITHUB_OUTPUT", os.Getenv("GITHUB_OUTPUT"))
+		defer os.Setenv("GITHUB_STEP_SUMMARY", os.Getenv("GITHUB_STEP_SUMMARY"))
+		os.Setenv("GITHUB_OUTPUT", path.Join(dir, "output"))
+		os.Setenv("GITHUB_STEP_SUMMARY", path.Join(dir, "summary.md"))
+
+		buf := new(bytes.Buffer)
+		provider, err := ci.NewProvider(ci.ProviderGitHub, dir, buf)
+		assert.Nil(t, err)
+		err = provider.Write(summary)
+		assert.Nil(t, err)
+		assert.Equal(t, `::error title=GET http%3A//foo/bar::error
+::warning title=SLO breach::GET http://foo/slow max-latency expected 1s, actual 2s
+`, buf.String())
+
+		output, err := os.ReadFile(path.Join(dir, "output"))
+		assert.Nil(t, err)
+		assert.Equal(t, "status=failed\ntotal=3\npassed=2\nfailed=1\nbreaches=1\n", string(output))
+
+		markdown, err := os.ReadFile(path.Join(dir, "summary.md"))
+		assert.Nil(t, err)
+		assert.Contains(t, string(markdown), "| 3 | 2 | 1 | 1s |")
+		assert.Contains(t, string(markdown), "| GET http://foo/slow | max-latency | 1s | 2s |")
+	})
+
+	t.Run("gitlab", func(t *testing.T) {
+		dir := t.TempDir()
+		provider, err := ci.NewProvider(ci.ProviderGitLab, dir, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, ci.ProviderGitLab, provider.Name())
+		err = provider.Write(summary)
+		assert.Nil(t, err)
+
+		dotenv, err := os.ReadFile(path.Join(dir, ci.DotenvFile))
+		assert.Nil(t, err)
+		assert.Contains(t, string(dotenv), "ATEST_STATUS=failed\n")
+
+		junit, err := os.ReadFile(path.Join(dir, ci.JUnitFile))
+		assert.Nil(t, err)
+		assert.Contains(t, string(junit), `<testsuite name="atest" tests="3" failures="2" time="1.000">`)
+		assert.Contains(t, string(junit), `<failure message="1 of 2 requests failed">error</failure>`)
+	})
+
+	t.Run("jenkins", func(t *testing.T) {
+		dir := t.TempDir()
+		provider, err := ci.NewProvider(ci.ProviderJenkins, dir, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, ci.ProviderJenkins, provider.Name())
+		err = provider.Write(summary)
+		assert.Nil(t, err)
+		assert.FileExists(t, path.Join(dir, ci.JUnitFile))
+
+		provider, _ = ci.NewProvider(ci.ProviderJenkins, path.Join(dir, "fake"), nil)
+		assert.NotNil(t, provider.Write(summary))
+	})
+
+	t.Run("none", func(t *testing.T) {
+		provider, err := ci.NewProvider(ci.ProviderNone, "", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, ci.ProviderNone, provider.Name())
+		assert.Nil(t, provider.Write(summary))
+	})
+}
@@ -0,0 +1,222 @@
+package ci
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+)
+
+const (
+	// ExitCodePassed indicates all the gates are passed
+	ExitCodePassed = 0
+	// ExitCodeFailed indicates some of the test cases are failed
+	ExitCodeFailed = 1
+	// ExitCodeSLOBreached indicates some of the APIs breach the SLO
+	ExitCodeSLOBreached = 2
+	// ExitCodeCoverage indicates the API coverage is lower than expected
+	ExitCodeCoverage = 3
+)
+
+const (
+	// StatusPassed is the status of a passed summary
+	StatusPassed = "passed"
+	// StatusFailed is the status of a failed summary
+	StatusFailed = "failed"
+)
+
+// Gate holds the thresholds of the pipeline, ignore a threshold if it is zero
+type Gate struct {
+	// MaxLatency is the max duration of a single request
+	MaxLatency time.Duration
+	// MaxAverage is the max average duration of an API
+	MaxAverage time.Duration
+	// MaxErrorRate is the max error rate of an API, from 0 to 1
+	MaxErrorRate float64
+	// MinCoverage is the min API coverage in percent, from 0 to 100
+	MinCoverage float64
+}
+
+// Summary is the normalized result of a run
+type Summary struct {
+	Status   string `json:"status"`
+	ExitCode int    `json:"exitCode"`
+	Total    int    `json:"total"`
+	Passed   int    `json:"passed"`
+	Failed   int    `json:"failed"`
+	Duration string `json:"duration"`
+	// Throughput is the count of the requests per second of the whole run
+	Throughput float64      `json:"throughput"`
+	Coverage   *Coverage    `json:"coverage,omitempty"`
+	Breaches   []Breach     `json:"breaches,omitempty"`
+	APIs       []APISummary `json:"apis"`
+	Timestamp  string       `json:"timestamp"`
+}
+
+// Coverage is the API coverage against the API spec
+type Coverage struct {
+	Covered int     `json:"covered"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+	// Missed are the APIs of the spec which are not exercised, such as: DELETE /users/{name}
+	Missed []string `json:"missed,omitempty"`
+}
+
+// Breach is an API which breaches the SLO
+type Breach struct {
+	API      string `json:"api"`
+	Rule     string `json:"rule"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// APISummary is the result of an API
+type APISummary struct {
+	API     string  `json:"api"`
+	Count   int     `json:"count"`
+	Failed  int     `json:"failed"`
+	Average string  `json:"average"`
+	Max     string  `json:"max"`
+	QPS     float64 `json:"qps"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// NewSummary creates the summary from the report results, the coverage could be nil
+func NewSummary(results []runner.ReportResult, coverage apispec.APIConverage, gate Gate, duration time.Duration) (summary *Summary) {
+	summary = &Summary{
+		Duration:  duration.String(),
+		APIs:      []APISummary{},
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	for _, result := range results {
+		summary.Total += result.Count
+		summary.Failed += result.Error
+		summary.APIs = append(summary.APIs, APISummary{
+			API:     result.API,
+			Count:   result.Count,
+			Failed:  result.Error,
+			Average: result.Average.String(),
+			Max:     result.Max.String(),
+			QPS:     roundFloat(result.QPS),
+			Error:   result.LastErrorMessage,
+		})
+
+		if gate.MaxLatency > 0 && result.Max > gate.MaxLatency {
+			summary.Breaches = append(summary.Breaches, Breach{
+				API: result.API, Rule: "max-latency", Expected: gate.MaxLatency.String(), Actual: result.Max.String(),
+			})
+		}
+		if gate.MaxAverage > 0 && result.Average > gate.MaxAverage {
+			summary.Breaches = append(summary.Breaches, Breach{
+				API: result.API, Rule: "max-average", Expected: gate.MaxAverage.String(), Actual: result.Average.String(),
+			})
+		}
+		if gate.MaxErrorRate > 0 && result.Count > 0 {
+			if rate := float64(result.Error) / float64(result.Count); rate > gate.MaxErrorRate {
+				summary.Breaches = append(summary.Breaches, Breach{
+					API: result.API, Rule: "max-error-rate", Expected: formatFloat(gate.MaxErrorRate), Actual: formatFloat(rate),
+				})
+			}
+		}
+	}
+	summary.Passed = summary.Total - summary.Failed
+	if duration > 0 {
+		summary.Throughput = roundFloat(float64(summary.Total) / duration.Seconds())
+	}
+
+	if coverage != nil {
+		summary.Coverage = getCoverage(results, coverage)
+	}
+
+	switch {
+	case summary.Failed > 0:
+		summary.ExitCode = ExitCodeFailed
+	case len(summary.Breaches) > 0:
+		summary.ExitCode = ExitCodeSLOBreached
+	case gate.MinCoverage > 0 && (summary.Coverage == nil || summary.Coverage.Percent < gate.MinCoverage):
+		summary.ExitCode = ExitCodeCoverage
+	}
+
+	summary.Status = StatusPassed
+	if summary.ExitCode != ExitCodePassed {
+		summary.Status = StatusFailed
+	}
+	return
+}
+
+// WriteFile writes the summary as JSON into the file
+func (s *Summary) WriteFile(filename string) (err error) {
+	var data []byte
+	if data, err = json.MarshalIndent(s, "", "  "); err == nil {
+		err = os.WriteFile(filename, data, 0644)
+	}
+	return
+}
+
+// Err returns nil if all the gates are passed
+func (s *Summary) Err() (err error) {
+	if s.ExitCode != ExitCodePassed {
+		err = &GateError{Code: s.ExitCode, Message: s.Reason()}
+	}
+	return
+}
+
+// Reason returns the reason of the failure
+func (s *Summary) Reason() (reason string) {
+	switch s.ExitCode {
+	case ExitCodeFailed:
+		reason = fmt.Sprintf("%d of %d requests failed", s.Failed, s.Total)
+	case ExitCodeSLOBreached:
+		reason = fmt.Sprintf("%d SLO breaches found", len(s.Breaches))
+	case ExitCodeCoverage:
+		reason = "the API coverage is lower than expected"
+		if s.Coverage != nil {
+			reason = fmt.Sprintf("the API coverage %s%% is lower than expected", formatFloat(s.Coverage.Percent))
+		}
+	}
+	return
+}
+
+// GateError is the error which carries the exit code
+type GateError struct {
+	Code    int
+	Message string
+}
+
+// Error returns the message of the error
+func (e *GateError) Error() string {
+	return e.Message
+}
+
+// ExitCode returns the expected exit code of the process
+func (e *GateError) ExitCode() int {
+	return e.Code
+}
+
+func getCoverage(results []runner.ReportResult, apiConverage apispec.APIConverage) (coverage *Coverage) {
+	apiCoverage := runner.NewAPICoverage(results, apiConverage)
+	coverage = &Coverage{
+		Covered: len(apiCoverage.Covered),
+		Total:   apiCoverage.Total,
+		Percent: apiCoverage.Percent,
+	}
+	for _, api := range apiCoverage.Missed {
+		coverage.Missed = append(coverage.Missed, api.String())
+	}
+	return
+}
+
+func formatFloat(val float64) string {
+	return strconv.FormatFloat(roundFloat(val), 'f', -1, 64)
+}
+
+// roundFloat keeps two decimal places
+func roundFloat(val float64) float64 {
+	return math.Round(val*100) / 100
+}
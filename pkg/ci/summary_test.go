@@ -0,0 +1,122 @@
+package ci_test
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/linuxsuren/api-testing/pkg/ci"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		results  []runner.ReportResult
+		coverage apispec.APIConverage
+		gate     ci.Gate
+		verify   func(*testing.T, *ci.Summary)
+	}{{
+		name: "passed",
+		results: []runner.ReportResult{{
+			API: "GET http://foo/bar", Count: 2, Average: time.Second, Max: time.Second, QPS: 2.0 / 3,
+		}},
+		coverage: apispec.NewFakeAPISpec([][]string{{"/bar", "GET"}, {"/foo", "GET"}}),
+		gate:     ci.Gate{MaxLatency: 2 * time.Second, MinCoverage: 50},
+		verify: func(t *testing.T, s *ci.Summary) {
+			assert.Equal(t, ci.StatusPassed, s.Status)
+			assert.Equal(t, ci.ExitCodePassed, s.ExitCode)
+			assert.Equal(t, 2, s.Total)
+			assert.Equal(t, 2, s.Passed)
+			assert.Equal(t, float64(2), s.Throughput)
+			assert.Equal(t, 0.67, s.APIs[0].QPS)
+			assert.Equal(t, &ci.Coverage{Covered: 1, Total: 2, Percent: 50, Missed: []string{"GET /foo"}}, s.Coverage)
+			assert.Nil(t, s.Err())
+		},
+	}, {
+		name: "failed",
+		results: []runner.ReportResult{{
+			API: "GET http://foo/bar", Count: 2, Error: 1, LastErrorMessage: "error",
+		}},
+		verify: func(t *testing.T, s *ci.Summary) {
+			assert.Equal(t, ci.StatusFailed, s.Status)
+			assert.Equal(t, ci.ExitCodeFailed, s.ExitCode)
+			assert.Equal(t, 1, s.Failed)
+			assert.Equal(t, "error", s.APIs[0].Error)
+			assert.Equal(t, "1 of 2 requests failed", s.Err().Error())
+		},
+	}, {
+		name: "SLO breached",
+		results: []runner.ReportResult{{
+			API: "GET http://foo/bar", Count: 2, Average: 2 * time.Second, Max: 3 * time.Second,
+		}},
+		gate: ci.Gate{MaxLatency: time.Second, MaxAverage: time.Second},
+		verify: func(t *testing.T, s *ci.Summary) {
+			assert.Equal(t, ci.ExitCodeSLOBreached, s.ExitCode)
+			assert.Equal(t, []ci.Breach{{
+				API: "GET http://foo/bar", Rule: "max-latency", Expected: "1s", Actual: "3s",
+			}, {
+				API: "GET http://foo/bar", Rule: "max-average", Expected: "1s", Actual: "2s",
+			}}, s.Breaches)
+		},
+	}, {
+		name: "error rate",
+		results: []runner.ReportResult{{
+			API: "GET http://foo/bar", Count: 4, Error: 1,
+		}},
+		gate: ci.Gate{MaxErrorRate: 0.1},
+		verify: func(t *testing.T, s *ci.Summary) {
+			assert.Equal(t, ci.ExitCodeFailed, s.ExitCode)
+			assert.Equal(t, "0.25", s.Breaches[0].Actual)
+		},
+	}, {
+		name:     "coverage is lower than expected",
+		results:  []runner.ReportResult{{API: "POST http://foo/bar", Count: 1}},
+		coverage: apispec.NewFakeAPISpec([][]string{{"/bar", "GET"}}),
+		gate:     ci.Gate{MinCoverage: 10},
+		verify: func(t *testing.T, s *ci.Summary) {
+			assert.Equal(t, ci.ExitCodeCoverage, s.ExitCode)
+			assert.Equal(t, "the API coverage 0% is lower than expected", s.Err().Error())
+
+			err, ok := s.Err().(*ci.GateError)
+			assert.True(t, ok)
+			assert.Equal(t, ci.ExitCodeCoverage, err.ExitCode())
+		},
+	}, {
+		name: "no coverage",
+		gate: ci.Gate{MinCoverage: 10},
+		verify: func(t *testing.T, s *ci.Summary) {
+			assert.Equal(t, ci.ExitCodeCoverage, s.ExitCode)
+			assert.Equal(t, []ci.APISummary{}, s.APIs)
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := ci.NewSummary(tt.results, tt.coverage, tt.gate, time.Second)
+			tt.verify(t, summary)
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	summary := ci.NewSummary([]runner.ReportResult{{API: "GET http://foo", Count: 1}}, nil, ci.Gate{}, time.Second)
+
+	filename := path.Join(t.TempDir(), "summary.json")
+	err := summary.WriteFile(filename)
+	assert.Nil(t, err)
+
+	data, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+
+	result := &ci.Summary{}
+	err = json.Unmarshal(data, result)
+	assert.Nil(t, err)
+	assert.Equal(t, summary, result)
+
+	err = summary.WriteFile(path.Join(filename, "fake"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,185 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// ErrNotSupported indicates the conversion direction is not supported by a format
+var ErrNotSupported = errors.New("not supported")
+
+// Converter converts between the test suite and a specific format
+type Converter interface {
+	// Name returns the unique name of the format
+	Name() string
+	// Import converts the data to a test suite
+	Import(data []byte) (*testing.TestSuite, error)
+	// Export converts the test suite to the data
+	Export(suite *testing.TestSuite) ([]byte, error)
+}
+
+// Detector is an optional interface of Converter which tells if the data is in its format
+type Detector interface {
+	Detect(data []byte) bool
+}
+
+// Registry holds the converters
+type Registry interface {
+	// Register adds a converter, the existing one with the same name will be replaced
+	Register(converter Converter) Registry
+	// Get returns the converter by name
+	Get(name string) (Converter, bool)
+	// Detect returns the first converter which recognizes the data
+	Detect(data []byte) (Converter, bool)
+	// Names returns the sorted names of all the converters
+	Names() []string
+	// Convert converts the data from a format to another one, detect the source format if from is empty
+	Convert(from, to string, data []byte) ([]byte, error)
+}
+
+type registry struct {
+	converters []Converter
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() Registry {
+	return &registry{}
+}
+
+// NewDefaultRegistry creates a registry with all the built-in converters
+func NewDefaultRegistry() Registry {
+	// the order matters for the detection, the native formats come last
+	return NewRegistry().
+		Register(NewPostmanConverter()).
+		Register(NewHARConverter()).
+		Register(NewOpenAPIConverter()).
+		Register(NewJMeterConverter()).
+		Register(NewCurlConverter()).
+		Register(NewJSONConverter()).
+		Register(NewYAMLConverter())
+}
+
+// Register adds a converter
+func (r *registry) Register(converter Converter) Registry {
+	for i, item := range r.converters {
+		if item.Name() == converter.Name() {
+			r.converters[i] = converter
+			return r
+		}
+	}
+	r.converters = append(r.converters, converter)
+	return r
+}
+
+// Get returns the converter by name
+func (r *registry) Get(name string) (converter Converter, ok bool) {
+	for _, item := range r.converters {
+		if item.Name() == name {
+			converter, ok = item, true
+			break
+		}
+	}
+	return
+}
+
+// Detect returns the converter which recognizes the data
+func (r *registry) Detect(data []byte) (converter Converter, ok bool) {
+	for _, item := range r.converters {
+		if detector, isDetector := item.(Detector); isDetector && detector.Detect(data) {
+			converter, ok = item, true
+			break
+		}
+	}
+	return
+}
+
+// Names returns the names of the converters
+func (r *registry) Names() (names []string) {
+	for _, item := range r.converters {
+		names = append(names, item.Name())
+	}
+	sort.Strings(names)
+	return
+}
+
+// Convert converts the data between two formats
+func (r *registry) Convert(from, to string, data []byte) (result []byte, err error) {
+	var source, target Converter
+	var ok bool
+	if from == "" {
+		if source, ok = r.Detect(data); !ok {
+			err = errors.New("cannot detect the format of the source")
+			return
+		}
+	} else if source, ok = r.Get(from); !ok {
+		err = fmt.Errorf("not supported format: '%s'", from)
+		return
+	}
+	if target, ok = r.Get(to); !ok {
+		err = fmt.Errorf("not supported format: '%s'", to)
+		return
+	}
+
+	var suite *testing.TestSuite
+	if suite, err = source.Import(data); err != nil {
+		err = fmt.Errorf("failed to import from %s: %v", source.Name(), err)
+		return
+	}
+	makeNamesUnique(suite)
+
+	if result, err = target.Export(suite); err != nil {
+		err = fmt.Errorf("failed to export to %s: %v", target.Name(), err)
+	}
+	return
+}
+
+var nonWordReg = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// caseName generates a test case name from the method and the API
+func caseName(method, api string) (name string) {
+	if u, err := url.Parse(api); err == nil && (u.Host != "" || u.Path != "") {
+		api = u.Path
+	}
+	name = strings.Trim(nonWordReg.ReplaceAllString(api, "-"), "-")
+	if name == "" {
+		name = "root"
+	}
+	if method = strings.ToLower(method); method != "" {
+		name = method + "-" + name
+	}
+	return
+}
+
+// makeNamesUnique adds a suffix to the duplicated test case names
+func makeNamesUnique(suite *testing.TestSuite) {
+	names := map[string]int{}
+	for i := range suite.Items {
+		name := suite.Items[i].Name
+		if count, ok := names[name]; ok {
+			names[name] = count + 1
+			suite.Items[i].Name = fmt.Sprintf("%s-%d", name, count+1)
+		} else {
+			names[name] = 0
+		}
+	}
+}
+
+// getMethod returns the method of the request, GET is the default one
+func getMethod(request *testing.Request) string {
+	return strings.ToUpper(testing.EmptyThenDefault(request.Method, "GET"))
+}
+
+// sortedKeys returns the sorted keys of a map
+func sortedKeys(data map[string]string) (keys []string) {
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
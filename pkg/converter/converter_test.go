@@ -0,0 +1,119 @@
+package converter_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/converter"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := converter.NewDefaultRegistry()
+	assert.Equal(t, []string{"curl", "har", "jmeter", "json", "openapi", "postman", "yaml"}, registry.Names())
+
+	_, ok := registry.Get("fake")
+	assert.False(t, ok)
+
+	registry.Register(converter.NewYAMLConverter())
+	assert.Equal(t, 7, len(registry.Names()))
+
+	tests := []struct {
+		file   string
+		expect string
+	}{{
+		file:   "testdata/suite.yaml",
+		expect: "yaml",
+	}, {
+		file:   "testdata/postman.json",
+		expect: "postman",
+	}, {
+		file:   "testdata/har.json",
+		expect: "har",
+	}, {
+		file:   "testdata/openapi.yaml",
+		expect: "openapi",
+	}, {
+		file:   "testdata/swagger.json",
+		expect: "openapi",
+	}, {
+		file:   "testdata/curl.sh",
+		expect: "curl",
+	}, {
+		file:   "testdata/jmeter.jmx",
+		expect: "jmeter",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			c, ok := registry.Detect(readFile(t, tt.file))
+			if assert.True(t, ok) {
+				assert.Equal(t, tt.expect, c.Name())
+			}
+		})
+	}
+
+	_, ok = registry.Detect([]byte("fake"))
+	assert.False(t, ok)
+}
+
+func TestConvert(t *testing.T) {
+	registry := converter.NewDefaultRegistry()
+
+	data, err := registry.Convert("", "yaml", readFile(t, "testdata/har.json"))
+	assert.Nil(t, err)
+	suite, err := atest.Parse(data)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"get-api-users", "post-api-users"}, caseNames(suite))
+
+	// the duplicated names get a suffix
+	data, err = registry.Convert("curl", "yaml", []byte("curl http://foo\ncurl http://foo"))
+	assert.Nil(t, err)
+	suite, err = atest.Parse(data)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"get-root", "get-root-1"}, caseNames(suite))
+
+	_, err = registry.Convert("", "yaml", []byte("fake"))
+	assert.NotNil(t, err)
+
+	_, err = registry.Convert("fake", "yaml", nil)
+	assert.NotNil(t, err)
+
+	_, err = registry.Convert("yaml", "fake", nil)
+	assert.NotNil(t, err)
+
+	_, err = registry.Convert("yaml", "json", []byte("fake"))
+	assert.NotNil(t, err)
+}
+
+func readFile(t *testing.T, file string) []byte {
+	data, err := os.ReadFile(file)
+	assert.Nil(t, err)
+	return data
+}
+
+func caseNames(suite *atest.TestSuite) (names []string) {
+	for _, item := range suite.Items {
+		names = append(names, item.Name)
+	}
+	return
+}
+
+// exportThenImport exports the sample suite, then imports it back
+func exportThenImport(t *testing.T, c converter.Converter) (suite *atest.TestSuite, data []byte) {
+	sample, err := converter.NewYAMLConverter().Import(readFile(t, "testdata/suite.yaml"))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	data, err = c.Export(sample)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	suite, err = c.Import(data)
+	if !assert.Nil(t, err, string(data)) {
+		t.FailNow()
+	}
+	return
+}
@@ -0,0 +1,244 @@
+package converter
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+type curlConverter struct{}
+
+// NewCurlConverter creates the converter of the curl commands, the commands are separated by lines,
+// and the comment line in front of a command is the name of the test case
+func NewCurlConverter() Converter {
+	return &curlConverter{}
+}
+
+// Name returns the name of the format
+func (c *curlConverter) Name() string {
+	return "curl"
+}
+
+// Detect returns true if the data starts with a curl command
+func (c *curlConverter) Detect(data []byte) bool {
+	commands, _ := splitCurlCommands(string(data))
+	return len(commands) > 0
+}
+
+// Import parses the curl commands
+func (c *curlConverter) Import(data []byte) (suite *testing.TestSuite, err error) {
+	suite = &testing.TestSuite{Name: "curl"}
+	commands, names := splitCurlCommands(string(data))
+	for i, command := range commands {
+		var request *testing.Request
+		if request, err = parseCurlCommand(command); err != nil {
+			err = fmt.Errorf("failed to parse '%s': %v", command, err)
+			return
+		}
+
+		suite.Items = append(suite.Items, testing.TestCase{
+			Name:    testing.EmptyThenDefault(names[i], caseName(request.Method, request.API)),
+			Request: *request,
+		})
+	}
+	return
+}
+
+// Export generates the curl commands
+func (c *curlConverter) Export(suite *testing.TestSuite) (data []byte, err error) {
+	buf := new(strings.Builder)
+	for i := range suite.Items {
+		testCase := suite.Items[i]
+		suite.Inherit(&testCase)
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "# %s\n", testCase.Name)
+		fmt.Fprintf(buf, "curl -X %s %s", getMethod(&testCase.Request),
+			shellQuote(withQuery(testCase.Request.API, testCase.Request.Query)))
+		for _, key := range sortedKeys(testCase.Request.Header) {
+			fmt.Fprintf(buf, " \\\n  -H %s", shellQuote(key+": "+testCase.Request.Header[key]))
+		}
+		if testCase.Request.Body != "" {
+			fmt.Fprintf(buf, " \\\n  --data-raw %s", shellQuote(testCase.Request.Body))
+		}
+		for _, key := range sortedKeys(testCase.Request.Form) {
+			fmt.Fprintf(buf, " \\\n  --data-urlencode %s", shellQuote(key+"="+testCase.Request.Form[key]))
+		}
+		buf.WriteString("\n")
+	}
+	data = []byte(buf.String())
+	return
+}
+
+// splitCurlCommands returns the curl commands and the names from the comments
+func splitCurlCommands(text string) (commands, names []string) {
+	var name, command string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if command == "" {
+			if strings.HasPrefix(trimmed, "#") {
+				name = strings.TrimSpace(strings.TrimPrefix(trimmed, "#"))
+				continue
+			}
+			if trimmed == "" {
+				continue
+			}
+			if !strings.HasPrefix(trimmed, "curl ") {
+				return nil, nil
+			}
+		}
+
+		if strings.HasSuffix(trimmed, "\\") {
+			command += strings.TrimSuffix(trimmed, "\\") + " "
+			continue
+		}
+
+		commands = append(commands, command+trimmed)
+		names = append(names, name)
+		name, command = "", ""
+	}
+	if command != "" {
+		commands = append(commands, strings.TrimSpace(command))
+		names = append(names, name)
+	}
+	return
+}
+
+// parseCurlCommand parses the common options of a curl command
+func parseCurlCommand(command string) (request *testing.Request, err error) {
+	var args []string
+	if args, err = shellSplit(command); err != nil {
+		return
+	}
+
+	request = &testing.Request{}
+	addHeader := func(key, value string) {
+		if request.Header == nil {
+			request.Header = map[string]string{}
+		}
+		request.Header[key] = value
+	}
+
+	var hasData bool
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		value := func() (val string) {
+			if i+1 < len(args) {
+				i++
+				val = args[i]
+			}
+			return
+		}
+
+		switch arg {
+		case "-X", "--request":
+			request.Method = strings.ToUpper(value())
+		case "-H", "--header":
+			if key, val, ok := strings.Cut(value(), ":"); ok {
+				addHeader(strings.TrimSpace(key), strings.TrimSpace(val))
+			}
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii", "--json":
+			data := value()
+			if request.Body != "" {
+				data = request.Body + "&" + data
+			}
+			request.Body = data
+			hasData = true
+			if arg == "--json" {
+				addHeader("Content-Type", "application/json")
+			}
+		case "--data-urlencode":
+			if request.Form == nil {
+				request.Form = map[string]string{}
+			}
+			key, val, _ := strings.Cut(value(), "=")
+			request.Form[key] = val
+			hasData = true
+		case "-u", "--user":
+			addHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(value())))
+		case "-A", "--user-agent":
+			addHeader("User-Agent", value())
+		case "-b", "--cookie":
+			addHeader("Cookie", value())
+		case "-e", "--referer":
+			addHeader("Referer", value())
+		case "--url":
+			request.API = value()
+		case "-o", "--output", "-m", "--max-time", "--connect-timeout", "-w", "--write-out", "-x", "--proxy":
+			// the options which have nothing to do with the test case
+			value()
+		default:
+			if !strings.HasPrefix(arg, "-") && request.API == "" {
+				request.API = arg
+			}
+		}
+	}
+
+	if request.API == "" {
+		err = fmt.Errorf("no URL found")
+	} else if request.Method == "" {
+		request.Method = "GET"
+		if hasData {
+			request.Method = "POST"
+		}
+	}
+	return
+}
+
+// shellSplit splits the command line with the quotes of shell
+func shellSplit(command string) (args []string, err error) {
+	var current strings.Builder
+	var quote rune
+	inArg, escaped := false, false
+	for _, r := range command {
+		switch {
+		case escaped:
+			// the backslash only escapes some characters in the double quotes
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inArg = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		err = fmt.Errorf("unclosed quote %q", quote)
+	} else if inArg {
+		args = append(args, current.String())
+	}
+	return
+}
+
+// shellQuote quotes the text with the single quotes
+func shellQuote(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
@@ -0,0 +1,58 @@
+package converter_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/converter"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurlConverter(t *testing.T) {
+	c := converter.NewCurlConverter()
+	assert.Equal(t, "curl", c.Name())
+
+	suite, err := c.Import(readFile(t, "testdata/curl.sh"))
+	assert.Nil(t, err)
+	assert.Equal(t, &atest.TestSuite{
+		Name: "curl",
+		Items: []atest.TestCase{{
+			Name: "users",
+			Request: atest.Request{
+				API:    "http://localhost:8080/api/users?page=1",
+				Method: "GET",
+				Header: map[string]string{"Accept": "application/json"},
+			},
+		}, {
+			Name: "post-api-users",
+			Request: atest.Request{
+				API:    "http://localhost:8080/api/users",
+				Method: "POST",
+				Header: map[string]string{"Content-Type": "application/json"},
+				Body:   `{"name": "linuxsuren"}`,
+			},
+		}, {
+			Name: "login",
+			Request: atest.Request{
+				API:    "http://localhost:8080/login",
+				Method: "POST",
+				Header: map[string]string{"Authorization": "Basic YWRtaW46c2VjcmV0"},
+				Form:   map[string]string{"username": "admin"},
+			},
+		}},
+	}, suite)
+
+	_, err = c.Import([]byte("curl -H 'a: b'"))
+	assert.NotNil(t, err)
+
+	_, err = c.Import([]byte("curl 'http://foo"))
+	assert.NotNil(t, err)
+
+	suite, data := exportThenImport(t, c)
+	assert.True(t, c.(converter.Detector).Detect(data))
+	assert.Equal(t, []string{"users", "create-user", "login"}, caseNames(suite))
+	assert.Equal(t, `{"name": "linuxsuren"}`, suite.Items[1].Request.Body)
+	assert.Equal(t, "application/json", suite.Items[1].Request.Header["Content-Type"])
+
+	assert.False(t, c.(converter.Detector).Detect([]byte("name: foo")))
+}
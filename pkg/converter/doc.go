@@ -0,0 +1,2 @@
+// Package converter converts the test suites between different formats
+package converter
@@ -0,0 +1,218 @@
+package converter
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/linuxsuren/api-testing/pkg/version"
+)
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text,omitempty"`
+	Params   []harNameValue `json:"params,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harIgnoredHeaders are the headers which are managed by the HTTP client
+var harIgnoredHeaders = map[string]struct{}{
+	"host":            {},
+	"content-length":  {},
+	"connection":      {},
+	"accept-encoding": {},
+}
+
+type harConverter struct{}
+
+// NewHARConverter creates the converter of the HTTP Archive
+func NewHARConverter() Converter {
+	return &harConverter{}
+}
+
+// Name returns the name of the format
+func (c *harConverter) Name() string {
+	return "har"
+}
+
+// Detect returns true if the data is a HTTP Archive
+func (c *harConverter) Detect(data []byte) bool {
+	har := &harFile{}
+	return json.Unmarshal(data, har) == nil && har.Log.Version != "" && har.Log.Entries != nil
+}
+
+// Import converts the entries to test cases, the response status and body become the expectation
+func (c *harConverter) Import(data []byte) (suite *testing.TestSuite, err error) {
+	har := &harFile{}
+	if err = json.Unmarshal(data, har); err != nil {
+		return
+	}
+
+	suite = &testing.TestSuite{Name: testing.EmptyThenDefault(har.Log.Creator.Name, "har")}
+	for _, entry := range har.Log.Entries {
+		request := testing.Request{
+			API:    entry.Request.URL,
+			Method: strings.ToUpper(entry.Request.Method),
+		}
+		for _, header := range entry.Request.Headers {
+			if _, ok := harIgnoredHeaders[strings.ToLower(header.Name)]; ok || strings.HasPrefix(header.Name, ":") {
+				continue
+			}
+			if request.Header == nil {
+				request.Header = map[string]string{}
+			}
+			request.Header[header.Name] = header.Value
+		}
+		if postData := entry.Request.PostData; postData != nil {
+			if postData.Text != "" {
+				request.Body = postData.Text
+			} else if len(postData.Params) > 0 {
+				request.Form = map[string]string{}
+				for _, param := range postData.Params {
+					request.Form[param.Name] = param.Value
+				}
+			}
+		}
+
+		suite.Items = append(suite.Items, testing.TestCase{
+			Name:    caseName(request.Method, request.API),
+			Request: request,
+			Expect: testing.Response{
+				StatusCode: entry.Response.Status,
+				Body:       entry.Response.Content.Text,
+			},
+		})
+	}
+	return
+}
+
+// Export converts the test cases to entries, the expected status code becomes the response status
+func (c *harConverter) Export(suite *testing.TestSuite) (data []byte, err error) {
+	har := &harFile{
+		Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: "atest", Version: version.GetVersion()},
+			Entries: []harEntry{},
+		},
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for i := range suite.Items {
+		testCase := suite.Items[i]
+		suite.Inherit(&testCase)
+		request := harRequest{
+			Method:      getMethod(&testCase.Request),
+			URL:         withQuery(testCase.Request.API, testCase.Request.Query),
+			HTTPVersion: "HTTP/1.1",
+			Headers:     []harNameValue{},
+			QueryString: []harNameValue{},
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		}
+		for _, key := range sortedKeys(testCase.Request.Header) {
+			request.Headers = append(request.Headers, harNameValue{Name: key, Value: testCase.Request.Header[key]})
+		}
+		for _, key := range sortedKeys(testCase.Request.Query) {
+			request.QueryString = append(request.QueryString, harNameValue{Name: key, Value: testCase.Request.Query[key]})
+		}
+		if testCase.Request.Body != "" {
+			request.PostData = &harPostData{
+				MimeType: testing.EmptyThenDefault(testCase.Request.Header["Content-Type"], "application/json"),
+				Text:     testCase.Request.Body,
+			}
+		} else if len(testCase.Request.Form) > 0 {
+			request.PostData = &harPostData{MimeType: "application/x-www-form-urlencoded"}
+			for _, key := range sortedKeys(testCase.Request.Form) {
+				request.PostData.Params = append(request.PostData.Params, harNameValue{Name: key, Value: testCase.Request.Form[key]})
+			}
+		}
+
+		status := testCase.Expect.StatusCode
+		if status == 0 {
+			status = http.StatusOK
+		}
+		har.Log.Entries = append(har.Log.Entries, harEntry{
+			StartedDateTime: now,
+			Request:         request,
+			Response: harResponse{
+				Status:      status,
+				StatusText:  http.StatusText(status),
+				HTTPVersion: "HTTP/1.1",
+				Headers:     []harNameValue{},
+				Cookies:     []harNameValue{},
+				Content:     harContent{Size: len(testCase.Expect.Body), MimeType: "application/json", Text: testCase.Expect.Body},
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+		})
+	}
+	data, err = json.MarshalIndent(har, "", "  ")
+	return
+}
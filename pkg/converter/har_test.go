@@ -0,0 +1,49 @@
+package converter_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/converter"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHARConverter(t *testing.T) {
+	c := converter.NewHARConverter()
+	assert.Equal(t, "har", c.Name())
+
+	suite, err := c.Import(readFile(t, "testdata/har.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, &atest.TestSuite{
+		Name: "WebInspector",
+		Items: []atest.TestCase{{
+			Name: "get-api-users",
+			Request: atest.Request{
+				API:    "http://localhost:8080/api/users?page=1",
+				Method: "GET",
+				Header: map[string]string{"Accept": "application/json"},
+			},
+			Expect: atest.Response{StatusCode: 200},
+		}, {
+			Name: "post-api-users",
+			Request: atest.Request{
+				API:    "http://localhost:8080/api/users",
+				Method: "POST",
+				Header: map[string]string{"Content-Type": "application/json"},
+				Body:   `{"name": "linuxsuren"}`,
+			},
+			Expect: atest.Response{StatusCode: 201},
+		}},
+	}, suite)
+
+	_, err = c.Import([]byte("fake"))
+	assert.NotNil(t, err)
+
+	suite, data := exportThenImport(t, c)
+	assert.True(t, c.(converter.Detector).Detect(data))
+	assert.Equal(t, 3, len(suite.Items))
+	assert.Equal(t, "http://localhost:8080/api/users?page=1", suite.Items[0].Request.API)
+	assert.Equal(t, 201, suite.Items[1].Expect.StatusCode)
+	assert.Equal(t, 200, suite.Items[2].Expect.StatusCode)
+	assert.Equal(t, map[string]string{"username": "admin"}, suite.Items[2].Request.Form)
+}
@@ -0,0 +1,258 @@
+package converter
+
+import (
+	_ "embed"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/render"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+//go:embed data/jmeter.jmx
+var jmeterTemplate string
+
+type jmeterTestPlan struct {
+	Name     string
+	Samplers []jmeterSampler
+}
+
+type jmeterSampler struct {
+	Name       string
+	Domain     string
+	Port       string
+	Protocol   string
+	Path       string
+	Method     string
+	Raw        bool
+	Arguments  []jmeterArgument
+	Headers    []jmeterArgument
+	StatusCode string
+}
+
+type jmeterArgument struct {
+	Name   string
+	Value  string
+	Encode bool
+}
+
+// jmxElement is the common structure of the elements in a JMX file
+type jmxElement struct {
+	TestName        string              `xml:"testname,attr"`
+	Enabled         string              `xml:"enabled,attr"`
+	StringProps     []jmxProp           `xml:"stringProp"`
+	BoolProps       []jmxProp           `xml:"boolProp"`
+	ElementProps    []jmxElement        `xml:"elementProp"`
+	CollectionProps []jmxCollectionProp `xml:"collectionProp"`
+}
+
+type jmxProp struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type jmxCollectionProp struct {
+	Name         string       `xml:"name,attr"`
+	StringProps  []jmxProp    `xml:"stringProp"`
+	ElementProps []jmxElement `xml:"elementProp"`
+}
+
+func (e *jmxElement) prop(name string) string {
+	for _, props := range [][]jmxProp{e.StringProps, e.BoolProps} {
+		for _, prop := range props {
+			if prop.Name == name {
+				return prop.Value
+			}
+		}
+	}
+	return ""
+}
+
+func (e *jmxElement) collection(name string) (collection jmxCollectionProp) {
+	for _, item := range e.CollectionProps {
+		if item.Name == name {
+			collection = item
+			break
+		}
+	}
+	return
+}
+
+type jmeterConverter struct{}
+
+// NewJMeterConverter creates the converter of the JMeter test plan
+func NewJMeterConverter() Converter {
+	return &jmeterConverter{}
+}
+
+// Name returns the name of the format
+func (c *jmeterConverter) Name() string {
+	return "jmeter"
+}
+
+// Detect returns true if the data is a JMeter test plan
+func (c *jmeterConverter) Detect(data []byte) bool {
+	return strings.Contains(string(data), "<jmeterTestPlan")
+}
+
+// Import converts the HTTP samplers to test cases. The header managers and the status code assertions
+// after a sampler belong to it, the header managers in front of all the samplers belong to all of them.
+func (c *jmeterConverter) Import(data []byte) (suite *testing.TestSuite, err error) {
+	suite = &testing.TestSuite{}
+	globalHeaders := map[string]string{}
+
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		var token xml.Token
+		if token, err = decoder.Token(); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			break
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		element := &jmxElement{}
+		switch start.Name.Local {
+		case "TestPlan":
+			if err = decoder.DecodeElement(element, &start); err == nil {
+				suite.Name = element.TestName
+			}
+		case "HTTPSamplerProxy":
+			if err = decoder.DecodeElement(element, &start); err == nil && element.Enabled != "false" {
+				suite.Items = append(suite.Items, c.importSampler(element, globalHeaders))
+			}
+		case "HeaderManager":
+			if err = decoder.DecodeElement(element, &start); err == nil && element.Enabled != "false" {
+				headers := globalHeaders
+				if len(suite.Items) > 0 {
+					last := &suite.Items[len(suite.Items)-1]
+					if last.Request.Header == nil {
+						last.Request.Header = map[string]string{}
+					}
+					headers = last.Request.Header
+				}
+				for _, header := range element.collection("HeaderManager.headers").ElementProps {
+					headers[header.prop("Header.name")] = header.prop("Header.value")
+				}
+			}
+		case "ResponseAssertion":
+			if err = decoder.DecodeElement(element, &start); err == nil && len(suite.Items) > 0 &&
+				element.prop("Assertion.test_field") == "Assertion.response_code" {
+				for _, item := range element.collection("Asserion.test_strings").StringProps {
+					if code, convErr := strconv.Atoi(strings.TrimSpace(item.Value)); convErr == nil {
+						suite.Items[len(suite.Items)-1].Expect.StatusCode = code
+						break
+					}
+				}
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return
+}
+
+func (c *jmeterConverter) importSampler(element *jmxElement, globalHeaders map[string]string) (testCase testing.TestCase) {
+	api := &url.URL{
+		Scheme: testing.EmptyThenDefault(element.prop("HTTPSampler.protocol"), "http"),
+		Host:   element.prop("HTTPSampler.domain"),
+	}
+	if port := element.prop("HTTPSampler.port"); port != "" {
+		api.Host += ":" + port
+	}
+
+	request := testing.Request{
+		API:    api.String() + element.prop("HTTPSampler.path"),
+		Method: strings.ToUpper(testing.EmptyThenDefault(element.prop("HTTPSampler.method"), "GET")),
+	}
+	for key, val := range globalHeaders {
+		if request.Header == nil {
+			request.Header = map[string]string{}
+		}
+		request.Header[key] = val
+	}
+
+	raw := element.prop("HTTPSampler.postBodyRaw") == "true"
+	for _, arguments := range element.ElementProps {
+		for _, argument := range arguments.collection("Arguments.arguments").ElementProps {
+			name, value := argument.prop("Argument.name"), argument.prop("Argument.value")
+			switch {
+			case raw:
+				request.Body += value
+			case request.Method == "GET":
+				if request.Query == nil {
+					request.Query = map[string]string{}
+				}
+				request.Query[name] = value
+			default:
+				if request.Form == nil {
+					request.Form = map[string]string{}
+				}
+				request.Form[name] = value
+			}
+		}
+	}
+
+	testCase = testing.TestCase{
+		Name:    testing.EmptyThenDefault(element.TestName, caseName(request.Method, request.API)),
+		Request: request,
+	}
+	return
+}
+
+// Export converts the test cases to the HTTP samplers of a thread group
+func (c *jmeterConverter) Export(suite *testing.TestSuite) (data []byte, err error) {
+	plan := jmeterTestPlan{Name: suite.Name}
+	for i := range suite.Items {
+		testCase := suite.Items[i]
+		suite.Inherit(&testCase)
+
+		var api *url.URL
+		if api, err = url.Parse(testCase.Request.API); err != nil {
+			return
+		}
+
+		sampler := jmeterSampler{
+			Name:     testCase.Name,
+			Domain:   api.Hostname(),
+			Port:     api.Port(),
+			Protocol: api.Scheme,
+			Path:     api.RequestURI(),
+			Method:   getMethod(&testCase.Request),
+			Raw:      testCase.Request.Body != "",
+		}
+		if sampler.Raw {
+			sampler.Arguments = []jmeterArgument{{Value: testCase.Request.Body}}
+		} else {
+			for _, key := range sortedKeys(testCase.Request.Query) {
+				sampler.Arguments = append(sampler.Arguments, jmeterArgument{Name: key, Value: testCase.Request.Query[key], Encode: true})
+			}
+			for _, key := range sortedKeys(testCase.Request.Form) {
+				sampler.Arguments = append(sampler.Arguments, jmeterArgument{Name: key, Value: testCase.Request.Form[key], Encode: true})
+			}
+		}
+		for _, key := range sortedKeys(testCase.Request.Header) {
+			sampler.Headers = append(sampler.Headers, jmeterArgument{Name: key, Value: testCase.Request.Header[key]})
+		}
+		if testCase.Expect.StatusCode > 0 {
+			sampler.StatusCode = strconv.Itoa(testCase.Expect.StatusCode)
+		}
+		plan.Samplers = append(plan.Samplers, sampler)
+	}
+
+	var result string
+	if result, err = render.Render("jmeter", jmeterTemplate, plan); err == nil {
+		data = []byte(result + "\n")
+	}
+	return
+}
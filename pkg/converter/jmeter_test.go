@@ -0,0 +1,51 @@
+package converter_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/converter"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJMeterConverter(t *testing.T) {
+	c := converter.NewJMeterConverter()
+	assert.Equal(t, "jmeter", c.Name())
+
+	suite, err := c.Import(readFile(t, "testdata/jmeter.jmx"))
+	assert.Nil(t, err)
+	assert.Equal(t, &atest.TestSuite{
+		Name: "sample",
+		Items: []atest.TestCase{{
+			Name: "users",
+			Request: atest.Request{
+				API:    "http://localhost:8080/api/users",
+				Method: "GET",
+				Query:  map[string]string{"page": "1"},
+				Header: map[string]string{"Accept": "application/json"},
+			},
+			Expect: atest.Response{StatusCode: 200},
+		}, {
+			Name: "create-user",
+			Request: atest.Request{
+				API:    "http://localhost:8080/api/users",
+				Method: "POST",
+				Header: map[string]string{"Accept": "application/json", "Content-Type": "application/json"},
+				Body:   `{"name": "linuxsuren"}`,
+			},
+		}},
+	}, suite)
+
+	_, err = c.Import([]byte("<jmeterTestPlan>"))
+	assert.NotNil(t, err)
+
+	suite, data := exportThenImport(t, c)
+	assert.True(t, c.(converter.Detector).Detect(data))
+	assert.Equal(t, "sample", suite.Name)
+	assert.Equal(t, []string{"users", "create-user", "login"}, caseNames(suite))
+	assert.Equal(t, map[string]string{"page": "1"}, suite.Items[0].Request.Query)
+	assert.Equal(t, `{"name": "linuxsuren"}`, suite.Items[1].Request.Body)
+	assert.Equal(t, 201, suite.Items[1].Expect.StatusCode)
+	assert.Equal(t, "http://localhost:8080/login", suite.Items[2].Request.API)
+	assert.Equal(t, map[string]string{"username": "admin"}, suite.Items[2].Request.Form)
+}
@@ -0,0 +1,79 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	ghodssyaml "github.com/ghodss/yaml"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"gopkg.in/yaml.v2"
+)
+
+type yamlConverter struct{}
+
+// NewYAMLConverter creates the converter of the native YAML format
+func NewYAMLConverter() Converter {
+	return &yamlConverter{}
+}
+
+// Name returns the name of the format
+func (c *yamlConverter) Name() string {
+	return "yaml"
+}
+
+// Import parses and validates the test suite
+func (c *yamlConverter) Import(data []byte) (*testing.TestSuite, error) {
+	return testing.Parse(data)
+}
+
+// Export marshals the test suite to YAML
+func (c *yamlConverter) Export(suite *testing.TestSuite) ([]byte, error) {
+	return yaml.Marshal(suite)
+}
+
+// Detect returns true if the data is a valid test suite
+func (c *yamlConverter) Detect(data []byte) bool {
+	_, err := testing.Parse(data)
+	return err == nil
+}
+
+type jsonConverter struct{}
+
+// NewJSONConverter creates the converter of the native JSON format
+func NewJSONConverter() Converter {
+	return &jsonConverter{}
+}
+
+// Name returns the name of the format
+func (c *jsonConverter) Name() string {
+	return "json"
+}
+
+// Import parses and validates the test suite, JSON is a subset of YAML
+func (c *jsonConverter) Import(data []byte) (*testing.TestSuite, error) {
+	return testing.Parse(data)
+}
+
+// Export marshals the test suite to JSON which has the same fields as the YAML one
+func (c *jsonConverter) Export(suite *testing.TestSuite) (data []byte, err error) {
+	if data, err = yaml.Marshal(suite); err != nil {
+		return
+	}
+	if data, err = ghodssyaml.YAMLToJSON(data); err == nil {
+		buf := new(bytes.Buffer)
+		if err = json.Indent(buf, data, "", "  "); err == nil {
+			data = buf.Bytes()
+		}
+	}
+	return
+}
+
+// Detect returns true if the data is a valid test suite in JSON
+func (c *jsonConverter) Detect(data []byte) bool {
+	if !strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		return false
+	}
+	_, err := testing.Parse(data)
+	return err == nil
+}
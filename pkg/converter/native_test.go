@@ -0,0 +1,24 @@
+package converter_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/converter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNativeConverters(t *testing.T) {
+	sample, err := converter.NewYAMLConverter().Import(readFile(t, "testdata/suite.yaml"))
+	assert.Nil(t, err)
+
+	for _, c := range []converter.Converter{converter.NewYAMLConverter(), converter.NewJSONConverter()} {
+		t.Run(c.Name(), func(t *testing.T) {
+			suite, data := exportThenImport(t, c)
+			assert.Equal(t, sample, suite)
+			assert.True(t, c.(converter.Detector).Detect(data))
+		})
+	}
+
+	assert.False(t, converter.NewJSONConverter().(converter.Detector).Detect(readFile(t, "testdata/suite.yaml")))
+	assert.False(t, converter.NewJSONConverter().(converter.Detector).Detect([]byte("{}")))
+}
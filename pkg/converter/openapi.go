@@ -0,0 +1,209 @@
+package converter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+type openAPIDoc struct {
+	OpenAPI  string                                `json:"openapi,omitempty"`
+	Swagger  string                                `json:"swagger,omitempty"`
+	Info     openAPIInfo                           `json:"info"`
+	Servers  []openAPIServer                       `json:"servers,omitempty"`
+	Host     string                                `json:"host,omitempty"`
+	BasePath string                                `json:"basePath,omitempty"`
+	Schemes  []string                              `json:"schemes,omitempty"`
+	Paths    map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIRequestBody struct {
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Example interface{} `json:"example,omitempty"`
+}
+
+type openAPIResponse struct {
+	Description string `json:"description"`
+}
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+type openAPIConverter struct{}
+
+// NewOpenAPIConverter creates the converter of the OpenAPI v3 and Swagger v2 documents
+func NewOpenAPIConverter() Converter {
+	return &openAPIConverter{}
+}
+
+// Name returns the name of the format
+func (c *openAPIConverter) Name() string {
+	return "openapi"
+}
+
+// Detect returns true if the data is an OpenAPI or Swagger document
+func (c *openAPIConverter) Detect(data []byte) bool {
+	doc := &openAPIDoc{}
+	return yaml.Unmarshal(data, doc) == nil && (doc.OpenAPI != "" || doc.Swagger != "")
+}
+
+// Import generates a test case for each operation, the expected status code is the first successful response
+func (c *openAPIConverter) Import(data []byte) (suite *testing.TestSuite, err error) {
+	doc := &openAPIDoc{}
+	if err = yaml.Unmarshal(data, doc); err != nil {
+		return
+	}
+
+	suite = &testing.TestSuite{Name: doc.Info.Title}
+	if len(doc.Servers) > 0 {
+		suite.API = doc.Servers[0].URL
+	} else if doc.Host != "" {
+		scheme := "https"
+		if len(doc.Schemes) > 0 {
+			scheme = doc.Schemes[0]
+		}
+		suite.API = scheme + "://" + doc.Host + doc.BasePath
+	}
+	suite.API = strings.TrimSuffix(suite.API, "/")
+
+	var paths []string
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for _, method := range openAPIMethods {
+			raw, ok := doc.Paths[path][method]
+			if !ok {
+				continue
+			}
+
+			operation := &openAPIOperation{}
+			if err = json.Unmarshal(raw, operation); err != nil {
+				return
+			}
+
+			testCase := testing.TestCase{
+				Name: testing.EmptyThenDefault(operation.OperationID, caseName(method, path)),
+				Request: testing.Request{
+					API:    path,
+					Method: strings.ToUpper(method),
+				},
+				Expect: testing.Response{
+					StatusCode: getSuccessStatus(operation.Responses),
+				},
+			}
+			if operation.RequestBody != nil {
+				if media, ok := operation.RequestBody.Content["application/json"]; ok {
+					testCase.Request.Header = map[string]string{"Content-Type": "application/json"}
+					if media.Example != nil {
+						var body []byte
+						if body, err = json.Marshal(media.Example); err != nil {
+							return
+						}
+						testCase.Request.Body = string(body)
+					}
+				}
+			}
+			suite.Items = append(suite.Items, testCase)
+		}
+	}
+	return
+}
+
+// Export generates an OpenAPI v3 document, the test cases with the same path and method are merged
+func (c *openAPIConverter) Export(suite *testing.TestSuite) (data []byte, err error) {
+	doc := &openAPIDoc{
+		OpenAPI: "3.0.0",
+		Info:    openAPIInfo{Title: suite.Name, Version: "1.0.0"},
+		Paths:   map[string]map[string]json.RawMessage{},
+	}
+	if suite.API != "" {
+		doc.Servers = []openAPIServer{{URL: suite.API}}
+	}
+
+	for i := range suite.Items {
+		testCase := &suite.Items[i]
+		path := testCase.Request.API
+		if !strings.HasPrefix(path, "/") {
+			if u, parseErr := url.Parse(path); parseErr == nil {
+				path = testing.EmptyThenDefault(u.Path, "/")
+			}
+		}
+		method := strings.ToLower(getMethod(&testCase.Request))
+		if _, ok := doc.Paths[path][method]; ok {
+			continue
+		}
+
+		status := testCase.Expect.StatusCode
+		if status == 0 {
+			status = http.StatusOK
+		}
+		operation := &openAPIOperation{
+			OperationID: testCase.Name,
+			Responses: map[string]openAPIResponse{
+				strconv.Itoa(status): {Description: http.StatusText(status)},
+			},
+		}
+		if testCase.Request.Body != "" {
+			var example interface{}
+			if json.Unmarshal([]byte(testCase.Request.Body), &example) != nil {
+				example = testCase.Request.Body
+			}
+			operation.RequestBody = &openAPIRequestBody{
+				Content: map[string]openAPIMediaType{"application/json": {Example: example}},
+			}
+		}
+
+		var raw []byte
+		if raw, err = json.Marshal(operation); err != nil {
+			return
+		}
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]json.RawMessage{}
+		}
+		doc.Paths[path][method] = raw
+	}
+	data, err = json.MarshalIndent(doc, "", "  ")
+	return
+}
+
+// getSuccessStatus returns the first 2xx status code
+func getSuccessStatus(responses map[string]openAPIResponse) (status int) {
+	var codes []int
+	for key := range responses {
+		if code, err := strconv.Atoi(key); err == nil && code >= 200 && code < 300 {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) > 0 {
+		sort.Ints(codes)
+		status = codes[0]
+	}
+	return
+}
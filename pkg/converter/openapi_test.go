@@ -0,0 +1,54 @@
+package converter_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/converter"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIConverter(t *testing.T) {
+	c := converter.NewOpenAPIConverter()
+	assert.Equal(t, "openapi", c.Name())
+
+	suite, err := c.Import(readFile(t, "testdata/openapi.yaml"))
+	assert.Nil(t, err)
+	assert.Equal(t, &atest.TestSuite{
+		Name: "sample",
+		API:  "http://localhost:8080/api",
+		Items: []atest.TestCase{{
+			Name:    "users",
+			Request: atest.Request{API: "/users", Method: "GET"},
+			Expect:  atest.Response{StatusCode: 200},
+		}, {
+			Name: "post-users",
+			Request: atest.Request{
+				API:    "/users",
+				Method: "POST",
+				Header: map[string]string{"Content-Type": "application/json"},
+				Body:   `{"name":"linuxsuren"}`,
+			},
+			Expect: atest.Response{StatusCode: 201},
+		}, {
+			Name:    "delete-users-name",
+			Request: atest.Request{API: "/users/{name}", Method: "DELETE"},
+			Expect:  atest.Response{StatusCode: 204},
+		}},
+	}, suite)
+
+	suite, err = c.Import(readFile(t, "testdata/swagger.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:8080/api", suite.API)
+	assert.Equal(t, []string{"users"}, caseNames(suite))
+
+	_, err = c.Import([]byte("fake"))
+	assert.NotNil(t, err)
+
+	suite, data := exportThenImport(t, c)
+	assert.True(t, c.(converter.Detector).Detect(data))
+	assert.Equal(t, "http://localhost:8080/api", suite.API)
+	assert.Equal(t, []string{"login", "users", "create-user"}, caseNames(suite))
+	assert.Equal(t, 201, suite.Items[2].Expect.StatusCode)
+	assert.Equal(t, `{"name":"linuxsuren"}`, suite.Items[2].Request.Body)
+}
@@ -0,0 +1,360 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// postmanBaseURL is the variable of the suite API in the exported collection
+const postmanBaseURL = "baseUrl"
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Auth     *postmanAuth      `json:"auth,omitempty"`
+	Variable []postmanKeyValue `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	PostmanID string `json:"_postman_id,omitempty"`
+	Name      string `json:"name"`
+	Schema    string `json:"schema"`
+}
+
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item,omitempty"`
+	Request *postmanRequest `json:"request,omitempty"`
+	Event   []postmanEvent  `json:"event,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Header []postmanKeyValue `json:"header,omitempty"`
+	Body   *postmanBody      `json:"body,omitempty"`
+	URL    postmanURL        `json:"url"`
+	Auth   *postmanAuth      `json:"auth,omitempty"`
+}
+
+// postmanAuth is the authentication of a request or the collection, the parameters are in the field of its type
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Basic  []postmanKeyValue `json:"basic,omitempty"`
+	APIKey []postmanKeyValue `json:"apikey,omitempty"`
+	Bearer []postmanKeyValue `json:"bearer,omitempty"`
+	OAuth2 []postmanKeyValue `json:"oauth2,omitempty"`
+}
+
+// postmanEvent is the script which runs before the request (prerequest) or after the response (test)
+type postmanEvent struct {
+	Listen string        `json:"listen"`
+	Script postmanScript `json:"script"`
+}
+
+type postmanScript struct {
+	Type string   `json:"type"`
+	Exec []string `json:"exec"`
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw,omitempty"`
+	URLEncoded []postmanKeyValue `json:"urlencoded,omitempty"`
+}
+
+type postmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// postmanURL could be a string or an object in the collection
+type postmanURL struct {
+	Raw   string            `json:"raw"`
+	Query []postmanKeyValue `json:"query,omitempty"`
+}
+
+// UnmarshalJSON supports both the string and the object
+func (u *postmanURL) UnmarshalJSON(data []byte) (err error) {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &u.Raw)
+	}
+	type alias postmanURL
+	return json.Unmarshal(data, (*alias)(u))
+}
+
+type postmanConverter struct{}
+
+// NewPostmanConverter creates the converter of the Postman collection v2.1
+func NewPostmanConverter() Converter {
+	return &postmanConverter{}
+}
+
+// Name returns the name of the format
+func (c *postmanConverter) Name() string {
+	return "postman"
+}
+
+// Detect returns true if the data is a Postman collection
+func (c *postmanConverter) Detect(data []byte) bool {
+	collection := &postmanCollection{}
+	return json.Unmarshal(data, collection) == nil &&
+		(collection.Info.PostmanID != "" || strings.Contains(collection.Info.Schema, "getpostman.com"))
+}
+
+// Import converts the collection to a test suite, the folders become the groups
+func (c *postmanConverter) Import(data []byte) (suite *testing.TestSuite, err error) {
+	collection := &postmanCollection{}
+	if err = json.Unmarshal(data, collection); err != nil {
+		return
+	}
+
+	variables := map[string]string{}
+	for _, item := range collection.Variable {
+		variables[item.Key] = item.Value
+	}
+
+	suite = &testing.TestSuite{Name: collection.Info.Name, Auth: importPostmanAuth(collection.Auth)}
+	c.importItems(suite, collection.Item, "", variables)
+	return
+}
+
+var postmanVariableReg = regexp.MustCompile(`{{\s*([\w.-]+)\s*}}`)
+
+var postmanStatusReg = regexp.MustCompile(`pm\.response\.to\.have\.status\((\d+)\)`)
+
+func (c *postmanConverter) importItems(suite *testing.TestSuite, items []postmanItem, group string, variables map[string]string) {
+	replace := func(text string) string {
+		return postmanVariableReg.ReplaceAllStringFunc(text, func(item string) string {
+			key := postmanVariableReg.FindStringSubmatch(item)[1]
+			if val, ok := variables[key]; ok {
+				return val
+			}
+			return item
+		})
+	}
+
+	for _, item := range items {
+		if item.Request == nil {
+			c.importItems(suite, item.Item, item.Name, variables)
+			continue
+		}
+
+		request := testing.Request{
+			API:    replace(item.Request.URL.Raw),
+			Method: strings.ToUpper(item.Request.Method),
+			Auth:   importPostmanAuth(item.Request.Auth),
+		}
+		for _, header := range item.Request.Header {
+			if !header.Disabled {
+				if request.Header == nil {
+					request.Header = map[string]string{}
+				}
+				request.Header[header.Key] = replace(header.Value)
+			}
+		}
+		if body := item.Request.Body; body != nil {
+			switch body.Mode {
+			case "raw":
+				request.Body = replace(body.Raw)
+			case "urlencoded":
+				request.Form = map[string]string{}
+				for _, field := range body.URLEncoded {
+					if !field.Disabled {
+						request.Form[field.Key] = replace(field.Value)
+					}
+				}
+			}
+		}
+
+		testCase := testing.TestCase{
+			Name:    testing.EmptyThenDefault(item.Name, caseName(request.Method, request.API)),
+			Group:   group,
+			Request: request,
+		}
+		// only the status code is taken from the test scripts, the others are too flexible to convert
+		for _, event := range item.Event {
+			if event.Listen != "test" {
+				continue
+			}
+			if matched := postmanStatusReg.FindStringSubmatch(strings.Join(event.Script.Exec, "\n")); matched != nil {
+				testCase.Expect.StatusCode, _ = strconv.Atoi(matched[1])
+			}
+		}
+		suite.Items = append(suite.Items, testCase)
+	}
+}
+
+// Export converts the test suite to a collection, the groups become the folders.
+// The suite API becomes the variable baseUrl, and the expectations become the test scripts
+func (c *postmanConverter) Export(suite *testing.TestSuite) (data []byte, err error) {
+	collection := &postmanCollection{
+		Info: postmanInfo{Name: suite.Name, Schema: postmanSchema},
+		Item: []postmanItem{},
+		Auth: exportPostmanAuth(suite.Auth),
+	}
+	if suite.API != "" {
+		collection.Variable = []postmanKeyValue{{Key: postmanBaseURL, Value: strings.TrimSuffix(suite.API, "/")}}
+	}
+
+	folders := map[string]int{}
+	for i := range suite.Items {
+		testCase := &suite.Items[i]
+		api := testCase.Request.API
+		if suite.API != "" && strings.HasPrefix(api, "/") {
+			api = "{{" + postmanBaseURL + "}}" + api
+		}
+		item := postmanItem{
+			Name: testCase.Name,
+			Request: &postmanRequest{
+				Method: getMethod(&testCase.Request),
+				URL:    postmanURL{Raw: withQuery(api, testCase.Request.Query)},
+				Auth:   exportPostmanAuth(testCase.Request.Auth),
+			},
+		}
+		for _, key := range sortedKeys(testCase.Request.Query) {
+			item.Request.URL.Query = append(item.Request.URL.Query, postmanKeyValue{Key: key, Value: testCase.Request.Query[key]})
+		}
+		if exec := postmanTestScript(&testCase.Expect); len(exec) > 0 {
+			item.Event = []postmanEvent{{Listen: "test", Script: postmanScript{Type: "text/javascript", Exec: exec}}}
+		}
+		for _, key := range sortedKeys(testCase.Request.Header) {
+			item.Request.Header = append(item.Request.Header, postmanKeyValue{Key: key, Value: testCase.Request.Header[key]})
+		}
+		if testCase.Request.Body != "" {
+			item.Request.Body = &postmanBody{Mode: "raw", Raw: testCase.Request.Body}
+		} else if len(testCase.Request.Form) > 0 {
+			item.Request.Body = &postmanBody{Mode: "urlencoded"}
+			for _, key := range sortedKeys(testCase.Request.Form) {
+				item.Request.Body.URLEncoded = append(item.Request.Body.URLEncoded, postmanKeyValue{Key: key, Value: testCase.Request.Form[key]})
+			}
+		}
+
+		if testCase.Group == "" {
+			collection.Item = append(collection.Item, item)
+		} else if index, ok := folders[testCase.Group]; ok {
+			collection.Item[index].Item = append(collection.Item[index].Item, item)
+		} else {
+			folders[testCase.Group] = len(collection.Item)
+			collection.Item = append(collection.Item, postmanItem{Name: testCase.Group, Item: []postmanItem{item}})
+		}
+	}
+	data, err = json.MarshalIndent(collection, "", "  ")
+	return
+}
+
+// postmanTestScript converts the status code, the headers and the substrings of the body to the test script
+func postmanTestScript(expect *testing.Response) (exec []string) {
+	if expect.StatusCode > 0 {
+		exec = append(exec, fmt.Sprintf("pm.test(\"status code is %d\", function () {", expect.StatusCode),
+			fmt.Sprintf("    pm.response.to.have.status(%d);", expect.StatusCode), "});")
+	}
+	for _, key := range sortedKeys(expect.Header) {
+		exec = append(exec, fmt.Sprintf("pm.test(%s, function () {", jsString("header "+key)),
+			fmt.Sprintf("    pm.response.to.have.header(%s, %s);", jsString(key), jsString(expect.Header[key])), "});")
+	}
+	for _, text := range expect.BodyContains {
+		exec = append(exec, fmt.Sprintf("pm.test(%s, function () {", jsString("body contains "+text)),
+			fmt.Sprintf("    pm.expect(pm.response.text()).to.include(%s);", jsString(text)), "});")
+	}
+	return
+}
+
+// jsString returns the quoted string literal of JavaScript
+func jsString(text string) string {
+	data, _ := json.Marshal(text)
+	return string(data)
+}
+
+// exportPostmanAuth converts the authentication, the API key of the Authorization header with the prefix Bearer is a bearer token
+func exportPostmanAuth(auth *testing.Auth) (result *postmanAuth) {
+	switch {
+	case auth == nil:
+	case auth.Basic != nil:
+		result = &postmanAuth{Type: "basic", Basic: []postmanKeyValue{
+			{Key: "username", Value: auth.Basic.Username},
+			{Key: "password", Value: auth.Basic.Password},
+		}}
+	case auth.APIKey != nil:
+		if strings.EqualFold(auth.APIKey.Name, "Authorization") && strings.HasPrefix(auth.APIKey.Value, "Bearer ") &&
+			auth.APIKey.In != "query" {
+			result = &postmanAuth{Type: "bearer", Bearer: []postmanKeyValue{
+				{Key: "token", Value: strings.TrimPrefix(auth.APIKey.Value, "Bearer ")},
+			}}
+		} else {
+			result = &postmanAuth{Type: "apikey", APIKey: []postmanKeyValue{
+				{Key: "key", Value: auth.APIKey.Name},
+				{Key: "value", Value: auth.APIKey.Value},
+				{Key: "in", Value: testing.EmptyThenDefault(auth.APIKey.In, "header")},
+			}}
+		}
+	case auth.OAuth2 != nil:
+		result = &postmanAuth{Type: "oauth2", OAuth2: []postmanKeyValue{
+			{Key: "grant_type", Value: "client_credentials"},
+			{Key: "accessTokenUrl", Value: auth.OAuth2.TokenURL},
+			{Key: "clientId", Value: auth.OAuth2.ClientID},
+			{Key: "clientSecret", Value: auth.OAuth2.ClientSecret},
+			{Key: "scope", Value: strings.Join(auth.OAuth2.Scopes, " ")},
+		}}
+	}
+	return
+}
+
+// importPostmanAuth converts the basic, the API key and the bearer authentications, the others are ignored
+func importPostmanAuth(auth *postmanAuth) (result *testing.Auth) {
+	if auth == nil {
+		return
+	}
+
+	get := func(params []postmanKeyValue, key string) string {
+		for _, param := range params {
+			if param.Key == key {
+				return param.Value
+			}
+		}
+		return ""
+	}
+	switch auth.Type {
+	case "basic":
+		result = &testing.Auth{Basic: &testing.BasicAuth{
+			Username: get(auth.Basic, "username"),
+			Password: get(auth.Basic, "password"),
+		}}
+	case "apikey":
+		result = &testing.Auth{APIKey: &testing.APIKeyAuth{
+			Name:  get(auth.APIKey, "key"),
+			Value: get(auth.APIKey, "value"),
+			In:    get(auth.APIKey, "in"),
+		}}
+	case "bearer":
+		result = &testing.Auth{APIKey: &testing.APIKeyAuth{
+			Name:  "Authorization",
+			Value: "Bearer " + get(auth.Bearer, "token"),
+		}}
+	}
+	return
+}
+
+// withQuery appends the query parameters to the API
+func withQuery(api string, query map[string]string) string {
+	if len(query) == 0 {
+		return api
+	}
+
+	values := url.Values{}
+	for key, val := range query {
+		values.Set(key, val)
+	}
+	if strings.Contains(api, "?") {
+		return api + "&" + values.Encode()
+	}
+	return api + "?" + values.Encode()
+}
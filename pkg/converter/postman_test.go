@@ -0,0 +1,104 @@
+package converter_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/converter"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostmanConverter(t *testing.T) {
+	c := converter.NewPostmanConverter()
+	assert.Equal(t, "postman", c.Name())
+
+	suite, err := c.Import(readFile(t, "testdata/postman.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, &atest.TestSuite{
+		Name: "sample",
+		Items: []atest.TestCase{{
+			Name: "users",
+			Request: atest.Request{
+				API:    "http://localhost:8080/api/users?page=1",
+				Method: "GET",
+				Header: map[string]string{"Accept": "application/json"},
+			},
+			Expect: atest.Response{StatusCode: 200},
+		}, {
+			Name:  "create-user",
+			Group: "admin",
+			Request: atest.Request{
+				API:    "http://localhost:8080/api/users",
+				Method: "POST",
+				Header: map[string]string{"Authorization": "Bearer {{token}}"},
+				Body:   `{"name": "linuxsuren"}`,
+			},
+		}, {
+			Name:  "login",
+			Group: "admin",
+			Request: atest.Request{
+				API:    "http://localhost:8080/api/login",
+				Method: "POST",
+				Form:   map[string]string{"username": "admin"},
+			},
+		}},
+	}, suite)
+
+	_, err = c.Import([]byte("fake"))
+	assert.NotNil(t, err)
+
+	suite, data := exportThenImport(t, c)
+	assert.True(t, c.(converter.Detector).Detect(data))
+	assert.Equal(t, []string{"users", "create-user", "login"}, caseNames(suite))
+	assert.Equal(t, "http://localhost:8080/api/users?page=1", suite.Items[0].Request.API)
+	assert.Equal(t, "admin", suite.Items[1].Group)
+	assert.Equal(t, `{"name": "linuxsuren"}`, suite.Items[1].Request.Body)
+	assert.Equal(t, map[string]string{"username": "admin"}, suite.Items[2].Request.Form)
+	assert.Equal(t, 201, suite.Items[1].Expect.StatusCode)
+	assert.Contains(t, string(data), `"raw": "{{baseUrl}}/users?page=1"`)
+	assert.Contains(t, string(data), `"value": "http://localhost:8080/api"`)
+	assert.Contains(t, string(data), `"    pm.response.to.have.status(201);"`)
+}
+
+func TestPostmanExport(t *testing.T) {
+	c := converter.NewPostmanConverter()
+	data, err := c.Export(&atest.TestSuite{
+		Name: "sample",
+		Auth: &atest.Auth{Basic: &atest.BasicAuth{Username: "admin", Password: "{{.param.password}}"}},
+		Items: []atest.TestCase{{
+			Name: "token",
+			Request: atest.Request{
+				API:  "http://localhost/token",
+				Auth: &atest.Auth{APIKey: &atest.APIKeyAuth{Name: "Authorization", Value: "Bearer {{.param.token}}"}},
+			},
+			Expect: atest.Response{
+				Header:       map[string]string{"Content-Type": "application/json"},
+				BodyContains: []string{`"token"`},
+			},
+		}, {
+			Name: "key",
+			Request: atest.Request{
+				API:  "/key",
+				Auth: &atest.Auth{APIKey: &atest.APIKeyAuth{Name: "key", Value: "value", In: "query"}},
+			},
+		}, {
+			Name: "oauth2",
+			Request: atest.Request{
+				API:  "/oauth2",
+				Auth: &atest.Auth{OAuth2: &atest.OAuth2{TokenURL: "http://localhost/token", ClientID: "id", Scopes: []string{"read", "write"}}},
+			},
+		}},
+	})
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "baseUrl")
+	assert.Contains(t, string(data), `"raw": "/key"`)
+	assert.Contains(t, string(data), `"    pm.expect(pm.response.text()).to.include(\"\\\"token\\\"\");"`)
+	assert.Contains(t, string(data), `"value": "read write"`)
+
+	suite, err := c.Import(data)
+	assert.Nil(t, err)
+	assert.Equal(t, &atest.Auth{Basic: &atest.BasicAuth{Username: "admin", Password: "{{.param.password}}"}}, suite.Auth)
+	assert.Equal(t, &atest.Auth{APIKey: &atest.APIKeyAuth{Name: "Authorization", Value: "Bearer {{.param.token}}"}}, suite.Items[0].Request.Auth)
+	assert.Equal(t, &atest.Auth{APIKey: &atest.APIKeyAuth{Name: "key", Value: "value", In: "query"}}, suite.Items[1].Request.Auth)
+	assert.Nil(t, suite.Items[2].Request.Auth)
+}
@@ -0,0 +1,9 @@
+# users
+curl 'http://localhost:8080/api/users?page=1' -H 'Accept: application/json' --compressed
+
+curl -X POST "http://localhost:8080/api/users" \
+  -H "Content-Type: application/json" \
+  -d "{\"name\": \"linuxsuren\"}"
+
+# login
+curl http://localhost:8080/login --data-urlencode 'username=admin' -u 'admin:secret'
@@ -0,0 +1,108 @@
+// Package exec runs the external commands of the prepare and clean steps
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"sort"
+	"time"
+)
+
+// Command represents an external command
+type Command struct {
+	Name    string
+	Args    []string
+	Env     map[string]string
+	Dir     string
+	Timeout time.Duration
+	// Stderr receives the stderr of the command, the stderr is written into the output if it's nil
+	Stderr io.Writer
+}
+
+// Executor runs the external commands
+type Executor interface {
+	// Run writes the stdout and stderr of the command into the output, and returns the exit code of it.
+	// The error is not nil only if the command cannot start or it's timeout.
+	Run(ctx context.Context, command Command, output io.Writer) (exitCode int, err error)
+}
+
+type defaultExecutor struct{}
+
+// NewExecutor creates an executor which runs the commands in the local processes
+func NewExecutor() Executor {
+	return &defaultExecutor{}
+}
+
+// Run runs the command, the environment variables are inherited from the current process
+func (e *defaultExecutor) Run(ctx context.Context, command Command, output io.Writer) (exitCode int, err error) {
+	if command.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, command.Timeout)
+		defer cancel()
+	}
+
+	cmd := osexec.CommandContext(ctx, command.Name, command.Args...)
+	cmd.Dir = command.Dir
+	cmd.Env = append(os.Environ(), envList(command.Env)...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if command.Stderr != nil {
+		cmd.Stderr = command.Stderr
+	}
+
+	if err = cmd.Run(); err != nil {
+		var exitErr *osexec.ExitError
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// the deadline might come from the parent context, the duration is unknown then
+			if command.Timeout > 0 {
+				err = fmt.Errorf("command '%s' is timeout after %v", command.Name, command.Timeout)
+			} else {
+				err = fmt.Errorf("command '%s' is timeout", command.Name)
+			}
+		} else if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+			err = nil
+		}
+	}
+	return
+}
+
+func envList(env map[string]string) (result []string) {
+	for key, val := range env {
+		result = append(result, fmt.Sprintf("%s=%s", key, val))
+	}
+	sort.Strings(result)
+	return
+}
+
+// FakeExecutor records the commands instead of running them, it's useful for the unit tests
+type FakeExecutor struct {
+	ExitCode int
+	Output   string
+	// OutputFunc returns the output of each command, the Output is used if it's nil
+	OutputFunc func(Command) string
+	// Stderr is written into the stderr of the command, or the output if the command has no stderr
+	Stderr   string
+	Err      error
+	Commands []Command
+}
+
+// Run records the command, then returns the expected exit code and output
+func (e *FakeExecutor) Run(ctx context.Context, command Command, output io.Writer) (exitCode int, err error) {
+	e.Commands = append(e.Commands, command)
+	if e.OutputFunc != nil {
+		_, _ = io.WriteString(output, e.OutputFunc(command))
+	} else {
+		_, _ = io.WriteString(output, e.Output)
+	}
+	if command.Stderr != nil {
+		_, _ = io.WriteString(command.Stderr, e.Stderr)
+	} else {
+		_, _ = io.WriteString(output, e.Stderr)
+	}
+	return e.ExitCode, e.Err
+}
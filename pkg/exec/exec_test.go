@@ -0,0 +1,111 @@
+package exec_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor(t *testing.T) {
+	executor := exec.NewExecutor()
+
+	t.Run("normal", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, "name"), []byte("world"), 0644))
+		buf := new(bytes.Buffer)
+		exitCode, err := executor.Run(context.TODO(), exec.Command{
+			Name: "sh",
+			Args: []string{"-c", "echo $GREETING; cat name"},
+			Env:  map[string]string{"GREETING": "hello"},
+			Dir:  dir,
+		}, buf)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, exitCode)
+		assert.Equal(t, "hello\nworld", buf.String())
+	})
+
+	t.Run("exit code", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		exitCode, err := executor.Run(context.TODO(), exec.Command{
+			Name: "sh",
+			Args: []string{"-c", "echo failed >&2; exit 3"},
+		}, buf)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, exitCode)
+		assert.Equal(t, "failed\n", buf.String())
+	})
+
+	t.Run("separate stderr", func(t *testing.T) {
+		buf, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		exitCode, err := executor.Run(context.TODO(), exec.Command{
+			Name:   "sh",
+			Args:   []string{"-c", "echo out; echo err >&2"},
+			Stderr: stderr,
+		}, buf)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, exitCode)
+		assert.Equal(t, "out\n", buf.String())
+		assert.Equal(t, "err\n", stderr.String())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := executor.Run(context.TODO(), exec.Command{
+			Name:    "sleep",
+			Args:    []string{"3"},
+			Timeout: 100 * time.Millisecond,
+		}, new(bytes.Buffer))
+		assert.EqualError(t, err, "command 'sleep' is timeout after 100ms")
+	})
+
+	t.Run("timeout of the parent context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+		_, err := executor.Run(ctx, exec.Command{
+			Name: "sleep",
+			Args: []string{"3"},
+		}, new(bytes.Buffer))
+		assert.EqualError(t, err, "command 'sleep' is timeout")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := executor.Run(context.TODO(), exec.Command{Name: "atest-fake-command"}, new(bytes.Buffer))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestFakeExecutor(t *testing.T) {
+	executor := &exec.FakeExecutor{ExitCode: 1, Output: "output", Err: errors.New("fake")}
+	buf := new(bytes.Buffer)
+	exitCode, err := executor.Run(context.TODO(), exec.Command{Name: "foo"}, buf)
+	assert.Equal(t, 1, exitCode)
+	assert.NotNil(t, err)
+	assert.Equal(t, "output", buf.String())
+	assert.Equal(t, []exec.Command{{Name: "foo"}}, executor.Commands)
+}
+
+func TestFakeExecutorStderr(t *testing.T) {
+	executor := &exec.FakeExecutor{
+		OutputFunc: func(command exec.Command) string {
+			return command.Name
+		},
+		Stderr: "warning",
+	}
+
+	buf, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	_, err := executor.Run(context.TODO(), exec.Command{Name: "foo", Stderr: stderr}, buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", buf.String())
+	assert.Equal(t, "warning", stderr.String())
+
+	buf.Reset()
+	_, err = executor.Run(context.TODO(), exec.Command{Name: "bar"}, buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "barwarning", buf.String())
+}
@@ -0,0 +1,4 @@
+// Package extension provides a gRPC based extension mechanism.
+// An extension is an executable file which serves the Extension service on a unix socket,
+// it could provide the stores of test suites, the runners of other protocols, and the report writers.
+package extension
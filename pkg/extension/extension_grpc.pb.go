@@ -0,0 +1,321 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: pkg/extension/extension.proto
+
+package extension
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ExtensionClient is the client API for Extension service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExtensionClient interface {
+	GetInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Info, error)
+	ListSuites(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SuiteNames, error)
+	LoadSuite(ctx context.Context, in *Suite, opts ...grpc.CallOption) (*Suite, error)
+	SaveSuite(ctx context.Context, in *Suite, opts ...grpc.CallOption) (*Result, error)
+	DeleteSuite(ctx context.Context, in *Suite, opts ...grpc.CallOption) (*Result, error)
+	RunCase(ctx context.Context, in *Case, opts ...grpc.CallOption) (*Result, error)
+	WriteReport(ctx context.Context, in *Report, opts ...grpc.CallOption) (*Result, error)
+}
+
+type extensionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExtensionClient(cc grpc.ClientConnInterface) ExtensionClient {
+	return &extensionClient{cc}
+}
+
+func (c *extensionClient) GetInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Info, error) {
+	out := new(Info)
+	err := c.cc.Invoke(ctx, "/extension.Extension/GetInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionClient) ListSuites(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SuiteNames, error) {
+	out := new(SuiteNames)
+	err := c.cc.Invoke(ctx, "/extension.Extension/ListSuites", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionClient) LoadSuite(ctx context.Context, in *Suite, opts ...grpc.CallOption) (*Suite, error) {
+	out := new(Suite)
+	err := c.cc.Invoke(ctx, "/extension.Extension/LoadSuite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionClient) SaveSuite(ctx context.Context, in *Suite, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, "/extension.Extension/SaveSuite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionClient) DeleteSuite(ctx context.Context, in *Suite, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, "/extension.Extension/DeleteSuite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionClient) RunCase(ctx context.Context, in *Case, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, "/extension.Extension/RunCase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionClient) WriteReport(ctx context.Context, in *Report, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, "/extension.Extension/WriteReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExtensionServer is the server API for Extension service.
+// All implementations must embed UnimplementedExtensionServer
+// for forward compatibility
+type ExtensionServer interface {
+	GetInfo(context.Context, *Empty) (*Info, error)
+	ListSuites(context.Context, *Empty) (*SuiteNames, error)
+	LoadSuite(context.Context, *Suite) (*Suite, error)
+	SaveSuite(context.Context, *Suite) (*Result, error)
+	DeleteSuite(context.Context, *Suite) (*Result, error)
+	RunCase(context.Context, *Case) (*Result, error)
+	WriteReport(context.Context, *Report) (*Result, error)
+	mustEmbedUnimplementedExtensionServer()
+}
+
+// UnimplementedExtensionServer must be embedded to have forward compatible implementations.
+type UnimplementedExtensionServer struct {
+}
+
+func (UnimplementedExtensionServer) GetInfo(context.Context, *Empty) (*Info, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+}
+func (UnimplementedExtensionServer) ListSuites(context.Context, *Empty) (*SuiteNames, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSuites not implemented")
+}
+func (UnimplementedExtensionServer) LoadSuite(context.Context, *Suite) (*Suite, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadSuite not implemented")
+}
+func (UnimplementedExtensionServer) SaveSuite(context.Context, *Suite) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveSuite not implemented")
+}
+func (UnimplementedExtensionServer) DeleteSuite(context.Context, *Suite) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSuite not implemented")
+}
+func (UnimplementedExtensionServer) RunCase(context.Context, *Case) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunCase not implemented")
+}
+func (UnimplementedExtensionServer) WriteReport(context.Context, *Report) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteReport not implemented")
+}
+func (UnimplementedExtensionServer) mustEmbedUnimplementedExtensionServer() {}
+
+// UnsafeExtensionServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExtensionServer will
+// result in compilation errors.
+type UnsafeExtensionServer interface {
+	mustEmbedUnimplementedExtensionServer()
+}
+
+func RegisterExtensionServer(s grpc.ServiceRegistrar, srv ExtensionServer) {
+	s.RegisterService(&Extension_ServiceDesc, srv)
+}
+
+func _Extension_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/extension.Extension/GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServer).GetInfo(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Extension_ListSuites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServer).ListSuites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/extension.Extension/ListSuites",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServer).ListSuites(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Extension_LoadSuite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Suite)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServer).LoadSuite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/extension.Extension/LoadSuite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServer).LoadSuite(ctx, req.(*Suite))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Extension_SaveSuite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Suite)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServer).SaveSuite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/extension.Extension/SaveSuite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServer).SaveSuite(ctx, req.(*Suite))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Extension_DeleteSuite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Suite)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServer).DeleteSuite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/extension.Extension/DeleteSuite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServer).DeleteSuite(ctx, req.(*Suite))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Extension_RunCase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Case)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServer).RunCase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/extension.Extension/RunCase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServer).RunCase(ctx, req.(*Case))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Extension_WriteReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Report)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServer).WriteReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/extension.Extension/WriteReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServer).WriteReport(ctx, req.(*Report))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Extension_ServiceDesc is the grpc.ServiceDesc for Extension service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Extension_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "extension.Extension",
+	HandlerType: (*ExtensionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetInfo",
+			Handler:    _Extension_GetInfo_Handler,
+		},
+		{
+			MethodName: "ListSuites",
+			Handler:    _Extension_ListSuites_Handler,
+		},
+		{
+			MethodName: "LoadSuite",
+			Handler:    _Extension_LoadSuite_Handler,
+		},
+		{
+			MethodName: "SaveSuite",
+			Handler:    _Extension_SaveSuite_Handler,
+		},
+		{
+			MethodName: "DeleteSuite",
+			Handler:    _Extension_DeleteSuite_Handler,
+		},
+		{
+			MethodName: "RunCase",
+			Handler:    _Extension_RunCase_Handler,
+		},
+		{
+			MethodName: "WriteReport",
+			Handler:    _Extension_WriteReport_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/extension/extension.proto",
+}
@@ -0,0 +1,107 @@
+// Package extensiontest provides a fake extension for testing
+package extensiontest
+
+import (
+	context "context"
+	"errors"
+	"log"
+	"net"
+
+	"github.com/linuxsuren/api-testing/pkg/extension"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeExtension struct {
+	extension.UnimplementedExtensionServer
+	info   *extension.Info
+	suites map[string]string
+	report string
+}
+
+// NewFakeExtension creates a fake extension which keeps the test suites in memory
+func NewFakeExtension(info *extension.Info) extension.ExtensionServer {
+	return &fakeExtension{info: info, suites: map[string]string{}}
+}
+
+// GetInfo returns the info of the extension
+func (e *fakeExtension) GetInfo(ctx context.Context, in *extension.Empty) (*extension.Info, error) {
+	return e.info, nil
+}
+
+// ListSuites returns all the suite names
+func (e *fakeExtension) ListSuites(ctx context.Context, in *extension.Empty) (reply *extension.SuiteNames, err error) {
+	reply = &extension.SuiteNames{}
+	for name := range e.suites {
+		reply.Names = append(reply.Names, name)
+	}
+	return
+}
+
+// LoadSuite returns the suite
+func (e *fakeExtension) LoadSuite(ctx context.Context, in *extension.Suite) (reply *extension.Suite, err error) {
+	if data, ok := e.suites[in.Name]; ok {
+		reply = &extension.Suite{Name: in.Name, Data: data}
+	} else {
+		err = errors.New("not found")
+	}
+	return
+}
+
+// SaveSuite saves the suite
+func (e *fakeExtension) SaveSuite(ctx context.Context, in *extension.Suite) (*extension.Result, error) {
+	e.suites[in.Name] = in.Data
+	return &extension.Result{}, nil
+}
+
+// DeleteSuite deletes the suite
+func (e *fakeExtension) DeleteSuite(ctx context.Context, in *extension.Suite) (*extension.Result, error) {
+	if _, ok := e.suites[in.Name]; !ok {
+		return &extension.Result{Error: "not found"}, nil
+	}
+	delete(e.suites, in.Name)
+	return &extension.Result{}, nil
+}
+
+// RunCase returns the test case context as the output
+func (e *fakeExtension) RunCase(ctx context.Context, in *extension.Case) (*extension.Result, error) {
+	return &extension.Result{Output: in.Context}, nil
+}
+
+// WriteReport keeps the report in memory
+func (e *fakeExtension) WriteReport(ctx context.Context, in *extension.Report) (*extension.Result, error) {
+	e.report = in.Data
+	return &extension.Result{}, nil
+}
+
+// NewFakeClient creates a fake client
+func NewFakeClient(ctx context.Context, impl extension.ExtensionServer) (extension.ExtensionClient, func()) {
+	buffer := 101024 * 1024
+	lis := bufconn.Listen(buffer)
+
+	baseServer := grpc.NewServer()
+	extension.RegisterExtensionServer(baseServer, impl)
+	go func() {
+		if err := baseServer.Serve(lis); err != nil {
+			log.Printf("error serving server: %v", err)
+		}
+	}()
+
+	conn, err := grpc.DialContext(ctx, "",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Printf("error connecting to server: %v", err)
+	}
+
+	closer := func() {
+		err := lis.Close()
+		if err != nil {
+			log.Printf("error closing listener: %v", err)
+		}
+		baseServer.Stop()
+	}
+	return extension.NewExtensionClient(conn), closer
+}
@@ -0,0 +1,220 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/linuxsuren/api-testing/pkg/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// SocketEnv is the environment variable name of the unix socket which the extension should listen on
+	SocketEnv = "ATEST_EXTENSION_SOCKET"
+	// FilePrefix is the prefix of the extension executable files
+	FilePrefix = "atest-ext-"
+	// KindStore indicates the extension provides a store of test suites
+	KindStore = "store"
+	// KindRunner indicates the extension provides a runner of some protocols
+	KindRunner = "runner"
+	// KindWriter indicates the extension provides a report writer
+	KindWriter = "writer"
+)
+
+// Manager is responsible for discovering and holding the extensions
+type Manager interface {
+	// Discover starts all the extensions in the directories
+	Discover(dirs ...string) error
+	// Register adds an extension with the client
+	Register(client ExtensionClient) error
+	// GetStore returns the store which is provided by the named extension
+	GetStore(name string) (store.Store, bool)
+	// GetRunner returns the test case runner which supports the protocol
+	GetRunner(protocol string) (runner.TestCaseRunner, bool)
+	// GetReportWriter returns the report writer which is provided by the named extension
+	GetReportWriter(name string) (runner.ReportResultWriter, bool)
+	// Stop stops all the extension processes
+	Stop()
+}
+
+type extension struct {
+	info    *Info
+	client  ExtensionClient
+	conn    *grpc.ClientConn
+	process *exec.Cmd
+	socket  string
+}
+
+type defaultManager struct {
+	extensions []*extension
+	timeout    time.Duration
+	// started is the count of the started processes, it makes the sockets unique even if some extensions are skipped
+	started int
+}
+
+// NewManager creates an extension manager
+func NewManager() Manager {
+	return &defaultManager{timeout: 5 * time.Second}
+}
+
+// DefaultDir returns the default directory of the extensions
+func DefaultDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "atest", "extensions")
+}
+
+// Discover starts all the executable files which have the prefix atest-ext-.
+// The extensions which fail to start are skipped, the others keep working
+func (m *defaultManager) Discover(dirs ...string) (err error) {
+	for _, dir := range dirs {
+		var files []string
+		if files, err = filepath.Glob(filepath.Join(dir, FilePrefix+"*")); err != nil {
+			return
+		}
+
+		for _, file := range files {
+			if info, statErr := os.Stat(file); statErr != nil || info.IsDir() || info.Mode()&0111 == 0 {
+				continue
+			}
+
+			if startErr := m.start(file); startErr != nil {
+				log.Printf("failed to start extension %s, %v", file, startErr)
+			}
+		}
+	}
+	return
+}
+
+func (m *defaultManager) start(file string) (err error) {
+	socket := filepath.Join(os.TempDir(), fmt.Sprintf("%s%d-%d.sock", FilePrefix, os.Getpid(), m.started))
+	m.started++
+	_ = os.Remove(socket)
+
+	process := exec.Command(file)
+	process.Env = append(os.Environ(), fmt.Sprintf("%s=%s", SocketEnv, socket))
+	process.Stdout = os.Stdout
+	process.Stderr = os.Stderr
+	if err = process.Start(); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	var conn *grpc.ClientConn
+	if conn, err = grpc.DialContext(ctx, "unix://"+socket, grpc.WithBlock(),
+		grpc.WithTransportCredentials(insecure.NewCredentials())); err != nil {
+		_ = process.Process.Kill()
+		_ = process.Wait()
+		_ = os.Remove(socket)
+		return
+	}
+
+	ext := &extension{
+		client:  NewExtensionClient(conn),
+		conn:    conn,
+		process: process,
+		socket:  socket,
+	}
+	if ext.info, err = ext.client.GetInfo(ctx, &Empty{}); err == nil {
+		m.extensions = append(m.extensions, ext)
+	} else {
+		ext.stop()
+	}
+	return
+}
+
+// Register adds an extension which is already running
+func (m *defaultManager) Register(client ExtensionClient) (err error) {
+	ext := &extension{client: client}
+	if ext.info, err = client.GetInfo(context.Background(), &Empty{}); err == nil {
+		m.extensions = append(m.extensions, ext)
+	}
+	return
+}
+
+// GetStore returns the store by the extension name
+func (m *defaultManager) GetStore(name string) (s store.Store, ok bool) {
+	var ext *extension
+	if ext, ok = m.find(KindStore, func(info *Info) bool {
+		return info.Name == name
+	}); ok {
+		s = NewStore(ext.client)
+	}
+	return
+}
+
+// GetRunner returns the runner which supports the protocol
+func (m *defaultManager) GetRunner(protocol string) (r runner.TestCaseRunner, ok bool) {
+	var ext *extension
+	if ext, ok = m.find(KindRunner, func(info *Info) bool {
+		for _, item := range info.Protocols {
+			if strings.EqualFold(item, protocol) {
+				return true
+			}
+		}
+		return false
+	}); ok {
+		r = NewTestCaseRunner(ext.client)
+	}
+	return
+}
+
+// GetReportWriter returns the report writer by the extension name
+func (m *defaultManager) GetReportWriter(name string) (w runner.ReportResultWriter, ok bool) {
+	var ext *extension
+	if ext, ok = m.find(KindWriter, func(info *Info) bool {
+		return info.Name == name
+	}); ok {
+		w = NewReportResultWriter(ext.client)
+	}
+	return
+}
+
+// Stop stops all the extensions
+func (m *defaultManager) Stop() {
+	for _, ext := range m.extensions {
+		ext.stop()
+	}
+	m.extensions = nil
+}
+
+func (m *defaultManager) find(kind string, match func(*Info) bool) (ext *extension, ok bool) {
+	for _, item := range m.extensions {
+		if hasKind(item.info, kind) && match(item.info) {
+			ext, ok = item, true
+			break
+		}
+	}
+	return
+}
+
+func (e *extension) stop() {
+	if e.conn != nil {
+		_ = e.conn.Close()
+	}
+	if e.process != nil && e.process.Process != nil {
+		_ = e.process.Process.Kill()
+		_ = e.process.Wait()
+	}
+	if e.socket != "" {
+		_ = os.Remove(e.socket)
+	}
+}
+
+func hasKind(info *Info, kind string) bool {
+	for _, item := range info.Kinds {
+		if item == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package extension_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/extension"
+	"github.com/linuxsuren/api-testing/pkg/extension/extensiontest"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager(t *testing.T) {
+	client, closer := extensiontest.NewFakeClient(context.Background(), extensiontest.NewFakeExtension(&extension.Info{
+		Name:      "fake",
+		Kinds:     []string{extension.KindStore, extension.KindRunner, extension.KindWriter},
+		Protocols: []string{"mqtt"},
+	}))
+	defer closer()
+
+	manager := extension.NewManager()
+	defer manager.Stop()
+	err := manager.Register(client)
+	assert.Nil(t, err)
+
+	_, ok := manager.GetStore("fake-store")
+	assert.False(t, ok)
+	_, ok = manager.GetRunner("http")
+	assert.False(t, ok)
+	_, ok = manager.GetReportWriter("fake-writer")
+	assert.False(t, ok)
+
+	t.Run("store", func(t *testing.T) {
+		s, ok := manager.GetStore("fake")
+		assert.True(t, ok)
+
+		err := s.Save("a.yaml", []byte("name: a"))
+		assert.Nil(t, err)
+
+		names, err := s.List()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a.yaml"}, names)
+
+		data, err := s.Load("a.yaml")
+		assert.Nil(t, err)
+		assert.Equal(t, "name: a", string(data))
+
+		err = s.Delete("a.yaml")
+		assert.Nil(t, err)
+		err = s.Delete("a.yaml")
+		assert.NotNil(t, err)
+		_, err = s.Load("a.yaml")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("runner", func(t *testing.T) {
+		r, ok := manager.GetRunner("MQTT")
+		assert.True(t, ok)
+
+		reporter := runner.NewMemoryTestReporter()
+		r.WithTestReporter(reporter)
+		output, err := r.RunTestCase(&atest.TestCase{
+			Name: "fake",
+			Request: atest.Request{
+				API: "mqtt://localhost",
+			},
+		}, map[string]interface{}{"key": "value"}, context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"key": "value"}, output)
+		assert.Equal(t, 1, len(reporter.GetAllRecords()))
+	})
+
+	t.Run("writer", func(t *testing.T) {
+		w, ok := manager.GetReportWriter("fake")
+		assert.True(t, ok)
+		assert.NotNil(t, w.WithAPIConverage(nil))
+
+		err := w.Output([]runner.ReportResult{{API: "api"}})
+		assert.Nil(t, err)
+	})
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, extension.FilePrefix+"not-executable"), []byte("fake"), 0644)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(dir, extension.FilePrefix+"invalid"), []byte("fake"), 0755)
+	assert.Nil(t, err)
+
+	manager := extension.NewManager()
+	defer manager.Stop()
+
+	err = manager.Discover(filepath.Join(dir, "fake"))
+	assert.Nil(t, err)
+
+	// the invalid extension is skipped
+	err = manager.Discover(dir)
+	assert.Nil(t, err)
+	_, ok := manager.GetStore("invalid")
+	assert.False(t, ok)
+}
+
+func TestServe(t *testing.T) {
+	os.Setenv(extension.SocketEnv, "")
+	err := extension.Serve(extensiontest.NewFakeExtension(&extension.Info{}))
+	assert.NotNil(t, err)
+}
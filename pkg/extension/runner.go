@@ -0,0 +1,98 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+)
+
+type extensionRunner struct {
+	client       ExtensionClient
+	testReporter runner.TestReporter
+	writer       io.Writer
+	log          runner.LevelWriter
+}
+
+// NewTestCaseRunner creates a test case runner which delegates to the extension
+func NewTestCaseRunner(client ExtensionClient) runner.TestCaseRunner {
+	r := &extensionRunner{client: client}
+	return r.WithOutputWriter(io.Discard).
+		WithWriteLevel("info").
+		WithTestReporter(runner.NewDiscardTestReporter())
+}
+
+// RunTestCase sends the test case to the extension
+func (r *extensionRunner) RunTestCase(testcase *testing.TestCase, dataContext interface{}, ctx context.Context) (output interface{}, err error) {
+	r.log.Info("start to run: '%s' via extension\n", testcase.Name)
+	record := runner.NewReportRecord()
+	defer func(rr *runner.ReportRecord) {
+		rr.EndTime = time.Now()
+		rr.Error = err
+		rr.Name = testcase.Name
+		rr.API = testcase.Request.API
+		rr.Method = testcase.Request.Method
+		rr.WarmUp = runner.IsWarmUp(ctx)
+		r.testReporter.PutRecord(rr)
+	}(record)
+
+	var caseData, contextData []byte
+	if caseData, err = yaml.Marshal(testcase); err != nil {
+		return
+	}
+	if contextData, err = json.Marshal(dataContext); err != nil {
+		return
+	}
+
+	task := &Case{
+		Data:       string(caseData),
+		Context:    string(contextData),
+		ContextDir: runner.NewContextKeyBuilder().ParentDir().GetContextValueOrEmpty(ctx),
+	}
+
+	requestCtx, cancel := runner.RequestContext(ctx)
+	defer cancel()
+
+	var result *Result
+	result, err = r.client.RunCase(requestCtx, task)
+	if err = resultError(result, err); err != nil {
+		return
+	}
+
+	record.Body = result.Output
+	r.log.Debug("output: %s\n", result.Output)
+	if result.Output != "" {
+		err = json.Unmarshal([]byte(result.Output), &output)
+	}
+	return
+}
+
+// WithOutputWriter sets the io.Writer
+func (r *extensionRunner) WithOutputWriter(writer io.Writer) runner.TestCaseRunner {
+	r.writer = writer
+	return r
+}
+
+// WithWriteLevel sets the level writer
+func (r *extensionRunner) WithWriteLevel(level string) runner.TestCaseRunner {
+	if level != "" {
+		r.log = runner.NewLevelWriter(level, r.writer)
+	}
+	return r
+}
+
+// WithTestReporter sets the TestReporter
+func (r *extensionRunner) WithTestReporter(reporter runner.TestReporter) runner.TestCaseRunner {
+	r.testReporter = reporter
+	return r
+}
+
+// WithExecer does nothing due to the extension runs in another process
+func (r *extensionRunner) WithExecer(fakeruntime.Execer) runner.TestCaseRunner {
+	return r
+}
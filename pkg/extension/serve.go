@@ -0,0 +1,29 @@
+package extension
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+)
+
+// Serve serves the extension on the unix socket which is given by atest.
+// It's a helper function for the extension authors.
+func Serve(impl ExtensionServer) (err error) {
+	socket := os.Getenv(SocketEnv)
+	if socket == "" {
+		err = fmt.Errorf("the environment variable %s is required", SocketEnv)
+		return
+	}
+
+	var lis net.Listener
+	if lis, err = net.Listen("unix", socket); err != nil {
+		return
+	}
+
+	s := grpc.NewServer()
+	RegisterExtensionServer(s, impl)
+	err = s.Serve(lis)
+	return
+}
@@ -0,0 +1,52 @@
+package extension
+
+import (
+	"context"
+	"errors"
+
+	"github.com/linuxsuren/api-testing/pkg/store"
+)
+
+type extensionStore struct {
+	client ExtensionClient
+}
+
+// NewStore creates a store which delegates to the extension
+func NewStore(client ExtensionClient) store.Store {
+	return &extensionStore{client: client}
+}
+
+// List returns the names of the test suites
+func (s *extensionStore) List() (names []string, err error) {
+	var reply *SuiteNames
+	if reply, err = s.client.ListSuites(context.Background(), &Empty{}); err == nil {
+		names = reply.Names
+	}
+	return
+}
+
+// Load returns the content of the test suite
+func (s *extensionStore) Load(name string) (data []byte, err error) {
+	var reply *Suite
+	if reply, err = s.client.LoadSuite(context.Background(), &Suite{Name: name}); err == nil {
+		data = []byte(reply.Data)
+	}
+	return
+}
+
+// Save saves the test suite
+func (s *extensionStore) Save(name string, data []byte) error {
+	return resultError(s.client.SaveSuite(context.Background(), &Suite{Name: name, Data: string(data)}))
+}
+
+// Delete removes the test suite
+func (s *extensionStore) Delete(name string) error {
+	return resultError(s.client.DeleteSuite(context.Background(), &Suite{Name: name}))
+}
+
+func resultError(result *Result, err error) error {
+	if err == nil && result.Error != "" {
+		err = errors.New(result.Error)
+	}
+	return err
+}
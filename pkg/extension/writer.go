@@ -0,0 +1,41 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+)
+
+type extensionWriter struct {
+	client        ExtensionClient
+	apiConverage  apispec.APIConverage
+	resourceUsage *runner.ResourceUsage
+}
+
+// NewReportResultWriter creates a report writer which delegates to the extension
+func NewReportResultWriter(client ExtensionClient) runner.ReportResultWriter {
+	return &extensionWriter{client: client}
+}
+
+// Output sends the JSON format report results to the extension
+func (w *extensionWriter) Output(results []runner.ReportResult) (err error) {
+	var data []byte
+	if data, err = json.Marshal(results); err == nil {
+		err = resultError(w.client.WriteReport(context.Background(), &Report{Data: string(data)}))
+	}
+	return
+}
+
+// WithAPIConverage sets the api coverage
+func (w *extensionWriter) WithAPIConverage(apiConverage apispec.APIConverage) runner.ReportResultWriter {
+	w.apiConverage = apiConverage
+	return w
+}
+
+// WithResourceUsage sets the resource usage of the runner
+func (w *extensionWriter) WithResourceUsage(usage *runner.ResourceUsage) runner.ReportResultWriter {
+	w.resourceUsage = usage
+	return w
+}
@@ -0,0 +1,2 @@
+// Package fuzz provides the mutators of the request bodies, and the inputs of the OpenAPI operations
+package fuzz
@@ -0,0 +1,198 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Mutation is the kind of a mutation
+type Mutation string
+
+const (
+	// MutationTypeFlip changes the type of a field, such as from a string to a number
+	MutationTypeFlip Mutation = "typeFlip"
+	// MutationOversized replaces a field with an oversized string
+	MutationOversized Mutation = "oversized"
+	// MutationInjection replaces a field with an injection string, such as the SQL and the script injections
+	MutationInjection Mutation = "injection"
+	// MutationInvalidUTF8 replaces a field with the invalid UTF-8 bytes
+	MutationInvalidUTF8 Mutation = "invalidUTF8"
+)
+
+// AllMutations are all the supported mutations
+var AllMutations = []Mutation{MutationTypeFlip, MutationOversized, MutationInjection, MutationInvalidUTF8}
+
+// OversizedLength is the length of the oversized strings
+var OversizedLength = 64 * 1024
+
+var injections = []string{
+	`' OR '1'='1`,
+	`"; DROP TABLE users; --`,
+	`<script>alert(1)</script>`,
+	`{{7*7}}`,
+	`${jndi:ldap://localhost/a}`,
+	`../../../../etc/passwd`,
+	`$(id)`,
+	`{"$gt": ""}`,
+}
+
+const (
+	invalidUTF8            = "\xff\xfe\xfd"
+	invalidUTF8Placeholder = "__atest_fuzz_invalid_utf8__"
+)
+
+// Mutator mutates the request bodies
+type Mutator interface {
+	// Mutate returns the mutated body, and the description of the mutation, such as: injection at $.user.name
+	Mutate(body string) (mutated, description string)
+}
+
+type randomMutator struct {
+	rand      *rand.Rand
+	mutations []Mutation
+}
+
+// NewMutator creates a mutator which picks the field and the mutation randomly, the same seed produces the same mutations.
+// All the mutations are used if it's empty.
+func NewMutator(seed int64, mutations ...Mutation) (mutator Mutator, err error) {
+	if len(mutations) == 0 {
+		mutations = AllMutations
+	}
+	for _, mutation := range mutations {
+		if !isSupported(mutation) {
+			err = fmt.Errorf("unsupported mutation '%s'", mutation)
+			return
+		}
+	}
+
+	mutator = &randomMutator{
+		rand:      rand.New(rand.NewSource(seed)),
+		mutations: mutations,
+	}
+	return
+}
+
+func isSupported(mutation Mutation) bool {
+	for _, item := range AllMutations {
+		if item == mutation {
+			return true
+		}
+	}
+	return false
+}
+
+// Mutate mutates a random field of the JSON body, or the whole body if it's not JSON
+func (m *randomMutator) Mutate(body string) (mutated, description string) {
+	mutation := m.mutations[m.rand.Intn(len(m.mutations))]
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil || body == "" {
+		return m.mutateText(body, mutation), fmt.Sprintf("%s of the body", mutation)
+	}
+
+	paths := collectPaths(data, "$", nil)
+	path := paths[m.rand.Intn(len(paths))]
+	data = replace(data, "$", path, func(val interface{}) interface{} {
+		return m.mutateValue(val, mutation)
+	})
+
+	result, _ := json.Marshal(data)
+	mutated = strings.ReplaceAll(string(result), invalidUTF8Placeholder, invalidUTF8)
+	description = fmt.Sprintf("%s at %s", mutation, path)
+	return
+}
+
+func (m *randomMutator) mutateText(body string, mutation Mutation) string {
+	switch mutation {
+	case MutationOversized:
+		return body + strings.Repeat("A", OversizedLength)
+	case MutationInjection:
+		return body + m.injection()
+	case MutationInvalidUTF8:
+		return body + invalidUTF8
+	default:
+		// flip the empty or plain text body to a JSON
+		if body == "" {
+			return "{}"
+		}
+		return "[]"
+	}
+}
+
+func (m *randomMutator) mutateValue(val interface{}, mutation Mutation) interface{} {
+	switch mutation {
+	case MutationOversized:
+		return strings.Repeat("A", OversizedLength)
+	case MutationInjection:
+		return m.injection()
+	case MutationInvalidUTF8:
+		return invalidUTF8Placeholder
+	default:
+		return flipType(val)
+	}
+}
+
+func (m *randomMutator) injection() string {
+	return injections[m.rand.Intn(len(injections))]
+}
+
+// flipType returns a value of another type
+func flipType(val interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		return 12345
+	case float64:
+		return fmt.Sprint(v)
+	case bool:
+		return fmt.Sprint(v)
+	case nil:
+		return map[string]interface{}{}
+	case map[string]interface{}:
+		return []interface{}{}
+	default:
+		return ""
+	}
+}
+
+// collectPaths returns the JSON paths of all the values, such as: $, $.user, $.user.tags[0]
+func collectPaths(data interface{}, path string, paths []string) []string {
+	paths = append(paths, path)
+	switch v := data.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			paths = collectPaths(v[key], path+"."+key, paths)
+		}
+	case []interface{}:
+		for i, item := range v {
+			paths = collectPaths(item, fmt.Sprintf("%s[%d]", path, i), paths)
+		}
+	}
+	return paths
+}
+
+// replace replaces the value of the target path
+func replace(data interface{}, path, target string, mutate func(interface{}) interface{}) interface{} {
+	if path == target {
+		return mutate(data)
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = replace(val, path+"."+key, target, mutate)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = replace(item, fmt.Sprintf("%s[%d]", path, i), target, mutate)
+		}
+	}
+	return data
+}
@@ -0,0 +1,93 @@
+package fuzz_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/linuxsuren/api-testing/pkg/fuzz"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMutator(t *testing.T) {
+	body := `{"name":"linuxsuren","age":18,"admin":false,"tags":["a"],"profile":null}`
+	tests := []struct {
+		name     string
+		mutation fuzz.Mutation
+		verify   func(t *testing.T, mutated, description string)
+	}{{
+		name:     "type flip",
+		mutation: fuzz.MutationTypeFlip,
+		verify: func(t *testing.T, mutated, description string) {
+			assert.True(t, strings.HasPrefix(description, "typeFlip at $"), description)
+			assert.True(t, json.Valid([]byte(mutated)))
+			assert.NotEqual(t, body, mutated)
+		},
+	}, {
+		name:     "oversized",
+		mutation: fuzz.MutationOversized,
+		verify: func(t *testing.T, mutated, description string) {
+			assert.True(t, strings.HasPrefix(description, "oversized at $"), description)
+			assert.Greater(t, len(mutated), fuzz.OversizedLength)
+		},
+	}, {
+		name:     "injection",
+		mutation: fuzz.MutationInjection,
+		verify: func(t *testing.T, mutated, description string) {
+			assert.True(t, strings.HasPrefix(description, "injection at $"), description)
+			assert.True(t, json.Valid([]byte(mutated)))
+		},
+	}, {
+		name:     "invalid UTF-8",
+		mutation: fuzz.MutationInvalidUTF8,
+		verify: func(t *testing.T, mutated, description string) {
+			assert.True(t, strings.HasPrefix(description, "invalidUTF8 at $"), description)
+			assert.False(t, utf8.ValidString(mutated))
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutator, err := fuzz.NewMutator(1, tt.mutation)
+			assert.Nil(t, err)
+			for i := 0; i < 10; i++ {
+				mutated, description := mutator.Mutate(body)
+				tt.verify(t, mutated, description)
+			}
+		})
+	}
+}
+
+func TestMutatorWithText(t *testing.T) {
+	mutator, err := fuzz.NewMutator(1, fuzz.MutationInvalidUTF8)
+	assert.Nil(t, err)
+	mutated, description := mutator.Mutate("plain")
+	assert.Equal(t, "invalidUTF8 of the body", description)
+	assert.True(t, strings.HasPrefix(mutated, "plain"))
+	assert.False(t, utf8.ValidString(mutated))
+
+	mutator, err = fuzz.NewMutator(1, fuzz.MutationTypeFlip)
+	assert.Nil(t, err)
+	mutated, _ = mutator.Mutate("")
+	assert.Equal(t, "{}", mutated)
+}
+
+func TestMutatorReproducible(t *testing.T) {
+	body := `{"user":{"name":"a","roles":["admin","dev"]}}`
+	first, err := fuzz.NewMutator(100)
+	assert.Nil(t, err)
+	second, err := fuzz.NewMutator(100)
+	assert.Nil(t, err)
+
+	for i := 0; i < 20; i++ {
+		mutated, description := first.Mutate(body)
+		expected, expectedDescription := second.Mutate(body)
+		assert.Equal(t, expected, mutated)
+		assert.Equal(t, expectedDescription, description)
+	}
+}
+
+func TestUnsupportedMutation(t *testing.T) {
+	_, err := fuzz.NewMutator(1, "fake")
+	assert.ErrorContains(t, err, "unsupported mutation 'fake'")
+}
@@ -0,0 +1,434 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+)
+
+// Input is a fuzzed request of an operation
+type Input struct {
+	// Description is the mutation, such as: oversized string of the query parameter name
+	Description string
+	Method      string
+	// Path is the path of the operation, the path parameters are filled and escaped
+	Path   string
+	Query  map[string]string
+	Header map[string]string
+	Body   string
+}
+
+// value is an invalid value of a schema, the parameter or the field is omitted if omit is true
+type value struct {
+	val         interface{}
+	description string
+	omit        bool
+}
+
+// maxDepth is the max depth of the fields of the body which are mutated
+const maxDepth = 3
+
+// NewInputs generates the inputs of the operation from the types of the parameters and the body.
+// Each input mutates one parameter or one field of the body with the boundary values, the invalid types,
+// or the oversized strings, the others are valid. The same operation always has the same inputs
+func NewInputs(operation apispec.Operation) (inputs []Input) {
+	// build creates the input with the valid values, except the target parameter
+	build := func(description string, target int, item value) Input {
+		input := Input{
+			Description: description,
+			Method:      operation.Method,
+			Path:        operation.Path,
+			Query:       map[string]string{},
+			Header:      map[string]string{},
+		}
+		for i, parameter := range operation.Parameters {
+			val := validValue(parameter.Schema)
+			if i == target {
+				if item.omit {
+					continue
+				}
+				val = item.val
+			}
+
+			text := formatParameter(val)
+			switch parameter.In {
+			case "header":
+				input.Header[parameter.Name] = text
+			case "path":
+				input.Path = strings.ReplaceAll(input.Path, "{"+parameter.Name+"}", url.PathEscape(text))
+			default:
+				input.Query[parameter.Name] = text
+			}
+		}
+		if operation.Body != nil {
+			input.Body = marshal(validValue(operation.Body))
+			input.Header["Content-Type"] = "application/json"
+		}
+		return input
+	}
+
+	for i, parameter := range operation.Parameters {
+		values := invalidValues(parameter.Schema)
+		if parameter.Required && parameter.In != "path" {
+			values = append(values, value{description: "missing", omit: true})
+		}
+		for _, item := range values {
+			if item.val == nil && !item.omit {
+				// there is no null of the parameters
+				continue
+			}
+			if parameter.In == "header" && item.val == invalidUTF8Placeholder {
+				// the HTTP client rejects the invalid header values
+				continue
+			}
+			description := fmt.Sprintf("%s of the %s parameter %s", item.description, parameter.In, parameter.Name)
+			if item.omit {
+				description = fmt.Sprintf("missing the required %s parameter %s", parameter.In, parameter.Name)
+			}
+			inputs = append(inputs, build(description, i, item))
+		}
+	}
+
+	if operation.Body != nil {
+		input := build("malformed JSON of the body", -1, value{})
+		input.Body = "{"
+		inputs = append(inputs, input)
+
+		walkBody(operation.Body, nil, 0, func(path []string, item value) {
+			input := build(fmt.Sprintf("%s at %s of the body", item.description, jsonPath(path)), -1, value{})
+			input.Body = marshal(setPath(validValue(operation.Body), path, item))
+			inputs = append(inputs, input)
+		})
+	}
+	return
+}
+
+// walkBody calls the visit with the invalid values of the body and its fields
+func walkBody(schema map[string]interface{}, path []string, depth int, visit func([]string, value)) {
+	for _, item := range invalidValues(schema) {
+		visit(path, item)
+	}
+	if depth >= maxDepth {
+		return
+	}
+
+	schema = mergeAllOf(schema)
+	switch schemaType(schema) {
+	case "object":
+		properties, _ := schema["properties"].(map[string]interface{})
+		required := map[string]bool{}
+		for _, name := range toStrings(schema["required"]) {
+			required[name] = true
+		}
+		for _, name := range sortedKeys(properties) {
+			property, _ := properties[name].(map[string]interface{})
+			fieldPath := append(append([]string{}, path...), name)
+			if required[name] {
+				visit(fieldPath, value{description: "missing the required field", omit: true})
+			}
+			walkBody(property, fieldPath, depth+1, visit)
+		}
+	case "array":
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			walkBody(items, append(append([]string{}, path...), "0"), depth+1, visit)
+		}
+	}
+}
+
+// invalidValues returns the boundary values and the invalid values of the schema
+func invalidValues(schema map[string]interface{}) (values []value) {
+	schema = mergeAllOf(schema)
+	if _, ok := schema["enum"]; ok {
+		values = append(values, value{val: "atest-not-in-enum", description: "out of the enum"})
+	}
+
+	switch schemaType(schema) {
+	case "integer", "number":
+		if min, ok := toFloat(schema["minimum"]); ok {
+			values = append(values, value{val: min - 1, description: "below the minimum"})
+		}
+		if max, ok := toFloat(schema["maximum"]); ok {
+			values = append(values, value{val: max + 1, description: "above the maximum"})
+		}
+		values = append(values,
+			value{val: 0, description: "zero"},
+			value{val: -1, description: "negative number"},
+			value{val: json.Number("99999999999999999999"), description: "overflowed number"})
+		if schemaType(schema) == "integer" {
+			values = append(values, value{val: 1.5, description: "fraction of the integer"})
+		}
+		values = append(values, value{val: "atest", description: "string instead of the number"})
+	case "string":
+		values = append(values, value{val: "", description: "empty string"})
+		if min, ok := toFloat(schema["minLength"]); ok && min > 1 {
+			values = append(values, value{val: strings.Repeat("a", int(min)-1), description: "shorter than the minLength"})
+		}
+		if max, ok := toFloat(schema["maxLength"]); ok {
+			values = append(values, value{val: strings.Repeat("a", int(max)+1), description: "longer than the maxLength"})
+		}
+		if format, _ := schema["format"].(string); format != "" {
+			values = append(values, value{val: "atest-invalid-" + format, description: "invalid " + format})
+		}
+		values = append(values, value{val: strings.Repeat("A", OversizedLength), description: "oversized string"})
+		for _, injection := range injections {
+			values = append(values, value{val: injection, description: fmt.Sprintf("injection %q", injection)})
+		}
+		values = append(values,
+			value{val: invalidUTF8Placeholder, description: "invalid UTF-8"},
+			value{val: 12345, description: "number instead of the string"})
+	case "boolean":
+		values = append(values, value{val: "atest", description: "string instead of the boolean"})
+	case "array":
+		if max, ok := toFloat(schema["maxItems"]); ok {
+			items, _ := schema["items"].(map[string]interface{})
+			array := make([]interface{}, int(max)+1)
+			for i := range array {
+				array[i] = validValue(items)
+			}
+			values = append(values, value{val: array, description: "more than the maxItems"})
+		}
+		values = append(values, value{val: "atest", description: "string instead of the array"})
+	case "object":
+		values = append(values,
+			value{val: []interface{}{}, description: "array instead of the object"},
+			value{val: "atest", description: "string instead of the object"})
+	}
+
+	if nullable, _ := schema["nullable"].(bool); !nullable && schemaType(schema) != "" {
+		values = append(values, value{description: "null"})
+	}
+	return
+}
+
+// validValue returns a value which matches the schema, the example and the default value take precedence
+func validValue(schema map[string]interface{}) interface{} {
+	for _, key := range []string{"example", "default", "const"} {
+		if val, ok := schema[key]; ok && val != nil {
+			return val
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if list, ok := schema[key].([]interface{}); ok && len(list) > 0 {
+			item, _ := list[0].(map[string]interface{})
+			return validValue(item)
+		}
+	}
+
+	schema = mergeAllOf(schema)
+	switch schemaType(schema) {
+	case "integer", "number":
+		val := float64(1)
+		if min, ok := toFloat(schema["minimum"]); ok {
+			val = min
+			if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive {
+				val++
+			}
+		} else if max, ok := toFloat(schema["maximum"]); ok && max < val {
+			val = max
+		}
+		return val
+	case "string":
+		return validString(schema)
+	case "boolean":
+		return true
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		count := 1
+		if min, ok := toFloat(schema["minItems"]); ok && int(min) > count {
+			count = int(min)
+		}
+		array := make([]interface{}, count)
+		for i := range array {
+			array[i] = validValue(items)
+		}
+		return array
+	case "object":
+		object := map[string]interface{}{}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range properties {
+			propertySchema, _ := property.(map[string]interface{})
+			object[name] = validValue(propertySchema)
+		}
+		return object
+	}
+	return "atest"
+}
+
+func validString(schema map[string]interface{}) (val string) {
+	format, _ := schema["format"].(string)
+	switch format {
+	case "date":
+		val = "2006-01-02"
+	case "date-time":
+		val = "2006-01-02T15:04:05Z"
+	case "uuid":
+		val = "00000000-0000-0000-0000-000000000000"
+	case "email":
+		val = "atest@example.com"
+	case "uri", "url":
+		val = "https://example.com"
+	case "ipv4":
+		val = "127.0.0.1"
+	default:
+		val = "atest"
+	}
+	if min, ok := toFloat(schema["minLength"]); ok && len(val) < int(min) {
+		val += strings.Repeat("a", int(min)-len(val))
+	}
+	if max, ok := toFloat(schema["maxLength"]); ok && len(val) > int(max) {
+		val = val[:int(max)]
+	}
+	return
+}
+
+// mergeAllOf merges the properties and the required fields of the allOf into the schema
+func mergeAllOf(schema map[string]interface{}) map[string]interface{} {
+	allOf, ok := schema["allOf"].([]interface{})
+	if !ok {
+		return schema
+	}
+
+	merged := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	var required []interface{}
+	for _, item := range append(allOf, schema) {
+		itemMap, _ := item.(map[string]interface{})
+		itemMap = mergeAllOf(itemMap)
+		for key, val := range itemMap {
+			switch key {
+			case "allOf":
+			case "properties":
+				props, _ := val.(map[string]interface{})
+				for name, property := range props {
+					properties[name] = property
+				}
+			case "required":
+				list, _ := val.([]interface{})
+				required = append(required, list...)
+			default:
+				merged[key] = val
+			}
+		}
+	}
+	if len(properties) > 0 {
+		merged["properties"] = properties
+	}
+	if len(required) > 0 {
+		merged["required"] = required
+	}
+	return merged
+}
+
+// schemaType returns the type of the schema, it's inferred from the keywords if it's not declared
+func schemaType(schema map[string]interface{}) string {
+	switch val := schema["type"].(type) {
+	case string:
+		return val
+	case []interface{}:
+		for _, item := range val {
+			if text, ok := item.(string); ok && text != "null" {
+				return text
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+// setPath replaces or removes the value of the path
+func setPath(data interface{}, path []string, item value) interface{} {
+	if len(path) == 0 {
+		return item.val
+	}
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 && item.omit {
+			delete(v, path[0])
+		} else if child, ok := v[path[0]]; ok || len(path) == 1 {
+			v[path[0]] = setPath(child, path[1:], item)
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			v[0] = setPath(v[0], path[1:], item)
+		}
+	}
+	return data
+}
+
+func jsonPath(path []string) string {
+	result := "$"
+	for _, segment := range path {
+		if segment == "0" {
+			result += "[0]"
+		} else {
+			result += "." + segment
+		}
+	}
+	return result
+}
+
+// marshal returns the JSON of the data, the injections are not escaped
+func marshal(data interface{}) string {
+	buf := new(strings.Builder)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(data)
+	return strings.ReplaceAll(strings.TrimSuffix(buf.String(), "\n"), invalidUTF8Placeholder, invalidUTF8)
+}
+
+func formatParameter(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return strings.ReplaceAll(v, invalidUTF8Placeholder, invalidUTF8)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, formatParameter(item))
+		}
+		return strings.Join(items, ",")
+	default:
+		return marshal(v)
+	}
+}
+
+func toFloat(val interface{}) (result float64, ok bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return
+}
+
+func toStrings(val interface{}) (result []string) {
+	list, _ := val.([]interface{})
+	for _, item := range list {
+		if text, ok := item.(string); ok {
+			result = append(result, text)
+		}
+	}
+	return
+}
+
+func sortedKeys(data map[string]interface{}) (keys []string) {
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
@@ -0,0 +1,94 @@
+package fuzz_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/linuxsuren/api-testing/pkg/fuzz"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInputs(t *testing.T) {
+	operation := apispec.Operation{
+		Method: "POST",
+		Path:   "/users/{id}",
+		Parameters: []apispec.Parameter{{
+			Name: "id", In: "path", Required: true,
+			Schema: map[string]interface{}{"type": "integer", "minimum": float64(1)},
+		}, {
+			Name: "limit", In: "query", Required: true,
+			Schema: map[string]interface{}{"type": "integer", "maximum": float64(100)},
+		}, {
+			Name: "X-Trace", In: "header",
+			Schema: map[string]interface{}{"type": "string", "format": "uuid"},
+		}},
+		Body: map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"name"},
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{"type": "string", "maxLength": float64(3)},
+				"tags": map[string]interface{}{
+					"type":  "array",
+					"items": map[string]interface{}{"type": "string", "enum": []interface{}{"a"}},
+				},
+			},
+		},
+	}
+
+	inputs := fuzz.NewInputs(operation)
+	assert.Equal(t, inputs, fuzz.NewInputs(operation), "the inputs are reproducible")
+
+	find := func(description string) (input fuzz.Input) {
+		for _, item := range inputs {
+			if item.Description == description {
+				return item
+			}
+		}
+		assert.Fail(t, "not found the input", description)
+		return
+	}
+
+	input := find("below the minimum of the path parameter id")
+	assert.Equal(t, "POST", input.Method)
+	assert.Equal(t, "/users/0", input.Path)
+	assert.Equal(t, map[string]string{"limit": "1"}, input.Query)
+	assert.Equal(t, map[string]string{
+		"Content-Type": "application/json",
+		"X-Trace":      "00000000-0000-0000-0000-000000000000",
+	}, input.Header)
+	assert.Equal(t, `{"name":"ate","tags":["a"]}`, input.Body)
+
+	assert.Equal(t, "/users/atest", find("string instead of the number of the path parameter id").Path)
+	assert.Equal(t, "101", find("above the maximum of the query parameter limit").Query["limit"])
+	assert.Equal(t, "99999999999999999999", find("overflowed number of the query parameter limit").Query["limit"])
+	assert.NotContains(t, find("missing the required query parameter limit").Query, "limit")
+	assert.Equal(t, "atest-invalid-uuid", find("invalid uuid of the header parameter X-Trace").Header["X-Trace"])
+	assert.Len(t, find("oversized string of the header parameter X-Trace").Header["X-Trace"], fuzz.OversizedLength)
+
+	assert.Equal(t, "{", find("malformed JSON of the body").Body)
+	assert.Equal(t, "null", find("null at $ of the body").Body)
+	assert.Equal(t, `{"tags":["a"]}`, find("missing the required field at $.name of the body").Body)
+	assert.Equal(t, `{"name":"aaaa","tags":["a"]}`, find("longer than the maxLength at $.name of the body").Body)
+	assert.Equal(t, `{"name":12345,"tags":["a"]}`, find("number instead of the string at $.name of the body").Body)
+	assert.Equal(t, `{"name":"<script>alert(1)</script>","tags":["a"]}`, find(`injection "<script>alert(1)</script>" at $.name of the body`).Body)
+	assert.Equal(t, `{"name":"ate","tags":["atest-not-in-enum"]}`, find("out of the enum at $.tags[0] of the body").Body)
+	assert.Equal(t, `{"name":"ate","tags":"atest"}`, find("string instead of the array at $.tags of the body").Body)
+
+	for _, item := range inputs {
+		assert.NotContains(t, item.Description, "invalid UTF-8 of the header", "the invalid header values are skipped")
+	}
+}
+
+func TestNewInputsWithoutBody(t *testing.T) {
+	inputs := fuzz.NewInputs(apispec.Operation{Method: "GET", Path: "/health"})
+	assert.Empty(t, inputs)
+
+	inputs = fuzz.NewInputs(apispec.Operation{Method: "GET", Path: "/users", Parameters: []apispec.Parameter{{
+		Name: "active", In: "query", Schema: map[string]interface{}{"type": "boolean", "example": false},
+	}}})
+	if assert.Equal(t, 1, len(inputs)) {
+		assert.Equal(t, "string instead of the boolean of the query parameter active", inputs[0].Description)
+		assert.Equal(t, "atest", inputs[0].Query["active"])
+		assert.Empty(t, inputs[0].Body)
+	}
+}
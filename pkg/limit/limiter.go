@@ -5,21 +5,28 @@ import (
 	"time"
 )
 
+// RateLimiter limits the rate of the requests
 type RateLimiter interface {
+	// TryAccept takes a token if there is one, it never blocks
 	TryAccept() bool
+	// Accept blocks until a token is taken
 	Accept()
 	Stop()
+	// Burst returns the count of the available tokens
 	Burst() int32
 }
 
+// defaultRateLimiter is a token bucket, the tokens are refilled at the QPS and
+// the bucket holds the burst tokens at most
 type defaultRateLimiter struct {
-	qps       int32
-	burst     int32
-	lastToken time.Time
-	singal    chan struct{}
-	mu        sync.Mutex
+	qps    int32
+	burst  int32
+	tokens float64
+	last   time.Time
+	mu     sync.Mutex
 }
 
+// NewDefaultRateLimiter creates a token bucket rate limiter, the bucket is full at the beginning
 func NewDefaultRateLimiter(qps, burst int32) RateLimiter {
 	if qps <= 0 {
 		qps = 5
@@ -27,69 +34,64 @@ func NewDefaultRateLimiter(qps, burst int32) RateLimiter {
 	if burst <= 0 {
 		burst = 5
 	}
-	limiter := &defaultRateLimiter{
+	return &defaultRateLimiter{
 		qps:    qps,
 		burst:  burst,
-		singal: make(chan struct{}, 1),
+		tokens: float64(burst),
+		last:   time.Now(),
 	}
-	go limiter.updateBurst()
-	return limiter
 }
 
 func (r *defaultRateLimiter) TryAccept() bool {
-	_, ok := r.resver()
+	_, ok := r.reserve(false)
 	return ok
 }
 
-func (r *defaultRateLimiter) resver() (delay time.Duration, ok bool) {
-	delay = time.Now().Sub(r.lastToken) / time.Millisecond
-	r.lastToken = time.Now()
-	if delay > 0 {
-		ok = true
-	} else if r.Burst() > 0 {
-		r.Setburst(r.Burst() - 1)
-		ok = true
-	} else {
-		delay = time.Second / time.Duration(r.qps)
+func (r *defaultRateLimiter) Accept() {
+	if delay, ok := r.reserve(true); !ok && delay > 0 {
+		time.Sleep(delay)
 	}
-	return
 }
 
-func (r *defaultRateLimiter) Accept() {
-	delay, ok := r.resver()
-	if ok {
+// reserve takes a token if there is one. Otherwise, it returns the delay until the next token,
+// and the token is taken in advance if wait is true
+func (r *defaultRateLimiter) reserve(wait bool) (delay time.Duration, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refill(time.Now())
+	if r.tokens >= 1 {
+		r.tokens--
+		ok = true
 		return
 	}
 
-	if delay > 0 {
-		time.Sleep(delay)
+	delay = time.Duration((1 - r.tokens) * float64(time.Second) / float64(r.qps))
+	if wait {
+		r.tokens--
 	}
 	return
 }
 
-func (r *defaultRateLimiter) Setburst(burst int32) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.burst = burst
+func (r *defaultRateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(r.last); elapsed > 0 {
+		r.tokens += elapsed.Seconds() * float64(r.qps)
+		if r.tokens > float64(r.burst) {
+			r.tokens = float64(r.burst)
+		}
+		r.last = now
+	}
 }
 
 func (r *defaultRateLimiter) Burst() int32 {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return r.burst
-}
-
-func (r *defaultRateLimiter) Stop() {
-	r.singal <- struct{}{}
-}
-
-func (r *defaultRateLimiter) updateBurst() {
-	for {
-		select {
-		case <-time.After(time.Second):
-			r.Setburst(r.Burst() + r.qps)
-		case <-r.singal:
-			return
-		}
+	r.refill(time.Now())
+	if r.tokens < 0 {
+		return 0
 	}
+	return int32(r.tokens)
 }
+
+// Stop does nothing since the tokens are refilled lazily
+func (r *defaultRateLimiter) Stop() {}
@@ -25,3 +25,23 @@ func TestXxx(t *testing.T) {
 	}
 	assert.True(t, num <= 10)
 }
+
+func TestTokenBucket(t *testing.T) {
+	limiter := NewDefaultRateLimiter(20, 2)
+	assert.Equal(t, int32(2), limiter.Burst())
+	assert.True(t, limiter.TryAccept())
+	assert.True(t, limiter.TryAccept())
+	assert.False(t, limiter.TryAccept())
+
+	begin := time.Now()
+	for i := 0; i < 4; i++ {
+		limiter.Accept()
+	}
+	// the four tokens are refilled at 20 QPS
+	assert.GreaterOrEqual(t, time.Since(begin), 150*time.Millisecond)
+	assert.Less(t, time.Since(begin), time.Second)
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(2), limiter.Burst(), "the bucket holds the burst tokens at most")
+	limiter.Stop()
+}
@@ -0,0 +1,202 @@
+package lock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+)
+
+const (
+	holderAnnotation  = "atest.linuxsuren.github.io/holder"
+	expiresAnnotation = "atest.linuxsuren.github.io/expires"
+)
+
+type configMapLocker struct {
+	config    *kubernetes.Config
+	client    *http.Client
+	namespace string
+	ttl       time.Duration
+	holder    string
+}
+
+// NewConfigMapLocker creates a locker which is based on the ConfigMaps of the namespace, it works for the processes
+// which share a Kubernetes cluster. The lock is the ConfigMap atest-lock-<name>, it will be taken over after the TTL.
+func NewConfigMapLocker(config *kubernetes.Config, namespace string, ttl time.Duration) (locker Locker, err error) {
+	if namespace == "" {
+		namespace = config.Namespace
+	}
+
+	var client *http.Client
+	if client, err = config.HTTPClient(); err == nil {
+		locker = &configMapLocker{
+			config:    config,
+			client:    client,
+			namespace: namespace,
+			ttl:       ttl,
+			holder:    newHolder(),
+		}
+	}
+	return
+}
+
+type lockConfigMap struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   lockMetadata      `json:"metadata"`
+	Data       map[string]string `json:"data,omitempty"`
+}
+
+type lockMetadata struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+}
+
+// Lock creates the ConfigMap, it's held by others if it exists
+func (l *configMapLocker) Lock(ctx context.Context, name string) (err error) {
+	if err = validateName(name); err != nil {
+		return
+	}
+
+	for {
+		cm := &lockConfigMap{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Metadata: lockMetadata{
+				Name:      l.configMapName(name),
+				Namespace: l.namespace,
+				Annotations: map[string]string{
+					holderAnnotation:  l.holder,
+					expiresAnnotation: time.Now().Add(l.ttl).UTC().Format(time.RFC3339),
+				},
+			},
+		}
+
+		var status int
+		if status, err = l.request(ctx, http.MethodPost, l.collectionAPI(), cm, nil); err == nil {
+			return
+		} else if status != http.StatusConflict {
+			return
+		}
+
+		if err = l.removeIfExpired(ctx, name); err != nil {
+			return
+		}
+
+		if err = wait(ctx, name); err != nil {
+			return
+		}
+	}
+}
+
+// Unlock deletes the ConfigMap if it's held by this locker
+func (l *configMapLocker) Unlock(name string) (err error) {
+	ctx := context.Background()
+	cm := &lockConfigMap{}
+	var status int
+	if status, err = l.request(ctx, http.MethodGet, l.resourceAPI(name), nil, cm); err != nil {
+		if status == http.StatusNotFound {
+			err = nil
+		}
+		return
+	}
+
+	if cm.Metadata.Annotations[holderAnnotation] == l.holder {
+		err = l.delete(ctx, name, cm.Metadata.ResourceVersion)
+	}
+	return
+}
+
+// removeIfExpired deletes the ConfigMap if it's expired, the resource version makes sure
+// that it's not taken over by others at the same time
+func (l *configMapLocker) removeIfExpired(ctx context.Context, name string) (err error) {
+	if l.ttl <= 0 {
+		return
+	}
+
+	cm := &lockConfigMap{}
+	var status int
+	if status, err = l.request(ctx, http.MethodGet, l.resourceAPI(name), nil, cm); err != nil {
+		if status == http.StatusNotFound {
+			err = nil
+		}
+		return
+	}
+
+	expires, parseErr := time.Parse(time.RFC3339, cm.Metadata.Annotations[expiresAnnotation])
+	if parseErr == nil && time.Now().After(expires) {
+		err = l.delete(ctx, name, cm.Metadata.ResourceVersion)
+	}
+	return
+}
+
+func (l *configMapLocker) delete(ctx context.Context, name, resourceVersion string) (err error) {
+	options := map[string]interface{}{
+		"preconditions": map[string]string{"resourceVersion": resourceVersion},
+	}
+
+	var status int
+	if status, err = l.request(ctx, http.MethodDelete, l.resourceAPI(name), options, nil); err != nil &&
+		(status == http.StatusNotFound || status == http.StatusConflict) {
+		err = nil
+	}
+	return
+}
+
+func (l *configMapLocker) configMapName(name string) string {
+	return "atest-lock-" + name
+}
+
+func (l *configMapLocker) collectionAPI() string {
+	return fmt.Sprintf("%s/api/v1/namespaces/%s/configmaps", strings.TrimSuffix(l.config.Host, "/"), l.namespace)
+}
+
+func (l *configMapLocker) resourceAPI(name string) string {
+	return fmt.Sprintf("%s/%s", l.collectionAPI(), l.configMapName(name))
+}
+
+func (l *configMapLocker) request(ctx context.Context, method, api string, payload, result interface{}) (status int, err error) {
+	var body io.Reader
+	if payload != nil {
+		var data []byte
+		if data, err = json.Marshal(payload); err != nil {
+			return
+		}
+		body = bytes.NewReader(data)
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, method, api, body); err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp *http.Response
+	if resp, err = l.client.Do(req); err != nil {
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	status = resp.StatusCode
+
+	var data []byte
+	if data, err = io.ReadAll(resp.Body); err != nil {
+		return
+	}
+
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		err = fmt.Errorf("unexpected status code %d from %s %s, %s", status, method, api, string(data))
+	} else if result != nil {
+		err = json.Unmarshal(data, result)
+	}
+	return
+}
@@ -0,0 +1,108 @@
+package lock_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/lock"
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	urlFoo         = "http://foo"
+	configMapsAPI  = "/api/v1/namespaces/demo/configmaps"
+	lockConfigMap  = configMapsAPI + "/atest-lock-env"
+	holderKey      = "atest.linuxsuren.github.io/holder"
+	expiresKey     = "atest.linuxsuren.github.io/expires"
+	defaultToken   = "Bearer token"
+	authorization  = "Authorization"
+	expiredLockFmt = `{"metadata":{"name":"atest-lock-env","resourceVersion":"1","annotations":{"%s":"other","%s":"2000-01-01T00:00:00Z"}}}`
+)
+
+func TestConfigMapLocker(t *testing.T) {
+	lock.RetryInterval = time.Millisecond
+	config := &kubernetes.Config{Config: &rest.Config{Host: urlFoo, BearerToken: "token"}, Namespace: "demo"}
+
+	t.Run("acquire and release", func(t *testing.T) {
+		defer gock.Off()
+		// the transport of the client is taken when creating the locker
+		gock.Intercept()
+		locker, err := lock.NewConfigMapLocker(config, "", time.Hour)
+		assert.Nil(t, err)
+
+		var holder string
+		gock.New(urlFoo).Post(configMapsAPI+"$").MatchHeader(authorization, defaultToken).
+			AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+				cm := struct {
+					Metadata struct {
+						Annotations map[string]string `json:"annotations"`
+					} `json:"metadata"`
+				}{}
+				err := json.NewDecoder(req.Body).Decode(&cm)
+				holder = cm.Metadata.Annotations[holderKey]
+				return err == nil, err
+			}).Reply(http.StatusCreated).BodyString(`{}`)
+		assert.Nil(t, locker.Lock(context.TODO(), "env"))
+
+		gock.New(urlFoo).Get(lockConfigMap).Reply(http.StatusOK).
+			JSON(map[string]interface{}{"metadata": map[string]interface{}{
+				"resourceVersion": "2", "annotations": map[string]string{holderKey: holder}}})
+		gock.New(urlFoo).Delete(lockConfigMap).Reply(http.StatusOK).BodyString(`{}`)
+		assert.Nil(t, locker.Unlock("env"))
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("take over the expired lock", func(t *testing.T) {
+		defer gock.Off()
+		gock.Intercept()
+		locker, err := lock.NewConfigMapLocker(config, "demo", time.Hour)
+		assert.Nil(t, err)
+
+		gock.New(urlFoo).Post(configMapsAPI + "$").Reply(http.StatusConflict).BodyString(`{}`)
+		gock.New(urlFoo).Get(lockConfigMap).Reply(http.StatusOK).
+			BodyString(fmt.Sprintf(expiredLockFmt, holderKey, expiresKey))
+		gock.New(urlFoo).Delete(lockConfigMap).Reply(http.StatusOK).BodyString(`{}`)
+		gock.New(urlFoo).Post(configMapsAPI + "$").Reply(http.StatusCreated).BodyString(`{}`)
+		assert.Nil(t, locker.Lock(context.TODO(), "env"))
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("held by others", func(t *testing.T) {
+		defer gock.Off()
+		gock.Intercept()
+		locker, err := lock.NewConfigMapLocker(config, "demo", time.Hour)
+		assert.Nil(t, err)
+
+		gock.New(urlFoo).Post(configMapsAPI + "$").Persist().Reply(http.StatusConflict).BodyString(`{}`)
+		gock.New(urlFoo).Get(lockConfigMap).Persist().Reply(http.StatusOK).
+			JSON(map[string]interface{}{"metadata": map[string]interface{}{"annotations": map[string]string{
+				holderKey: "other", expiresKey: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}}})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorContains(t, locker.Lock(ctx, "env"), "timeout waiting for the lock 'env'")
+
+		// not held by this locker
+		assert.Nil(t, locker.Unlock("env"))
+	})
+
+	t.Run("unexpected status", func(t *testing.T) {
+		defer gock.Off()
+		gock.Intercept()
+		locker, err := lock.NewConfigMapLocker(config, "demo", time.Hour)
+		assert.Nil(t, err)
+
+		gock.New(urlFoo).Post(configMapsAPI + "$").Reply(http.StatusForbidden).BodyString(`forbidden`)
+		assert.ErrorContains(t, locker.Lock(context.TODO(), "env"), "unexpected status code 403")
+
+		gock.New(urlFoo).Get(lockConfigMap).Reply(http.StatusNotFound).BodyString(`{}`)
+		assert.Nil(t, locker.Unlock("env"))
+	})
+}
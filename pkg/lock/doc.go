@@ -0,0 +1,2 @@
+// Package lock provides the named locks which are shared between the processes
+package lock
@@ -0,0 +1,75 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type fileLocker struct {
+	dir    string
+	ttl    time.Duration
+	holder string
+}
+
+// NewFileLocker creates a locker which is based on the files of the directory, it works for the processes
+// of the same host. A lock which is older than the TTL is considered as stale, it will be taken over.
+func NewFileLocker(dir string, ttl time.Duration) Locker {
+	return &fileLocker{
+		dir:    dir,
+		ttl:    ttl,
+		holder: newHolder(),
+	}
+}
+
+// Lock creates the lock file exclusively
+func (l *fileLocker) Lock(ctx context.Context, name string) (err error) {
+	if err = validateName(name); err != nil {
+		return
+	}
+	if err = os.MkdirAll(l.dir, 0755); err != nil {
+		return
+	}
+
+	file := l.file(name)
+	for {
+		var f *os.File
+		if f, err = os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644); err == nil {
+			if _, err = f.WriteString(l.holder); err != nil {
+				_ = f.Close()
+				_ = os.Remove(file)
+				return
+			}
+			return f.Close()
+		} else if !errors.Is(err, fs.ErrExist) {
+			return
+		}
+
+		if info, statErr := os.Stat(file); statErr == nil && l.ttl > 0 && time.Since(info.ModTime()) > l.ttl {
+			_ = os.Remove(file)
+			continue
+		}
+
+		if err = wait(ctx, name); err != nil {
+			return
+		}
+	}
+}
+
+// Unlock removes the lock file if it's held by this locker
+func (l *fileLocker) Unlock(name string) (err error) {
+	var data []byte
+	if data, err = os.ReadFile(l.file(name)); err == nil && string(data) == l.holder {
+		err = os.Remove(l.file(name))
+	} else if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	return
+}
+
+func (l *fileLocker) file(name string) string {
+	return filepath.Join(l.dir, name+".lock")
+}
@@ -0,0 +1,65 @@
+package lock_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/lock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileLocker(t *testing.T) {
+	lock.RetryInterval = time.Millisecond
+	dir := t.TempDir()
+	locker := lock.NewFileLocker(dir, time.Hour)
+	other := lock.NewFileLocker(dir, time.Hour)
+
+	assert.Nil(t, locker.Lock(context.TODO(), "env"))
+	assert.FileExists(t, filepath.Join(dir, "env.lock"))
+
+	t.Run("held by others", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorContains(t, other.Lock(ctx, "env"), "timeout waiting for the lock 'env'")
+	})
+
+	t.Run("unlock by others", func(t *testing.T) {
+		assert.Nil(t, other.Unlock("env"))
+		assert.FileExists(t, filepath.Join(dir, "env.lock"))
+	})
+
+	t.Run("acquired after unlock", func(t *testing.T) {
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			_ = locker.Unlock("env")
+		}()
+		assert.Nil(t, other.Lock(context.TODO(), "env"))
+		assert.Nil(t, other.Unlock("env"))
+		assert.NoFileExists(t, filepath.Join(dir, "env.lock"))
+	})
+
+	t.Run("unlock without lock", func(t *testing.T) {
+		assert.Nil(t, locker.Unlock("env"))
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		assert.ErrorContains(t, locker.Lock(context.TODO(), "../env"), "invalid lock name")
+	})
+}
+
+func TestFileLockerStale(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "env.lock")
+	assert.Nil(t, os.WriteFile(file, []byte("fake"), 0644))
+	past := time.Now().Add(-2 * time.Hour)
+	assert.Nil(t, os.Chtimes(file, past, past))
+
+	locker := lock.NewFileLocker(dir, time.Hour)
+	assert.Nil(t, locker.Lock(context.TODO(), "env"))
+	data, err := os.ReadFile(file)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "fake", string(data))
+}
@@ -0,0 +1,47 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/util"
+)
+
+// RetryInterval is the interval of trying to acquire a lock which is held by others
+var RetryInterval = time.Second
+
+// Locker is a named lock which is shared between the processes
+type Locker interface {
+	// Lock blocks until the lock is acquired or the context is done
+	Lock(ctx context.Context, name string) error
+	// Unlock releases the lock if it's held by this locker
+	Unlock(name string) error
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+func validateName(name string) (err error) {
+	if !validName.MatchString(name) {
+		err = fmt.Errorf("invalid lock name '%s', it should consist of lower case alphanumeric characters, '-' or '.'", name)
+	}
+	return
+}
+
+// newHolder returns a unique identity of the lock holder
+func newHolder() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), util.String(8))
+}
+
+// wait returns an error if the context is done before the retry interval
+func wait(ctx context.Context, name string) (err error) {
+	select {
+	case <-ctx.Done():
+		err = fmt.Errorf("timeout waiting for the lock '%s'", name)
+	case <-time.After(RetryInterval):
+	}
+	return
+}
@@ -0,0 +1,2 @@
+// Package mock provides a stub server which responds with the expectations of the test cases
+package mock
@@ -0,0 +1,249 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/render"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// Option is the behavior of the mock server
+type Option struct {
+	// Latency is the delay of each response
+	Latency time.Duration
+	// Jitter is the max random delay which is added to the latency
+	Jitter time.Duration
+	// FaultRate is the probability of responding with the fault status, from 0 to 1
+	FaultRate float64
+	// FaultStatus is the status code of the faults, 500 is the default one
+	FaultStatus int
+	// CORS allows the requests from all the origins
+	CORS bool
+}
+
+// Route is a mocked API
+type Route struct {
+	Name     string
+	Method   string
+	Path     string
+	Response testing.Response
+
+	segments []string
+}
+
+// Server is a mock server
+type Server interface {
+	http.Handler
+	// Routes returns all the routes
+	Routes() []Route
+}
+
+type server struct {
+	routes []Route
+	option Option
+	random func() float64
+	mu     sync.Mutex
+}
+
+// NewServer creates a mock server from the test suites, each test case becomes a route
+func NewServer(suites []*testing.TestSuite, option Option) Server {
+	s := &server{
+		option: option,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())).Float64,
+	}
+	if s.option.FaultStatus == 0 {
+		s.option.FaultStatus = http.StatusInternalServerError
+	}
+
+	for _, suite := range suites {
+		prefix := ""
+		if result, err := render.Render("base api", suite.API, map[string]interface{}{}); err == nil {
+			prefix = strings.TrimSuffix(getPath(result), "/")
+		}
+
+		for _, testCase := range suite.Items {
+			path := testCase.Request.API
+			if strings.HasPrefix(path, "/") {
+				path = prefix + path
+			}
+			path = getPath(path)
+			s.routes = append(s.routes, Route{
+				Name:     testCase.Name,
+				Method:   strings.ToUpper(testing.EmptyThenDefault(testCase.Request.Method, http.MethodGet)),
+				Path:     path,
+				Response: testCase.Expect,
+				segments: strings.Split(strings.Trim(path, "/"), "/"),
+			})
+		}
+	}
+
+	// the static routes have the priority
+	sort.SliceStable(s.routes, func(i, j int) bool {
+		return wildcards(s.routes[i].segments) < wildcards(s.routes[j].segments)
+	})
+	return s
+}
+
+// Routes returns the routes
+func (s *server) Routes() []Route {
+	return s.routes
+}
+
+// ServeHTTP responds with the expectation of the matched route
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.option.CORS {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "*")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	s.delay()
+
+	route := s.match(r)
+	switch {
+	case route == nil:
+		log.Printf("%s %s not found", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	case s.fault():
+		log.Printf("%s %s fault injected", r.Method, r.URL.Path)
+		w.WriteHeader(s.option.FaultStatus)
+		fmt.Fprintf(w, `{"message": "fault injected by the mock server"}`)
+	default:
+		log.Printf("%s %s matched '%s'", r.Method, r.URL.Path, route.Name)
+		writeResponse(w, &route.Response)
+	}
+}
+
+func (s *server) match(r *http.Request) *Route {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for i := range s.routes {
+		route := &s.routes[i]
+		if route.Method == r.Method && matchSegments(route.segments, segments) {
+			return route
+		}
+	}
+	return nil
+}
+
+func (s *server) delay() {
+	latency := s.option.Latency
+	if s.option.Jitter > 0 {
+		s.mu.Lock()
+		latency += time.Duration(s.random() * float64(s.option.Jitter))
+		s.mu.Unlock()
+	}
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+}
+
+func (s *server) fault() bool {
+	if s.option.FaultRate <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.random() < s.option.FaultRate
+}
+
+func writeResponse(w http.ResponseWriter, response *testing.Response) {
+	for key, val := range response.Header {
+		w.Header().Set(key, val)
+	}
+
+	body := response.Body
+	if body == "" && len(response.BodyFieldsExpect) > 0 {
+		if data, err := json.Marshal(expandFields(response.BodyFieldsExpect)); err == nil {
+			body = string(data)
+		}
+	}
+	if w.Header().Get("Content-Type") == "" && json.Valid([]byte(body)) {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	status := response.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	fmt.Fprint(w, body)
+}
+
+// expandFields converts the expected fields, such as: data.name, to a nested object
+func expandFields(fields map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, val := range fields {
+		current := result
+		keys := strings.Split(key, ".")
+		for _, item := range keys[:len(keys)-1] {
+			next, ok := current[item].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				current[item] = next
+			}
+			current = next
+		}
+		current[keys[len(keys)-1]] = val
+	}
+	return result
+}
+
+// getPath returns the path of the API, the template part of the host is ignored
+func getPath(api string) string {
+	if !strings.HasPrefix(api, "/") {
+		if index := strings.Index(api, "://"); index >= 0 {
+			api = api[index+3:]
+		}
+		if index := strings.Index(api, "/"); index >= 0 {
+			api = api[index:]
+		} else {
+			api = "/"
+		}
+	}
+	if u, err := url.Parse(api); err == nil && u.Path != "" {
+		return u.Path
+	}
+	if index := strings.Index(api, "?"); index >= 0 {
+		api = api[:index]
+	}
+	return api
+}
+
+// isWildcard returns true if the segment is a path parameter or a template, such as: {name}, {{.name}}
+func isWildcard(segment string) bool {
+	return strings.HasPrefix(segment, "{") || segment == "*"
+}
+
+func wildcards(segments []string) (count int) {
+	for _, segment := range segments {
+		if isWildcard(segment) {
+			count++
+		}
+	}
+	return
+}
+
+func matchSegments(route, request []string) bool {
+	if len(route) != len(request) {
+		return false
+	}
+	for i := range route {
+		if route[i] != request[i] && !isWildcard(route[i]) {
+			return false
+		}
+	}
+	return true
+}
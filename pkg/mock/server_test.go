@@ -0,0 +1,153 @@
+package mock_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/mock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	suites := []*atest.TestSuite{{
+		Name: "users",
+		API:  "http://localhost:8080/api/",
+		Items: []atest.TestCase{{
+			Name:    "user",
+			Request: atest.Request{API: "/users/{{.name}}"},
+			Expect: atest.Response{
+				BodyFieldsExpect: map[string]interface{}{"data.name": "linuxsuren"},
+			},
+		}, {
+			Name:    "admin",
+			Request: atest.Request{API: "/users/admin"},
+			Expect: atest.Response{
+				Body:   "admin",
+				Header: map[string]string{"Content-Type": "text/plain"},
+			},
+		}, {
+			Name:    "create",
+			Request: atest.Request{API: "/users", Method: "post"},
+			Expect:  atest.Response{StatusCode: http.StatusCreated, Body: `{}`},
+		}, {
+			Name:    "absolute",
+			Request: atest.Request{API: "{{env \"SERVER\"}}/health?verbose=true"},
+		}},
+	}}
+
+	server := mock.NewServer(suites, mock.Option{})
+	assert.Equal(t, []string{"admin", "create", "absolute", "user"}, routeNames(server.Routes()))
+	assert.Equal(t, "/api/users/{{.name}}", server.Routes()[3].Path)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectStatus int
+		expectBody   string
+		expectType   string
+	}{{
+		name:         "path parameter",
+		method:       http.MethodGet,
+		path:         "/api/users/linuxsuren",
+		expectStatus: http.StatusOK,
+		expectBody:   `{"data":{"name":"linuxsuren"}}`,
+		expectType:   "application/json",
+	}, {
+		name:         "static route first",
+		method:       http.MethodGet,
+		path:         "/api/users/admin",
+		expectStatus: http.StatusOK,
+		expectBody:   "admin",
+		expectType:   "text/plain",
+	}, {
+		name:         "method",
+		method:       http.MethodPost,
+		path:         "/api/users",
+		expectStatus: http.StatusCreated,
+		expectBody:   "{}",
+		expectType:   "application/json",
+	}, {
+		name:         "template host",
+		method:       http.MethodGet,
+		path:         "/health",
+		expectStatus: http.StatusOK,
+	}, {
+		name:         "not found",
+		method:       http.MethodGet,
+		path:         "/api/users",
+		expectStatus: http.StatusNotFound,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.expectStatus, recorder.Code)
+			if tt.expectStatus != http.StatusNotFound {
+				assert.Equal(t, tt.expectBody, recorder.Body.String())
+				assert.Equal(t, tt.expectType, recorder.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestServerOption(t *testing.T) {
+	suites := []*atest.TestSuite{{
+		Items: []atest.TestCase{{
+			Name:    "health",
+			Request: atest.Request{API: "http://foo/health"},
+		}},
+	}}
+
+	t.Run("fault and latency", func(t *testing.T) {
+		server := mock.NewServer(suites, mock.Option{
+			Latency:     10 * time.Millisecond,
+			Jitter:      time.Millisecond,
+			FaultRate:   1,
+			FaultStatus: http.StatusServiceUnavailable,
+		})
+
+		begin := time.Now()
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.GreaterOrEqual(t, time.Since(begin), 10*time.Millisecond)
+	})
+
+	t.Run("default fault status", func(t *testing.T) {
+		server := mock.NewServer(suites, mock.Option{FaultRate: 1})
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.Run("CORS", func(t *testing.T) {
+		server := httptest.NewServer(mock.NewServer(suites, mock.Option{CORS: true}))
+		defer server.Close()
+
+		request, _ := http.NewRequest(http.MethodOptions, server.URL+"/health", nil)
+		request.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		resp, err := http.DefaultClient.Do(request)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+
+		resp, err = http.Get(server.URL + "/health")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+		data, _ := io.ReadAll(resp.Body)
+		assert.Empty(t, data)
+	})
+}
+
+func routeNames(routes []mock.Route) (names []string) {
+	for _, route := range routes {
+		names = append(names, route.Name)
+	}
+	return
+}
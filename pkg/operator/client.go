@@ -0,0 +1,155 @@
+package operator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+)
+
+const serviceAccountToken = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// Client is a small client for the ATestSuite resources
+type Client interface {
+	ListSuites(namespace string) ([]ATestSuite, error)
+	UpdateStatus(suite *ATestSuite) error
+	CreateEvent(suite *ATestSuite, eventType, reason, message string) error
+}
+
+type defaultClient struct {
+	server string
+	token  string
+}
+
+// NewClient creates a client with the server address and token
+func NewClient(server, token string) Client {
+	return &defaultClient{
+		server: strings.TrimSuffix(server, "/"),
+		token:  token,
+	}
+}
+
+// NewClientFromEnv creates a client from the environment variables KUBERNETES_SERVER and KUBERNETES_TOKEN,
+// or from the in-cluster service account.
+func NewClientFromEnv() (client Client, err error) {
+	server := os.Getenv("KUBERNETES_SERVER")
+	token := os.Getenv("KUBERNETES_TOKEN")
+	if server == "" {
+		if host := os.Getenv("KUBERNETES_SERVICE_HOST"); host != "" {
+			server = fmt.Sprintf("https://%s:%s", host, os.Getenv("KUBERNETES_SERVICE_PORT"))
+		}
+	}
+	if token == "" {
+		var data []byte
+		if data, err = os.ReadFile(serviceAccountToken); err == nil {
+			token = strings.TrimSpace(string(data))
+		}
+	}
+
+	if server == "" || token == "" {
+		err = fmt.Errorf("KUBERNETES_SERVER and KUBERNETES_TOKEN are required when running out of the cluster")
+		return
+	}
+	client = NewClient(server, token)
+	return
+}
+
+// ListSuites lists the ATestSuite resources, list them in all namespaces if the namespace is empty
+func (c *defaultClient) ListSuites(namespace string) (suites []ATestSuite, err error) {
+	api := fmt.Sprintf("%s/apis/%s/%s/%s", c.server, Group, Version, Resource)
+	if namespace != "" {
+		api = fmt.Sprintf("%s/apis/%s/%s/namespaces/%s/%s", c.server, Group, Version, namespace, Resource)
+	}
+
+	list := &ATestSuiteList{}
+	if err = c.request(http.MethodGet, api, nil, list); err == nil {
+		suites = list.Items
+	}
+	return
+}
+
+// UpdateStatus updates the status subresource
+func (c *defaultClient) UpdateStatus(suite *ATestSuite) error {
+	api := fmt.Sprintf("%s/apis/%s/%s/namespaces/%s/%s/%s/status", c.server, Group, Version,
+		suite.Metadata.Namespace, Resource, suite.Metadata.Name)
+	patch := map[string]interface{}{"status": suite.Status}
+	return c.request(http.MethodPatch, api, patch, nil)
+}
+
+// CreateEvent creates a Kubernetes Event which is related to the ATestSuite
+func (c *defaultClient) CreateEvent(suite *ATestSuite, eventType, reason, message string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	event := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Event",
+		"metadata": map[string]string{
+			"generateName": suite.Metadata.Name + "-",
+			"namespace":    suite.Metadata.Namespace,
+		},
+		"involvedObject": map[string]string{
+			"apiVersion":      Group + "/" + Version,
+			"kind":            "ATestSuite",
+			"name":            suite.Metadata.Name,
+			"namespace":       suite.Metadata.Namespace,
+			"uid":             suite.Metadata.UID,
+			"resourceVersion": suite.Metadata.ResourceVersion,
+		},
+		"type":           eventType,
+		"reason":         reason,
+		"message":        message,
+		"firstTimestamp": now,
+		"lastTimestamp":  now,
+		"count":          1,
+		"source":         map[string]string{"component": "atest-operator"},
+	}
+	api := fmt.Sprintf("%s/api/v1/namespaces/%s/events", c.server, suite.Metadata.Namespace)
+	return c.request(http.MethodPost, api, event, nil)
+}
+
+func (c *defaultClient) request(method, api string, payload, result interface{}) (err error) {
+	var body io.Reader
+	if payload != nil {
+		var data []byte
+		if data, err = json.Marshal(payload); err != nil {
+			return
+		}
+		body = bytes.NewReader(data)
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequest(method, api, body); err != nil {
+		return
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	if method == http.MethodPatch {
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+	} else {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	var resp *http.Response
+	if resp, err = kubernetes.GetClient().Do(req); err != nil {
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var data []byte
+	if data, err = io.ReadAll(resp.Body); err != nil {
+		return
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		err = fmt.Errorf("unexpected status code %d from %s %s, %s", resp.StatusCode, method, api, string(data))
+	} else if result != nil {
+		err = json.Unmarshal(data, result)
+	}
+	return
+}
@@ -0,0 +1,64 @@
+package operator_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/operator"
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	gock.InterceptClient(kubernetes.GetClient())
+	defer gock.RestoreClient(http.DefaultClient)
+	defer gock.Off()
+
+	client := operator.NewClient(urlFoo+"/", "token")
+	suite := &operator.ATestSuite{
+		Metadata: operator.ObjectMeta{Name: "foo", Namespace: "ns"},
+	}
+
+	gock.New(urlFoo).Get("/apis/atest.linuxsuren.io/v1alpha1/atestsuites").
+		MatchHeader("Authorization", "Bearer token").
+		Reply(http.StatusOK).
+		JSON(`{"items":[{"metadata":{"name":"foo","namespace":"ns"},"spec":{"suite":"name: foo"}}]}`)
+	suites, err := client.ListSuites("")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(suites))
+	assert.Equal(t, "name: foo", suites[0].Spec.Suite)
+
+	gock.New(urlFoo).Get("/apis/atest.linuxsuren.io/v1alpha1/namespaces/ns/atestsuites").
+		Reply(http.StatusForbidden)
+	_, err = client.ListSuites("ns")
+	assert.NotNil(t, err)
+
+	gock.New(urlFoo).Patch("/apis/atest.linuxsuren.io/v1alpha1/namespaces/ns/atestsuites/foo/status").
+		Reply(http.StatusOK)
+	err = client.UpdateStatus(suite)
+	assert.Nil(t, err)
+
+	gock.New(urlFoo).Post("/api/v1/namespaces/ns/events").
+		Reply(http.StatusCreated)
+	err = client.CreateEvent(suite, "Normal", "Passed", "message")
+	assert.Nil(t, err)
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	os.Setenv("KUBERNETES_SERVER", "")
+	os.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, err := operator.NewClientFromEnv()
+	assert.NotNil(t, err)
+
+	os.Setenv("KUBERNETES_SERVER", urlFoo)
+	os.Setenv("KUBERNETES_TOKEN", "token")
+	defer os.Unsetenv("KUBERNETES_SERVER")
+	defer os.Unsetenv("KUBERNETES_TOKEN")
+	client, err := operator.NewClientFromEnv()
+	assert.Nil(t, err)
+	assert.NotNil(t, client)
+}
+
+const urlFoo = "http://foo"
@@ -0,0 +1,187 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// Controller reconciles the ATestSuite resources
+type Controller interface {
+	// Run reconciles the resources periodically until the context is done
+	Run(ctx context.Context) error
+	// Reconcile runs the test suites which are changed or scheduled
+	Reconcile(ctx context.Context) error
+	// Metrics returns the handler of the Prometheus metrics
+	Metrics() http.Handler
+}
+
+// SuiteRunner runs the test suite with the prepare and clean steps, the dependencies and the timeouts of it.
+// Only the test cases in scope run if the cases are not empty, the records of them are put into the reporter
+type SuiteRunner func(ctx context.Context, suite []byte, cases []string, reporter runner.TestReporter) error
+
+type defaultController struct {
+	client    Client
+	namespace string
+	resync    time.Duration
+	run       SuiteRunner
+	metrics   *metrics
+	now       func() time.Time
+}
+
+// NewController creates a controller, it watches all namespaces if the namespace is empty
+func NewController(client Client, namespace string, resync time.Duration, run SuiteRunner) Controller {
+	return &defaultController{
+		client:    client,
+		namespace: namespace,
+		resync:    resync,
+		run:       run,
+		metrics:   newMetrics(),
+		now:       time.Now,
+	}
+}
+
+// Run reconciles the resources periodically
+func (c *defaultController) Run(ctx context.Context) (err error) {
+	ticker := time.NewTicker(c.resync)
+	defer ticker.Stop()
+
+	for {
+		if reconcileErr := c.Reconcile(ctx); reconcileErr != nil {
+			log.Printf("failed to reconcile: %v", reconcileErr)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile runs the test suites which need to run
+func (c *defaultController) Reconcile(ctx context.Context) (err error) {
+	var suites []ATestSuite
+	if suites, err = c.client.ListSuites(c.namespace); err != nil {
+		return
+	}
+
+	for i := range suites {
+		suite := &suites[i]
+		if !c.shouldRun(suite) {
+			continue
+		}
+
+		begin := c.now()
+		c.runSuite(ctx, suite)
+		duration := c.now().Sub(begin)
+		suite.Status.LastRunTime = begin.UTC().Format(time.RFC3339)
+		suite.Status.Duration = duration.String()
+		suite.Status.ObservedGeneration = suite.Metadata.Generation
+		c.metrics.record(suite, duration)
+
+		if updateErr := c.client.UpdateStatus(suite); updateErr != nil {
+			log.Printf("failed to update the status of %s/%s: %v", suite.Metadata.Namespace, suite.Metadata.Name, updateErr)
+		}
+
+		eventType := "Normal"
+		if suite.Status.Phase != PhasePassed {
+			eventType = "Warning"
+		}
+		if eventErr := c.client.CreateEvent(suite, eventType, suite.Status.Phase, suite.Status.Message); eventErr != nil {
+			log.Printf("failed to create event for %s/%s: %v", suite.Metadata.Namespace, suite.Metadata.Name, eventErr)
+		}
+	}
+	return
+}
+
+// Metrics returns the handler of the metrics
+func (c *defaultController) Metrics() http.Handler {
+	return c.metrics
+}
+
+func (c *defaultController) shouldRun(suite *ATestSuite) bool {
+	if suite.Metadata.Generation != suite.Status.ObservedGeneration || suite.Status.LastRunTime == "" {
+		return true
+	}
+
+	if suite.Spec.Suspend || suite.Spec.Interval == "" {
+		return false
+	}
+
+	interval, err := time.ParseDuration(suite.Spec.Interval)
+	if err != nil || interval <= 0 {
+		return false
+	}
+
+	lastRunTime, err := time.Parse(time.RFC3339, suite.Status.LastRunTime)
+	if err != nil {
+		// it's regarded as the suite ran just now, a new generation of it runs anyway
+		log.Printf("invalid last run time of %s/%s: %v", suite.Metadata.Namespace, suite.Metadata.Name, err)
+		return false
+	}
+	return c.now().Sub(lastRunTime) >= interval
+}
+
+func (c *defaultController) runSuite(ctx context.Context, suite *ATestSuite) {
+	status := &suite.Status
+	status.Passed, status.Failed, status.Results = 0, 0, nil
+
+	data := []byte(suite.Spec.Suite)
+	if _, err := testing.Parse(data); err != nil {
+		status.Phase = PhaseInvalid
+		status.Message = err.Error()
+		return
+	}
+
+	reporter := runner.NewMemoryTestReporter()
+	err := c.run(ctx, data, suite.Spec.Cases, reporter)
+
+	// the record of a test case is the last one of its name, the records of the prepare and clean steps have no name
+	index := map[string]int{}
+	for _, record := range reporter.GetAllRecords() {
+		if record.Name == "" {
+			continue
+		}
+
+		result := CaseResult{
+			Name:     record.Name,
+			Passed:   record.Error == nil,
+			Duration: record.Duration().String(),
+		}
+		if record.Error != nil {
+			result.Message = record.Error.Error()
+		}
+		if i, ok := index[record.Name]; ok {
+			status.Results[i] = result
+		} else {
+			index[record.Name] = len(status.Results)
+			status.Results = append(status.Results, result)
+		}
+	}
+	for _, result := range status.Results {
+		if result.Passed {
+			status.Passed++
+		} else {
+			status.Failed++
+		}
+	}
+
+	switch {
+	case status.Failed > 0:
+		status.Phase = PhaseFailed
+		status.Message = fmt.Sprintf("%d of %d test cases failed", status.Failed, status.Passed+status.Failed)
+	case err != nil:
+		// such as the failed prepare steps
+		status.Phase = PhaseFailed
+		status.Message = err.Error()
+	default:
+		status.Phase = PhasePassed
+		status.Message = fmt.Sprintf("%d test cases passed", status.Passed)
+	}
+}
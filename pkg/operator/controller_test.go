@@ -0,0 +1,147 @@
+package operator_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/operator"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClient struct {
+	suites   []operator.ATestSuite
+	statuses map[string]operator.ATestSuiteStatus
+	events   []string
+}
+
+func (c *fakeClient) ListSuites(namespace string) ([]operator.ATestSuite, error) {
+	return c.suites, nil
+}
+
+func (c *fakeClient) UpdateStatus(suite *operator.ATestSuite) error {
+	c.statuses[suite.Metadata.Name] = suite.Status
+	return nil
+}
+
+func (c *fakeClient) CreateEvent(suite *operator.ATestSuite, eventType, reason, message string) error {
+	c.events = append(c.events, eventType+":"+reason)
+	return nil
+}
+
+func TestReconcile(t *testing.T) {
+	defer gock.Clean()
+	gock.New(urlFoo).Get("/bar").Reply(http.StatusOK).JSON(`{}`)
+
+	client := &fakeClient{
+		statuses: map[string]operator.ATestSuiteStatus{},
+		suites: []operator.ATestSuite{{
+			Metadata: operator.ObjectMeta{Name: "passed", Generation: 1},
+			Spec: operator.ATestSuiteSpec{
+				Suite: simpleSuite,
+			},
+		}, {
+			Metadata: operator.ObjectMeta{Name: "invalid", Generation: 1},
+			Spec: operator.ATestSuiteSpec{
+				Suite: "fake",
+			},
+		}, {
+			Metadata: operator.ObjectMeta{Name: "not-changed", Generation: 1},
+			Spec: operator.ATestSuiteSpec{
+				Suite: simpleSuite,
+			},
+			Status: operator.ATestSuiteStatus{
+				ObservedGeneration: 1,
+				LastRunTime:        time.Now().UTC().Format(time.RFC3339),
+			},
+		}, {
+			Metadata: operator.ObjectMeta{Name: "scheduled", Generation: 1},
+			Spec: operator.ATestSuiteSpec{
+				Suite:    simpleSuite,
+				Interval: "1m",
+				Cases:    []string{"fake"},
+			},
+			Status: operator.ATestSuiteStatus{
+				ObservedGeneration: 1,
+				LastRunTime:        time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+			},
+		}},
+	}
+
+	controller := operator.NewController(client, "", time.Minute, runSuite)
+	err := controller.Reconcile(context.Background())
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(client.statuses))
+	assert.Equal(t, operator.PhasePassed, client.statuses["passed"].Phase)
+	assert.Equal(t, 1, client.statuses["passed"].Passed)
+	assert.Equal(t, int64(1), client.statuses["passed"].ObservedGeneration)
+	assert.Equal(t, operator.PhaseInvalid, client.statuses["invalid"].Phase)
+	assert.Equal(t, operator.PhasePassed, client.statuses["scheduled"].Phase)
+	assert.Equal(t, 0, client.statuses["scheduled"].Passed)
+	assert.Equal(t, []string{"Normal:Passed", "Warning:Invalid", "Normal:Passed"}, client.events)
+
+	recorder := httptest.NewRecorder()
+	controller.Metrics().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, recorder.Body.String(), `atest_suite_runs_total{suite="/passed",result="passed"} 1`)
+	assert.Contains(t, recorder.Body.String(), `atest_suite_runs_total{suite="/invalid",result="failed"} 1`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = controller.Run(ctx)
+	assert.Nil(t, err)
+}
+
+func TestReconcileWithFailedSuite(t *testing.T) {
+	client := &fakeClient{
+		statuses: map[string]operator.ATestSuiteStatus{},
+		suites: []operator.ATestSuite{{
+			Metadata: operator.ObjectMeta{Name: "prepare", Generation: 1},
+			Spec:     operator.ATestSuiteSpec{Suite: simpleSuite},
+		}, {
+			Metadata: operator.ObjectMeta{Name: "invalid-last-run-time", Generation: 1},
+			Spec:     operator.ATestSuiteSpec{Suite: simpleSuite, Interval: "1m"},
+			Status:   operator.ATestSuiteStatus{ObservedGeneration: 1, LastRunTime: "fake"},
+		}},
+	}
+
+	controller := operator.NewController(client, "", time.Minute, func(context.Context, []byte, []string, runner.TestReporter) error {
+		return errors.New("failed to prepare")
+	})
+	assert.Nil(t, controller.Reconcile(context.Background()))
+	assert.Equal(t, 1, len(client.statuses))
+	assert.Equal(t, operator.PhaseFailed, client.statuses["prepare"].Phase)
+	assert.Equal(t, "failed to prepare", client.statuses["prepare"].Message)
+}
+
+// runSuite runs the test cases one by one, the operator command runs them via the run command
+func runSuite(ctx context.Context, data []byte, cases []string, reporter runner.TestReporter) (err error) {
+	var suite *atest.TestSuite
+	if suite, err = atest.Parse(data); err != nil {
+		return
+	}
+	for _, testCase := range suite.Items {
+		if !testCase.InScope(cases) {
+			continue
+		}
+		suite.Inherit(&testCase)
+		if _, err = runner.NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&testCase, nil, ctx); err != nil {
+			return
+		}
+	}
+	return
+}
+
+const simpleSuite = `name: simple
+api: http://foo
+items:
+- name: bar
+  request:
+    api: /bar
+`
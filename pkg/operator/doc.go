@@ -0,0 +1,2 @@
+// Package operator provides a Kubernetes controller which reconciles the ATestSuite custom resources
+package operator
@@ -0,0 +1,79 @@
+package operator
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type suiteMetric struct {
+	passedRuns   int
+	failedRuns   int
+	passedCases  int
+	failedCases  int
+	lastDuration time.Duration
+}
+
+// metrics holds the statistics of the test suites, and exposes them in the Prometheus text format
+type metrics struct {
+	suites map[string]*suiteMetric
+	mu     sync.Mutex
+}
+
+func newMetrics() *metrics {
+	return &metrics{suites: map[string]*suiteMetric{}}
+}
+
+func (m *metrics) record(suite *ATestSuite, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := suite.Metadata.Namespace + "/" + suite.Metadata.Name
+	item, ok := m.suites[key]
+	if !ok {
+		item = &suiteMetric{}
+		m.suites[key] = item
+	}
+
+	if suite.Status.Phase == PhasePassed {
+		item.passedRuns++
+	} else {
+		item.failedRuns++
+	}
+	item.passedCases += suite.Status.Passed
+	item.failedCases += suite.Status.Failed
+	item.lastDuration = duration
+}
+
+// ServeHTTP writes the metrics
+func (m *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var keys []string
+	for key := range m.suites {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintln(w, "# HELP atest_suite_runs_total The count of the test suite runs.")
+	fmt.Fprintln(w, "# TYPE atest_suite_runs_total counter")
+	for _, key := range keys {
+		fmt.Fprintf(w, "atest_suite_runs_total{suite=%q,result=\"passed\"} %d\n", key, m.suites[key].passedRuns)
+		fmt.Fprintf(w, "atest_suite_runs_total{suite=%q,result=\"failed\"} %d\n", key, m.suites[key].failedRuns)
+	}
+	fmt.Fprintln(w, "# HELP atest_case_runs_total The count of the test case runs.")
+	fmt.Fprintln(w, "# TYPE atest_case_runs_total counter")
+	for _, key := range keys {
+		fmt.Fprintf(w, "atest_case_runs_total{suite=%q,result=\"passed\"} %d\n", key, m.suites[key].passedCases)
+		fmt.Fprintf(w, "atest_case_runs_total{suite=%q,result=\"failed\"} %d\n", key, m.suites[key].failedCases)
+	}
+	fmt.Fprintln(w, "# HELP atest_suite_duration_seconds The duration of the last test suite run.")
+	fmt.Fprintln(w, "# TYPE atest_suite_duration_seconds gauge")
+	for _, key := range keys {
+		fmt.Fprintf(w, "atest_suite_duration_seconds{suite=%q} %g\n", key, m.suites[key].lastDuration.Seconds())
+	}
+}
@@ -0,0 +1,74 @@
+package operator
+
+const (
+	// Group is the API group of ATestSuite
+	Group = "atest.linuxsuren.io"
+	// Version is the API version of ATestSuite
+	Version = "v1alpha1"
+	// Resource is the plural name of ATestSuite
+	Resource = "atestsuites"
+)
+
+// ATestSuite represents a test suite in Kubernetes
+type ATestSuite struct {
+	APIVersion string           `json:"apiVersion,omitempty"`
+	Kind       string           `json:"kind,omitempty"`
+	Metadata   ObjectMeta       `json:"metadata"`
+	Spec       ATestSuiteSpec   `json:"spec"`
+	Status     ATestSuiteStatus `json:"status,omitempty"`
+}
+
+// ObjectMeta is the necessary part of the Kubernetes object metadata
+type ObjectMeta struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace,omitempty"`
+	UID             string `json:"uid,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	Generation      int64  `json:"generation,omitempty"`
+}
+
+// ATestSuiteSpec is the desired state of ATestSuite
+type ATestSuiteSpec struct {
+	// Suite is the YAML content of the test suite
+	Suite string `json:"suite"`
+	// Interval is the duration between two runs, such as: 10m. Only run on change if it's empty.
+	Interval string `json:"interval,omitempty"`
+	// Cases is the name list of the test cases to run, run all of them if it's empty
+	Cases []string `json:"cases,omitempty"`
+	// Suspend stops the scheduled runs
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// ATestSuiteStatus is the observed state of ATestSuite
+type ATestSuiteStatus struct {
+	Phase              string       `json:"phase,omitempty"`
+	ObservedGeneration int64        `json:"observedGeneration,omitempty"`
+	LastRunTime        string       `json:"lastRunTime,omitempty"`
+	Duration           string       `json:"duration,omitempty"`
+	Passed             int          `json:"passed"`
+	Failed             int          `json:"failed"`
+	Message            string       `json:"message,omitempty"`
+	Results            []CaseResult `json:"results,omitempty"`
+}
+
+// CaseResult is the result of a test case
+type CaseResult struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Duration string `json:"duration,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// ATestSuiteList is a list of ATestSuite
+type ATestSuiteList struct {
+	Items []ATestSuite `json:"items"`
+}
+
+const (
+	// PhasePassed indicates all the test cases are passed
+	PhasePassed = "Passed"
+	// PhaseFailed indicates some of the test cases are failed
+	PhaseFailed = "Failed"
+	// PhaseInvalid indicates the test suite is invalid
+	PhaseInvalid = "Invalid"
+)
@@ -0,0 +1,137 @@
+package render
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	// register the hash functions of the algorithms
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+var jwtHashes = map[string]crypto.Hash{
+	"256": crypto.SHA256,
+	"384": crypto.SHA384,
+	"512": crypto.SHA512,
+}
+
+// generateJWT signs the claims with HS256, HS384, HS512, RS256, RS384 or RS512. The key of HS* is the secret,
+// and the key of RS* is the PEM private key or the file of it. The claims is a map or a JSON object, the iat is
+// now if it's missing, and the exp and nbf could be the durations which are relative to now, such as: 1h
+func generateJWT(alg, key string, claims interface{}) (token string, err error) {
+	var payload map[string]interface{}
+	if payload, err = jwtClaims(claims, time.Now()); err != nil {
+		return
+	}
+
+	hash, ok := jwtHashes[strings.TrimLeft(alg, "HSR")]
+	if !ok || (!strings.HasPrefix(alg, "HS") && !strings.HasPrefix(alg, "RS")) {
+		err = fmt.Errorf("not supported JWT algorithm: %s", alg)
+		return
+	}
+
+	var header, body []byte
+	if header, err = json.Marshal(map[string]string{"alg": alg, "typ": "JWT"}); err != nil {
+		return
+	}
+	if body, err = json.Marshal(payload); err != nil {
+		return
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+
+	var signature []byte
+	if strings.HasPrefix(alg, "HS") {
+		mac := hmac.New(hash.New, []byte(key))
+		mac.Write([]byte(unsigned))
+		signature = mac.Sum(nil)
+	} else {
+		var privateKey *rsa.PrivateKey
+		if privateKey, err = parseRSAPrivateKey(key); err != nil {
+			return
+		}
+
+		digest := hash.New()
+		digest.Write([]byte(unsigned))
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, privateKey, hash, digest.Sum(nil)); err != nil {
+			return
+		}
+	}
+	token = unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+	return
+}
+
+func jwtClaims(claims interface{}, now time.Time) (payload map[string]interface{}, err error) {
+	switch val := claims.(type) {
+	case nil:
+		payload = map[string]interface{}{}
+	case map[string]interface{}:
+		payload = make(map[string]interface{}, len(val))
+		for key, item := range val {
+			payload[key] = item
+		}
+	case string:
+		if err = json.Unmarshal([]byte(val), &payload); err != nil {
+			err = fmt.Errorf("invalid JWT claims: %v", err)
+			return
+		}
+	default:
+		err = fmt.Errorf("invalid JWT claims: %v, it should be a map or a JSON object", claims)
+		return
+	}
+
+	if _, ok := payload["iat"]; !ok {
+		payload["iat"] = now.Unix()
+	}
+	for _, key := range []string{"exp", "nbf"} {
+		if duration, ok := payload[key].(string); ok {
+			var dur time.Duration
+			if dur, err = time.ParseDuration(duration); err != nil {
+				err = fmt.Errorf("invalid %s of the JWT claims: %v", key, err)
+				return
+			}
+			payload[key] = now.Add(dur).Unix()
+		}
+	}
+	return
+}
+
+// parseRSAPrivateKey supports the PKCS #1 and PKCS #8 keys, the key is read from the file if it's not a PEM
+func parseRSAPrivateKey(key string) (privateKey *rsa.PrivateKey, err error) {
+	data := []byte(key)
+	if !strings.Contains(key, "-----BEGIN") {
+		if data, err = os.ReadFile(key); err != nil {
+			return
+		}
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		err = fmt.Errorf("invalid PEM of the private key")
+		return
+	}
+
+	if privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return
+	}
+
+	var parsed interface{}
+	if parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		err = fmt.Errorf("invalid private key: %v", err)
+		return
+	}
+	var ok bool
+	if privateKey, ok = parsed.(*rsa.PrivateKey); !ok {
+		err = fmt.Errorf("the private key is not RSA")
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package render
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateJWT(t *testing.T) {
+	t.Run("HS256", func(t *testing.T) {
+		token, err := generateJWT("HS256", "secret", map[string]interface{}{"sub": "admin", "iat": 1, "exp": 2})
+		assert.Nil(t, err)
+
+		parts := strings.Split(token, ".")
+		if assert.Equal(t, 3, len(parts)) {
+			assert.Equal(t, `{"alg":"HS256","typ":"JWT"}`, decodeSegment(t, parts[0]))
+			assert.Equal(t, `{"exp":2,"iat":1,"sub":"admin"}`, decodeSegment(t, parts[1]))
+
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write([]byte(parts[0] + "." + parts[1]))
+			assert.Equal(t, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), parts[2])
+		}
+	})
+
+	t.Run("RS256", func(t *testing.T) {
+		token, err := generateJWT("RS256", "testdata/rsa.pem", `{"sub":"admin"}`)
+		assert.Nil(t, err)
+
+		parts := strings.Split(token, ".")
+		if assert.Equal(t, 3, len(parts)) {
+			privateKey, err := parseRSAPrivateKey("testdata/rsa.pem")
+			assert.Nil(t, err)
+
+			signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+			assert.Nil(t, err)
+			digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			assert.Nil(t, rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, digest[:], signature))
+		}
+	})
+
+	t.Run("template", func(t *testing.T) {
+		token, err := Render("jwt", `{{jwt "HS512" "secret" (dict "sub" "admin" "exp" "1h")}}`, nil)
+		assert.Nil(t, err)
+
+		claims := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal([]byte(decodeSegment(t, strings.Split(token, ".")[1])), &claims))
+		assert.Equal(t, "admin", claims["sub"])
+		assert.Equal(t, claims["iat"].(float64)+3600, claims["exp"])
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := generateJWT("ES256", "secret", nil)
+		assert.ErrorContains(t, err, "not supported JWT algorithm")
+
+		_, err = generateJWT("RS256", "testdata/fake.pem", nil)
+		assert.Error(t, err)
+
+		_, err = generateJWT("RS256", "-----BEGIN fake", nil)
+		assert.ErrorContains(t, err, "invalid PEM")
+
+		_, err = generateJWT("HS256", "secret", "{")
+		assert.ErrorContains(t, err, "invalid JWT claims")
+
+		_, err = generateJWT("HS256", "secret", map[string]interface{}{"exp": "fake"})
+		assert.ErrorContains(t, err, "invalid exp of the JWT claims")
+	})
+}
+
+func TestJWTClaims(t *testing.T) {
+	now := time.Unix(100, 0)
+	claims, err := jwtClaims(map[string]interface{}{"nbf": "-1m", "exp": 200}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"iat": int64(100), "nbf": int64(40), "exp": 200}, claims)
+}
+
+func decodeSegment(t *testing.T, segment string) string {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	assert.Nil(t, err)
+	return string(data)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/Masterminds/sprig/v3"
+	"github.com/linuxsuren/api-testing/pkg/secret"
 	"github.com/linuxsuren/api-testing/pkg/util"
 )
 
@@ -25,12 +26,41 @@ func Render(name, text string, ctx interface{}) (result string, err error) {
 	return
 }
 
+// RenderValue renders the strings of the value, the maps and the slices are rendered recursively
+func RenderValue(name string, value interface{}, ctx interface{}) (result interface{}, err error) {
+	switch val := value.(type) {
+	case string:
+		result, err = Render(name, val, ctx)
+	case map[string]interface{}:
+		items := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			if items[key], err = RenderValue(name, item, ctx); err != nil {
+				return
+			}
+		}
+		result = items
+	case []interface{}:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			if items[i], err = RenderValue(name, item, ctx); err != nil {
+				return
+			}
+		}
+		result = items
+	default:
+		result = value
+	}
+	return
+}
+
 // FuncMap reutrns all the supported functions
 func FuncMap() template.FuncMap {
 	funcs := sprig.FuncMap()
 	funcs["randomKubernetesName"] = func() string {
 		return util.String(8)
 	}
+	funcs["secret"] = secret.Get
+	funcs["jwt"] = generateJWT
 	return funcs
 }
 
@@ -2,6 +2,7 @@ package render
 
 import (
 	"bytes"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -43,7 +44,13 @@ func TestRender(t *testing.T) {
 		verify: func(t *testing.T, s string) {
 			assert.Equal(t, 20, len(s), s)
 		},
+	}, {
+		name:   "secret",
+		text:   `{{secret "RENDER_SECRET"}}`,
+		expect: "secret-value",
 	}}
+	os.Setenv("RENDER_SECRET", "secret-value")
+	defer os.Unsetenv("RENDER_SECRET")
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result, err := Render(tt.name, tt.text, tt.ctx)
@@ -86,3 +93,20 @@ func TestRenderThenPrint(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderValue(t *testing.T) {
+	result, err := RenderValue("filter", map[string]interface{}{
+		"name": "{{.name}}",
+		"age":  map[string]interface{}{"$gt": float64(18)},
+		"tags": []interface{}{"{{.tag}}", true},
+	}, map[string]string{"name": "rick", "tag": "admin"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name": "rick",
+		"age":  map[string]interface{}{"$gt": float64(18)},
+		"tags": []interface{}{"admin", true},
+	}, result)
+
+	_, err = RenderValue("filter", []interface{}{"{{.name"}, nil)
+	assert.Error(t, err)
+}
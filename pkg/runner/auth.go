@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/linuxsuren/api-testing/pkg/render"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// authorize puts the credential of the auth into the request, the existing header or query of the request is kept.
+// The auth may be shared with the other test cases, so the templates are rendered into the copies
+func authorize(ctx context.Context, auth *testing.Auth, request *http.Request, dataContext interface{}) (err error) {
+	switch {
+	case auth == nil:
+	case auth.Basic != nil:
+		basic := *auth.Basic
+		if err = renderFields(dataContext, &basic.Username, &basic.Password); err == nil && request.Header.Get("Authorization") == "" {
+			request.SetBasicAuth(basic.Username, basic.Password)
+		}
+	case auth.APIKey != nil:
+		apiKey := *auth.APIKey
+		if err = renderFields(dataContext, &apiKey.Value); err == nil {
+			err = putAPIKey(request, apiKey)
+		}
+	case auth.OAuth2 != nil:
+		if request.Header.Get("Authorization") != "" {
+			return
+		}
+
+		options := *auth.OAuth2
+		if err = renderFields(dataContext, &options.TokenURL, &options.ClientID, &options.ClientSecret); err != nil {
+			return
+		}
+
+		var token string
+		if token, err = defaultOAuth2TokenCache.token(ctx, &options); err == nil {
+			request.Header.Set("Authorization", token)
+		} else {
+			err = fmt.Errorf("failed to get the OAuth2 token: %v", err)
+		}
+	}
+	return
+}
+
+func putAPIKey(request *http.Request, apiKey testing.APIKeyAuth) (err error) {
+	switch apiKey.In {
+	case "", "header":
+		if request.Header.Get(apiKey.Name) == "" {
+			request.Header.Set(apiKey.Name, apiKey.Value)
+		}
+	case "query":
+		if query := request.URL.Query(); !query.Has(apiKey.Name) {
+			query.Set(apiKey.Name, apiKey.Value)
+			request.URL.RawQuery = query.Encode()
+		}
+	default:
+		err = fmt.Errorf("invalid location of the API key: %s, it should be header or query", apiKey.In)
+	}
+	return
+}
+
+func renderFields(dataContext interface{}, fields ...*string) (err error) {
+	for _, field := range fields {
+		if *field, err = render.Render("auth", *field, dataContext); err != nil {
+			return
+		}
+	}
+	return
+}
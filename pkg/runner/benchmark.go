@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Benchmark is the achieved throughput and the latency distribution of all the requests against the target QPS
+type Benchmark struct {
+	TargetQPS float64
+	QPS       float64
+	Count     int
+	Error     int
+	P50       time.Duration
+	P90       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	Max       time.Duration
+}
+
+// NewBenchmark creates the benchmark of the records, the QPS is the count of the requests
+// per wall-clock second, from the first begin time to the last end time. The warm-up records are excluded
+func NewBenchmark(allRecords []*ReportRecord, targetQPS float64) (benchmark *Benchmark) {
+	records := make([]*ReportRecord, 0, len(allRecords))
+	for _, record := range allRecords {
+		if !record.WarmUp {
+			records = append(records, record)
+		}
+	}
+	benchmark = &Benchmark{TargetQPS: targetQPS, Count: len(records)}
+	if len(records) == 0 {
+		return
+	}
+
+	durations := make([]time.Duration, 0, len(records))
+	first, last := records[0].BeginTime, records[0].EndTime
+	for _, record := range records {
+		durations = append(durations, record.Duration())
+		benchmark.Error += record.ErrorCount()
+		if record.BeginTime.Before(first) {
+			first = record.BeginTime
+		}
+		last = getLaterTime(record.EndTime, last)
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	benchmark.P50 = percentile(durations, 50)
+	benchmark.P90 = percentile(durations, 90)
+	benchmark.P95 = percentile(durations, 95)
+	benchmark.P99 = percentile(durations, 99)
+	benchmark.Max = durations[len(durations)-1]
+	if duration := last.Sub(first).Seconds(); duration > 0 {
+		benchmark.QPS = float64(benchmark.Count) / duration
+	}
+	return
+}
+
+// Achieved returns the percent of the achieved QPS to the target one
+func (b *Benchmark) Achieved() float64 {
+	if b.TargetQPS <= 0 {
+		return 0
+	}
+	return b.QPS / b.TargetQPS * 100
+}
+
+// String returns the summary of the benchmark
+func (b *Benchmark) String() string {
+	return fmt.Sprintf("target QPS: %.2f, achieved QPS: %.2f (%.1f%%), requests: %d, errors: %d, latency p50: %s, p90: %s, p95: %s, p99: %s, max: %s",
+		b.TargetQPS, b.QPS, b.Achieved(), b.Count, b.Error, b.P50, b.P90, b.P95, b.P99, b.Max)
+}
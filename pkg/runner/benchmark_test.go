@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBenchmark(t *testing.T) {
+	now := time.Now()
+	var records []*ReportRecord
+	for i := 1; i <= 10; i++ {
+		record := &ReportRecord{
+			BeginTime: now.Add(time.Duration(i-1) * 100 * time.Millisecond),
+		}
+		record.EndTime = record.BeginTime.Add(time.Duration(i) * time.Millisecond)
+		if i == 10 {
+			record.Error = errors.New("fake")
+		}
+		records = append(records, record)
+	}
+
+	benchmark := NewBenchmark(records, 20)
+	assert.Equal(t, 10, benchmark.Count)
+	assert.Equal(t, 1, benchmark.Error)
+	assert.Equal(t, 5*time.Millisecond, benchmark.P50)
+	assert.Equal(t, 9*time.Millisecond, benchmark.P90)
+	assert.Equal(t, 10*time.Millisecond, benchmark.P99)
+	assert.Equal(t, 10*time.Millisecond, benchmark.Max)
+	assert.InDelta(t, 10/0.91, benchmark.QPS, 0.01)
+	assert.InDelta(t, 10/0.91/20*100, benchmark.Achieved(), 0.01)
+	assert.Contains(t, benchmark.String(), "target QPS: 20.00, achieved QPS: 10.99 (54.9%), requests: 10, errors: 1, latency p50: 5ms")
+
+	t.Run("no records", func(t *testing.T) {
+		benchmark := NewBenchmark(nil, 0)
+		assert.Equal(t, 0, benchmark.Count)
+		assert.Zero(t, benchmark.Achieved())
+	})
+}
@@ -0,0 +1,165 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+type cachedResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+	expires    time.Time
+}
+
+// responseCache keeps the responses of the GET requests, it's shared by all the runners of the process
+type responseCache struct {
+	lock  sync.Mutex
+	items map[string]cachedResponse
+}
+
+var defaultResponseCache = &responseCache{items: map[string]cachedResponse{}}
+
+// get returns the response if it's not expired
+func (c *responseCache) get(key string) (resp *http.Response, body []byte, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var item cachedResponse
+	if item, ok = c.items[key]; !ok {
+		return
+	}
+	if ok = time.Now().Before(item.expires); !ok {
+		delete(c.items, key)
+		return
+	}
+
+	body = item.body
+	resp = &http.Response{
+		StatusCode: item.statusCode,
+		Status:     fmt.Sprintf("%d %s", item.statusCode, http.StatusText(item.statusCode)),
+		Header:     item.header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+	return
+}
+
+func (c *responseCache) put(key string, resp *http.Response, body []byte, ttl time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.items[key] = cachedResponse{
+		statusCode: resp.StatusCode,
+		header:     resp.Header.Clone(),
+		body:       body,
+		expires:    time.Now().Add(ttl),
+	}
+}
+
+// cacheTransport returns the cached response of the same GET or HEAD request if there is, otherwise sends it via the next one.
+// It's the innermost transport of the client, so the cookie jar, the cassette, the chaos and the dump work with the cached
+// responses as well. The key of the cache is the method, the URL, the headers (including the cookies) and the options of
+// the transport, such as the TLS client certificate and the proxy.
+type cacheTransport struct {
+	next    http.RoundTripper
+	ttl     time.Duration
+	options string
+}
+
+// newCacheTransport wraps the transport with the cache of the request, the streams of the server-sent events are not cached
+func newCacheTransport(next http.RoundTripper, req *testing.Request, scheme, contextDir string) (transport http.RoundTripper, err error) {
+	transport = next
+	if req.Cache == nil || req.SSE != nil {
+		return
+	}
+
+	var ttl time.Duration
+	if ttl, err = parseDurationOrDefault(req.Cache.TTL, 0); err != nil {
+		err = fmt.Errorf("invalid TTL of the cache: %v", err)
+		return
+	}
+	transport = &cacheTransport{next: next, ttl: ttl, options: transportKey(req, scheme, contextDir)}
+	return
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *cacheTransport) RoundTrip(request *http.Request) (resp *http.Response, err error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		return next.RoundTrip(request)
+	}
+
+	key := cacheKey(request, t.options)
+	var ok bool
+	if resp, _, ok = defaultResponseCache.get(key); ok {
+		resp.Request = request
+		return
+	}
+
+	if resp, err = next.RoundTrip(request); err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		return
+	}
+	ttl := cacheTTL(resp.Header, t.ttl)
+	if ttl <= 0 {
+		return
+	}
+
+	var body []byte
+	body, err = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	defaultResponseCache.put(key, resp, body, ttl)
+	return
+}
+
+func cacheKey(request *http.Request, options string) string {
+	names := make([]string, 0, len(request.Header))
+	for name := range request.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	key := new(strings.Builder)
+	fmt.Fprintf(key, "%s %s\n", request.Method, request.URL.String())
+	for _, name := range names {
+		fmt.Fprintf(key, "%s: %s\n", name, strings.Join(request.Header[name], ","))
+	}
+	fmt.Fprintf(key, "options: %s\n", options)
+	return key.String()
+}
+
+// cacheTTL returns the TTL if it's set, otherwise the max-age of the Cache-Control.
+// The response is not cached if the Cache-Control is no-store.
+func cacheTTL(header http.Header, ttl time.Duration) time.Duration {
+	var maxAge time.Duration
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	if ttl > 0 {
+		return ttl
+	}
+	return maxAge
+}
@@ -0,0 +1,176 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		cache   *atest.Cache
+		header  map[string]string
+		prepare func()
+		expect  int
+	}{{
+		name:  "TTL",
+		cache: &atest.Cache{TTL: "1m"},
+		prepare: func() {
+			gock.New(urlLocalhost).Get("/foo").Reply(http.StatusOK).BodyString(`{"name":"foo"}`)
+		},
+		expect: 1,
+	}, {
+		name:  "max-age of the Cache-Control",
+		cache: &atest.Cache{},
+		prepare: func() {
+			gock.New(urlLocalhost).Get("/foo").Reply(http.StatusOK).
+				SetHeader("Cache-Control", "public, max-age=60").BodyString(`{"name":"foo"}`)
+		},
+		expect: 1,
+	}, {
+		name:  "no-store",
+		cache: &atest.Cache{TTL: "1m"},
+		prepare: func() {
+			gock.New(urlLocalhost).Get("/foo").Times(2).Reply(http.StatusOK).
+				SetHeader("Cache-Control", "no-store").BodyString(`{"name":"foo"}`)
+		},
+		expect: 2,
+	}, {
+		name:  "no TTL",
+		cache: &atest.Cache{},
+		prepare: func() {
+			gock.New(urlLocalhost).Get("/foo").Times(2).Reply(http.StatusOK).BodyString(`{"name":"foo"}`)
+		},
+		expect: 2,
+	}, {
+		name: "without cache",
+		prepare: func() {
+			gock.New(urlLocalhost).Get("/foo").Times(2).Reply(http.StatusOK).BodyString(`{"name":"foo"}`)
+		},
+		expect: 2,
+	}, {
+		name:   "POST",
+		method: http.MethodPost,
+		cache:  &atest.Cache{TTL: "1m"},
+		prepare: func() {
+			gock.New(urlLocalhost).Post("/foo").Times(2).Reply(http.StatusOK).BodyString(`{"name":"foo"}`)
+		},
+		expect: 2,
+	}, {
+		name:  "server error",
+		cache: &atest.Cache{TTL: "1m"},
+		prepare: func() {
+			gock.New(urlLocalhost).Get("/foo").Times(2).Reply(http.StatusBadGateway).BodyString(`{}`)
+		},
+		expect: 2,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			defaultResponseCache.items = map[string]cachedResponse{}
+			tt.prepare()
+
+			var requests int
+			gock.Observe(func(*http.Request, gock.Mock) {
+				requests++
+			})
+			defer gock.Observe(nil)
+
+			for i := 0; i < 2; i++ {
+				_, _ = NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+					Request: atest.Request{API: urlFoo, Method: tt.method, Cache: tt.cache},
+					Expect:  atest.Response{StatusCode: http.StatusOK},
+				}, nil, context.TODO())
+			}
+			assert.Equal(t, tt.expect, requests)
+		})
+	}
+}
+
+func TestResponseCacheKey(t *testing.T) {
+	defer gock.Off()
+	defaultResponseCache.items = map[string]cachedResponse{}
+	gock.New(urlLocalhost).Get("/foo").MatchHeader("user", "a").Reply(http.StatusOK).BodyString(`{"user":"a"}`)
+	gock.New(urlLocalhost).Get("/foo").MatchHeader("user", "b").Reply(http.StatusOK).BodyString(`{"user":"b"}`)
+
+	for _, user := range []string{"a", "b", "a", "b"} {
+		_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+			Request: atest.Request{API: urlFoo, Header: map[string]string{"user": user}, Cache: &atest.Cache{TTL: "1m"}},
+			Expect:  atest.Response{BodyFieldsExpect: map[string]interface{}{"user": user}},
+		}, nil, context.TODO())
+		assert.Nil(t, err)
+	}
+	assert.True(t, gock.IsDone())
+}
+
+func TestResponseCacheWithCookies(t *testing.T) {
+	defer gock.Off()
+	defaultResponseCache.items = map[string]cachedResponse{}
+	gock.New(urlLocalhost).Get("/foo").MatchHeader("Cookie", "user=a").Reply(http.StatusOK).BodyString(`{"user":"a"}`)
+	gock.New(urlLocalhost).Get("/foo").MatchHeader("Cookie", "user=b").Reply(http.StatusOK).BodyString(`{"user":"b"}`)
+
+	for _, user := range []string{"a", "b", "a", "b"} {
+		ctx := WithCookieJar(context.TODO())
+		getCookieJar(ctx).SetCookies(&url.URL{Scheme: "http", Host: "localhost"}, []*http.Cookie{{Name: "user", Value: user}})
+		_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+			Request: atest.Request{API: urlFoo, Cache: &atest.Cache{TTL: "1m"}},
+			Expect:  atest.Response{BodyFieldsExpect: map[string]interface{}{"user": user}},
+		}, nil, ctx)
+		assert.Nil(t, err)
+	}
+	assert.True(t, gock.IsDone())
+}
+
+func TestResponseCacheWithChaos(t *testing.T) {
+	defer gock.Off()
+	defaultResponseCache.items = map[string]cachedResponse{}
+	gock.New(urlLocalhost).Get("/foo").Reply(http.StatusOK).BodyString(`{}`)
+
+	testCase := &atest.TestCase{Request: atest.Request{API: urlFoo, Cache: &atest.Cache{TTL: "1m"}}}
+	_, err := NewSimpleTestCaseRunner().RunTestCase(testCase, nil, context.TODO())
+	assert.Nil(t, err)
+
+	// the faults are injected into the cached responses as well
+	testCase.Chaos = &atest.Chaos{Rate: 1, Faults: []string{ChaosReset}}
+	_, err = NewSimpleTestCaseRunner().RunTestCase(testCase, nil, context.TODO())
+	assert.ErrorContains(t, err, "chaos: connection reset by peer")
+}
+
+func TestCacheKey(t *testing.T) {
+	request, err := http.NewRequest(http.MethodGet, urlFoo, nil)
+	assert.Nil(t, err)
+	withProxy := transportKey(&atest.Request{Proxy: &atest.Proxy{URL: "http://proxy:3128"}}, "http", "")
+	withCert := transportKey(&atest.Request{TLS: &atest.TLS{Cert: "client.pem", Key: "client.key"}}, "https", "")
+	assert.NotEqual(t, cacheKey(request, withProxy), cacheKey(request, withCert))
+	assert.NotEqual(t, cacheKey(request, withProxy), cacheKey(request, transportKey(&atest.Request{}, "http", "")))
+}
+
+func TestResponseCacheExpired(t *testing.T) {
+	cache := &responseCache{items: map[string]cachedResponse{}}
+	cache.put("key", &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, []byte("body"), -time.Second)
+	_, _, ok := cache.get("key")
+	assert.False(t, ok)
+	assert.Empty(t, cache.items)
+}
+
+func TestInvalidCacheTTL(t *testing.T) {
+	_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: urlFoo, Cache: &atest.Cache{TTL: "fake"}},
+	}, nil, context.TODO())
+	assert.ErrorContains(t, err, "invalid TTL of the cache")
+}
+
+func TestCacheTTL(t *testing.T) {
+	assert.Equal(t, time.Minute, cacheTTL(http.Header{"Cache-Control": []string{"max-age=10"}}, time.Minute))
+	assert.Equal(t, 10*time.Second, cacheTTL(http.Header{"Cache-Control": []string{"max-age=10"}}, 0))
+	assert.Equal(t, time.Duration(0), cacheTTL(http.Header{"Cache-Control": []string{"no-cache"}}, time.Minute))
+	assert.Equal(t, time.Duration(0), cacheTTL(http.Header{}, 0))
+}
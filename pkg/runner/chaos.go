@@ -0,0 +1,204 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const (
+	// ChaosLatency delays the request
+	ChaosLatency = "latency"
+	// ChaosReset fails the request as the connection is reset by the server
+	ChaosReset = "reset"
+	// ChaosTruncate cuts the response body in the middle
+	ChaosTruncate = "truncate"
+)
+
+// AllChaosFaults are all the supported faults
+var AllChaosFaults = []string{ChaosLatency, ChaosReset, ChaosTruncate}
+
+const defaultChaosLatency = time.Second
+
+// Chaos returns the key of the fault injector of the HTTP requests
+func (c ContextKey) Chaos() ContextKey {
+	return ContextKey("chaos")
+}
+
+// WithChaos returns a context with the fault injector of the options, the faults are injected into the HTTP requests of it
+func WithChaos(ctx context.Context, options *testing.Chaos) (context.Context, error) {
+	injector, err := newChaosInjector(options)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, NewContextKeyBuilder().Chaos(), injector), nil
+}
+
+func getChaos(ctx context.Context) *chaosInjector {
+	injector, _ := ctx.Value(NewContextKeyBuilder().Chaos()).(*chaosInjector)
+	return injector
+}
+
+type chaosInjector struct {
+	rate    float64
+	faults  []string
+	latency time.Duration
+	rand    *rand.Rand
+	lock    sync.Mutex
+}
+
+func newChaosInjector(options *testing.Chaos) (injector *chaosInjector, err error) {
+	if options.Rate < 0 || options.Rate > 1 {
+		err = fmt.Errorf("invalid rate of the chaos: %v, it should be from 0 to 1", options.Rate)
+		return
+	}
+
+	faults := options.Faults
+	if len(faults) == 0 {
+		faults = AllChaosFaults
+	}
+	for _, fault := range faults {
+		if !isChaosFault(fault) {
+			err = fmt.Errorf("unsupported fault of the chaos: '%s'", fault)
+			return
+		}
+	}
+
+	var latency time.Duration
+	if latency, err = parseDurationOrDefault(options.Latency, defaultChaosLatency); err != nil {
+		err = fmt.Errorf("invalid latency of the chaos: %v", err)
+		return
+	}
+
+	seed := options.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	injector = &chaosInjector{
+		rate:    options.Rate,
+		faults:  faults,
+		latency: latency,
+		rand:    rand.New(rand.NewSource(seed)),
+	}
+	return
+}
+
+func isChaosFault(fault string) bool {
+	for _, item := range AllChaosFaults {
+		if item == fault {
+			return true
+		}
+	}
+	return false
+}
+
+// pick returns the fault of a request and the delay of the latency, the fault is empty if there is no fault
+func (c *chaosInjector) pick() (fault string, delay time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.rand.Float64() >= c.rate {
+		return
+	}
+	fault = c.faults[c.rand.Intn(len(c.faults))]
+	if fault == ChaosLatency && c.latency > 0 {
+		delay = time.Duration(c.rand.Int63n(int64(c.latency))) + 1
+	}
+	return
+}
+
+// RoundTripper wraps the transport, the default one is used if it's nil
+func (c *chaosInjector) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &chaosTransport{injector: c, next: next}
+}
+
+type chaosTransport struct {
+	injector *chaosInjector
+	next     http.RoundTripper
+}
+
+// RoundTrip injects a random fault into the request
+func (t *chaosTransport) RoundTrip(request *http.Request) (resp *http.Response, err error) {
+	fault, delay := t.injector.pick()
+	switch fault {
+	case ChaosLatency:
+		select {
+		case <-request.Context().Done():
+			closeRequestBody(request)
+			err = request.Context().Err()
+			return
+		case <-time.After(delay):
+		}
+	case ChaosReset:
+		closeRequestBody(request)
+		err = &chaosError{err: syscall.ECONNRESET}
+		return
+	}
+
+	if resp, err = t.next.RoundTrip(request); err == nil && fault == ChaosTruncate {
+		resp.Body = newTruncatedBody(resp.Body)
+	}
+	return
+}
+
+func closeRequestBody(request *http.Request) {
+	if request.Body != nil {
+		_ = request.Body.Close()
+	}
+}
+
+// chaosError is the injected error, it's unwrapped as the original one
+type chaosError struct {
+	err error
+}
+
+func (e *chaosError) Error() string {
+	return fmt.Sprintf("chaos: %v", e.err)
+}
+
+func (e *chaosError) Unwrap() error {
+	return e.err
+}
+
+// truncatedBody returns the first half of the body, then the unexpected EOF
+type truncatedBody struct {
+	reader io.Reader
+	body   io.ReadCloser
+}
+
+func newTruncatedBody(body io.ReadCloser) io.ReadCloser {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return &truncatedBody{reader: io.MultiReader(bytes.NewReader(data), &errorReader{err: err}), body: body}
+	}
+	return &truncatedBody{
+		reader: io.MultiReader(bytes.NewReader(data[:len(data)/2]), &errorReader{err: &chaosError{err: io.ErrUnexpectedEOF}}),
+		body:   body,
+	}
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	return b.reader.Read(p)
+}
+
+func (b *truncatedBody) Close() error {
+	return b.body.Close()
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,131 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaos(t *testing.T) {
+	send := func(chaos *atest.Chaos, req *atest.Request) (body []byte, err error) {
+		var ctx context.Context
+		if ctx, err = WithChaos(context.Background(), chaos); err != nil {
+			return
+		}
+		var request *http.Request
+		if request, err = http.NewRequestWithContext(ctx, http.MethodGet, urlFoo, nil); err == nil {
+			_, body, err = doRequest(request, req)
+		}
+		return
+	}
+
+	t.Run("reset", func(t *testing.T) {
+		_, err := send(&atest.Chaos{Rate: 1, Faults: []string{ChaosReset}}, &atest.Request{})
+		assert.ErrorContains(t, err, "chaos: connection reset by peer")
+		assert.True(t, errors.Is(err, syscall.ECONNRESET))
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Get("/").Reply(http.StatusOK).BodyString("hello world!")
+
+		body, err := send(&atest.Chaos{Rate: 1, Faults: []string{ChaosTruncate}}, &atest.Request{})
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), err)
+		assert.Equal(t, "hello ", string(body))
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Get("/").Reply(http.StatusOK).BodyString("hello")
+
+		begin := time.Now()
+		body, err := send(&atest.Chaos{Rate: 1, Faults: []string{ChaosLatency}, Latency: "50ms"}, &atest.Request{})
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(body))
+		assert.Less(t, time.Since(begin), time.Second)
+	})
+
+	t.Run("latency exceeds the timeout", func(t *testing.T) {
+		_, err := send(&atest.Chaos{Rate: 1, Faults: []string{ChaosLatency}, Latency: "1h"}, &atest.Request{Timeout: "10ms"})
+		assert.True(t, isTimeout(err), err)
+	})
+
+	t.Run("no fault", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Get("/").Times(10).Reply(http.StatusOK).BodyString("hello")
+
+		for i := 0; i < 10; i++ {
+			body, err := send(&atest.Chaos{Rate: 0}, &atest.Request{})
+			assert.NoError(t, err)
+			assert.Equal(t, "hello", string(body))
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := WithChaos(context.Background(), &atest.Chaos{Rate: 2})
+		assert.ErrorContains(t, err, "invalid rate of the chaos")
+
+		_, err = WithChaos(context.Background(), &atest.Chaos{Rate: 1, Faults: []string{"fake"}})
+		assert.ErrorContains(t, err, "unsupported fault of the chaos: 'fake'")
+
+		_, err = WithChaos(context.Background(), &atest.Chaos{Rate: 1, Latency: "fake"})
+		assert.ErrorContains(t, err, "invalid latency of the chaos")
+	})
+
+	t.Run("reproducible with the seed", func(t *testing.T) {
+		first, err := newChaosInjector(&atest.Chaos{Rate: 0.5, Seed: 1})
+		assert.NoError(t, err)
+		second, err := newChaosInjector(&atest.Chaos{Rate: 0.5, Seed: 1})
+		assert.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			fault, delay := first.pick()
+			expectFault, expectDelay := second.pick()
+			assert.Equal(t, expectFault, fault)
+			assert.Equal(t, expectDelay, delay)
+		}
+	})
+}
+
+func TestRunTestCaseWithChaos(t *testing.T) {
+	run := func(chaos *atest.Chaos) (records []*ReportRecord, err error) {
+		reporter := NewMemoryTestReporter()
+		_, err = NewSimpleTestCaseRunner().WithOutputWriter(new(bytes.Buffer)).WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+			Request: atest.Request{API: urlFoo},
+			Retry:   &atest.Retry{MaxAttempts: 2, Backoff: "1ms"},
+			Chaos:   chaos,
+		}, nil, context.TODO())
+		records = reporter.GetAllRecords()
+		return
+	}
+
+	t.Run("retried after the fault", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Get("/").Reply(http.StatusOK).BodyString("{}")
+
+		// the first request is reset, the second one has no fault with the seed
+		records, err := run(&atest.Chaos{Rate: 0.5, Faults: []string{ChaosReset}, Seed: 6})
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(records)) {
+			assert.Equal(t, 1, records[0].Retries)
+		}
+	})
+
+	t.Run("the attempts are exhausted", func(t *testing.T) {
+		_, err := run(&atest.Chaos{Rate: 1, Faults: []string{ChaosReset}})
+		assert.ErrorContains(t, err, "chaos: connection reset by peer")
+	})
+
+	t.Run("invalid chaos", func(t *testing.T) {
+		_, err := run(&atest.Chaos{Rate: -1})
+		assert.ErrorContains(t, err, "invalid rate of the chaos")
+	})
+}
@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// decompressBody decodes the body by the Content-Encoding of the response, the encodings are decoded in the reverse order.
+// The body is kept as it is if there's an encoding which is not supported, such as br. The Content-Encoding header is kept
+// for the assertions, and the response is marked as uncompressed
+func decompressBody(resp *http.Response, body []byte) (data []byte, err error) {
+	data = body
+	encodings := contentEncodings(resp.Header)
+	if len(body) == 0 {
+		// such as the responses of the HEAD requests
+		return
+	}
+	for _, encoding := range encodings {
+		if encoding != "gzip" && encoding != "x-gzip" && encoding != "deflate" && encoding != "identity" {
+			return
+		}
+	}
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var reader io.ReadCloser
+		switch encodings[i] {
+		case "gzip", "x-gzip":
+			reader, err = gzip.NewReader(bytes.NewReader(data))
+		case "deflate":
+			// it should be the zlib format, but some servers send the raw deflate
+			if reader, err = zlib.NewReader(bytes.NewReader(data)); err != nil {
+				reader, err = flate.NewReader(bytes.NewReader(data)), nil
+			}
+		default:
+			continue
+		}
+		if err == nil {
+			data, err = io.ReadAll(reader)
+			_ = reader.Close()
+		}
+		if err != nil {
+			return
+		}
+		resp.Uncompressed = true
+	}
+	return
+}
+
+// contentEncoding returns the encoding which is used by the server, the transport decodes the gzip body transparently
+// and removes the header if the request doesn't have the Accept-Encoding
+func contentEncoding(resp *http.Response) string {
+	if encodings := contentEncodings(resp.Header); len(encodings) > 0 {
+		return strings.Join(encodings, ", ")
+	} else if resp.Uncompressed {
+		return "gzip"
+	}
+	return "identity"
+}
+
+func contentEncodings(header http.Header) (encodings []string) {
+	for _, value := range header.Values("Content-Encoding") {
+		for _, encoding := range strings.Split(value, ",") {
+			if encoding = strings.ToLower(strings.TrimSpace(encoding)); encoding != "" {
+				encodings = append(encodings, encoding)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,133 @@
+package runner
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressedResponse(t *testing.T) {
+	const body = `{"name": "linuxsuren"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		var writer io.WriteCloser
+		switch r.URL.Query().Get("encoding") {
+		case "gzip":
+			writer = gzip.NewWriter(buf)
+		case "deflate":
+			writer = zlib.NewWriter(buf)
+		case "raw-deflate":
+			writer, _ = flate.NewWriter(buf, flate.DefaultCompression)
+		default:
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		_, _ = writer.Write([]byte(body))
+		_ = writer.Close()
+
+		encoding := r.URL.Query().Get("encoding")
+		if encoding == "raw-deflate" {
+			encoding = "deflate"
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		encoding       string
+		acceptEncoding string
+		expect         string
+		err            string
+	}{{
+		name:     "transparent gzip",
+		encoding: "gzip",
+		expect:   "gzip",
+	}, {
+		name:           "explicit gzip",
+		encoding:       "gzip",
+		acceptEncoding: "gzip",
+		expect:         "gzip",
+	}, {
+		name:           "deflate",
+		encoding:       "deflate",
+		acceptEncoding: "deflate",
+		expect:         "deflate",
+	}, {
+		name:           "raw deflate",
+		encoding:       "raw-deflate",
+		acceptEncoding: "deflate",
+		expect:         "deflate",
+	}, {
+		name:   "identity",
+		expect: "identity",
+	}, {
+		name:           "mis-negotiated compression",
+		acceptEncoding: "br",
+		expect:         "br",
+		err:            "unexpected Content-Encoding: case: mis-negotiated compression, expect br, actual identity",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := atest.Request{API: server.URL + "/users?encoding=" + tt.encoding}
+			if tt.acceptEncoding != "" {
+				request.Header = map[string]string{"Accept-Encoding": tt.acceptEncoding}
+			}
+
+			_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+				Name:    tt.name,
+				Request: request,
+				Expect: atest.Response{
+					ContentEncoding:  tt.expect,
+					BodyFieldsExpect: map[string]interface{}{"name": "linuxsuren"},
+				},
+			}, nil, context.TODO())
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestDecompressBody(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := gzip.NewWriter(buf)
+	_, _ = writer.Write([]byte("hello"))
+	_ = writer.Close()
+
+	// the encodings are decoded in the reverse order
+	inner := new(bytes.Buffer)
+	zlibWriter := zlib.NewWriter(inner)
+	_, _ = zlibWriter.Write(buf.Bytes())
+	_ = zlibWriter.Close()
+
+	resp := &http.Response{Header: http.Header{"Content-Encoding": []string{"gzip, deflate"}}}
+	data, err := decompressBody(resp, inner.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.True(t, resp.Uncompressed)
+	assert.Equal(t, "gzip, deflate", contentEncoding(resp))
+
+	// the not supported encoding is kept
+	resp = &http.Response{Header: http.Header{"Content-Encoding": []string{"br"}}}
+	data, err = decompressBody(resp, []byte("fake"))
+	assert.NoError(t, err)
+	assert.Equal(t, "fake", string(data))
+	assert.Equal(t, "br", contentEncoding(resp))
+
+	resp = &http.Response{Header: http.Header{"Content-Encoding": []string{"gzip"}}}
+	_, err = decompressBody(resp, []byte("fake"))
+	assert.Error(t, err)
+}
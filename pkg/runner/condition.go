@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// shouldSkip returns the reason if the test case is skipped by its condition or skipIf expression.
+// The expressions are evaluated against the data context, the undefined variables are nil
+func shouldSkip(testcase *testing.TestCase, dataContext interface{}) (reason string, err error) {
+	env, ok := dataContext.(map[string]interface{})
+	if !ok {
+		env = map[string]interface{}{}
+	}
+
+	var result bool
+	if testcase.Condition != "" {
+		if result, err = evalCondition(testcase.Condition, env); err == nil && !result {
+			reason = fmt.Sprintf("skipped due to the condition: %s", testcase.Condition)
+		}
+	}
+	if err == nil && reason == "" && testcase.SkipIf != "" {
+		if result, err = evalCondition(testcase.SkipIf, env); err == nil && result {
+			reason = fmt.Sprintf("skipped due to the skipIf: %s", testcase.SkipIf)
+		}
+	}
+	return
+}
+
+func evalCondition(condition string, env map[string]interface{}) (result bool, err error) {
+	var program *vm.Program
+	if program, err = expr.Compile(condition, expr.Env(env), expr.AsBool(), expr.AllowUndefinedVariables()); err != nil {
+		err = fmt.Errorf("invalid condition '%s': %v", condition, err)
+		return
+	}
+
+	var output interface{}
+	if output, err = expr.Run(program, env); err != nil {
+		err = fmt.Errorf("failed to evaluate the condition '%s': %v", condition, err)
+		return
+	}
+	var ok bool
+	if result, ok = output.(bool); !ok {
+		err = fmt.Errorf("the condition '%s' is not a bool, actual: %v", condition, output)
+	}
+	return
+}
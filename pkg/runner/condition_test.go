@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCondition(t *testing.T) {
+	dataContext := map[string]interface{}{
+		"env":   map[string]interface{}{"FEATURE": "on"},
+		"login": map[string]interface{}{"role": "admin"},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		skipIf    string
+		skipped   bool
+		err       string
+	}{{
+		name: "no condition",
+	}, {
+		name:      "condition is true",
+		condition: `env.FEATURE == "on"`,
+	}, {
+		name:      "condition is false",
+		condition: `login.role == "guest"`,
+		skipped:   true,
+	}, {
+		name:      "undefined variable",
+		condition: `missing != nil`,
+		skipped:   true,
+	}, {
+		name:    "skipIf is true",
+		skipIf:  `env.FEATURE == "on"`,
+		skipped: true,
+	}, {
+		name:   "skipIf is false",
+		skipIf: `login.role != "admin"`,
+	}, {
+		name:      "not a bool",
+		condition: `login.role`,
+		err:       "condition 'login.role'",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			if !tt.skipped && tt.err == "" {
+				gock.New(urlLocalhost).Get("/users").Reply(200).BodyString("{}")
+			}
+
+			reporter := NewMemoryTestReporter()
+			output, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+				Name:      tt.name,
+				Condition: tt.condition,
+				SkipIf:    tt.skipIf,
+				Request:   atest.Request{API: urlLocalhost + "/users"},
+			}, dataContext, context.TODO())
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, gock.IsDone())
+
+			if records := reporter.GetAllRecords(); assert.Equal(t, 1, len(records)) {
+				assert.Equal(t, tt.skipped, records[0].Skipped)
+			}
+			if tt.skipped {
+				assert.Nil(t, output)
+				results, err := reporter.ExportAllReportResults()
+				assert.NoError(t, err)
+				assert.Equal(t, 1, results[0].Skipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const defaultContainerWaitTimeout = time.Minute
+
+var containerWaitInterval = 500 * time.Millisecond
+
+// startContainer runs the container via the docker CLI, the container ports are published to random local ports
+func (r *simpleTestCaseRunner) startContainer(ctx context.Context, container testing.Container, resources *preparedResources) (err error) {
+	args := []string{"run", "--detach", "--label", "atest=true"}
+	envNames := make([]string, 0, len(container.Env))
+	for name := range container.Env {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+	for _, name := range envNames {
+		args = append(args, "--env", fmt.Sprintf("%s=%s", name, container.Env[name]))
+	}
+	for _, port := range container.Ports {
+		args = append(args, "--publish", "127.0.0.1::"+port)
+	}
+	args = append(append(args, container.Image), container.Args...)
+
+	r.log.Info("prepare: start container %s from %s\n", container.Name, container.Image)
+	var id string
+	if id, err = r.docker(ctx, args...); err != nil {
+		return
+	}
+	resources.register(teardown{
+		name: "remove " + container.Name,
+		run: func(ctx context.Context) (rmErr error) {
+			_, rmErr = r.docker(ctx, "rm", "--force", "--volumes", id)
+			return
+		},
+	})
+
+	info := map[string]interface{}{"id": id}
+	ports := map[string]string{}
+	var address string
+	for i, port := range container.Ports {
+		var mapped string
+		if mapped, err = r.docker(ctx, "port", id, port); err != nil {
+			return
+		}
+		// there might be multiple lines for IPv4 and IPv6
+		mapped = strings.TrimSpace(strings.Split(mapped, "\n")[0])
+		ports[port] = mapped
+
+		if i == 0 {
+			address = mapped
+			info["address"] = mapped
+			if host, hostPort, splitErr := net.SplitHostPort(mapped); splitErr == nil {
+				info["host"] = host
+				info["port"] = hostPort
+			}
+		}
+	}
+	info["ports"] = ports
+
+	if resources.containerContext == nil {
+		resources.containerContext = map[string]interface{}{}
+	}
+	resources.containerContext[container.Name] = info
+	err = r.waitContainer(ctx, id, container, address)
+	return
+}
+
+// waitContainer blocks until the container is ready or timeout
+func (r *simpleTestCaseRunner) waitContainer(ctx context.Context, id string, container testing.Container, address string) (err error) {
+	var timeout time.Duration
+	if timeout, err = parseDurationOrDefault(container.Wait.Timeout, defaultContainerWaitTimeout); err != nil {
+		err = fmt.Errorf("invalid wait timeout of container '%s': %v", container.Name, err)
+		return
+	}
+
+	var ready func(context.Context) bool
+	switch {
+	case container.Wait.Log != "":
+		ready = func(ctx context.Context) bool {
+			logs, logErr := r.dockerLogs(ctx, id)
+			return logErr == nil && strings.Contains(logs, container.Wait.Log)
+		}
+	case container.Wait.HTTP != "":
+		if address == "" {
+			err = fmt.Errorf("container '%s' has no port to wait for HTTP", container.Name)
+			return
+		}
+		ready = func(ctx context.Context) bool {
+			return httpReady(ctx, fmt.Sprintf("http://%s/%s", address, strings.TrimPrefix(container.Wait.HTTP, "/")), 0)
+		}
+	case address != "":
+		ready = func(ctx context.Context) bool {
+			return tcpReady(ctx, address)
+		}
+	default:
+		return
+	}
+
+	if err = waitUntil(ctx, containerWaitInterval, timeout, ready); err != nil {
+		err = fmt.Errorf("container '%s' is not ready in %v", container.Name, timeout)
+		return
+	}
+	r.log.Info("prepare: container %s is ready\n", container.Name)
+	return
+}
+
+// docker runs the docker command and returns the trimmed stdout. The stderr is kept out of the output,
+// such as the progress of pulling the image, it's in the error if the command is failed
+func (r *simpleTestCaseRunner) docker(ctx context.Context, args ...string) (output string, err error) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	var exitCode int
+	if exitCode, err = r.executor.Run(ctx, exec.Command{Name: "docker", Args: args, Stderr: stderr}, stdout); err == nil && exitCode != 0 {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = strings.TrimSpace(stdout.String())
+		}
+		err = fmt.Errorf("failed to run docker %s: %s", args[0], message)
+	}
+	output = strings.TrimSpace(stdout.String())
+	return
+}
+
+// dockerLogs returns the logs of the container, both the stdout and the stderr of the container are included
+func (r *simpleTestCaseRunner) dockerLogs(ctx context.Context, id string) (logs string, err error) {
+	buf := new(bytes.Buffer)
+	var exitCode int
+	if exitCode, err = r.executor.Run(ctx, exec.Command{Name: "docker", Args: []string{"logs", id}}, buf); err == nil && exitCode != 0 {
+		err = fmt.Errorf("failed to run docker logs: %s", strings.TrimSpace(buf.String()))
+	}
+	logs = buf.String()
+	return
+}
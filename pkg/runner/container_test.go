@@ -0,0 +1,191 @@
+package runner
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer(t *testing.T) {
+	containerWaitInterval = 10 * time.Millisecond
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer listener.Close()
+	address := listener.Addr().String()
+
+	tests := []struct {
+		name      string
+		container atest.Container
+		executor  *exec.FakeExecutor
+		prepare   func()
+		verify    func(*testing.T, *exec.FakeExecutor, *preparedResources, error)
+	}{{
+		name: "wait for the port",
+		container: atest.Container{
+			Name:  "db",
+			Image: "postgres",
+			Env:   map[string]string{"B": "b", "A": "a"},
+			Ports: []string{"5432"},
+			Args:  []string{"-c", "fsync=off"},
+		},
+		executor: &exec.FakeExecutor{
+			OutputFunc: dockerOutputs(map[string]string{"run": "4f2a9c\n", "port": address + "\n[::1]:1234\n"}),
+			// the progress of pulling the image is not a part of the container id
+			Stderr: "Unable to find image 'postgres:latest' locally\n",
+		},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, resources *preparedResources, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"run", "--detach", "--label", "atest=true", "--env", "A=a", "--env", "B=b",
+				"--publish", "127.0.0.1::5432", "postgres", "-c", "fsync=off"}, executor.Commands[0].Args)
+			assert.Equal(t, []string{"port", "4f2a9c", "5432"}, executor.Commands[1].Args)
+
+			if assert.Equal(t, 1, len(resources.teardowns)) {
+				assert.Nil(t, resources.teardowns[0].run(context.TODO()))
+				assert.Equal(t, []string{"rm", "--force", "--volumes", "4f2a9c"}, executor.Commands[len(executor.Commands)-1].Args)
+			}
+
+			host, port, _ := net.SplitHostPort(address)
+			assert.Equal(t, map[string]interface{}{
+				"db": map[string]interface{}{
+					"id":      "4f2a9c",
+					"address": address,
+					"host":    host,
+					"port":    port,
+					"ports":   map[string]string{"5432": address},
+				},
+			}, resources.containerContext)
+		},
+	}, {
+		name: "wait for the log",
+		container: atest.Container{
+			Name:  "mq",
+			Image: "rabbitmq",
+			Wait:  atest.ContainerWait{Log: "ready"},
+		},
+		executor: &exec.FakeExecutor{
+			OutputFunc: dockerOutputs(map[string]string{"run": "mq-id", "logs": "starting\n"}),
+			// the logs of the container could be in the stderr
+			Stderr: "server is ready\n",
+		},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, resources *preparedResources, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"logs", "mq-id"}, executor.Commands[1].Args)
+		},
+	}, {
+		name: "log timeout",
+		container: atest.Container{
+			Name:  "mq",
+			Image: "rabbitmq",
+			Wait:  atest.ContainerWait{Log: "ready", Timeout: "50ms"},
+		},
+		executor: &exec.FakeExecutor{Output: "starting"},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, resources *preparedResources, err error) {
+			assert.ErrorContains(t, err, "container 'mq' is not ready in 50ms")
+			if assert.Equal(t, 1, len(resources.teardowns)) {
+				assert.Equal(t, "remove mq", resources.teardowns[0].name)
+			}
+		},
+	}, {
+		name: "wait for HTTP",
+		container: atest.Container{
+			Name:  "web",
+			Image: "nginx",
+			Ports: []string{"80"},
+			Wait:  atest.ContainerWait{HTTP: "/health"},
+		},
+		executor: &exec.FakeExecutor{Output: "127.0.0.1:32768"},
+		prepare: func() {
+			gock.New("http://127.0.0.1:32768").Get("/health").Reply(http.StatusOK)
+		},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, resources *preparedResources, err error) {
+			assert.Nil(t, err)
+		},
+	}, {
+		name: "wait for HTTP without port",
+		container: atest.Container{
+			Name:  "web",
+			Image: "nginx",
+			Wait:  atest.ContainerWait{HTTP: "/health"},
+		},
+		executor: &exec.FakeExecutor{},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, resources *preparedResources, err error) {
+			assert.NotNil(t, err)
+		},
+	}, {
+		name: "invalid timeout",
+		container: atest.Container{
+			Name:  "web",
+			Image: "nginx",
+			Wait:  atest.ContainerWait{Timeout: "fake"},
+		},
+		executor: &exec.FakeExecutor{},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, resources *preparedResources, err error) {
+			assert.NotNil(t, err)
+		},
+	}, {
+		name: "failed to run",
+		container: atest.Container{
+			Name:  "web",
+			Image: "nginx",
+		},
+		executor: &exec.FakeExecutor{ExitCode: 125, Stderr: "no such image"},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, resources *preparedResources, err error) {
+			assert.ErrorContains(t, err, "failed to run docker run: no such image")
+			assert.Empty(t, resources.teardowns)
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+
+			runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+			runner.executor = tt.executor
+			resources := &preparedResources{}
+			err := runner.startContainer(context.TODO(), tt.container, resources)
+			tt.verify(t, tt.executor, resources, err)
+		})
+	}
+}
+
+func TestContainerInTestCase(t *testing.T) {
+	defer gock.Off()
+	gock.New("http://127.0.0.1:32768").Get("/").Reply(http.StatusOK)
+	gock.New("http://127.0.0.1:32768").Get("/users").Reply(http.StatusOK).BodyString(`{}`)
+
+	executor := &exec.FakeExecutor{OutputFunc: dockerOutputs(map[string]string{"run": "web-id", "port": "127.0.0.1:32768"})}
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+	runner.executor = executor
+
+	dataContext := map[string]interface{}{}
+	_, err := runner.RunTestCase(&atest.TestCase{
+		Prepare: atest.Prepare{Containers: []atest.Container{{
+			Name:  "web",
+			Image: "nginx",
+			Ports: []string{"80"},
+			Wait:  atest.ContainerWait{HTTP: "/"},
+		}}},
+		Request: atest.Request{API: "http://{{.containers.web.address}}/users"},
+	}, dataContext, context.TODO())
+	assert.Nil(t, err)
+	assert.NotNil(t, dataContext["containers"])
+	assert.Equal(t, []string{"rm", "--force", "--volumes", "web-id"}, executor.Commands[len(executor.Commands)-1].Args)
+}
+
+// dockerOutputs returns the stdout of the docker commands by the sub-command
+func dockerOutputs(outputs map[string]string) func(exec.Command) string {
+	return func(command exec.Command) string {
+		return outputs[command.Args[0]]
+	}
+}
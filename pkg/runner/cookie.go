@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// CookieJar returns the key of the cookie jar which is shared by the test cases of a suite
+func (c ContextKey) CookieJar() ContextKey {
+	return ContextKey("cookieJar")
+}
+
+// WithCookieJar returns a context with a new cookie jar, the cookies of the responses are sent by the following requests
+func WithCookieJar(ctx context.Context) context.Context {
+	jar, _ := cookiejar.New(nil)
+	return context.WithValue(ctx, NewContextKeyBuilder().CookieJar(), jar)
+}
+
+// getCookieJar returns the cookie jar of the context, or nil if there is not
+func getCookieJar(ctx context.Context) http.CookieJar {
+	jar, _ := ctx.Value(NewContextKeyBuilder().CookieJar()).(http.CookieJar)
+	return jar
+}
+
+// verifyCookies checks the cookies which are set by the response, the value could be a regular expression
+func verifyCookies(name string, expected map[string]testing.Cookie, cookies []*http.Cookie) (err error) {
+	for key, expect := range expected {
+		var cookie *http.Cookie
+		for _, item := range cookies {
+			if item.Name == key {
+				cookie = item
+			}
+		}
+		if cookie == nil {
+			err = fmt.Errorf("case: %s, not found the cookie %s", name, key)
+			return
+		}
+
+		if expect.Value != "" {
+			if err = expectString(name, expect.Value, cookie.Value); err != nil {
+				err = fmt.Errorf("unexpected value of the cookie %s: %v", key, err)
+				return
+			}
+		}
+		if expect.Path != "" && expect.Path != cookie.Path {
+			err = fmt.Errorf("case: %s, expect the path of the cookie %s is %s, actual %s", name, key, expect.Path, cookie.Path)
+		} else if expect.Domain != "" && !strings.EqualFold(strings.TrimPrefix(expect.Domain, "."), strings.TrimPrefix(cookie.Domain, ".")) {
+			err = fmt.Errorf("case: %s, expect the domain of the cookie %s is %s, actual %s", name, key, expect.Domain, cookie.Domain)
+		} else if expect.HTTPOnly != nil && *expect.HTTPOnly != cookie.HttpOnly {
+			err = fmt.Errorf("case: %s, expect the httpOnly of the cookie %s is %v, actual %v", name, key, *expect.HTTPOnly, cookie.HttpOnly)
+		} else if expect.Secure != nil && *expect.Secure != cookie.Secure {
+			err = fmt.Errorf("case: %s, expect the secure of the cookie %s is %v, actual %v", name, key, *expect.Secure, cookie.Secure)
+		} else if sameSite := sameSiteName(cookie.SameSite); expect.SameSite != "" && !strings.EqualFold(expect.SameSite, sameSite) {
+			err = fmt.Errorf("case: %s, expect the sameSite of the cookie %s is %s, actual %s", name, key, expect.SameSite, sameSite)
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func sameSiteName(sameSite http.SameSite) string {
+	switch sameSite {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
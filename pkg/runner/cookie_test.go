@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/", HttpOnly: true, SameSite: http.SameSiteLaxMode})
+		default:
+			if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "abc123" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	httpOnly := true
+	login := &atest.TestCase{
+		Name:    "login",
+		Request: atest.Request{API: server.URL + "/login"},
+		Expect: atest.Response{
+			Cookies: map[string]atest.Cookie{
+				"session": {Value: "regex:^[a-z0-9]+$", Path: "/", HTTPOnly: &httpOnly, SameSite: "lax"},
+			},
+		},
+	}
+	profile := &atest.TestCase{
+		Name:    "profile",
+		Request: atest.Request{API: server.URL + "/profile"},
+	}
+
+	ctx := WithCookieJar(context.TODO())
+	_, err := NewSimpleTestCaseRunner().RunTestCase(login, nil, ctx)
+	assert.Nil(t, err)
+	_, err = NewSimpleTestCaseRunner().RunTestCase(profile, nil, ctx)
+	assert.Nil(t, err)
+
+	// the cookies are not sent without the jar
+	_, err = NewSimpleTestCaseRunner().RunTestCase(profile, nil, context.TODO())
+	assert.ErrorContains(t, err, "expect 200, actual 401")
+}
+
+func TestVerifyCookies(t *testing.T) {
+	yes, no := true, false
+	cookies := []*http.Cookie{{
+		Name:     "session",
+		Value:    "abc123",
+		Path:     "/",
+		Domain:   "example.com",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	}}
+
+	tests := []struct {
+		name   string
+		expect map[string]atest.Cookie
+		err    string
+	}{{
+		name:   "the existence",
+		expect: map[string]atest.Cookie{"session": {}},
+	}, {
+		name: "all the attributes",
+		expect: map[string]atest.Cookie{"session": {
+			Value: "abc123", Path: "/", Domain: ".example.com", HTTPOnly: &yes, Secure: &yes, SameSite: "Strict",
+		}},
+	}, {
+		name:   "not found",
+		expect: map[string]atest.Cookie{"token": {}},
+		err:    "not found the cookie token",
+	}, {
+		name:   "different value",
+		expect: map[string]atest.Cookie{"session": {Value: "regex:^\\d+$"}},
+		err:    "unexpected value of the cookie session",
+	}, {
+		name:   "different path",
+		expect: map[string]atest.Cookie{"session": {Path: "/api"}},
+		err:    "expect the path of the cookie session is /api, actual /",
+	}, {
+		name:   "different domain",
+		expect: map[string]atest.Cookie{"session": {Domain: "foo.com"}},
+		err:    "expect the domain of the cookie session is foo.com, actual example.com",
+	}, {
+		name:   "not httpOnly",
+		expect: map[string]atest.Cookie{"session": {HTTPOnly: &no}},
+		err:    "expect the httpOnly of the cookie session is false, actual true",
+	}, {
+		name:   "not secure",
+		expect: map[string]atest.Cookie{"session": {Secure: &no}},
+		err:    "expect the secure of the cookie session is false, actual true",
+	}, {
+		name:   "different sameSite",
+		expect: map[string]atest.Cookie{"session": {SameSite: "None"}},
+		err:    "expect the sameSite of the cookie session is None, actual Strict",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyCookies("case", tt.expect, cookies)
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/render"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// verifyDownload saves the body if it's required, then checks the length and the checksum of it.
+// The output has the length, the checksum, and the saved file
+func verifyDownload(name string, expect *testing.Download, body []byte, contextDir string, dataContext interface{}) (output interface{}, err error) {
+	checksum := sha256.Sum256(body)
+	result := map[string]interface{}{
+		"length": len(body),
+		"sha256": hex.EncodeToString(checksum[:]),
+	}
+
+	if expect.SaveTo != "" {
+		var file string
+		if file, err = saveBody(name, expect.SaveTo, body, contextDir, dataContext); err != nil {
+			return
+		}
+		result["file"] = file
+	}
+
+	switch {
+	case expect.Length > 0 && int64(len(body)) != expect.Length:
+		err = fmt.Errorf("case: %s, expect the length of the body: %d, actual: %d", name, expect.Length, len(body))
+	case expect.SHA256 != "" && !strings.EqualFold(expect.SHA256, result["sha256"].(string)):
+		err = fmt.Errorf("case: %s, expect the SHA-256 of the body: %s, actual: %s", name, expect.SHA256, result["sha256"])
+	}
+	output = result
+	return
+}
+
+// describeDownload is the brief of a binary body in the report
+func describeDownload(body []byte) string {
+	checksum := sha256.Sum256(body)
+	return fmt.Sprintf("<binary body: %d bytes, sha256: %s>", len(body), hex.EncodeToString(checksum[:]))
+}
+
+// saveBody writes the body into the templated file, it's relative to the directory of the test suite
+func saveBody(name, saveTo string, body []byte, contextDir string, dataContext interface{}) (file string, err error) {
+	if file, err = render.Render("save to", saveTo, dataContext); err != nil {
+		return
+	}
+	file = resolvePath(contextDir, file)
+	if err = writeBody(file, body); err != nil {
+		err = fmt.Errorf("case: %s, failed to save the body: %v", name, err)
+	}
+	return
+}
+
+// writeBody writes the body into the file, the parent directories are created if they don't exist
+func writeBody(file string, body []byte) (err error) {
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err == nil {
+		err = os.WriteFile(file, body, 0644)
+	}
+	return
+}
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownload(t *testing.T) {
+	// the SHA-256 of "hello"
+	const checksum = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	tests := []struct {
+		name     string
+		download *atest.Download
+		status   int
+		err      string
+	}{{
+		name:     "normal",
+		download: &atest.Download{Length: 5, SHA256: checksum},
+	}, {
+		name:     "upper case checksum",
+		download: &atest.Download{SHA256: "2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824"},
+	}, {
+		name:     "save to the file",
+		download: &atest.Download{SaveTo: "artifacts/{{.name}}.bin"},
+	}, {
+		name:     "unexpected length",
+		download: &atest.Download{Length: 6},
+		err:      "expect the length of the body: 6, actual: 5",
+	}, {
+		name:     "unexpected checksum",
+		download: &atest.Download{SHA256: "fake"},
+		err:      "expect the SHA-256 of the body: fake, actual: " + checksum,
+	}, {
+		name:     "unexpected status code",
+		download: &atest.Download{},
+		status:   404,
+		err:      "expect 200, actual 404",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			status := tt.status
+			if status == 0 {
+				status = 200
+			}
+			gock.New(urlLocalhost).Get("/files/hello").
+				Reply(status).SetHeader("Content-Type", "application/octet-stream").BodyString("hello")
+
+			dir := t.TempDir()
+			reporter := NewMemoryTestReporter()
+			ctx := context.WithValue(context.TODO(), NewContextKeyBuilder().ParentDir(), dir)
+			output, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+				Name:    tt.name,
+				Request: atest.Request{API: urlLocalhost + "/files/hello"},
+				Expect:  atest.Response{Download: tt.download},
+			}, map[string]interface{}{"name": "hello"}, ctx)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 5, output.(map[string]interface{})["length"])
+			assert.Equal(t, checksum, output.(map[string]interface{})["sha256"])
+
+			if records := reporter.GetAllRecords(); assert.Equal(t, 1, len(records)) {
+				assert.Equal(t, "<binary body: 5 bytes, sha256: "+checksum+">", records[0].Body)
+			}
+
+			if tt.download.SaveTo != "" {
+				file := filepath.Join(dir, "artifacts", "hello.bin")
+				assert.Equal(t, file, output.(map[string]interface{})["file"])
+				data, err := os.ReadFile(file)
+				assert.NoError(t, err)
+				assert.Equal(t, "hello", string(data))
+			}
+		})
+	}
+}
+
+func TestSaveTo(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlLocalhost).Get("/users").Times(2).
+		Reply(200).SetHeader("Content-Type", "application/json").BodyString(`{"name": "linuxsuren"}`)
+
+	dir := t.TempDir()
+	ctx := context.WithValue(context.TODO(), NewContextKeyBuilder().ParentDir(), dir)
+	_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Name:    "users",
+		Request: atest.Request{API: urlLocalhost + "/users"},
+		Expect: atest.Response{
+			SaveTo:           "out/{{.name}}.json",
+			BodyFieldsExpect: map[string]interface{}{"name": "linuxsuren"},
+		},
+	}, map[string]interface{}{"name": "users"}, ctx)
+	assert.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "out", "users.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name": "linuxsuren"}`, string(data))
+
+	// the body is saved even if it's not expected
+	file := filepath.Join(dir, "unexpected.json")
+	_, err = NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Name:    "users",
+		Request: atest.Request{API: urlLocalhost + "/users"},
+		Expect: atest.Response{
+			SaveTo:           file,
+			BodyFieldsExpect: map[string]interface{}{"name": "rick"},
+		},
+	}, nil, context.TODO())
+	assert.Error(t, err)
+	_, err = os.Stat(file)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,180 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/secret"
+)
+
+// DumpWriter returns the key of the writer which the requests and the responses are dumped into
+func (c ContextKey) DumpWriter() ContextKey {
+	return ContextKey("dumpWriter")
+}
+
+// CaseName returns the key of the name of the running test case
+func (c ContextKey) CaseName() ContextKey {
+	return ContextKey("caseName")
+}
+
+// WithDumpWriter returns a context with the writer, each HTTP request and its response are dumped into it as a JSON line
+func WithDumpWriter(ctx context.Context, writer io.Writer) context.Context {
+	return context.WithValue(ctx, NewContextKeyBuilder().DumpWriter(), &dumpWriter{writer: writer})
+}
+
+type dumpWriter struct {
+	writer io.Writer
+	lock   sync.Mutex
+}
+
+// exchange is the dump of a request and its response
+type exchange struct {
+	Time           string              `json:"time"`
+	Case           string              `json:"case,omitempty"`
+	Method         string              `json:"method"`
+	URL            string              `json:"url"`
+	RequestHeader  map[string][]string `json:"requestHeader,omitempty"`
+	RequestBody    string              `json:"requestBody,omitempty"`
+	Protocol       string              `json:"protocol,omitempty"`
+	StatusCode     int                 `json:"statusCode,omitempty"`
+	ResponseHeader map[string][]string `json:"responseHeader,omitempty"`
+	ResponseBody   string              `json:"responseBody,omitempty"`
+	Duration       int64               `json:"durationMs"`
+	Error          string              `json:"error,omitempty"`
+}
+
+// dumpExchange writes the headers and the bodies of the request and the response if there is a dump writer,
+// the secrets are redacted
+func dumpExchange(request *http.Request, resp *http.Response, body []byte, begin time.Time, err error) {
+	dumper, ok := request.Context().Value(NewContextKeyBuilder().DumpWriter()).(*dumpWriter)
+	if !ok {
+		return
+	}
+
+	item := exchange{
+		Time:          begin.UTC().Format(time.RFC3339Nano),
+		Case:          NewContextKeyBuilder().CaseName().GetContextValueOrEmpty(request.Context()),
+		Method:        request.Method,
+		URL:           request.URL.String(),
+		RequestHeader: request.Header,
+		RequestBody:   string(getRequestBody(request)),
+		Duration:      time.Since(begin).Milliseconds(),
+	}
+	if resp != nil {
+		item.Protocol = resp.Proto
+		item.StatusCode = resp.StatusCode
+		item.ResponseHeader = resp.Header
+		item.ResponseBody = string(body)
+	}
+	if err != nil {
+		item.Error = err.Error()
+	}
+
+	data, _ := json.Marshal(item)
+	dumper.lock.Lock()
+	defer dumper.lock.Unlock()
+	_, _ = fmt.Fprintln(dumper.writer, secret.Redact(string(data)))
+}
+
+// getRequestBody returns the body of the request without consuming it
+func getRequestBody(request *http.Request) (body []byte) {
+	if request.GetBody == nil {
+		return
+	}
+	if reader, err := request.GetBody(); err == nil {
+		defer func() {
+			_ = reader.Close()
+		}()
+		body, _ = io.ReadAll(reader)
+	}
+	return
+}
+
+type rotateWriter struct {
+	file       string
+	maxSize    int64
+	maxBackups int
+	lock       sync.Mutex
+	size       int64
+	current    *os.File
+}
+
+// NewRotateWriter creates a writer of the file, the file is rotated once its size exceeds the max size.
+// The backups are file.1, file.2 and so on, the oldest ones are removed if there are more than the max backups.
+// The existing file is rotated at the beginning, so the file only has the content of the current run
+func NewRotateWriter(file string, maxSize int64, maxBackups int) (writer io.WriteCloser, err error) {
+	w := &rotateWriter{file: file, maxSize: maxSize, maxBackups: maxBackups}
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return
+	}
+	if info, statErr := os.Stat(file); statErr == nil && info.Size() > 0 {
+		err = w.rotate()
+	} else {
+		err = w.open()
+	}
+	if err == nil {
+		writer = w
+	}
+	return
+}
+
+// Write writes the data, the file is rotated before writing if the data makes it exceed the max size
+func (w *rotateWriter) Write(data []byte) (n int, err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(data)) > w.maxSize {
+		if err = w.rotate(); err != nil {
+			return
+		}
+	}
+	n, err = w.current.Write(data)
+	w.size += int64(n)
+	return
+}
+
+// Close closes the current file
+func (w *rotateWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.current.Close()
+}
+
+// rotate moves the file to the first backup, and shifts the existing backups
+func (w *rotateWriter) rotate() (err error) {
+	if w.current != nil {
+		if err = w.current.Close(); err != nil {
+			return
+		}
+	}
+
+	if w.maxBackups <= 0 {
+		err = os.Remove(w.file)
+	} else {
+		_ = os.Remove(fmt.Sprintf("%s.%d", w.file, w.maxBackups))
+		for i := w.maxBackups - 1; i > 0; i-- {
+			if renameErr := os.Rename(fmt.Sprintf("%s.%d", w.file, i), fmt.Sprintf("%s.%d", w.file, i+1)); renameErr != nil && !os.IsNotExist(renameErr) {
+				err = renameErr
+				return
+			}
+		}
+		err = os.Rename(w.file, w.file+".1")
+	}
+	if err == nil {
+		err = w.open()
+	}
+	return
+}
+
+func (w *rotateWriter) open() (err error) {
+	w.current, err = os.OpenFile(w.file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	w.size = 0
+	return
+}
@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpExchange(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlFoo).Post("/").Reply(http.StatusCreated).SetHeader("X-Name", "linuxsuren").BodyString("created")
+
+	buf := new(bytes.Buffer)
+	ctx := WithDumpWriter(context.Background(), buf)
+	ctx = context.WithValue(ctx, NewContextKeyBuilder().CaseName(), "create")
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, urlFoo, strings.NewReader("hello"))
+	assert.NoError(t, err)
+	request.Header.Set("Accept", "text/plain")
+
+	_, body, err := doRequest(request, &atest.Request{})
+	assert.NoError(t, err)
+	assert.Equal(t, "created", string(body))
+
+	item := exchange{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &item))
+	assert.Equal(t, "create", item.Case)
+	assert.Equal(t, http.MethodPost, item.Method)
+	assert.Equal(t, urlFoo, item.URL)
+	assert.Equal(t, []string{"text/plain"}, item.RequestHeader["Accept"])
+	assert.Equal(t, "hello", item.RequestBody)
+	assert.Equal(t, http.StatusCreated, item.StatusCode)
+	assert.Equal(t, []string{"linuxsuren"}, item.ResponseHeader["X-Name"])
+	assert.Equal(t, "created", item.ResponseBody)
+
+	t.Run("failed request", func(t *testing.T) {
+		buf.Reset()
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, urlFoo, nil)
+		assert.NoError(t, err)
+		dumpExchange(request, nil, nil, time.Now(), errors.New("fake"))
+		assert.Contains(t, buf.String(), `"error":"fake"`)
+		assert.NotContains(t, buf.String(), "statusCode")
+	})
+
+	t.Run("no dump writer", func(t *testing.T) {
+		request, err := http.NewRequest(http.MethodGet, urlFoo, nil)
+		assert.NoError(t, err)
+		dumpExchange(request, nil, nil, time.Now(), nil)
+	})
+}
+
+func TestRotateWriter(t *testing.T) {
+	file := path.Join(t.TempDir(), "logs", "dump.log")
+	assert.NoError(t, os.MkdirAll(path.Dir(file), 0755))
+	assert.NoError(t, os.WriteFile(file, []byte("previous\n"), 0644))
+
+	writer, err := NewRotateWriter(file, 10, 2)
+	assert.NoError(t, err)
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = writer.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	// the previous run and the first line are removed since there are only two backups
+	assertFile(t, file, "fourth\n")
+	assertFile(t, file+".1", "third\n")
+	assertFile(t, file+".2", "second\n")
+	assert.NoFileExists(t, file+".3")
+
+	t.Run("no backups", func(t *testing.T) {
+		writer, err := NewRotateWriter(file, 0, 0)
+		assert.NoError(t, err)
+		_, err = writer.Write([]byte("fifth\n"))
+		assert.NoError(t, err)
+		assert.NoError(t, writer.Close())
+		assertFile(t, file, "fifth\n")
+	})
+
+	t.Run("invalid directory", func(t *testing.T) {
+		_, err := NewRotateWriter(path.Join(file, "fake"), 0, 0)
+		assert.Error(t, err)
+	})
+}
+
+func assertFile(t *testing.T, file, expect string) {
+	data, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, expect, string(data))
+}
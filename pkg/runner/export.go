@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ExportKey is the key of the exported values of the test cases in the data context
+const ExportKey = "cases"
+
+// exportSource is the response which the values are exported from
+type exportSource struct {
+	status int
+	// header returns the values of the key, the gRPC metadata keys are in lower case
+	header  func(key string) []string
+	cookies []*http.Cookie
+	body    []byte
+}
+
+// exportValues extracts the values of the response, then puts them into the data context if it's a map
+func exportValues(export map[string]string, source *exportSource, name string, dataContext interface{}) (err error) {
+	if len(export) == 0 {
+		return
+	}
+
+	values := make(map[string]interface{}, len(export))
+	for key, expression := range export {
+		if values[key], err = source.value(expression); err != nil {
+			err = fmt.Errorf("failed to export %s: %v", key, err)
+			return
+		}
+	}
+
+	if ctxMap, ok := dataContext.(map[string]interface{}); ok {
+		mergeContext(ctxMap, ExportKey, map[string]interface{}{name: values})
+	}
+	return
+}
+
+// value returns the status code, the body, a header, a cookie, or the value of a JSONPath.
+// The indefinite JSONPath returns all the matched values
+func (s *exportSource) value(expression string) (val interface{}, err error) {
+	switch {
+	case expression == "status":
+		val = s.status
+	case expression == "body":
+		val = string(s.body)
+	case strings.HasPrefix(expression, "header."):
+		key := strings.TrimPrefix(expression, "header.")
+		if values := s.header(key); len(values) > 0 {
+			val = strings.Join(values, ",")
+		} else {
+			err = fmt.Errorf("not found the header %s", key)
+		}
+	case strings.HasPrefix(expression, "cookie."):
+		key := strings.TrimPrefix(expression, "cookie.")
+		for _, cookie := range s.cookies {
+			if cookie.Name == key {
+				val = cookie.Value
+			}
+		}
+		if val == nil {
+			err = fmt.Errorf("not found the cookie %s", key)
+		}
+	case isJSONPath(expression):
+		var path *jsonPath
+		if path, err = parseJSONPath(expression); err != nil {
+			return
+		}
+
+		var data interface{}
+		if err = json.Unmarshal(s.body, &data); err != nil {
+			err = fmt.Errorf("the body is not JSON: %v", err)
+			return
+		}
+
+		values := path.query(data)
+		if len(values) == 0 {
+			err = fmt.Errorf("not found %s", expression)
+		} else if path.definite {
+			val = values[0]
+		} else {
+			val = values
+		}
+	default:
+		err = fmt.Errorf("unknown expression %s, it should be status, body, header.<name>, cookie.<name>, or a JSONPath", expression)
+	}
+	return
+}
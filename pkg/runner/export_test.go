@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportSourceValue(t *testing.T) {
+	header := http.Header{}
+	header.Add("X-Token", "abc")
+	source := &exportSource{
+		status:  http.StatusCreated,
+		header:  header.Values,
+		cookies: []*http.Cookie{{Name: "session", Value: "123"}},
+		body:    []byte(`{"id": 1, "items": [{"name": "foo"}, {"name": "bar"}]}`),
+	}
+
+	tests := []struct {
+		expression string
+		expect     interface{}
+		err        string
+	}{{
+		expression: "status",
+		expect:     http.StatusCreated,
+	}, {
+		expression: "body",
+		expect:     string(source.body),
+	}, {
+		expression: "header.x-token",
+		expect:     "abc",
+	}, {
+		expression: "cookie.session",
+		expect:     "123",
+	}, {
+		expression: "$.id",
+		expect:     float64(1),
+	}, {
+		expression: "$.items[*].name",
+		expect:     []interface{}{"foo", "bar"},
+	}, {
+		expression: "header.fake",
+		err:        "not found the header fake",
+	}, {
+		expression: "cookie.fake",
+		err:        "not found the cookie fake",
+	}, {
+		expression: "$.fake",
+		err:        "not found $.fake",
+	}, {
+		expression: "$.items[",
+		err:        "the bracket is not closed",
+	}, {
+		expression: "fake",
+		err:        "unknown expression fake",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			val, err := source.value(tt.expression)
+			if tt.err == "" {
+				assert.Nil(t, err)
+				assert.Equal(t, tt.expect, val)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+
+	_, err := (&exportSource{body: []byte("foo")}).value("$.id")
+	assert.ErrorContains(t, err, "the body is not JSON")
+}
+
+func TestExportValues(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlLocalhost).Get("/foo").Reply(http.StatusOK).SetHeader("X-Token", "abc").BodyString(`{"id": 1}`)
+
+	dataContext := map[string]interface{}{
+		ExportKey: map[string]interface{}{"other": map[string]interface{}{"id": 2}},
+	}
+	_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Name:    "foo",
+		Request: atest.Request{API: urlFoo},
+		Export: map[string]string{
+			"id":    "$.id",
+			"token": "header.X-Token",
+		},
+	}, dataContext, context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"other": map[string]interface{}{"id": 2},
+		"foo":   map[string]interface{}{"id": float64(1), "token": "abc"},
+	}, dataContext[ExportKey])
+
+	gock.New(urlLocalhost).Get("/foo").Reply(http.StatusOK).BodyString(`{}`)
+	_, err = NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Name:    "foo",
+		Request: atest.Request{API: urlFoo},
+		Export:  map[string]string{"id": "$.id"},
+	}, dataContext, context.TODO())
+	assert.ErrorContains(t, err, "failed to export id: not found $.id")
+}
@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/fuzz"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const defaultFuzzIterations = 10
+
+// runFuzz sends the mutated bodies of the rendered request, the responses which are server errors or violate
+// the schema of the expect are flagged. Each iteration is a record of the report, the method of it is FUZZ.
+func (r *simpleTestCaseRunner) runFuzz(ctx context.Context, testcase *testing.TestCase) (err error) {
+	options := testcase.Fuzz
+	iterations := options.Iterations
+	if iterations <= 0 {
+		iterations = defaultFuzzIterations
+	}
+	seed := options.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	mutations := make([]fuzz.Mutation, 0, len(options.Mutations))
+	for _, mutation := range options.Mutations {
+		mutations = append(mutations, fuzz.Mutation(mutation))
+	}
+	var mutator fuzz.Mutator
+	if mutator, err = fuzz.NewMutator(seed, mutations...); err != nil {
+		return
+	}
+
+	var body string
+	var reader io.Reader
+	if reader, err = testcase.Request.GetBody(); err != nil {
+		return
+	} else if reader != nil {
+		var data []byte
+		if data, err = io.ReadAll(reader); err != nil {
+			return
+		}
+		body = string(data)
+	}
+
+	r.log.Info("fuzz: send %d mutated bodies to %s with seed %d\n", iterations, testcase.Request.API, seed)
+	var failed int
+	var firstErr error
+	for i := 0; i < iterations && ctx.Err() == nil; i++ {
+		mutated, description := mutator.Mutate(body)
+		record := NewReportRecord()
+		record.Name = testcase.Name
+		record.Method = "FUZZ"
+		record.API = testcase.Request.API
+
+		if record.Error = r.fuzzOnce(ctx, &testcase.Request, &testcase.Expect, mutated); record.Error != nil {
+			record.Error = fmt.Errorf("%s: %v", description, record.Error)
+			record.Body = record.Error.Error()
+			r.log.Warn("fuzz: %v\n", record.Error)
+
+			failed++
+			if firstErr == nil {
+				firstErr = record.Error
+			}
+		} else {
+			r.log.Debug("fuzz: %s is passed\n", description)
+		}
+		record.EndTime = time.Now()
+		r.putRecord(ctx, record)
+	}
+
+	if failed > 0 {
+		err = fmt.Errorf("fuzz: %d of %d iterations are flagged with seed %d, the first one is %v", failed, iterations, seed, firstErr)
+	}
+	return
+}
+
+// fuzzOnce sends the mutated body, returns an error if the response is a server error or violates the schema
+func (r *simpleTestCaseRunner) fuzzOnce(ctx context.Context, req *testing.Request, expect *testing.Response, body string) (err error) {
+	var request *http.Request
+	if request, err = http.NewRequestWithContext(ctx, req.Method, req.API, strings.NewReader(body)); err != nil {
+		return
+	}
+	for key, val := range req.Header {
+		request.Header.Add(key, val)
+	}
+
+	var resp *http.Response
+	var data []byte
+	waitRateLimit(ctx)
+	if resp, data, err = doRequest(request, req); err != nil {
+		return
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		err = fmt.Errorf("server error %d, %s", resp.StatusCode, string(data))
+	} else if expect.Schema != "" && resp.StatusCode < http.StatusMultipleChoices {
+		if schemaErr := jsonSchemaValidation(expect.Schema, data); schemaErr != nil {
+			err = fmt.Errorf("violate the schema, %v", schemaErr)
+		}
+	}
+	return
+}
@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzz(t *testing.T) {
+	const schema = `{"type": "object", "required": ["id"]}`
+	tests := []struct {
+		name    string
+		fuzz    *atest.Fuzz
+		prepare func()
+		expect  string
+		records int
+	}{{
+		name: "passed",
+		fuzz: &atest.Fuzz{Iterations: 3, Seed: 1},
+		prepare: func() {
+			gock.New(urlLocalhost).Post("/foo").Times(3).Reply(http.StatusBadRequest).BodyString(`{"message":"invalid"}`)
+		},
+		records: 3,
+	}, {
+		name: "server error",
+		fuzz: &atest.Fuzz{Iterations: 2, Seed: 1, Mutations: []string{"injection"}},
+		prepare: func() {
+			gock.New(urlLocalhost).Post("/foo").Times(2).Reply(http.StatusInternalServerError).BodyString(`oops`)
+		},
+		expect:  "fuzz: 2 of 2 iterations are flagged with seed 1, the first one is injection at $",
+		records: 2,
+	}, {
+		name: "violate the schema",
+		fuzz: &atest.Fuzz{Iterations: 1, Seed: 1},
+		prepare: func() {
+			gock.New(urlLocalhost).Post("/foo").Reply(http.StatusOK).BodyString(`{"message":"ok"}`)
+		},
+		expect:  "violate the schema",
+		records: 1,
+	}, {
+		name:   "unsupported mutation",
+		fuzz:   &atest.Fuzz{Mutations: []string{"fake"}},
+		expect: "unsupported mutation 'fake'",
+	}, {
+		name: "default iterations",
+		fuzz: &atest.Fuzz{},
+		prepare: func() {
+			gock.New(urlLocalhost).Post("/foo").Times(defaultFuzzIterations).Reply(http.StatusOK).BodyString(`{"id":1}`)
+		},
+		records: defaultFuzzIterations,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New(urlLocalhost).Post("/foo").BodyString(`{"name":"linuxsuren","age":18}`).
+				Reply(http.StatusOK).BodyString(`{"id":1}`)
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+
+			reporter := NewMemoryTestReporter()
+			_, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+				Request: atest.Request{
+					API:    urlFoo,
+					Method: http.MethodPost,
+					Body:   `{"name":"linuxsuren","age":18}`,
+				},
+				Expect: atest.Response{Schema: schema},
+				Fuzz:   tt.fuzz,
+			}, nil, context.TODO())
+			if tt.expect == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expect)
+			}
+
+			var records int
+			for _, record := range reporter.(*memoryTestReporter).records {
+				if record.Method == "FUZZ" {
+					records++
+				}
+			}
+			assert.Equal(t, tt.records, records)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// graphQLResponse is the standard response of GraphQL
+type graphQLResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// verifyGraphQL checks the errors and the data of the GraphQL response, the response should not have errors
+// unless they're expected
+func verifyGraphQL(name string, expect *testing.GraphQLResponse, body []byte) (err error) {
+	resp := &graphQLResponse{}
+	if err = json.Unmarshal(body, resp); err != nil {
+		err = fmt.Errorf("case: %s, invalid GraphQL response: %v", name, err)
+		return
+	}
+	if expect == nil {
+		expect = &testing.GraphQLResponse{}
+	}
+
+	messages := make([]string, 0, len(resp.Errors))
+	for _, item := range resp.Errors {
+		messages = append(messages, item.Message)
+	}
+
+	if len(expect.Errors) == 0 && len(messages) > 0 {
+		err = fmt.Errorf("case: %s, unexpected GraphQL errors: %s", name, strings.Join(messages, "; "))
+		return
+	}
+	for _, expected := range expect.Errors {
+		if !containsMessage(messages, expected) {
+			err = fmt.Errorf("case: %s, expect the GraphQL error '%s', actual: %s", name, expected, strings.Join(messages, "; "))
+			return
+		}
+	}
+
+	if err = verifyFields(resp.Data, expect.Data); err != nil {
+		err = fmt.Errorf("case: %s, GraphQL data %v", name, err)
+	}
+	return
+}
+
+func containsMessage(messages []string, expected string) bool {
+	for _, message := range messages {
+		if strings.Contains(message, expected) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQL(t *testing.T) {
+	const query = `{"operationName":"user","query":"query user($id: ID!) { user(id: $id) { name } }","variables":{"id":"1"}}`
+
+	tests := []struct {
+		name   string
+		body   string
+		expect *atest.GraphQLResponse
+		err    string
+	}{{
+		name:   "normal",
+		body:   `{"data":{"user":{"name":"linuxsuren"}}}`,
+		expect: &atest.GraphQLResponse{Data: map[string]interface{}{"user/name": "linuxsuren"}},
+	}, {
+		name: "unexpected errors",
+		body: `{"data":null,"errors":[{"message":"user not found"}]}`,
+		err:  "unexpected GraphQL errors: user not found",
+	}, {
+		name:   "expected errors",
+		body:   `{"data":null,"errors":[{"message":"user not found"}]}`,
+		expect: &atest.GraphQLResponse{Errors: []string{"not found"}},
+	}, {
+		name:   "missing the expected errors",
+		body:   `{"data":{"user":{"name":"linuxsuren"}}}`,
+		expect: &atest.GraphQLResponse{Errors: []string{"not found"}},
+		err:    "expect the GraphQL error 'not found'",
+	}, {
+		name:   "unexpected data",
+		body:   `{"data":{"user":{"name":"rick"}}}`,
+		expect: &atest.GraphQLResponse{Data: map[string]interface{}{"user/name": "linuxsuren"}},
+		err:    "GraphQL data field[user/name] expect value: linuxsuren, actual: rick",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New(urlLocalhost).Post("/graphql").MatchType("json").BodyString(query).
+				Reply(http.StatusOK).BodyString(tt.body)
+
+			_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+				Request: atest.Request{
+					API: urlLocalhost + "/graphql",
+					GraphQL: &atest.GraphQL{
+						Query:         `query user($id: ID!) { user(id: $id) { name } }`,
+						OperationName: "user",
+						Variables:     map[string]interface{}{"id": "{{.id}}"},
+					},
+				},
+				Expect: atest.Response{GraphQL: tt.expect},
+			}, map[string]interface{}{"id": "1"}, context.TODO())
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// grpcResponse is the result of a gRPC call, the body is the JSON form of the response message,
+// or the google.rpc.Status if the code is not OK
+type grpcResponse struct {
+	code   codes.Code
+	header metadata.MD
+	body   []byte
+	// duration is the time of the call, the connecting and the reflection are not included
+	duration time.Duration
+}
+
+// runGRPC calls the unary gRPC method of the test case, then verifies the status code, the header metadata and the response message
+func (r *simpleTestCaseRunner) runGRPC(ctx context.Context, testcase *testing.TestCase, dataContext interface{},
+	contextDir string, record *ReportRecord) (output interface{}, err error) {
+	request := &testcase.Request
+	if err = request.Render(dataContext, contextDir); err != nil {
+		return
+	}
+
+	var maxResponseTime time.Duration
+	if maxResponseTime, err = parseMaxResponseTime(&testcase.Expect); err != nil {
+		return
+	}
+
+	r.log.Info("start to call %s/%s of %s\n", request.GRPC.Service, request.GRPC.Method, request.API)
+
+	var resp *grpcResponse
+	if resp, err = invokeGRPC(ctx, request, contextDir); err != nil {
+		return
+	}
+	record.ResponseTime = resp.duration
+	record.Body = string(resp.body)
+	r.log.Debug("response body: %s\n", record.Body)
+
+	if err = expectResponseTime(testcase.Name, maxResponseTime, record); err != nil {
+		return
+	}
+
+	if output, err = verifyGRPCResponse(testcase.Name, &testcase.Expect, resp); err == nil {
+		err = exportValues(testcase.Export, &exportSource{
+			status: int(resp.code),
+			header: resp.header.Get,
+			body:   resp.body,
+		}, testcase.Name, dataContext)
+	}
+	return
+}
+
+// invokeGRPC calls the method with the JSON body, the descriptors come from the protoset file or the server reflection
+func invokeGRPC(ctx context.Context, request *testing.Request, contextDir string) (resp *grpcResponse, err error) {
+	options := request.GRPC
+	credential := insecure.NewCredentials()
+	if options.TLS || request.TLS != nil {
+		var tlsConfig *tls.Config
+		if tlsConfig, err = newTLSConfig(request.TLS, contextDir); err != nil {
+			return
+		}
+		credential = credentials.NewTLS(tlsConfig)
+	}
+
+	var conn *grpc.ClientConn
+	if conn, err = grpc.DialContext(ctx, request.API, grpc.WithTransportCredentials(credential)); err != nil {
+		err = fmt.Errorf("failed to connect %s: %v", request.API, err)
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	var files *protoregistry.Files
+	if options.ProtoSet != "" {
+		files, err = loadProtoSet(resolvePath(contextDir, options.ProtoSet))
+	} else {
+		files, err = reflectFiles(ctx, conn, options.Service)
+	}
+	if err != nil {
+		return
+	}
+
+	var method protoreflect.MethodDescriptor
+	if method, err = findMethod(files, options.Service, options.Method); err != nil {
+		return
+	}
+
+	input := dynamicpb.NewMessage(method.Input())
+	if strings.TrimSpace(request.Body) != "" {
+		if err = protojson.Unmarshal([]byte(request.Body), input); err != nil {
+			err = fmt.Errorf("invalid request message of %s: %v", method.FullName(), err)
+			return
+		}
+	}
+
+	if len(request.Header) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(request.Header))
+	}
+
+	resp = &grpcResponse{}
+	reply := dynamicpb.NewMessage(method.Output())
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+	begin := time.Now()
+	callErr := conn.Invoke(ctx, fullMethod, input, reply, grpc.Header(&resp.header))
+	resp.duration = time.Since(begin)
+	if callErr != nil {
+		callStatus := status.Convert(callErr)
+		resp.code = callStatus.Code()
+		resp.body, err = protojson.Marshal(callStatus.Proto())
+	} else {
+		resp.body, err = protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(reply)
+	}
+
+	// the output of protojson is unstable on purpose, it's compacted for the comparison of the body
+	if err == nil {
+		buf := new(bytes.Buffer)
+		if err = json.Compact(buf, resp.body); err == nil {
+			resp.body = buf.Bytes()
+		}
+	}
+	return
+}
+
+// findMethod returns the unary method of the service, the streaming methods are not supported
+func findMethod(files *protoregistry.Files, service, name string) (method protoreflect.MethodDescriptor, err error) {
+	var descriptor protoreflect.Descriptor
+	if descriptor, err = files.FindDescriptorByName(protoreflect.FullName(service)); err != nil {
+		err = fmt.Errorf("cannot find the service %s: %v", service, err)
+		return
+	}
+
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		err = fmt.Errorf("%s is not a service", service)
+		return
+	}
+
+	if method = serviceDescriptor.Methods().ByName(protoreflect.Name(name)); method == nil {
+		err = fmt.Errorf("cannot find the method %s of %s", name, service)
+	} else if method.IsStreamingClient() || method.IsStreamingServer() {
+		err = fmt.Errorf("the streaming method %s of %s is not supported", name, service)
+	}
+	return
+}
+
+// loadProtoSet parses the FileDescriptorSet file, it should include the imports
+func loadProtoSet(file string) (files *protoregistry.Files, err error) {
+	var data []byte
+	if data, err = os.ReadFile(file); err != nil {
+		return
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err = proto.Unmarshal(data, set); err != nil {
+		err = fmt.Errorf("invalid protoset %s: %v", file, err)
+		return
+	}
+	files, err = protodesc.NewFiles(set)
+	return
+}
+
+// reflectFiles fetches the file which contains the symbol and the dependencies of it via the server reflection
+func reflectFiles(ctx context.Context, conn *grpc.ClientConn, symbol string) (files *protoregistry.Files, err error) {
+	var stream rpb.ServerReflection_ServerReflectionInfoClient
+	if stream, err = rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx); err != nil {
+		err = fmt.Errorf("failed to call the server reflection: %v", err)
+		return
+	}
+	defer func() {
+		_ = stream.CloseSend()
+	}()
+
+	set := &descriptorpb.FileDescriptorSet{}
+	fetched, requested := map[string]bool{}, map[string]bool{}
+	pending := []*rpb.ServerReflectionRequest{{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	}}
+	for len(pending) > 0 {
+		if err = stream.Send(pending[0]); err != nil {
+			return
+		}
+		pending = pending[1:]
+
+		var resp *rpb.ServerReflectionResponse
+		if resp, err = stream.Recv(); err != nil {
+			err = fmt.Errorf("failed to call the server reflection: %v", err)
+			return
+		}
+		if errResp := resp.GetErrorResponse(); errResp != nil {
+			err = fmt.Errorf("failed to find %s via the server reflection: %s", symbol, errResp.GetErrorMessage())
+			return
+		}
+
+		for _, data := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			file := &descriptorpb.FileDescriptorProto{}
+			if err = proto.Unmarshal(data, file); err != nil {
+				return
+			}
+			if fetched[file.GetName()] {
+				continue
+			}
+			fetched[file.GetName()] = true
+			set.File = append(set.File, file)
+		}
+
+		// the dependencies might be sent already, only the missing ones are requested
+		for _, file := range set.File {
+			for _, dependency := range file.GetDependency() {
+				if !fetched[dependency] && !requested[dependency] {
+					requested[dependency] = true
+					pending = append(pending, &rpb.ServerReflectionRequest{
+						MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: dependency},
+					})
+				}
+			}
+		}
+	}
+	files, err = protodesc.NewFiles(set)
+	return
+}
+
+// verifyGRPCResponse checks the status code, the header metadata, the body and the JSON schema of the response,
+// the status code is the code of gRPC, it's OK if the expected one is zero
+func verifyGRPCResponse(name string, expect *testing.Response, resp *grpcResponse) (output interface{}, err error) {
+	if expected := codes.Code(expect.StatusCode); expected != resp.code {
+		err = fmt.Errorf("case: %s, expect the status code %v, actual %v, body: %s", name, expected, resp.code, string(resp.body))
+		return
+	}
+
+	for key, val := range expect.Header {
+		if err = expectString(name, val, strings.Join(resp.header.Get(key), ",")); err != nil {
+			return
+		}
+	}
+
+	if output, err = verifyResponseBodyData(name, *expect, resp.body); err != nil {
+		return
+	}
+
+	err = jsonSchemaValidation(expect.Schema, resp.body)
+	return
+}
@@ -0,0 +1,171 @@
+package runner
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestRunGRPC(t *testing.T) {
+	listener, err := startHealthServer(t)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	dir := t.TempDir()
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(grpc_health_v1.File_grpc_health_v1_health_proto),
+	}})
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "health.protoset"), data, 0644))
+
+	tests := []struct {
+		name    string
+		request atest.Request
+		expect  atest.Response
+		err     string
+	}{{
+		name: "via the server reflection",
+		request: atest.Request{
+			GRPC: &atest.GRPC{Service: "grpc.health.v1.Health", Method: "Check"},
+			Body: `{"service": ""}`,
+		},
+		expect: atest.Response{
+			BodyFieldsExpect: map[string]interface{}{"status": "SERVING"},
+		},
+	}, {
+		name: "via the protoset",
+		request: atest.Request{
+			GRPC: &atest.GRPC{Service: "grpc.health.v1.Health", Method: "Check", ProtoSet: "health.protoset"},
+		},
+		expect: atest.Response{
+			Verify: []string{`data.status == "SERVING"`},
+		},
+	}, {
+		name: "expected status code",
+		request: atest.Request{
+			GRPC: &atest.GRPC{Service: "grpc.health.v1.Health", Method: "Check"},
+			Body: `{"service": "fake"}`,
+		},
+		expect: atest.Response{
+			StatusCode:       5,
+			BodyFieldsExpect: map[string]interface{}{"message": "unknown service"},
+		},
+	}, {
+		name: "unexpected status code",
+		request: atest.Request{
+			GRPC: &atest.GRPC{Service: "grpc.health.v1.Health", Method: "Check"},
+			Body: `{"service": "fake"}`,
+		},
+		err: "expect the status code OK, actual NotFound",
+	}, {
+		name: "invalid request message",
+		request: atest.Request{
+			GRPC: &atest.GRPC{Service: "grpc.health.v1.Health", Method: "Check"},
+			Body: `{"fake": ""}`,
+		},
+		err: "invalid request message of grpc.health.v1.Health.Check",
+	}, {
+		name: "streaming method",
+		request: atest.Request{
+			GRPC: &atest.GRPC{Service: "grpc.health.v1.Health", Method: "Watch"},
+		},
+		err: "the streaming method Watch of grpc.health.v1.Health is not supported",
+	}, {
+		name: "unknown method",
+		request: atest.Request{
+			GRPC: &atest.GRPC{Service: "grpc.health.v1.Health", Method: "Fake"},
+		},
+		err: "cannot find the method Fake",
+	}, {
+		name: "unknown service",
+		request: atest.Request{
+			GRPC: &atest.GRPC{Service: "fake.Service", Method: "Fake"},
+		},
+		err: "failed to find fake.Service via the server reflection",
+	}, {
+		name: "protoset not found",
+		request: atest.Request{
+			GRPC: &atest.GRPC{Service: "grpc.health.v1.Health", Method: "Check", ProtoSet: "fake.protoset"},
+		},
+		err: "fake.protoset",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.API = listener.Addr().String()
+			reporter := NewMemoryTestReporter()
+			ctx := context.WithValue(context.TODO(), NewContextKeyBuilder().ParentDir(), dir)
+			_, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+				Name:    tt.name,
+				Request: tt.request,
+				Expect:  tt.expect,
+			}, nil, ctx)
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+
+			if records := reporter.GetAllRecords(); assert.Equal(t, 1, len(records)) {
+				assert.Equal(t, "GRPC", records[0].Method)
+				assert.Equal(t, listener.Addr().String()+"/grpc.health.v1.Health/"+tt.request.GRPC.Method, records[0].API)
+			}
+		})
+	}
+}
+
+func TestRunGRPCBeforeJob(t *testing.T) {
+	listener, err := startHealthServer(t)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	count := 0
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+	runner.jobFunctions = map[string]func(params ...interface{}) (interface{}, error){
+		"count": func(params ...interface{}) (interface{}, error) {
+			count++
+			return count, nil
+		},
+	}
+
+	_, err = runner.RunTestCase(&atest.TestCase{
+		Name:   "health",
+		Before: atest.Job{Items: []string{"count()"}},
+		Request: atest.Request{
+			API:  listener.Addr().String(),
+			GRPC: &atest.GRPC{Service: "grpc.health.v1.Health", Method: "Check"},
+		},
+	}, nil, context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+}
+
+// startHealthServer starts a gRPC server with the health service and the server reflection, it's stopped when the test finished
+func startHealthServer(t *testing.T) (listener net.Listener, err error) {
+	if listener, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+		return
+	}
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return
+}
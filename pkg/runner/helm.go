@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// installHelm installs or upgrades the release, and waits until the resources are ready
+func (r *simpleTestCaseRunner) installHelm(ctx context.Context, item testing.Helm, contextDir string) (err error) {
+	var timeout time.Duration
+	if timeout, err = parseDurationOrDefault(item.Timeout, defaultKubernetesWaitTimeout); err != nil {
+		return
+	}
+
+	chart := item.Chart
+	if strings.HasPrefix(chart, ".") {
+		chart = resolvePath(contextDir, chart)
+	}
+
+	args := []string{"upgrade", item.Release, chart, "--install", "--create-namespace", "--wait", "--timeout", timeout.String()}
+	if item.Version != "" {
+		args = append(args, "--version", item.Version)
+	}
+	for _, values := range item.Values {
+		args = append(args, "--values", resolvePath(contextDir, values))
+	}
+
+	keys := make([]string, 0, len(item.Set))
+	for key := range item.Set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "--set", fmt.Sprintf("%s=%s", key, item.Set[key]))
+	}
+
+	r.log.Info("prepare: install %s from %s\n", item.Release, item.Chart)
+	if err = r.runCLI(ctx, "helm", helmArgs(item, args...)); err != nil {
+		err = fmt.Errorf("failed to install %s: %v", item.Release, err)
+	}
+	return
+}
+
+// uninstallHelm uninstalls the release
+func (r *simpleTestCaseRunner) uninstallHelm(ctx context.Context, item testing.Helm) (err error) {
+	if err = r.runCLI(ctx, "helm", helmArgs(item, "uninstall", item.Release, "--ignore-not-found")); err != nil {
+		err = fmt.Errorf("failed to uninstall %s: %v", item.Release, err)
+	}
+	return
+}
+
+// helmArgs appends the global flags after the arguments
+func helmArgs(item testing.Helm, args ...string) []string {
+	if item.Kubeconfig != "" {
+		args = append(args, "--kubeconfig", item.Kubeconfig)
+	}
+	if item.Context != "" {
+		args = append(args, "--kube-context", item.Context)
+	}
+	if item.Namespace != "" {
+		args = append(args, "--namespace", item.Namespace)
+	}
+	return args
+}
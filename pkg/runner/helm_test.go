@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelm(t *testing.T) {
+	item := atest.Helm{
+		Chart:   "./chart",
+		Release: "demo",
+		Version: "1.0.0",
+		Values:  []string{"values.yaml"},
+		Set:     map[string]string{"image.tag": "v1"},
+	}
+
+	executor := &exec.FakeExecutor{}
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+	runner.executor = executor
+	assert.Nil(t, runner.installHelm(context.TODO(), item, "suites"))
+	assert.Nil(t, runner.uninstallHelm(context.TODO(), item))
+	assert.Equal(t, []exec.Command{{
+		Name: "helm",
+		Args: []string{"upgrade", "demo", filepath.Join("suites", "chart"), "--install", "--create-namespace", "--wait",
+			"--timeout", "5m0s", "--version", "1.0.0", "--values", filepath.Join("suites", "values.yaml"), "--set", "image.tag=v1"},
+	}, {
+		Name: "helm",
+		Args: []string{"uninstall", "demo", "--ignore-not-found"},
+	}}, executor.Commands)
+
+	item.Timeout = "fake"
+	assert.NotNil(t, runner.installHelm(context.TODO(), item, "suites"))
+
+	runner.executor = &exec.FakeExecutor{Err: errors.New("fake")}
+	item.Timeout = ""
+	assert.ErrorContains(t, runner.installHelm(context.TODO(), item, "suites"), "failed to install demo")
+	assert.ErrorContains(t, runner.uninstallHelm(context.TODO(), item), "failed to uninstall demo")
+
+	runner.executor = &exec.FakeExecutor{ExitCode: 1, Output: "chart not found"}
+	assert.ErrorContains(t, runner.installHelm(context.TODO(), item, "suites"), "unexpected exit code 1, output: chart not found")
+}
+
+func TestHelmArgs(t *testing.T) {
+	assert.Equal(t, []string{"uninstall", "demo"}, helmArgs(atest.Helm{}, "uninstall", "demo"))
+	assert.Equal(t, []string{"uninstall", "demo", "--kubeconfig", "config", "--kube-context", "kind", "--namespace", "demo"},
+		helmArgs(atest.Helm{
+			Kubeconfig: "config",
+			Context:    "kind",
+			Namespace:  "demo",
+		}, "uninstall", "demo"))
+}
@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"context"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// HookKey is the key of the outputs of the named hook steps in the data context, they're referenced as {{.hooks.<name>}}
+const HookKey = "hooks"
+
+const (
+	hookBefore = "before"
+	hookAfter  = "after"
+)
+
+// runHook runs the expressions, the HTTP requests and the commands of the hook one by one.
+// The outputs of the named steps are returned. All the steps of the after hook run even if some
+// of them are failed or the context is canceled, the first error will be returned.
+func (r *simpleTestCaseRunner) runHook(ctx context.Context, phase string, job testing.Job, contextDir string,
+	dataContext interface{}) (outputs map[string]interface{}, err error) {
+	after := phase == hookAfter
+	if after {
+		ctx = detachedContext{ctx}
+	}
+	// next keeps the first error, and tells if the next step should run
+	next := func(stepErr error) bool {
+		if stepErr != nil && err == nil {
+			err = stepErr
+		}
+		return err == nil || after
+	}
+	setOutput := func(name string, output interface{}) {
+		if name != "" {
+			if outputs == nil {
+				outputs = map[string]interface{}{}
+			}
+			outputs[name] = output
+		}
+	}
+
+	if !next(r.runJob(job)) {
+		return
+	}
+
+	for _, step := range job.HTTP {
+		step := step
+		var output interface{}
+		stepErr := r.runStep(ctx, phase, httpStepName(step), step.Policy, func(stepCtx context.Context) (stepErr error) {
+			output, stepErr = r.runHTTPStep(stepCtx, phase, step, contextDir, withHooks(dataContext, outputs))
+			return
+		})
+		if stepErr == nil {
+			setOutput(step.Name, output)
+		}
+		if !next(stepErr) {
+			return
+		}
+	}
+
+	for _, command := range job.Commands {
+		command := command
+		var stdout string
+		stepErr := r.runStep(ctx, phase, commandName(command), command.Policy, func(stepCtx context.Context) (stepErr error) {
+			stdout, stepErr = r.runCommand(stepCtx, phase, command, contextDir)
+			return
+		})
+		if stepErr == nil {
+			setOutput(command.Name, parseOutput(stdout))
+		}
+		if !next(stepErr) {
+			return
+		}
+	}
+	return
+}
+
+// withHooks puts the outputs of the hooks into the data context if it's a map or nil
+func withHooks(dataContext interface{}, outputs map[string]interface{}) interface{} {
+	if len(outputs) == 0 {
+		return dataContext
+	}
+
+	ctxMap, ok := dataContext.(map[string]interface{})
+	if !ok {
+		if dataContext != nil {
+			return dataContext
+		}
+		ctxMap = map[string]interface{}{}
+	}
+	mergeContext(ctxMap, HookKey, outputs)
+	return ctxMap
+}
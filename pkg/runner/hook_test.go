@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaseHooks(t *testing.T) {
+	newTestCase := func(status int) *atest.TestCase {
+		return &atest.TestCase{
+			Name: "users",
+			Before: atest.Job{
+				HTTP: []atest.HTTPStep{{
+					Name:    "login",
+					Request: atest.Request{API: urlLocalhost + "/login", Method: http.MethodPost},
+				}},
+			},
+			After: atest.Job{
+				Commands: []atest.Command{{Command: "delete", Args: []string{"users"}}, {Command: "reset"}},
+			},
+			Request: atest.Request{
+				API:    urlLocalhost + "/users",
+				Header: map[string]string{"Authorization": "Bearer {{.hooks.login.token}}"},
+			},
+			Expect: atest.Response{StatusCode: status},
+		}
+	}
+
+	t.Run("normal", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlLocalhost).Post("/login").Reply(http.StatusOK).BodyString(`{"token":"abc"}`)
+		gock.New(urlLocalhost).Get("/users").MatchHeader("Authorization", "Bearer abc").
+			Reply(http.StatusOK).BodyString(`{}`)
+
+		executor := &exec.FakeExecutor{}
+		runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+		runner.executor = executor
+		_, err := runner.RunTestCase(newTestCase(http.StatusOK), nil, context.TODO())
+		assert.NoError(t, err)
+		assert.True(t, gock.IsDone())
+		assert.Equal(t, 2, len(executor.Commands))
+	})
+
+	t.Run("the after hook runs even if the case is failed", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlLocalhost).Post("/login").Reply(http.StatusOK).BodyString(`{"token":"abc"}`)
+		gock.New(urlLocalhost).Get("/users").Reply(http.StatusOK).BodyString(`{}`)
+
+		executor := &exec.FakeExecutor{Err: errors.New("fake")}
+		runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+		runner.executor = executor
+		_, err := runner.RunTestCase(newTestCase(http.StatusCreated), nil, context.TODO())
+		assert.ErrorContains(t, err, "expect 201, actual 200")
+		// all the steps of the after hook run even if some of them are failed
+		assert.Equal(t, 2, len(executor.Commands))
+	})
+
+	t.Run("failed before hook", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlLocalhost).Post("/login").Reply(http.StatusUnauthorized)
+
+		executor := &exec.FakeExecutor{}
+		runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+		runner.executor = executor
+		_, err := runner.RunTestCase(newTestCase(http.StatusOK), nil, context.TODO())
+		assert.ErrorContains(t, err, "expect 200, actual 401")
+		assert.Equal(t, 2, len(executor.Commands))
+	})
+}
+
+func TestSuiteHooks(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlLocalhost).Post("/login").Reply(http.StatusOK).BodyString(`{"token":"abc"}`)
+	gock.New(urlLocalhost).Delete("/sessions/abc").Reply(http.StatusNoContent)
+
+	suite := &atest.TestSuite{
+		Name: "suite",
+		Before: atest.Job{
+			HTTP: []atest.HTTPStep{{
+				Name:    "login",
+				Request: atest.Request{API: urlLocalhost + "/login", Method: http.MethodPost},
+			}},
+		},
+		After: atest.Job{
+			HTTP: []atest.HTTPStep{{
+				Request: atest.Request{API: urlLocalhost + "/sessions/{{.hooks.login.token}}", Method: http.MethodDelete},
+				Expect:  atest.Response{StatusCode: http.StatusNoContent},
+			}},
+		},
+		Clean: atest.Clean{CleanPolicy: atest.CleanPolicyNever},
+	}
+
+	suiteRunner := NewSuiteRunner(io.Discard, "info", fakeruntime.FakeExecer{})
+	dataContext := map[string]interface{}{}
+	assert.Nil(t, suiteRunner.Prepare(context.TODO(), suite, dataContext))
+	assert.Equal(t, map[string]interface{}{"login": map[string]interface{}{"token": "abc"}}, dataContext[HookKey])
+
+	// the after hook runs no matter the clean policy and the result
+	assert.Nil(t, suiteRunner.Clean(context.TODO(), suite, dataContext, true))
+	assert.True(t, gock.IsDone())
+}
@@ -1,85 +1,58 @@
 package runner
 
 import (
+	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/andreyvit/diff"
 	"github.com/antonmedv/expr"
 	"github.com/antonmedv/expr/vm"
+	"github.com/linuxsuren/api-testing/pkg/exec"
 	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
 	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/linuxsuren/api-testing/pkg/util"
 	fakeruntime "github.com/linuxsuren/go-fake-runtime"
 	unstructured "github.com/linuxsuren/unstructured/pkg"
-	"github.com/xeipuuv/gojsonschema"
 )
 
-// LevelWriter represents a writer with level
-type LevelWriter interface {
-	Info(format string, a ...any)
-	Debug(format string, a ...any)
-}
-
-// FormatPrinter represents a formart printer with level
-type FormatPrinter interface {
-	Fprintf(w io.Writer, level, format string, a ...any) (n int, err error)
-}
-
-type defaultLevelWriter struct {
-	level int
-	io.Writer
-	FormatPrinter
-}
-
-// NewDefaultLevelWriter creates a default LevelWriter instance
-func NewDefaultLevelWriter(level string, writer io.Writer) LevelWriter {
-	result := &defaultLevelWriter{
-		Writer: writer,
-	}
-	switch level {
-	case "debug":
-		result.level = 7
-	case "info":
-		result.level = 3
-	}
-	return result
-}
-
-// Fprintf implements interface FormatPrinter
-func (w *defaultLevelWriter) Fprintf(writer io.Writer, level int, format string, a ...any) (n int, err error) {
-	if level <= w.level {
-		return fmt.Fprintf(writer, format, a...)
-	}
-	return
-}
-
-// Info writes the info level message
-func (w *defaultLevelWriter) Info(format string, a ...any) {
-	w.Fprintf(w.Writer, 3, format, a...)
-}
-
-// Debug writes the debug level message
-func (w *defaultLevelWriter) Debug(format string, a ...any) {
-	w.Fprintf(w.Writer, 7, format, a...)
-}
-
 // ReportResult represents the report result of a set of the same API requests
 type ReportResult struct {
-	API              string
-	Count            int
-	Average          time.Duration
-	Max              time.Duration
-	Min              time.Duration
-	QPS              int
+	API     string
+	Count   int
+	Average time.Duration
+	Max     time.Duration
+	Min     time.Duration
+	// P50, P90, P95 and P99 are the percentiles of the durations
+	P50 time.Duration `json:",omitempty"`
+	P90 time.Duration `json:",omitempty"`
+	P95 time.Duration `json:",omitempty"`
+	P99 time.Duration `json:",omitempty"`
+	// QPS is the count of the requests per wall-clock second, from the first begin time to the last end time
+	QPS              float64
 	Error            int
 	LastErrorMessage string
+	// Retries is the count of the retried attempts
+	Retries int `json:",omitempty"`
+	// Timeout is the count of the requests which are not responded in time
+	Timeout int `json:",omitempty"`
+	// Slow is the count of the responses which exceed the max response time
+	Slow int `json:",omitempty"`
+	// Reused is the count of the requests which are sent over the idle connections
+	Reused int `json:",omitempty"`
+	// Skipped is the count of the test cases which are skipped by their conditions
+	Skipped int `json:",omitempty"`
+	// Findings are the distinct findings of the security checks
+	Findings []SecurityFinding `json:",omitempty"`
 }
 
 // ReportResultSlice is the alias type of ReportResult slice
@@ -107,11 +80,14 @@ type simpleTestCaseRunner struct {
 	writer       io.Writer
 	log          LevelWriter
 	execer       fakeruntime.Execer
+	executor     exec.Executor
+	// jobFunctions are the extra expr functions of the jobs, they're used together with the built-in ones
+	jobFunctions map[string]func(params ...interface{}) (interface{}, error)
 }
 
 // NewSimpleTestCaseRunner creates the instance of the simple test case runner
 func NewSimpleTestCaseRunner() TestCaseRunner {
-	runner := &simpleTestCaseRunner{}
+	runner := &simpleTestCaseRunner{executor: exec.NewExecutor()}
 	return runner.WithOutputWriter(io.Discard).
 		WithWriteLevel("info").
 		WithTestReporter(NewDiscardTestReporter()).
@@ -141,92 +117,388 @@ func (c ContextKey) GetContextValueOrEmpty(ctx context.Context) string {
 
 // RunTestCase is the main entry point of a test case
 func (r *simpleTestCaseRunner) RunTestCase(testcase *testing.TestCase, dataContext interface{}, ctx context.Context) (output interface{}, err error) {
+	if testcase.Repeat > 0 || testcase.Foreach != "" {
+		output, err = r.runIterations(testcase, dataContext, ctx)
+		return
+	}
+
+	restore := r.withLog(r.log.With(Fields{"case": testcase.Name}))
 	r.log.Info("start to run: '%s'\n", testcase.Name)
 	record := NewReportRecord()
+	var skipReason string
+	ctx, span := StartSpan(ctx, testcase.Name, SpanKindInternal)
+	ctx = context.WithValue(ctx, NewContextKeyBuilder().CaseName(), testcase.Name)
 	defer func(rr *ReportRecord) {
 		rr.EndTime = time.Now()
 		rr.Error = err
+		rr.Name = testcase.Name
 		rr.API = testcase.Request.API
 		rr.Method = testcase.Request.Method
-		r.testReporter.PutRecord(rr)
+		rr.Timeout = err != nil && isTimeout(err)
+		if rr.Skipped = skipReason != ""; rr.Skipped {
+			rr.Body = skipReason
+		}
+		if grpcOptions := testcase.Request.GRPC; grpcOptions != nil {
+			rr.Method = "GRPC"
+			rr.API = fmt.Sprintf("%s/%s/%s", testcase.Request.API, grpcOptions.Service, grpcOptions.Method)
+		} else if testcase.Request.WebSocket != nil {
+			rr.Method = "WS"
+		} else if testcase.Request.MQTT != nil {
+			rr.Method = "MQTT"
+		} else if testcase.Request.Socket != nil {
+			rr.Method = strings.ToUpper(strings.SplitN(testcase.Request.API, ":", 2)[0])
+		}
+		r.putRecord(ctx, rr)
+		span.SetAttributes(Fields{"atest.api": rr.API, "atest.method": rr.Method})
+		span.End(err)
+
+		if log := r.log.With(withResult(Fields{}, rr.Duration(), err)); rr.Skipped {
+			log.Info("skipped: '%s', %s\n", testcase.Name, skipReason)
+		} else if err == nil {
+			log.Info("finished: '%s' took %v\n", testcase.Name, rr.Duration())
+		} else {
+			log.Error("failed: '%s' took %v, %v\n", testcase.Name, rr.Duration(), err)
+		}
+		restore()
 	}(record)
 
+	if skipReason, err = shouldSkip(testcase, dataContext); err != nil || skipReason != "" {
+		return
+	}
+
+	contextDir := NewContextKeyBuilder().ParentDir().GetContextValueOrEmpty(ctx)
+	resources := &preparedResources{}
+
+	// the lock is released after the clean steps
+	var unlock func() error
+	if unlock, err = r.acquireLock(ctx, testcase.Prepare.Lock); err != nil {
+		return
+	}
 	defer func() {
-		if err == nil {
-			err = runJob(testcase.After)
+		if unlockErr := unlock(); err == nil {
+			err = unlockErr
 		}
 	}()
 
-	client := http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+	defer func() {
+		if !testcase.Clean.ShouldRun(err != nil) {
+			r.log.Info("skip the clean of '%s' due to the policy: %s\n", testcase.Name, testcase.Clean.CleanPolicy)
+			return
+		}
+		if cleanErr := r.runClean(ctx, testcase.Clean, contextDir, dataContext, resources); err == nil {
+			err = cleanErr
+		}
+	}()
+
+	defer recoverPanic(&err)
+
+	defer func() {
+		if _, afterErr := r.runHook(ctx, hookAfter, testcase.After, contextDir, dataContext); err == nil {
+			err = afterErr
+		}
+	}()
+
+	if err = r.runPrepare(ctx, testcase.Prepare, contextDir, dataContext, resources); err != nil {
+		return
 	}
+	dataContext = resources.withContext(dataContext)
 
-	contextDir := NewContextKeyBuilder().ParentDir().GetContextValueOrEmpty(ctx)
-	if err = testcase.Request.Render(dataContext, contextDir); err != nil {
+	var hookOutputs map[string]interface{}
+	if hookOutputs, err = r.runHook(ctx, hookBefore, testcase.Before, contextDir, dataContext); err != nil {
 		return
 	}
+	dataContext = withHooks(dataContext, hookOutputs)
 
-	var requestBody io.Reader
-	if requestBody, err = testcase.Request.GetBody(); err != nil {
+	if testcase.Expect.Schema, err = loadSchema(contextDir, testcase.Expect.Schema); err != nil {
 		return
 	}
 
-	var request *http.Request
-	if request, err = http.NewRequestWithContext(ctx, testcase.Request.Method, testcase.Request.API, requestBody); err != nil {
+	// the timeout of the request doesn't cover the steps above, the HTTP requests are limited one by one
+	protocolCtx, cancel := RequestContext(ctx)
+	defer cancel()
+	if testcase.Request.GRPC != nil {
+		output, err = r.runGRPC(protocolCtx, testcase, dataContext, contextDir, record)
+		return
+	} else if testcase.Request.WebSocket != nil {
+		output, err = r.runWebSocket(protocolCtx, testcase, dataContext, contextDir, record)
+		return
+	} else if testcase.Request.MQTT != nil {
+		output, err = r.runMQTT(protocolCtx, testcase, dataContext, contextDir, record)
+		return
+	} else if testcase.Request.Socket != nil {
+		output, err = r.runSocket(protocolCtx, testcase, dataContext, contextDir, record)
 		return
 	}
 
-	// set headers
-	for key, val := range testcase.Request.Header {
-		request.Header.Add(key, val)
+	// the faults are injected into the request of the test case only
+	requestCtx := ctx
+	if testcase.Chaos != nil {
+		if requestCtx, err = WithChaos(ctx, testcase.Chaos); err != nil {
+			return
+		}
 	}
 
-	if err = runJob(testcase.Before); err != nil {
+	var request *http.Request
+	if request, err = newRequest(requestCtx, &testcase.Request, dataContext, contextDir); err != nil {
 		return
 	}
-
-	r.log.Info("start to send request to %s\n", testcase.Request.API)
-
-	// TODO only do this for unit testing, should remove it once we have a better way
-	if strings.HasPrefix(testcase.Request.API, "http://") {
-		client = *http.DefaultClient
+	if err = authorize(ctx, testcase.Request.Auth, request, dataContext); err != nil {
+		return
 	}
 
-	// send the HTTP request
-	var resp *http.Response
-	if resp, err = client.Do(request); err != nil {
+	var maxResponseTime time.Duration
+	if maxResponseTime, err = parseMaxResponseTime(&testcase.Expect); err != nil {
 		return
 	}
 
+	r.log.Info("start to send request to %s\n", testcase.Request.API)
+
+	// send the HTTP request
+	var resp *http.Response
 	var responseBodyData []byte
-	if responseBodyData, err = io.ReadAll(resp.Body); err != nil {
+	if resp, responseBodyData, err = r.doRequestUntil(ctx, request, testcase, record); err != nil {
 		return
 	}
 	record.Body = string(responseBodyData)
+	if testcase.Expect.Download != nil {
+		record.Body = describeDownload(responseBodyData)
+	}
 	r.log.Debug("response body: %s\n", record.Body)
+	if testcase.Expect.SaveTo != "" {
+		if _, err = saveBody(testcase.Name, testcase.Expect.SaveTo, responseBodyData, contextDir, dataContext); err != nil {
+			return
+		}
+	}
 
-	if err = testcase.Expect.Render(nil); err != nil {
+	if err = expectResponseTime(testcase.Name, maxResponseTime, record); err != nil {
 		return
 	}
-	if err = expectInt(testcase.Name, testcase.Expect.StatusCode, resp.StatusCode); err != nil {
-		err = fmt.Errorf("error is: %v", err)
+
+	if record.Findings, err = r.checkSecurity(testcase.Security, request, testcase.Request.Body, resp, responseBodyData); err != nil {
 		return
 	}
 
-	for key, val := range testcase.Expect.Header {
-		actualVal := resp.Header.Get(key)
-		if err = expectString(testcase.Name, val, actualVal); err != nil {
+	if isJSONRPCNotified(testcase.Request.JSONRPC, responseBodyData) {
+		// the server doesn't respond the notifications
+		err = verifyStatusAndHeader(testcase.Name, &testcase.Expect, resp)
+	} else if testcase.Request.SSE != nil {
+		if err = verifyStatusAndHeader(testcase.Name, &testcase.Expect, resp); err == nil {
+			output, err = verifySSE(testcase.Name, testcase.Request.SSE, testcase.Expect.Events, responseBodyData, contextDir)
+		}
+	} else if testcase.Expect.Download != nil {
+		if err = verifyStatusAndHeader(testcase.Name, &testcase.Expect, resp); err == nil {
+			output, err = verifyDownload(testcase.Name, testcase.Expect.Download, responseBodyData, contextDir, dataContext)
+		}
+	} else if output, err = verifyResponse(testcase.Name, &testcase.Expect, resp, responseBodyData); err == nil && testcase.Request.GraphQL != nil {
+		err = verifyGraphQL(testcase.Name, testcase.Expect.GraphQL, responseBodyData)
+	}
+	if err == nil && testcase.Request.JSONRPC != nil {
+		err = verifyJSONRPC(testcase.Name, testcase.Request.JSONRPC, testcase.Expect.JSONRPC, responseBodyData)
+	}
+	if err == nil && testcase.Request.SOAP != nil {
+		err = verifySOAP(testcase.Name, testcase.Expect.SOAPFault, responseBodyData)
+	}
+	if err == nil {
+		err = verifyOpenAPI(ctx, testcase.Name, request, resp, responseBodyData)
+	}
+	if err == nil && len(testcase.Expect.SQL) > 0 {
+		err = r.verifySQL(ctx, testcase.Name, testcase.Expect.SQL, dataContext)
+	}
+	if err == nil && testcase.Expect.Redis != nil {
+		if err = r.runRedis(ctx, "expect", testcase.Expect.Redis, dataContext); err != nil {
+			err = fmt.Errorf("case: %s, %v", testcase.Name, err)
+		}
+	}
+	if err == nil && len(testcase.Expect.Mongo) > 0 {
+		err = r.verifyMongo(ctx, testcase.Name, testcase.Expect.Mongo, dataContext)
+	}
+	if err == nil && testcase.Fuzz != nil {
+		err = r.runFuzz(ctx, testcase)
+	}
+	if err == nil {
+		err = exportValues(testcase.Export, &exportSource{
+			status:  resp.StatusCode,
+			header:  resp.Header.Values,
+			cookies: resp.Cookies(),
+			body:    responseBodyData,
+		}, testcase.Name, dataContext)
+	}
+	if err == nil {
+		recordPact(ctx, testcase, request, resp, responseBodyData)
+	}
+	return
+}
+
+// newRequest renders the request, then creates the HTTP request with it
+func newRequest(ctx context.Context, req *testing.Request, dataContext interface{}, contextDir string) (request *http.Request, err error) {
+	if err = req.Render(dataContext, contextDir); err != nil {
+		return
+	}
+
+	var requestBody io.Reader
+	if requestBody, err = req.GetBody(); err != nil {
+		return
+	}
+	if req.Protobuf != nil && req.Protobuf.Request != "" {
+		var data []byte
+		if requestBody != nil {
+			if data, err = io.ReadAll(requestBody); err != nil {
+				return
+			}
+		}
+		if data, err = encodeProtobuf(req.Protobuf, data, contextDir); err != nil {
 			return
 		}
+		requestBody = bytes.NewReader(data)
+	}
+
+	if request, err = http.NewRequestWithContext(ctx, req.Method, req.API, requestBody); err != nil {
+		return
+	}
+
+	// set headers
+	for key, val := range req.Header {
+		request.Header.Add(key, val)
+	}
+	return
+}
+
+// doRequest sends the HTTP request with the transport of the options and the timeout, then reads the response body
+func doRequest(request *http.Request, req *testing.Request) (resp *http.Response, body []byte, err error) {
+	var timeout time.Duration
+	if timeout, err = parseDurationOrDefault(req.Timeout, 0); err != nil {
+		err = fmt.Errorf("invalid timeout of the request: %v", err)
+		return
 	}
 
-	if output, err = verifyResponseBodyData(testcase.Name, testcase.Expect, responseBodyData); err != nil {
+	// the transport is reused by the requests of the same options, so are the idle connections
+	contextDir := NewContextKeyBuilder().ParentDir().GetContextValueOrEmpty(request.Context())
+	client := http.Client{}
+	if client.Transport, err = getTransport(req, request.URL.Scheme, contextDir); err != nil {
 		return
 	}
+	if client.Transport, err = newCacheTransport(client.Transport, req, request.URL.Scheme, contextDir); err != nil {
+		return
+	}
+
+	client.Jar = getCookieJar(request.Context())
+	if cassette := getCassette(request.Context()); cassette != nil {
+		client.Transport = cassette.RoundTripper(client.Transport)
+	}
+	if chaos := getChaos(request.Context()); chaos != nil {
+		client.Transport = chaos.RoundTripper(client.Transport)
+	}
+
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(request.Context(), timeout)
+		defer cancel()
+		request = request.WithContext(ctx)
+		client.Timeout = timeout
+	}
 
-	err = jsonSchemaValidation(testcase.Expect.Schema, responseBodyData)
+	// the trace context is propagated to the server via the traceparent header
+	ctx, span := StartSpan(request.Context(), request.Method, SpanKindClient)
+	if span != nil {
+		request = request.WithContext(ctx)
+		request.Header.Set("traceparent", span.TraceParent())
+		span.SetAttributes(Fields{"http.method": request.Method, "http.url": request.URL.String()})
+		defer func() {
+			if resp != nil {
+				span.SetAttributes(Fields{"http.status_code": resp.StatusCode})
+			}
+			span.End(err)
+		}()
+	}
+
+	begin := time.Now()
+	if resp, err = client.Do(request); err == nil {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		if req.SSE != nil {
+			body, err = readEvents(resp.Body, req.SSE)
+		} else if body, err = io.ReadAll(resp.Body); err == nil {
+			body, err = decompressBody(resp, body)
+		}
+		if err == nil && req.Protobuf != nil && req.Protobuf.Response != "" {
+			body, err = decodeProtobuf(req.Protobuf, resp.Header.Get(util.ContentType), body, contextDir)
+		}
+	}
+	if err != nil && timeout > 0 && isTimeout(err) {
+		err = &timeoutError{duration: timeout, err: err}
+	}
+	dumpExchange(request, resp, body, begin, err)
+	return
+}
+
+// timeoutError is the error of a request which is not responded in time
+type timeoutError struct {
+	duration time.Duration
+	err      error
+}
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("timeout after %v: %v", e.duration, e.err)
+}
+
+// Timeout makes it as a net.Error
+func (e *timeoutError) Timeout() bool {
+	return true
+}
+
+// Temporary makes it as a net.Error
+func (e *timeoutError) Temporary() bool {
+	return false
+}
+
+// isTimeout returns true if the error is caused by the timeout of the request or the context
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return (errors.As(err, &netErr) && netErr.Timeout()) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// verifyResponse checks the status code, the headers, the body and the JSON schema of the response
+func verifyResponse(name string, expect *testing.Response, resp *http.Response, body []byte) (output interface{}, err error) {
+	if err = verifyStatusAndHeader(name, expect, resp); err != nil {
+		return
+	}
+
+	if isXMLResponse(expect, resp.Header.Get(util.ContentType)) {
+		output, err = verifyXMLBody(name, *expect, body)
+		return
+	}
+
+	if output, err = verifyResponseBodyData(name, *expect, body); err != nil {
+		return
+	}
+
+	err = jsonSchemaValidation(expect.Schema, body)
+	return
+}
+
+// verifyStatusAndHeader checks the status code and the headers of the response
+func verifyStatusAndHeader(name string, expect *testing.Response, resp *http.Response) (err error) {
+	if err = expect.Render(nil); err != nil {
+		return
+	}
+	if err = expectInt(name, expect.StatusCode, resp.StatusCode); err != nil {
+		err = fmt.Errorf("error is: %v", err)
+		return
+	}
+	if expect.ContentEncoding != "" {
+		if err = expectString(name, expect.ContentEncoding, contentEncoding(resp)); err != nil {
+			err = fmt.Errorf("unexpected Content-Encoding: %v", err)
+			return
+		}
+	}
+
+	for key, val := range expect.Header {
+		actualVal := resp.Header.Get(key)
+		if err = expectString(name, val, actualVal); err != nil {
+			return
+		}
+	}
+	err = verifyCookies(name, expect.Cookies, resp.Cookies())
 	return
 }
 
@@ -236,14 +508,32 @@ func (r *simpleTestCaseRunner) WithOutputWriter(writer io.Writer) TestCaseRunner
 	return r
 }
 
-// WithWriteLevel sets the level writer
+// WithWriteLevel sets the level writer, such as: info, debug, or debug,json for the JSON lines.
+// The levels of the components could be set as well, such as: info,prepare=debug,reporter=trace
 func (r *simpleTestCaseRunner) WithWriteLevel(level string) TestCaseRunner {
 	if level != "" {
-		r.log = NewDefaultLevelWriter(level, r.writer)
+		r.log = NewLevelWriter(level, r.writer)
 	}
 	return r
 }
 
+// withLog replaces the log until the returned function is called
+func (r *simpleTestCaseRunner) withLog(log LevelWriter) (restore func()) {
+	origin := r.log
+	r.log = log
+	return func() {
+		r.log = origin
+	}
+}
+
+// putRecord puts the record into the reporter, the secrets are redacted from it
+func (r *simpleTestCaseRunner) putRecord(ctx context.Context, record *ReportRecord) {
+	record.WarmUp = IsWarmUp(ctx)
+	record.redact()
+	r.testReporter.PutRecord(record)
+	r.log.Component(ComponentReporter).Trace("put the record: %s %s took %v\n", record.Method, record.API, record.Duration())
+}
+
 // WithTestReporter sets the TestReporter
 func (r *simpleTestCaseRunner) WithTestReporter(reporter TestReporter) TestCaseRunner {
 	r.testReporter = reporter
@@ -263,35 +553,238 @@ func expectInt(name string, expect, actual int) (err error) {
 	return
 }
 
+// parseMaxResponseTime returns the max response time of the expect, it's zero if there is no limit
+func parseMaxResponseTime(expect *testing.Response) (max time.Duration, err error) {
+	if max, err = parseDurationOrDefault(expect.MaxResponseTime, 0); err != nil {
+		err = fmt.Errorf("invalid max response time: %v", err)
+	}
+	return
+}
+
+// expectResponseTime marks the record as slow if the response time exceeds the max one
+func expectResponseTime(name string, max time.Duration, record *ReportRecord) (err error) {
+	if record.Slow = max > 0 && record.ResponseTime > max; record.Slow {
+		err = fmt.Errorf("case: %s, the response time %v exceeds the max %v", name, record.ResponseTime, max)
+	}
+	return
+}
+
+// the match modes of the expected body
+const (
+	bodyMatchExact  = "exact"
+	bodyMatchSubset = "subset"
+)
+
+// expectBody compares the body with the expected one, then checks the substrings and the pattern of it.
+// The JSON body could have more fields or items than the expected one in the subset mode
+func expectBody(name string, expect testing.Response, body string) (err error) {
+	if expect.Body != "" {
+		switch expect.BodyMatch {
+		case "", bodyMatchExact:
+			if body != strings.TrimSpace(expect.Body) {
+				err = fmt.Errorf("case: %s, got different response body, diff: \n%s", name, diff.LineDiff(expect.Body, body))
+			}
+		case bodyMatchSubset:
+			err = expectJSONSubset(name, expect.Body, body)
+		default:
+			err = fmt.Errorf("case: %s, unknown body match mode: %s", name, expect.BodyMatch)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	if err = expectBodyContains(name, expect.BodyContains, body); err != nil {
+		return
+	}
+	if err = expectBodyNotContains(name, expect.BodyNotContains, body); err != nil {
+		return
+	}
+	err = expectBodyPattern(name, expect.BodyPattern, body)
+	return
+}
+
+// expectJSONSubset checks the expected JSON is a subset of the body
+func expectJSONSubset(name, expect, body string) (err error) {
+	var expectData, bodyData interface{}
+	if err = json.Unmarshal([]byte(expect), &expectData); err != nil {
+		err = fmt.Errorf("case: %s, the expected body is not JSON: %v", name, err)
+		return
+	}
+	if err = json.Unmarshal([]byte(body), &bodyData); err != nil {
+		err = fmt.Errorf("case: %s, the response body is not JSON: %v", name, err)
+		return
+	}
+
+	if path := jsonSubsetDiff(expectData, bodyData, "$"); path != "" {
+		err = fmt.Errorf("case: %s, the response body does not contain the expected one, different at %s, body: %s", name, path, body)
+	}
+	return
+}
+
+// jsonSubsetDiff returns the path of the first difference, or empty if the expected value is a subset of the actual one.
+// The objects could have more keys, and each item of the expected array should match one of the actual items
+func jsonSubsetDiff(expect, actual interface{}, path string) string {
+	switch expectVal := expect.(type) {
+	case map[string]interface{}:
+		actualVal, ok := actual.(map[string]interface{})
+		if !ok {
+			return path
+		}
+		for _, key := range sortedKeys(expectVal) {
+			child, ok := actualVal[key]
+			if !ok {
+				return path + "." + key
+			}
+			if diffPath := jsonSubsetDiff(expectVal[key], child, path+"."+key); diffPath != "" {
+				return diffPath
+			}
+		}
+	case []interface{}:
+		actualVal, ok := actual.([]interface{})
+		if !ok {
+			return path
+		}
+		for i, item := range expectVal {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			matched := false
+			for _, actualItem := range actualVal {
+				if matched = jsonSubsetDiff(item, actualItem, itemPath) == ""; matched {
+					break
+				}
+			}
+			if !matched {
+				return itemPath
+			}
+		}
+	default:
+		if !reflect.DeepEqual(expect, actual) {
+			return path
+		}
+	}
+	return ""
+}
+
+// expectBodyContains checks the body contains all the substrings
+func expectBodyContains(name string, substrings []string, body string) (err error) {
+	for _, substring := range substrings {
+		if !strings.Contains(body, substring) {
+			err = fmt.Errorf("case: %s, the body should contain %q, body: %s", name, substring, body)
+			return
+		}
+	}
+	return
+}
+
+// expectBodyNotContains checks the body does not contain any of the substrings
+func expectBodyNotContains(name string, substrings []string, body string) (err error) {
+	for _, substring := range substrings {
+		if substring != "" && strings.Contains(body, substring) {
+			err = fmt.Errorf("case: %s, the body should not contain %q", name, substring)
+			return
+		}
+	}
+	return
+}
+
+// regexPrefix marks the expected string as a regular expression
+const regexPrefix = "regex:"
+
+// expectString compares the strings, the expected one is a regular expression if it has the prefix regex:
 func expectString(name, expect, actual string) (err error) {
-	if expect != actual {
+	if pattern := strings.TrimPrefix(expect, regexPrefix); pattern != expect {
+		var matched bool
+		if matched, err = regexp.MatchString(pattern, actual); err != nil {
+			err = fmt.Errorf("case: %s, invalid regular expression %s: %v", name, pattern, err)
+		} else if !matched {
+			err = fmt.Errorf("case: %s, expect to match %s, actual %s", name, pattern, actual)
+		}
+	} else if expect != actual {
 		err = fmt.Errorf("case: %s, expect %s, actual %s", name, expect, actual)
 	}
 	return
 }
 
-func jsonSchemaValidation(schema string, body []byte) (err error) {
-	if schema == "" {
+// expectBodyPattern checks if the body matches the regular expression
+func expectBodyPattern(name, pattern, body string) (err error) {
+	if pattern == "" {
 		return
 	}
 
-	schemaLoader := gojsonschema.NewStringLoader(schema)
-	jsonLoader := gojsonschema.NewBytesLoader(body)
-
-	var result *gojsonschema.Result
-	if result, err = gojsonschema.Validate(schemaLoader, jsonLoader); err == nil && !result.Valid() {
-		err = fmt.Errorf("JSON schema validation failed: %v", result.Errors())
+	var matched bool
+	if matched, err = regexp.MatchString(pattern, body); err != nil {
+		err = fmt.Errorf("case: %s, invalid body pattern %s: %v", name, pattern, err)
+	} else if !matched {
+		err = fmt.Errorf("case: %s, the body does not match the pattern %s, body: %s", name, pattern, body)
 	}
 	return
 }
 
-func verifyResponseBodyData(caseName string, expect testing.Response, responseBodyData []byte) (output interface{}, err error) {
-	if expect.Body != "" {
-		if string(responseBodyData) != strings.TrimSpace(expect.Body) {
-			err = fmt.Errorf("case: %s, got different response body, diff: \n%s", caseName,
-				diff.LineDiff(expect.Body, string(responseBodyData)))
+// verifyFields checks the fields of the body, the key is a JSONPath expression or the path which is split by "/"
+func verifyFields(data interface{}, fields map[string]interface{}) (err error) {
+	bodyMap, _ := data.(map[string]interface{})
+	for key, expectVal := range fields {
+		var val interface{}
+		var ok bool
+		if isJSONPath(key) {
+			val, ok, err = jsonPathField(data, key, expectVal)
+		} else {
+			val, ok, err = unstructured.NestedField(bodyMap, strings.Split(key, "/")...)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to get field: %s, %v", key, err)
+			return
+		} else if !ok {
+			err = fmt.Errorf("not found field: %s", key)
 			return
+		} else if !fieldEquals(expectVal, val) {
+			err = fmt.Errorf("field[%s] expect value: %v, actual: %v", key, expectVal, val)
+			return
+		}
+	}
+	return
+}
+
+// verifyFieldsNotExpect checks the fields should not exist if the value is nil, or should not be the value
+func verifyFieldsNotExpect(data interface{}, fields map[string]interface{}) (err error) {
+	bodyMap, _ := data.(map[string]interface{})
+	for key, notExpectVal := range fields {
+		var values []interface{}
+		if isJSONPath(key) {
+			var path *jsonPath
+			if path, err = parseJSONPath(key); err != nil {
+				return
+			}
+			values = path.query(data)
+		} else if val, ok, _ := unstructured.NestedField(bodyMap, strings.Split(key, "/")...); ok {
+			values = []interface{}{val}
 		}
+
+		for _, val := range values {
+			if notExpectVal == nil {
+				err = fmt.Errorf("field[%s] should not exist, actual: %v", key, val)
+				return
+			} else if fieldEquals(notExpectVal, val) {
+				err = fmt.Errorf("field[%s] should not be: %v", key, val)
+				return
+			}
+		}
+	}
+	return
+}
+
+// fieldEquals compares the values, the expected int is equal to the float of JSON which has the same text
+func fieldEquals(expectVal, val interface{}) bool {
+	if reflect.DeepEqual(expectVal, val) {
+		return true
+	}
+	return expectVal != nil && reflect.TypeOf(expectVal).Kind() == reflect.Int &&
+		fmt.Sprintf("%v", expectVal) == fmt.Sprintf("%v", val)
+}
+
+func verifyResponseBodyData(caseName string, expect testing.Response, responseBodyData []byte) (output interface{}, err error) {
+	if err = expectBody(caseName, expect, string(responseBodyData)); err != nil {
+		return
 	}
 
 	var bodyMap map[string]interface{}
@@ -320,24 +813,11 @@ func verifyResponseBodyData(caseName string, expect testing.Response, responseBo
 		}
 	}
 
-	for key, expectVal := range expect.BodyFieldsExpect {
-		var val interface{}
-		var ok bool
-		if val, ok, err = unstructured.NestedField(bodyMap, strings.Split(key, "/")...); err != nil {
-			err = fmt.Errorf("failed to get field: %s, %v", key, err)
-			return
-		} else if !ok {
-			err = fmt.Errorf("not found field: %s", key)
-			return
-		} else if !reflect.DeepEqual(expectVal, val) {
-			if reflect.TypeOf(expectVal).Kind() == reflect.Int {
-				if strings.Compare(fmt.Sprintf("%v", expectVal), fmt.Sprintf("%v", val)) == 0 {
-					continue
-				}
-			}
-			err = fmt.Errorf("field[%s] expect value: %v, actual: %v", key, expectVal, val)
-			return
-		}
+	if err = verifyFields(output, expect.BodyFieldsExpect); err != nil {
+		return
+	}
+	if err = verifyFieldsNotExpect(output, expect.BodyFieldsNotExpect); err != nil {
+		return
 	}
 
 	for _, verify := range expect.Verify {
@@ -362,13 +842,27 @@ func verifyResponseBodyData(caseName string, expect testing.Response, responseBo
 	return
 }
 
-func runJob(job testing.Job) (err error) {
+// builtinJobFunctions returns the expr functions which can be used in the items of the jobs
+func builtinJobFunctions() map[string]func(params ...interface{}) (interface{}, error) {
+	return map[string]func(params ...interface{}) (interface{}, error){
+		"sleep": ExprFuncSleep,
+	}
+}
+
+func (r *simpleTestCaseRunner) runJob(job testing.Job) (err error) {
 	var program *vm.Program
 	env := struct{}{}
+	functions := builtinJobFunctions()
+	for name, fn := range r.jobFunctions {
+		functions[name] = fn
+	}
+	options := []expr.Option{expr.Env(env)}
+	for name, fn := range functions {
+		options = append(options, expr.Function(name, fn))
+	}
 
 	for _, item := range job.Items {
-		if program, err = expr.Compile(item, expr.Env(env),
-			expr.Function("sleep", ExprFuncSleep)); err != nil {
+		if program, err = expr.Compile(item, options...); err != nil {
 			fmt.Printf("failed to compile: %s, %v\n", item, err)
 			return
 		}
@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// runHTTPStep sends the request then verifies the response. The output is the parsed JSON body,
+// or the plain text if the body is not JSON.
+func (r *simpleTestCaseRunner) runHTTPStep(ctx context.Context, phase string, step testing.HTTPStep, contextDir string,
+	dataContext interface{}) (output interface{}, err error) {
+	name := httpStepName(step)
+
+	var request *http.Request
+	if request, err = newRequest(ctx, &step.Request, dataContext, contextDir); err != nil {
+		return
+	}
+
+	r.log.Info("%s: send request to %s\n", phase, step.Request.API)
+	var resp *http.Response
+	var body []byte
+	if resp, body, err = doRequest(request, &step.Request); err != nil {
+		return
+	}
+	r.log.Debug("%s: response body of %s: %s\n", phase, name, string(body))
+
+	if json.Valid(body) {
+		output, err = verifyResponse(name, &step.Expect, resp, body)
+	} else if err = verifyStatusAndHeader(name, &step.Expect, resp); err == nil {
+		output = string(body)
+	}
+	return
+}
+
+// httpStepName returns the name of the step, or the API if the name is empty
+func httpStepName(step testing.HTTPStep) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	return step.Request.API
+}
@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunHTTPStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    atest.HTTPStep
+		prepare func()
+		verify  func(*testing.T, interface{}, error)
+	}{{
+		name: "JSON response",
+		step: atest.HTTPStep{
+			Name: "login",
+			Request: atest.Request{
+				API:    urlFoo,
+				Method: http.MethodPost,
+				Body:   `{"user":"{{.user}}"}`,
+			},
+			Expect: atest.Response{
+				BodyFieldsExpect: map[string]interface{}{"token": "abc"},
+			},
+		},
+		prepare: func() {
+			gock.New(urlLocalhost).Post("/foo").BodyString(`{"user":"admin"}`).
+				Reply(http.StatusOK).BodyString(`{"token":"abc"}`)
+		},
+		verify: func(t *testing.T, output interface{}, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]interface{}{"token": "abc"}, output)
+		},
+	}, {
+		name: "plain text response",
+		step: atest.HTTPStep{
+			Request: atest.Request{API: urlFoo, Method: http.MethodDelete},
+			Expect:  atest.Response{StatusCode: http.StatusAccepted},
+		},
+		prepare: func() {
+			gock.New(urlLocalhost).Delete("/foo").Reply(http.StatusAccepted).BodyString("OK")
+		},
+		verify: func(t *testing.T, output interface{}, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, "OK", output)
+		},
+	}, {
+		name: "unexpected status code",
+		step: atest.HTTPStep{
+			Request: atest.Request{API: urlFoo},
+		},
+		prepare: func() {
+			gock.New(urlLocalhost).Get("/foo").Reply(http.StatusInternalServerError)
+		},
+		verify: func(t *testing.T, output interface{}, err error) {
+			assert.NotNil(t, err)
+		},
+	}, {
+		name: "failed to send",
+		step: atest.HTTPStep{
+			Request: atest.Request{API: urlFoo},
+		},
+		verify: func(t *testing.T, output interface{}, err error) {
+			assert.NotNil(t, err)
+		},
+	}, {
+		name: "invalid template",
+		step: atest.HTTPStep{
+			Request: atest.Request{API: "{{.fake"},
+		},
+		verify: func(t *testing.T, output interface{}, err error) {
+			assert.NotNil(t, err)
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+
+			runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+			output, err := runner.runHTTPStep(context.TODO(), "prepare", tt.step, "",
+				map[string]interface{}{"user": "admin"})
+			tt.verify(t, output, err)
+		})
+	}
+}
+
+func TestHTTPStepInTestCase(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlLocalhost).Post("/login").Reply(http.StatusOK).BodyString(`{"token":"abc"}`)
+	gock.New(urlLocalhost).Get("/foo").MatchHeader("Authorization", "Bearer abc").
+		Reply(http.StatusOK).BodyString(`{}`)
+	gock.New(urlLocalhost).Post("/reset").MatchHeader("Authorization", "Bearer abc").
+		Reply(http.StatusOK)
+
+	_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Prepare: atest.Prepare{HTTP: []atest.HTTPStep{{
+			Name:    "login",
+			Request: atest.Request{API: urlLocalhost + "/login", Method: http.MethodPost},
+		}}},
+		Clean: atest.Clean{HTTP: []atest.HTTPStep{{
+			Request: atest.Request{
+				API:    urlLocalhost + "/reset",
+				Method: http.MethodPost,
+				Header: map[string]string{"Authorization": "Bearer {{.prepare.login.token}}"},
+			},
+		}}},
+		Request: atest.Request{
+			API:    urlFoo,
+			Header: map[string]string{"Authorization": "Bearer {{.prepare.login.token}}"},
+		},
+	}, nil, context.TODO())
+	assert.Nil(t, err)
+	assert.True(t, gock.IsDone())
+}
@@ -1,12 +1,13 @@
 package runner
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	_ "embed"
 
@@ -42,12 +43,20 @@ func TestTestCase(t *testing.T) {
 		verify   func(t *testing.T, output interface{}, err error)
 	}{{
 		name: "failed during the prepare stage",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{
+				Kubernetes: []atest.Kubernetes{{File: "demo.yaml"}},
+			},
+		},
+		execer: fakeruntime.FakeExecer{ExpectError: errors.New("fake")},
+	}, {
+		name: "failed during the before job",
 		testCase: &atest.TestCase{
 			Before: atest.Job{
 				Items: []string{"demo.yaml"},
 			},
 		},
-		execer: fakeruntime.FakeExecer{ExpectError: errors.New("fake")},
+		execer: fakeruntime.FakeExecer{},
 	}, {
 		name: "normal, response is map",
 		testCase: &atest.TestCase{
@@ -105,6 +114,42 @@ func TestTestCase(t *testing.T) {
 			assert.Nil(t, err)
 			assert.Equal(t, []interface{}{"foo", "bar"}, output)
 		},
+	}, {
+		name: "the regular expressions of the header and the body",
+		testCase: &atest.TestCase{
+			Request: fooRequst,
+			Expect: atest.Response{
+				BodyPattern: `"id":\s*"[0-9a-f-]{36}"`,
+				Header: map[string]string{
+					"Date": `regex:^\w{3}, \d{2} \w{3} \d{4}`,
+				},
+			},
+		},
+		prepare: func() {
+			gock.New(urlLocalhost).
+				Get("/foo").
+				Reply(http.StatusOK).
+				SetHeader("Date", "Mon, 02 Jan 2006 15:04:05 GMT").
+				BodyString(`{"id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}`)
+		},
+		verify: noError,
+	}, {
+		name: "the body does not match the pattern",
+		testCase: &atest.TestCase{
+			Request: fooRequst,
+			Expect: atest.Response{
+				BodyPattern: `"id":\s*\d+`,
+			},
+		},
+		prepare: func() {
+			gock.New(urlLocalhost).
+				Get("/foo").
+				Reply(http.StatusOK).
+				BodyString(`{"id": "abc"}`)
+		},
+		verify: func(t *testing.T, output interface{}, err error) {
+			assert.ErrorContains(t, err, "the body does not match the pattern")
+		},
 	}, {
 		name: "normal, response from file",
 		testCase: &atest.TestCase{
@@ -351,31 +396,216 @@ func TestTestCase(t *testing.T) {
 	}
 }
 
-func TestLevelWriter(t *testing.T) {
+func TestRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	reporter := NewMemoryTestReporter()
+	_, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: server.URL, Timeout: "50ms"},
+	}, nil, context.TODO())
+	assert.ErrorContains(t, err, "timeout after 50ms")
+	assert.True(t, isTimeout(err))
+
+	results, err := reporter.ExportAllReportResults()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(results)) {
+		assert.Equal(t, 1, results[0].Timeout)
+		assert.Equal(t, 1, results[0].Error)
+	}
+
+	_, err = NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: server.URL, Timeout: "fake"},
+	}, nil, context.TODO())
+	assert.ErrorContains(t, err, "invalid timeout of the request")
+	assert.False(t, isTimeout(err))
+}
+
+func TestMaxResponseTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	reporter := NewMemoryTestReporter()
+	_, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+		Name:    "slow",
+		Request: atest.Request{API: server.URL + "/slow"},
+		Expect:  atest.Response{MaxResponseTime: "50ms"},
+	}, nil, context.TODO())
+	assert.ErrorContains(t, err, "case: slow, the response time")
+	assert.ErrorContains(t, err, "exceeds the max 50ms")
+
+	_, err = NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+		Name:    "fast",
+		Request: atest.Request{API: server.URL + "/fast"},
+		Expect:  atest.Response{MaxResponseTime: "5s"},
+	}, nil, context.TODO())
+	assert.Nil(t, err)
+
+	records := reporter.GetAllRecords()
+	if assert.Equal(t, 2, len(records)) {
+		assert.True(t, records[0].Slow)
+		assert.GreaterOrEqual(t, records[0].ResponseTime, 100*time.Millisecond)
+		assert.False(t, records[1].Slow)
+		assert.Greater(t, records[1].ResponseTime, time.Duration(0))
+	}
+
+	results, err := reporter.ExportAllReportResults()
+	assert.Nil(t, err)
+	for _, result := range results {
+		assert.Equal(t, result.Error, result.Slow, result.API)
+	}
+
+	_, err = NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: server.URL},
+		Expect:  atest.Response{MaxResponseTime: "fake"},
+	}, nil, context.TODO())
+	assert.ErrorContains(t, err, "invalid max response time")
+}
+
+func TestExpectString(t *testing.T) {
+	assert.Nil(t, expectString("case", "foo", "foo"))
+	assert.ErrorContains(t, expectString("case", "foo", "bar"), "expect foo, actual bar")
+	assert.Nil(t, expectString("case", "regex:^f.o$", "foo"))
+	assert.ErrorContains(t, expectString("case", "regex:^f.o$", "bar"), "expect to match ^f.o$, actual bar")
+	assert.ErrorContains(t, expectString("case", "regex:(", "bar"), "invalid regular expression (")
+}
+
+func TestExpectBodyPattern(t *testing.T) {
+	assert.Nil(t, expectBodyPattern("case", "", "foo"))
+	assert.Nil(t, expectBodyPattern("case", `"time":\s*"\d{4}-`, `{"time": "2023-01-01"}`))
+	assert.ErrorContains(t, expectBodyPattern("case", `^\[`, "{}"), "the body does not match the pattern")
+	assert.ErrorContains(t, expectBodyPattern("case", "(", "{}"), "invalid body pattern (")
+}
+
+func TestExpectBody(t *testing.T) {
+	body := `{"name": "linuxsuren", "age": 100, "tags": ["a", "b"], "items": [{"id": 1, "name": "foo"}, {"id": 2, "name": "bar"}]}`
 	tests := []struct {
 		name   string
-		buf    *bytes.Buffer
-		level  string
-		expect string
+		expect atest.Response
+		err    string
 	}{{
-		name:   "debug",
-		buf:    new(bytes.Buffer),
-		level:  "debug",
-		expect: "debuginfo",
-	}, {
-		name:   "info",
-		buf:    new(bytes.Buffer),
-		level:  "info",
-		expect: "info",
+		name:   "exact",
+		expect: atest.Response{Body: body + "\n"},
+	}, {
+		name:   "not exact",
+		expect: atest.Response{Body: `{"name": "linuxsuren"}`, BodyMatch: "exact"},
+		err:    "got different response body",
+	}, {
+		name: "subset",
+		expect: atest.Response{
+			Body:      `{"name": "linuxsuren", "tags": ["b"], "items": [{"name": "bar"}]}`,
+			BodyMatch: "subset",
+		},
+	}, {
+		name:   "missing key of the subset",
+		expect: atest.Response{Body: `{"name": "linuxsuren", "email": "a@b.c"}`, BodyMatch: "subset"},
+		err:    "different at $.email",
+	}, {
+		name:   "different value of the subset",
+		expect: atest.Response{Body: `{"age": 18}`, BodyMatch: "subset"},
+		err:    "different at $.age",
+	}, {
+		name:   "missing item of the subset",
+		expect: atest.Response{Body: `{"items": [{"id": 3}]}`, BodyMatch: "subset"},
+		err:    "different at $.items[0]",
+	}, {
+		name:   "different type of the subset",
+		expect: atest.Response{Body: `{"tags": {"a": 1}}`, BodyMatch: "subset"},
+		err:    "different at $.tags",
+	}, {
+		name:   "invalid expected body of the subset",
+		expect: atest.Response{Body: `{`, BodyMatch: "subset"},
+		err:    "the expected body is not JSON",
+	}, {
+		name:   "unknown match mode",
+		expect: atest.Response{Body: body, BodyMatch: "fake"},
+		err:    "unknown body match mode: fake",
+	}, {
+		name:   "contains",
+		expect: atest.Response{BodyContains: []string{`"name": "linuxsuren"`, "foo"}},
+	}, {
+		name:   "not contains",
+		expect: atest.Response{BodyContains: []string{"foo", "fake"}},
+		err:    `the body should contain "fake"`,
 	}}
 	for _, tt := range tests {
-		writer := NewDefaultLevelWriter(tt.level, tt.buf)
-		if assert.NotNil(t, writer) {
-			writer.Debug("debug")
-			writer.Info("info")
+		t.Run(tt.name, func(t *testing.T) {
+			err := expectBody("case", tt.expect, body)
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
 
-			assert.Equal(t, tt.expect, tt.buf.String())
-		}
+	assert.ErrorContains(t, expectBody("case", atest.Response{Body: "{}", BodyMatch: "subset"}, "foo"),
+		"the response body is not JSON")
+}
+
+func TestExpectBodyNotContains(t *testing.T) {
+	assert.Nil(t, expectBodyNotContains("case", nil, "foo"))
+	assert.Nil(t, expectBodyNotContains("case", []string{"", "password"}, `{"name": "foo"}`))
+	assert.ErrorContains(t, expectBodyNotContains("case", []string{"token", "password"}, `{"password": "bar"}`),
+		`case: case, the body should not contain "password"`)
+}
+
+func TestVerifyFieldsNotExpect(t *testing.T) {
+	body := `{"name": "linuxsuren", "age": 100, "items": [{"id": 1, "deleted": false}, {"id": 2}]}`
+	tests := []struct {
+		name   string
+		fields map[string]interface{}
+		err    string
+	}{{
+		name: "absent fields",
+		fields: map[string]interface{}{
+			"password":                 nil,
+			"profile/secret":           nil,
+			"$.items[?(@.deleted)].id": nil,
+		},
+	}, {
+		name: "different values",
+		fields: map[string]interface{}{
+			"name":          "admin",
+			"age":           18,
+			"$.items[*].id": 3,
+		},
+	}, {
+		name:   "the field exists",
+		fields: map[string]interface{}{"name": nil},
+		err:    "field[name] should not exist, actual: linuxsuren",
+	}, {
+		name:   "the same int value",
+		fields: map[string]interface{}{"age": 100},
+		err:    "field[age] should not be: 100",
+	}, {
+		name:   "one of the matched values",
+		fields: map[string]interface{}{"$.items[*].id": 2},
+		err:    "field[$.items[*].id] should not be: 2",
+	}, {
+		name:   "invalid JSONPath",
+		fields: map[string]interface{}{"$.items[": nil},
+		err:    "the bracket is not closed",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifyResponseBodyData(tt.name, atest.Response{BodyFieldsNotExpect: tt.fields}, []byte(body))
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
 	}
 }
 
@@ -399,6 +629,30 @@ func TestJSONSchemaValidation(t *testing.T) {
 		schema: defaultSchemaForTest,
 		body:   `{"name": "linuxsuren", "age": "100"}`,
 		hasErr: true,
+	}, {
+		name:   "draft 2020-12",
+		schema: schema2020ForTest,
+		body:   `{"tags": ["a", 1], "owner": {"name": "linuxsuren"}}`,
+		hasErr: false,
+	}, {
+		name:   "draft 2020-12, wrong prefix items",
+		schema: schema2020ForTest,
+		body:   `{"tags": [1, 1]}`,
+		hasErr: true,
+	}, {
+		name:   "draft 2020-12, wrong additional items",
+		schema: schema2020ForTest,
+		body:   `{"tags": ["a", 1, "b"]}`,
+		hasErr: true,
+	}, {
+		name:   "draft 2020-12, missing dependent property",
+		schema: schema2020ForTest,
+		body:   `{"owner": {"name": "linuxsuren", "email": "a@b.c"}}`,
+		hasErr: true,
+	}, {
+		name:   "invalid schema",
+		schema: `{"type": `,
+		hasErr: true,
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -428,7 +682,7 @@ func TestRunJob(t *testing.T) {
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := runJob(tt.job)
+			err := NewSimpleTestCaseRunner().(*simpleTestCaseRunner).runJob(tt.job)
 			assert.Equal(t, tt.hasErr, err != nil, err)
 		})
 	}
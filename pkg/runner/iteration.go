@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/antonmedv/expr"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// IterationKey is the key of the current iteration of the repeated test case in the data context,
+// it's referenced as {{.iteration.index}} and {{.iteration.value}}
+const IterationKey = "iteration"
+
+// runIterations runs the test case once for each iteration, it stops at the first failed one.
+// The output is the list of the outputs of the iterations
+func (r *simpleTestCaseRunner) runIterations(testcase *testing.TestCase, dataContext interface{}, ctx context.Context) (output interface{}, err error) {
+	var values []interface{}
+	if values, err = iterationValues(testcase, dataContext); err != nil {
+		err = fmt.Errorf("case: %s, %v", testcase.Name, err)
+		return
+	}
+
+	ctxMap, _ := dataContext.(map[string]interface{})
+	outputs := make([]interface{}, 0, len(values))
+	for i, value := range values {
+		// the maps are rendered in place, they're copied for each iteration
+		item := *testcase
+		item.Repeat, item.Foreach = 0, ""
+		item.Request.Header = copyStrings(testcase.Request.Header)
+		item.Request.Form = copyStrings(testcase.Request.Form)
+
+		iterationContext := make(map[string]interface{}, len(ctxMap)+1)
+		for key, val := range ctxMap {
+			iterationContext[key] = val
+		}
+		iterationContext[IterationKey] = map[string]interface{}{"index": i, "value": value}
+
+		var itemOutput interface{}
+		itemOutput, err = r.RunTestCase(&item, iterationContext, ctx)
+		if exported, ok := iterationContext[ExportKey]; ok && ctxMap != nil {
+			ctxMap[ExportKey] = exported
+		}
+		if err != nil {
+			err = fmt.Errorf("iteration %d: %v", i, err)
+			return
+		}
+		outputs = append(outputs, itemOutput)
+	}
+	output = outputs
+	return
+}
+
+// iterationValues returns the indexes of the repeat, or the items of the foreach list
+func iterationValues(testcase *testing.TestCase, dataContext interface{}) (values []interface{}, err error) {
+	if testcase.Repeat > 0 && testcase.Foreach != "" {
+		err = errors.New("repeat and foreach cannot be used together")
+		return
+	}
+
+	if testcase.Repeat > 0 {
+		values = make([]interface{}, testcase.Repeat)
+		for i := range values {
+			values[i] = i
+		}
+		return
+	}
+
+	env, ok := dataContext.(map[string]interface{})
+	if !ok {
+		env = map[string]interface{}{}
+	}
+	var result interface{}
+	if result, err = expr.Eval(testcase.Foreach, env); err != nil {
+		err = fmt.Errorf("failed to evaluate the foreach '%s': %v", testcase.Foreach, err)
+		return
+	}
+
+	list := reflect.ValueOf(result)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		err = fmt.Errorf("the foreach '%s' is not a list, actual: %v", testcase.Foreach, result)
+		return
+	}
+	values = make([]interface{}, list.Len())
+	for i := range values {
+		values[i] = list.Index(i).Interface()
+	}
+	return
+}
+
+// copyStrings returns a copy of the map, it's nil if the map is nil
+func copyStrings(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]string, len(values))
+	for key, val := range values {
+		result[key] = val
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterations(t *testing.T) {
+	t.Run("repeat", func(t *testing.T) {
+		defer gock.Off()
+		for _, page := range []string{"0", "1", "2"} {
+			gock.New(urlLocalhost).Get("/users").MatchParam("page", page).
+				Reply(200).SetHeader("Content-Type", "application/json").BodyString(`{"page": ` + page + `}`)
+		}
+
+		reporter := NewMemoryTestReporter()
+		output, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+			Name:    "users",
+			Repeat:  3,
+			Request: atest.Request{API: urlLocalhost + "/users?page={{.iteration.index}}"},
+		}, nil, context.TODO())
+		assert.NoError(t, err)
+		assert.True(t, gock.IsDone())
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"page": float64(0)},
+			map[string]interface{}{"page": float64(1)},
+			map[string]interface{}{"page": float64(2)},
+		}, output)
+		assert.Equal(t, 3, len(reporter.GetAllRecords()))
+	})
+
+	t.Run("foreach", func(t *testing.T) {
+		defer gock.Off()
+		for _, name := range []string{"rick", "morty"} {
+			gock.New(urlLocalhost).Post("/users").MatchHeader("X-Index", "[01]").BodyString(`{"name":"`+name+`"}`).
+				Reply(201).SetHeader("Content-Type", "application/json").BodyString(`{"name": "` + name + `"}`)
+		}
+
+		dataContext := map[string]interface{}{
+			"env": map[string]interface{}{"users": []interface{}{"rick", "morty"}},
+		}
+		testCase := &atest.TestCase{
+			Name:    "create",
+			Foreach: "env.users",
+			Request: atest.Request{
+				API:    urlLocalhost + "/users",
+				Method: "POST",
+				Header: map[string]string{"X-Index": "{{.iteration.index}}"},
+				Body:   `{"name":"{{.iteration.value}}"}`,
+			},
+			Expect: atest.Response{StatusCode: 201},
+			Export: map[string]string{"name": "$.name"},
+		}
+		output, err := NewSimpleTestCaseRunner().RunTestCase(testCase, dataContext, context.TODO())
+		assert.NoError(t, err)
+		assert.True(t, gock.IsDone())
+		assert.Equal(t, 2, len(output.([]interface{})))
+		// the templates are kept for the next runs
+		assert.Equal(t, "{{.iteration.index}}", testCase.Request.Header["X-Index"])
+		// the values of the last iteration are exported
+		assert.Equal(t, map[string]interface{}{"create": map[string]interface{}{"name": "morty"}}, dataContext[ExportKey])
+	})
+
+	t.Run("failed iteration", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlLocalhost).Get("/users").Reply(200).BodyString("{}")
+		gock.New(urlLocalhost).Get("/users").Reply(500).BodyString("{}")
+
+		_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+			Name:    "users",
+			Repeat:  3,
+			Request: atest.Request{API: urlLocalhost + "/users"},
+		}, nil, context.TODO())
+		assert.ErrorContains(t, err, "iteration 1: ")
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		runner := NewSimpleTestCaseRunner()
+		_, err := runner.RunTestCase(&atest.TestCase{Name: "users", Repeat: 1, Foreach: "users"}, nil, context.TODO())
+		assert.ErrorContains(t, err, "repeat and foreach cannot be used together")
+
+		_, err = runner.RunTestCase(&atest.TestCase{Name: "users", Foreach: "name"},
+			map[string]interface{}{"name": "rick"}, context.TODO())
+		assert.ErrorContains(t, err, "the foreach 'name' is not a list, actual: rick")
+
+		_, err = runner.RunTestCase(&atest.TestCase{Name: "users", Foreach: "users"}, nil, context.TODO())
+		assert.ErrorContains(t, err, "failed to evaluate the foreach 'users'")
+	})
+}
@@ -0,0 +1,380 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+)
+
+// jsonPathSelector returns the matched children of the node
+type jsonPathSelector func(node, root interface{}) []interface{}
+
+// jsonPathSegment selects the children of the nodes, or the descendants of them if it's recursive
+type jsonPathSegment struct {
+	recursive bool
+	selectors []jsonPathSelector
+}
+
+// jsonPath is a parsed JSONPath expression, it's definite if it addresses one node at most
+type jsonPath struct {
+	segments []jsonPathSegment
+	definite bool
+}
+
+// isJSONPath checks if the key of the fields is a JSONPath expression instead of a path which is split by "/"
+func isJSONPath(key string) bool {
+	return strings.HasPrefix(key, "$")
+}
+
+// parseJSONPath supports the dot and bracket notations, the wildcards, the recursive descent, the indexes, the slices,
+// the unions, and the filters. The filter is an expr expression, the @ is the current node and the $ is the root
+func parseJSONPath(path string) (result *jsonPath, err error) {
+	if !isJSONPath(path) {
+		err = fmt.Errorf("the JSONPath should start with $: %s", path)
+		return
+	}
+
+	result = &jsonPath{definite: true}
+	for i := 1; i < len(path); {
+		segment := jsonPathSegment{}
+		if strings.HasPrefix(path[i:], "..") {
+			segment.recursive = true
+			i += 2
+		} else if path[i] == '.' {
+			i++
+		} else if path[i] != '[' {
+			err = fmt.Errorf("invalid JSONPath %s at %d", path, i)
+			return
+		}
+
+		if i < len(path) && path[i] == '[' {
+			end := closingBracket(path, i)
+			if end < 0 {
+				err = fmt.Errorf("the bracket is not closed in JSONPath %s at %d", path, i)
+				return
+			}
+			var definite bool
+			if segment.selectors, definite, err = parseBracket(path[i+1 : end]); err != nil {
+				err = fmt.Errorf("invalid JSONPath %s: %v", path, err)
+				return
+			}
+			result.definite = result.definite && definite
+			i = end + 1
+		} else {
+			end := i
+			for end < len(path) && path[end] != '.' && path[end] != '[' {
+				end++
+			}
+			name := path[i:end]
+			switch name {
+			case "":
+				err = fmt.Errorf("the name is empty in JSONPath %s at %d", path, i)
+				return
+			case "*":
+				segment.selectors = []jsonPathSelector{selectWildcard}
+				result.definite = false
+			default:
+				segment.selectors = []jsonPathSelector{selectName(name)}
+			}
+			i = end
+		}
+
+		result.definite = result.definite && !segment.recursive
+		result.segments = append(result.segments, segment)
+	}
+	return
+}
+
+// query returns the matched nodes in the document order, the keys of the objects are sorted
+func (p *jsonPath) query(data interface{}) []interface{} {
+	nodes := []interface{}{data}
+	for _, segment := range p.segments {
+		var matched []interface{}
+		for _, node := range nodes {
+			candidates := []interface{}{node}
+			if segment.recursive {
+				candidates = descendants(node, nil)
+			}
+			for _, candidate := range candidates {
+				for _, selector := range segment.selectors {
+					matched = append(matched, selector(candidate, data)...)
+				}
+			}
+		}
+		nodes = matched
+	}
+	return nodes
+}
+
+// jsonPathField returns the value of the definite JSONPath, or all the matched values of the indefinite one.
+// The only matched value is returned if the expected value is not a list
+func jsonPathField(data interface{}, key string, expectVal interface{}) (val interface{}, ok bool, err error) {
+	var path *jsonPath
+	if path, err = parseJSONPath(key); err != nil {
+		return
+	}
+
+	values := path.query(data)
+	if ok = len(values) > 0; !ok {
+		return
+	}
+
+	if _, isList := expectVal.([]interface{}); path.definite || (!isList && len(values) == 1) {
+		val = values[0]
+	} else if isList {
+		val = values
+	} else {
+		err = fmt.Errorf("the JSONPath %s matches %d values, expect a list of them", key, len(values))
+	}
+	return
+}
+
+func parseBracket(content string) (selectors []jsonPathSelector, definite bool, err error) {
+	content = strings.TrimSpace(content)
+	switch {
+	case content == "*":
+		selectors = []jsonPathSelector{selectWildcard}
+	case strings.HasPrefix(content, "?"):
+		var selector jsonPathSelector
+		if selector, err = selectFilter(strings.TrimSpace(content[1:])); err == nil {
+			selectors = []jsonPathSelector{selector}
+		}
+	default:
+		items := splitUnquoted(content, ',')
+		definite = len(items) == 1
+		for _, item := range items {
+			item = strings.TrimSpace(item)
+
+			var selector jsonPathSelector
+			if unquoted, unquoteErr := unquote(item); unquoteErr == nil {
+				selector = selectName(unquoted)
+			} else if strings.Contains(item, ":") {
+				selector, err = selectSlice(item)
+				definite = false
+			} else {
+				var index int
+				if index, err = strconv.Atoi(item); err != nil {
+					err = fmt.Errorf("invalid selector: %s", item)
+				}
+				selector = selectIndex(index)
+			}
+			if err != nil {
+				return
+			}
+			selectors = append(selectors, selector)
+		}
+	}
+	return
+}
+
+func selectWildcard(node, _ interface{}) (children []interface{}) {
+	switch val := node.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(val) {
+			children = append(children, val[key])
+		}
+	case []interface{}:
+		children = val
+	}
+	return
+}
+
+func selectName(name string) jsonPathSelector {
+	return func(node, _ interface{}) []interface{} {
+		if object, ok := node.(map[string]interface{}); ok {
+			if child, ok := object[name]; ok {
+				return []interface{}{child}
+			}
+		}
+		return nil
+	}
+}
+
+// selectIndex supports the negative index which counts from the end
+func selectIndex(index int) jsonPathSelector {
+	return func(node, _ interface{}) []interface{} {
+		if array, ok := node.([]interface{}); ok {
+			i := index
+			if i < 0 {
+				i += len(array)
+			}
+			if i >= 0 && i < len(array) {
+				return []interface{}{array[i]}
+			}
+		}
+		return nil
+	}
+}
+
+// selectSlice supports [start:end:step], the step should be positive
+func selectSlice(item string) (selector jsonPathSelector, err error) {
+	parts := strings.Split(item, ":")
+	if len(parts) > 3 {
+		err = fmt.Errorf("invalid slice: %s", item)
+		return
+	}
+
+	bounds := make([]*int, 3)
+	for i, part := range parts {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		var bound int
+		if bound, err = strconv.Atoi(part); err != nil {
+			err = fmt.Errorf("invalid slice: %s", item)
+			return
+		}
+		bounds[i] = &bound
+	}
+
+	step := 1
+	if bounds[2] != nil {
+		if step = *bounds[2]; step <= 0 {
+			err = fmt.Errorf("the step of the slice should be positive: %s", item)
+			return
+		}
+	}
+
+	selector = func(node, _ interface{}) (children []interface{}) {
+		array, ok := node.([]interface{})
+		if !ok {
+			return
+		}
+		start, end := sliceBound(bounds[0], 0, len(array)), sliceBound(bounds[1], len(array), len(array))
+		for i := start; i < end; i += step {
+			children = append(children, array[i])
+		}
+		return
+	}
+	return
+}
+
+func sliceBound(bound *int, defaultVal, length int) int {
+	if bound == nil {
+		return defaultVal
+	}
+	val := *bound
+	if val < 0 {
+		val += length
+	}
+	if val < 0 {
+		val = 0
+	} else if val > length {
+		val = length
+	}
+	return val
+}
+
+// selectFilter selects the children which make the expression true, the failures of the evaluation are not matched
+func selectFilter(filter string) (selector jsonPathSelector, err error) {
+	if strings.HasPrefix(filter, "(") && strings.HasSuffix(filter, ")") {
+		filter = filter[1 : len(filter)-1]
+	}
+
+	var program *vm.Program
+	if program, err = expr.Compile(replaceUnquoted(filter, map[rune]string{'@': "item", '$': "root"})); err != nil {
+		err = fmt.Errorf("invalid filter %s: %v", filter, err)
+		return
+	}
+
+	selector = func(node, root interface{}) (children []interface{}) {
+		for _, child := range selectWildcard(node, root) {
+			// the non-boolean result is the existence test, such as [?(@.name)]
+			result, runErr := expr.Run(program, map[string]interface{}{"item": child, "root": root})
+			if runErr == nil && result != nil && result != false {
+				children = append(children, child)
+			}
+		}
+		return
+	}
+	return
+}
+
+// descendants returns the node and all the descendants of it in the document order
+func descendants(node interface{}, result []interface{}) []interface{} {
+	result = append(result, node)
+	for _, child := range selectWildcard(node, nil) {
+		result = descendants(child, result)
+	}
+	return result
+}
+
+func sortedKeys(object map[string]interface{}) (keys []string) {
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// closingBracket returns the index of the bracket which closes the one at the start, the quoted ones are skipped
+func closingBracket(path string, start int) int {
+	depth := 0
+	var quote rune
+	for i, c := range path[start:] {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			if depth--; depth == 0 {
+				return start + i
+			}
+		}
+	}
+	return -1
+}
+
+func splitUnquoted(text string, sep rune) (items []string) {
+	var quote rune
+	last := 0
+	for i, c := range text {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == sep:
+			items = append(items, text[last:i])
+			last = i + 1
+		}
+	}
+	return append(items, text[last:])
+}
+
+func replaceUnquoted(text string, replacements map[rune]string) string {
+	var quote rune
+	builder := strings.Builder{}
+	for _, c := range text {
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+		} else if c == '\'' || c == '"' {
+			quote = c
+		} else if replacement, ok := replacements[c]; ok {
+			builder.WriteString(replacement)
+			continue
+		}
+		builder.WriteRune(c)
+	}
+	return builder.String()
+}
+
+func unquote(text string) (string, error) {
+	if len(text) >= 2 && text[0] == '\'' && text[len(text)-1] == '\'' {
+		return text[1 : len(text)-1], nil
+	}
+	return strconv.Unquote(text)
+}
@@ -0,0 +1,157 @@
+package runner
+
+import (
+	"encoding/json"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const jsonPathBodyForTest = `{
+	"items": [
+		{"id": 1, "name": "a", "price": 8, "tags": ["x"]},
+		{"id": 3, "name": "c", "price": 12},
+		{"id": 4, "name": "d", "price": 5}
+	],
+	"meta": {"name": "m", "limit": 10}
+}`
+
+func TestJSONPathQuery(t *testing.T) {
+	var data interface{}
+	assert.Nil(t, json.Unmarshal([]byte(jsonPathBodyForTest), &data))
+
+	tests := []struct {
+		path     string
+		expect   []interface{}
+		definite bool
+	}{{
+		path:     "$",
+		expect:   []interface{}{data},
+		definite: true,
+	}, {
+		path:     "$.meta.name",
+		expect:   []interface{}{"m"},
+		definite: true,
+	}, {
+		path:     `$['meta']["name"]`,
+		expect:   []interface{}{"m"},
+		definite: true,
+	}, {
+		path:     "$.items[-1].name",
+		expect:   []interface{}{"d"},
+		definite: true,
+	}, {
+		path:     "$.items[0].tags[0]",
+		expect:   []interface{}{"x"},
+		definite: true,
+	}, {
+		path:   "$.items[*].name",
+		expect: []interface{}{"a", "c", "d"},
+	}, {
+		path:   "$.meta.*",
+		expect: []interface{}{10.0, "m"},
+	}, {
+		path:   "$..name",
+		expect: []interface{}{"a", "c", "d", "m"},
+	}, {
+		path:   "$.items[0,2].id",
+		expect: []interface{}{1.0, 4.0},
+	}, {
+		path:   "$.items[1:].id",
+		expect: []interface{}{3.0, 4.0},
+	}, {
+		path:   "$.items[::2].id",
+		expect: []interface{}{1.0, 4.0},
+	}, {
+		path:   "$.items[?(@.id==3)].name",
+		expect: []interface{}{"c"},
+	}, {
+		path:   "$.items[?(@.price < $.meta.limit && @.name != 'd')].id",
+		expect: []interface{}{1.0},
+	}, {
+		path:   "$.items[?(@.tags)].id",
+		expect: []interface{}{1.0},
+	}, {
+		path:   "$.items[?(@.id > 10)].name",
+		expect: nil,
+	}, {
+		path:     "$.fake",
+		expect:   nil,
+		definite: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := parseJSONPath(tt.path)
+			if assert.Nil(t, err) {
+				assert.Equal(t, tt.expect, path.query(data))
+				assert.Equal(t, tt.definite, path.definite)
+			}
+		})
+	}
+}
+
+func TestInvalidJSONPath(t *testing.T) {
+	for _, path := range []string{"items", "$items", "$.items[0", "$.items[a]", "$.items[0:1:0]", "$.items[1:2:3:4]", "$.", "$.items[?(@.id ==)]"} {
+		_, err := parseJSONPath(path)
+		assert.NotNil(t, err, path)
+	}
+}
+
+func TestJSONPathFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]interface{}
+		err    string
+	}{{
+		name: "the only matched value",
+		fields: map[string]interface{}{
+			"$.items[?(@.id==3)].name": "c",
+			"$.meta.limit":             10,
+		},
+	}, {
+		name: "all the matched values",
+		fields: map[string]interface{}{
+			"$.items[*].id": []interface{}{1.0, 3.0, 4.0},
+		},
+	}, {
+		name: "mixed with the slash-separated path",
+		fields: map[string]interface{}{
+			"meta/name":   "m",
+			"$.meta.name": "m",
+		},
+	}, {
+		name: "multiple matched values",
+		fields: map[string]interface{}{
+			"$.items[*].id": 1.0,
+		},
+		err: "the JSONPath $.items[*].id matches 3 values, expect a list of them",
+	}, {
+		name: "not found",
+		fields: map[string]interface{}{
+			"$.items[?(@.id==5)].name": "e",
+		},
+		err: "not found field: $.items[?(@.id==5)].name",
+	}, {
+		name: "different value",
+		fields: map[string]interface{}{
+			"$.items[0].name": "b",
+		},
+		err: "field[$.items[0].name] expect value: b, actual: a",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifyResponseBodyData(tt.name, atest.Response{BodyFieldsExpect: tt.fields}, []byte(jsonPathBodyForTest))
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+
+	_, err := verifyResponseBodyData("array", atest.Response{
+		BodyFieldsExpect: map[string]interface{}{"$[1].name": "b"},
+	}, []byte(`[{"name": "a"}, {"name": "b"}]`))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,141 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// jsonRPCResponse is the standard response of JSON-RPC 2.0
+type jsonRPCResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Result  interface{} `json:"result"`
+	Error   *struct {
+		Code    int         `json:"code"`
+		Message string      `json:"message"`
+		Data    interface{} `json:"data"`
+	} `json:"error"`
+}
+
+// verifyJSONRPC checks the responses of the calls, the response should not have an error unless it's expected.
+// The responses of a batch are matched by the ids, and the notifications should not be responded
+func verifyJSONRPC(name string, request *testing.JSONRPC, expect *testing.JSONRPCResponse, body []byte) (err error) {
+	if expect == nil {
+		expect = &testing.JSONRPCResponse{}
+	}
+
+	if len(request.Batch) == 0 {
+		if request.Notification {
+			return
+		}
+		resp := &jsonRPCResponse{}
+		if err = json.Unmarshal(body, resp); err != nil {
+			err = fmt.Errorf("case: %s, invalid JSON-RPC response: %v", name, err)
+		} else if !sameJSONRPCID(request.ID, resp.ID) {
+			err = fmt.Errorf("case: %s, expect the JSON-RPC id %v, actual: %v", name, request.ID, resp.ID)
+		} else if err = verifyJSONRPCResponse(resp, expect); err != nil {
+			err = fmt.Errorf("case: %s, %v", name, err)
+		}
+		return
+	}
+
+	var responses []jsonRPCResponse
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err = json.Unmarshal(body, &responses); err != nil {
+			err = fmt.Errorf("case: %s, invalid JSON-RPC batch response: %v", name, err)
+			return
+		}
+	}
+	for _, call := range request.Batch {
+		var resp *jsonRPCResponse
+		for i := range responses {
+			if !call.Notification && sameJSONRPCID(call.ID, responses[i].ID) {
+				resp = &responses[i]
+				break
+			}
+		}
+		if call.Notification {
+			continue
+		} else if resp == nil {
+			err = fmt.Errorf("case: %s, not found the JSON-RPC response of id %v", name, call.ID)
+			return
+		}
+
+		expected := &testing.JSONRPCResponse{}
+		for i := range expect.Batch {
+			if sameJSONRPCID(expect.Batch[i].ID, call.ID) {
+				expected = &expect.Batch[i]
+				break
+			}
+		}
+		if err = verifyJSONRPCResponse(resp, expected); err != nil {
+			err = fmt.Errorf("case: %s, id %v, %v", name, call.ID, err)
+			return
+		}
+	}
+	return
+}
+
+// isJSONRPCNotified returns true if all the calls are notifications, and the body is empty
+func isJSONRPCNotified(request *testing.JSONRPC, body []byte) bool {
+	if request == nil || len(strings.TrimSpace(string(body))) > 0 {
+		return false
+	}
+	if len(request.Batch) == 0 {
+		return request.Notification
+	}
+	for _, call := range request.Batch {
+		if !call.Notification {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyJSONRPCResponse checks the error and the fields of the result
+func verifyJSONRPCResponse(resp *jsonRPCResponse, expect *testing.JSONRPCResponse) (err error) {
+	if resp.JSONRPC != "2.0" {
+		err = fmt.Errorf("invalid JSON-RPC version: '%s'", resp.JSONRPC)
+		return
+	}
+
+	switch {
+	case expect.Error == nil && resp.Error != nil:
+		err = fmt.Errorf("unexpected JSON-RPC error: %d %s", resp.Error.Code, resp.Error.Message)
+	case expect.Error != nil && resp.Error == nil:
+		err = fmt.Errorf("expect the JSON-RPC error, actual: %v", resp.Result)
+	case expect.Error != nil:
+		if expect.Error.Code != 0 && expect.Error.Code != resp.Error.Code {
+			err = fmt.Errorf("expect the JSON-RPC error code %d, actual: %d", expect.Error.Code, resp.Error.Code)
+		} else if !strings.Contains(resp.Error.Message, expect.Error.Message) {
+			err = fmt.Errorf("expect the JSON-RPC error '%s', actual: %s", expect.Error.Message, resp.Error.Message)
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	fields := make(map[string]interface{}, len(expect.Result))
+	for key, val := range expect.Result {
+		if key == "" {
+			if !fieldEquals(val, resp.Result) {
+				err = fmt.Errorf("JSON-RPC result expect value: %v, actual: %v", val, resp.Result)
+				return
+			}
+			continue
+		}
+		fields[key] = val
+	}
+	if err = verifyFields(resp.Result, fields); err != nil {
+		err = fmt.Errorf("JSON-RPC result %v", err)
+	}
+	return
+}
+
+// sameJSONRPCID compares the ids by the text, the numbers of JSON are float64
+func sameJSONRPCID(expect, actual interface{}) bool {
+	return fmt.Sprintf("%v", expect) == fmt.Sprintf("%v", actual)
+}
@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONRPC(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *atest.JSONRPC
+		payload string
+		status  int
+		body    string
+		expect  *atest.JSONRPCResponse
+		err     string
+	}{{
+		name:    "normal",
+		request: &atest.JSONRPC{Method: "getUser", Params: map[string]interface{}{"id": "{{.id}}"}},
+		payload: `{"jsonrpc":"2.0","method":"getUser","params":{"id":"1"},"id":1}`,
+		body:    `{"jsonrpc":"2.0","result":{"name":"linuxsuren"},"id":1}`,
+		expect:  &atest.JSONRPCResponse{Result: map[string]interface{}{"name": "linuxsuren"}},
+	}, {
+		name:    "the result itself",
+		request: &atest.JSONRPC{Method: "eth_blockNumber", ID: "block"},
+		payload: `{"jsonrpc":"2.0","method":"eth_blockNumber","id":"block"}`,
+		body:    `{"jsonrpc":"2.0","result":"0x10","id":"block"}`,
+		expect:  &atest.JSONRPCResponse{Result: map[string]interface{}{"": "0x10"}},
+	}, {
+		name:    "unexpected error",
+		request: &atest.JSONRPC{Method: "getUser"},
+		payload: `{"jsonrpc":"2.0","method":"getUser","id":1}`,
+		body:    `{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid params"},"id":1}`,
+		err:     "unexpected JSON-RPC error: -32602 invalid params",
+	}, {
+		name:    "expected error",
+		request: &atest.JSONRPC{Method: "getUser"},
+		payload: `{"jsonrpc":"2.0","method":"getUser","id":1}`,
+		body:    `{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid params"},"id":1}`,
+		expect:  &atest.JSONRPCResponse{Error: &atest.JSONRPCError{Code: -32602, Message: "invalid"}},
+	}, {
+		name:    "unexpected error code",
+		request: &atest.JSONRPC{Method: "getUser"},
+		payload: `{"jsonrpc":"2.0","method":"getUser","id":1}`,
+		body:    `{"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"},"id":1}`,
+		expect:  &atest.JSONRPCResponse{Error: &atest.JSONRPCError{Code: -32602}},
+		err:     "expect the JSON-RPC error code -32602, actual: -32601",
+	}, {
+		name:    "mismatched id",
+		request: &atest.JSONRPC{Method: "getUser"},
+		payload: `{"jsonrpc":"2.0","method":"getUser","id":1}`,
+		body:    `{"jsonrpc":"2.0","result":{},"id":2}`,
+		err:     "expect the JSON-RPC id 1, actual: 2",
+	}, {
+		name:    "notification",
+		request: &atest.JSONRPC{Method: "notify", Notification: true},
+		payload: `{"jsonrpc":"2.0","method":"notify"}`,
+		status:  http.StatusNoContent,
+		expect:  &atest.JSONRPCResponse{},
+	}, {
+		name: "batch",
+		request: &atest.JSONRPC{Batch: []atest.JSONRPCCall{
+			{Method: "getUser"}, {Method: "deleteUser"}, {Method: "notify", Notification: true},
+		}},
+		payload: `[{"jsonrpc":"2.0","method":"getUser","id":1},{"jsonrpc":"2.0","method":"deleteUser","id":2},{"jsonrpc":"2.0","method":"notify"}]`,
+		body:    `[{"jsonrpc":"2.0","error":{"code":-32000,"message":"forbidden"},"id":2},{"jsonrpc":"2.0","result":{"name":"linuxsuren"},"id":1}]`,
+		expect: &atest.JSONRPCResponse{Batch: []atest.JSONRPCResponse{
+			{ID: 1, Result: map[string]interface{}{"name": "linuxsuren"}},
+			{ID: 2, Error: &atest.JSONRPCError{Message: "forbidden"}},
+		}},
+	}, {
+		name: "missing response of batch",
+		request: &atest.JSONRPC{Batch: []atest.JSONRPCCall{
+			{Method: "getUser"}, {Method: "deleteUser"},
+		}},
+		payload: `[{"jsonrpc":"2.0","method":"getUser","id":1},{"jsonrpc":"2.0","method":"deleteUser","id":2}]`,
+		body:    `[{"jsonrpc":"2.0","result":{},"id":1}]`,
+		err:     "not found the JSON-RPC response of id 2",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			status := tt.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			gock.New(urlLocalhost).Post("/rpc").MatchType("json").BodyString(tt.payload).
+				Reply(status).SetHeader("Content-Type", "application/json").BodyString(tt.body)
+
+			expect := atest.Response{JSONRPC: tt.expect}
+			if tt.status != 0 {
+				expect.StatusCode = tt.status
+			}
+			_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+				Request: atest.Request{API: urlLocalhost + "/rpc", JSONRPC: tt.request},
+				Expect:  expect,
+			}, map[string]interface{}{"id": "1"}, context.TODO())
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
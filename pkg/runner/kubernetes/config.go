@@ -0,0 +1,65 @@
+package kubernetes
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const defaultNamespace = "default"
+
+// Config is the connection of a Kubernetes cluster
+type Config struct {
+	*rest.Config
+	Namespace string
+}
+
+// LoadConfig loads the config of the context from the kubeconfig file, the current context is used if it's empty.
+// The kubeconfig is searched in order if it's empty: the environment variables KUBERNETES_SERVER and KUBERNETES_TOKEN,
+// the files of the environment variable KUBECONFIG, $HOME/.kube/config, then the in-cluster service account.
+// It follows the loading rules of kubectl, so the exec and auth provider plugins are supported.
+func LoadConfig(kubeconfig, context string) (config *Config, err error) {
+	if kubeconfig == "" {
+		if server, token := os.Getenv("KUBERNETES_SERVER"), os.Getenv("KUBERNETES_TOKEN"); server != "" && token != "" {
+			config = &Config{Config: &rest.Config{Host: server, BearerToken: token}, Namespace: defaultNamespace}
+			return
+		}
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = expandHome(kubeconfig)
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: context})
+
+	var restConfig *rest.Config
+	if restConfig, err = clientConfig.ClientConfig(); err != nil {
+		if clientcmd.IsEmptyConfig(err) {
+			err = fmt.Errorf("no kubeconfig found, and it's not running in a Kubernetes cluster")
+		}
+		return
+	}
+
+	config = &Config{Config: restConfig}
+	if config.Namespace, _, err = clientConfig.Namespace(); err == nil && config.Namespace == "" {
+		config.Namespace = defaultNamespace
+	}
+	return
+}
+
+// HTTPClient returns the client which carries the credential of the config, and trusts the CA of it
+func (c *Config) HTTPClient() (*http.Client, error) {
+	return rest.HTTPClientFor(c.Config)
+}
+
+func expandHome(file string) string {
+	if strings.HasPrefix(file, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			file = filepath.Join(home, file[2:])
+		}
+	}
+	return file
+}
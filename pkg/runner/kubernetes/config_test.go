@@ -0,0 +1,129 @@
+package kubernetes_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		kubeconfig string
+		context    string
+		env        map[string]string
+		verify     func(*testing.T, *kubernetes.Config, error)
+	}{{
+		name:       "current context",
+		kubeconfig: "testdata/kubeconfig",
+		verify: func(t *testing.T, config *kubernetes.Config, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, urlFoo+"/", config.Host)
+			assert.Equal(t, "token", config.BearerToken)
+			assert.Equal(t, "demo", config.Namespace)
+		},
+	}, {
+		name:       "specific context",
+		kubeconfig: "testdata/kubeconfig",
+		context:    "remote",
+		verify: func(t *testing.T, config *kubernetes.Config, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, "https://remote", config.Host)
+			assert.Equal(t, "file-token", strings.TrimSpace(config.BearerToken))
+			assert.Equal(t, "default", config.Namespace)
+			assert.True(t, config.Insecure)
+
+			client, err := config.HTTPClient()
+			assert.Nil(t, err)
+			assert.NotNil(t, client.Transport)
+		},
+	}, {
+		name:       "exec plugin",
+		kubeconfig: "testdata/kubeconfig",
+		context:    "exec",
+		verify: func(t *testing.T, config *kubernetes.Config, err error) {
+			assert.Nil(t, err)
+			if assert.NotNil(t, config.ExecProvider) {
+				assert.Equal(t, "aws", config.ExecProvider.Command)
+				assert.Equal(t, []string{"eks", "get-token", "--cluster-name", "demo"}, config.ExecProvider.Args)
+			}
+		},
+	}, {
+		name:       "context not found",
+		kubeconfig: "testdata/kubeconfig",
+		context:    "fake",
+		verify: func(t *testing.T, config *kubernetes.Config, err error) {
+			assert.ErrorContains(t, err, "context was not found for specified context: fake")
+		},
+	}, {
+		name:       "cluster not found",
+		kubeconfig: "testdata/kubeconfig",
+		context:    "invalid",
+		verify: func(t *testing.T, config *kubernetes.Config, err error) {
+			assert.ErrorContains(t, err, "cluster has no server defined")
+		},
+	}, {
+		name:       "kubeconfig not found",
+		kubeconfig: "testdata/fake",
+		verify: func(t *testing.T, config *kubernetes.Config, err error) {
+			assert.NotNil(t, err)
+		},
+	}, {
+		name: "from the environment variables",
+		env: map[string]string{
+			"KUBERNETES_SERVER": urlFoo,
+			"KUBERNETES_TOKEN":  "token",
+		},
+		verify: func(t *testing.T, config *kubernetes.Config, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, urlFoo, config.Host)
+			assert.Equal(t, "token", config.BearerToken)
+			assert.False(t, config.Insecure)
+		},
+	}, {
+		name: "from KUBECONFIG",
+		env: map[string]string{
+			"KUBECONFIG": "testdata/fake:testdata/kubeconfig",
+		},
+		verify: func(t *testing.T, config *kubernetes.Config, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, "demo", config.Namespace)
+		},
+	}, {
+		name: "not in cluster",
+		env: map[string]string{
+			"KUBECONFIG":              "testdata/fake",
+			"KUBERNETES_SERVICE_HOST": "",
+		},
+		verify: func(t *testing.T, config *kubernetes.Config, err error) {
+			assert.ErrorContains(t, err, "no kubeconfig found")
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBERNETES_SERVER", "")
+			t.Setenv("KUBERNETES_TOKEN", "")
+			for key, val := range tt.env {
+				t.Setenv(key, val)
+			}
+
+			config, err := kubernetes.LoadConfig(tt.kubeconfig, tt.context)
+			tt.verify(t, config, err)
+		})
+	}
+}
+
+func TestHTTPClient(t *testing.T) {
+	client, err := (&kubernetes.Config{Config: &rest.Config{Host: urlFoo}}).HTTPClient()
+	assert.Nil(t, err)
+	assert.NotNil(t, client)
+
+	_, err = (&kubernetes.Config{Config: &rest.Config{
+		Host:            "https://foo",
+		TLSClientConfig: rest.TLSClientConfig{CertData: []byte("fake"), KeyData: []byte("fake")},
+	}}).HTTPClient()
+	assert.NotNil(t, err)
+}
@@ -1,2 +1,2 @@
-// Package kubernetes provides a low level client for small footprint consideration
+// Package kubernetes provides the clients of the Kubernetes resources
 package kubernetes
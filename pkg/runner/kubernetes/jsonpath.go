@@ -0,0 +1,57 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var jsonPathSegment = regexp.MustCompile(`^([^\[\]]*)((?:\[\d+\])*)$`)
+
+// JSONPath returns the field of the object, it supports a subset of the JSONPath of kubectl:
+// the fields and the array indexes, such as: {.spec.ports[0].nodePort}
+func JSONPath(obj interface{}, path string) (result interface{}, err error) {
+	path = strings.TrimSpace(path)
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+
+	result = obj
+	if path == "" {
+		return
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		matches := jsonPathSegment.FindStringSubmatch(segment)
+		if matches == nil {
+			err = fmt.Errorf("invalid JSONPath '%s'", path)
+			return
+		}
+
+		if field := matches[1]; field != "" {
+			m, ok := result.(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf("cannot get '%s' of a non-object in '%s'", field, path)
+				return
+			}
+			if result, ok = m[field]; !ok {
+				err = fmt.Errorf("cannot find '%s' in '%s'", field, path)
+				return
+			}
+		}
+
+		for _, index := range strings.Split(strings.Trim(matches[2], "[]"), "][") {
+			if index == "" {
+				continue
+			}
+			i, _ := strconv.Atoi(index)
+			items, ok := result.([]interface{})
+			if !ok || i >= len(items) {
+				err = fmt.Errorf("index %d is out of range in '%s'", i, path)
+				return
+			}
+			result = items[i]
+		}
+	}
+	return
+}
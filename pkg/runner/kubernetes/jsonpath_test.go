@@ -0,0 +1,46 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONPath(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"clusterIP": "10.0.0.1",
+			"ports": []interface{}{map[string]interface{}{
+				"nodePort": float64(30080),
+			}},
+		},
+		"matrix": []interface{}{[]interface{}{"a", "b"}},
+	}
+
+	tests := []struct {
+		path   string
+		expect interface{}
+		err    string
+	}{
+		{path: "{.spec.clusterIP}", expect: "10.0.0.1"},
+		{path: ".spec.ports[0].nodePort", expect: float64(30080)},
+		{path: "$.matrix[0][1]", expect: "b"},
+		{path: "", expect: obj},
+		{path: ".spec.fake", err: "cannot find 'fake'"},
+		{path: ".spec.ports[1]", err: "index 1 is out of range"},
+		{path: ".spec.clusterIP.fake", err: "cannot get 'fake' of a non-object"},
+		{path: ".spec.ports[a]", err: "invalid JSONPath"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			result, err := kubernetes.JSONPath(obj, tt.path)
+			if tt.err == "" {
+				assert.Nil(t, err)
+				assert.Equal(t, tt.expect, result)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
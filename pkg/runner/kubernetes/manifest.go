@@ -0,0 +1,447 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// PollInterval is the interval of checking the rollout, the pods and the conditions
+var PollInterval = 2 * time.Second
+
+const (
+	// conditionFailed is the terminal condition of the resources, such as the Jobs
+	conditionFailed = "Failed"
+	fieldManager    = "atest"
+)
+
+// ManifestClient applies and deletes the manifests via the API server, it's a small replacement of kubectl
+type ManifestClient interface {
+	// Apply creates or updates the objects of the manifest via the server-side apply
+	Apply(ctx context.Context, manifest []byte) error
+	// Delete deletes the objects of the manifest in the reverse order, the absent objects are ignored
+	Delete(ctx context.Context, manifest []byte) error
+	// WaitRollout waits until the workload is rolled out, such as: deployment/nginx, statefulset/db, daemonset/agent
+	WaitRollout(ctx context.Context, workload string) error
+	// WaitPods waits until the pods which match the label selector are ready
+	WaitPods(ctx context.Context, selector string) error
+	// Get returns a resource of the manifest from the API server, the resource is like: Service/nginx
+	Get(ctx context.Context, manifest []byte, resource string) (map[string]interface{}, error)
+	// WaitCondition waits until the condition of a resource of the manifest is True, such as: Job/migrate and Complete
+	WaitCondition(ctx context.Context, manifest []byte, resource, condition string) error
+}
+
+type manifestClient struct {
+	namespace string
+	client    dynamic.Interface
+	mapper    meta.RESTMapper
+}
+
+// NewManifestClient creates a manifest client, the namespace of the config is used if the namespace is empty
+func NewManifestClient(config *Config, namespace string) (client ManifestClient, err error) {
+	if namespace == "" {
+		namespace = config.Namespace
+	}
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	var httpClient *http.Client
+	if httpClient, err = config.HTTPClient(); err != nil {
+		return
+	}
+
+	var dynamicClient dynamic.Interface
+	if dynamicClient, err = dynamic.NewForConfigAndClient(config.Config, httpClient); err != nil {
+		return
+	}
+	var discoveryClient discovery.DiscoveryInterface
+	if discoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(config.Config, httpClient); err == nil {
+		client = &manifestClient{
+			namespace: namespace,
+			client:    dynamicClient,
+			mapper:    restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		}
+	}
+	return
+}
+
+// ReadManifest reads the manifest from a URL, a file, or all the YAML and JSON files of a directory
+func ReadManifest(path string) (data []byte, err error) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		var resp *http.Response
+		if resp, err = http.Get(path); err != nil {
+			return
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("failed to get %s, status code: %d", path, resp.StatusCode)
+			return
+		}
+		return io.ReadAll(resp.Body)
+	}
+
+	var info os.FileInfo
+	if info, err = os.Stat(path); err != nil || !info.IsDir() {
+		if err == nil {
+			data, err = os.ReadFile(path)
+		}
+		return
+	}
+
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml", "*.json"} {
+		var matches []string
+		if matches, err = filepath.Glob(filepath.Join(path, pattern)); err != nil {
+			return
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	buf := new(bytes.Buffer)
+	for _, file := range files {
+		var fileData []byte
+		if fileData, err = os.ReadFile(file); err != nil {
+			return
+		}
+		buf.WriteString("\n---\n")
+		buf.Write(fileData)
+	}
+	data = buf.Bytes()
+	return
+}
+
+var documentSeparator = regexp.MustCompile(`(?m)^---[ \t]*(#.*)?$`)
+
+// ParseManifest parses the YAML documents of the manifest, the items of a List are expanded
+func ParseManifest(manifest []byte) (objects []map[string]interface{}, err error) {
+	for _, doc := range documentSeparator.Split(string(manifest), -1) {
+		var data []byte
+		if data, err = yaml.YAMLToJSON([]byte(doc)); err != nil {
+			return
+		}
+
+		obj := map[string]interface{}{}
+		if err = json.Unmarshal(data, &obj); err != nil || len(obj) == 0 {
+			if err != nil {
+				err = fmt.Errorf("invalid manifest: %v", err)
+				return
+			}
+			continue
+		}
+
+		if items, ok := obj["items"].([]interface{}); ok && strings.HasSuffix(stringField(obj, "kind"), "List") {
+			for _, item := range items {
+				if itemObj, ok := item.(map[string]interface{}); ok {
+					objects = append(objects, itemObj)
+				}
+			}
+		} else {
+			objects = append(objects, obj)
+		}
+	}
+	return
+}
+
+// Apply applies the objects one by one
+func (c *manifestClient) Apply(ctx context.Context, manifest []byte) (err error) {
+	var objects []map[string]interface{}
+	if objects, err = ParseManifest(manifest); err != nil {
+		return
+	}
+
+	for _, item := range objects {
+		obj := &unstructured.Unstructured{Object: item}
+		var resource dynamic.ResourceInterface
+		if resource, err = c.resourceOf(obj); err != nil {
+			return
+		}
+
+		if _, err = resource.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: fieldManager, Force: true}); err != nil {
+			err = fmt.Errorf("failed to apply %s %s: %v", obj.GetKind(), obj.GetName(), err)
+			return
+		}
+	}
+	return
+}
+
+// Delete deletes the objects in the reverse order
+func (c *manifestClient) Delete(ctx context.Context, manifest []byte) (err error) {
+	var objects []map[string]interface{}
+	if objects, err = ParseManifest(manifest); err != nil {
+		return
+	}
+
+	for i := len(objects) - 1; i >= 0; i-- {
+		obj := &unstructured.Unstructured{Object: objects[i]}
+		var resource dynamic.ResourceInterface
+		if resource, err = c.resourceOf(obj); err != nil {
+			return
+		}
+
+		if err = resource.Delete(ctx, obj.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			err = fmt.Errorf("failed to delete %s %s: %v", obj.GetKind(), obj.GetName(), err)
+			return
+		}
+		err = nil
+	}
+	return
+}
+
+// WaitRollout checks the status of the workload until it's rolled out
+func (c *manifestClient) WaitRollout(ctx context.Context, workload string) error {
+	kind, name, ok := strings.Cut(workload, "/")
+	if !ok {
+		return fmt.Errorf("invalid workload '%s', it should be like: deployment/nginx", workload)
+	}
+
+	var resource string
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments", "deploy":
+		resource = "deployments"
+	case "statefulset", "statefulsets", "sts":
+		resource = "statefulsets"
+	case "daemonset", "daemonsets", "ds":
+		resource = "daemonsets"
+	default:
+		return fmt.Errorf("not supported workload kind '%s'", kind)
+	}
+
+	workloads := c.client.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: resource}).Namespace(c.namespace)
+	return c.poll(ctx, workload, func() (ready bool, err error) {
+		var obj *unstructured.Unstructured
+		if obj, err = workloads.Get(ctx, name, metav1.GetOptions{}); err == nil {
+			ready = rolledOut(resource, obj.Object)
+		}
+		return
+	})
+}
+
+// WaitPods checks the pods until all of them are ready, there should be one pod at least
+func (c *manifestClient) WaitPods(ctx context.Context, selector string) error {
+	pods := c.client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace(c.namespace)
+	return c.poll(ctx, "pods "+selector, func() (ready bool, err error) {
+		var list *unstructured.UnstructuredList
+		if list, err = pods.List(ctx, metav1.ListOptions{LabelSelector: selector}); err == nil {
+			ready = len(list.Items) > 0
+			for _, pod := range list.Items {
+				ready = ready && podReady(pod.Object)
+			}
+		}
+		return
+	})
+}
+
+// Get finds the resource in the manifest by the kind (case-insensitive) and the name, then gets it from the API server
+func (c *manifestClient) Get(ctx context.Context, manifest []byte, resource string) (result map[string]interface{}, err error) {
+	var obj *unstructured.Unstructured
+	var client dynamic.ResourceInterface
+	if obj, client, err = c.findResource(manifest, resource); err != nil {
+		return
+	}
+
+	if obj, err = client.Get(ctx, obj.GetName(), metav1.GetOptions{}); err == nil {
+		result = obj.Object
+	}
+	return
+}
+
+// WaitCondition checks the resource of the manifest until the status of the condition is True.
+// It stops early if the Failed condition is True, such as a failed Job
+func (c *manifestClient) WaitCondition(ctx context.Context, manifest []byte, resource, condition string) (err error) {
+	var obj *unstructured.Unstructured
+	var client dynamic.ResourceInterface
+	if obj, client, err = c.findResource(manifest, resource); err != nil {
+		return
+	}
+
+	var failure error
+	if err = c.poll(ctx, resource+" "+condition, func() (done bool, err error) {
+		var current *unstructured.Unstructured
+		if current, err = client.Get(ctx, obj.GetName(), metav1.GetOptions{}); err != nil {
+			return
+		}
+		if done = conditionTrue(current.Object, condition); !done && !strings.EqualFold(condition, conditionFailed) {
+			if message, failed := conditionFailure(current.Object); failed {
+				failure = fmt.Errorf("%s is failed: %s", resource, message)
+				done = true
+			}
+		}
+		return
+	}); err == nil {
+		err = failure
+	}
+	return
+}
+
+// findResource finds the resource in the manifest by the kind (case-insensitive) and the name, then returns the client of it
+func (c *manifestClient) findResource(manifest []byte, resource string) (obj *unstructured.Unstructured, client dynamic.ResourceInterface, err error) {
+	kind, name, ok := strings.Cut(resource, "/")
+	if !ok {
+		err = fmt.Errorf("invalid resource '%s', it should be like: Service/nginx", resource)
+		return
+	}
+
+	var objects []map[string]interface{}
+	if objects, err = ParseManifest(manifest); err != nil {
+		return
+	}
+
+	for _, item := range objects {
+		obj = &unstructured.Unstructured{Object: item}
+		if strings.EqualFold(obj.GetKind(), kind) && obj.GetName() == name {
+			client, err = c.resourceOf(obj)
+			return
+		}
+	}
+	obj = nil
+	err = fmt.Errorf("cannot find %s in the manifest", resource)
+	return
+}
+
+// poll checks the condition until it's true, or the context is done. The errors of the checks are ignored except the last one.
+func (c *manifestClient) poll(ctx context.Context, target string, check func() (bool, error)) (err error) {
+	for {
+		var ready bool
+		if ready, err = check(); ready {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				err = fmt.Errorf("timeout waiting for %s", target)
+			} else {
+				err = fmt.Errorf("timeout waiting for %s: %v", target, err)
+			}
+			return
+		case <-time.After(PollInterval):
+		}
+	}
+}
+
+// resourceOf returns the client of the object, the resource is found via the discovery API.
+// The namespace of the client is set to the namespaced object if it has no namespace
+func (c *manifestClient) resourceOf(obj *unstructured.Unstructured) (client dynamic.ResourceInterface, err error) {
+	gvk := obj.GroupVersionKind()
+	if gvk.Version == "" || gvk.Kind == "" || obj.GetName() == "" {
+		err = fmt.Errorf("apiVersion, kind and metadata.name are required")
+		return
+	}
+
+	var mapping *meta.RESTMapping
+	if mapping, err = c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		err = fmt.Errorf("cannot find the resource of %s in %s: %v", gvk.Kind, obj.GetAPIVersion(), err)
+		return
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		client = c.client.Resource(mapping.Resource)
+		return
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(c.namespace)
+	}
+	client = c.client.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+	return
+}
+
+// rolledOut returns true if the latest generation is observed, and all the replicas are updated and available
+func rolledOut(resource string, obj map[string]interface{}) bool {
+	if intField(obj, "status", "observedGeneration") < intField(obj, "metadata", "generation") {
+		return false
+	}
+
+	switch resource {
+	case "daemonsets":
+		desired := intField(obj, "status", "desiredNumberScheduled")
+		return intField(obj, "status", "updatedNumberScheduled") == desired &&
+			intField(obj, "status", "numberAvailable") == desired
+	case "statefulsets":
+		replicas := replicasOf(obj)
+		return intField(obj, "status", "updatedReplicas") == replicas &&
+			intField(obj, "status", "readyReplicas") == replicas
+	default:
+		replicas := replicasOf(obj)
+		return intField(obj, "status", "updatedReplicas") == replicas &&
+			intField(obj, "status", "replicas") == replicas &&
+			intField(obj, "status", "availableReplicas") == replicas
+	}
+}
+
+func podReady(pod map[string]interface{}) bool {
+	return conditionTrue(pod, "Ready")
+}
+
+// conditionFailure returns the message of the Failed condition if it's True, or the reason if there's no message
+func conditionFailure(obj map[string]interface{}) (message string, failed bool) {
+	status, _ := obj["status"].(map[string]interface{})
+	conditions, _ := status["conditions"].([]interface{})
+	for _, item := range conditions {
+		if condition, ok := item.(map[string]interface{}); ok {
+			if name, _ := condition["type"].(string); strings.EqualFold(name, conditionFailed) && condition["status"] == "True" {
+				message, _ = condition["message"].(string)
+				if message == "" {
+					message, _ = condition["reason"].(string)
+				}
+				return message, true
+			}
+		}
+	}
+	return
+}
+
+// conditionTrue returns true if the status of the condition is True, the type is case-insensitive
+func conditionTrue(obj map[string]interface{}, conditionType string) bool {
+	status, _ := obj["status"].(map[string]interface{})
+	conditions, _ := status["conditions"].([]interface{})
+	for _, item := range conditions {
+		if condition, ok := item.(map[string]interface{}); ok {
+			if name, _ := condition["type"].(string); strings.EqualFold(name, conditionType) {
+				return condition["status"] == "True"
+			}
+		}
+	}
+	return false
+}
+
+// replicasOf returns the replicas of the spec, default is 1
+func replicasOf(obj map[string]interface{}) int64 {
+	if replicas, found, err := unstructured.NestedInt64(obj, "spec", "replicas"); found && err == nil {
+		return replicas
+	}
+	return 1
+}
+
+func intField(obj map[string]interface{}, fields ...string) int64 {
+	val, _, _ := unstructured.NestedInt64(obj, fields...)
+	return val
+}
+
+func stringField(obj map[string]interface{}, field string) string {
+	val, _ := obj[field].(string)
+	return val
+}
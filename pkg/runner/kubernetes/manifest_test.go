@@ -0,0 +1,219 @@
+package kubernetes_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+func TestParseManifest(t *testing.T) {
+	data, err := os.ReadFile("testdata/manifest.yaml")
+	assert.Nil(t, err)
+
+	objects, err := kubernetes.ParseManifest(data)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(objects)) {
+		assert.Equal(t, "Namespace", objects[0]["kind"])
+		assert.Equal(t, "ConfigMap", objects[1]["kind"])
+	}
+
+	_, err = kubernetes.ParseManifest([]byte("[fake"))
+	assert.NotNil(t, err)
+}
+
+func TestReadManifest(t *testing.T) {
+	data, err := kubernetes.ReadManifest("testdata/manifest.yaml")
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "kind: Namespace")
+
+	data, err = kubernetes.ReadManifest("testdata")
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "kind: Namespace")
+
+	_, err = kubernetes.ReadManifest("testdata/fake.yaml")
+	assert.NotNil(t, err)
+
+	defer gock.Off()
+	gock.New(urlFoo).Get("/manifest.yaml").Reply(http.StatusOK).BodyString("kind: Namespace")
+	gock.New(urlFoo).Get("/fake.yaml").Reply(http.StatusNotFound)
+	data, err = kubernetes.ReadManifest(urlFoo + "/manifest.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "kind: Namespace", string(data))
+
+	_, err = kubernetes.ReadManifest(urlFoo + "/fake.yaml")
+	assert.ErrorContains(t, err, "status code: 404")
+}
+
+func TestManifestClient(t *testing.T) {
+	manifest, err := os.ReadFile("testdata/manifest.yaml")
+	assert.Nil(t, err)
+
+	defer gock.Off()
+	mockDiscovery()
+	gock.New(urlFoo).Patch("/api/v1/namespaces/demo$").MatchHeader("Authorization", defaultToken).
+		MatchParam("fieldManager", "atest").MatchParam("force", "true").
+		Reply(http.StatusOK).JSON(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"demo"}}`)
+	gock.New(urlFoo).Patch("/api/v1/namespaces/ns/configmaps/config").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			data, err := io.ReadAll(req.Body)
+			return strings.Contains(string(data), `"namespace":"ns"`), err
+		}).Reply(http.StatusCreated).JSON(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config"}}`)
+	gock.New(urlFoo).Delete("/api/v1/namespaces/ns/configmaps/config").Reply(http.StatusNotFound).JSON(`{}`)
+	gock.New(urlFoo).Delete("/api/v1/namespaces/demo$").Reply(http.StatusOK).JSON(`{}`)
+
+	client, err := kubernetes.NewManifestClient(newConfig("token"), "ns")
+	assert.Nil(t, err)
+	assert.Nil(t, client.Apply(context.TODO(), manifest))
+	assert.Nil(t, client.Delete(context.TODO(), manifest))
+	assert.True(t, gock.IsDone())
+
+	gock.New(urlFoo).Patch("/api/v1/namespaces/demo$").Reply(http.StatusForbidden).JSON(`{}`)
+	assert.ErrorContains(t, client.Apply(context.TODO(), manifest), "failed to apply Namespace demo")
+
+	gock.New(urlFoo).Delete("/api/v1/namespaces/ns/configmaps/config").Reply(http.StatusForbidden).JSON(`{}`)
+	assert.ErrorContains(t, client.Delete(context.TODO(), manifest), "failed to delete ConfigMap config")
+
+	gock.New(urlFoo).Get("/api/v1/namespaces/ns/configmaps/config").Reply(http.StatusOK).
+		JSON(`{"apiVersion":"v1","kind":"ConfigMap","data":{"key":"value"}}`)
+	obj, err := client.Get(context.TODO(), manifest, "configmap/config")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"data":       map[string]interface{}{"key": "value"},
+	}, obj)
+
+	_, err = client.Get(context.TODO(), manifest, "Secret/config")
+	assert.ErrorContains(t, err, "cannot find Secret/config in the manifest")
+	_, err = client.Get(context.TODO(), manifest, "config")
+	assert.ErrorContains(t, err, "invalid resource")
+
+	assert.ErrorContains(t, client.Apply(context.TODO(), []byte("kind: Pod")), "apiVersion, kind and metadata.name are required")
+	assert.ErrorContains(t, client.Apply(context.TODO(), []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: fake`)), "cannot find the resource of Pod in v1")
+}
+
+func TestWaitRollout(t *testing.T) {
+	kubernetes.PollInterval = time.Millisecond
+	defer gock.Off()
+	gock.Intercept()
+
+	client, err := kubernetes.NewManifestClient(&kubernetes.Config{Config: &rest.Config{Host: urlFoo}, Namespace: "ns"}, "")
+	assert.Nil(t, err)
+
+	gock.New(urlFoo).Get("/apis/apps/v1/namespaces/ns/deployments/nginx").Reply(http.StatusOK).
+		JSON(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"generation":2},"status":{"observedGeneration":1}}`)
+	gock.New(urlFoo).Get("/apis/apps/v1/namespaces/ns/deployments/nginx").Reply(http.StatusOK).
+		JSON(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"generation":2},"spec":{"replicas":2},` +
+			`"status":{"observedGeneration":2,"replicas":2,"updatedReplicas":2,"availableReplicas":2}}`)
+	assert.Nil(t, client.WaitRollout(context.TODO(), "deploy/nginx"))
+
+	gock.New(urlFoo).Get("/apis/apps/v1/namespaces/ns/statefulsets/db").Reply(http.StatusOK).
+		JSON(`{"apiVersion":"apps/v1","kind":"StatefulSet","status":{"updatedReplicas":1,"readyReplicas":1}}`)
+	assert.Nil(t, client.WaitRollout(context.TODO(), "statefulset/db"))
+
+	gock.New(urlFoo).Get("/apis/apps/v1/namespaces/ns/daemonsets/agent").Reply(http.StatusOK).
+		JSON(`{"apiVersion":"apps/v1","kind":"DaemonSet","status":{"desiredNumberScheduled":3,"updatedNumberScheduled":3,"numberAvailable":3}}`)
+	assert.Nil(t, client.WaitRollout(context.TODO(), "ds/agent"))
+
+	gock.New(urlFoo).Get("/apis/apps/v1/namespaces/ns/deployments/fake").Persist().Reply(http.StatusNotFound).JSON(`{}`)
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorContains(t, client.WaitRollout(ctx, "deployment/fake"), "timeout waiting for deployment/fake")
+
+	assert.ErrorContains(t, client.WaitRollout(context.TODO(), "nginx"), "invalid workload")
+	assert.ErrorContains(t, client.WaitRollout(context.TODO(), "job/nginx"), "not supported workload kind")
+}
+
+func TestWaitPods(t *testing.T) {
+	kubernetes.PollInterval = time.Millisecond
+	defer gock.Off()
+	gock.Intercept()
+
+	client, err := kubernetes.NewManifestClient(newConfig(""), "ns")
+	assert.Nil(t, err)
+
+	gock.New(urlFoo).Get("/api/v1/namespaces/ns/pods").MatchParam("labelSelector", "app=nginx").Reply(http.StatusOK).
+		JSON(`{"apiVersion":"v1","kind":"PodList","items":[]}`)
+	gock.New(urlFoo).Get("/api/v1/namespaces/ns/pods").MatchParam("labelSelector", "app=nginx").Reply(http.StatusOK).
+		JSON(`{"apiVersion":"v1","kind":"PodList","items":[{"status":{"conditions":[{"type":"Ready","status":"False"}]}}]}`)
+	gock.New(urlFoo).Get("/api/v1/namespaces/ns/pods").MatchParam("labelSelector", "app=nginx").Reply(http.StatusOK).
+		JSON(`{"apiVersion":"v1","kind":"PodList","items":[{"status":{"conditions":[{"type":"Ready","status":"True"}]}}]}`)
+	assert.Nil(t, client.WaitPods(context.TODO(), "app=nginx"))
+	assert.True(t, gock.IsDone())
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	gock.New(urlFoo).Get("/api/v1/namespaces/ns/pods").Reply(http.StatusOK).JSON(`{"apiVersion":"v1","kind":"PodList","items":[]}`)
+	assert.ErrorContains(t, client.WaitPods(ctx, "app=fake"), "timeout waiting for pods app=fake")
+}
+
+func TestWaitCondition(t *testing.T) {
+	kubernetes.PollInterval = time.Millisecond
+	defer gock.Off()
+
+	manifest := []byte(`apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate`)
+	const jobAPI = "/apis/batch/v1/namespaces/ns/jobs/migrate"
+
+	mockDiscovery()
+	gock.New(urlFoo).Get(jobAPI).Reply(http.StatusOK).
+		JSON(`{"apiVersion":"batch/v1","kind":"Job","status":{}}`)
+	gock.New(urlFoo).Get(jobAPI).Reply(http.StatusOK).
+		JSON(`{"apiVersion":"batch/v1","kind":"Job","status":{"conditions":[{"type":"Complete","status":"True"}]}}`)
+	client, err := kubernetes.NewManifestClient(newConfig(""), "ns")
+	assert.Nil(t, err)
+	assert.Nil(t, client.WaitCondition(context.TODO(), manifest, "job/migrate", "complete"))
+	assert.True(t, gock.IsDone())
+
+	// stop early once the job is failed
+	gock.New(urlFoo).Get(jobAPI).Reply(http.StatusOK).
+		JSON(`{"apiVersion":"batch/v1","kind":"Job","status":{"conditions":[{"type":"Failed","status":"True",` +
+			`"reason":"BackoffLimitExceeded","message":"Job has reached the specified backoff limit"}]}}`)
+	assert.EqualError(t, client.WaitCondition(context.TODO(), manifest, "Job/migrate", "Complete"),
+		"Job/migrate is failed: Job has reached the specified backoff limit")
+	assert.True(t, gock.IsDone())
+
+	// the Failed condition could be waited as well
+	gock.New(urlFoo).Get(jobAPI).Reply(http.StatusOK).
+		JSON(`{"apiVersion":"batch/v1","kind":"Job","status":{"conditions":[{"type":"Failed","status":"True"}]}}`)
+	assert.Nil(t, client.WaitCondition(context.TODO(), manifest, "Job/migrate", "Failed"))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	gock.New(urlFoo).Get(jobAPI).Reply(http.StatusOK).
+		JSON(`{"apiVersion":"batch/v1","kind":"Job","status":{"conditions":[{"type":"Failed","status":"False"}]}}`)
+	assert.ErrorContains(t, client.WaitCondition(ctx, manifest, "Job/migrate", "Complete"), "timeout waiting for Job/migrate Complete")
+
+	assert.ErrorContains(t, client.WaitCondition(context.TODO(), manifest, "Job/fake", "Ready"), "cannot find Job/fake in the manifest")
+}
+
+func newConfig(token string) *kubernetes.Config {
+	return &kubernetes.Config{Config: &rest.Config{Host: urlFoo, BearerToken: token}}
+}
+
+// mockDiscovery mocks the discovery API of the core and the batch groups, it's requested once by a manifest client
+func mockDiscovery() {
+	gock.New(urlFoo).Get("/api$").Reply(http.StatusOK).JSON(`{"kind":"APIVersions","versions":["v1"]}`)
+	gock.New(urlFoo).Get("/apis$").Reply(http.StatusOK).JSON(`{"kind":"APIGroupList","apiVersion":"v1","groups":[{"name":"batch",` +
+		`"versions":[{"groupVersion":"batch/v1","version":"v1"}],"preferredVersion":{"groupVersion":"batch/v1","version":"v1"}}]}`)
+	gock.New(urlFoo).Get("/api/v1$").Reply(http.StatusOK).JSON(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"v1",` +
+		`"resources":[{"name":"namespaces","namespaced":false,"kind":"Namespace"},` +
+		`{"name":"namespaces/status","namespaced":false,"kind":"Namespace"},` +
+		`{"name":"configmaps","namespaced":true,"kind":"ConfigMap"}]}`)
+	gock.New(urlFoo).Get("/apis/batch/v1$").Reply(http.StatusOK).JSON(`{"kind":"APIResourceList","apiVersion":"v1",` +
+		`"groupVersion":"batch/v1","resources":[{"name":"jobs","namespaced":true,"kind":"Job"}]}`)
+}
@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Load runs an iteration, such as a test suite, repeatedly with the virtual users
+type Load struct {
+	// VirtualUsers is the count of the iterations which run in parallel, default is 1
+	VirtualUsers int
+	// Duration stops starting the new iterations once it's over
+	Duration time.Duration
+	// Iterations is the total count of the iterations of all the virtual users.
+	// It's one if both the duration and the iterations are zero
+	Iterations int
+	// RampUp starts the virtual users evenly during it
+	RampUp time.Duration
+	// WarmUpIterations are the first iterations which are flagged as the warm-up, they're in addition to the iterations
+	WarmUpIterations int
+	// WarmUp flags the iterations which start during it as the warm-up, it's in addition to the duration
+	WarmUp time.Duration
+}
+
+// Run runs the iteration until the duration is over, or all the iterations are started, or one of them is failed.
+// The stop channel of the iteration is closed once it should not start more requests, the running ones are not canceled.
+// The warm-up iterations run first, the records of them should be excluded from the statistics
+func (l Load) Run(ctx context.Context, iteration func(stop <-chan struct{}, warmUp bool) error) (err error) {
+	users := l.VirtualUsers
+	if users <= 0 {
+		users = 1
+	}
+	iterations := int64(l.Iterations)
+	if l.Duration <= 0 && iterations <= 0 {
+		iterations = 1
+	}
+	warmUps := int64(l.WarmUpIterations)
+	if iterations > 0 {
+		iterations += warmUps
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	stopCtx := groupCtx
+	if l.Duration > 0 {
+		var cancel context.CancelFunc
+		stopCtx, cancel = context.WithTimeout(groupCtx, l.WarmUp+l.Duration)
+		defer cancel()
+	}
+
+	var started int64
+	begin := time.Now()
+	for i := 0; i < users; i++ {
+		delay := l.RampUp * time.Duration(i) / time.Duration(users)
+		group.Go(func() error {
+			if delay > 0 {
+				select {
+				case <-stopCtx.Done():
+					return nil
+				case <-time.After(delay):
+				}
+			}
+
+			for stopCtx.Err() == nil {
+				index := atomic.AddInt64(&started, 1)
+				if iterations > 0 && index > iterations {
+					break
+				}
+				warmUp := index <= warmUps || time.Since(begin) < l.WarmUp
+				if err := iteration(stopCtx.Done(), warmUp); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	err = group.Wait()
+	return
+}
@@ -0,0 +1,113 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	count := func(load Load, iteration func(int64) error) (total int64, err error) {
+		err = load.Run(context.Background(), func(stop <-chan struct{}, warmUp bool) error {
+			return iteration(atomic.AddInt64(&total, 1))
+		})
+		return
+	}
+
+	t.Run("once by default", func(t *testing.T) {
+		total, err := count(Load{VirtualUsers: 3}, func(int64) error { return nil })
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("iterations", func(t *testing.T) {
+		total, err := count(Load{VirtualUsers: 3, Iterations: 10}, func(int64) error { return nil })
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), total)
+	})
+
+	t.Run("duration", func(t *testing.T) {
+		begin := time.Now()
+		total, err := count(Load{VirtualUsers: 2, Duration: 100 * time.Millisecond}, func(int64) error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Greater(t, total, int64(2))
+		assert.Less(t, time.Since(begin), time.Second)
+	})
+
+	t.Run("the iterations are done before the duration", func(t *testing.T) {
+		begin := time.Now()
+		total, err := count(Load{Duration: time.Minute, Iterations: 3}, func(int64) error { return nil })
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Less(t, time.Since(begin), time.Second)
+	})
+
+	t.Run("stop once failed", func(t *testing.T) {
+		total, err := count(Load{Iterations: 10}, func(index int64) error {
+			if index == 2 {
+				return errors.New("fake")
+			}
+			return nil
+		})
+		assert.Error(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("ramp up", func(t *testing.T) {
+		begin := time.Now()
+		total, err := count(Load{VirtualUsers: 2, Iterations: 2, RampUp: 100 * time.Millisecond}, func(int64) error {
+			time.Sleep(60 * time.Millisecond)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.GreaterOrEqual(t, time.Since(begin), 100*time.Millisecond)
+	})
+
+	t.Run("stop channel", func(t *testing.T) {
+		load := Load{Duration: 50 * time.Millisecond}
+		err := load.Run(context.Background(), func(stop <-chan struct{}, warmUp bool) error {
+			<-stop
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("warm-up iterations", func(t *testing.T) {
+		var warmUps, total int64
+		load := Load{VirtualUsers: 2, Iterations: 3, WarmUpIterations: 2}
+		err := load.Run(context.Background(), func(stop <-chan struct{}, warmUp bool) error {
+			if warmUp {
+				atomic.AddInt64(&warmUps, 1)
+			}
+			atomic.AddInt64(&total, 1)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), warmUps)
+		assert.Equal(t, int64(5), total, "the warm-up iterations are in addition to the iterations")
+	})
+
+	t.Run("warm-up duration", func(t *testing.T) {
+		var warmUps, total int64
+		load := Load{Duration: 50 * time.Millisecond, WarmUp: 50 * time.Millisecond}
+		err := load.Run(context.Background(), func(stop <-chan struct{}, warmUp bool) error {
+			if warmUp {
+				atomic.AddInt64(&warmUps, 1)
+			}
+			atomic.AddInt64(&total, 1)
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Greater(t, warmUps, int64(0))
+		assert.Greater(t, total, warmUps)
+	})
+}
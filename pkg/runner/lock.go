@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/lock"
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const (
+	defaultLockTimeout = 10 * time.Minute
+	defaultLockTTL     = time.Hour
+)
+
+// lockDir is the directory of the file locks, it's shared by all the processes of the same host
+var lockDir = filepath.Join(os.TempDir(), "atest-locks")
+
+// acquireLock blocks until the lock is acquired, the unlock is a no-op if there is no lock.
+// The unlock is nil if it's failed to acquire the lock.
+func (r *simpleTestCaseRunner) acquireLock(ctx context.Context, item *testing.Lock) (unlock func() error, err error) {
+	if item == nil {
+		unlock = func() error { return nil }
+		return
+	}
+
+	var timeout, ttl time.Duration
+	if timeout, err = parseDurationOrDefault(item.Timeout, defaultLockTimeout); err != nil {
+		return
+	}
+	if ttl, err = parseDurationOrDefault(item.TTL, defaultLockTTL); err != nil {
+		return
+	}
+
+	var locker lock.Locker
+	switch item.Kind {
+	case "", testing.LockKindFile:
+		locker = lock.NewFileLocker(lockDir, ttl)
+	case testing.LockKindConfigMap:
+		var config *kubernetes.Config
+		if config, err = kubernetes.LoadConfig(item.Kubeconfig, item.Context); err == nil {
+			locker, err = lock.NewConfigMapLocker(config, item.Namespace, ttl)
+		}
+	default:
+		err = fmt.Errorf("unknown kind of the lock '%s'", item.Kind)
+	}
+	if err != nil {
+		return
+	}
+
+	log := r.log.Component(ComponentPrepare)
+	log.Info("prepare: acquire the lock %s\n", item.Name)
+	lockCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err = locker.Lock(lockCtx, item.Name); err != nil {
+		err = fmt.Errorf("failed to acquire the lock %s: %v", item.Name, err)
+		return
+	}
+
+	unlock = func() (err error) {
+		log.Info("clean: release the lock %s\n", item.Name)
+		if err = locker.Unlock(item.Name); err != nil {
+			err = fmt.Errorf("failed to release the lock %s: %v", item.Name, err)
+		}
+		return
+	}
+	return
+}
@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	"github.com/linuxsuren/api-testing/pkg/lock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquireLock(t *testing.T) {
+	lockDir = t.TempDir()
+	lock.RetryInterval = time.Millisecond
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+
+	unlock, err := runner.acquireLock(context.TODO(), nil)
+	assert.Nil(t, err)
+	assert.Nil(t, unlock())
+
+	unlock, err = runner.acquireLock(context.TODO(), &atest.Lock{Name: "env"})
+	assert.Nil(t, err)
+	assert.FileExists(t, filepath.Join(lockDir, "env.lock"))
+
+	_, err = runner.acquireLock(context.TODO(), &atest.Lock{Name: "env", Timeout: "10ms"})
+	assert.ErrorContains(t, err, "failed to acquire the lock env")
+
+	assert.Nil(t, unlock())
+	assert.NoFileExists(t, filepath.Join(lockDir, "env.lock"))
+
+	_, err = runner.acquireLock(context.TODO(), &atest.Lock{Name: "env", Kind: "fake"})
+	assert.ErrorContains(t, err, "unknown kind of the lock 'fake'")
+
+	_, err = runner.acquireLock(context.TODO(), &atest.Lock{Name: "env", Timeout: "fake"})
+	assert.ErrorContains(t, err, "invalid duration 'fake'")
+
+	_, err = runner.acquireLock(context.TODO(), &atest.Lock{Name: "env", TTL: "fake"})
+	assert.ErrorContains(t, err, "invalid duration 'fake'")
+}
+
+func TestTestCaseWithLock(t *testing.T) {
+	lockDir = t.TempDir()
+	defer gock.Off()
+	gock.New(urlFoo).Reply(http.StatusOK).BodyString(`{}`)
+
+	_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: urlFoo},
+		Prepare: atest.Prepare{Lock: &atest.Lock{Name: "env"}},
+	}, map[string]interface{}{}, context.TODO())
+	assert.Nil(t, err)
+	assert.NoFileExists(t, filepath.Join(lockDir, "env.lock"))
+}
+
+func TestSuiteRunnerWithLock(t *testing.T) {
+	lockDir = t.TempDir()
+	lock.RetryInterval = time.Millisecond
+	suite := &atest.TestSuite{
+		Prepare: atest.Prepare{
+			DockerCompose: []atest.DockerCompose{{File: "compose.yaml"}},
+			Lock:          &atest.Lock{Name: "env", Timeout: "10ms"},
+		},
+		Clean: atest.Clean{CleanPrepare: true},
+	}
+
+	suiteRunner := NewSuiteRunner(io.Discard, "", fakeruntime.FakeExecer{})
+	suiteRunner.(*simpleSuiteRunner).caseRunner.executor = &exec.FakeExecutor{}
+	assert.Nil(t, suiteRunner.Prepare(context.TODO(), suite, map[string]interface{}{}))
+	assert.FileExists(t, filepath.Join(lockDir, "env.lock"))
+
+	// the clean steps are skipped if the lock is not acquired
+	other := NewSuiteRunner(io.Discard, "", fakeruntime.FakeExecer{})
+	other.(*simpleSuiteRunner).caseRunner.executor = &exec.FakeExecutor{Err: errors.New("fake")}
+	assert.ErrorContains(t, other.Prepare(context.TODO(), suite, map[string]interface{}{}), "failed to acquire the lock env")
+	assert.Nil(t, other.Clean(context.TODO(), suite, map[string]interface{}{}, true))
+
+	assert.Nil(t, suiteRunner.Clean(context.TODO(), suite, map[string]interface{}{}, false))
+	assert.NoFileExists(t, filepath.Join(lockDir, "env.lock"))
+}
@@ -0,0 +1,242 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/secret"
+)
+
+// LevelWriter represents a writer with level
+type LevelWriter interface {
+	Trace(format string, a ...any)
+	Debug(format string, a ...any)
+	Info(format string, a ...any)
+	Warn(format string, a ...any)
+	Error(format string, a ...any)
+	// With returns a writer which carries the fields, such as the case name
+	With(fields Fields) LevelWriter
+	// Component returns a writer with the level of the component, such as: runner, prepare, reporter
+	Component(name string) LevelWriter
+}
+
+// FormatPrinter represents a formart printer with level
+type FormatPrinter interface {
+	Fprintf(w io.Writer, level, format string, a ...any) (n int, err error)
+}
+
+// Fields are the structured context of the log entries, such as the case name, the phase and the duration
+type Fields map[string]interface{}
+
+const (
+	levelError = 1
+	levelWarn  = 2
+	levelInfo  = 3
+	levelDebug = 7
+	levelTrace = 9
+)
+
+var levelNames = map[string]int{
+	"error": levelError,
+	"warn":  levelWarn,
+	"info":  levelInfo,
+	"debug": levelDebug,
+	"trace": levelTrace,
+}
+
+const (
+	// ComponentRunner is the component of sending the requests and verifying the responses
+	ComponentRunner = "runner"
+	// ComponentPrepare is the component of the prepare and clean steps
+	ComponentPrepare = "prepare"
+	// ComponentReporter is the component of the report records
+	ComponentReporter = "reporter"
+)
+
+// logLevels are the default level and the levels of the components
+type logLevels struct {
+	level      int
+	components map[string]int
+}
+
+func (l *logLevels) of(component string) int {
+	if level, ok := l.components[component]; ok {
+		return level
+	}
+	return l.level
+}
+
+// parseLogOptions parses the comma separated options, such as: "info,prepare=debug,reporter=warn,json".
+// The unknown level means nothing will be written.
+func parseLogOptions(options string) (levels *logLevels, format string) {
+	levels = &logLevels{components: map[string]int{}}
+	for _, option := range strings.Split(options, ",") {
+		option = strings.TrimSpace(option)
+		if component, level, ok := strings.Cut(option, "="); ok {
+			levels.components[strings.TrimSpace(component)] = parseLevel(strings.TrimSpace(level))
+		} else if option == "json" {
+			format = option
+		} else if option != "" {
+			levels.level = parseLevel(option)
+		}
+	}
+	return
+}
+
+func parseLevel(level string) int {
+	return levelNames[level]
+}
+
+func levelName(level int) string {
+	for name, val := range levelNames {
+		if val == level {
+			return name
+		}
+	}
+	return ""
+}
+
+// NewLevelWriter creates a LevelWriter by the options, it's the plain text by default. The options are
+// the level, the levels of the components and the format, such as: "info,prepare=debug,json" writes
+// the JSON lines which could be ingested by Loki or ELK.
+func NewLevelWriter(options string, writer io.Writer) LevelWriter {
+	levels, format := parseLogOptions(options)
+	base := newLevelWriterBase(levels, writer)
+	if format == "json" {
+		return &jsonLevelWriter{base}
+	}
+	return &defaultLevelWriter{base}
+}
+
+// levelWriterBase holds the levels and the fields which are shared by the writers
+type levelWriterBase struct {
+	levels *logLevels
+	level  int
+	fields Fields
+	writer io.Writer
+	lock   *sync.Mutex
+}
+
+func newLevelWriterBase(levels *logLevels, writer io.Writer) levelWriterBase {
+	return levelWriterBase{
+		levels: levels,
+		level:  levels.of(ComponentRunner),
+		writer: writer,
+		lock:   &sync.Mutex{},
+	}
+}
+
+// with returns a copy which has the merged fields
+func (b levelWriterBase) with(fields Fields) levelWriterBase {
+	merged := make(Fields, len(b.fields)+len(fields))
+	for key, val := range b.fields {
+		merged[key] = val
+	}
+	for key, val := range fields {
+		merged[key] = val
+	}
+	b.fields = merged
+	return b
+}
+
+// component returns a copy which has the level of the component
+func (b levelWriterBase) component(name string) levelWriterBase {
+	b = b.with(Fields{"component": name})
+	b.level = b.levels.of(name)
+	return b
+}
+
+func (b levelWriterBase) write(data []byte) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	_, _ = b.writer.Write(data)
+}
+
+type defaultLevelWriter struct {
+	levelWriterBase
+}
+
+// NewDefaultLevelWriter creates a default LevelWriter instance, each line has the time, the level and the fields
+func NewDefaultLevelWriter(level string, writer io.Writer) LevelWriter {
+	levels, _ := parseLogOptions(level)
+	return &defaultLevelWriter{newLevelWriterBase(levels, writer)}
+}
+
+// Fprintf implements interface FormatPrinter
+func (w *defaultLevelWriter) Fprintf(writer io.Writer, level int, format string, a ...any) (n int, err error) {
+	if level > w.level {
+		return
+	}
+
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, "%s %-5s ", time.Now().Format(time.RFC3339), strings.ToUpper(levelName(level)))
+	if len(w.fields) > 0 {
+		keys := make([]string, 0, len(w.fields))
+		for key := range w.fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			pairs = append(pairs, fmt.Sprintf("%s=%v", key, w.fields[key]))
+		}
+		fmt.Fprintf(buf, "[%s] ", strings.Join(pairs, " "))
+	}
+	fmt.Fprintf(buf, format, a...)
+	if !strings.HasSuffix(buf.String(), "\n") {
+		buf.WriteString("\n")
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return io.WriteString(writer, secret.Redact(buf.String()))
+}
+
+// Trace writes the trace level message
+func (w *defaultLevelWriter) Trace(format string, a ...any) {
+	w.Fprintf(w.writer, levelTrace, format, a...)
+}
+
+// Debug writes the debug level message
+func (w *defaultLevelWriter) Debug(format string, a ...any) {
+	w.Fprintf(w.writer, levelDebug, format, a...)
+}
+
+// Info writes the info level message
+func (w *defaultLevelWriter) Info(format string, a ...any) {
+	w.Fprintf(w.writer, levelInfo, format, a...)
+}
+
+// Warn writes the warn level message
+func (w *defaultLevelWriter) Warn(format string, a ...any) {
+	w.Fprintf(w.writer, levelWarn, format, a...)
+}
+
+// Error writes the error level message
+func (w *defaultLevelWriter) Error(format string, a ...any) {
+	w.Fprintf(w.writer, levelError, format, a...)
+}
+
+// With returns a writer which carries the fields
+func (w *defaultLevelWriter) With(fields Fields) LevelWriter {
+	return &defaultLevelWriter{w.with(fields)}
+}
+
+// Component returns a writer with the level of the component
+func (w *defaultLevelWriter) Component(name string) LevelWriter {
+	return &defaultLevelWriter{w.component(name)}
+}
+
+// withResult returns the fields of the duration and the error
+func withResult(fields Fields, duration time.Duration, err error) Fields {
+	fields["durationMs"] = duration.Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	return fields
+}
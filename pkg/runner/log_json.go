@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/secret"
+)
+
+type jsonLevelWriter struct {
+	levelWriterBase
+}
+
+// NewJSONLevelWriter creates a LevelWriter which writes a JSON object per line, the keys are
+// time, level, msg and the fields
+func NewJSONLevelWriter(level string, writer io.Writer) LevelWriter {
+	levels, _ := parseLogOptions(level)
+	return &jsonLevelWriter{newLevelWriterBase(levels, writer)}
+}
+
+// Trace writes the trace level message
+func (w *jsonLevelWriter) Trace(format string, a ...any) {
+	w.print(levelTrace, format, a...)
+}
+
+// Debug writes the debug level message
+func (w *jsonLevelWriter) Debug(format string, a ...any) {
+	w.print(levelDebug, format, a...)
+}
+
+// Info writes the info level message
+func (w *jsonLevelWriter) Info(format string, a ...any) {
+	w.print(levelInfo, format, a...)
+}
+
+// Warn writes the warn level message
+func (w *jsonLevelWriter) Warn(format string, a ...any) {
+	w.print(levelWarn, format, a...)
+}
+
+// Error writes the error level message
+func (w *jsonLevelWriter) Error(format string, a ...any) {
+	w.print(levelError, format, a...)
+}
+
+// With returns a writer which carries the fields, the fields of the writer are kept
+func (w *jsonLevelWriter) With(fields Fields) LevelWriter {
+	return &jsonLevelWriter{w.with(fields)}
+}
+
+// Component returns a writer with the level of the component
+func (w *jsonLevelWriter) Component(name string) LevelWriter {
+	return &jsonLevelWriter{w.component(name)}
+}
+
+func (w *jsonLevelWriter) print(level int, format string, a ...any) {
+	if level > w.level {
+		return
+	}
+
+	entry := make(Fields, len(w.fields)+3)
+	for key, val := range w.fields {
+		if text, ok := val.(string); ok {
+			val = secret.Redact(text)
+		}
+		entry[key] = val
+	}
+	entry["time"] = time.Now().Format(time.RFC3339Nano)
+	entry["level"] = levelName(level)
+	entry["msg"] = secret.Redact(strings.TrimSpace(fmt.Sprintf(format, a...)))
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		data, _ = json.Marshal(Fields{"level": entry["level"], "msg": entry["msg"], "error": err.Error()})
+	}
+	w.write(append(data, '\n'))
+}
@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONLevelWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := NewJSONLevelWriter("info", buf)
+	writer.Debug("debug")
+	writer.With(Fields{"case": "foo"}).With(withResult(Fields{"phase": "prepare"}, 0, errors.New("fake"))).Info("info: %s\n", "bar")
+	writer.Info("plain")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Equal(t, 2, len(lines)) {
+		entry := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal([]byte(lines[0]), &entry))
+		assert.NotEmpty(t, entry["time"])
+		delete(entry, "time")
+		assert.Equal(t, map[string]interface{}{
+			"level":      "info",
+			"msg":        "info: bar",
+			"case":       "foo",
+			"phase":      "prepare",
+			"durationMs": float64(0),
+			"error":      "fake",
+		}, entry)
+
+		entry = map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal([]byte(lines[1]), &entry))
+		assert.Nil(t, entry["case"])
+	}
+}
+
+func TestJSONLogOfTestCase(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlFoo).Reply(http.StatusOK).BodyString(`{}`)
+
+	buf := new(bytes.Buffer)
+	runner := NewSimpleTestCaseRunner().WithOutputWriter(buf).WithWriteLevel("debug,json")
+	_, err := runner.RunTestCase(&atest.TestCase{
+		Name:    "foo",
+		Request: atest.Request{API: urlFoo},
+	}, nil, context.TODO())
+	assert.Nil(t, err)
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		entry := map[string]interface{}{}
+		if assert.Nil(t, json.Unmarshal([]byte(line), &entry), line) {
+			assert.Equal(t, "foo", entry["case"])
+		}
+	}
+	assert.Contains(t, buf.String(), `"durationMs"`)
+}
@@ -0,0 +1,132 @@
+package runner
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/secret"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  string
+		expect []string
+	}{{
+		name:   "trace",
+		level:  "trace",
+		expect: []string{"TRACE trace", "DEBUG debug", "INFO  info", "WARN  warn", "ERROR error"},
+	}, {
+		name:   "debug",
+		level:  "debug",
+		expect: []string{"DEBUG debug", "INFO  info", "WARN  warn", "ERROR error"},
+	}, {
+		name:   "info",
+		level:  "info",
+		expect: []string{"INFO  info", "WARN  warn", "ERROR error"},
+	}, {
+		name:   "error",
+		level:  "error",
+		expect: []string{"ERROR error"},
+	}, {
+		name:  "unknown",
+		level: "fake",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			writer := NewDefaultLevelWriter(tt.level, buf)
+			writer.Trace("trace")
+			writer.Debug("debug")
+			writer.Info("info\n")
+			writer.Warn("warn")
+			writer.Error("error")
+
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if len(tt.expect) == 0 {
+				assert.Empty(t, buf.String())
+				return
+			}
+			if assert.Equal(t, len(tt.expect), len(lines)) {
+				for i, line := range lines {
+					assert.Regexp(t, regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\S+ `+tt.expect[i]+`$`), line)
+				}
+			}
+		})
+	}
+}
+
+func TestLevelWriterWithFields(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := NewLevelWriter("info,prepare=debug,reporter=error", buf).With(Fields{"case": "foo"})
+	writer.Debug("runner")
+	writer.Component(ComponentPrepare).Debug("prepare: %s", "step")
+	writer.Component(ComponentReporter).Info("reporter")
+	writer.Component("fake").Info("fake")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Equal(t, 2, len(lines), buf.String()) {
+		assert.True(t, strings.HasSuffix(lines[0], "DEBUG [case=foo component=prepare] prepare: step"), lines[0])
+		assert.True(t, strings.HasSuffix(lines[1], "INFO  [case=foo component=fake] fake"), lines[1])
+	}
+}
+
+func TestNewLevelWriter(t *testing.T) {
+	assert.IsType(t, &defaultLevelWriter{}, NewLevelWriter("info", nil))
+	assert.IsType(t, &jsonLevelWriter{}, NewLevelWriter("debug,json", nil))
+	assert.IsType(t, &jsonLevelWriter{}, NewLevelWriter("json, info, prepare = debug", nil))
+}
+
+func TestParseLogOptions(t *testing.T) {
+	levels, format := parseLogOptions("warn, prepare=trace, reporter=fake, json")
+	assert.Equal(t, "json", format)
+	assert.Equal(t, levelWarn, levels.of(ComponentRunner))
+	assert.Equal(t, levelTrace, levels.of(ComponentPrepare))
+	assert.Equal(t, 0, levels.of(ComponentReporter))
+
+	levels, format = parseLogOptions("")
+	assert.Empty(t, format)
+	assert.Equal(t, 0, levels.of(ComponentRunner))
+}
+
+func TestRedactSecrets(t *testing.T) {
+	os.Setenv("LOG_TOKEN", "log-token")
+	defer os.Unsetenv("LOG_TOKEN")
+	_, err := secret.Get("LOG_TOKEN")
+	assert.Nil(t, err)
+
+	buf := new(bytes.Buffer)
+	NewLevelWriter("info", buf).With(Fields{"header": "Bearer log-token"}).Info("token: %s", "log-token")
+	NewLevelWriter("info,json", buf).With(Fields{"header": "Bearer log-token"}).Info("token: %s", "log-token")
+	assert.NotContains(t, buf.String(), "log-token")
+	assert.Equal(t, 4, strings.Count(buf.String(), secret.Mask))
+
+	record := &ReportRecord{
+		API:      "http://foo?token=log-token",
+		Body:     "log-token",
+		Error:    errors.New("invalid token log-token"),
+		Findings: []SecurityFinding{{Message: "leaked log-token"}},
+	}
+	record.redact()
+	assert.Equal(t, &ReportRecord{
+		API:      "http://foo?token=******",
+		Body:     "******",
+		Error:    errors.New("invalid token ******"),
+		Findings: []SecurityFinding{{Message: "leaked ******"}},
+	}, record)
+}
+
+func TestRedactRules(t *testing.T) {
+	buf := new(bytes.Buffer)
+	NewLevelWriter("info", buf).Info("request header Authorization: Bearer rule-token\n")
+	assert.NotContains(t, buf.String(), "rule-token")
+
+	record := &ReportRecord{Body: `{"name":"linuxsuren","password":"rule-password"}`}
+	record.redact()
+	assert.Equal(t, `{"name":"linuxsuren","password":"******"}`, record.Body)
+}
@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const defaultKubernetesWaitTimeout = 5 * time.Minute
+
+// applyKubernetes applies the manifest via the API server, then waits for the rollout and the pods if necessary.
+// The outputs are read after the waits.
+func (r *simpleTestCaseRunner) applyKubernetes(ctx context.Context, item testing.Kubernetes, contextDir string) (
+	outputs map[string]interface{}, err error) {
+	var timeout time.Duration
+	if timeout, err = parseDurationOrDefault(item.Timeout, defaultKubernetesWaitTimeout); err != nil {
+		return
+	}
+
+	var client kubernetes.ManifestClient
+	var manifest []byte
+	r.log.Info("prepare: apply %s\n", item.File)
+	if client, manifest, err = loadManifest(item, contextDir); err == nil {
+		err = client.Apply(ctx, manifest)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to apply %s: %v", item.File, err)
+		return
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for _, workload := range item.Rollout {
+		if err = client.WaitRollout(waitCtx, workload); err != nil {
+			err = fmt.Errorf("failed to wait for the rollout of %s: %v", workload, err)
+			return
+		}
+	}
+
+	if item.PodSelector != "" {
+		if err = client.WaitPods(waitCtx, item.PodSelector); err != nil {
+			err = fmt.Errorf("failed to wait for the pods %s: %v", item.PodSelector, err)
+			return
+		}
+	}
+
+	for _, condition := range item.Conditions {
+		resource, conditionType, _ := strings.Cut(condition, "=")
+		conditionType = testing.EmptyThenDefault(conditionType, "Ready")
+		if err = client.WaitCondition(waitCtx, manifest, resource, conditionType); err != nil {
+			err = fmt.Errorf("failed to wait for the condition %s of %s: %v", conditionType, resource, err)
+			return
+		}
+	}
+
+	for _, output := range item.Outputs {
+		var obj map[string]interface{}
+		var val interface{}
+		if obj, err = client.Get(ctx, manifest, output.Resource); err == nil {
+			val, err = kubernetes.JSONPath(obj, output.JSONPath)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to get the output %s: %v", output.Name, err)
+			return
+		}
+
+		if outputs == nil {
+			outputs = map[string]interface{}{}
+		}
+		outputs[output.Name] = val
+	}
+	return
+}
+
+// deleteKubernetes deletes the resources of the manifest
+func (r *simpleTestCaseRunner) deleteKubernetes(ctx context.Context, item testing.Kubernetes, contextDir string) (err error) {
+	var client kubernetes.ManifestClient
+	var manifest []byte
+	if client, manifest, err = loadManifest(item, contextDir); err == nil {
+		err = client.Delete(ctx, manifest)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to delete %s: %v", item.File, err)
+	}
+	return
+}
+
+// loadManifest reads the manifest which is relative to the directory of the test suite, and creates the client
+// with the kubeconfig, the context and the namespace of the item
+func loadManifest(item testing.Kubernetes, contextDir string) (client kubernetes.ManifestClient, manifest []byte, err error) {
+	file := item.File
+	if !strings.Contains(file, "://") {
+		file = resolvePath(contextDir, file)
+	}
+	if manifest, err = kubernetes.ReadManifest(file); err != nil {
+		return
+	}
+
+	var config *kubernetes.Config
+	if config, err = kubernetes.LoadConfig(item.Kubeconfig, item.Context); err == nil {
+		client, err = kubernetes.NewManifestClient(config, item.Namespace)
+	}
+	return
+}
@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyKubernetes(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlLocalhost).Get("/apis/apps/v1$").Times(2).Reply(http.StatusOK).
+		BodyString(`{"resources":[{"name":"deployments","namespaced":true,"kind":"Deployment"}]}`)
+	gock.New(urlLocalhost).Patch("/apis/apps/v1/namespaces/demo/deployments/nginx").
+		MatchHeader("Authorization", "Bearer token").
+		MatchHeader("Content-Type", "application/apply-patch+yaml").
+		Reply(http.StatusOK).BodyString(`{}`)
+	gock.New(urlLocalhost).Get("/apis/apps/v1/namespaces/demo/deployments/nginx").Reply(http.StatusOK).
+		BodyString(`{"metadata":{"generation":1},"status":{"observedGeneration":1,"replicas":1,"updatedReplicas":1,"availableReplicas":1}}`)
+	gock.New(urlLocalhost).Get("/api/v1/namespaces/demo/pods").MatchParam("labelSelector", "app=nginx").
+		Reply(http.StatusOK).
+		BodyString(`{"items":[{"status":{"conditions":[{"type":"Ready","status":"True"}]}}]}`)
+	gock.New(urlLocalhost).Get("/apis/apps/v1/namespaces/demo/deployments/nginx").Reply(http.StatusOK).
+		BodyString(`{"spec":{"replicas":1}}`)
+	gock.New(urlLocalhost).Delete("/apis/apps/v1/namespaces/demo/deployments/nginx").Reply(http.StatusNotFound)
+
+	item := atest.Kubernetes{
+		File:        "deploy.yaml",
+		Kubeconfig:  "testdata/kubeconfig",
+		Rollout:     []string{"deployment/nginx"},
+		PodSelector: "app=nginx",
+		Outputs: []atest.KubernetesOutput{{
+			Name:     "replicas",
+			Resource: "Deployment/nginx",
+			JSONPath: "{.spec.replicas}",
+		}},
+	}
+
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+	outputs, err := runner.applyKubernetes(context.TODO(), item, "testdata")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"replicas": float64(1)}, outputs)
+	assert.Nil(t, runner.deleteKubernetes(context.TODO(), item, "testdata"))
+	assert.True(t, gock.IsDone())
+
+	item.Timeout = "fake"
+	_, err = runner.applyKubernetes(context.TODO(), item, "testdata")
+	assert.NotNil(t, err)
+
+	item.Timeout = ""
+	item.File = "fake.yaml"
+	_, err = runner.applyKubernetes(context.TODO(), item, "testdata")
+	assert.ErrorContains(t, err, "failed to apply fake.yaml")
+	assert.ErrorContains(t, runner.deleteKubernetes(context.TODO(), item, "testdata"), "failed to delete fake.yaml")
+
+	item.File = "deploy.yaml"
+	item.Context = "fake"
+	_, err = runner.applyKubernetes(context.TODO(), item, "testdata")
+	assert.ErrorContains(t, err, "cannot find context 'fake'")
+}
@@ -0,0 +1,196 @@
+package runner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/render"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+const defaultMongoTimeout = 10 * time.Second
+
+// verifyMongo finds the documents of the collections after the response is verified, the documents should be the same as
+// the expected ones in order. Only the fields of the expected documents are compared, the values are compared as strings
+func (r *simpleTestCaseRunner) verifyMongo(ctx context.Context, name string, queries []testing.MongoQuery, dataContext interface{}) (err error) {
+	for i, query := range queries {
+		var uri string
+		if uri, err = render.Render("mongo uri", query.URI, dataContext); err != nil {
+			return
+		}
+		var filter interface{}
+		if filter, err = render.RenderValue("mongo filter", query.Filter, dataContext); err != nil {
+			return
+		}
+
+		r.log.Debug("verify MongoDB collection: %s.%s\n", query.Database, query.Collection)
+		var documents []map[string]interface{}
+		if documents, err = findMongo(ctx, uri, query.Database, query.Collection, filter); err != nil {
+			err = fmt.Errorf("case: %s, failed to run MongoDB query %d: %v", name, i+1, err)
+			return
+		}
+		if err = compareDocuments(query.Documents, documents); err != nil {
+			err = fmt.Errorf("case: %s, MongoDB query %d %v", name, i+1, err)
+			return
+		}
+	}
+	return
+}
+
+// findMongo returns all the documents of the collection which match the filter, the database of the URI is used if it's empty
+func findMongo(ctx context.Context, uri, database, collection string, filter interface{}) (documents []map[string]interface{}, err error) {
+	var client *mongo.Client
+	if client, err = mongo.Connect(ctx, options.Client().ApplyURI(uri).
+		SetConnectTimeout(defaultMongoTimeout).SetServerSelectionTimeout(defaultMongoTimeout)); err != nil {
+		return
+	}
+	defer func() {
+		_ = client.Disconnect(context.Background())
+	}()
+
+	if database == "" {
+		location, parseErr := connstring.Parse(uri)
+		if parseErr != nil {
+			err = parseErr
+			return
+		}
+		database = location.Database
+	}
+	return findDocuments(ctx, client.Database(database).Collection(collection), filter)
+}
+
+// findDocuments finds the documents via the cursor, the filter could be the extended JSON, such as: {"$oid": "<hex>"}
+func findDocuments(ctx context.Context, collection *mongo.Collection, filter interface{}) (documents []map[string]interface{}, err error) {
+	query := bson.D{}
+	var data []byte
+	if data, err = json.Marshal(filter); err != nil {
+		return
+	}
+	if string(data) != "null" {
+		if err = bson.UnmarshalExtJSON(data, false, &query); err != nil {
+			err = fmt.Errorf("invalid filter: %v", err)
+			return
+		}
+	}
+
+	var cursor *mongo.Cursor
+	if cursor, err = collection.Find(ctx, query); err != nil {
+		return
+	}
+	var items []bson.D
+	if err = cursor.All(ctx, &items); err == nil {
+		for _, item := range items {
+			documents = append(documents, mongoValue(item).(map[string]interface{}))
+		}
+	}
+	return
+}
+
+// mongoValue converts the BSON value into the plain one. The ObjectId is the hex string, the binary is the base64 string,
+// the UUID is the formatted string, the datetime is the RFC 3339 string, and the integers are int32 or int64
+func mongoValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case primitive.D:
+		document := make(map[string]interface{}, len(val))
+		for _, element := range val {
+			document[element.Key] = mongoValue(element.Value)
+		}
+		return document
+	case primitive.A:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			items[i] = mongoValue(item)
+		}
+		return items
+	case primitive.ObjectID:
+		return val.Hex()
+	case primitive.DateTime:
+		return val.Time().UTC().Format(time.RFC3339Nano)
+	case primitive.Binary:
+		if val.Subtype == 4 && len(val.Data) == 16 {
+			text := hex.EncodeToString(val.Data)
+			return text[:8] + "-" + text[8:12] + "-" + text[12:16] + "-" + text[16:20] + "-" + text[20:]
+		}
+		return base64.StdEncoding.EncodeToString(val.Data)
+	case primitive.Regex:
+		return "/" + val.Pattern + "/" + val.Options
+	case primitive.Timestamp:
+		return uint64(val.T)<<32 | uint64(val.I)
+	case primitive.Decimal128:
+		return val.String()
+	case primitive.Null, primitive.Undefined, primitive.MinKey, primitive.MaxKey:
+		return nil
+	default:
+		return value
+	}
+}
+
+// compareDocuments checks the count of the documents, and the fields of the expected documents.
+// The nested fields could be the dotted paths, such as: address.city, or tags.0
+func compareDocuments(expect, actual []map[string]interface{}) (err error) {
+	if len(expect) != len(actual) {
+		err = fmt.Errorf("expect %d documents, actual: %d", len(expect), len(actual))
+		return
+	}
+
+	for i, document := range expect {
+		for field, expectVal := range document {
+			val, ok := lookupField(actual[i], field)
+			switch {
+			case !ok:
+				err = fmt.Errorf("not found field %s of document %d", field, i+1)
+			case expectVal == nil && val != nil:
+				err = fmt.Errorf("field %s of document %d expect null, actual: %v", field, i+1, val)
+			case expectVal != nil && (val == nil || fmt.Sprintf("%v", expectVal) != fmt.Sprintf("%v", val)):
+				err = fmt.Errorf("field %s of document %d expect value: %v, actual: %v", field, i+1, expectVal, val)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func lookupField(document map[string]interface{}, field string) (val interface{}, ok bool) {
+	if val, ok = document[field]; ok {
+		return
+	}
+
+	val = document
+	for _, key := range strings.Split(field, ".") {
+		switch current := val.(type) {
+		case map[string]interface{}:
+			val, ok = current[key]
+		case []interface{}:
+			var index int
+			if index, ok = parseIndex(key, len(current)); ok {
+				val = current[index]
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			return
+		}
+	}
+	return
+}
+
+func parseIndex(key string, size int) (index int, ok bool) {
+	var err error
+	index, err = strconv.Atoi(key)
+	ok = err == nil && index >= 0 && index < size
+	return
+}
@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestFindDocuments(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("next batch", func(mt *mtest.T) {
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(42, ns, mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: primitive.ObjectID{1}},
+				{Key: "name", Value: "rick"},
+				{Key: "age", Value: int32(70)},
+				{Key: "address", Value: bson.D{{Key: "city", Value: "Seattle"}}},
+			}),
+			mtest.CreateCursorResponse(0, ns, mtest.NextBatch, bson.D{
+				{Key: "name", Value: "morty"},
+				{Key: "tags", Value: bson.A{"student"}},
+			}, bson.D{
+				{Key: "name", Value: "summer"},
+				{Key: "email", Value: nil},
+			}),
+		)
+
+		documents, err := findDocuments(context.TODO(), mt.Coll,
+			map[string]interface{}{"_id": map[string]interface{}{"$oid": "010000000000000000000000"}})
+		assert.NoError(mt, err)
+		assert.Equal(mt, []map[string]interface{}{{
+			"_id":     "010000000000000000000000",
+			"name":    "rick",
+			"age":     int32(70),
+			"address": map[string]interface{}{"city": "Seattle"},
+		}, {
+			"name": "morty",
+			"tags": []interface{}{"student"},
+		}, {
+			"name":  "summer",
+			"email": nil,
+		}}, documents)
+	})
+
+	mt.Run("error reply", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 26, Name: "NamespaceNotFound", Message: "not found collection: teams",
+		}))
+		_, err := findDocuments(context.TODO(), mt.Coll, nil)
+		assert.ErrorContains(mt, err, "not found collection: teams")
+	})
+
+	mt.Run("invalid filter", func(mt *mtest.T) {
+		_, err := findDocuments(context.TODO(), mt.Coll, map[string]interface{}{"_id": map[string]interface{}{"$oid": "fake"}})
+		assert.ErrorContains(mt, err, "invalid filter")
+	})
+}
+
+func TestCompareDocuments(t *testing.T) {
+	actual := []map[string]interface{}{{
+		"name":    "rick",
+		"age":     int32(70),
+		"address": map[string]interface{}{"city": "Seattle"},
+	}, {
+		"name": "morty",
+		"tags": []interface{}{"student"},
+	}}
+
+	tests := []struct {
+		name   string
+		expect []map[string]interface{}
+		err    string
+	}{{
+		name:   "normal",
+		expect: []map[string]interface{}{{"age": float64(70), "address.city": "Seattle"}, {"name": "morty", "tags.0": "student"}},
+	}, {
+		name:   "unexpected count",
+		expect: []map[string]interface{}{{"name": "rick"}},
+		err:    "expect 1 documents, actual: 2",
+	}, {
+		name:   "unexpected value",
+		expect: []map[string]interface{}{{"age": float64(15)}, {}},
+		err:    "field age of document 1 expect value: 15, actual: 70",
+	}, {
+		name:   "not found field",
+		expect: []map[string]interface{}{{}, {"tags.1": "student"}},
+		err:    "not found field tags.1 of document 2",
+	}, {
+		name:   "unexpected null",
+		expect: []map[string]interface{}{{"name": nil}, {}},
+		err:    "field name of document 1 expect null, actual: rick",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := compareDocuments(tt.expect, actual)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestVerifyMongo(t *testing.T) {
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+	err := runner.verifyMongo(context.TODO(), "users", []atest.MongoQuery{{URI: "fake://localhost", Collection: "users"}}, nil)
+	assert.ErrorContains(t, err, "case: users, failed to run MongoDB query 1")
+
+	err = runner.verifyMongo(context.TODO(), "users", []atest.MongoQuery{{URI: "{{.fake", Collection: "users"}}, nil)
+	assert.Error(t, err)
+}
+
+func TestMongoValue(t *testing.T) {
+	date := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, map[string]interface{}{
+		"date":      "2023-01-02T03:04:05Z",
+		"binary":    "aGVsbG8=",
+		"uuid":      "00112233-4455-6677-8899-aabbccddeeff",
+		"regex":     "/^rick/i",
+		"timestamp": uint64(1)<<32 | 2,
+		"null":      nil,
+		"array":     []interface{}{"a", map[string]interface{}{"b": int64(1)}},
+	}, mongoValue(bson.D{
+		{Key: "date", Value: primitive.NewDateTimeFromTime(date)},
+		{Key: "binary", Value: primitive.Binary{Data: []byte("hello")}},
+		{Key: "uuid", Value: primitive.Binary{Subtype: 4, Data: []byte{
+			0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}}},
+		{Key: "regex", Value: primitive.Regex{Pattern: "^rick", Options: "i"}},
+		{Key: "timestamp", Value: primitive.Timestamp{T: 1, I: 2}},
+		{Key: "null", Value: primitive.Null{}},
+		{Key: "array", Value: bson.A{"a", bson.D{{Key: "b", Value: int64(1)}}}},
+	}))
+}
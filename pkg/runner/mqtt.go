@@ -0,0 +1,177 @@
+package runner
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/linuxsuren/api-testing/pkg/util"
+)
+
+const (
+	defaultMQTTTimeout = 10 * time.Second
+	// mqttKeepAlive is the interval of the PINGREQ
+	mqttKeepAlive = 60 * time.Second
+)
+
+// runMQTT subscribes the topic filter, publishes the body, then waits for the message which passes the expect.
+// The messages which don't pass the expect are skipped, the output is the matched message
+func (r *simpleTestCaseRunner) runMQTT(ctx context.Context, testcase *testing.TestCase, dataContext interface{},
+	contextDir string, record *ReportRecord) (output interface{}, err error) {
+	request := &testcase.Request
+	if err = request.Render(dataContext, contextDir); err != nil {
+		return
+	}
+
+	options := request.MQTT
+	if options.QoS != 0 && options.QoS != 1 {
+		err = fmt.Errorf("not supported QoS %d of MQTT, it should be 0 or 1", options.QoS)
+		return
+	} else if options.Topic == "" && options.Subscribe == "" {
+		err = fmt.Errorf("the topic or the subscribe of MQTT should be set")
+		return
+	}
+
+	var timeout time.Duration
+	if timeout, err = parseDurationOrDefault(options.Timeout, defaultMQTTTimeout); err != nil {
+		err = fmt.Errorf("invalid timeout of the MQTT: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var clientOptions *mqtt.ClientOptions
+	if clientOptions, err = newMQTTOptions(ctx, request, contextDir, timeout); err != nil {
+		return
+	}
+
+	// the messages are handled one by one, the handler is blocked until the message is verified or it's done
+	messages := make(chan mqtt.Message)
+	clientOptions.SetDefaultPublishHandler(func(_ mqtt.Client, message mqtt.Message) {
+		select {
+		case messages <- message:
+		case <-ctx.Done():
+		}
+	})
+
+	r.log.Info("start to connect %s\n", request.API)
+	client := mqtt.NewClient(clientOptions)
+	if err = waitMQTT(ctx, client.Connect()); err != nil {
+		err = fmt.Errorf("failed to connect %s: %v", request.API, err)
+		return
+	}
+	defer func() {
+		// release the handler before disconnecting
+		cancel()
+		client.Disconnect(0)
+	}()
+
+	if options.Subscribe != "" {
+		token := client.Subscribe(options.Subscribe, byte(options.QoS), nil)
+		if err = waitMQTT(ctx, token); err != nil {
+			return
+		} else if token.(*mqtt.SubscribeToken).Result()[options.Subscribe] == 0x80 {
+			err = fmt.Errorf("failed to subscribe the MQTT topic %s", options.Subscribe)
+			return
+		}
+	}
+	if options.Topic != "" {
+		r.log.Debug("mqtt: publish %s to %s\n", request.Body, options.Topic)
+		if err = waitMQTT(ctx, client.Publish(options.Topic, byte(options.QoS), options.Retain, request.Body)); err != nil {
+			return
+		}
+	}
+	if options.Subscribe == "" {
+		return
+	}
+
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("not received the expected message of %s in %v", options.Subscribe, timeout)
+			if lastErr != nil {
+				err = fmt.Errorf("%v, the last one of it: %v", err, lastErr)
+			}
+			return
+		case message := <-messages:
+			payload := string(message.Payload())
+			r.log.Debug("mqtt: receive %s from %s\n", payload, message.Topic())
+			record.Body = payload
+
+			if output, lastErr = verifyMessage(testcase.Name, testcase.Expect, payload); lastErr == nil {
+				return
+			}
+		}
+	}
+}
+
+// waitMQTT waits for the token until the context is done
+func waitMQTT(ctx context.Context, token mqtt.Token) (err error) {
+	select {
+	case <-token.Done():
+		err = token.Error()
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	return
+}
+
+// newMQTTOptions returns the options of the broker with the clean session, the mqtts and ssl schemes are over TLS.
+// The default ports are 1883 and 8883
+func newMQTTOptions(ctx context.Context, request *testing.Request, contextDir string, timeout time.Duration) (clientOptions *mqtt.ClientOptions, err error) {
+	var location *url.URL
+	if location, err = url.Parse(request.API); err != nil {
+		return
+	}
+
+	var tlsConfig *tls.Config
+	scheme, port := "tcp", "1883"
+	switch location.Scheme {
+	case "mqtt", "tcp":
+	case "mqtts", "ssl", "tls":
+		scheme, port = "ssl", "8883"
+		if tlsConfig, err = newTLSConfig(request.TLS, contextDir); err != nil {
+			return
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = location.Hostname()
+		}
+	default:
+		err = fmt.Errorf("not supported scheme of MQTT: '%s', it should be mqtt or mqtts", location.Scheme)
+		return
+	}
+	if location.Port() != "" {
+		port = location.Port()
+	}
+
+	options := request.MQTT
+	clientOptions = mqtt.NewClientOptions().
+		AddBroker(fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(location.Hostname(), port))).
+		SetClientID(testing.EmptyThenDefault(options.ClientID, "atest-"+util.String(8))).
+		SetUsername(options.Username).
+		SetPassword(options.Password).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetKeepAlive(mqttKeepAlive).
+		SetConnectTimeout(timeout)
+	if tlsConfig != nil {
+		clientOptions.SetTLSConfig(tlsConfig)
+	}
+
+	var dial dialFunc
+	if dial, err = newRequestDial(request); err == nil && dial != nil {
+		clientOptions.SetCustomOpenConnectionFn(func(uri *url.URL, _ mqtt.ClientOptions) (conn net.Conn, err error) {
+			if conn, err = dial(ctx, "tcp", uri.Host); err == nil && tlsConfig != nil {
+				conn = tls.Client(conn, tlsConfig)
+			}
+			return
+		})
+	}
+	return
+}
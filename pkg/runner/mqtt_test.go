@@ -0,0 +1,166 @@
+package runner
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBroker is a minimal MQTT broker, the retained message is sent once a topic is subscribed
+type fakeBroker struct {
+	listener net.Listener
+	lock     sync.Mutex
+	subs     map[net.Conn]string
+	retained string
+}
+
+func newFakeBroker(t *testing.T, retained string) *fakeBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	broker := &fakeBroker{listener: listener, subs: map[net.Conn]string{}, retained: retained}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(conn)
+		}
+	}()
+	return broker
+}
+
+func (b *fakeBroker) serve(conn net.Conn) {
+	defer func() {
+		b.lock.Lock()
+		delete(b.subs, conn)
+		b.lock.Unlock()
+		_ = conn.Close()
+	}()
+
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch request := packet.(type) {
+		case *packets.ConnectPacket:
+			ack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+			if request.Username == "bad" {
+				ack.ReturnCode = packets.ErrRefusedNotAuthorised
+			}
+			_ = ack.Write(conn)
+		case *packets.SubscribePacket:
+			b.lock.Lock()
+			b.subs[conn] = request.Topics[0]
+			b.lock.Unlock()
+			ack := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+			ack.MessageID, ack.ReturnCodes = request.MessageID, request.Qoss
+			_ = ack.Write(conn)
+			if b.retained != "" {
+				retained := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+				retained.TopicName, retained.Payload = "devices/0", []byte(b.retained)
+				_ = retained.Write(conn)
+			}
+		case *packets.PublishPacket:
+			if request.Qos > 0 {
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = request.MessageID
+				_ = ack.Write(conn)
+			}
+			b.lock.Lock()
+			for sub, filter := range b.subs {
+				if isMQTTTopic(filter, request.TopicName) {
+					// forward with the QoS 1 to verify the acknowledgement of the client
+					forward := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+					forward.TopicName, forward.Qos, forward.MessageID, forward.Payload = request.TopicName, 1, 2, request.Payload
+					_ = forward.Write(sub)
+				}
+			}
+			b.lock.Unlock()
+		case *packets.PingreqPacket:
+			_ = packets.NewControlPacket(packets.Pingresp).Write(conn)
+		case *packets.DisconnectPacket:
+			return
+		}
+	}
+}
+
+// isMQTTTopic checks if the topic matches the filter which has the wildcards: + and #
+func isMQTTTopic(filter, topic string) bool {
+	filters, levels := strings.Split(filter, "/"), strings.Split(topic, "/")
+	for i, item := range filters {
+		if item == "#" {
+			return true
+		} else if i >= len(levels) || (item != "+" && item != levels[i]) {
+			return false
+		}
+	}
+	return len(filters) == len(levels)
+}
+
+func TestMQTT(t *testing.T) {
+	broker := newFakeBroker(t, `{"temperature": 10}`)
+	defer broker.listener.Close()
+	api := "mqtt://" + broker.listener.Addr().String()
+
+	tests := []struct {
+		name   string
+		api    string
+		mqtt   *atest.MQTT
+		expect atest.Response
+		output interface{}
+		err    string
+	}{{
+		name:   "publish and subscribe",
+		mqtt:   &atest.MQTT{Topic: "devices/1", Subscribe: "devices/+", QoS: 1},
+		expect: atest.Response{BodyFieldsExpect: map[string]interface{}{"temperature": float64(20)}},
+		output: map[string]interface{}{"temperature": float64(20)},
+	}, {
+		name:   "publish only",
+		mqtt:   &atest.MQTT{Topic: "devices/1"},
+		output: nil,
+	}, {
+		name:   "not received",
+		mqtt:   &atest.MQTT{Topic: "devices/1", Subscribe: "devices/#", Timeout: "200ms"},
+		expect: atest.Response{BodyFieldsExpect: map[string]interface{}{"temperature": float64(30)}},
+		err:    "not received the expected message of devices/# in 200ms",
+	}, {
+		name: "refused",
+		mqtt: &atest.MQTT{Topic: "devices/1", Username: "bad"},
+		err:  "not Authorized",
+	}, {
+		name: "invalid QoS",
+		mqtt: &atest.MQTT{Topic: "devices/1", QoS: 2},
+		err:  "not supported QoS 2",
+	}, {
+		name: "invalid scheme",
+		api:  "http://" + broker.listener.Addr().String(),
+		mqtt: &atest.MQTT{Topic: "devices/1"},
+		err:  "not supported scheme of MQTT",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := NewMemoryTestReporter()
+			output, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+				Request: atest.Request{API: atest.EmptyThenDefault(tt.api, api), Body: `{"temperature": 20}`, MQTT: tt.mqtt},
+				Expect:  tt.expect,
+			}, nil, context.TODO())
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.output, output)
+			if records := reporter.GetAllRecords(); assert.Len(t, records, 1) {
+				assert.Equal(t, "MQTT", records[0].Method)
+			}
+		})
+	}
+}
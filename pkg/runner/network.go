@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// newDialContext returns the dial function which forces the IP version and binds the local address
+func newDialContext(options *testing.Network) (dial dialFunc, err error) {
+	network := "tcp"
+	switch options.IPVersion {
+	case 0:
+	case 4, 6:
+		network = fmt.Sprintf("tcp%d", options.IPVersion)
+	default:
+		err = fmt.Errorf("invalid IP version %d, it should be 4 or 6", options.IPVersion)
+		return
+	}
+
+	var localIP net.IP
+	switch {
+	case options.SourceAddress != "" && options.Interface != "":
+		err = fmt.Errorf("the source address and the interface cannot be set at the same time")
+	case options.SourceAddress != "":
+		if localIP = net.ParseIP(options.SourceAddress); localIP == nil {
+			err = fmt.Errorf("invalid source address '%s'", options.SourceAddress)
+		}
+	case options.Interface != "":
+		localIP, err = interfaceIP(options.Interface, options.IPVersion)
+	}
+	if err != nil {
+		return
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if localIP != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: localIP}
+	}
+	dial = func(ctx context.Context, _, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+	return
+}
+
+// interfaceIP returns the first address of the interface which matches the IP version
+func interfaceIP(name string, ipVersion int) (ip net.IP, err error) {
+	var nic *net.Interface
+	if nic, err = net.InterfaceByName(name); err != nil {
+		return
+	}
+
+	var addrs []net.Addr
+	if addrs, err = nic.Addrs(); err != nil {
+		return
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		isIPv4 := ipNet.IP.To4() != nil
+		if ipVersion == 0 || (ipVersion == 4) == isIPv4 {
+			ip = ipNet.IP
+			return
+		}
+	}
+	err = fmt.Errorf("no IPv%d address found of interface '%s'", ipVersion, name)
+	return
+}
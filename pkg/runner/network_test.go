@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestWithNetwork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		_, _ = w.Write([]byte(`{"remote":"` + host + `"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		network *atest.Network
+		expect  string
+	}{{
+		name:    "IPv4 with the source address",
+		network: &atest.Network{IPVersion: 4, SourceAddress: "127.0.0.1"},
+	}, {
+		name:    "IPv6 only",
+		network: &atest.Network{IPVersion: 6},
+		expect:  "dial tcp6",
+	}, {
+		name:    "invalid IP version",
+		network: &atest.Network{IPVersion: 5},
+		expect:  "invalid IP version 5",
+	}, {
+		name:    "invalid source address",
+		network: &atest.Network{SourceAddress: "fake"},
+		expect:  "invalid source address 'fake'",
+	}, {
+		name:    "both the source address and the interface",
+		network: &atest.Network{SourceAddress: "127.0.0.1", Interface: "lo"},
+		expect:  "cannot be set at the same time",
+	}, {
+		name:    "unknown interface",
+		network: &atest.Network{Interface: "fake"},
+		expect:  "fake",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+				Request: atest.Request{API: server.URL, Network: tt.network},
+				Expect: atest.Response{
+					BodyFieldsExpect: map[string]interface{}{"remote": "127.0.0.1"},
+				},
+			}, nil, context.TODO())
+			if tt.expect == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expect)
+			}
+		})
+	}
+}
+
+func TestInterfaceIP(t *testing.T) {
+	nics, err := net.Interfaces()
+	assert.Nil(t, err)
+
+	for _, nic := range nics {
+		if nic.Flags&net.FlagLoopback == 0 {
+			continue
+		}
+
+		ip, err := interfaceIP(nic.Name, 4)
+		if assert.Nil(t, err) {
+			assert.True(t, ip.IsLoopback())
+			assert.NotNil(t, ip.To4())
+		}
+
+		_, err = interfaceIP(nic.Name, 6)
+		if err != nil {
+			assert.True(t, strings.HasPrefix(err.Error(), "no IPv6 address found"))
+		}
+		return
+	}
+}
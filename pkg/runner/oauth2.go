@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// oauth2ExpiryDelta refreshes the token a bit earlier, in case it's expired during the request
+const oauth2ExpiryDelta = 10 * time.Second
+
+type oauth2Token struct {
+	value   string
+	expires time.Time
+}
+
+// oauth2TokenCache keeps the tokens of the client credentials, it's shared by all the runners of the process
+type oauth2TokenCache struct {
+	lock  sync.Mutex
+	items map[string]oauth2Token
+}
+
+var defaultOAuth2TokenCache = &oauth2TokenCache{items: map[string]oauth2Token{}}
+
+// token returns the cached token if it's not expired, otherwise fetches a new one.
+// The lock is held during fetching, so the parallel test cases share the same token
+func (c *oauth2TokenCache) token(ctx context.Context, options *testing.OAuth2) (value string, err error) {
+	params := url.Values{}
+	for name, val := range options.Params {
+		params.Set(name, val)
+	}
+	key := strings.Join([]string{options.TokenURL, options.ClientID, options.ClientSecret,
+		strings.Join(options.Scopes, " "), params.Encode()}, "\n")
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if item, ok := c.items[key]; ok && (item.expires.IsZero() || time.Now().Before(item.expires)) {
+		value = item.value
+		return
+	}
+
+	var item oauth2Token
+	if item, err = fetchOAuth2Token(ctx, options); err == nil {
+		c.items[key] = item
+		value = item.value
+	}
+	return
+}
+
+// fetchOAuth2Token requests the token with the client credentials grant, the client is authenticated via the basic auth
+func fetchOAuth2Token(ctx context.Context, options *testing.OAuth2) (token oauth2Token, err error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(options.Scopes) > 0 {
+		form.Set("scope", strings.Join(options.Scopes, " "))
+	}
+	for key, val := range options.Params {
+		form.Set(key, val)
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, options.TokenURL, strings.NewReader(form.Encode())); err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(options.ClientID), url.QueryEscape(options.ClientSecret))
+
+	var resp *http.Response
+	if resp, err = http.DefaultClient.Do(req); err != nil {
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var data []byte
+	if data, err = io.ReadAll(resp.Body); err != nil {
+		return
+	} else if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status code %d from %s, %s", resp.StatusCode, options.TokenURL, string(data))
+		return
+	}
+
+	result := struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}{}
+	if err = json.Unmarshal(data, &result); err != nil {
+		err = fmt.Errorf("invalid token response: %v", err)
+		return
+	} else if result.AccessToken == "" {
+		err = fmt.Errorf("no access_token in the response of %s", options.TokenURL)
+		return
+	}
+
+	// the token type is case-insensitive, but some servers only accept the Bearer
+	tokenType := result.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	token.value = tokenType + " " + result.AccessToken
+	if result.ExpiresIn > 0 {
+		token.expires = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - oauth2ExpiryDelta)
+	}
+	return
+}
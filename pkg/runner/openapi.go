@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/linuxsuren/api-testing/pkg/util"
+)
+
+// OpenAPI returns the key of the OpenAPI document
+func (c ContextKey) OpenAPI() ContextKey {
+	return ContextKey("openapi")
+}
+
+// WithOpenAPI returns a context with the OpenAPI document, the HTTP responses are validated against it
+func WithOpenAPI(ctx context.Context, spec *apispec.OpenAPI) context.Context {
+	return context.WithValue(ctx, NewContextKeyBuilder().OpenAPI(), spec)
+}
+
+// verifyOpenAPI checks the status code, the content type and the body of the response against the operation
+// which is declared by the OpenAPI document of the context. The operations which are not declared are ignored
+func verifyOpenAPI(ctx context.Context, name string, request *http.Request, resp *http.Response, body []byte) (err error) {
+	spec, ok := ctx.Value(NewContextKeyBuilder().OpenAPI()).(*apispec.OpenAPI)
+	if !ok || spec == nil {
+		return
+	}
+
+	var operation, schema string
+	if operation, schema, err = spec.ResponseSchema(request.Method, request.URL.Path, resp.StatusCode,
+		resp.Header.Get(util.ContentType)); err == nil && len(body) > 0 {
+		err = jsonSchemaValidation(schema, body)
+	}
+	if err != nil {
+		err = fmt.Errorf("case: %s, the response does not match the OpenAPI operation %s, %v", name, operation, err)
+	}
+	return
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyOpenAPI(t *testing.T) {
+	spec, err := apispec.ParseOpenAPI([]byte(`openapi: 3.0.0
+paths:
+  /users/{id}:
+    get:
+      responses:
+        "200":
+          description: the user
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+components:
+  schemas:
+    User:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+        email:
+          type: string
+          nullable: true
+`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx := WithOpenAPI(context.TODO(), spec)
+
+	runCase := func(api string, status int) error {
+		_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+			Name:    "user",
+			Request: atest.Request{API: urlLocalhost + api},
+			Expect:  atest.Response{StatusCode: status},
+		}, nil, ctx)
+		return err
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlLocalhost).Get("/users/1").Reply(http.StatusOK).JSON(`{"id":1,"email":null}`)
+		assert.Nil(t, runCase("/users/1", http.StatusOK))
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlLocalhost).Get("/users/1").Reply(http.StatusOK).JSON(`{"email":"foo@bar.com"}`)
+		err := runCase("/users/1", http.StatusOK)
+		assert.ErrorContains(t, err, "case: user, the response does not match the OpenAPI operation GET /users/{id}")
+		assert.ErrorContains(t, err, "id is required")
+	})
+
+	t.Run("not declared status code", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlLocalhost).Get("/users/1").Reply(http.StatusInternalServerError).JSON(`{}`)
+		assert.ErrorContains(t, runCase("/users/1", http.StatusInternalServerError), "the status code 500 is not declared")
+	})
+
+	t.Run("not declared operation", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlLocalhost).Get("/foo").Reply(http.StatusOK).JSON(`{}`)
+		assert.Nil(t, runCase("/foo", http.StatusOK))
+	})
+}
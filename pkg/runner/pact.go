@@ -0,0 +1,183 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/linuxsuren/api-testing/pkg/secret"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/linuxsuren/api-testing/pkg/util"
+)
+
+// pactSpecification is the version of the Pact specification of the contract files
+const pactSpecification = "2.0.0"
+
+// Pact collects the HTTP interactions of the passed test cases as a consumer-driven contract,
+// the provider verifies the contract with the Pact tools
+type Pact interface {
+	// Record keeps the request and the response of the test case, the later one replaces the previous one of the same case
+	Record(testcase *testing.TestCase, request *http.Request, resp *http.Response, body []byte)
+	// Save writes the contract into the file, the secrets are redacted
+	Save() error
+}
+
+type pactFile struct {
+	Consumer     pactParticipant   `json:"consumer"`
+	Provider     pactParticipant   `json:"provider"`
+	Interactions []pactInteraction `json:"interactions"`
+	Metadata     pactMetadata      `json:"metadata"`
+}
+
+type pactParticipant struct {
+	Name string `json:"name"`
+}
+
+type pactMetadata struct {
+	PactSpecification pactVersion `json:"pactSpecification"`
+}
+
+type pactVersion struct {
+	Version string `json:"version"`
+}
+
+type pactInteraction struct {
+	Description string       `json:"description"`
+	Request     pactRequest  `json:"request"`
+	Response    pactResponse `json:"response"`
+}
+
+type pactRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   string            `json:"query,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+}
+
+type pactResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+}
+
+type pact struct {
+	file         string
+	consumer     string
+	provider     string
+	lock         sync.Mutex
+	interactions map[string]pactInteraction
+}
+
+// NewPact creates a contract between the consumer and the provider, it's written into the file once it's saved
+func NewPact(file, consumer, provider string) Pact {
+	return &pact{
+		file:         file,
+		consumer:     consumer,
+		provider:     provider,
+		interactions: map[string]pactInteraction{},
+	}
+}
+
+// WithPact returns a context with the contract, the interactions of the passed test cases are recorded into it
+func WithPact(ctx context.Context, pact Pact) context.Context {
+	return context.WithValue(ctx, NewContextKeyBuilder().Pact(), pact)
+}
+
+// Pact returns the key of the consumer-driven contract
+func (c ContextKey) Pact() ContextKey {
+	return ContextKey("pact")
+}
+
+// recordPact records the interaction of the test case if there is a contract in the context
+func recordPact(ctx context.Context, testcase *testing.TestCase, request *http.Request, resp *http.Response, body []byte) {
+	if pact, ok := ctx.Value(NewContextKeyBuilder().Pact()).(Pact); ok {
+		pact.Record(testcase, request, resp, body)
+	}
+}
+
+// Record keeps the declared headers of the request only, the ones of the authentication and the client are not
+// a part of the contract. The content type is the only header of the response which the provider should match
+func (p *pact) Record(testcase *testing.TestCase, request *http.Request, resp *http.Response, body []byte) {
+	item := pactInteraction{
+		Description: testcase.Name,
+		Request: pactRequest{
+			Method: request.Method,
+			Path:   testing.EmptyThenDefault(request.URL.Path, "/"),
+			Query:  request.URL.RawQuery,
+			Body:   pactBody(getRequestBody(request), request.Header.Get(util.ContentType)),
+		},
+		Response: pactResponse{
+			Status: resp.StatusCode,
+			Body:   pactBody(body, resp.Header.Get(util.ContentType)),
+		},
+	}
+	for key := range testcase.Request.Header {
+		if item.Request.Headers == nil {
+			item.Request.Headers = map[string]string{}
+		}
+		item.Request.Headers[http.CanonicalHeaderKey(key)] = request.Header.Get(key)
+	}
+	if contentType := resp.Header.Get(util.ContentType); contentType != "" {
+		item.Response.Headers = map[string]string{util.ContentType: contentType}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.interactions[item.Description] = item
+}
+
+// Save writes the interactions in the order of the descriptions, the file is not written if there is no interaction
+func (p *pact) Save() (err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.interactions) == 0 {
+		return
+	}
+
+	content := &pactFile{
+		Consumer: pactParticipant{Name: p.consumer},
+		Provider: pactParticipant{Name: p.provider},
+		Metadata: pactMetadata{PactSpecification: pactVersion{Version: pactSpecification}},
+	}
+	for _, item := range p.interactions {
+		content.Interactions = append(content.Interactions, item)
+	}
+	sort.Slice(content.Interactions, func(i, j int) bool {
+		return content.Interactions[i].Description < content.Interactions[j].Description
+	})
+
+	var data []byte
+	if data, err = json.Marshal(content); err != nil {
+		return
+	}
+	// the masked JSON is compacted by the redaction, so it's indented after that
+	data = []byte(secret.Redact(string(data)))
+	buf := new(bytes.Buffer)
+	if json.Indent(buf, data, "", "  ") == nil {
+		data = buf.Bytes()
+	}
+	if err = os.MkdirAll(filepath.Dir(p.file), 0755); err == nil {
+		err = os.WriteFile(p.file, data, 0644)
+	}
+	return
+}
+
+// pactBody returns the JSON value of the JSON body, or the text of the others
+func pactBody(body []byte, contentType string) interface{} {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var data interface{}
+	if strings.Contains(contentType, "json") && json.Unmarshal(body, &data) == nil {
+		return data
+	}
+	return string(body)
+}
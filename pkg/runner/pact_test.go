@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPact(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlFoo).Post("/users").MatchParam("page", "1").
+		Reply(http.StatusCreated).JSON(`{"name":"linuxsuren","password":"secret"}`)
+	gock.New(urlFoo).Get("/users").Times(2).Reply(http.StatusOK).JSON(`[]`)
+	gock.New(urlFoo).Get("/health").Reply(http.StatusInternalServerError)
+
+	file := path.Join(t.TempDir(), "pacts", "atest-foo.json")
+	pact := NewPact(file, "atest", "foo")
+	assert.NoError(t, pact.Save())
+	assert.NoFileExists(t, file, "there is no interaction")
+
+	ctx := WithPact(context.Background(), pact)
+	for _, testCase := range []*atest.TestCase{{
+		Name: "create",
+		Request: atest.Request{
+			API:    urlFoo + "/users?page=1",
+			Method: http.MethodPost,
+			Header: map[string]string{"content-type": "application/json"},
+			Body:   `{"name":"linuxsuren"}`,
+			Auth:   &atest.Auth{Basic: &atest.BasicAuth{Username: "admin", Password: "admin"}},
+		},
+		Expect: atest.Response{StatusCode: http.StatusCreated},
+	}, {
+		Name:    "list",
+		Request: atest.Request{API: urlFoo + "/users"},
+		Expect:  atest.Response{StatusCode: http.StatusOK},
+	}, {
+		Name:    "list",
+		Request: atest.Request{API: urlFoo + "/users"},
+		Expect:  atest.Response{StatusCode: http.StatusOK},
+	}} {
+		_, err := NewSimpleTestCaseRunner().RunTestCase(testCase, nil, ctx)
+		assert.NoError(t, err)
+	}
+	_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Name:    "health",
+		Request: atest.Request{API: urlFoo + "/health"},
+		Expect:  atest.Response{StatusCode: http.StatusOK},
+	}, nil, ctx)
+	assert.Error(t, err)
+	assert.True(t, gock.IsDone())
+
+	assert.NoError(t, pact.Save())
+	data, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	content := &pactFile{}
+	assert.NoError(t, json.Unmarshal(data, content))
+	assert.Equal(t, "atest", content.Consumer.Name)
+	assert.Equal(t, "foo", content.Provider.Name)
+	assert.Equal(t, pactSpecification, content.Metadata.PactSpecification.Version)
+	if assert.Len(t, content.Interactions, 2, "the failed case is not recorded, the same case is recorded once") {
+		create := content.Interactions[0]
+		assert.Equal(t, "create", create.Description)
+		assert.Equal(t, pactRequest{
+			Method:  http.MethodPost,
+			Path:    "/foo/users",
+			Query:   "page=1",
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    map[string]interface{}{"name": "linuxsuren"},
+		}, create.Request, "the authorization header is not a part of the contract")
+		assert.Equal(t, pactResponse{
+			Status:  http.StatusCreated,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    map[string]interface{}{"name": "linuxsuren", "password": "******"},
+		}, create.Response)
+
+		list := content.Interactions[1]
+		assert.Equal(t, "list", list.Description)
+		assert.Equal(t, []interface{}{}, list.Response.Body)
+		assert.Nil(t, list.Request.Body)
+	}
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const (
+	defaultWaitForInterval = time.Second
+	defaultWaitForTimeout  = time.Minute
+)
+
+// doRequestUntil sends the request again and again until the condition of the waitFor is met, or timeout.
+// The last response is verified by the expect
+func (r *simpleTestCaseRunner) doRequestUntil(ctx context.Context, request *http.Request, testcase *testing.TestCase,
+	record *ReportRecord) (resp *http.Response, body []byte, err error) {
+	waitFor := testcase.WaitFor
+	if waitFor == nil {
+		return r.doRequestWithRetry(ctx, request, testcase, record)
+	}
+
+	if waitFor.Until == "" {
+		err = fmt.Errorf("case: %s, the until of the waitFor is required", testcase.Name)
+		return
+	}
+
+	var interval, timeout time.Duration
+	if interval, err = parseDurationOrDefault(waitFor.Interval, defaultWaitForInterval); err != nil {
+		return
+	}
+	if timeout, err = parseDurationOrDefault(waitFor.Timeout, defaultWaitForTimeout); err != nil {
+		return
+	}
+
+	attempt := 0
+	pollErr := waitUntil(ctx, interval, timeout, func(context.Context) bool {
+		// the body of the request is read by the previous attempt
+		if attempt++; attempt > 1 && request.GetBody != nil {
+			if request.Body, err = request.GetBody(); err != nil {
+				return true
+			}
+		}
+		if resp, body, err = r.doRequestWithRetry(ctx, request, testcase, record); err != nil {
+			return true
+		}
+
+		var met bool
+		if met, err = evalCondition(waitFor.Until, responseEnv(resp, body)); err != nil || met {
+			return true
+		}
+		r.log.Info("the condition of %s is not met, attempt %d: %s\n", request.URL, attempt, waitFor.Until)
+		return false
+	})
+	if err == nil && pollErr != nil {
+		err = fmt.Errorf("case: %s, the condition '%s' is not met after %d attempts: %v", testcase.Name, waitFor.Until, attempt, pollErr)
+	}
+	return
+}
+
+// responseEnv returns the variables of the response for the expressions, the data is the JSON body if it's valid
+func responseEnv(resp *http.Response, body []byte) map[string]interface{} {
+	header := make(map[string]string, len(resp.Header))
+	for key := range resp.Header {
+		header[key] = resp.Header.Get(key)
+	}
+
+	env := map[string]interface{}{
+		"status": resp.StatusCode,
+		"header": header,
+		"body":   string(body),
+	}
+	var data interface{}
+	if json.Unmarshal(body, &data) == nil {
+		env["data"] = data
+	}
+	return env
+}
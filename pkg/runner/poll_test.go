@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitFor(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the body of the request is sent in each attempt
+		if data, _ := io.ReadAll(r.Body); string(data) != `{"id": 1}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		status := "running"
+		if atomic.AddInt32(&count, 1) >= 3 {
+			status = "done"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Job-Status", status)
+		_, _ = w.Write([]byte(`{"status": "` + status + `"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		waitFor *atest.WaitFor
+		count   int32
+		err     string
+	}{{
+		name:    "met",
+		waitFor: &atest.WaitFor{Until: `data.status == "done"`, Interval: "10ms"},
+		count:   3,
+	}, {
+		name:    "met by the header",
+		waitFor: &atest.WaitFor{Until: `status == 200 && header["X-Job-Status"] == "done"`, Interval: "10ms"},
+		count:   3,
+	}, {
+		name:    "timeout",
+		waitFor: &atest.WaitFor{Until: `data.status == "failed"`, Interval: "10ms", Timeout: "100ms"},
+		err:     `case: timeout, the condition 'data.status == "failed"' is not met after`,
+	}, {
+		name:    "invalid condition",
+		waitFor: &atest.WaitFor{Until: `data.status`},
+		count:   1,
+		err:     "the condition 'data.status' is not a bool",
+	}, {
+		name:    "no condition",
+		waitFor: &atest.WaitFor{},
+		err:     "the until of the waitFor is required",
+	}, {
+		name:    "invalid interval",
+		waitFor: &atest.WaitFor{Until: "true", Interval: "fake"},
+		err:     "invalid duration 'fake'",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&count, 0)
+			_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+				Name: tt.name,
+				Request: atest.Request{
+					API:    server.URL + "/jobs",
+					Method: http.MethodPost,
+					Body:   `{"id": 1}`,
+				},
+				WaitFor: tt.waitFor,
+				Expect:  atest.Response{BodyFieldsExpect: map[string]interface{}{"status": "done"}},
+			}, nil, context.TODO())
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+			if tt.count > 0 {
+				assert.Equal(t, tt.count, atomic.LoadInt32(&count))
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// preparedResources are the resources which are created by the prepare steps
+type preparedResources struct {
+	teardowns        []teardown
+	containerContext map[string]interface{}
+	outputs          map[string]interface{}
+}
+
+// withContext puts the containers and the outputs of the prepare steps into the template context
+// under the keys "containers" and "prepare"
+func (p *preparedResources) withContext(dataContext interface{}) interface{} {
+	if len(p.containerContext) == 0 && len(p.outputs) == 0 {
+		return dataContext
+	}
+
+	ctxMap, ok := dataContext.(map[string]interface{})
+	if !ok {
+		if dataContext != nil {
+			return dataContext
+		}
+		ctxMap = map[string]interface{}{}
+	}
+
+	mergeContext(ctxMap, "containers", p.containerContext)
+	mergeContext(ctxMap, "prepare", p.outputs)
+	return ctxMap
+}
+
+// setOutput keeps the output of a named step
+func (p *preparedResources) setOutput(name string, output interface{}) {
+	if name == "" {
+		return
+	}
+	if p.outputs == nil {
+		p.outputs = map[string]interface{}{}
+	}
+	p.outputs[name] = output
+}
+
+func mergeContext(ctxMap map[string]interface{}, key string, values map[string]interface{}) {
+	if len(values) == 0 {
+		return
+	}
+
+	target, ok := ctxMap[key].(map[string]interface{})
+	if !ok {
+		target = map[string]interface{}{}
+	}
+	for name, val := range values {
+		target[name] = val
+	}
+	ctxMap[key] = target
+}
+
+// runPrepare applies the Kubernetes manifests, the Helm charts, the Docker Compose stacks and the containers, waits for the probes,
+// then runs the SQL scripts, the Redis commands, the HTTP requests and the commands one by one
+func (r *simpleTestCaseRunner) runPrepare(ctx context.Context, prepare testing.Prepare, contextDir string,
+	dataContext interface{}, resources *preparedResources) (err error) {
+	defer r.withLog(r.log.Component(ComponentPrepare))()
+
+	// the manifests, the releases and the stacks might be created partially, and deleting them is idempotent,
+	// so they're registered before the steps
+	for _, item := range prepare.Kubernetes {
+		item := item
+		resources.register(teardown{
+			name:     "delete " + item.File,
+			policy:   item.Policy,
+			prepared: true,
+			run: func(ctx context.Context) error {
+				return r.deleteKubernetes(ctx, item, contextDir)
+			},
+		})
+
+		var outputs map[string]interface{}
+		if err = r.runStep(ctx, "prepare", "apply "+item.File, item.Policy, func(stepCtx context.Context) (stepErr error) {
+			outputs, stepErr = r.applyKubernetes(stepCtx, item, contextDir)
+			return
+		}); err != nil {
+			return
+		}
+		for name, val := range outputs {
+			resources.setOutput(name, val)
+		}
+	}
+
+	for _, item := range prepare.Helm {
+		item := item
+		resources.register(teardown{
+			name:     "uninstall " + item.Release,
+			policy:   item.Policy,
+			prepared: true,
+			run: func(ctx context.Context) error {
+				return r.uninstallHelm(ctx, item)
+			},
+		})
+
+		if err = r.runStep(ctx, "prepare", "install "+item.Release, item.Policy, func(stepCtx context.Context) error {
+			return r.installHelm(stepCtx, item, contextDir)
+		}); err != nil {
+			return
+		}
+	}
+
+	for _, item := range prepare.DockerCompose {
+		item := item
+		resources.register(teardown{
+			name:     "down " + item.File,
+			policy:   item.Policy,
+			prepared: true,
+			run: func(ctx context.Context) error {
+				return r.composeDown(ctx, item, contextDir)
+			},
+		})
+
+		if err = r.runStep(ctx, "prepare", "up "+item.File, item.Policy, func(stepCtx context.Context) error {
+			return r.composeUp(stepCtx, item, contextDir)
+		}); err != nil {
+			return
+		}
+	}
+
+	for _, container := range prepare.Containers {
+		container := container
+		if err = r.runStep(ctx, "prepare", "start "+container.Name, container.Policy, func(stepCtx context.Context) error {
+			return r.startContainer(stepCtx, container, resources)
+		}); err != nil {
+			return
+		}
+	}
+
+	for _, probe := range prepare.Wait {
+		if err = r.waitProbe(ctx, probe, contextDir, resources.withContext(dataContext)); err != nil {
+			return
+		}
+	}
+
+	if prepare.SQL != nil {
+		if err = r.runStep(ctx, "prepare", "run the SQL scripts", prepare.SQL.Policy, func(stepCtx context.Context) error {
+			return r.runSQL(stepCtx, "prepare", prepare.SQL, contextDir, resources.withContext(dataContext))
+		}); err != nil {
+			return
+		}
+	}
+
+	if prepare.Redis != nil {
+		if err = r.runStep(ctx, "prepare", "run the Redis commands", prepare.Redis.Policy, func(stepCtx context.Context) error {
+			return r.runRedis(stepCtx, "prepare", prepare.Redis, resources.withContext(dataContext))
+		}); err != nil {
+			return
+		}
+	}
+
+	for _, step := range prepare.HTTP {
+		step := step
+		var output interface{}
+		if err = r.runStep(ctx, "prepare", httpStepName(step), step.Policy, func(stepCtx context.Context) (stepErr error) {
+			output, stepErr = r.runHTTPStep(stepCtx, "prepare", step, contextDir, resources.withContext(dataContext))
+			return
+		}); err != nil {
+			return
+		}
+		resources.setOutput(step.Name, output)
+	}
+
+	for _, command := range prepare.Commands {
+		command := command
+		var stdout string
+		if err = r.runStep(ctx, "prepare", commandName(command), command.Policy, func(stepCtx context.Context) (stepErr error) {
+			stdout, stepErr = r.runCommand(stepCtx, "prepare", command, contextDir)
+			return
+		}); err != nil {
+			return
+		}
+		resources.setOutput(command.Name, parseOutput(stdout))
+	}
+	return
+}
+
+// runClean sends the HTTP requests, runs the commands and the SQL scripts, then unwinds the teardowns of the prepared resources.
+// The containers are always removed, the Helm releases, the Kubernetes resources and the Docker Compose stacks are deleted
+// if the cleanPrepare is true. All the steps run even if some of them are failed or the context is canceled, the first
+// error will be returned.
+func (r *simpleTestCaseRunner) runClean(ctx context.Context, clean testing.Clean, contextDir string,
+	dataContext interface{}, resources *preparedResources) (err error) {
+	defer r.withLog(r.log.Component(ComponentPrepare))()
+	ctx = detachedContext{ctx}
+	for _, step := range clean.HTTP {
+		step := step
+		if cleanErr := r.runStep(ctx, "clean", httpStepName(step), step.Policy, func(stepCtx context.Context) (stepErr error) {
+			_, stepErr = r.runHTTPStep(stepCtx, "clean", step, contextDir, resources.withContext(dataContext))
+			return
+		}); cleanErr != nil && err == nil {
+			err = cleanErr
+		}
+	}
+
+	for _, command := range clean.Commands {
+		command := command
+		if cleanErr := r.runStep(ctx, "clean", commandName(command), command.Policy, func(stepCtx context.Context) (stepErr error) {
+			_, stepErr = r.runCommand(stepCtx, "clean", command, contextDir)
+			return
+		}); cleanErr != nil && err == nil {
+			err = cleanErr
+		}
+	}
+
+	if clean.SQL != nil {
+		if cleanErr := r.runStep(ctx, "clean", "run the SQL scripts", clean.SQL.Policy, func(stepCtx context.Context) error {
+			return r.runSQL(stepCtx, "clean", clean.SQL, contextDir, resources.withContext(dataContext))
+		}); cleanErr != nil && err == nil {
+			err = cleanErr
+		}
+	}
+
+	if cleanErr := r.unwind(ctx, resources, clean.CleanPrepare); cleanErr != nil && err == nil {
+		err = cleanErr
+	}
+	return
+}
+
+// composeUp starts the stack, --wait blocks until the containers are running or healthy if they have health checks
+func (r *simpleTestCaseRunner) composeUp(ctx context.Context, item testing.DockerCompose, contextDir string) (err error) {
+	args := composeArgs(item, contextDir, "up", "--detach", "--wait")
+	if item.Timeout != "" {
+		var timeout time.Duration
+		if timeout, err = time.ParseDuration(item.Timeout); err != nil {
+			err = fmt.Errorf("invalid timeout of %s: %v", item.File, err)
+			return
+		}
+		// the timeout is in seconds, it's rounded up
+		args = append(args, "--wait-timeout", fmt.Sprint(int64((timeout+time.Second-1)/time.Second)))
+	}
+	args = append(args, item.Services...)
+
+	if err = r.runCLI(ctx, "docker", args); err != nil {
+		err = fmt.Errorf("failed to start %s: %v", item.File, err)
+	}
+	return
+}
+
+// composeDown stops the stack, and removes the volumes of it
+func (r *simpleTestCaseRunner) composeDown(ctx context.Context, item testing.DockerCompose, contextDir string) (err error) {
+	if err = r.runCLI(ctx, "docker", composeArgs(item, contextDir, "down", "--volumes", "--remove-orphans")); err != nil {
+		err = fmt.Errorf("failed to stop %s: %v", item.File, err)
+	}
+	return
+}
+
+// runCLI runs the command line tool, such as docker and helm. It's killed once the context is done, such as the timeout of the step
+func (r *simpleTestCaseRunner) runCLI(ctx context.Context, name string, args []string) (err error) {
+	output := new(bytes.Buffer)
+	var exitCode int
+	if exitCode, err = r.executor.Run(ctx, exec.Command{Name: name, Args: args}, output); err == nil && exitCode != 0 {
+		err = fmt.Errorf("unexpected exit code %d, output: %s", exitCode, output.String())
+	}
+	r.log.Debug("output of %s %s:\n%s\n", name, strings.Join(args, " "), output.String())
+	return
+}
+
+// composeArgs returns the arguments of the docker compose command with the file and the project
+func composeArgs(item testing.DockerCompose, contextDir string, command ...string) []string {
+	args := []string{"compose", "-f", resolvePath(contextDir, item.File)}
+	if item.Project != "" {
+		args = append(args, "--project-name", item.Project)
+	}
+	return append(args, command...)
+}
+
+// runCommand runs the command, and returns the stdout of it
+func (r *simpleTestCaseRunner) runCommand(ctx context.Context, phase string, command testing.Command, contextDir string) (stdout string, err error) {
+	cmd := exec.Command{
+		Name: command.Command,
+		Args: command.Args,
+		Env:  command.Env,
+		Dir:  command.Dir,
+	}
+	if cmd.Dir == "" {
+		cmd.Dir = contextDir
+	} else {
+		cmd.Dir = resolvePath(contextDir, cmd.Dir)
+	}
+	if command.Timeout != "" {
+		if cmd.Timeout, err = time.ParseDuration(command.Timeout); err != nil {
+			err = fmt.Errorf("invalid timeout of command '%s': %v", command.Command, err)
+			return
+		}
+	}
+
+	name := commandName(command)
+	r.log.Info("%s: run %s\n", phase, name)
+
+	output, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.Stderr = stderr
+	var exitCode int
+	if exitCode, err = r.executor.Run(ctx, cmd, output); err != nil {
+		err = fmt.Errorf("%s: failed to run %s: %v", phase, name, err)
+	} else if !expectExitCode(command.ExitCodes, exitCode) {
+		err = fmt.Errorf("%s: unexpected exit code %d of %s, output: %s%s", phase, exitCode, name, output.String(), stderr.String())
+	}
+	r.log.Debug("%s: output of %s:\n%s%s\n", phase, name, output.String(), stderr.String())
+	stdout = output.String()
+	return
+}
+
+// parseOutput returns the parsed JSON, or the trimmed text if it's not JSON
+func parseOutput(text string) (output interface{}) {
+	text = strings.TrimSpace(text)
+	if err := json.Unmarshal([]byte(text), &output); err != nil {
+		output = text
+	}
+	return
+}
+
+// commandName returns the name of the command, or the command line if the name is empty
+func commandName(command testing.Command) string {
+	if command.Name != "" {
+		return command.Name
+	}
+	return strings.TrimSpace(strings.Join(append([]string{command.Command}, command.Args...), " "))
+}
+
+func expectExitCode(expected []int, exitCode int) bool {
+	if len(expected) == 0 {
+		return exitCode == 0
+	}
+	for _, code := range expected {
+		if code == exitCode {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath returns the path which is relative to the directory of the test suite
+func resolvePath(contextDir, path string) string {
+	if !filepath.IsAbs(path) && contextDir != "" {
+		path = filepath.Join(contextDir, path)
+	}
+	return path
+}
@@ -0,0 +1,332 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPrepareAndClean(t *testing.T) {
+	tests := []struct {
+		name     string
+		testCase *atest.TestCase
+		executor *exec.FakeExecutor
+		execer   fakeruntime.Execer
+		verify   func(*testing.T, *exec.FakeExecutor, string, error)
+	}{{
+		name: "normal",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{Commands: []atest.Command{{
+				Command: "make",
+				Args:    []string{"seed"},
+				Env:     map[string]string{"key": "value"},
+				Dir:     "sub",
+				Timeout: "1m",
+			}}},
+			Clean: atest.Clean{Commands: []atest.Command{{
+				Name:    "reset",
+				Command: "make",
+				Args:    []string{"reset"},
+				Dir:     "/tmp",
+			}}},
+		},
+		executor: &exec.FakeExecutor{Output: "done"},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, []exec.Command{{
+				Name:    "make",
+				Args:    []string{"seed"},
+				Env:     map[string]string{"key": "value"},
+				Dir:     filepath.Join("suites", "sub"),
+				Timeout: time.Minute,
+			}, {
+				Name: "make",
+				Args: []string{"reset"},
+				Dir:  "/tmp",
+			}}, executor.Commands)
+			assert.Contains(t, log, "prepare: run make seed")
+			assert.Contains(t, log, "clean: run reset")
+			assert.Contains(t, log, "done")
+		},
+	}, {
+		name: "expected exit code",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{Commands: []atest.Command{{
+				Command:   "grep",
+				ExitCodes: []int{0, 1},
+			}}},
+		},
+		executor: &exec.FakeExecutor{ExitCode: 1},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.Nil(t, err)
+		},
+	}, {
+		name: "unexpected exit code",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{Commands: []atest.Command{{Command: "make"}, {Command: "make"}}},
+			Clean:   atest.Clean{Commands: []atest.Command{{Command: "reset"}}},
+		},
+		executor: &exec.FakeExecutor{ExitCode: 2, Output: "no rule"},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.ErrorContains(t, err, "unexpected exit code 2 of make, output: no rule")
+			// the clean steps run even if the prepare is failed
+			assert.Equal(t, 2, len(executor.Commands))
+		},
+	}, {
+		name: "skip the clean on failure",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{Commands: []atest.Command{{Command: "make"}}},
+			Clean: atest.Clean{
+				CleanPolicy: atest.CleanPolicyOnSuccess,
+				Commands:    []atest.Command{{Command: "reset"}},
+			},
+		},
+		executor: &exec.FakeExecutor{ExitCode: 2},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.NotNil(t, err)
+			assert.Equal(t, 1, len(executor.Commands))
+			assert.Contains(t, log, "due to the policy: onSuccess")
+		},
+	}, {
+		name: "clean on failure only",
+		testCase: &atest.TestCase{
+			Clean: atest.Clean{
+				CleanPolicy: atest.CleanPolicyOnFailure,
+				Commands:    []atest.Command{{Command: "reset"}},
+			},
+		},
+		executor: &exec.FakeExecutor{},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.Nil(t, err)
+			assert.Empty(t, executor.Commands)
+		},
+	}, {
+		name: "failed to run",
+		testCase: &atest.TestCase{
+			Clean: atest.Clean{Commands: []atest.Command{{Command: "reset"}}},
+		},
+		executor: &exec.FakeExecutor{Err: errors.New("fake")},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.ErrorContains(t, err, "clean: failed to run reset")
+		},
+	}, {
+		name: "invalid timeout",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{Commands: []atest.Command{{Command: "make", Timeout: "fake"}}},
+		},
+		executor: &exec.FakeExecutor{},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.NotNil(t, err)
+			assert.Empty(t, executor.Commands)
+		},
+	}, {
+		name: "failed to apply the Kubernetes manifests",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{Kubernetes: []atest.Kubernetes{{File: "demo.yaml"}}},
+			Clean:   atest.Clean{CleanPrepare: true},
+		},
+		executor: &exec.FakeExecutor{},
+		execer:   fakeruntime.FakeExecer{ExpectError: errors.New("fake")},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.ErrorContains(t, err, "failed to apply demo.yaml")
+		},
+	}, {
+		name: "helm",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{Helm: []atest.Helm{{Chart: "bitnami/nginx", Release: "web"}}},
+			Clean:   atest.Clean{CleanPrepare: true},
+		},
+		executor: &exec.FakeExecutor{},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, []exec.Command{{
+				Name: "helm",
+				Args: []string{"upgrade", "web", "bitnami/nginx", "--install", "--create-namespace", "--wait", "--timeout", "5m0s"},
+			}, {
+				Name: "helm",
+				Args: []string{"uninstall", "web", "--ignore-not-found"},
+			}}, executor.Commands)
+		},
+	}, {
+		name: "failed to install the Helm release",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{Helm: []atest.Helm{{Chart: "bitnami/nginx", Release: "web"}}},
+			Clean:   atest.Clean{CleanPrepare: true},
+		},
+		executor: &exec.FakeExecutor{ExitCode: 1, Output: "timed out waiting for the condition"},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.ErrorContains(t, err, "failed to install web: unexpected exit code 1")
+			if assert.Equal(t, 2, len(executor.Commands)) {
+				assert.Equal(t, []string{"uninstall", "web", "--ignore-not-found"}, executor.Commands[1].Args)
+			}
+		},
+	}, {
+		name: "docker compose",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{DockerCompose: []atest.DockerCompose{{File: "compose.yaml"}}},
+			Clean:   atest.Clean{CleanPrepare: true},
+		},
+		executor: &exec.FakeExecutor{},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.Nil(t, err)
+			compose := filepath.Join("suites", "compose.yaml")
+			assert.Equal(t, []exec.Command{{
+				Name: "docker",
+				Args: []string{"compose", "-f", compose, "up", "--detach", "--wait"},
+			}, {
+				Name: "docker",
+				Args: []string{"compose", "-f", compose, "down", "--volumes", "--remove-orphans"},
+			}}, executor.Commands)
+		},
+	}, {
+		name: "failed to start the Docker Compose stack",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{DockerCompose: []atest.DockerCompose{{File: "compose.yaml"}}},
+		},
+		executor: &exec.FakeExecutor{ExitCode: 1, Output: "no such image"},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.ErrorContains(t, err, "failed to start compose.yaml: unexpected exit code 1, output: no such image")
+		},
+	}, {
+		name: "invalid timeout of the Docker Compose stack",
+		testCase: &atest.TestCase{
+			Prepare: atest.Prepare{DockerCompose: []atest.DockerCompose{{File: "compose.yaml", Timeout: "fake"}}},
+		},
+		executor: &exec.FakeExecutor{},
+		verify: func(t *testing.T, executor *exec.FakeExecutor, log string, err error) {
+			assert.ErrorContains(t, err, "invalid timeout of compose.yaml")
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New(urlLocalhost).Get("/foo").Reply(http.StatusOK).BodyString(`{}`)
+			tt.testCase.Request.API = urlFoo
+
+			buf := new(bytes.Buffer)
+			runner := NewSimpleTestCaseRunner().WithOutputWriter(buf).WithWriteLevel("debug")
+			if tt.execer != nil {
+				runner.WithExecer(tt.execer)
+			}
+			runner.(*simpleTestCaseRunner).executor = tt.executor
+
+			ctx := context.WithValue(context.TODO(), NewContextKeyBuilder().ParentDir(), "suites")
+			_, err := runner.RunTestCase(tt.testCase, nil, ctx)
+			tt.verify(t, tt.executor, buf.String(), err)
+		})
+	}
+}
+
+// blockingExecutor blocks the commands until the context is done
+type blockingExecutor struct {
+	stopped chan struct{}
+}
+
+func (e *blockingExecutor) Run(ctx context.Context, command exec.Command, output io.Writer) (int, error) {
+	<-ctx.Done()
+	close(e.stopped)
+	return -1, nil
+}
+
+func TestComposeStepTimeout(t *testing.T) {
+	executor := &blockingExecutor{stopped: make(chan struct{})}
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+	runner.executor = executor
+
+	_, err := runner.RunTestCase(&atest.TestCase{
+		Prepare: atest.Prepare{DockerCompose: []atest.DockerCompose{{
+			File:   "compose.yaml",
+			Policy: &atest.StepPolicy{Timeout: "10ms"},
+		}}},
+		Request: atest.Request{API: urlFoo},
+	}, nil, context.TODO())
+	assert.ErrorContains(t, err, "timeout after 10ms")
+
+	select {
+	case <-executor.stopped:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the docker compose command is not stopped")
+	}
+}
+
+func TestCommandOutputInContext(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlLocalhost).Get("/foo").MatchHeader("version", "v1").Reply(http.StatusOK).BodyString(`{}`)
+
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+	runner.executor = &exec.FakeExecutor{Output: "v1\n"}
+	_, err := runner.RunTestCase(&atest.TestCase{
+		Prepare: atest.Prepare{Commands: []atest.Command{{
+			Name:    "version",
+			Command: "git",
+			Args:    []string{"describe"},
+		}}},
+		Request: atest.Request{
+			API:    urlFoo,
+			Header: map[string]string{"version": "{{.prepare.version}}"},
+		},
+	}, nil, context.TODO())
+	assert.Nil(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+func TestParseOutput(t *testing.T) {
+	assert.Equal(t, "text", parseOutput(" text\n"))
+	assert.Equal(t, map[string]interface{}{"ip": "10.0.0.1"}, parseOutput(`{"ip": "10.0.0.1"}`))
+	assert.Equal(t, float64(1), parseOutput("1"))
+}
+
+func TestExpectExitCode(t *testing.T) {
+	assert.True(t, expectExitCode(nil, 0))
+	assert.False(t, expectExitCode(nil, 1))
+	assert.True(t, expectExitCode([]int{1, 2}, 2))
+	assert.False(t, expectExitCode([]int{1, 2}, 0))
+}
+
+func TestResolvePath(t *testing.T) {
+	assert.Equal(t, filepath.Join("suites", "compose.yaml"), resolvePath("suites", "compose.yaml"))
+	assert.Equal(t, "/tmp/compose.yaml", resolvePath("suites", "/tmp/compose.yaml"))
+	assert.Equal(t, "compose.yaml", resolvePath("", "compose.yaml"))
+}
+
+func TestComposeArgs(t *testing.T) {
+	assert.Equal(t, []string{"compose", "-f", filepath.Join("suites", "compose.yaml"), "down"},
+		composeArgs(atest.DockerCompose{File: "compose.yaml"}, "suites", "down"))
+	assert.Equal(t, []string{"compose", "-f", "compose.yaml", "--project-name", "ci-1", "up", "--wait"},
+		composeArgs(atest.DockerCompose{File: "compose.yaml", Project: "ci-1"}, "", "up", "--wait"))
+}
+
+func TestPreparedResourcesWithContext(t *testing.T) {
+	resources := &preparedResources{}
+	assert.Nil(t, resources.withContext(nil))
+
+	resources.containerContext = map[string]interface{}{"db": "info"}
+	assert.Equal(t, map[string]interface{}{
+		"containers": map[string]interface{}{"db": "info"},
+	}, resources.withContext(nil))
+	assert.Equal(t, "fake", resources.withContext("fake"))
+	assert.Equal(t, map[string]interface{}{
+		"key":        "value",
+		"containers": map[string]interface{}{"db": "info", "web": "info"},
+	}, resources.withContext(map[string]interface{}{
+		"key":        "value",
+		"containers": map[string]interface{}{"web": "info"},
+	}))
+
+	resources.outputs = map[string]interface{}{"login": "token"}
+	assert.Equal(t, map[string]interface{}{
+		"containers": map[string]interface{}{"db": "info"},
+		"prepare":    map[string]interface{}{"login": "token"},
+	}, resources.withContext(nil))
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// encodeProtobuf encodes the JSON body into the binary form of the request message
+func encodeProtobuf(options *testing.Protobuf, body []byte, contextDir string) (data []byte, err error) {
+	var message *dynamicpb.Message
+	if message, err = newProtobufMessage(options.ProtoSet, options.Request, contextDir); err != nil {
+		return
+	}
+
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err = protojson.Unmarshal(body, message); err != nil {
+			err = fmt.Errorf("invalid request message of %s: %v", options.Request, err)
+			return
+		}
+	}
+	data, err = proto.Marshal(message)
+	return
+}
+
+// decodeProtobuf decodes the binary body into the JSON form of the response message.
+// The JSON and the text bodies are kept as they are, such as the error pages of the gateways
+func decodeProtobuf(options *testing.Protobuf, contentType string, body []byte, contextDir string) (data []byte, err error) {
+	if mediaType, _, _ := mime.ParseMediaType(contentType); strings.HasSuffix(mediaType, "json") || strings.HasPrefix(mediaType, "text/") {
+		data = body
+		return
+	}
+
+	var message *dynamicpb.Message
+	if message, err = newProtobufMessage(options.ProtoSet, options.Response, contextDir); err != nil {
+		return
+	}
+	if err = proto.Unmarshal(body, message); err != nil {
+		err = fmt.Errorf("invalid response message of %s: %v", options.Response, err)
+		return
+	}
+
+	if data, err = (protojson.MarshalOptions{EmitUnpopulated: true}).Marshal(message); err == nil {
+		// the output of protojson is unstable on purpose, it's compacted for the comparison of the body
+		buf := new(bytes.Buffer)
+		if err = json.Compact(buf, data); err == nil {
+			data = buf.Bytes()
+		}
+	}
+	return
+}
+
+// newProtobufMessage creates the message via the descriptors of the protoset file
+func newProtobufMessage(protoSet, name, contextDir string) (message *dynamicpb.Message, err error) {
+	var files *protoregistry.Files
+	if files, err = loadProtoSet(resolvePath(contextDir, protoSet)); err != nil {
+		return
+	}
+
+	var descriptor protoreflect.Descriptor
+	if descriptor, err = files.FindDescriptorByName(protoreflect.FullName(name)); err != nil {
+		err = fmt.Errorf("cannot find the message %s: %v", name, err)
+		return
+	}
+
+	messageDescriptor, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		err = fmt.Errorf("%s is not a message", name)
+		return
+	}
+	message = dynamicpb.NewMessage(messageDescriptor)
+	return
+}
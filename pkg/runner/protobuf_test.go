@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/linuxsuren/api-testing/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestProtobuf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		request := &grpc_health_v1.HealthCheckRequest{}
+		if r.Header.Get(util.ContentType) != util.Protobuf || proto.Unmarshal(data, request) != nil {
+			w.Header().Set(util.ContentType, util.JSON)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "invalid request"}`))
+			return
+		}
+
+		status := grpc_health_v1.HealthCheckResponse_SERVING
+		if request.Service != "" {
+			status = grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+		data, _ = proto.Marshal(&grpc_health_v1.HealthCheckResponse{Status: status})
+		w.Header().Set(util.ContentType, r.Header.Get("Accept"))
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(grpc_health_v1.File_grpc_health_v1_health_proto),
+	}})
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "health.protoset"), data, 0644))
+
+	tests := []struct {
+		name     string
+		body     string
+		header   map[string]string
+		protobuf *atest.Protobuf
+		expect   atest.Response
+		err      string
+	}{{
+		name:     "normal",
+		body:     `{"service": ""}`,
+		protobuf: &atest.Protobuf{ProtoSet: "health.protoset", Request: "grpc.health.v1.HealthCheckRequest", Response: "grpc.health.v1.HealthCheckResponse"},
+		expect:   atest.Response{Body: `{"status":"SERVING"}`},
+	}, {
+		name:     "templated body",
+		body:     `{"service": "{{.service}}"}`,
+		protobuf: &atest.Protobuf{ProtoSet: "health.protoset", Request: "grpc.health.v1.HealthCheckRequest", Response: "grpc.health.v1.HealthCheckResponse"},
+		expect:   atest.Response{BodyFieldsExpect: map[string]interface{}{"status": "SERVICE_UNKNOWN"}},
+	}, {
+		name:     "the JSON error is kept",
+		header:   map[string]string{util.ContentType: util.JSON},
+		protobuf: &atest.Protobuf{ProtoSet: "health.protoset", Request: "grpc.health.v1.HealthCheckRequest", Response: "grpc.health.v1.HealthCheckResponse"},
+		expect:   atest.Response{StatusCode: http.StatusBadRequest, BodyFieldsExpect: map[string]interface{}{"message": "invalid request"}},
+	}, {
+		name:     "invalid request message",
+		body:     `{"fake": ""}`,
+		protobuf: &atest.Protobuf{ProtoSet: "health.protoset", Request: "grpc.health.v1.HealthCheckRequest"},
+		err:      "invalid request message of grpc.health.v1.HealthCheckRequest",
+	}, {
+		name:     "not found message",
+		protobuf: &atest.Protobuf{ProtoSet: "health.protoset", Request: "grpc.health.v1.Fake"},
+		err:      "cannot find the message grpc.health.v1.Fake",
+	}, {
+		name:     "not a message",
+		protobuf: &atest.Protobuf{ProtoSet: "health.protoset", Request: "grpc.health.v1.Health"},
+		err:      "grpc.health.v1.Health is not a message",
+	}, {
+		name:     "not found protoset",
+		protobuf: &atest.Protobuf{ProtoSet: "fake.protoset", Request: "grpc.health.v1.HealthCheckRequest"},
+		err:      "fake.protoset",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.TODO(), NewContextKeyBuilder().ParentDir(), dir)
+			_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+				Name: tt.name,
+				Request: atest.Request{
+					API:      server.URL + "/health",
+					Method:   http.MethodPost,
+					Header:   tt.header,
+					Body:     tt.body,
+					Protobuf: tt.protobuf,
+				},
+				Expect: tt.expect,
+			}, map[string]interface{}{"service": "fake"}, ctx)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestDecodeProtobuf(t *testing.T) {
+	data, err := decodeProtobuf(&atest.Protobuf{Response: "fake"}, "text/plain; charset=utf-8", []byte("bad gateway"), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "bad gateway", string(data))
+}
@@ -0,0 +1,85 @@
+package runner
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"golang.org/x/net/http2"
+)
+
+const (
+	// ProtocolHTTP1 forces the HTTP/1.1, the HTTP/2 is not negotiated
+	ProtocolHTTP1 = "http1"
+	// ProtocolHTTP2 requires the HTTP/2, it's negotiated via ALPN over TLS, or the h2c with the prior knowledge over the cleartext
+	ProtocolHTTP2 = "http2"
+)
+
+// newProtocolTransport returns the transport of the protocol of the request, the network and the resolve options are honored.
+// The proxy is not supported by the HTTP/2 transport
+func newProtocolTransport(req *testing.Request, scheme string, tlsConfig *tls.Config) (transport http.RoundTripper, err error) {
+	var dial dialFunc
+	if dial, err = newRequestDial(req); err != nil {
+		return
+	}
+
+	switch req.Protocol {
+	case ProtocolHTTP1:
+		http1 := &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+			DialContext:     dial,
+			// the non-nil empty map disables the HTTP/2
+			TLSNextProto: map[string]func(string, *tls.Conn) http.RoundTripper{},
+		}
+		if req.Proxy != nil {
+			if http1.Proxy, err = newProxyFunc(req.Proxy); err != nil {
+				return
+			}
+		}
+		if err = setPool(http1, req.Pool); err == nil {
+			transport = http1
+		}
+	case ProtocolHTTP2:
+		if req.Proxy != nil {
+			err = fmt.Errorf("the proxy is not supported by the protocol %s", req.Protocol)
+			return
+		}
+		if dial == nil {
+			dialer := &net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}
+			dial = dialer.DialContext
+		}
+
+		http2Transport := &http2.Transport{TLSClientConfig: tlsConfig}
+		if scheme == "http" {
+			http2Transport.AllowHTTP = true
+			http2Transport.DialTLSContext = func(ctx context.Context, network, address string, _ *tls.Config) (net.Conn, error) {
+				return dial(ctx, network, address)
+			}
+		} else {
+			http2Transport.DialTLSContext = func(ctx context.Context, network, address string, config *tls.Config) (net.Conn, error) {
+				conn, err := dial(ctx, network, address)
+				if err != nil {
+					return nil, err
+				}
+				tlsConn := tls.Client(conn, config)
+				if err = tlsConn.HandshakeContext(ctx); err != nil {
+					_ = conn.Close()
+					return nil, err
+				}
+				return tlsConn, nil
+			}
+		}
+		transport = http2Transport
+	default:
+		err = fmt.Errorf("not supported protocol: '%s', it should be %s or %s", req.Protocol, ProtocolHTTP1, ProtocolHTTP2)
+	}
+	return
+}
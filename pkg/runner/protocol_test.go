@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestProtocol(t *testing.T) {
+	// the server responds the protocol of the request
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"proto":"` + r.Proto + `"}`))
+	})
+	cleartextServer := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer cleartextServer.Close()
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+
+	tests := []struct {
+		name     string
+		api      string
+		protocol string
+		expect   string
+	}{{
+		name:     "h2c",
+		api:      cleartextServer.URL,
+		protocol: ProtocolHTTP2,
+		expect:   "HTTP/2.0",
+	}, {
+		name:     "HTTP/1.1 over the cleartext",
+		api:      cleartextServer.URL,
+		protocol: ProtocolHTTP1,
+		expect:   "HTTP/1.1",
+	}, {
+		name:     "HTTP/2 over TLS",
+		api:      tlsServer.URL,
+		protocol: ProtocolHTTP2,
+		expect:   "HTTP/2.0",
+	}, {
+		name:     "HTTP/1.1 over TLS",
+		api:      tlsServer.URL,
+		protocol: ProtocolHTTP1,
+		expect:   "HTTP/1.1",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := NewMemoryTestReporter()
+			_, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+				Request: atest.Request{API: tt.api, Protocol: tt.protocol},
+				Expect:  atest.Response{BodyFieldsExpect: map[string]interface{}{"proto": tt.expect}},
+			}, nil, context.TODO())
+			assert.NoError(t, err)
+			if records := reporter.GetAllRecords(); assert.Len(t, records, 1) {
+				assert.Equal(t, tt.expect, records[0].Protocol)
+			}
+		})
+	}
+
+	t.Run("not supported protocol", func(t *testing.T) {
+		_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+			Request: atest.Request{API: cleartextServer.URL, Protocol: "http3"},
+		}, nil, context.TODO())
+		assert.ErrorContains(t, err, "not supported protocol")
+	})
+
+	t.Run("HTTP/2 with the proxy", func(t *testing.T) {
+		_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+			Request: atest.Request{API: cleartextServer.URL, Protocol: ProtocolHTTP2, Proxy: &atest.Proxy{URL: "http://proxy:3128"}},
+		}, nil, context.TODO())
+		assert.ErrorContains(t, err, "the proxy is not supported")
+	})
+}
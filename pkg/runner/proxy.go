@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+type proxyFunc func(*http.Request) (*url.URL, error)
+
+// newProxyFunc returns the proxy of the transport, the hosts of the NoProxy are connected directly
+func newProxyFunc(options *testing.Proxy) (proxy proxyFunc, err error) {
+	var proxyURL *url.URL
+	if proxyURL, err = url.Parse(options.URL); err != nil {
+		err = fmt.Errorf("invalid proxy URL: %v", err)
+		return
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		err = fmt.Errorf("not supported proxy: '%s', the scheme should be http, https or socks5", options.URL)
+		return
+	}
+	if options.Username != "" {
+		proxyURL.User = url.UserPassword(options.Username, options.Password)
+	}
+
+	proxy = func(req *http.Request) (*url.URL, error) {
+		if isNoProxy(req.URL.Hostname(), options.NoProxy) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}
+	return
+}
+
+func isNoProxy(host string, noProxy []string) bool {
+	for _, item := range noProxy {
+		item = strings.TrimPrefix(strings.TrimSpace(item), ".")
+		if item == "*" || host == item || strings.HasSuffix(host, "."+item) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxy(t *testing.T) {
+	os.Setenv("PROXY_PASSWORD", "proxy-password")
+	defer os.Unsetenv("PROXY_PASSWORD")
+
+	// the proxy responds the absolute URL and the credential of the request
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"url":"` + r.URL.String() + `","auth":"` + r.Header.Get("Proxy-Authorization") + `"}`))
+	}))
+	defer proxyServer.Close()
+
+	_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: "http://example.com/foo", Proxy: &atest.Proxy{
+			URL:      proxyServer.URL,
+			Username: "admin",
+			Password: `{{secret "PROXY_PASSWORD"}}`,
+		}},
+		Expect: atest.Response{BodyFieldsExpect: map[string]interface{}{
+			"url":  "http://example.com/foo",
+			"auth": "Basic YWRtaW46cHJveHktcGFzc3dvcmQ=",
+		}},
+	}, nil, context.TODO())
+	assert.Nil(t, err)
+
+	_, err = NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: "http://example.com/foo", Proxy: &atest.Proxy{URL: "ftp://proxy"}},
+	}, nil, context.TODO())
+	assert.ErrorContains(t, err, "not supported proxy")
+}
+
+func TestNewProxyFunc(t *testing.T) {
+	proxy, err := newProxyFunc(&atest.Proxy{URL: "socks5://proxy:1080", NoProxy: []string{"localhost", ".example.com"}})
+	assert.Nil(t, err)
+
+	for api, expect := range map[string]string{
+		"http://foo.com":         "socks5://proxy:1080",
+		"http://localhost:8080":  "",
+		"http://example.com":     "",
+		"http://api.example.com": "",
+		"http://myexample.com":   "socks5://proxy:1080",
+	} {
+		request, _ := http.NewRequest(http.MethodGet, api, nil)
+		proxyURL, err := proxy(request)
+		assert.Nil(t, err)
+		if expect == "" {
+			assert.Nil(t, proxyURL, api)
+		} else {
+			assert.Equal(t, expect, proxyURL.String(), api)
+		}
+	}
+
+	proxy, err = newProxyFunc(&atest.Proxy{URL: "http://proxy", NoProxy: []string{"*"}})
+	assert.Nil(t, err)
+	request, _ := http.NewRequest(http.MethodGet, "http://foo.com", nil)
+	proxyURL, _ := proxy(request)
+	assert.Nil(t, proxyURL)
+
+	_, err = newProxyFunc(&atest.Proxy{URL: ":fake"})
+	assert.ErrorContains(t, err, "invalid proxy URL")
+}
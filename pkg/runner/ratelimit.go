@@ -0,0 +1,25 @@
+package runner
+
+import (
+	"context"
+
+	"github.com/linuxsuren/api-testing/pkg/limit"
+)
+
+// RateLimiter returns the key of the rate limiter of the HTTP requests
+func (c ContextKey) RateLimiter() ContextKey {
+	return ContextKey("rateLimiter")
+}
+
+// WithRateLimiter returns a context with the limiter, each HTTP request of the test cases waits for it,
+// including the retries and the fuzz iterations
+func WithRateLimiter(ctx context.Context, limiter limit.RateLimiter) context.Context {
+	return context.WithValue(ctx, NewContextKeyBuilder().RateLimiter(), limiter)
+}
+
+// waitRateLimit waits for the rate limiter of the context if there is one
+func waitRateLimit(ctx context.Context) {
+	if limiter, ok := ctx.Value(NewContextKeyBuilder().RateLimiter()).(limit.RateLimiter); ok {
+		limiter.Accept()
+	}
+}
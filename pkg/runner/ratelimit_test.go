@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/limit"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlFoo).Get("/").Times(3).Reply(http.StatusOK).BodyString("{}")
+
+	ctx := WithRateLimiter(context.Background(), limit.NewDefaultRateLimiter(10, 1))
+	reporter := NewMemoryTestReporter()
+	begin := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+			Request: atest.Request{API: urlFoo},
+		}, nil, ctx)
+		assert.NoError(t, err)
+	}
+	// the first token is in the bucket, the other two are refilled at 10 per second
+	assert.GreaterOrEqual(t, time.Since(begin), 150*time.Millisecond)
+	assert.True(t, gock.IsDone())
+
+	for _, record := range reporter.GetAllRecords() {
+		assert.Less(t, record.ResponseTime, 50*time.Millisecond, "the waiting is not a part of the response time")
+	}
+
+	t.Run("no rate limiter", func(t *testing.T) {
+		waitRateLimit(context.Background())
+	})
+}
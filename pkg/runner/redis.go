@@ -0,0 +1,144 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/antonmedv/expr"
+	"github.com/linuxsuren/api-testing/pkg/render"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultRedisTimeout = 10 * time.Second
+
+// runRedis runs the commands one by one, the replies are verified if there are expectations of them.
+// The address, the credential and the arguments are templated
+func (r *simpleTestCaseRunner) runRedis(ctx context.Context, phase string, options *testing.Redis, dataContext interface{}) (err error) {
+	if options == nil || len(options.Commands) == 0 {
+		return
+	}
+
+	values := map[string]string{"address": options.Address, "username": options.Username, "password": options.Password}
+	for key, val := range values {
+		if values[key], err = render.Render("redis "+key, val, dataContext); err != nil {
+			return
+		}
+	}
+
+	var clientOptions *redis.Options
+	if clientOptions, err = newRedisOptions(values["address"], values["username"], values["password"], options.DB); err != nil {
+		err = fmt.Errorf("%s: invalid Redis address %s: %v", phase, values["address"], err)
+		return
+	}
+	client := redis.NewClient(clientOptions)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if err = client.Ping(ctx).Err(); err != nil {
+		err = fmt.Errorf("%s: failed to connect the Redis %s: %v", phase, values["address"], err)
+		return
+	}
+
+	for i, command := range options.Commands {
+		args := make([]interface{}, len(command.Args))
+		for j, arg := range command.Args {
+			if args[j], err = render.Render("redis argument", arg, dataContext); err != nil {
+				return
+			}
+		}
+		if len(args) == 0 {
+			err = fmt.Errorf("%s: the Redis command %d is empty", phase, i+1)
+			return
+		}
+
+		r.log.Debug("%s: run Redis command %s\n", phase, args[0])
+		var reply interface{}
+		if reply, err = client.Do(ctx, args...).Result(); errors.Is(err, redis.Nil) {
+			reply, err = nil, nil
+		} else if err != nil {
+			err = fmt.Errorf("%s: failed to run the Redis command %d %s: %v", phase, i+1, args[0], err)
+			return
+		}
+		if err = verifyRedisReply(command, reply); err != nil {
+			err = fmt.Errorf("%s: the Redis command %d %s %v", phase, i+1, args[0], err)
+			return
+		}
+	}
+	return
+}
+
+// newRedisOptions parses the address which could be the URL of the redis or rediss scheme, the username,
+// the password and the database override the ones of the URL. The replies are in the RESP2 protocol
+func newRedisOptions(address, username, password string, db int) (options *redis.Options, err error) {
+	if strings.HasPrefix(address, "redis://") || strings.HasPrefix(address, "rediss://") {
+		if options, err = redis.ParseURL(address); err != nil {
+			return
+		}
+	} else {
+		options = &redis.Options{Addr: address}
+	}
+
+	if username != "" {
+		options.Username = username
+	}
+	if password != "" {
+		options.Password = password
+	}
+	if db > 0 {
+		options.DB = db
+	}
+	options.Protocol = 2
+	options.MaxRetries = -1
+	options.DialTimeout = defaultRedisTimeout
+	options.ReadTimeout = defaultRedisTimeout
+	options.WriteTimeout = defaultRedisTimeout
+	options.ContextTimeoutEnabled = true
+	return
+}
+
+// verifyRedisReply compares the reply with the expected one, then checks the expressions of it
+func verifyRedisReply(command testing.RedisCommand, reply interface{}) (err error) {
+	switch {
+	case command.NilReply && reply != nil:
+		err = fmt.Errorf("expect the nil reply, actual: %v", reply)
+	case command.Reply != nil && !reflect.DeepEqual(redisText(command.Reply), redisText(reply)):
+		err = fmt.Errorf("expect reply: %v, actual: %v", command.Reply, reply)
+	}
+	if err != nil {
+		return
+	}
+
+	env := map[string]interface{}{"reply": reply}
+	for _, verify := range command.Verify {
+		var result interface{}
+		if result, err = expr.Eval(verify, env); err != nil {
+			return
+		} else if ok, _ := result.(bool); !ok {
+			err = fmt.Errorf("failed to verify: %s, the reply is %v", verify, reply)
+			return
+		}
+	}
+	return
+}
+
+// redisText converts the scalars into strings, the arrays are converted item by item
+func redisText(value interface{}) interface{} {
+	switch val := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			items[i] = redisText(item)
+		}
+		return items
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
@@ -0,0 +1,176 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeRedis serves PING, SET, GET, TTL, KEYS, AUTH and SELECT of the RESP2 protocol, the password is secret.
+// The other commands, such as HELLO, are unknown
+func newFakeRedis(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	var lock sync.Mutex
+	data := map[string]string{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					args, err := readFakeRedisCommand(reader)
+					if err != nil {
+						return
+					}
+
+					lock.Lock()
+					var response string
+					switch strings.ToUpper(args[0]) {
+					case "PING":
+						response = "+PONG\r\n"
+					case "AUTH":
+						response = "+OK\r\n"
+						if args[len(args)-1] != "secret" {
+							response = "-WRONGPASS invalid password\r\n"
+						}
+					case "SELECT":
+						response = "+OK\r\n"
+					case "SET":
+						data[args[1]] = args[2]
+						response = "+OK\r\n"
+					case "GET":
+						if val, ok := data[args[1]]; ok {
+							response = fmt.Sprintf("$%d\r\n%s\r\n", len(val), val)
+						} else {
+							response = "$-1\r\n"
+						}
+					case "TTL":
+						response = ":60\r\n"
+					case "KEYS":
+						response = fmt.Sprintf("*%d\r\n", len(data))
+						for key := range data {
+							response += fmt.Sprintf("$%d\r\n%s\r\n", len(key), key)
+						}
+					default:
+						response = "-ERR unknown command\r\n"
+					}
+					lock.Unlock()
+					_, _ = conn.Write([]byte(response))
+				}
+			}(conn)
+		}
+	}()
+	return listener
+}
+
+// readFakeRedisCommand reads a command which is an array of the bulk strings
+func readFakeRedisCommand(reader *bufio.Reader) (args []string, err error) {
+	readSize := func() (size int, err error) {
+		var line string
+		if line, err = reader.ReadString('\n'); err == nil {
+			size, err = strconv.Atoi(strings.TrimSpace(line)[1:])
+		}
+		return
+	}
+
+	var count int
+	if count, err = readSize(); err != nil {
+		return
+	}
+	for i := 0; i < count; i++ {
+		var size int
+		if size, err = readSize(); err != nil {
+			return
+		}
+		data := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, data); err != nil {
+			return
+		}
+		args = append(args, string(data[:size]))
+	}
+	return
+}
+
+func TestRunRedis(t *testing.T) {
+	listener := newFakeRedis(t)
+	defer listener.Close()
+
+	tests := []struct {
+		name     string
+		password string
+		commands []atest.RedisCommand
+		err      string
+	}{{
+		name:     "normal",
+		password: "{{.password}}",
+		commands: []atest.RedisCommand{
+			{Args: []string{"SET", "user:{{.id}}", "rick"}, Reply: "OK"},
+			{Args: []string{"GET", "user:1"}, Reply: "rick"},
+			{Args: []string{"GET", "user:2"}, NilReply: true},
+			{Args: []string{"TTL", "user:1"}, Reply: float64(60), Verify: []string{"reply > 0 && reply <= 60"}},
+			{Args: []string{"KEYS", "*"}, Reply: []interface{}{"user:1"}},
+		},
+	}, {
+		name:     "unexpected reply",
+		commands: []atest.RedisCommand{{Args: []string{"GET", "user:1"}, Reply: "morty"}},
+		err:      "expect: the Redis command 1 GET expect reply: morty, actual: rick",
+	}, {
+		name:     "unexpected nil reply",
+		commands: []atest.RedisCommand{{Args: []string{"GET", "user:1"}, NilReply: true}},
+		err:      "expect the nil reply, actual: rick",
+	}, {
+		name:     "failed to verify",
+		commands: []atest.RedisCommand{{Args: []string{"TTL", "user:1"}, Verify: []string{"reply > 60"}}},
+		err:      "failed to verify: reply > 60, the reply is 60",
+	}, {
+		name:     "error reply",
+		commands: []atest.RedisCommand{{Args: []string{"FLUSHALL"}}},
+		err:      "failed to run the Redis command 1 FLUSHALL: ERR unknown command",
+	}, {
+		name:     "wrong password",
+		password: "fake",
+		commands: []atest.RedisCommand{{Args: []string{"GET", "user:1"}}},
+		err:      "failed to connect the Redis",
+	}, {
+		name:     "empty command",
+		commands: []atest.RedisCommand{{}},
+		err:      "the Redis command 1 is empty",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+			err := runner.runRedis(context.TODO(), "expect", &atest.Redis{
+				Address:  "redis://" + listener.Addr().String(),
+				Password: tt.password,
+				DB:       1,
+				Commands: tt.commands,
+			}, map[string]interface{}{"id": "1", "password": "secret"})
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+
+	err := NewSimpleTestCaseRunner().(*simpleTestCaseRunner).runRedis(context.TODO(), "prepare", &atest.Redis{
+		Address:  "{{.fake",
+		Commands: []atest.RedisCommand{{Args: []string{"GET", "user:1"}}},
+	}, nil)
+	assert.Error(t, err)
+}
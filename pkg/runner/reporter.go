@@ -1,6 +1,12 @@
 package runner
 
-import "time"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/secret"
+)
 
 // TestReporter is the interface of the report
 type TestReporter interface {
@@ -11,12 +17,32 @@ type TestReporter interface {
 
 // ReportRecord represents the raw data of a HTTP request
 type ReportRecord struct {
+	// Name is the name of the test case
+	Name      string
 	Method    string
 	API       string
 	Body      string
 	BeginTime time.Time
 	EndTime   time.Time
 	Error     error
+	// Retries is the count of the retried attempts of the request
+	Retries int
+	// Timeout is true if the request is not responded in time
+	Timeout bool
+	// ResponseTime is the duration of the last attempt of the request
+	ResponseTime time.Duration
+	// Slow is true if the response time exceeds the max response time of the expect
+	Slow bool
+	// Findings are the problems which are found by the security checks
+	Findings []SecurityFinding
+	// Reused is true if the request is sent over an idle connection
+	Reused bool
+	// Protocol is the negotiated protocol of the response, such as: HTTP/1.1 or HTTP/2.0
+	Protocol string
+	// Skipped is true if the test case is skipped by its condition
+	Skipped bool
+	// WarmUp is true if the request is sent during the warm-up, it's excluded from the report results
+	WarmUp bool
 }
 
 // Duration returns the duration between begin and end time
@@ -32,6 +58,38 @@ func (r *ReportRecord) ErrorCount() int {
 	return 1
 }
 
+// TimeoutCount returns 1 if the request is not responded in time
+func (r *ReportRecord) TimeoutCount() int {
+	if r.Timeout {
+		return 1
+	}
+	return 0
+}
+
+// SlowCount returns 1 if the response time exceeds the max response time of the expect
+func (r *ReportRecord) SlowCount() int {
+	if r.Slow {
+		return 1
+	}
+	return 0
+}
+
+// SkippedCount returns 1 if the test case is skipped by its condition
+func (r *ReportRecord) SkippedCount() int {
+	if r.Skipped {
+		return 1
+	}
+	return 0
+}
+
+// ReusedCount returns 1 if the request is sent over an idle connection
+func (r *ReportRecord) ReusedCount() int {
+	if r.Reused {
+		return 1
+	}
+	return 0
+}
+
 // GetErrorMessage returns the error message
 func (r *ReportRecord) GetErrorMessage() string {
 	if r.ErrorCount() > 0 {
@@ -41,9 +99,39 @@ func (r *ReportRecord) GetErrorMessage() string {
 	}
 }
 
+// redact replaces the secrets in the API, the body, the error and the findings
+func (r *ReportRecord) redact() {
+	r.API = secret.Redact(r.API)
+	r.Body = secret.Redact(r.Body)
+	if r.Error != nil {
+		if message := secret.Redact(r.Error.Error()); message != r.Error.Error() {
+			r.Error = errors.New(message)
+		}
+	}
+	for i := range r.Findings {
+		r.Findings[i].Message = secret.Redact(r.Findings[i].Message)
+	}
+}
+
 // NewReportRecord creates a record, and set the begin time to be now
 func NewReportRecord() *ReportRecord {
 	return &ReportRecord{
 		BeginTime: time.Now(),
 	}
 }
+
+// WarmUp returns the key of the warm-up flag
+func (c ContextKey) WarmUp() ContextKey {
+	return ContextKey("warmUp")
+}
+
+// WithWarmUp returns a context which flags the records of the requests as the warm-up
+func WithWarmUp(ctx context.Context) context.Context {
+	return context.WithValue(ctx, NewContextKeyBuilder().WarmUp(), true)
+}
+
+// IsWarmUp returns true if the requests of the context are the warm-up
+func IsWarmUp(ctx context.Context) bool {
+	warmUp, _ := ctx.Value(NewContextKeyBuilder().WarmUp()).(bool)
+	return warmUp
+}
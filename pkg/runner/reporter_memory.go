@@ -2,11 +2,13 @@ package runner
 
 import (
 	"sort"
+	"sync"
 	"time"
 )
 
 type memoryTestReporter struct {
 	records []*ReportRecord
+	lock    sync.RWMutex
 }
 
 // NewMemoryTestReporter creates a memory based test reporter
@@ -22,15 +24,21 @@ type ReportResultWithTotal struct {
 	Total time.Duration
 	First time.Time
 	Last  time.Time
+	// durations are used to calculate the percentiles
+	durations []time.Duration
 }
 
 // PutRecord puts the record to memory
 func (r *memoryTestReporter) PutRecord(record *ReportRecord) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	r.records = append(r.records, record)
 }
 
 // GetAllRecords returns all the records
 func (r *memoryTestReporter) GetAllRecords() []*ReportRecord {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
 	return r.records
 }
 
@@ -45,10 +53,13 @@ func getMaxAndMin(max, min, duration time.Duration) (time.Duration, time.Duratio
 	return max, min
 }
 
-// ExportAllReportResults exports all the report results
+// ExportAllReportResults exports all the report results, the warm-up records are excluded
 func (r *memoryTestReporter) ExportAllReportResults() (result ReportResultSlice, err error) {
 	resultWithTotal := map[string]*ReportResultWithTotal{}
-	for _, record := range r.records {
+	for _, record := range r.GetAllRecords() {
+		if record.WarmUp {
+			continue
+		}
 		api := record.Method + " " + record.API
 		duration := record.Duration()
 
@@ -57,21 +68,35 @@ func (r *memoryTestReporter) ExportAllReportResults() (result ReportResultSlice,
 			item.Error += record.ErrorCount()
 			item.Total += duration
 			item.Count += 1
+			item.durations = append(item.durations, duration)
+			item.Retries += record.Retries
+			item.Timeout += record.TimeoutCount()
+			item.Slow += record.SlowCount()
+			item.Reused += record.ReusedCount()
+			item.Skipped += record.SkippedCount()
 
 			item.Last = getLaterTime(record.EndTime, item.Last)
 			item.LastErrorMessage = getOriginalStringWhenEmpty(item.LastErrorMessage, record.GetErrorMessage())
+			item.Findings = mergeFindings(item.Findings, record.Findings)
 		} else {
 			resultWithTotal[api] = &ReportResultWithTotal{
 				ReportResult: ReportResult{
-					API:   api,
-					Count: 1,
-					Max:   duration,
-					Min:   duration,
-					Error: record.ErrorCount(),
+					API:      api,
+					Count:    1,
+					Max:      duration,
+					Min:      duration,
+					Error:    record.ErrorCount(),
+					Retries:  record.Retries,
+					Timeout:  record.TimeoutCount(),
+					Slow:     record.SlowCount(),
+					Reused:   record.ReusedCount(),
+					Skipped:  record.SkippedCount(),
+					Findings: mergeFindings(nil, record.Findings),
 				},
-				First: record.BeginTime,
-				Last:  record.EndTime,
-				Total: duration,
+				First:     record.BeginTime,
+				Last:      record.EndTime,
+				Total:     duration,
+				durations: []time.Duration{duration},
 			}
 			resultWithTotal[api].LastErrorMessage = record.GetErrorMessage()
 		}
@@ -79,8 +104,15 @@ func (r *memoryTestReporter) ExportAllReportResults() (result ReportResultSlice,
 
 	for _, r := range resultWithTotal {
 		r.Average = r.Total / time.Duration(r.Count)
-		if duration := int(r.Last.Sub(r.First).Seconds()); duration > 0 {
-			r.QPS = r.Count / duration
+		sort.Slice(r.durations, func(i, j int) bool {
+			return r.durations[i] < r.durations[j]
+		})
+		r.P50 = percentile(r.durations, 50)
+		r.P90 = percentile(r.durations, 90)
+		r.P95 = percentile(r.durations, 95)
+		r.P99 = percentile(r.durations, 99)
+		if duration := r.Last.Sub(r.First).Seconds(); duration > 0 {
+			r.QPS = float64(r.Count) / duration
 		}
 		result = append(result, r.ReportResult)
 	}
@@ -89,6 +121,18 @@ func (r *memoryTestReporter) ExportAllReportResults() (result ReportResultSlice,
 	return
 }
 
+// percentile returns the nearest-rank percentile of the sorted durations
+func percentile(durations []time.Duration, p int) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	index := (p*len(durations)+99)/100 - 1
+	if index < 0 {
+		index = 0
+	}
+	return durations[index]
+}
+
 func getLaterTime(a, b time.Time) time.Time {
 	if a.After(b) {
 		return a
@@ -102,3 +146,20 @@ func getOriginalStringWhenEmpty(a, b string) string {
 	}
 	return b
 }
+
+// mergeFindings appends the findings which are not in the existing ones
+func mergeFindings(existing, findings []SecurityFinding) []SecurityFinding {
+	for _, finding := range findings {
+		found := false
+		for _, item := range existing {
+			if item == finding {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, finding)
+		}
+	}
+	return existing
+}
@@ -60,6 +60,11 @@ func TestExportAllReportResults(t *testing.T) {
 			Average: time.Second * 5,
 			Max:     time.Second * 5,
 			Min:     time.Second * 5,
+			P50:     time.Second * 5,
+			P90:     time.Second * 5,
+			P95:     time.Second * 5,
+			P99:     time.Second * 5,
+			QPS:     0.2,
 			Count:   1,
 			Error:   0,
 		}, {
@@ -67,6 +72,11 @@ func TestExportAllReportResults(t *testing.T) {
 			Average:          time.Second * 3,
 			Max:              time.Second * 4,
 			Min:              time.Second * 2,
+			P50:              time.Second * 3,
+			P90:              time.Second * 4,
+			P95:              time.Second * 4,
+			P99:              time.Second * 4,
+			QPS:              0.75,
 			Count:            3,
 			Error:            1,
 			LastErrorMessage: "fake",
@@ -75,6 +85,10 @@ func TestExportAllReportResults(t *testing.T) {
 			Average: time.Second,
 			Max:     time.Second,
 			Min:     time.Second,
+			P50:     time.Second,
+			P90:     time.Second,
+			P95:     time.Second,
+			P99:     time.Second,
 			QPS:     1,
 			Count:   1,
 			Error:   0,
@@ -94,10 +108,75 @@ func TestExportAllReportResults(t *testing.T) {
 			Average:          time.Second * 4,
 			Max:              time.Second * 4,
 			Min:              time.Second * 4,
+			P50:              time.Second * 4,
+			P90:              time.Second * 4,
+			P95:              time.Second * 4,
+			P99:              time.Second * 4,
+			QPS:              0.25,
 			Count:            1,
 			Error:            1,
 			LastErrorMessage: "fake",
 		}},
+	}, {
+		name: "distinct security findings",
+		records: []*runner.ReportRecord{{
+			API:       urlFoo,
+			Method:    http.MethodGet,
+			BeginTime: now,
+			EndTime:   now.Add(time.Second),
+			Findings:  []runner.SecurityFinding{{Check: "csp", Message: "csp"}},
+		}, {
+			API:       urlFoo,
+			Method:    http.MethodGet,
+			BeginTime: now,
+			EndTime:   now.Add(time.Second),
+			Findings:  []runner.SecurityFinding{{Check: "csp", Message: "csp"}, {Check: "hsts", Message: "hsts"}},
+		}},
+		expect: runner.ReportResultSlice{{
+			API:      "GET http://foo",
+			Average:  time.Second,
+			Max:      time.Second,
+			Min:      time.Second,
+			P50:      time.Second,
+			P90:      time.Second,
+			P95:      time.Second,
+			P99:      time.Second,
+			QPS:      2,
+			Count:    2,
+			Findings: []runner.SecurityFinding{{Check: "csp", Message: "csp"}, {Check: "hsts", Message: "hsts"}},
+		}},
+	}, {
+		name: "the warm-up records are excluded",
+		records: []*runner.ReportRecord{{
+			API:       urlFoo,
+			Method:    http.MethodGet,
+			BeginTime: now,
+			EndTime:   now.Add(time.Second * 10),
+			WarmUp:    true,
+		}, {
+			API:       urlFoo,
+			Method:    http.MethodGet,
+			BeginTime: now,
+			EndTime:   now.Add(time.Second),
+		}, {
+			API:       urlBar,
+			Method:    http.MethodGet,
+			BeginTime: now,
+			EndTime:   now.Add(time.Second),
+			WarmUp:    true,
+		}},
+		expect: runner.ReportResultSlice{{
+			API:     "GET http://foo",
+			Average: time.Second,
+			Max:     time.Second,
+			Min:     time.Second,
+			P50:     time.Second,
+			P90:     time.Second,
+			P95:     time.Second,
+			P99:     time.Second,
+			QPS:     1,
+			Count:   1,
+		}},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -115,3 +194,28 @@ func TestExportAllReportResults(t *testing.T) {
 		})
 	}
 }
+
+func TestExportPercentiles(t *testing.T) {
+	now := time.Now()
+	reporter := runner.NewMemoryTestReporter()
+	// put the records in the reverse order, the percentiles should not depend on it
+	for i := 100; i > 0; i-- {
+		reporter.PutRecord(&runner.ReportRecord{
+			API:       urlFoo,
+			Method:    http.MethodGet,
+			BeginTime: now,
+			EndTime:   now.Add(time.Duration(i) * time.Millisecond),
+		})
+	}
+
+	result, err := reporter.ExportAllReportResults()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(result)) {
+		assert.Equal(t, 50*time.Millisecond, result[0].P50)
+		assert.Equal(t, 90*time.Millisecond, result[0].P90)
+		assert.Equal(t, 95*time.Millisecond, result[0].P95)
+		assert.Equal(t, 99*time.Millisecond, result[0].P99)
+		assert.Equal(t, 100*time.Millisecond, result[0].Max)
+		assert.Equal(t, float64(1000), result[0].QPS)
+	}
+}
@@ -0,0 +1,163 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MetricsTestReporter is a TestReporter which exposes the Prometheus metrics of the records
+type MetricsTestReporter interface {
+	TestReporter
+	// ServeHTTP writes the metrics in the Prometheus text format
+	http.Handler
+	// Push replaces the metrics of the job in the Pushgateway
+	Push(gateway, job string) error
+}
+
+// durationBuckets are the upper bounds of the latency histogram in seconds
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type apiSeries struct {
+	method string
+	api    string
+}
+
+type apiMetrics struct {
+	requests int
+	errors   int
+	buckets  []int
+	sum      float64
+}
+
+type prometheusTestReporter struct {
+	TestReporter
+	lock   sync.RWMutex
+	series map[apiSeries]*apiMetrics
+}
+
+// NewPrometheusTestReporter creates a reporter which puts the records into the reporter, and counts the requests,
+// the errors and the latency of them by the method and the API. The query of the API is ignored
+func NewPrometheusTestReporter(reporter TestReporter) MetricsTestReporter {
+	return &prometheusTestReporter{
+		TestReporter: reporter,
+		series:       map[apiSeries]*apiMetrics{},
+	}
+}
+
+// PutRecord puts the record into the reporter, then counts it
+func (r *prometheusTestReporter) PutRecord(record *ReportRecord) {
+	r.TestReporter.PutRecord(record)
+
+	key := apiSeries{method: record.Method, api: record.API}
+	if index := strings.Index(key.api, "?"); index >= 0 {
+		key.api = key.api[:index]
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	metrics, ok := r.series[key]
+	if !ok {
+		metrics = &apiMetrics{buckets: make([]int, len(durationBuckets))}
+		r.series[key] = metrics
+	}
+	metrics.requests++
+	metrics.errors += record.ErrorCount()
+
+	seconds := record.Duration().Seconds()
+	metrics.sum += seconds
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			metrics.buckets[i]++
+		}
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (r *prometheusTestReporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.writeMetrics(w)
+}
+
+// Push replaces the metrics of the job in the Pushgateway
+func (r *prometheusTestReporter) Push(gateway, job string) (err error) {
+	buf := new(bytes.Buffer)
+	r.writeMetrics(buf)
+
+	api := fmt.Sprintf("%s/metrics/job/%s", strings.TrimSuffix(gateway, "/"), url.PathEscape(job))
+	var req *http.Request
+	if req, err = http.NewRequest(http.MethodPut, api, buf); err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	var resp *http.Response
+	if resp, err = http.DefaultClient.Do(req); err != nil {
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		data, _ := io.ReadAll(resp.Body)
+		err = fmt.Errorf("unexpected status code %d from %s, %s", resp.StatusCode, api, string(data))
+	}
+	return
+}
+
+func (r *prometheusTestReporter) writeMetrics(w io.Writer) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	keys := make([]apiSeries, 0, len(r.series))
+	for key := range r.series {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].api == keys[j].api {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].api < keys[j].api
+	})
+
+	fmt.Fprintln(w, "# HELP atest_requests_total The count of the requests.")
+	fmt.Fprintln(w, "# TYPE atest_requests_total counter")
+	for _, key := range keys {
+		fmt.Fprintf(w, "atest_requests_total{%s} %d\n", key.labels(), r.series[key].requests)
+	}
+
+	fmt.Fprintln(w, "# HELP atest_request_errors_total The count of the failed requests.")
+	fmt.Fprintln(w, "# TYPE atest_request_errors_total counter")
+	for _, key := range keys {
+		fmt.Fprintf(w, "atest_request_errors_total{%s} %d\n", key.labels(), r.series[key].errors)
+	}
+
+	fmt.Fprintln(w, "# HELP atest_request_duration_seconds The latency of the requests.")
+	fmt.Fprintln(w, "# TYPE atest_request_duration_seconds histogram")
+	for _, key := range keys {
+		metrics, labels := r.series[key], key.labels()
+		for i, bound := range durationBuckets {
+			fmt.Fprintf(w, "atest_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels,
+				strconv.FormatFloat(bound, 'g', -1, 64), metrics.buckets[i])
+		}
+		fmt.Fprintf(w, "atest_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, metrics.requests)
+		fmt.Fprintf(w, "atest_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(metrics.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "atest_request_duration_seconds_count{%s} %d\n", labels, metrics.requests)
+	}
+}
+
+func (s apiSeries) labels() string {
+	return fmt.Sprintf("method=%s,api=%s", quoteLabel(s.method), quoteLabel(s.api))
+}
+
+// quoteLabel escapes the backslash, the double quote and the line feed of the label value
+func quoteLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
@@ -0,0 +1,90 @@
+package runner_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusTestReporter(t *testing.T) {
+	now := time.Now()
+	memory := runner.NewMemoryTestReporter()
+	reporter := runner.NewPrometheusTestReporter(memory)
+	reporter.PutRecord(&runner.ReportRecord{API: urlFoo + "?id=1", Method: http.MethodGet, BeginTime: now, EndTime: now.Add(20 * time.Millisecond)})
+	reporter.PutRecord(&runner.ReportRecord{API: urlFoo, Method: http.MethodGet, BeginTime: now, EndTime: now.Add(3 * time.Second),
+		Error: errors.New("fake")})
+	reporter.PutRecord(&runner.ReportRecord{API: urlBar, Method: http.MethodPost, BeginTime: now, EndTime: now.Add(time.Minute)})
+	assert.Equal(t, 3, len(memory.GetAllRecords()))
+
+	recorder := httptest.NewRecorder()
+	reporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP atest_requests_total The count of the requests.
+# TYPE atest_requests_total counter
+atest_requests_total{method="POST",api="http://bar"} 1
+atest_requests_total{method="GET",api="http://foo"} 2
+# HELP atest_request_errors_total The count of the failed requests.
+# TYPE atest_request_errors_total counter
+atest_request_errors_total{method="POST",api="http://bar"} 0
+atest_request_errors_total{method="GET",api="http://foo"} 1
+# HELP atest_request_duration_seconds The latency of the requests.
+# TYPE atest_request_duration_seconds histogram
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="0.005"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="0.01"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="0.025"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="0.05"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="0.1"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="0.25"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="0.5"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="1"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="2.5"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="5"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="10"} 0
+atest_request_duration_seconds_bucket{method="POST",api="http://bar",le="+Inf"} 1
+atest_request_duration_seconds_sum{method="POST",api="http://bar"} 60
+atest_request_duration_seconds_count{method="POST",api="http://bar"} 1
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="0.005"} 0
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="0.01"} 0
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="0.025"} 1
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="0.05"} 1
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="0.1"} 1
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="0.25"} 1
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="0.5"} 1
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="1"} 1
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="2.5"} 1
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="5"} 2
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="10"} 2
+atest_request_duration_seconds_bucket{method="GET",api="http://foo",le="+Inf"} 2
+atest_request_duration_seconds_sum{method="GET",api="http://foo"} 3.02
+atest_request_duration_seconds_count{method="GET",api="http://foo"} 2
+`, recorder.Body.String())
+
+	t.Run("push", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFake).Put("/metrics/job/nightly").
+			AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+				data, err := io.ReadAll(req.Body)
+				return string(data) == recorder.Body.String(), err
+			}).Reply(http.StatusOK)
+		assert.Nil(t, reporter.Push(urlFake+"/", "nightly"))
+
+		gock.New(urlFake).Put("/metrics/job/nightly").Reply(http.StatusBadRequest).BodyString("invalid")
+		assert.ErrorContains(t, reporter.Push(urlFake, "nightly"), "unexpected status code 400")
+	})
+}
+
+func TestQuoteLabel(t *testing.T) {
+	reporter := runner.NewPrometheusTestReporter(runner.NewDiscardTestReporter())
+	reporter.PutRecord(&runner.ReportRecord{API: "step \"a\"\\b\nc"})
+
+	recorder := httptest.NewRecorder()
+	reporter.ServeHTTP(recorder, nil)
+	assert.Contains(t, recorder.Body.String(), `atest_requests_total{method="",api="step \"a\"\\b\nc"} 1`)
+}
@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type streamTestReporter struct {
+	TestReporter
+	writer io.Writer
+	format func(record *ReportRecord, passed, failed int) string
+	lock   sync.Mutex
+	passed int
+	failed int
+}
+
+// NewProgressTestReporter creates a reporter which puts the records into the reporter,
+// and writes a progress line of each record once it's completed
+func NewProgressTestReporter(reporter TestReporter, writer io.Writer) TestReporter {
+	return &streamTestReporter{
+		TestReporter: reporter,
+		writer:       writer,
+		format:       progressLine,
+	}
+}
+
+// NewNDJSONTestReporter creates a reporter which puts the records into the reporter,
+// and writes an event of each record in the newline delimited JSON once it's completed
+func NewNDJSONTestReporter(reporter TestReporter, writer io.Writer) TestReporter {
+	return &streamTestReporter{
+		TestReporter: reporter,
+		writer:       writer,
+		format:       ndjsonEvent,
+	}
+}
+
+// PutRecord puts the record into the reporter, then writes it
+func (r *streamTestReporter) PutRecord(record *ReportRecord) {
+	r.TestReporter.PutRecord(record)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if record.Error == nil {
+		r.passed++
+	} else {
+		r.failed++
+	}
+	fmt.Fprintln(r.writer, r.format(record, r.passed, r.failed))
+}
+
+func progressLine(record *ReportRecord, passed, failed int) string {
+	status := "PASS"
+	if record.Error != nil {
+		status = "FAIL"
+	} else if record.Skipped {
+		status = "SKIP"
+	}
+	line := fmt.Sprintf("[%d passed, %d failed] %s %s %s %v", passed, failed, status, record.Method, record.API,
+		record.Duration().Round(time.Millisecond))
+	if record.Name != "" {
+		line = fmt.Sprintf("%s (%s)", line, record.Name)
+	}
+	if record.Error != nil {
+		line = fmt.Sprintf("%s: %v", line, record.Error)
+	}
+	return line
+}
+
+// streamEvent is the event of a completed record in the NDJSON stream
+type streamEvent struct {
+	Time     string `json:"time"`
+	Name     string `json:"name,omitempty"`
+	Method   string `json:"method"`
+	API      string `json:"api"`
+	Protocol string `json:"protocol,omitempty"`
+	Duration int64  `json:"durationMs"`
+	Passed   bool   `json:"passed"`
+	Error    string `json:"error,omitempty"`
+	Retries  int    `json:"retries,omitempty"`
+	Timeout  bool   `json:"timeout,omitempty"`
+	Slow     bool   `json:"slow,omitempty"`
+	Reused   bool   `json:"reused,omitempty"`
+	Skipped  bool   `json:"skipped,omitempty"`
+	Total    int    `json:"total"`
+	Failed   int    `json:"failed"`
+}
+
+func ndjsonEvent(record *ReportRecord, passed, failed int) string {
+	event := streamEvent{
+		Time:     record.EndTime.UTC().Format(time.RFC3339Nano),
+		Name:     record.Name,
+		Method:   record.Method,
+		API:      record.API,
+		Protocol: record.Protocol,
+		Duration: record.Duration().Milliseconds(),
+		Passed:   record.Error == nil,
+		Retries:  record.Retries,
+		Timeout:  record.Timeout,
+		Slow:     record.Slow,
+		Reused:   record.Reused,
+		Skipped:  record.Skipped,
+		Total:    passed + failed,
+		Failed:   failed,
+	}
+	if record.Error != nil {
+		event.Error = record.Error.Error()
+	}
+
+	data, _ := json.Marshal(event)
+	return string(data)
+}
@@ -0,0 +1,57 @@
+package runner_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamTestReporter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []*runner.ReportRecord{{
+		Name:      "foo",
+		Method:    http.MethodGet,
+		API:       urlFoo,
+		BeginTime: now,
+		EndTime:   now.Add(120 * time.Millisecond),
+	}, {
+		Method:    http.MethodPost,
+		API:       urlBar,
+		BeginTime: now,
+		EndTime:   now.Add(time.Second),
+		Error:     errors.New("fake"),
+		Retries:   2,
+	}}
+
+	t.Run("progress", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		memory := runner.NewMemoryTestReporter()
+		reporter := runner.NewProgressTestReporter(memory, buf)
+		for _, record := range records {
+			reporter.PutRecord(record)
+		}
+
+		assert.Equal(t, records, memory.GetAllRecords())
+		assert.Equal(t, `[1 passed, 0 failed] PASS GET http://foo 120ms (foo)
+[1 passed, 1 failed] FAIL POST http://bar 1s: fake
+`, buf.String())
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		reporter := runner.NewNDJSONTestReporter(runner.NewMemoryTestReporter(), buf)
+		for _, record := range records {
+			reporter.PutRecord(record)
+		}
+
+		assert.Equal(t, len(records), len(reporter.GetAllRecords()))
+		assert.Equal(t, `{"time":"2023-01-01T00:00:00.12Z","name":"foo","method":"GET","api":"http://foo","durationMs":120,"passed":true,"total":1,"failed":0}
+{"time":"2023-01-01T00:00:01Z","method":"POST","api":"http://bar","durationMs":1000,"passed":false,"error":"fake","retries":2,"total":2,"failed":1}
+`, buf.String())
+	})
+}
@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// newResolveDial returns the dial function which connects the pinned address of the host and port, like the --resolve of curl.
+// The entry is host:port:address, such as: api.example.com:443:10.0.0.8. The TLS server name is still the host of the API
+func newResolveDial(entries []string, dial dialFunc) (resolveDial dialFunc, err error) {
+	addresses := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		var host, port, address string
+		if host, port, address, err = parseResolve(entry); err != nil {
+			return
+		}
+		addresses[net.JoinHostPort(host, port)] = address
+	}
+
+	if dial == nil {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+		dial = dialer.DialContext
+	}
+	resolveDial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(address); err == nil {
+			if ip, ok := addresses[net.JoinHostPort(strings.ToLower(host), port)]; ok {
+				address = net.JoinHostPort(ip, port)
+			}
+		}
+		return dial(ctx, network, address)
+	}
+	return
+}
+
+// parseResolve parses the entry of host:port:address, the IPv6 address could be in the brackets
+func parseResolve(entry string) (host, port, address string, err error) {
+	items := strings.SplitN(entry, ":", 3)
+	if len(items) != 3 || items[0] == "" {
+		err = fmt.Errorf("invalid resolve entry '%s', it should be host:port:address", entry)
+		return
+	}
+
+	host, port, address = strings.ToLower(items[0]), items[1], strings.TrimSuffix(strings.TrimPrefix(items[2], "["), "]")
+	if _, err = net.LookupPort("tcp", port); err != nil {
+		err = fmt.Errorf("invalid port of resolve entry '%s': %v", entry, err)
+	} else if net.ParseIP(address) == nil {
+		err = fmt.Errorf("invalid address of resolve entry '%s'", entry)
+	}
+	return
+}
@@ -0,0 +1,85 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResolve(t *testing.T) {
+	tests := []struct {
+		entry   string
+		host    string
+		port    string
+		address string
+		err     string
+	}{{
+		entry:   "API.example.com:443:10.0.0.8",
+		host:    "api.example.com",
+		port:    "443",
+		address: "10.0.0.8",
+	}, {
+		entry:   "api.example.com:8080:[2001:db8::8]",
+		host:    "api.example.com",
+		port:    "8080",
+		address: "2001:db8::8",
+	}, {
+		entry: "api.example.com:10.0.0.8",
+		err:   "it should be host:port:address",
+	}, {
+		entry: "api.example.com:port:10.0.0.8",
+		err:   "invalid port",
+	}, {
+		entry: "api.example.com:443:canary",
+		err:   "invalid address",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			host, port, address, err := parseResolve(tt.entry)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.host, host)
+			assert.Equal(t, tt.port, port)
+			assert.Equal(t, tt.address, address)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	// the server responds the host and the TLS server name of the request
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"host":"%s","serverName":"%s"}`, r.Host, r.TLS.ServerName)
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	port := serverURL.Port()
+	host := "canary.example.com:" + port
+
+	_, err = NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{
+			API:     "https://" + host + "/users",
+			Resolve: []string{"canary.example.com:" + port + ":127.0.0.1"},
+		},
+		Expect: atest.Response{BodyFieldsExpect: map[string]interface{}{
+			"host":       host,
+			"serverName": "canary.example.com",
+		}},
+	}, nil, context.TODO())
+	assert.NoError(t, err)
+
+	_, err = NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: server.URL, Resolve: []string{"canary.example.com"}},
+	}, nil, context.TODO())
+	assert.ErrorContains(t, err, "invalid resolve entry")
+}
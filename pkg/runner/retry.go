@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const defaultRetryBackoff = time.Second
+
+var defaultRetryOnStatus = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// doRequestWithRetry sends the request, then sends it again if it's failed to send or the status code should be retried.
+// The count of the retries is kept in the record.
+func (r *simpleTestCaseRunner) doRequestWithRetry(ctx context.Context, request *http.Request, testcase *testing.TestCase,
+	record *ReportRecord) (resp *http.Response, body []byte, err error) {
+	retry := testcase.Retry
+	if retry == nil || retry.MaxAttempts <= 1 {
+		return r.doTimedRequest(request, &testcase.Request, record)
+	}
+
+	var backoff time.Duration
+	if backoff, err = parseDurationOrDefault(retry.Backoff, defaultRetryBackoff); err != nil {
+		err = fmt.Errorf("invalid backoff of the retry: %v", err)
+		return
+	}
+	onStatus := retry.OnStatus
+	if len(onStatus) == 0 {
+		onStatus = defaultRetryOnStatus
+	}
+
+	for attempt := 1; ; attempt++ {
+		var reason string
+		if resp, body, err = r.doTimedRequest(request, &testcase.Request, record); err != nil {
+			reason = err.Error()
+		} else if expectStatus(onStatus, resp.StatusCode) {
+			reason = fmt.Sprintf("status code %d", resp.StatusCode)
+		} else {
+			return
+		}
+
+		if attempt >= retry.MaxAttempts {
+			return
+		}
+		r.log.Warn("retry %s after %v, attempt %d of %d is failed: %s\n", request.URL, backoff, attempt, retry.MaxAttempts, reason)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		// the body of the request is read by the previous attempt
+		if request.GetBody != nil {
+			if request.Body, err = request.GetBody(); err != nil {
+				return
+			}
+		}
+		record.Retries++
+	}
+}
+
+// doTimedRequest sends the request, and keeps the response time in the record
+func (r *simpleTestCaseRunner) doTimedRequest(request *http.Request, req *testing.Request,
+	record *ReportRecord) (resp *http.Response, body []byte, err error) {
+	// the waiting for the rate limit is not a part of the response time
+	waitRateLimit(request.Context())
+	record.Reused = false
+	requestCtx, cancel := RequestContext(request.Context())
+	defer cancel()
+	request = request.WithContext(httptrace.WithClientTrace(requestCtx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			record.Reused = info.Reused
+		},
+	}))
+	begin := time.Now()
+	resp, body, err = doRequest(request, req)
+	record.ResponseTime = time.Since(begin)
+	if resp != nil && resp.Proto != "" {
+		record.Protocol = resp.Proto
+		r.log.Debug("the negotiated protocol of %s is %s\n", request.URL, resp.Proto)
+	}
+	return
+}
+
+func expectStatus(expected []int, statusCode int) bool {
+	for _, code := range expected {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   *atest.Retry
+		prepare func()
+		retries int
+		err     string
+	}{{
+		name:  "retried until success",
+		retry: &atest.Retry{MaxAttempts: 3, Backoff: "1ms"},
+		prepare: func() {
+			gock.New(urlLocalhost).Post("/foo").BodyString(`{"name":"foo"}`).Reply(http.StatusBadGateway)
+			gock.New(urlLocalhost).Post("/foo").BodyString(`{"name":"foo"}`).Reply(http.StatusServiceUnavailable)
+			gock.New(urlLocalhost).Post("/foo").BodyString(`{"name":"foo"}`).Reply(http.StatusOK).BodyString(`{}`)
+		},
+		retries: 2,
+	}, {
+		name:  "attempts are exhausted",
+		retry: &atest.Retry{MaxAttempts: 2, Backoff: "1ms"},
+		prepare: func() {
+			gock.New(urlLocalhost).Post("/foo").Times(2).Reply(http.StatusBadGateway)
+		},
+		retries: 1,
+		err:     "expect 200, actual 502",
+	}, {
+		name:  "status code is not retried",
+		retry: &atest.Retry{MaxAttempts: 3, Backoff: "1ms", OnStatus: []int{http.StatusTooManyRequests}},
+		prepare: func() {
+			gock.New(urlLocalhost).Post("/foo").Reply(http.StatusBadGateway)
+		},
+		err: "expect 200, actual 502",
+	}, {
+		name:  "no retry",
+		retry: nil,
+		prepare: func() {
+			gock.New(urlLocalhost).Post("/foo").Reply(http.StatusOK).BodyString(`{}`)
+		},
+	}, {
+		name:    "invalid backoff",
+		retry:   &atest.Retry{MaxAttempts: 2, Backoff: "fake"},
+		prepare: func() {},
+		err:     "invalid backoff of the retry",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			tt.prepare()
+
+			buf := new(bytes.Buffer)
+			reporter := NewMemoryTestReporter()
+			_, err := NewSimpleTestCaseRunner().WithOutputWriter(buf).WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+				Request: atest.Request{
+					API:    urlFoo,
+					Method: http.MethodPost,
+					Body:   `{"name":"foo"}`,
+				},
+				Retry: tt.retry,
+			}, nil, context.TODO())
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+			assert.True(t, gock.IsDone())
+
+			if records := reporter.GetAllRecords(); assert.Equal(t, 1, len(records)) {
+				assert.Equal(t, tt.retries, records[0].Retries)
+			}
+			if tt.retries > 0 {
+				assert.Contains(t, buf.String(), "attempt 1 of")
+			}
+		})
+	}
+}
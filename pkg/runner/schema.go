@@ -0,0 +1,130 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const draft07 = "http://json-schema.org/draft-07/schema#"
+
+// loadSchema returns the inline schema as it is, or reads the file which is relative to the directory of the test suite
+func loadSchema(contextDir, schema string) (result string, err error) {
+	result = schema
+	if trimmed := strings.TrimSpace(schema); trimmed == "" || strings.HasPrefix(trimmed, "{") {
+		return
+	}
+
+	var data []byte
+	if data, err = os.ReadFile(resolvePath(contextDir, schema)); err != nil {
+		err = fmt.Errorf("failed to read the JSON schema: %v", err)
+		return
+	}
+	result = string(data)
+	return
+}
+
+func jsonSchemaValidation(schema string, body []byte) (err error) {
+	if schema == "" {
+		return
+	}
+
+	var doc interface{}
+	if err = json.Unmarshal([]byte(schema), &doc); err != nil {
+		err = fmt.Errorf("invalid JSON schema: %v", err)
+		return
+	}
+	if root, ok := doc.(map[string]interface{}); ok && isDraft2020(root["$schema"]) {
+		root["$schema"] = draft07
+		convertSchema(root)
+	}
+
+	schemaLoader := gojsonschema.NewGoLoader(doc)
+	jsonLoader := gojsonschema.NewBytesLoader(body)
+
+	var result *gojsonschema.Result
+	if result, err = gojsonschema.Validate(schemaLoader, jsonLoader); err == nil && !result.Valid() {
+		err = fmt.Errorf("JSON schema validation failed: %v", result.Errors())
+	}
+	return
+}
+
+// isDraft2020 checks if the schema is the draft 2020-12 or 2019-09, both of them are converted to the draft-07
+func isDraft2020(schema interface{}) bool {
+	uri, _ := schema.(string)
+	return strings.Contains(uri, "/draft/2020-12/") || strings.Contains(uri, "/draft/2019-09/")
+}
+
+// schemaMaps are the keywords whose values are the maps of the subschemas
+var schemaMaps = map[string]bool{
+	"properties":        true,
+	"patternProperties": true,
+	"$defs":             true,
+	"definitions":       true,
+	"dependentSchemas":  true,
+}
+
+// schemaValues are the keywords whose values are the data instead of the subschemas
+var schemaValues = map[string]bool{
+	"const":    true,
+	"enum":     true,
+	"default":  true,
+	"examples": true,
+}
+
+// convertSchema rewrites the keywords of the draft 2020-12 to the draft-07 ones in place.
+// The keywords without an equivalent, such as unevaluatedProperties, are ignored by the validation.
+func convertSchema(schema map[string]interface{}) {
+	for key, val := range schema {
+		if schemaValues[key] {
+			continue
+		}
+		switch sub := val.(type) {
+		case map[string]interface{}:
+			if schemaMaps[key] {
+				for _, item := range sub {
+					convertSubschema(item)
+				}
+			} else {
+				convertSchema(sub)
+			}
+		case []interface{}:
+			for _, item := range sub {
+				convertSubschema(item)
+			}
+		}
+	}
+
+	if prefixItems, ok := schema["prefixItems"]; ok {
+		if items, ok := schema["items"]; ok {
+			schema["additionalItems"] = items
+		}
+		schema["items"] = prefixItems
+		delete(schema, "prefixItems")
+	}
+
+	dependencies, _ := schema["dependencies"].(map[string]interface{})
+	for _, key := range []string{"dependentRequired", "dependentSchemas"} {
+		if val, ok := schema[key].(map[string]interface{}); ok {
+			if dependencies == nil {
+				dependencies = map[string]interface{}{}
+			}
+			for name, dependency := range val {
+				dependencies[name] = dependency
+			}
+			delete(schema, key)
+		}
+	}
+	if dependencies != nil {
+		schema["dependencies"] = dependencies
+	}
+}
+
+func convertSubschema(schema interface{}) {
+	if sub, ok := schema.(map[string]interface{}); ok {
+		convertSchema(sub)
+	}
+}
@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSchema(t *testing.T) {
+	schema, err := loadSchema("testdata", "")
+	assert.Nil(t, err)
+	assert.Empty(t, schema)
+
+	schema, err = loadSchema("testdata", defaultSchemaForTest)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultSchemaForTest, schema)
+
+	schema, err = loadSchema("testdata", "schema.json")
+	assert.Nil(t, err)
+	assert.Contains(t, schema, `"required": ["name"]`)
+
+	_, err = loadSchema("testdata", "fake.json")
+	assert.ErrorContains(t, err, "failed to read the JSON schema")
+}
+
+func TestConvertSchema(t *testing.T) {
+	schema := map[string]interface{}{
+		"prefixItems":       []interface{}{map[string]interface{}{"type": "string"}},
+		"items":             false,
+		"dependentRequired": map[string]interface{}{"a": []interface{}{"b"}},
+		"dependentSchemas":  map[string]interface{}{"c": map[string]interface{}{"required": []interface{}{"d"}}},
+		"const":             map[string]interface{}{"prefixItems": true},
+		"properties": map[string]interface{}{
+			"prefixItems": map[string]interface{}{"prefixItems": []interface{}{}},
+		},
+	}
+	convertSchema(schema)
+	assert.Equal(t, map[string]interface{}{
+		"items":           []interface{}{map[string]interface{}{"type": "string"}},
+		"additionalItems": false,
+		"dependencies": map[string]interface{}{
+			"a": []interface{}{"b"},
+			"c": map[string]interface{}{"required": []interface{}{"d"}},
+		},
+		"const": map[string]interface{}{"prefixItems": true},
+		"properties": map[string]interface{}{
+			"prefixItems": map[string]interface{}{"items": []interface{}{}},
+		},
+	}, schema)
+}
+
+func TestSchemaFromFile(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlLocalhost).Get("/foo").Reply(http.StatusOK).BodyString(`{"age": 1}`)
+
+	ctx := context.WithValue(context.TODO(), NewContextKeyBuilder().ParentDir(), "testdata")
+	_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: urlFoo},
+		Expect:  atest.Response{Schema: "schema.json"},
+	}, nil, ctx)
+	assert.ErrorContains(t, err, "JSON schema validation failed")
+
+	_, err = NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: urlFoo},
+		Expect:  atest.Response{Schema: "fake.json"},
+	}, nil, ctx)
+	assert.ErrorContains(t, err, "failed to read the JSON schema")
+}
+
+const schema2020ForTest = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"tags": {
+			"type": "array",
+			"prefixItems": [{"type": "string"}, {"type": "integer"}],
+			"items": false
+		},
+		"owner": {"$ref": "#/$defs/owner"}
+	},
+	"$defs": {
+		"owner": {
+			"type": "object",
+			"properties": {"name": {"type": "string"}},
+			"dependentRequired": {"email": ["phone"]}
+		}
+	}
+}`
@@ -0,0 +1,153 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// SecurityFinding is a problem of the response hygiene which is found by a security check
+type SecurityFinding struct {
+	Check   string
+	Message string
+}
+
+// securityCheck returns the message of the finding, or empty if the response is fine
+type securityCheck func(*securityTarget) string
+
+// securityTarget is the request and the response which are checked
+type securityTarget struct {
+	request *http.Request
+	body    string
+	resp    *http.Response
+	data    string
+	markers []string
+}
+
+var securityChecks = map[string]securityCheck{
+	"hsts":               checkHSTS,
+	"csp":                checkCSP,
+	"contentTypeOptions": checkContentTypeOptions,
+	"stackTrace":         checkStackTrace,
+	"reflection":         checkReflection,
+}
+
+// securityCheckOrder keeps the findings in a stable order
+var securityCheckOrder = []string{"hsts", "csp", "contentTypeOptions", "stackTrace", "reflection"}
+
+// defaultSecurityMarkers are the common payloads of the cross-site scripting
+var defaultSecurityMarkers = []string{"<script", "javascript:", "onerror=", "onload="}
+
+var stackTracePatterns = []*regexp.Regexp{
+	// Java
+	regexp.MustCompile(`\bat [\w$.]+\([\w$]+\.java:\d+\)`),
+	// Python
+	regexp.MustCompile(`Traceback \(most recent call last\)`),
+	// Go
+	regexp.MustCompile(`goroutine \d+ \[[\w ]+\]:`),
+	// Node.js
+	regexp.MustCompile(`\bat .+\(.+\.[cm]?js:\d+:\d+\)`),
+	// .NET
+	regexp.MustCompile(`\bat .+ in .+\.cs:line \d+`),
+	// PHP and Ruby
+	regexp.MustCompile(`(?i)stack trace:\s*\n\s*#0 |\.rb:\d+:in `),
+}
+
+// checkSecurity runs the checks of the response, the findings are logged as warnings
+func (r *simpleTestCaseRunner) checkSecurity(security *testing.Security, request *http.Request, body string,
+	resp *http.Response, data []byte) (findings []SecurityFinding, err error) {
+	if security == nil {
+		return
+	}
+
+	names := security.Checks
+	if len(names) == 0 {
+		names = securityCheckOrder
+	}
+	for _, name := range names {
+		if _, ok := securityChecks[name]; !ok {
+			err = fmt.Errorf("unknown security check: %s", name)
+			return
+		}
+	}
+
+	target := &securityTarget{
+		request: request,
+		body:    body,
+		resp:    resp,
+		data:    string(data),
+		markers: security.Markers,
+	}
+	if len(target.markers) == 0 {
+		target.markers = defaultSecurityMarkers
+	}
+	for _, name := range names {
+		if message := securityChecks[name](target); message != "" {
+			findings = append(findings, SecurityFinding{Check: name, Message: message})
+			r.log.Warn("security: [%s] %s\n", name, message)
+		}
+	}
+	return
+}
+
+func checkHSTS(target *securityTarget) string {
+	if target.request.URL.Scheme != "https" {
+		return ""
+	}
+	if header := target.resp.Header.Get("Strict-Transport-Security"); !strings.Contains(strings.ToLower(header), "max-age=") {
+		return "missing the header Strict-Transport-Security"
+	}
+	return ""
+}
+
+func checkCSP(target *securityTarget) string {
+	if target.resp.Header.Get("Content-Security-Policy") == "" {
+		return "missing the header Content-Security-Policy"
+	}
+	return ""
+}
+
+func checkContentTypeOptions(target *securityTarget) string {
+	if header := target.resp.Header.Get("X-Content-Type-Options"); !strings.EqualFold(header, "nosniff") {
+		return "the header X-Content-Type-Options is not nosniff"
+	}
+	return ""
+}
+
+// checkStackTrace looks for the stack traces in the bodies of the error responses
+func checkStackTrace(target *securityTarget) string {
+	if target.resp.StatusCode < http.StatusBadRequest {
+		return ""
+	}
+	for _, pattern := range stackTracePatterns {
+		if found := pattern.FindString(target.data); found != "" {
+			return fmt.Sprintf("the error body contains a stack trace: %s", strings.TrimSpace(found))
+		}
+	}
+	return ""
+}
+
+// checkReflection looks for the markers which are sent by the request and returned by the response as they are
+func checkReflection(target *securityTarget) string {
+	sent := target.body + "\n" + target.request.URL.String()
+	if query, err := url.QueryUnescape(target.request.URL.RawQuery); err == nil {
+		sent += "\n" + query
+	}
+	for _, values := range target.request.Header {
+		sent += "\n" + strings.Join(values, "\n")
+	}
+	sent = strings.ToLower(sent)
+	data := strings.ToLower(target.data)
+
+	for _, marker := range target.markers {
+		marker = strings.ToLower(marker)
+		if marker != "" && strings.Contains(sent, marker) && strings.Contains(data, marker) {
+			return fmt.Sprintf("the payload marker %q is reflected", marker)
+		}
+	}
+	return ""
+}
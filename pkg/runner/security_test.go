@@ -0,0 +1,140 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSecurity(t *testing.T) {
+	tests := []struct {
+		name     string
+		security *atest.Security
+		url      string
+		body     string
+		header   http.Header
+		status   int
+		data     string
+		expect   []SecurityFinding
+		err      string
+	}{{
+		name: "not enabled",
+		url:  "https://foo",
+	}, {
+		name:     "all the checks are passed",
+		security: &atest.Security{},
+		url:      "https://foo",
+		header: http.Header{
+			"Strict-Transport-Security": []string{"max-age=31536000"},
+			"Content-Security-Policy":   []string{"default-src 'self'"},
+			"X-Content-Type-Options":    []string{"nosniff"},
+		},
+		status: http.StatusOK,
+	}, {
+		name:     "missing headers",
+		security: &atest.Security{},
+		url:      "https://foo",
+		header:   http.Header{"X-Content-Type-Options": []string{"sniff"}},
+		status:   http.StatusOK,
+		expect: []SecurityFinding{
+			{Check: "hsts", Message: "missing the header Strict-Transport-Security"},
+			{Check: "csp", Message: "missing the header Content-Security-Policy"},
+			{Check: "contentTypeOptions", Message: "the header X-Content-Type-Options is not nosniff"},
+		},
+	}, {
+		name:     "HSTS is for HTTPS only",
+		security: &atest.Security{Checks: []string{"hsts"}},
+		url:      "http://foo",
+		status:   http.StatusOK,
+	}, {
+		name:     "stack trace in the error body",
+		security: &atest.Security{Checks: []string{"stackTrace"}},
+		url:      "http://foo",
+		status:   http.StatusInternalServerError,
+		data:     "java.lang.NullPointerException\n\tat com.example.Foo.bar(Foo.java:12)",
+		expect: []SecurityFinding{{
+			Check:   "stackTrace",
+			Message: "the error body contains a stack trace: at com.example.Foo.bar(Foo.java:12)",
+		}},
+	}, {
+		name:     "stack trace in the successful body",
+		security: &atest.Security{Checks: []string{"stackTrace"}},
+		url:      "http://foo",
+		status:   http.StatusOK,
+		data:     "Traceback (most recent call last)",
+	}, {
+		name:     "reflected marker",
+		security: &atest.Security{Checks: []string{"reflection"}},
+		url:      "http://foo?name=%3Cscript%3E",
+		status:   http.StatusOK,
+		data:     "hello <SCRIPT>",
+		expect: []SecurityFinding{{
+			Check:   "reflection",
+			Message: `the payload marker "<script" is reflected`,
+		}},
+	}, {
+		name:     "custom marker which is not sent",
+		security: &atest.Security{Checks: []string{"reflection"}, Markers: []string{"atest-marker"}},
+		url:      "http://foo",
+		body:     `{"name":"<script>"}`,
+		status:   http.StatusOK,
+		data:     "<script> atest-marker",
+	}, {
+		name:     "unknown check",
+		security: &atest.Security{Checks: []string{"fake"}},
+		url:      "http://foo",
+		err:      "unknown security check: fake",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			assert.Nil(t, err)
+			resp := &http.Response{StatusCode: tt.status, Header: tt.header}
+			if resp.Header == nil {
+				resp.Header = http.Header{}
+			}
+
+			runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+			findings, err := runner.checkSecurity(tt.security, request, tt.body, resp, []byte(tt.data))
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+			assert.Equal(t, tt.expect, findings)
+		})
+	}
+}
+
+func TestSecurityFindingsInReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	buf := new(bytes.Buffer)
+	reporter := NewMemoryTestReporter()
+	runner := NewSimpleTestCaseRunner().WithTestReporter(reporter).WithOutputWriter(buf)
+	_, err := runner.RunTestCase(&atest.TestCase{
+		Request:  atest.Request{API: server.URL},
+		Security: &atest.Security{},
+	}, nil, context.TODO())
+	// the findings don't fail the test case
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "security: [contentTypeOptions]")
+
+	results, err := reporter.ExportAllReportResults()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(results)) {
+		assert.Equal(t, []SecurityFinding{{
+			Check:   "contentTypeOptions",
+			Message: "the header X-Content-Type-Options is not nosniff",
+		}}, results[0].Findings)
+	}
+}
@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// verifySOAP checks the fault of the SOAP response, the response should not have a fault unless it's expected.
+// The fault string is the faultstring of SOAP 1.1, or the Reason/Text of SOAP 1.2
+func verifySOAP(name, expectFault string, body []byte) (err error) {
+	var root *xmlNode
+	if root, err = parseXML(body); err != nil {
+		err = fmt.Errorf("case: %s, invalid SOAP response: %v", name, err)
+		return
+	}
+
+	var faults []string
+	for _, path := range []string{"/Envelope/Body/Fault/faultstring", "/Envelope/Body/Fault/Reason/Text"} {
+		var values []string
+		if values, err = queryXPath(root, path); err != nil {
+			return
+		}
+		faults = append(faults, values...)
+	}
+	if len(faults) == 0 {
+		if values, _ := queryXPath(root, "/Envelope/Body/Fault"); len(values) > 0 {
+			faults = values
+		}
+	}
+
+	switch {
+	case expectFault == "" && len(faults) > 0:
+		err = fmt.Errorf("case: %s, unexpected SOAP fault: %s", name, strings.Join(faults, "; "))
+	case expectFault != "" && !containsMessage(faults, expectFault):
+		err = fmt.Errorf("case: %s, expect the SOAP fault '%s', actual: %s", name, expectFault, strings.Join(faults, "; "))
+	}
+	return
+}
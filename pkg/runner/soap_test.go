@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSOAP(t *testing.T) {
+	const request = `<?xml version="1.0" encoding="UTF-8"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">` +
+		`<soap:Body><m:GetUser xmlns:m="urn:users"><m:ID>1</m:ID></m:GetUser></soap:Body></soap:Envelope>`
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		expect atest.Response
+		err    string
+	}{{
+		name:   "normal",
+		status: http.StatusOK,
+		body:   `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><m:GetUserResponse xmlns:m="urn:users"><m:Name>linuxsuren</m:Name></m:GetUserResponse></soap:Body></soap:Envelope>`,
+		expect: atest.Response{XMLFields: map[string]interface{}{"/Envelope/Body/GetUserResponse/Name": "linuxsuren"}},
+	}, {
+		name:   "unexpected fault",
+		status: http.StatusOK,
+		body:   `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault><faultcode>soap:Client</faultcode><faultstring>user not found</faultstring></soap:Fault></soap:Body></soap:Envelope>`,
+		err:    "unexpected SOAP fault: user not found",
+	}, {
+		name:   "expected fault of SOAP 1.2",
+		status: http.StatusInternalServerError,
+		body:   `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault><env:Reason><env:Text xml:lang="en">user not found</env:Text></env:Reason></env:Fault></env:Body></env:Envelope>`,
+		expect: atest.Response{StatusCode: http.StatusInternalServerError, SOAPFault: "not found"},
+	}, {
+		name:   "missing the expected fault",
+		status: http.StatusOK,
+		body:   `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body/></soap:Envelope>`,
+		expect: atest.Response{SOAPFault: "not found"},
+		err:    "expect the SOAP fault 'not found'",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New(urlLocalhost).Post("/users").MatchHeader("SOAPAction", `"urn:users#GetUser"`).
+				MatchType("text/xml").BodyString(request).
+				Reply(tt.status).SetHeader("Content-Type", "text/xml").BodyString(tt.body)
+
+			_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+				Request: atest.Request{
+					API: urlLocalhost + "/users",
+					SOAP: &atest.SOAP{
+						Action: "urn:users#GetUser",
+						Body:   `<m:GetUser xmlns:m="urn:users"><m:ID>{{.id}}</m:ID></m:GetUser>`,
+					},
+				},
+				Expect: tt.expect,
+			}, map[string]interface{}{"id": "1"}, context.TODO())
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+			assert.True(t, gock.IsDone())
+		})
+	}
+
+	err := verifySOAP("invalid", "", []byte("not xml"))
+	assert.ErrorContains(t, err, "invalid SOAP response")
+}
@@ -0,0 +1,126 @@
+package runner
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const (
+	defaultSocketTimeout = 5 * time.Second
+	maxSocketResponse    = 64 * 1024
+)
+
+// runSocket connects the TCP or UDP address of the API, sends the body, then reads the response if it's expected.
+// The response time is the duration of connecting, or until the response is read. The output is the response
+func (r *simpleTestCaseRunner) runSocket(ctx context.Context, testcase *testing.TestCase, dataContext interface{},
+	contextDir string, record *ReportRecord) (output interface{}, err error) {
+	request := &testcase.Request
+	if err = request.Render(dataContext, contextDir); err != nil {
+		return
+	}
+
+	var location *url.URL
+	if location, err = url.Parse(request.API); err != nil {
+		return
+	}
+	network := location.Scheme
+	if network != "tcp" && network != "udp" {
+		err = fmt.Errorf("not supported scheme of the socket: '%s', it should be tcp or udp", network)
+		return
+	}
+
+	var timeout, maxResponseTime time.Duration
+	if timeout, err = parseDurationOrDefault(request.Socket.Timeout, defaultSocketTimeout); err != nil {
+		err = fmt.Errorf("invalid timeout of the socket: %v", err)
+		return
+	}
+	if maxResponseTime, err = parseMaxResponseTime(&testcase.Expect); err != nil {
+		return
+	}
+
+	var payload []byte
+	if request.Socket.Hex {
+		if payload, err = hex.DecodeString(strings.Join(strings.Fields(request.Body), "")); err != nil {
+			err = fmt.Errorf("invalid hex body: %v", err)
+			return
+		}
+	} else {
+		payload = []byte(request.Body)
+	}
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	dialCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	var dial dialFunc
+	if network == "tcp" {
+		if dial, err = newRequestDial(request); err != nil {
+			return
+		}
+	}
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	r.log.Info("start to connect %s\n", request.API)
+	begin := time.Now()
+	var conn net.Conn
+	if conn, err = dial(dialCtx, network, location.Host); err != nil {
+		err = fmt.Errorf("failed to connect %s: %v", request.API, err)
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	record.ResponseTime = time.Since(begin)
+	if err = conn.SetDeadline(deadline); err != nil {
+		return
+	}
+
+	if len(payload) > 0 {
+		r.log.Debug("socket: send %d bytes\n", len(payload))
+		if _, err = conn.Write(payload); err != nil {
+			err = fmt.Errorf("failed to send the body: %v", err)
+			return
+		}
+	}
+
+	if request.Socket.Read || hasExpectedBody(&testcase.Expect) {
+		buf := make([]byte, maxSocketResponse)
+		var count int
+		if count, err = conn.Read(buf); err != nil {
+			err = fmt.Errorf("failed to read the response: %v", err)
+			return
+		}
+		record.ResponseTime = time.Since(begin)
+
+		response := string(buf[:count])
+		if request.Socket.Hex {
+			response = hex.EncodeToString(buf[:count])
+		}
+		record.Body = response
+		r.log.Debug("socket: receive %s\n", response)
+
+		if output, err = verifyMessage(testcase.Name, testcase.Expect, response); err != nil {
+			return
+		}
+	}
+	err = expectResponseTime(testcase.Name, maxResponseTime, record)
+	return
+}
+
+// hasExpectedBody checks if there is any expectation of the body
+func hasExpectedBody(expect *testing.Response) bool {
+	return expect.Body != "" || expect.BodyPattern != "" || len(expect.BodyContains) > 0 || len(expect.BodyNotContains) > 0 ||
+		len(expect.BodyFieldsExpect) > 0 || len(expect.BodyFieldsNotExpect) > 0 || len(expect.Verify) > 0 || expect.Schema != ""
+}
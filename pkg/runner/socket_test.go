@@ -0,0 +1,133 @@
+package runner
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSocket(t *testing.T) {
+	// the TCP server responds +PONG to PING, and nothing to the others
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer tcpListener.Close()
+	go func() {
+		for {
+			conn, err := tcpListener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				buf := make([]byte, 1024)
+				for {
+					count, err := conn.Read(buf)
+					if err != nil {
+						return
+					}
+					if strings.HasPrefix(string(buf[:count]), "PING") {
+						_, _ = conn.Write([]byte("+PONG\r\n"))
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	// the UDP server echoes the datagrams
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer udpConn.Close()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			count, addr, err := udpConn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = udpConn.WriteTo(buf[:count], addr)
+		}
+	}()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	closedAddress := closed.Addr().String()
+	assert.NoError(t, closed.Close())
+
+	tests := []struct {
+		name   string
+		api    string
+		body   string
+		socket *atest.Socket
+		expect atest.Response
+		method string
+		output interface{}
+		err    string
+	}{{
+		name:   "connect only",
+		api:    "tcp://" + tcpListener.Addr().String(),
+		socket: &atest.Socket{},
+		method: "TCP",
+	}, {
+		name:   "response prefix",
+		api:    "tcp://" + tcpListener.Addr().String(),
+		body:   "PING\r\n",
+		socket: &atest.Socket{},
+		expect: atest.Response{BodyPattern: `^\+PONG`},
+		method: "TCP",
+		output: "+PONG\r\n",
+	}, {
+		name:   "hex over UDP",
+		api:    "udp://" + udpConn.LocalAddr().String(),
+		body:   "01 02 ff",
+		socket: &atest.Socket{Hex: true},
+		expect: atest.Response{Body: "0102ff"},
+		method: "UDP",
+		output: "0102ff",
+	}, {
+		name:   "no response",
+		api:    "tcp://" + tcpListener.Addr().String(),
+		body:   "QUIT\r\n",
+		socket: &atest.Socket{Read: true, Timeout: "100ms"},
+		err:    "failed to read the response",
+	}, {
+		name:   "refused",
+		api:    "tcp://" + closedAddress,
+		socket: &atest.Socket{},
+		err:    "failed to connect",
+	}, {
+		name:   "invalid hex",
+		api:    "udp://" + udpConn.LocalAddr().String(),
+		body:   "fake",
+		socket: &atest.Socket{Hex: true},
+		err:    "invalid hex body",
+	}, {
+		name:   "invalid scheme",
+		api:    "http://" + tcpListener.Addr().String(),
+		socket: &atest.Socket{},
+		err:    "not supported scheme of the socket",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := NewMemoryTestReporter()
+			output, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+				Request: atest.Request{API: tt.api, Body: tt.body, Socket: tt.socket},
+				Expect:  tt.expect,
+			}, nil, context.TODO())
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.output, output)
+			if records := reporter.GetAllRecords(); assert.Len(t, records, 1) {
+				assert.Equal(t, tt.method, records[0].Method)
+				assert.Greater(t, records[0].ResponseTime, time.Duration(0))
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package runner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/render"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// runSQL executes the script files one by one, then the inline statements. Each file runs in a transaction, so do the statements
+func (r *simpleTestCaseRunner) runSQL(ctx context.Context, phase string, script *testing.SQL, contextDir string, dataContext interface{}) (err error) {
+	if script == nil || (len(script.Files) == 0 && len(script.Statements) == 0) {
+		return
+	}
+
+	var dsn string
+	if dsn, err = render.Render("dsn", script.DSN, dataContext); err != nil {
+		return
+	}
+
+	var db *sql.DB
+	if db, err = sql.Open(script.Driver, dsn); err != nil {
+		err = fmt.Errorf("%s: failed to open the database: %v", phase, err)
+		return
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	for _, file := range script.Files {
+		r.log.Info("%s: execute SQL file %s\n", phase, file)
+
+		var data []byte
+		if data, err = os.ReadFile(resolvePath(contextDir, file)); err != nil {
+			return
+		}
+		if err = execStatements(ctx, db, splitSQLStatements(string(data))); err != nil {
+			err = fmt.Errorf("%s: failed to execute %s: %v", phase, file, err)
+			return
+		}
+	}
+
+	if len(script.Statements) > 0 {
+		r.log.Info("%s: execute %d SQL statements\n", phase, len(script.Statements))
+		statements := make([]string, len(script.Statements))
+		for i, statement := range script.Statements {
+			if statements[i], err = render.Render("sql statement", statement, dataContext); err != nil {
+				return
+			}
+		}
+		if err = execStatements(ctx, db, statements); err != nil {
+			err = fmt.Errorf("%s: failed to execute the statements: %v", phase, err)
+		}
+	}
+	return
+}
+
+// verifySQL runs the queries after the response is verified, the rows should be the same as the expected ones in order.
+// Only the columns of the expected rows are compared, the values are compared as strings and the NULL is nil
+func (r *simpleTestCaseRunner) verifySQL(ctx context.Context, name string, queries []testing.SQLQuery, dataContext interface{}) (err error) {
+	for i, query := range queries {
+		var dsn, statement string
+		if dsn, err = render.Render("dsn", query.DSN, dataContext); err != nil {
+			return
+		}
+		if statement, err = render.Render("sql query", query.Query, dataContext); err != nil {
+			return
+		}
+
+		r.log.Debug("verify SQL query: %s\n", statement)
+		var rows []map[string]interface{}
+		if rows, err = querySQL(ctx, query.Driver, dsn, statement); err != nil {
+			err = fmt.Errorf("case: %s, failed to run SQL query %d: %v", name, i+1, err)
+			return
+		}
+		if err = compareRows(query.Rows, rows); err != nil {
+			err = fmt.Errorf("case: %s, SQL query %d %v", name, i+1, err)
+			return
+		}
+	}
+	return
+}
+
+// querySQL returns the rows of the query, the bytes are converted into strings
+func querySQL(ctx context.Context, driverName, dsn, query string) (result []map[string]interface{}, err error) {
+	var db *sql.DB
+	if db, err = sql.Open(driverName, dsn); err != nil {
+		return
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	var rows *sql.Rows
+	if rows, err = db.QueryContext(ctx, query); err != nil {
+		return
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var columns []string
+	if columns, err = rows.Columns(); err != nil {
+		return
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err = rows.Scan(pointers...); err != nil {
+			return
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if data, ok := values[i].([]byte); ok {
+				row[column] = string(data)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	err = rows.Err()
+	return
+}
+
+// compareRows checks the count of the rows, and the columns of the expected rows
+func compareRows(expect, actual []map[string]interface{}) (err error) {
+	if len(expect) != len(actual) {
+		err = fmt.Errorf("expect %d rows, actual: %d", len(expect), len(actual))
+		return
+	}
+
+	for i, row := range expect {
+		for column, expectVal := range row {
+			val, ok := actual[i][column]
+			switch {
+			case !ok:
+				err = fmt.Errorf("not found column %s of row %d", column, i+1)
+			case expectVal == nil && val != nil:
+				err = fmt.Errorf("column %s of row %d expect NULL, actual: %v", column, i+1, val)
+			case expectVal != nil && (val == nil || fmt.Sprintf("%v", expectVal) != fmt.Sprintf("%v", val)):
+				err = fmt.Errorf("column %s of row %d expect value: %v, actual: %v", column, i+1, expectVal, val)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func execStatements(ctx context.Context, db *sql.DB, statements []string) (err error) {
+	var tx *sql.Tx
+	if tx, err = db.BeginTx(ctx, nil); err != nil {
+		return
+	}
+
+	for _, statement := range statements {
+		if _, err = tx.ExecContext(ctx, statement); err != nil {
+			_ = tx.Rollback()
+			return
+		}
+	}
+	err = tx.Commit()
+	return
+}
+
+// splitSQLStatements splits the script by semicolons, the semicolons in the quotes and comments are ignored
+func splitSQLStatements(script string) (statements []string) {
+	var current strings.Builder
+	var quote rune
+	lineComment, blockComment := false, false
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case lineComment:
+			if c == '\n' {
+				lineComment = false
+				current.WriteRune(c)
+			}
+			continue
+		case blockComment:
+			if c == '*' && next == '/' {
+				blockComment = false
+				i++
+			}
+			continue
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '-' && next == '-':
+			lineComment = true
+			i++
+			continue
+		case c == '/' && next == '*':
+			blockComment = true
+			i++
+			continue
+		case c == ';':
+			flush()
+			continue
+		}
+		current.WriteRune(c)
+	}
+	flush()
+	return
+}
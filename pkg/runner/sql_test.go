@@ -0,0 +1,302 @@
+package runner
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSQL(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      *atest.SQL
+		dataContext interface{}
+		verify      func(*testing.T, []string, error)
+	}{{
+		name: "normal",
+		script: &atest.SQL{
+			Driver: fakeSQLDriverName,
+			DSN:    "{{.containers.db.address}}",
+			Files:  []string{"seed.sql"},
+		},
+		dataContext: map[string]interface{}{
+			"containers": map[string]interface{}{
+				"db": map[string]interface{}{"address": "127.0.0.1:5432"},
+			},
+		},
+		verify: func(t *testing.T, statements []string, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				"OPEN 127.0.0.1:5432",
+				"BEGIN",
+				"CREATE TABLE users (name VARCHAR(255))",
+				"INSERT INTO users VALUES ('a;b')",
+				`INSERT INTO users VALUES ("c")`,
+				"COMMIT",
+			}, statements)
+		},
+	}, {
+		name:   "empty",
+		script: &atest.SQL{Driver: fakeSQLDriverName},
+		verify: func(t *testing.T, statements []string, err error) {
+			assert.Nil(t, err)
+			assert.Empty(t, statements)
+		},
+	}, {
+		name: "failed to execute",
+		script: &atest.SQL{
+			Driver: fakeSQLDriverName,
+			DSN:    "fail",
+			Files:  []string{"seed.sql"},
+		},
+		verify: func(t *testing.T, statements []string, err error) {
+			assert.ErrorContains(t, err, "prepare: failed to execute seed.sql")
+			assert.Equal(t, []string{"OPEN fail", "BEGIN", "ROLLBACK"}, statements)
+		},
+	}, {
+		name: "file not found",
+		script: &atest.SQL{
+			Driver: fakeSQLDriverName,
+			Files:  []string{"fake.sql"},
+		},
+		verify: func(t *testing.T, statements []string, err error) {
+			assert.NotNil(t, err)
+		},
+	}, {
+		name: "unknown driver",
+		script: &atest.SQL{
+			Driver: "fake",
+			Files:  []string{"seed.sql"},
+		},
+		verify: func(t *testing.T, statements []string, err error) {
+			assert.ErrorContains(t, err, "failed to open the database")
+		},
+	}, {
+		name: "invalid DSN template",
+		script: &atest.SQL{
+			Driver: fakeSQLDriverName,
+			DSN:    "{{.fake",
+			Files:  []string{"seed.sql"},
+		},
+		verify: func(t *testing.T, statements []string, err error) {
+			assert.NotNil(t, err)
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSQLStatements = nil
+			runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+			err := runner.runSQL(context.TODO(), "prepare", tt.script, "testdata", tt.dataContext)
+			tt.verify(t, fakeSQLStatements, err)
+		})
+	}
+}
+
+func TestRunSQLStatements(t *testing.T) {
+	fakeSQLStatements = nil
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+	err := runner.runSQL(context.TODO(), "prepare", &atest.SQL{
+		Driver:     fakeSQLDriverName,
+		DSN:        "db",
+		Files:      []string{"seed.sql"},
+		Statements: []string{"INSERT INTO users VALUES ('{{.name}}')"},
+	}, "testdata", map[string]interface{}{"name": "rick"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"OPEN db",
+		"BEGIN",
+		"CREATE TABLE users (name VARCHAR(255))",
+		"INSERT INTO users VALUES ('a;b')",
+		`INSERT INTO users VALUES ("c")`,
+		"COMMIT",
+		"BEGIN",
+		"INSERT INTO users VALUES ('rick')",
+		"COMMIT",
+	}, fakeSQLStatements)
+}
+
+func TestVerifySQL(t *testing.T) {
+	tests := []struct {
+		name  string
+		query atest.SQLQuery
+		err   string
+	}{{
+		name: "normal",
+		query: atest.SQLQuery{
+			Query: "SELECT name, age FROM users WHERE team = '{{.team}}'",
+			Rows: []map[string]interface{}{
+				{"name": "rick", "age": 60},
+				{"name": "morty", "age": nil},
+			},
+		},
+	}, {
+		name: "subset of the columns",
+		query: atest.SQLQuery{
+			Query: "SELECT name, age FROM users",
+			Rows:  []map[string]interface{}{{"name": "rick"}, {"name": "morty"}},
+		},
+	}, {
+		name: "count of the rows",
+		query: atest.SQLQuery{
+			Query: "SELECT name, age FROM users",
+			Rows:  []map[string]interface{}{{"name": "rick"}},
+		},
+		err: "SQL query 1 expect 1 rows, actual: 2",
+	}, {
+		name: "unexpected value",
+		query: atest.SQLQuery{
+			Query: "SELECT name, age FROM users",
+			Rows:  []map[string]interface{}{{"name": "rick"}, {"name": "summer"}},
+		},
+		err: "column name of row 2 expect value: summer, actual: morty",
+	}, {
+		name: "unexpected NULL",
+		query: atest.SQLQuery{
+			Query: "SELECT name, age FROM users",
+			Rows:  []map[string]interface{}{{"age": nil}, {"age": 14}},
+		},
+		err: "column age of row 1 expect NULL, actual: 60",
+	}, {
+		name: "not found column",
+		query: atest.SQLQuery{
+			Query: "SELECT name, age FROM users",
+			Rows:  []map[string]interface{}{{"email": "rick"}, {}},
+		},
+		err: "not found column email of row 1",
+	}, {
+		name: "failed to query",
+		query: atest.SQLQuery{
+			DSN:   "fail",
+			Query: "SELECT name, age FROM users",
+		},
+		err: "failed to run SQL query 1",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSQLStatements = nil
+			tt.query.Driver = fakeSQLDriverName
+			tt.query.DSN = atest.EmptyThenDefault(tt.query.DSN, "db")
+			runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+			err := runner.verifySQL(context.TODO(), "users", []atest.SQLQuery{tt.query}, map[string]interface{}{"team": "a"})
+			if tt.err == "" {
+				assert.Nil(t, err)
+				if assert.Len(t, fakeSQLStatements, 2) {
+					assert.Equal(t, "OPEN db", fakeSQLStatements[0])
+					assert.NotContains(t, fakeSQLStatements[1], "{{", "the query is templated")
+				}
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSplitSQLStatements(t *testing.T) {
+	assert.Empty(t, splitSQLStatements(" ; -- comment\n"))
+	assert.Equal(t, []string{"SELECT `a;b`", "SELECT 1"}, splitSQLStatements("SELECT `a;b`;\nSELECT 1 -- end"))
+}
+
+const fakeSQLDriverName = "atest-fake"
+
+var fakeSQLStatements []string
+
+func init() {
+	sql.Register(fakeSQLDriverName, &fakeSQLDriver{})
+}
+
+// fakeSQLDriver records the statements, it fails the statements if the DSN is "fail"
+type fakeSQLDriver struct{}
+
+func (d *fakeSQLDriver) Open(name string) (driver.Conn, error) {
+	fakeSQLStatements = append(fakeSQLStatements, "OPEN "+name)
+	return &fakeSQLConn{fail: name == "fail"}, nil
+}
+
+type fakeSQLConn struct {
+	fail bool
+}
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeSQLConn) Close() error {
+	return nil
+}
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	fakeSQLStatements = append(fakeSQLStatements, "BEGIN")
+	return c, nil
+}
+
+func (c *fakeSQLConn) Commit() error {
+	fakeSQLStatements = append(fakeSQLStatements, "COMMIT")
+	return nil
+}
+
+func (c *fakeSQLConn) Rollback() error {
+	fakeSQLStatements = append(fakeSQLStatements, "ROLLBACK")
+	return nil
+}
+
+type fakeSQLStmt struct {
+	conn  *fakeSQLConn
+	query string
+}
+
+func (s *fakeSQLStmt) Close() error {
+	return nil
+}
+
+func (s *fakeSQLStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.conn.fail {
+		return nil, errors.New("fake")
+	}
+	fakeSQLStatements = append(fakeSQLStatements, strings.TrimSpace(s.query))
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.conn.fail {
+		return nil, errors.New("fake")
+	}
+	fakeSQLStatements = append(fakeSQLStatements, strings.TrimSpace(s.query))
+	return &fakeSQLRows{
+		columns: []string{"name", "age"},
+		values:  [][]driver.Value{{"rick", int64(60)}, {[]byte("morty"), nil}},
+	}, nil
+}
+
+// fakeSQLRows are the same rows of any query
+type fakeSQLRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeSQLRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeSQLRows) Close() error {
+	return nil
+}
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
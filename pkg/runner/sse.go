@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const defaultSSEDuration = 10 * time.Second
+
+// sseEvent is a dispatched Server-Sent Event
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// readEvents reads the stream until the count of the events or the duration, the stream is closed after that.
+// The body is the complete events which are read
+func readEvents(stream io.ReadCloser, options *testing.SSE) (body []byte, err error) {
+	var duration time.Duration
+	if duration, err = parseDurationOrDefault(options.Duration, defaultSSEDuration); err != nil {
+		err = fmt.Errorf("invalid duration of the SSE: %v", err)
+		return
+	}
+
+	var expired int32
+	timer := time.AfterFunc(duration, func() {
+		atomic.StoreInt32(&expired, 1)
+		_ = stream.Close()
+	})
+	defer timer.Stop()
+
+	buf := new(bytes.Buffer)
+	event := new(bytes.Buffer)
+	var count int
+	var hasData bool
+	reader := bufio.NewReader(stream)
+	for options.Count <= 0 || count < options.Count {
+		var line string
+		if line, err = reader.ReadString('\n'); err != nil {
+			if err == io.EOF || atomic.LoadInt32(&expired) == 1 {
+				err = nil
+			}
+			break
+		}
+
+		event.WriteString(line)
+		if strings.TrimRight(line, "\r\n") != "" {
+			hasData = hasData || strings.HasPrefix(line, "data")
+			continue
+		}
+		// the blank line dispatches the event
+		buf.Write(event.Bytes())
+		event.Reset()
+		if hasData {
+			count++
+		}
+		hasData = false
+	}
+	body = buf.Bytes()
+	return
+}
+
+// parseEvents parses the stream, the event without any data is not dispatched, and the comments are ignored
+func parseEvents(body []byte) (events []sseEvent) {
+	current := sseEvent{}
+	var data []string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data != nil {
+				current.Data = strings.Join(data, "\n")
+				current.Event = testing.EmptyThenDefault(current.Event, "message")
+				events = append(events, current)
+			}
+			current, data = sseEvent{ID: current.ID}, nil
+			continue
+		} else if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			current.Event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			current.ID = value
+		}
+	}
+	return
+}
+
+// verifySSE checks the count of the events, and matches the expected events in order with the collected ones.
+// The output is the list of the events
+func verifySSE(name string, options *testing.SSE, expect []testing.SSEEvent, body []byte, contextDir string) (output interface{}, err error) {
+	events := parseEvents(body)
+	if options.Count > 0 && len(events) < options.Count {
+		err = fmt.Errorf("case: %s, expect %d events, actual: %d", name, options.Count, len(events))
+		return
+	}
+
+	items := make([]interface{}, len(events))
+	for i, event := range events {
+		items[i] = map[string]interface{}{"id": event.ID, "event": event.Event, "data": event.Data}
+	}
+	output = items
+
+	next := 0
+	for i, expected := range expect {
+		eventName := testing.EmptyThenDefault(expected.Event, "message")
+		// the expected data is copied since it's shared by the runs of a test case
+		var data testing.Response
+		if expected.Data != nil {
+			data = *expected.Data
+			if data.Schema, err = loadSchema(contextDir, data.Schema); err != nil {
+				return
+			}
+		}
+		var lastErr error
+		matched := false
+		for ; next < len(events) && !matched; next++ {
+			if events[next].Event != eventName {
+				continue
+			}
+			if expected.Data == nil {
+				matched = true
+			} else if _, lastErr = verifyMessage(name, data, events[next].Data); lastErr == nil {
+				matched = true
+			}
+		}
+		if !matched {
+			err = fmt.Errorf("case: %s, not found the expected event %d '%s'", name, i+1, eventName)
+			if lastErr != nil {
+				err = fmt.Errorf("%v, the last one of it: %v", err, lastErr)
+			}
+			return
+		}
+	}
+	return
+}
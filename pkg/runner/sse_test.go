@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSE(t *testing.T) {
+	// the server sends the events, then keeps the stream open until the client closes it
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, ": welcome\n\n")
+		_, _ = fmt.Fprint(w, "data: hello\n\n")
+		_, _ = fmt.Fprint(w, "event: order\nid: 1\ndata: {\"id\": 1,\ndata: \"status\": \"created\"}\n\n")
+		_, _ = fmt.Fprint(w, "event: order\nid: 2\ndata: {\"id\": 2, \"status\": \"paid\"}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		sse    *atest.SSE
+		events []atest.SSEEvent
+		err    string
+	}{{
+		name: "count",
+		sse:  &atest.SSE{Count: 3},
+		events: []atest.SSEEvent{{
+			Data: &atest.Response{Body: "hello"},
+		}, {
+			Event: "order",
+			Data:  &atest.Response{BodyFieldsExpect: map[string]interface{}{"status": "paid"}},
+		}},
+	}, {
+		name: "duration",
+		sse:  &atest.SSE{Duration: "200ms"},
+		events: []atest.SSEEvent{{
+			Event: "order",
+			Data:  &atest.Response{BodyFieldsExpect: map[string]interface{}{"status": "created"}},
+		}},
+	}, {
+		name: "fewer events",
+		sse:  &atest.SSE{Count: 4, Duration: "200ms"},
+		err:  "expect 4 events, actual: 3",
+	}, {
+		name: "not in order",
+		sse:  &atest.SSE{Count: 3},
+		events: []atest.SSEEvent{{
+			Event: "order",
+		}, {
+			Data: &atest.Response{Body: "hello"},
+		}},
+		err: "not found the expected event 2 'message'",
+	}, {
+		name: "unexpected data",
+		sse:  &atest.SSE{Count: 3},
+		events: []atest.SSEEvent{{
+			Event: "order",
+			Data:  &atest.Response{BodyFieldsExpect: map[string]interface{}{"status": "refunded"}},
+		}},
+		err: "field[status] expect value: refunded, actual: paid",
+	}, {
+		name: "invalid duration",
+		sse:  &atest.SSE{Duration: "fake"},
+		err:  "invalid duration of the SSE",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+				Request: atest.Request{API: server.URL, SSE: tt.sse},
+				Expect:  atest.Response{Events: tt.events},
+			}, nil, context.TODO())
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			if events, ok := output.([]interface{}); assert.True(t, ok) && assert.Len(t, events, 3) {
+				assert.Equal(t, map[string]interface{}{"id": "1", "event": "order", "data": "{\"id\": 1,\n\"status\": \"created\"}"}, events[1])
+			}
+		})
+	}
+}
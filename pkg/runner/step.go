@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const defaultStepBackoff = time.Second
+
+// runStep runs a prepare or clean step with the timeout and the retries of the policy, then puts
+// the duration of it into the report. The method of the report record is the upper case of the phase.
+func (r *simpleTestCaseRunner) runStep(ctx context.Context, phase, name string, policy *testing.StepPolicy,
+	step func(context.Context) error) (err error) {
+	record := NewReportRecord()
+	record.Method = strings.ToUpper(phase)
+	record.API = name
+	defer func() {
+		record.EndTime = time.Now()
+		record.Error = err
+		if err != nil {
+			record.Body = err.Error()
+		}
+		r.putRecord(ctx, record)
+		r.log.With(withResult(Fields{"phase": phase, "step": name}, record.Duration(), err)).
+			Debug("%s: %s took %v\n", phase, name, record.Duration())
+	}()
+
+	if policy == nil {
+		policy = &testing.StepPolicy{}
+	}
+
+	var timeout, backoff time.Duration
+	if timeout, err = parseDurationOrDefault(policy.Timeout, 0); err != nil {
+		err = fmt.Errorf("invalid timeout of %s: %v", name, err)
+		return
+	}
+	if backoff, err = parseDurationOrDefault(policy.Backoff, defaultStepBackoff); err != nil {
+		err = fmt.Errorf("invalid backoff of %s: %v", name, err)
+		return
+	}
+
+	for i := 0; ; i++ {
+		if err = runWithTimeout(ctx, timeout, step); err == nil || i >= policy.Retry {
+			return
+		}
+
+		r.log.Warn("%s: retry %s after %v, %v\n", phase, name, backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// runWithTimeout returns an error once the timeout is reached. The context of the step is canceled,
+// the step keeps running in the background if it does not respect the context.
+func runWithTimeout(ctx context.Context, timeout time.Duration, step func(context.Context) error) (err error) {
+	if timeout <= 0 {
+		return runSafely(ctx, step)
+	}
+
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runSafely(stepCtx, step)
+	}()
+
+	select {
+	case err = <-done:
+	case <-stepCtx.Done():
+		err = fmt.Errorf("timeout after %v", timeout)
+	}
+	return
+}
+
+// runSafely turns the panic of the step into the error
+func runSafely(ctx context.Context, step func(context.Context) error) (err error) {
+	defer recoverPanic(&err)
+	return step(ctx)
+}
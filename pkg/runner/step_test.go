@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStep(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *atest.StepPolicy
+		errs   []error
+		verify func(*testing.T, int, string, error)
+	}{{
+		name: "without policy",
+		verify: func(t *testing.T, count int, log string, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, 1, count)
+		},
+	}, {
+		name:   "retry until success",
+		policy: &atest.StepPolicy{Retry: 3, Backoff: "1ms"},
+		errs:   []error{errors.New("fake"), errors.New("fake")},
+		verify: func(t *testing.T, count int, log string, err error) {
+			assert.Nil(t, err)
+			assert.Equal(t, 3, count)
+			assert.Contains(t, log, "prepare: retry apply after 1ms, fake")
+			assert.Contains(t, log, "prepare: retry apply after 2ms, fake")
+		},
+	}, {
+		name:   "run out of the retries",
+		policy: &atest.StepPolicy{Retry: 1, Backoff: "1ms"},
+		errs:   []error{errors.New("fake"), errors.New("fake"), nil},
+		verify: func(t *testing.T, count int, log string, err error) {
+			assert.ErrorContains(t, err, "fake")
+			assert.Equal(t, 2, count)
+		},
+	}, {
+		name:   "invalid timeout",
+		policy: &atest.StepPolicy{Timeout: "fake"},
+		verify: func(t *testing.T, count int, log string, err error) {
+			assert.ErrorContains(t, err, "invalid timeout of apply")
+			assert.Equal(t, 0, count)
+		},
+	}, {
+		name:   "invalid backoff",
+		policy: &atest.StepPolicy{Backoff: "fake"},
+		verify: func(t *testing.T, count int, log string, err error) {
+			assert.ErrorContains(t, err, "invalid backoff of apply")
+			assert.Equal(t, 0, count)
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			reporter := NewMemoryTestReporter()
+			runner := NewSimpleTestCaseRunner().WithOutputWriter(buf).WithWriteLevel("info").
+				WithTestReporter(reporter).(*simpleTestCaseRunner)
+
+			var count int
+			err := runner.runStep(context.TODO(), "prepare", "apply", tt.policy, func(context.Context) (err error) {
+				if count < len(tt.errs) {
+					err = tt.errs[count]
+				}
+				count++
+				return
+			})
+			tt.verify(t, count, buf.String(), err)
+
+			records := reporter.GetAllRecords()
+			if assert.Equal(t, 1, len(records)) {
+				assert.Equal(t, "PREPARE", records[0].Method)
+				assert.Equal(t, "apply", records[0].API)
+				assert.Equal(t, err, records[0].Error)
+			}
+		})
+	}
+}
+
+func TestRunWithTimeout(t *testing.T) {
+	err := runWithTimeout(context.TODO(), time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	assert.ErrorContains(t, err, "timeout after 1ms")
+
+	err = runWithTimeout(context.TODO(), time.Second, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return errors.New("fake")
+	})
+	assert.ErrorContains(t, err, "fake")
+
+	err = runWithTimeout(context.TODO(), 0, func(ctx context.Context) error {
+		return nil
+	})
+	assert.Nil(t, err)
+}
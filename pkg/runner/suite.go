@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"context"
+	"io"
+
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+)
+
+// SuiteRunner runs the prepare, clean steps and the hooks of a test suite, they run once for all the test cases.
+// The order is: the suite prepare and before hook, then the test cases, then the suite after hook and clean.
+type SuiteRunner interface {
+	// Prepare runs the prepare steps then the before hook, the containers and the outputs are put into the data context
+	Prepare(ctx context.Context, suite *testing.TestSuite, dataContext map[string]interface{}) error
+	// Clean runs the after hook, then the clean steps if the clean policy allows. It should be called even if Prepare is failed.
+	// The failed indicates if the prepare or any test case is failed.
+	Clean(ctx context.Context, suite *testing.TestSuite, dataContext map[string]interface{}, failed bool) error
+	// WithTestReporter sets the reporter of the step durations
+	WithTestReporter(TestReporter) SuiteRunner
+}
+
+type simpleSuiteRunner struct {
+	caseRunner *simpleTestCaseRunner
+	resources  *preparedResources
+	unlock     func() error
+}
+
+// NewSuiteRunner creates the instance of the suite runner, the default level is info
+func NewSuiteRunner(writer io.Writer, level string, execer fakeruntime.Execer) SuiteRunner {
+	if level == "" {
+		level = "info"
+	}
+	caseRunner := &simpleTestCaseRunner{executor: exec.NewExecutor()}
+	caseRunner.WithOutputWriter(writer).
+		WithWriteLevel(level).
+		WithTestReporter(NewDiscardTestReporter()).
+		WithExecer(execer)
+	return &simpleSuiteRunner{
+		caseRunner: caseRunner,
+		resources:  &preparedResources{},
+	}
+}
+
+// Prepare runs the suite-level prepare steps
+func (s *simpleSuiteRunner) Prepare(ctx context.Context, suite *testing.TestSuite, dataContext map[string]interface{}) (err error) {
+	defer s.withSuite(suite)()
+	contextDir := NewContextKeyBuilder().ParentDir().GetContextValueOrEmpty(ctx)
+	s.caseRunner.log.Info("start to prepare suite: '%s'\n", suite.Name)
+	if s.unlock, err = s.caseRunner.acquireLock(ctx, suite.Prepare.Lock); err != nil {
+		return
+	}
+	err = s.caseRunner.runPrepare(ctx, suite.Prepare, contextDir, dataContext, s.resources)
+	s.resources.withContext(dataContext)
+	if err == nil {
+		var outputs map[string]interface{}
+		outputs, err = s.caseRunner.runHook(ctx, hookBefore, suite.Before, contextDir, dataContext)
+		mergeContext(dataContext, HookKey, outputs)
+	}
+	return
+}
+
+// Clean runs the suite-level clean steps, then releases the lock. The clean steps are skipped
+// if the lock was not acquired, the environment might be in use by others.
+func (s *simpleSuiteRunner) Clean(ctx context.Context, suite *testing.TestSuite, dataContext map[string]interface{}, failed bool) (err error) {
+	defer s.withSuite(suite)()
+	if suite.Prepare.Lock != nil && s.unlock == nil {
+		s.caseRunner.log.Warn("skip the clean of suite '%s' due to the lock is not acquired\n", suite.Name)
+		return
+	}
+	defer func() {
+		if s.unlock != nil {
+			if unlockErr := s.unlock(); err == nil {
+				err = unlockErr
+			}
+			s.unlock = nil
+		}
+	}()
+
+	// the after hook runs no matter the test cases are failed or not
+	contextDir := NewContextKeyBuilder().ParentDir().GetContextValueOrEmpty(ctx)
+	_, err = s.caseRunner.runHook(ctx, hookAfter, suite.After, contextDir, dataContext)
+
+	if !suite.Clean.ShouldRun(failed) {
+		s.caseRunner.log.Info("skip the clean of suite '%s' due to the policy: %s\n", suite.Name, suite.Clean.CleanPolicy)
+		return
+	}
+
+	s.caseRunner.log.Info("start to clean suite: '%s'\n", suite.Name)
+	if cleanErr := s.caseRunner.runClean(ctx, suite.Clean, contextDir, dataContext, s.resources); err == nil {
+		err = cleanErr
+	}
+	return
+}
+
+// WithTestReporter sets the TestReporter
+func (s *simpleSuiteRunner) WithTestReporter(reporter TestReporter) SuiteRunner {
+	s.caseRunner.WithTestReporter(reporter)
+	return s
+}
+
+// withSuite puts the suite name into the structured logs, the returned function restores the log
+func (s *simpleSuiteRunner) withSuite(suite *testing.TestSuite) func() {
+	return s.caseRunner.withLog(s.caseRunner.log.With(Fields{"suite": suite.Name}))
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuiteRunner(t *testing.T) {
+	defer gock.Off()
+	gock.New(urlLocalhost).Post("/login").Reply(http.StatusOK).BodyString(`{"token":"abc"}`)
+	gock.New(urlLocalhost).Get("/foo").MatchHeader("Authorization", "Bearer abc").
+		Times(2).Reply(http.StatusOK).BodyString(`{}`)
+
+	suite := &atest.TestSuite{
+		Name: "suite",
+		Prepare: atest.Prepare{
+			DockerCompose: []atest.DockerCompose{{File: "compose.yaml"}},
+			HTTP: []atest.HTTPStep{{
+				Name:    "login",
+				Request: atest.Request{API: urlLocalhost + "/login", Method: http.MethodPost},
+			}},
+			Commands: []atest.Command{{Command: "seed"}},
+		},
+		Clean: atest.Clean{
+			CleanPrepare: true,
+			Commands:     []atest.Command{{Command: "reset"}},
+		},
+	}
+
+	executor := &exec.FakeExecutor{}
+	suiteRunner := NewSuiteRunner(io.Discard, "info", fakeruntime.FakeExecer{})
+	suiteRunner.(*simpleSuiteRunner).caseRunner.executor = executor
+
+	dataContext := map[string]interface{}{}
+	assert.Nil(t, suiteRunner.Prepare(context.TODO(), suite, dataContext))
+	assert.Equal(t, map[string]interface{}{"login": map[string]interface{}{"token": "abc"}}, dataContext["prepare"])
+
+	for i := 0; i < 2; i++ {
+		_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+			Request: atest.Request{
+				API:    urlFoo,
+				Header: map[string]string{"Authorization": "Bearer {{.prepare.login.token}}"},
+			},
+		}, dataContext, context.TODO())
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, suiteRunner.Clean(context.TODO(), suite, dataContext, false))
+	assert.True(t, gock.IsDone())
+	if assert.Equal(t, 4, len(executor.Commands)) {
+		assert.Equal(t, "docker", executor.Commands[0].Name)
+		assert.Equal(t, "seed", executor.Commands[1].Name)
+		assert.Equal(t, "reset", executor.Commands[2].Name)
+		assert.Equal(t, "docker", executor.Commands[3].Name)
+	}
+}
+
+func TestSuiteRunnerWithError(t *testing.T) {
+	suite := &atest.TestSuite{
+		Prepare: atest.Prepare{
+			DockerCompose: []atest.DockerCompose{{File: "compose.yaml"}},
+		},
+		Clean: atest.Clean{CleanPrepare: true},
+	}
+
+	suiteRunner := NewSuiteRunner(io.Discard, "", fakeruntime.FakeExecer{})
+	suiteRunner.(*simpleSuiteRunner).caseRunner.executor = &exec.FakeExecutor{Err: errors.New("fake")}
+	assert.ErrorContains(t, suiteRunner.Prepare(context.TODO(), suite, map[string]interface{}{}), "failed to start compose.yaml")
+	assert.ErrorContains(t, suiteRunner.Clean(context.TODO(), suite, map[string]interface{}{}, true), "failed to stop compose.yaml")
+
+	suite.Clean.CleanPolicy = atest.CleanPolicyOnSuccess
+	assert.Nil(t, suiteRunner.Clean(context.TODO(), suite, map[string]interface{}{}, true))
+}
@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// teardown deletes a resource which is created by the prepare steps
+type teardown struct {
+	name   string
+	policy *testing.StepPolicy
+	// prepared resources are deleted only if the cleanPrepare is true, the containers are always removed
+	prepared bool
+	run      func(context.Context) error
+}
+
+// register pushes the teardown of a created resource into the stack
+func (p *preparedResources) register(item teardown) {
+	p.teardowns = append(p.teardowns, item)
+}
+
+// unwind runs the teardowns in the reverse order of the registration. All of them run even if some
+// of them are failed, the first error will be returned. The stack is empty after it.
+func (r *simpleTestCaseRunner) unwind(ctx context.Context, resources *preparedResources, cleanPrepare bool) (err error) {
+	for len(resources.teardowns) > 0 {
+		last := len(resources.teardowns) - 1
+		item := resources.teardowns[last]
+		resources.teardowns = resources.teardowns[:last]
+
+		if item.prepared && !cleanPrepare {
+			continue
+		}
+		if cleanErr := r.runStep(ctx, "clean", item.name, item.policy, item.run); cleanErr != nil && err == nil {
+			err = cleanErr
+		}
+	}
+	return
+}
+
+// detachedContext keeps the values of the parent context, but it's never canceled.
+// The clean steps run with it, then the resources are deleted even if the run is canceled.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+// recoverPanic turns a panic into the error, it must be deferred directly
+func recoverPanic(err *error) {
+	if p := recover(); p != nil {
+		*err = fmt.Errorf("panic: %v", p)
+	}
+}
@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnwind(t *testing.T) {
+	var order []string
+	newTeardown := func(name string, prepared bool, err error) teardown {
+		return teardown{
+			name:     name,
+			prepared: prepared,
+			run: func(ctx context.Context) error {
+				assert.Nil(t, ctx.Err())
+				order = append(order, name)
+				return err
+			},
+		}
+	}
+
+	resources := &preparedResources{}
+	resources.register(newTeardown("delete deploy.yaml", true, nil))
+	resources.register(newTeardown("remove db", false, errors.New("first")))
+	resources.register(newTeardown("down compose.yaml", true, errors.New("second")))
+	resources.register(newTeardown("remove web", false, nil))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+	err := runner.runClean(ctx, atest.Clean{}, "", nil, resources)
+	assert.EqualError(t, err, "first")
+	assert.Equal(t, []string{"remove web", "remove db"}, order)
+	assert.Empty(t, resources.teardowns)
+
+	order = nil
+	resources.register(newTeardown("delete deploy.yaml", true, nil))
+	resources.register(newTeardown("down compose.yaml", true, errors.New("second")))
+	err = runner.runClean(ctx, atest.Clean{CleanPrepare: true}, "", nil, resources)
+	assert.EqualError(t, err, "second")
+	assert.Equal(t, []string{"down compose.yaml", "delete deploy.yaml"}, order)
+
+	// run only once
+	assert.Nil(t, runner.runClean(ctx, atest.Clean{CleanPrepare: true}, "", nil, resources))
+	assert.Equal(t, 2, len(order))
+}
+
+func TestRunStepWithPanic(t *testing.T) {
+	runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+	for _, policy := range []*atest.StepPolicy{nil, {Timeout: "1m"}} {
+		err := runner.runStep(context.TODO(), "prepare", "fake", policy, func(context.Context) error {
+			panic("fake")
+		})
+		assert.EqualError(t, err, "panic: fake")
+	}
+}
+
+func TestRecoverPanic(t *testing.T) {
+	err := func() (err error) {
+		defer recoverPanic(&err)
+		panic("fake")
+	}()
+	assert.EqualError(t, err, "panic: fake")
+}
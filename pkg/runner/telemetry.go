@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ResourceUsage is the resource usage of the runner process during a run, it tells whether
+// the bottleneck is the target service or the load generator itself
+type ResourceUsage struct {
+	Duration time.Duration
+	// CPUTime is the user and system CPU time of the process, it's zero if it's not supported by the OS
+	CPUTime time.Duration
+	// CPUPercent is the percentage of the CPU time against the duration of all the CPUs
+	CPUPercent    float64
+	NumCPU        int
+	MaxGoroutines int
+	MaxHeapAlloc  uint64
+	TotalAlloc    uint64
+	NumGC         uint32
+	GCPauseTotal  time.Duration
+}
+
+// ResourceMonitor samples the resource usage of the process until it's stopped
+type ResourceMonitor interface {
+	Stop() *ResourceUsage
+}
+
+type resourceMonitor struct {
+	lock          sync.Mutex
+	startTime     time.Time
+	startCPU      time.Duration
+	start         runtime.MemStats
+	maxGoroutines int
+	maxHeapAlloc  uint64
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+// NewResourceMonitor starts to sample the goroutines and the heap with the interval
+func NewResourceMonitor(interval time.Duration) ResourceMonitor {
+	m := &resourceMonitor{
+		startTime: time.Now(),
+		startCPU:  cpuTime(),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	runtime.ReadMemStats(&m.start)
+	m.sample()
+
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				m.sample()
+			}
+		}
+	}()
+	return m
+}
+
+func (m *resourceMonitor) sample() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if goroutines := runtime.NumGoroutine(); goroutines > m.maxGoroutines {
+		m.maxGoroutines = goroutines
+	}
+	if stats.HeapAlloc > m.maxHeapAlloc {
+		m.maxHeapAlloc = stats.HeapAlloc
+	}
+}
+
+// Stop stops the sampling, and returns the usage since the monitor is created
+func (m *resourceMonitor) Stop() *ResourceUsage {
+	close(m.stop)
+	<-m.done
+	m.sample()
+
+	var end runtime.MemStats
+	runtime.ReadMemStats(&end)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	usage := &ResourceUsage{
+		Duration:      time.Since(m.startTime),
+		CPUTime:       cpuTime() - m.startCPU,
+		NumCPU:        runtime.NumCPU(),
+		MaxGoroutines: m.maxGoroutines,
+		MaxHeapAlloc:  m.maxHeapAlloc,
+		TotalAlloc:    end.TotalAlloc - m.start.TotalAlloc,
+		NumGC:         end.NumGC - m.start.NumGC,
+		GCPauseTotal:  time.Duration(end.PauseTotalNs - m.start.PauseTotalNs),
+	}
+	if usage.Duration > 0 {
+		usage.CPUPercent = float64(usage.CPUTime) / float64(usage.Duration) / float64(usage.NumCPU) * 100
+	}
+	return usage
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package runner
+
+import (
+	"syscall"
+	"time"
+)
+
+// cpuTime returns the user and system CPU time of the process
+func cpuTime() time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
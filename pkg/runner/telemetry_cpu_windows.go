@@ -0,0 +1,8 @@
+package runner
+
+import "time"
+
+// cpuTime is not supported on Windows
+func cpuTime() time.Duration {
+	return 0
+}
@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceMonitor(t *testing.T) {
+	monitor := NewResourceMonitor(time.Millisecond)
+	data := make([][]byte, 0)
+	for i := 0; i < 10; i++ {
+		data = append(data, make([]byte, 1024))
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	usage := monitor.Stop()
+	assert.NotEmpty(t, data)
+	assert.Greater(t, usage.Duration, time.Duration(0))
+	assert.Greater(t, usage.NumCPU, 0)
+	assert.Greater(t, usage.MaxGoroutines, 0)
+	assert.Greater(t, usage.MaxHeapAlloc, uint64(0))
+	assert.Greater(t, usage.TotalAlloc, uint64(0))
+}
+
+func TestResourceUsageReport(t *testing.T) {
+	usage := &ResourceUsage{
+		CPUTime:       time.Second,
+		CPUPercent:    12.5,
+		NumCPU:        4,
+		MaxGoroutines: 10,
+		MaxHeapAlloc:  1536 * 1024,
+		TotalAlloc:    512,
+		NumGC:         3,
+		GCPauseTotal:  time.Millisecond,
+	}
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, NewResultWriter(buf).WithResourceUsage(usage).Output(nil))
+	assert.Contains(t, buf.String(), `Runner CPU: 1s (12.5% of 4 CPUs)
+Runner memory: max heap 1.5MiB, total allocated 512B
+Runner GC: 3 times, paused 1ms
+Runner goroutines: max 10`)
+
+	buf.Reset()
+	assert.Nil(t, NewMarkdownResultWriter(buf).WithResourceUsage(usage).Output(nil))
+	assert.Contains(t, buf.String(), "| CPU | 1s (12.5% of 4 CPUs) |")
+	assert.Contains(t, buf.String(), "| Max goroutines | 10 |")
+
+	buf.Reset()
+	assert.Nil(t, NewMarkdownResultWriter(buf).Output(nil))
+	assert.NotContains(t, buf.String(), "Runner")
+}
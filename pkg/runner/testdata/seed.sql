@@ -0,0 +1,5 @@
+-- create the users; it's a comment
+CREATE TABLE users (name VARCHAR(255));
+INSERT INTO users VALUES ('a;b');
+/* block; comment */
+INSERT INTO users VALUES ("c")
@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// ThinkTime is the pause after a test case, it's a fixed duration or a random one of a range
+type ThinkTime struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// ParseThinkTime parses the think time, such as: 500ms, or a range: 1s-3s. It's zero if the text is empty
+func ParseThinkTime(text string) (thinkTime ThinkTime, err error) {
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+
+	min, max := text, text
+	if index := strings.Index(text, "-"); index > 0 {
+		min, max = text[:index], text[index+1:]
+	}
+	if thinkTime.Min, err = time.ParseDuration(strings.TrimSpace(min)); err == nil {
+		thinkTime.Max, err = time.ParseDuration(strings.TrimSpace(max))
+	}
+	if err == nil && (thinkTime.Min < 0 || thinkTime.Max < thinkTime.Min) {
+		err = fmt.Errorf("the range should be from a non-negative duration to a larger one")
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid think time '%s': %v", text, err)
+	}
+	return
+}
+
+// Duration returns a random duration of the range
+func (t ThinkTime) Duration() time.Duration {
+	if t.Max <= t.Min {
+		return t.Min
+	}
+	return t.Min + time.Duration(rand.Int63n(int64(t.Max-t.Min)+1))
+}
+
+// Pause waits for the think time, it returns early once the context is done or the stop channel is closed
+func (t ThinkTime) Pause(ctx context.Context, stop <-chan struct{}) {
+	duration := t.Duration()
+	if duration <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-stop:
+	case <-timer.C:
+	}
+}
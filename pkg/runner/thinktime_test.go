@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseThinkTime(t *testing.T) {
+	tests := []struct {
+		text   string
+		expect ThinkTime
+		err    string
+	}{{
+		text: "",
+	}, {
+		text:   "500ms",
+		expect: ThinkTime{Min: 500 * time.Millisecond, Max: 500 * time.Millisecond},
+	}, {
+		text:   "1s - 3s",
+		expect: ThinkTime{Min: time.Second, Max: 3 * time.Second},
+	}, {
+		text: "fake",
+		err:  "invalid think time 'fake'",
+	}, {
+		text: "3s-1s",
+		err:  "the range should be from a non-negative duration to a larger one",
+	}, {
+		text: "-1s",
+		err:  "invalid think time '-1s'",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			thinkTime, err := ParseThinkTime(tt.text)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect, thinkTime)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestThinkTime(t *testing.T) {
+	thinkTime := ThinkTime{Min: time.Second, Max: 2 * time.Second}
+	for i := 0; i < 10; i++ {
+		duration := thinkTime.Duration()
+		assert.GreaterOrEqual(t, duration, time.Second)
+		assert.LessOrEqual(t, duration, 2*time.Second)
+	}
+	assert.Equal(t, time.Second, ThinkTime{Min: time.Second, Max: time.Second}.Duration())
+
+	begin := time.Now()
+	ThinkTime{Min: 50 * time.Millisecond, Max: 50 * time.Millisecond}.Pause(context.Background(), nil)
+	assert.GreaterOrEqual(t, time.Since(begin), 50*time.Millisecond)
+
+	t.Run("stopped", func(t *testing.T) {
+		stop := make(chan struct{})
+		close(stop)
+		begin := time.Now()
+		thinkTime.Pause(context.Background(), stop)
+		assert.Less(t, time.Since(begin), time.Second)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		begin = time.Now()
+		thinkTime.Pause(ctx, nil)
+		assert.Less(t, time.Since(begin), time.Second)
+	})
+
+	ThinkTime{}.Pause(context.Background(), nil)
+}
@@ -0,0 +1,25 @@
+package runner
+
+import (
+	"context"
+	"time"
+)
+
+// RequestTimeout returns the key of the timeout of the requests
+func (c ContextKey) RequestTimeout() ContextKey {
+	return ContextKey("requestTimeout")
+}
+
+// WithRequestTimeout returns a context with the timeout of each request, including the retries and the polling
+// attempts. The prepare and clean steps, the locks and the waitFor have their own timeouts, so they're not limited by it
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, NewContextKeyBuilder().RequestTimeout(), timeout)
+}
+
+// RequestContext returns a context which is canceled after the timeout of the requests, it's not limited if there is no timeout
+func RequestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout, ok := ctx.Value(NewContextKeyBuilder().RequestTimeout()).(time.Duration); ok && timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
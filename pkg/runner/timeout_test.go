@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowExecutor finishes the commands after the delay, or stops once the context is done
+type slowExecutor struct {
+	delay time.Duration
+}
+
+func (e *slowExecutor) Run(ctx context.Context, command exec.Command, output io.Writer) (int, error) {
+	select {
+	case <-ctx.Done():
+		return -1, ctx.Err()
+	case <-time.After(e.delay):
+		return 0, nil
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	ctx := WithRequestTimeout(context.Background(), 50*time.Millisecond)
+
+	t.Run("the prepare steps are not limited", func(t *testing.T) {
+		runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+		runner.executor = &slowExecutor{delay: 100 * time.Millisecond}
+		_, err := runner.RunTestCase(&atest.TestCase{
+			Prepare: atest.Prepare{Commands: []atest.Command{{Command: "make"}}},
+			Request: atest.Request{API: server.URL},
+		}, nil, ctx)
+		assert.Nil(t, err)
+	})
+
+	t.Run("the request is timeout", func(t *testing.T) {
+		_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+			Request: atest.Request{API: server.URL + "/slow"},
+		}, nil, ctx)
+		assert.ErrorContains(t, err, context.DeadlineExceeded.Error())
+	})
+
+	t.Run("no timeout", func(t *testing.T) {
+		requestCtx, cancel := RequestContext(context.Background())
+		defer cancel()
+		_, ok := requestCtx.Deadline()
+		assert.False(t, ok)
+	})
+}
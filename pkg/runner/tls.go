@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newTLSConfig loads the client certificate and the CA of the options. The server is not verified by default
+// for the compatibility, it's verified if the CA is set, or the SkipVerify is false
+func newTLSConfig(options *testing.TLS, contextDir string) (config *tls.Config, err error) {
+	config = &tls.Config{InsecureSkipVerify: true}
+	if options == nil {
+		return
+	}
+	config.ServerName = options.ServerName
+
+	if options.MinVersion != "" {
+		var ok bool
+		if config.MinVersion, ok = tlsVersions[options.MinVersion]; !ok {
+			err = fmt.Errorf("invalid min TLS version: %s, it should be 1.0, 1.1, 1.2 or 1.3", options.MinVersion)
+			return
+		}
+	}
+
+	if options.Cert != "" || options.Key != "" {
+		var cert tls.Certificate
+		if cert, err = tls.LoadX509KeyPair(resolvePath(contextDir, options.Cert), resolvePath(contextDir, options.Key)); err != nil {
+			err = fmt.Errorf("failed to load the client certificate: %v", err)
+			return
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if options.CA != "" {
+		var data []byte
+		if data, err = os.ReadFile(resolvePath(contextDir, options.CA)); err != nil {
+			return
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(data) {
+			err = fmt.Errorf("invalid certificate authority: %s", options.CA)
+			return
+		}
+		config.InsecureSkipVerify = false
+	}
+
+	if options.SkipVerify != nil {
+		config.InsecureSkipVerify = *options.SkipVerify
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package runner
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMutualTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"client":"` + r.TLS.PeerCertificates[0].Subject.CommonName + `"}`))
+	}))
+	cert, err := tls.LoadX509KeyPair("testdata/tls/server.pem", "testdata/tls/server.key")
+	assert.Nil(t, err)
+	ca, err := os.ReadFile("testdata/tls/ca.pem")
+	assert.Nil(t, err)
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}, ClientCAs: x509.NewCertPool(), ClientAuth: tls.RequireAndVerifyClientCert}
+	server.TLS.ClientCAs.AppendCertsFromPEM(ca)
+	server.StartTLS()
+	defer server.Close()
+
+	yes, no := true, false
+	tests := []struct {
+		name      string
+		tls       *atest.TLS
+		expectErr string
+	}{{
+		name: "client certificate and CA",
+		tls:  &atest.TLS{Cert: "tls/client.pem", Key: "tls/client.key", CA: "tls/ca.pem"},
+	}, {
+		name: "client certificate without CA",
+		tls:  &atest.TLS{Cert: "tls/client.pem", Key: "tls/client.key"},
+	}, {
+		name:      "without client certificate",
+		expectErr: "certificate required",
+	}, {
+		name:      "unmatched server name",
+		tls:       &atest.TLS{Cert: "tls/client.pem", Key: "tls/client.key", CA: "tls/ca.pem", ServerName: "fake"},
+		expectErr: "not fake",
+	}, {
+		name: "skip verifying the unmatched server name",
+		tls:  &atest.TLS{Cert: "tls/client.pem", Key: "tls/client.key", CA: "tls/ca.pem", ServerName: "fake", SkipVerify: &yes},
+	}, {
+		name:      "verify with the system CAs",
+		tls:       &atest.TLS{Cert: "tls/client.pem", Key: "tls/client.key", SkipVerify: &no},
+		expectErr: "unknown authority",
+	}, {
+		name: "min version",
+		tls:  &atest.TLS{Cert: "tls/client.pem", Key: "tls/client.key", MinVersion: "1.3"},
+	}, {
+		name:      "invalid min version",
+		tls:       &atest.TLS{MinVersion: "2.0"},
+		expectErr: "invalid min TLS version",
+	}, {
+		name:      "invalid client certificate",
+		tls:       &atest.TLS{Cert: "tls/ca.pem", Key: "tls/client.key"},
+		expectErr: "failed to load the client certificate",
+	}, {
+		name:      "invalid CA",
+		tls:       &atest.TLS{CA: "tls/client.key"},
+		expectErr: "invalid certificate authority",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.TODO(), NewContextKeyBuilder().ParentDir(), "testdata")
+			_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+				Request: atest.Request{API: server.URL, TLS: tt.tls},
+				Expect:  atest.Response{BodyFieldsExpect: map[string]interface{}{"client": "atest-client"}},
+			}, nil, ctx)
+			if tt.expectErr == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	config, err := newTLSConfig(nil, "")
+	assert.Nil(t, err)
+	assert.True(t, config.InsecureSkipVerify)
+
+	no := false
+	config, err = newTLSConfig(&atest.TLS{SkipVerify: &no, MinVersion: "1.2"}, "")
+	assert.Nil(t, err)
+	assert.False(t, config.InsecureSkipVerify)
+	assert.Nil(t, config.RootCAs)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+}
@@ -0,0 +1,248 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/secret"
+)
+
+// SpanKind is the kind of the span in OpenTelemetry
+type SpanKind int
+
+const (
+	// SpanKindInternal is the span of the test suites and the test cases
+	SpanKindInternal SpanKind = 1
+	// SpanKindClient is the span of the requests
+	SpanKindClient SpanKind = 3
+)
+
+// maxBufferedSpans is the count of the ended spans which triggers exporting
+const maxBufferedSpans = 512
+
+// Tracer records the spans of the test suites, the test cases and the requests
+type Tracer interface {
+	// Start starts a span which is the child of the span in the context
+	Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span)
+	// Flush exports the ended spans
+	Flush() error
+}
+
+// Span is an operation of a trace, the methods of a nil span do nothing
+type Span struct {
+	tracer     *otlpTracer
+	traceID    [16]byte
+	spanID     [8]byte
+	parentID   [8]byte
+	name       string
+	kind       SpanKind
+	start      time.Time
+	end        time.Time
+	attributes Fields
+	err        error
+	lock       sync.Mutex
+}
+
+// Tracer returns the key of the tracer
+func (c ContextKey) Tracer() ContextKey {
+	return ContextKey("tracer")
+}
+
+// Span returns the key of the current span
+func (c ContextKey) Span() ContextKey {
+	return ContextKey("span")
+}
+
+// WithTracer returns a context with the tracer, the spans are started by the runners with it
+func WithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, NewContextKeyBuilder().Tracer(), tracer)
+}
+
+// StartSpan starts a span with the tracer of the context, the span is nil if there is no tracer
+func StartSpan(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if tracer, ok := ctx.Value(NewContextKeyBuilder().Tracer()).(Tracer); ok {
+		return tracer.Start(ctx, name, kind)
+	}
+	return ctx, nil
+}
+
+// SetAttributes puts the attributes into the span
+func (s *Span) SetAttributes(attributes Fields) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for key, val := range attributes {
+		s.attributes[key] = val
+	}
+}
+
+// End ends the span, the status of the span is error if the err is not nil
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	s.end, s.err = time.Now(), err
+	s.lock.Unlock()
+	s.tracer.put(s)
+}
+
+// TraceParent returns the W3C trace context of the span, such as: 00-<trace id>-<span id>-01
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(s.traceID[:]), hex.EncodeToString(s.spanID[:]))
+}
+
+type otlpTracer struct {
+	endpoint    string
+	serviceName string
+	lock        sync.Mutex
+	spans       []*Span
+}
+
+// NewOTLPTracer creates a tracer which exports the spans to the OTLP/HTTP endpoint in JSON,
+// such as: http://localhost:4318/v1/traces of Jaeger or Tempo
+func NewOTLPTracer(endpoint, serviceName string) Tracer {
+	return &otlpTracer{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+	}
+}
+
+// Start starts a span which is the child of the span in the context, or a root span of a new trace
+func (t *otlpTracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	span := &Span{
+		tracer:     t,
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		attributes: Fields{},
+	}
+	if parent, ok := ctx.Value(NewContextKeyBuilder().Span()).(*Span); ok {
+		span.traceID, span.parentID = parent.traceID, parent.spanID
+	} else {
+		_, _ = rand.Read(span.traceID[:])
+	}
+	_, _ = rand.Read(span.spanID[:])
+	return context.WithValue(ctx, NewContextKeyBuilder().Span(), span), span
+}
+
+func (t *otlpTracer) put(span *Span) {
+	t.lock.Lock()
+	t.spans = append(t.spans, span)
+	full := len(t.spans) >= maxBufferedSpans
+	t.lock.Unlock()
+
+	if full {
+		_ = t.Flush()
+	}
+}
+
+// Flush exports the ended spans, they're dropped if it's failed
+func (t *otlpTracer) Flush() (err error) {
+	t.lock.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.lock.Unlock()
+	if len(spans) == 0 {
+		return
+	}
+
+	var data []byte
+	if data, err = json.Marshal(t.payload(spans)); err != nil {
+		return
+	}
+
+	var resp *http.Response
+	if resp, err = http.Post(t.endpoint, "application/json", bytes.NewReader(data)); err != nil {
+		err = fmt.Errorf("failed to export the spans: %v", err)
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(resp.Body)
+		err = fmt.Errorf("failed to export the spans, unexpected status code %d from %s, %s", resp.StatusCode, t.endpoint, string(body))
+	}
+	return
+}
+
+// payload is the ExportTraceServiceRequest of OTLP in JSON, the IDs are in hex and the 64-bit integers are strings.
+// The secrets are redacted from the attributes and the errors
+func (t *otlpTracer) payload(spans []*Span) map[string]interface{} {
+	items := make([]map[string]interface{}, len(spans))
+	for i, span := range spans {
+		span.lock.Lock()
+		item := map[string]interface{}{
+			"traceId":           hex.EncodeToString(span.traceID[:]),
+			"spanId":            hex.EncodeToString(span.spanID[:]),
+			"name":              span.name,
+			"kind":              span.kind,
+			"startTimeUnixNano": strconv.FormatInt(span.start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(span.end.UnixNano(), 10),
+			"attributes":        otlpAttributes(span.attributes),
+			"status":            map[string]interface{}{"code": 1},
+		}
+		if span.parentID != [8]byte{} {
+			item["parentSpanId"] = hex.EncodeToString(span.parentID[:])
+		}
+		if span.err != nil {
+			item["status"] = map[string]interface{}{"code": 2, "message": secret.Redact(span.err.Error())}
+		}
+		span.lock.Unlock()
+		items[i] = item
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": otlpAttributes(Fields{"service.name": t.serviceName}),
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": "github.com/linuxsuren/api-testing"},
+				"spans": items,
+			}},
+		}},
+	}
+}
+
+func otlpAttributes(attributes Fields) []interface{} {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]interface{}, len(keys))
+	for i, key := range keys {
+		var value map[string]interface{}
+		switch val := attributes[key].(type) {
+		case int:
+			value = map[string]interface{}{"intValue": strconv.Itoa(val)}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(val, 10)}
+		case bool:
+			value = map[string]interface{}{"boolValue": val}
+		default:
+			value = map[string]interface{}{"stringValue": secret.Redact(fmt.Sprintf("%v", val))}
+		}
+		result[i] = map[string]interface{}{"key": key, "value": value}
+	}
+	return result
+}
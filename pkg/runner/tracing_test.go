@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracing(t *testing.T) {
+	defer gock.Off()
+
+	var traceParent string
+	gock.New(urlLocalhost).Get("/foo").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			traceParent = req.Header.Get("traceparent")
+			return true, nil
+		}).Reply(http.StatusOK).JSON(`{}`)
+
+	tracer := NewOTLPTracer(urlLocalhost+"/v1/traces", "atest")
+	ctx, suiteSpan := StartSpan(WithTracer(context.TODO(), tracer), "suite", SpanKindInternal)
+	_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Name:    "foo",
+		Request: atest.Request{API: urlFoo},
+	}, nil, ctx)
+	assert.Nil(t, err)
+	suiteSpan.End(errors.New("fake"))
+
+	var payload struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID      string `json:"traceId"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+					Kind         int    `json:"kind"`
+					Status       struct {
+						Code    int    `json:"code"`
+						Message string `json:"message"`
+					} `json:"status"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	gock.New(urlLocalhost).Post("/v1/traces").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			data, err := io.ReadAll(req.Body)
+			if err == nil {
+				err = json.Unmarshal(data, &payload)
+			}
+			return err == nil, err
+		}).Reply(http.StatusOK)
+	assert.Nil(t, tracer.Flush())
+
+	spans := payload.ResourceSpans[0].ScopeSpans[0].Spans
+	if assert.Equal(t, 3, len(spans)) {
+		request, testCase, suite := spans[0], spans[1], spans[2]
+		assert.Equal(t, "GET", request.Name)
+		assert.Equal(t, int(SpanKindClient), request.Kind)
+		assert.Equal(t, "foo", testCase.Name)
+		assert.Equal(t, "suite", suite.Name)
+
+		assert.Equal(t, suite.TraceID, testCase.TraceID)
+		assert.Equal(t, suite.TraceID, request.TraceID)
+		assert.Empty(t, suite.ParentSpanID)
+		assert.Equal(t, suite.SpanID, testCase.ParentSpanID)
+		assert.Equal(t, testCase.SpanID, request.ParentSpanID)
+		assert.Equal(t, "00-"+request.TraceID+"-"+request.SpanID+"-01", traceParent)
+
+		assert.Equal(t, 1, testCase.Status.Code)
+		assert.Equal(t, 2, suite.Status.Code)
+		assert.Equal(t, "fake", suite.Status.Message)
+	}
+
+	// nothing to export
+	assert.Nil(t, tracer.Flush())
+
+	gock.New(urlLocalhost).Post("/v1/traces").Reply(http.StatusBadRequest)
+	_, span := tracer.Start(context.TODO(), "fake", SpanKindInternal)
+	span.End(nil)
+	assert.ErrorContains(t, tracer.Flush(), "unexpected status code 400")
+}
+
+func TestNilSpan(t *testing.T) {
+	ctx, span := StartSpan(context.TODO(), "fake", SpanKindInternal)
+	assert.Nil(t, span)
+	assert.Equal(t, context.TODO(), ctx)
+
+	span.SetAttributes(Fields{"key": "value"})
+	span.End(nil)
+	assert.Empty(t, span.TraceParent())
+}
+
+func TestOTLPAttributes(t *testing.T) {
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "bool", "value": map[string]interface{}{"boolValue": true}},
+		map[string]interface{}{"key": "int", "value": map[string]interface{}{"intValue": "1"}},
+		map[string]interface{}{"key": "int64", "value": map[string]interface{}{"intValue": "2"}},
+		map[string]interface{}{"key": "string", "value": map[string]interface{}{"stringValue": "foo"}},
+	}, otlpAttributes(Fields{"string": "foo", "int": 1, "int64": int64(2), "bool": true}))
+}
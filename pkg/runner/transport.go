@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const (
+	defaultMaxIdleConns    = 100
+	defaultIdleConnTimeout = 90 * time.Second
+)
+
+// transportCache keeps the transports of the different options, the idle connections of a transport are
+// reused by the test cases which have the same TLS, network, resolve, proxy, protocol and pool options
+type transportCache struct {
+	lock  sync.Mutex
+	items map[string]http.RoundTripper
+}
+
+var defaultTransportCache = &transportCache{items: map[string]http.RoundTripper{}}
+
+// getTransport returns the transport of the options of the request, it's created once for the same options.
+// The nil transport means http.DefaultTransport
+func getTransport(req *testing.Request, scheme, contextDir string) (transport http.RoundTripper, err error) {
+	// TODO only do this for unit testing, should remove it once we have a better way
+	if scheme == "http" && req.Network == nil && req.Proxy == nil && req.Protocol == "" && req.Pool == nil && len(req.Resolve) == 0 {
+		return
+	}
+
+	key := transportKey(req, scheme, contextDir)
+	defaultTransportCache.lock.Lock()
+	defer defaultTransportCache.lock.Unlock()
+	var ok bool
+	if transport, ok = defaultTransportCache.items[key]; !ok {
+		if transport, err = newTransport(req, scheme, contextDir); err == nil {
+			defaultTransportCache.items[key] = transport
+		}
+	}
+	return
+}
+
+// transportKey returns the key of the options which decide the transport
+func transportKey(req *testing.Request, scheme, contextDir string) string {
+	data, _ := json.Marshal([]interface{}{scheme, contextDir, req.TLS, req.Network, req.Proxy, req.Protocol, req.Pool, req.Resolve})
+	return string(data)
+}
+
+// newTransport creates the transport with the TLS, the network, the resolve, the proxy, the protocol and the pool options
+func newTransport(req *testing.Request, scheme, contextDir string) (transport http.RoundTripper, err error) {
+	var tlsConfig *tls.Config
+	if tlsConfig, err = newTLSConfig(req.TLS, contextDir); err != nil {
+		return
+	}
+	if req.Protocol != "" {
+		return newProtocolTransport(req, scheme, tlsConfig)
+	}
+
+	httpTransport := &http.Transport{TLSClientConfig: tlsConfig}
+	if req.Network != nil || req.Proxy != nil {
+		httpTransport.Proxy = http.ProxyFromEnvironment
+	}
+	if httpTransport.DialContext, err = newRequestDial(req); err != nil {
+		return
+	}
+	if req.Proxy != nil {
+		if httpTransport.Proxy, err = newProxyFunc(req.Proxy); err != nil {
+			return
+		}
+	}
+	if err = setPool(httpTransport, req.Pool); err == nil {
+		transport = httpTransport
+	}
+	return
+}
+
+// setPool sets the idle connections of the transport, the defaults are the same as http.DefaultTransport
+func setPool(transport *http.Transport, pool *testing.Pool) (err error) {
+	if pool == nil {
+		pool = &testing.Pool{}
+	}
+
+	transport.MaxIdleConns = pool.MaxIdleConns
+	if transport.MaxIdleConns <= 0 {
+		transport.MaxIdleConns = defaultMaxIdleConns
+	}
+	transport.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+	transport.DisableKeepAlives = pool.DisableKeepAlives
+	if transport.IdleConnTimeout, err = parseDurationOrDefault(pool.IdleConnTimeout, defaultIdleConnTimeout); err != nil {
+		err = fmt.Errorf("invalid idle connection timeout: %v", err)
+	}
+	return
+}
+
+// newRequestDial returns the dial function of the network and the resolve options, it's nil if there is neither of them
+func newRequestDial(req *testing.Request) (dial dialFunc, err error) {
+	if req.Network != nil {
+		if dial, err = newDialContext(req.Network); err != nil {
+			return
+		}
+	}
+	if len(req.Resolve) > 0 {
+		dial, err = newResolveDial(req.Resolve, dial)
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTransport(t *testing.T) {
+	transport, err := getTransport(&atest.Request{}, "http", "")
+	assert.NoError(t, err)
+	assert.Nil(t, transport, "the default transport is used")
+
+	pool := &atest.Pool{MaxIdleConns: 10, MaxIdleConnsPerHost: 5, IdleConnTimeout: "30s", DisableKeepAlives: true}
+	transport, err = getTransport(&atest.Request{Pool: pool}, "https", "")
+	assert.NoError(t, err)
+	if httpTransport, ok := transport.(*http.Transport); assert.True(t, ok) {
+		assert.Equal(t, 10, httpTransport.MaxIdleConns)
+		assert.Equal(t, 5, httpTransport.MaxIdleConnsPerHost)
+		assert.Equal(t, 30*time.Second, httpTransport.IdleConnTimeout)
+		assert.True(t, httpTransport.DisableKeepAlives)
+	}
+
+	same, err := getTransport(&atest.Request{Pool: &atest.Pool{MaxIdleConns: 10, MaxIdleConnsPerHost: 5, IdleConnTimeout: "30s", DisableKeepAlives: true}}, "https", "")
+	assert.NoError(t, err)
+	assert.Same(t, transport, same, "the transport of the same options is reused")
+
+	other, err := getTransport(&atest.Request{}, "https", "")
+	assert.NoError(t, err)
+	assert.NotSame(t, transport, other)
+	if httpTransport, ok := other.(*http.Transport); assert.True(t, ok) {
+		assert.Equal(t, defaultMaxIdleConns, httpTransport.MaxIdleConns)
+		assert.Equal(t, defaultIdleConnTimeout, httpTransport.IdleConnTimeout)
+	}
+
+	_, err = getTransport(&atest.Request{Pool: &atest.Pool{IdleConnTimeout: "fake"}}, "https", "")
+	assert.ErrorContains(t, err, "invalid idle connection timeout")
+}
+
+func TestReusedConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	for _, keepAlive := range []bool{true, false} {
+		reporter := NewMemoryTestReporter()
+		for i := 0; i < 2; i++ {
+			_, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+				Request: atest.Request{API: server.URL, Pool: &atest.Pool{DisableKeepAlives: !keepAlive}},
+			}, nil, context.TODO())
+			assert.NoError(t, err)
+		}
+
+		records := reporter.GetAllRecords()
+		if assert.Len(t, records, 2) {
+			assert.False(t, records[0].Reused)
+			assert.Equal(t, keepAlive, records[1].Reused, "the idle connection is reused by the next test case")
+		}
+		results, err := reporter.ExportAllReportResults()
+		assert.NoError(t, err)
+		if assert.Len(t, results, 1) && keepAlive {
+			assert.Equal(t, 1, results[0].Reused)
+		}
+	}
+}
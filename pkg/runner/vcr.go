@@ -0,0 +1,333 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/linuxsuren/api-testing/pkg/secret"
+	"github.com/linuxsuren/api-testing/pkg/util"
+)
+
+const (
+	// CassetteRecord sends the requests, and records the responses into the cassette
+	CassetteRecord = "record"
+	// CassetteReplay serves the recorded responses without sending the requests
+	CassetteReplay = "replay"
+)
+
+// Cassette keeps the HTTP interactions of a test suite in a file, the requests are matched by the method,
+// the URL and the body. The same requests are replayed in the order of recording
+type Cassette interface {
+	// RoundTripper wraps the transport which sends the requests in the record mode
+	RoundTripper(next http.RoundTripper) http.RoundTripper
+	// Save writes the recorded interactions into the file, it does nothing in the replay mode
+	Save() error
+	// Drifts returns the differences between the recorded responses and the previous ones of the same requests,
+	// such as: the status code, the content type and the fields of the JSON body
+	Drifts() []string
+}
+
+type cassetteFile struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  interactionRequest  `json:"request"`
+	Response interactionResponse `json:"response"`
+}
+
+type interactionRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type interactionResponse struct {
+	StatusCode int                 `json:"statusCode"`
+	Header     map[string][]string `json:"header,omitempty"`
+	Body       string              `json:"body,omitempty"`
+}
+
+func (r interactionRequest) key() string {
+	return r.Method + " " + r.URL + "\n" + r.Body
+}
+
+type cassette struct {
+	file     string
+	mode     string
+	lock     sync.Mutex
+	previous []interaction
+	recorded []interaction
+	replayed map[string]int
+	drifts   []string
+}
+
+// NewCassette creates a cassette of the file in the mode record or replay, the file should exist in the replay mode
+func NewCassette(file, mode string) (result Cassette, err error) {
+	c := &cassette{file: file, mode: mode, replayed: map[string]int{}}
+	switch mode {
+	case CassetteRecord, CassetteReplay:
+	default:
+		err = fmt.Errorf("not supported cassette mode: '%s'", mode)
+		return
+	}
+
+	var data []byte
+	if data, err = os.ReadFile(file); err == nil {
+		content := &cassetteFile{}
+		if err = yaml.Unmarshal(data, content); err != nil {
+			err = fmt.Errorf("invalid cassette %s: %v", file, err)
+			return
+		}
+		c.previous = content.Interactions
+	} else if os.IsNotExist(err) && mode == CassetteRecord {
+		err = nil
+	}
+	if err == nil {
+		result = c
+	}
+	return
+}
+
+// WithCassette returns a context with the cassette, the HTTP requests are recorded or replayed by it
+func WithCassette(ctx context.Context, cassette Cassette) context.Context {
+	return context.WithValue(ctx, NewContextKeyBuilder().Cassette(), cassette)
+}
+
+// Cassette returns the key of the cassette
+func (c ContextKey) Cassette() ContextKey {
+	return ContextKey("cassette")
+}
+
+// getCassette returns the cassette of the context, or nil if there is not
+func getCassette(ctx context.Context) Cassette {
+	cassette, _ := ctx.Value(NewContextKeyBuilder().Cassette()).(Cassette)
+	return cassette
+}
+
+type cassetteRoundTripper func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f cassetteRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// RoundTripper records or replays the interactions
+func (c *cassette) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return cassetteRoundTripper(func(request *http.Request) (resp *http.Response, err error) {
+		var body []byte
+		if request.Body != nil {
+			if body, err = io.ReadAll(request.Body); err != nil {
+				return
+			}
+			_ = request.Body.Close()
+			request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		req := interactionRequest{
+			Method: request.Method,
+			URL:    secret.Redact(request.URL.String()),
+			Body:   secret.Redact(string(body)),
+		}
+
+		if c.mode == CassetteReplay {
+			return c.replay(request, req)
+		}
+
+		if resp, err = next.RoundTrip(request); err != nil {
+			return
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		var respBody []byte
+		if respBody, err = io.ReadAll(resp.Body); err != nil {
+			return
+		}
+		c.record(interaction{Request: req, Response: interactionResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       secret.Redact(string(respBody)),
+		}})
+
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		return
+	})
+}
+
+// replay returns the recorded response of the request, the last one is returned repeatedly once all are replayed
+func (c *cassette) replay(request *http.Request, req interactionRequest) (resp *http.Response, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := req.key()
+	matched := findInteractions(c.previous, key)
+	if len(matched) == 0 {
+		err = fmt.Errorf("no recorded response of %s %s in the cassette %s", req.Method, req.URL, c.file)
+		return
+	}
+	index := c.replayed[key]
+	if index >= len(matched) {
+		index = len(matched) - 1
+	}
+	c.replayed[key]++
+
+	recorded := matched[index].Response
+	resp = &http.Response{
+		StatusCode:    recorded.StatusCode,
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header(recorded.Header).Clone(),
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       request,
+	}
+	if resp.Header == nil {
+		resp.Header = http.Header{}
+	}
+	return
+}
+
+// record keeps the interaction, and compares it with the previous one of the same request
+func (c *cassette) record(item interaction) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := item.Request.key()
+	index := len(findInteractions(c.recorded, key))
+	c.recorded = append(c.recorded, item)
+	if previous := findInteractions(c.previous, key); index < len(previous) {
+		prefix := item.Request.Method + " " + item.Request.URL
+		for _, drift := range compareResponses(previous[index].Response, item.Response) {
+			c.drifts = append(c.drifts, prefix+": "+drift)
+		}
+	}
+}
+
+// Save writes the recorded interactions into the file
+func (c *cassette) Save() (err error) {
+	if c.mode != CassetteRecord {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var data []byte
+	if data, err = yaml.Marshal(&cassetteFile{Interactions: c.recorded}); err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(c.file), 0755); err == nil {
+		err = os.WriteFile(c.file, data, 0644)
+	}
+	return
+}
+
+// Drifts returns the differences of the responses
+func (c *cassette) Drifts() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]string{}, c.drifts...)
+}
+
+func findInteractions(items []interaction, key string) (result []interaction) {
+	for _, item := range items {
+		if item.Request.key() == key {
+			result = append(result, item)
+		}
+	}
+	return
+}
+
+// compareResponses returns the differences of the status code, the content type and the shape of the JSON body.
+// The values of the fields are not compared, they're likely to be different between the recordings
+func compareResponses(previous, current interactionResponse) (drifts []string) {
+	if previous.StatusCode != current.StatusCode {
+		drifts = append(drifts, fmt.Sprintf("status code %d -> %d", previous.StatusCode, current.StatusCode))
+	}
+
+	previousType := http.Header(previous.Header).Get(util.ContentType)
+	currentType := http.Header(current.Header).Get(util.ContentType)
+	if previousType != currentType {
+		drifts = append(drifts, fmt.Sprintf("content type %q -> %q", previousType, currentType))
+	}
+
+	previousShape, currentShape := map[string]string{}, map[string]string{}
+	if !jsonShape(previous.Body, previousShape) || !jsonShape(current.Body, currentShape) {
+		return
+	}
+	for _, field := range sortedFields(previousShape, currentShape) {
+		before, after := previousShape[field], currentShape[field]
+		switch {
+		case after == "":
+			drifts = append(drifts, fmt.Sprintf("field %s is removed", field))
+		case before == "":
+			drifts = append(drifts, fmt.Sprintf("field %s is added", field))
+		case before != after:
+			drifts = append(drifts, fmt.Sprintf("field %s %s -> %s", field, before, after))
+		}
+	}
+	return
+}
+
+// jsonShape collects the paths and the types of the fields, the items of an array are merged into the path []
+func jsonShape(body string, shape map[string]string) bool {
+	var data interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return false
+	}
+	collectShape("$", data, shape)
+	return true
+}
+
+func collectShape(path string, data interface{}, shape map[string]string) {
+	switch val := data.(type) {
+	case map[string]interface{}:
+		shape[path] = "object"
+		for key, item := range val {
+			collectShape(path+"."+key, item, shape)
+		}
+	case []interface{}:
+		shape[path] = "array"
+		for _, item := range val {
+			collectShape(path+"[]", item, shape)
+		}
+	case string:
+		shape[path] = "string"
+	case float64:
+		shape[path] = "number"
+	case bool:
+		shape[path] = "boolean"
+	case nil:
+		if _, ok := shape[path]; !ok {
+			shape[path] = "null"
+		}
+	}
+}
+
+func sortedFields(shapes ...map[string]string) (fields []string) {
+	keys := map[string]bool{}
+	for _, shape := range shapes {
+		for key := range shape {
+			if !keys[key] {
+				keys[key] = true
+				fields = append(fields, key)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return
+}
@@ -0,0 +1,154 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCassette(t *testing.T) {
+	file := path.Join(t.TempDir(), "cassettes", "sample.yaml")
+	send := func(cassette Cassette, method, body string) (status int, respBody string, err error) {
+		client := http.Client{Transport: cassette.RoundTripper(nil)}
+		var request *http.Request
+		if request, err = http.NewRequest(method, urlFoo+"/users", strings.NewReader(body)); err != nil {
+			return
+		}
+		var resp *http.Response
+		if resp, err = client.Do(request); err == nil {
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			var data []byte
+			data, err = io.ReadAll(resp.Body)
+			status, respBody = resp.StatusCode, string(data)
+		}
+		return
+	}
+
+	t.Run("replay without the cassette", func(t *testing.T) {
+		_, err := NewCassette(file, CassetteReplay)
+		assert.Error(t, err)
+	})
+
+	t.Run("record", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Get("/users").Reply(http.StatusOK).JSON(`[{"name":"linuxsuren","age":1}]`)
+		gock.New(urlFoo).Get("/users").Reply(http.StatusOK).JSON(`[]`)
+		gock.New(urlFoo).Post("/users").BodyString("linuxsuren").Reply(http.StatusCreated)
+
+		cassette, err := NewCassette(file, CassetteRecord)
+		assert.NoError(t, err)
+		for _, method := range []string{http.MethodGet, http.MethodGet, http.MethodPost} {
+			_, _, err = send(cassette, method, "linuxsuren")
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, cassette.Save())
+		assert.Empty(t, cassette.Drifts())
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		cassette, err := NewCassette(file, CassetteReplay)
+		assert.NoError(t, err)
+
+		status, body, err := send(cassette, http.MethodGet, "linuxsuren")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `[{"name":"linuxsuren","age":1}]`, body)
+
+		// the last one is replayed repeatedly
+		for i := 0; i < 2; i++ {
+			_, body, err = send(cassette, http.MethodGet, "linuxsuren")
+			assert.NoError(t, err)
+			assert.Equal(t, `[]`, body)
+		}
+
+		status, _, err = send(cassette, http.MethodPost, "linuxsuren")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, status)
+
+		_, _, err = send(cassette, http.MethodPost, "fake")
+		assert.Error(t, err)
+		assert.NoError(t, cassette.Save())
+	})
+
+	t.Run("record again with the drifts", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Get("/users").Reply(http.StatusOK).JSON(`[{"name":"linuxsuren","age":"1"}]`)
+		gock.New(urlFoo).Get("/users").Reply(http.StatusOK).JSON(`[]`)
+		gock.New(urlFoo).Post("/users").Reply(http.StatusOK)
+
+		cassette, err := NewCassette(file, CassetteRecord)
+		assert.NoError(t, err)
+		for _, method := range []string{http.MethodGet, http.MethodGet, http.MethodPost} {
+			_, _, err = send(cassette, method, "linuxsuren")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, []string{
+			"GET http://localhost/foo/users: field $[].age number -> string",
+			"POST http://localhost/foo/users: status code 201 -> 200",
+		}, cassette.Drifts())
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := NewCassette(file, "fake")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid cassette", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(file, []byte("fake"), 0644))
+		_, err := NewCassette(file, CassetteReplay)
+		assert.Error(t, err)
+	})
+}
+
+func TestCassetteOfContext(t *testing.T) {
+	assert.Nil(t, getCassette(context.Background()))
+
+	cassette, err := NewCassette(path.Join(t.TempDir(), "sample.yaml"), CassetteRecord)
+	assert.NoError(t, err)
+	assert.Equal(t, cassette, getCassette(WithCassette(context.Background(), cassette)))
+}
+
+func TestCompareResponses(t *testing.T) {
+	tests := []struct {
+		name              string
+		previous, current interactionResponse
+		expect            []string
+	}{{
+		name:     "same",
+		previous: interactionResponse{StatusCode: 200, Body: `{"name":"a"}`},
+		current:  interactionResponse{StatusCode: 200, Body: `{"name":"b"}`},
+	}, {
+		name:     "content type",
+		previous: interactionResponse{StatusCode: 200, Header: map[string][]string{"Content-Type": {"application/json"}}},
+		current:  interactionResponse{StatusCode: 200, Header: map[string][]string{"Content-Type": {"text/plain"}}},
+		expect:   []string{`content type "application/json" -> "text/plain"`},
+	}, {
+		name:     "fields",
+		previous: interactionResponse{StatusCode: 200, Body: `{"name":"a","tags":[],"meta":null}`},
+		current:  interactionResponse{StatusCode: 200, Body: `{"age":1,"tags":[true],"meta":{}}`},
+		expect: []string{
+			"field $.age is added",
+			"field $.meta null -> object",
+			"field $.name is removed",
+			"field $.tags[] is added",
+		},
+	}, {
+		name:     "not JSON",
+		previous: interactionResponse{StatusCode: 200, Body: "a"},
+		current:  interactionResponse{StatusCode: 200, Body: `{}`},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, compareResponses(tt.previous, tt.current))
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	"github.com/linuxsuren/api-testing/pkg/render"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const (
+	defaultProbeInterval = time.Second
+	defaultProbeTimeout  = time.Minute
+)
+
+// waitProbe blocks until the probe is ready or timeout
+func (r *simpleTestCaseRunner) waitProbe(ctx context.Context, probe testing.Probe, contextDir string, dataContext interface{}) (err error) {
+	var interval, timeout time.Duration
+	if interval, err = parseDurationOrDefault(probe.Interval, defaultProbeInterval); err != nil {
+		return
+	}
+	if timeout, err = parseDurationOrDefault(probe.Timeout, defaultProbeTimeout); err != nil {
+		return
+	}
+
+	var ready func(context.Context) bool
+	var target string
+	switch {
+	case probe.HTTP != "":
+		if target, err = render.Render("probe", probe.HTTP, dataContext); err != nil {
+			return
+		}
+		ready = func(ctx context.Context) bool {
+			return httpReady(ctx, target, probe.Status)
+		}
+	case probe.TCP != "":
+		if target, err = render.Render("probe", probe.TCP, dataContext); err != nil {
+			return
+		}
+		ready = func(ctx context.Context) bool {
+			return tcpReady(ctx, target)
+		}
+	case probe.Command != nil:
+		target = probe.Command.Command
+		cmd := exec.Command{
+			Name: probe.Command.Command,
+			Args: probe.Command.Args,
+			Env:  probe.Command.Env,
+			Dir:  resolvePath(contextDir, probe.Command.Dir),
+		}
+		ready = func(ctx context.Context) bool {
+			exitCode, runErr := r.executor.Run(ctx, cmd, new(bytes.Buffer))
+			return runErr == nil && expectExitCode(probe.Command.ExitCodes, exitCode)
+		}
+	default:
+		err = fmt.Errorf("one of http, tcp, and command is required in the probe '%s'", probe.Name)
+		return
+	}
+
+	name := probe.Name
+	if name == "" {
+		name = target
+	}
+	r.log.Info("prepare: wait for %s\n", name)
+	if err = waitUntil(ctx, interval, timeout, ready); err != nil {
+		err = fmt.Errorf("%s is not ready: %v", name, err)
+	}
+	return
+}
+
+// waitUntil checks the readiness every interval until it's ready, or timeout
+func waitUntil(ctx context.Context, interval, timeout time.Duration, ready func(context.Context) bool) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for !ready(ctx) {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout after %v", timeout)
+		case <-time.After(interval):
+		}
+	}
+	return nil
+}
+
+// httpReady checks if the response status code is the expected one, any 2xx status code is expected if it's zero
+func httpReady(ctx context.Context, api string, status int) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api, nil)
+	if err != nil {
+		return false
+	}
+
+	var resp *http.Response
+	if resp, err = http.DefaultClient.Do(req); err == nil {
+		_ = resp.Body.Close()
+		if status != 0 {
+			return resp.StatusCode == status
+		}
+		return resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices
+	}
+	return false
+}
+
+func tcpReady(ctx context.Context, address string) bool {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err == nil {
+		_ = conn.Close()
+	}
+	return err == nil
+}
+
+func parseDurationOrDefault(text string, defVal time.Duration) (duration time.Duration, err error) {
+	if duration = defVal; text != "" {
+		if duration, err = time.ParseDuration(text); err != nil {
+			err = fmt.Errorf("invalid duration '%s': %v", text, err)
+		}
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/exec"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitProbe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer listener.Close()
+
+	tests := []struct {
+		name        string
+		probe       atest.Probe
+		executor    *exec.FakeExecutor
+		dataContext interface{}
+		prepare     func()
+		hasErr      bool
+	}{{
+		name:  "http",
+		probe: atest.Probe{HTTP: urlFoo, Interval: "10ms"},
+		prepare: func() {
+			gock.New(urlLocalhost).Get("/foo").Reply(http.StatusServiceUnavailable)
+			gock.New(urlLocalhost).Get("/foo").Reply(http.StatusNoContent)
+		},
+	}, {
+		name:  "http with the expected status",
+		probe: atest.Probe{HTTP: "{{.api}}", Status: http.StatusUnauthorized},
+		prepare: func() {
+			gock.New(urlLocalhost).Get("/foo").Reply(http.StatusUnauthorized)
+		},
+		dataContext: map[string]interface{}{"api": urlFoo},
+	}, {
+		name:  "http timeout",
+		probe: atest.Probe{HTTP: urlFoo, Interval: "10ms", Timeout: "50ms"},
+		prepare: func() {
+			gock.New(urlLocalhost).Get("/foo").Persist().Reply(http.StatusInternalServerError)
+		},
+		hasErr: true,
+	}, {
+		name:  "tcp",
+		probe: atest.Probe{Name: "listener", TCP: listener.Addr().String()},
+	}, {
+		name:     "command",
+		probe:    atest.Probe{Command: &atest.Command{Command: "pg_isready", ExitCodes: []int{0, 1}}},
+		executor: &exec.FakeExecutor{ExitCode: 1},
+	}, {
+		name:     "command timeout",
+		probe:    atest.Probe{Command: &atest.Command{Command: "pg_isready"}, Interval: "10ms", Timeout: "50ms"},
+		executor: &exec.FakeExecutor{ExitCode: 2},
+		hasErr:   true,
+	}, {
+		name:   "no target",
+		probe:  atest.Probe{Name: "fake"},
+		hasErr: true,
+	}, {
+		name:   "invalid interval",
+		probe:  atest.Probe{TCP: "localhost:3306", Interval: "fake"},
+		hasErr: true,
+	}, {
+		name:   "invalid timeout",
+		probe:  atest.Probe{TCP: "localhost:3306", Timeout: "fake"},
+		hasErr: true,
+	}, {
+		name:   "invalid template",
+		probe:  atest.Probe{HTTP: "{{.fake"},
+		hasErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+
+			runner := NewSimpleTestCaseRunner().(*simpleTestCaseRunner)
+			if tt.executor != nil {
+				runner.executor = tt.executor
+			}
+			err := runner.waitProbe(context.TODO(), tt.probe, "", tt.dataContext)
+			assert.Equal(t, tt.hasErr, err != nil, err)
+		})
+	}
+}
+
+func TestWaitUntil(t *testing.T) {
+	count := 0
+	err := waitUntil(context.TODO(), time.Millisecond, time.Second, func(ctx context.Context) bool {
+		count++
+		return count == 3
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	err = waitUntil(context.TODO(), time.Millisecond, 10*time.Millisecond, func(ctx context.Context) bool {
+		return false
+	})
+	assert.NotNil(t, err)
+}
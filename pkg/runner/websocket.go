@@ -0,0 +1,146 @@
+package runner
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/andreyvit/diff"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"golang.org/x/net/websocket"
+)
+
+const defaultWebSocketTimeout = 10 * time.Second
+
+// runWebSocket opens the connection of the test case, then sends and receives the messages one by one.
+// The output is the last received message.
+func (r *simpleTestCaseRunner) runWebSocket(ctx context.Context, testcase *testing.TestCase, dataContext interface{},
+	contextDir string, record *ReportRecord) (output interface{}, err error) {
+	request := &testcase.Request
+	if err = request.Render(dataContext, contextDir); err != nil {
+		return
+	}
+
+	timeout := defaultWebSocketTimeout
+	if request.WebSocket.Timeout != "" {
+		if timeout, err = time.ParseDuration(request.WebSocket.Timeout); err != nil {
+			err = fmt.Errorf("invalid timeout of the WebSocket: %v", err)
+			return
+		}
+	}
+
+	// the steps are copied by the rendering, but the expected messages are still shared
+	for i, step := range request.WebSocket.Steps {
+		if step.Receive != nil {
+			receive := *step.Receive
+			if receive.Schema, err = loadSchema(contextDir, receive.Schema); err != nil {
+				return
+			}
+			request.WebSocket.Steps[i].Receive = &receive
+		}
+	}
+
+	r.log.Info("start to connect %s\n", request.API)
+
+	var tlsConfig *tls.Config
+	if tlsConfig, err = newTLSConfig(request.TLS, contextDir); err != nil {
+		return
+	}
+
+	var conn *websocket.Conn
+	if conn, err = dialWebSocket(request, timeout, tlsConfig); err != nil {
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	var received []string
+	defer func() {
+		record.Body = strings.Join(received, "\n")
+	}()
+	for i, step := range request.WebSocket.Steps {
+		deadline := time.Now().Add(timeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+		if err = conn.SetDeadline(deadline); err != nil {
+			return
+		}
+
+		if step.Receive == nil {
+			r.log.Debug("websocket: send %s\n", step.Send)
+			if err = websocket.Message.Send(conn, step.Send); err != nil {
+				err = fmt.Errorf("failed to send the message of step %d: %v", i+1, err)
+				return
+			}
+			continue
+		}
+
+		var message string
+		if err = websocket.Message.Receive(conn, &message); err != nil {
+			err = fmt.Errorf("failed to receive the message of step %d: %v", i+1, err)
+			return
+		}
+		r.log.Debug("websocket: receive %s\n", message)
+		received = append(received, message)
+
+		if output, err = verifyMessage(testcase.Name, *step.Receive, message); err != nil {
+			err = fmt.Errorf("unexpected message of step %d: %v", i+1, err)
+			return
+		}
+	}
+	return
+}
+
+// dialWebSocket connects the API with the headers, the origin is the HTTP form of the API if it's not in the headers
+func dialWebSocket(request *testing.Request, timeout time.Duration, tlsConfig *tls.Config) (conn *websocket.Conn, err error) {
+	var location *url.URL
+	if location, err = url.Parse(request.API); err != nil {
+		return
+	}
+
+	origin := request.Header["Origin"]
+	if origin == "" {
+		scheme := "http"
+		if location.Scheme == "wss" {
+			scheme = "https"
+		}
+		origin = fmt.Sprintf("%s://%s", scheme, location.Host)
+	}
+
+	var config *websocket.Config
+	if config, err = websocket.NewConfig(request.API, origin); err != nil {
+		return
+	}
+	for key, val := range request.Header {
+		if key != "Origin" {
+			config.Header.Set(key, val)
+		}
+	}
+	config.TlsConfig = tlsConfig
+	config.Dialer = &net.Dialer{Timeout: timeout}
+
+	if conn, err = websocket.DialConfig(config); err != nil {
+		err = fmt.Errorf("failed to connect %s: %v", request.API, err)
+	}
+	return
+}
+
+// verifyMessage checks the received message, it's parsed as JSON only if there are fields, verifications or schema
+func verifyMessage(name string, expect testing.Response, message string) (output interface{}, err error) {
+	if len(expect.BodyFieldsExpect) == 0 && len(expect.BodyFieldsNotExpect) == 0 && len(expect.Verify) == 0 && expect.Schema == "" {
+		err = expectBody(name, expect, message)
+		output = message
+		return
+	}
+
+	if output, err = verifyResponseBodyData(name, expect, []byte(message)); err == nil {
+		err = jsonSchemaValidation(expect.Schema, []byte(message))
+	}
+	return
+}
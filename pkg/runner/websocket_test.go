@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func TestRunWebSocket(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		var message string
+		for websocket.Message.Receive(conn, &message) == nil {
+			if message == "ping" {
+				_ = websocket.Message.Send(conn, "pong")
+				continue
+			}
+			_ = websocket.Message.Send(conn, `{"echo":"`+message+`","token":"`+conn.Request().Header.Get("token")+`"}`)
+		}
+	}))
+	defer server.Close()
+	api := "ws" + strings.TrimPrefix(server.URL, "http") + "/chat"
+
+	tests := []struct {
+		name      string
+		websocket *atest.WebSocket
+		err       string
+		verify    func(*testing.T, interface{}, []*ReportRecord)
+	}{{
+		name: "normal",
+		websocket: &atest.WebSocket{Steps: []atest.WebSocketStep{
+			{Send: "ping"},
+			{Receive: &atest.Response{Body: "pong"}},
+			{Send: "{{.name}}"},
+			{Receive: &atest.Response{
+				BodyFieldsExpect: map[string]interface{}{"echo": "linuxsuren"},
+				Verify:           []string{`data.token == "fake"`},
+			}},
+		}},
+		verify: func(t *testing.T, output interface{}, records []*ReportRecord) {
+			assert.Equal(t, map[string]interface{}{"echo": "linuxsuren", "token": "fake"}, output)
+			if assert.Equal(t, 1, len(records)) {
+				assert.Equal(t, "WS", records[0].Method)
+				assert.Equal(t, api, records[0].API)
+				assert.Equal(t, "pong\n"+`{"echo":"linuxsuren","token":"fake"}`, records[0].Body)
+			}
+		},
+	}, {
+		name: "unexpected message",
+		websocket: &atest.WebSocket{Steps: []atest.WebSocketStep{
+			{Send: "ping"},
+			{Receive: &atest.Response{Body: "fake"}},
+		}},
+		err: "unexpected message of step 2",
+	}, {
+		name: "timeout",
+		websocket: &atest.WebSocket{Timeout: "100ms", Steps: []atest.WebSocketStep{
+			{Receive: &atest.Response{}},
+		}},
+		err: "failed to receive the message of step 1",
+	}, {
+		name:      "invalid timeout",
+		websocket: &atest.WebSocket{Timeout: "fake"},
+		err:       "invalid timeout of the WebSocket",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := NewMemoryTestReporter()
+			output, err := NewSimpleTestCaseRunner().WithTestReporter(reporter).RunTestCase(&atest.TestCase{
+				Request: atest.Request{
+					API:       api,
+					Header:    map[string]string{"token": "fake"},
+					WebSocket: tt.websocket,
+				},
+			}, map[string]interface{}{"name": "linuxsuren"}, context.TODO())
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+			if tt.verify != nil {
+				tt.verify(t, output, reporter.GetAllRecords())
+			}
+		})
+	}
+
+	_, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+		Request: atest.Request{API: "ws://127.0.0.1:1/fake", WebSocket: &atest.WebSocket{}},
+	}, nil, context.TODO())
+	assert.ErrorContains(t, err, "failed to connect ws://127.0.0.1:1/fake")
+}
@@ -2,8 +2,23 @@ package runner
 
 import "github.com/linuxsuren/api-testing/pkg/apispec"
 
+// NewAPICoverage returns the operations of the spec which are exercised or not by the report results,
+// it's nil if the spec is nil
+func NewAPICoverage(results []ReportResult, spec apispec.APIConverage) *apispec.Coverage {
+	if spec == nil {
+		return nil
+	}
+
+	requests := make([]apispec.API, len(results))
+	for i, result := range results {
+		requests[i] = apispec.ParseAPI(result.API)
+	}
+	return apispec.NewCoverage(spec, requests)
+}
+
 // ReportResultWriter is the interface of the report writer
 type ReportResultWriter interface {
 	Output([]ReportResult) error
 	WithAPIConverage(apiConverage apispec.APIConverage) ReportResultWriter
+	WithResourceUsage(usage *ResourceUsage) ReportResultWriter
 }
@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+)
+
+const (
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorReset  = "\033[0m"
+)
+
+// maxFailureMessage is the max length of the failure messages in the console table
+const maxFailureMessage = 200
+
+type consoleResultWriter struct {
+	writer        io.Writer
+	color         bool
+	apiConverage  apispec.APIConverage
+	resourceUsage *ResourceUsage
+}
+
+// NewConsoleResultWriter creates a writer which prints an aligned table and the failures for the interactive use,
+// the status is colorized if color is true
+func NewConsoleResultWriter(writer io.Writer, color bool) ReportResultWriter {
+	return &consoleResultWriter{writer: writer, color: color}
+}
+
+// Output writes the table and the failures to target writer
+func (w *consoleResultWriter) Output(results []ReportResult) error {
+	rows := [][]string{{"STATUS", "API", "COUNT", "ERRORS", "AVERAGE", "P95", "MAX"}}
+	var requests, errors int
+	var failures []ReportResult
+	for _, r := range results {
+		status := "PASS"
+		if r.Error > 0 {
+			status = "FAIL"
+			failures = append(failures, r)
+		} else if r.Slow > 0 || r.Timeout > 0 {
+			status = "SLOW"
+		} else if r.Skipped > 0 && r.Skipped == r.Count {
+			status = "SKIP"
+		}
+		requests += r.Count
+		errors += r.Error
+		rows = append(rows, []string{status, r.API, fmt.Sprint(r.Count), fmt.Sprint(r.Error),
+			roundDuration(r.Average), roundDuration(r.P95), roundDuration(r.Max)})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if width := utf8.RuneCountInString(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = cell
+			if j < len(row)-1 {
+				cells[j] += strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cell))
+			}
+		}
+		if i > 0 {
+			cells[0] = w.colorize(row[0], row[0]) + cells[0][len(row[0]):]
+		}
+		fmt.Fprintln(w.writer, strings.Join(cells, "  "))
+	}
+
+	if len(failures) > 0 {
+		fmt.Fprintln(w.writer, "\nFailures:")
+		for _, r := range failures {
+			fmt.Fprintf(w.writer, "%s %s\n", w.colorize("FAIL", r.API), conciseMessage(r.LastErrorMessage))
+		}
+	}
+
+	status := "PASS"
+	if errors > 0 {
+		status = "FAIL"
+	}
+	fmt.Fprintf(w.writer, "\n%s\n", w.colorize(status, fmt.Sprintf("%d APIs, %d requests, %d failed", len(results), requests, errors)))
+
+	securityFindingsPrint(results, w.writer)
+	apiConveragePrint(results, w.apiConverage, w.writer)
+	resourceUsagePrint(w.resourceUsage, w.writer)
+	return nil
+}
+
+// WithAPIConverage sets the api coverage
+func (w *consoleResultWriter) WithAPIConverage(apiConverage apispec.APIConverage) ReportResultWriter {
+	w.apiConverage = apiConverage
+	return w
+}
+
+// WithResourceUsage sets the resource usage of the runner
+func (w *consoleResultWriter) WithResourceUsage(usage *ResourceUsage) ReportResultWriter {
+	w.resourceUsage = usage
+	return w
+}
+
+// colorize wraps the text with the color of the status
+func (w *consoleResultWriter) colorize(status, text string) string {
+	if !w.color {
+		return text
+	}
+
+	color := colorGreen
+	switch status {
+	case "FAIL":
+		color = colorRed
+	case "SLOW", "SKIP":
+		color = colorYellow
+	}
+	return color + text + colorReset
+}
+
+// roundDuration keeps the durations short, such as: 1.234s instead of 1.234567891s
+func roundDuration(duration time.Duration) string {
+	if duration >= time.Millisecond {
+		duration = duration.Round(time.Millisecond)
+	}
+	return duration.String()
+}
+
+// conciseMessage returns the first line of the message, and truncates it if it's too long
+func conciseMessage(message string) string {
+	if index := strings.Index(message, "\n"); index >= 0 {
+		message = message[:index] + " ..."
+	}
+	if runes := []rune(message); len(runes) > maxFailureMessage {
+		message = string(runes[:maxFailureMessage]) + " ..."
+	}
+	return message
+}
@@ -0,0 +1,76 @@
+package runner_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleResultWriter(t *testing.T) {
+	results := []runner.ReportResult{{
+		API:     "GET http://foo",
+		Count:   3,
+		Average: 1234567 * time.Microsecond,
+		P95:     2 * time.Second,
+		Max:     2 * time.Second,
+	}, {
+		API:              "POST http://bar",
+		Count:            2,
+		Error:            1,
+		Average:          time.Millisecond,
+		P95:              time.Millisecond,
+		Max:              time.Millisecond,
+		LastErrorMessage: "unexpected status code 500\nthe response body",
+	}, {
+		API:     "GET http://fake",
+		Count:   1,
+		Slow:    1,
+		Average: 500,
+		P95:     500,
+		Max:     500,
+	}}
+
+	t.Run("without color", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := runner.NewConsoleResultWriter(buf, false)
+		writer.WithAPIConverage(nil)
+		writer.WithResourceUsage(nil)
+
+		err := writer.Output(results)
+		assert.Nil(t, err)
+		assert.Equal(t, `STATUS  API              COUNT  ERRORS  AVERAGE  P95    MAX
+PASS    GET http://foo   3      0       1.235s   2s     2s
+FAIL    POST http://bar  2      1       1ms      1ms    1ms
+SLOW    GET http://fake  1      0       500ns    500ns  500ns
+
+Failures:
+POST http://bar unexpected status code 500 ...
+
+3 APIs, 6 requests, 1 failed
+`, buf.String())
+	})
+
+	t.Run("with color", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := runner.NewConsoleResultWriter(buf, true).Output(results)
+		assert.Nil(t, err)
+		assert.Contains(t, buf.String(), "\033[32mPASS\033[0m    GET http://foo")
+		assert.Contains(t, buf.String(), "\033[31mFAIL\033[0m    POST http://bar")
+		assert.Contains(t, buf.String(), "\033[33mSLOW\033[0m    GET http://fake")
+		assert.Contains(t, buf.String(), "\033[31m3 APIs, 6 requests, 1 failed\033[0m")
+	})
+
+	t.Run("long message", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := runner.NewConsoleResultWriter(buf, false).Output([]runner.ReportResult{{
+			API: "api", Count: 1, Error: 1, LastErrorMessage: strings.Repeat("a", 300),
+		}})
+		assert.Nil(t, err)
+		assert.Contains(t, buf.String(), "api "+strings.Repeat("a", 200)+" ...\n")
+		assert.Contains(t, buf.String(), "1 APIs, 1 requests, 1 failed")
+	})
+}
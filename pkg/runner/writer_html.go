@@ -9,8 +9,9 @@ import (
 )
 
 type htmlResultWriter struct {
-	writer       io.Writer
-	apiConverage apispec.APIConverage
+	writer        io.Writer
+	apiConverage  apispec.APIConverage
+	resourceUsage *ResourceUsage
 }
 
 // NewHTMLResultWriter creates a new htmlResultWriter
@@ -20,7 +21,10 @@ func NewHTMLResultWriter(writer io.Writer) ReportResultWriter {
 
 // Output writes the HTML base report to target writer
 func (w *htmlResultWriter) Output(result []ReportResult) (err error) {
-	return render.RenderThenPrint("html-report", htmlReport, result, w.writer)
+	return render.RenderThenPrint("html-report", htmlReport, map[string]interface{}{
+		"Results":  result,
+		"Coverage": NewAPICoverage(result, w.apiConverage),
+	}, w.writer)
 }
 
 // WithAPIConverage sets the api coverage
@@ -29,5 +33,11 @@ func (w *htmlResultWriter) WithAPIConverage(apiConverage apispec.APIConverage) R
 	return w
 }
 
+// WithResourceUsage sets the resource usage of the runner
+func (w *htmlResultWriter) WithResourceUsage(usage *ResourceUsage) ReportResultWriter {
+	w.resourceUsage = usage
+	return w
+}
+
 //go:embed data/html.html
 var htmlReport string
@@ -6,6 +6,7 @@ import (
 
 	_ "embed"
 
+	"github.com/linuxsuren/api-testing/pkg/apispec"
 	"github.com/linuxsuren/api-testing/pkg/runner"
 	"github.com/stretchr/testify/assert"
 )
@@ -24,6 +25,10 @@ func TestHTMLResultWriter(t *testing.T) {
 			Max:     3,
 			Min:     3,
 			Average: 3,
+			P50:     3,
+			P90:     3,
+			P95:     3,
+			P99:     3,
 			Error:   0,
 			Count:   1,
 		}},
@@ -42,3 +47,15 @@ func TestHTMLResultWriter(t *testing.T) {
 
 //go:embed testdata/report.html
 var htmlReportExpect string
+
+func TestHTMLResultWriterWithCoverage(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := runner.NewHTMLResultWriter(buf)
+	w.WithAPIConverage(apispec.NewFakeAPISpec([][]string{{"/foo", "GET"}, {"/bar", "GET"}}))
+
+	err := w.Output([]runner.ReportResult{{API: "/foo", Count: 1}})
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "<caption>API Coverage: 1/2 (50%)</caption>")
+	assert.Contains(t, buf.String(), "<tr><td>GET /foo</td><td>yes</td></tr>")
+	assert.Contains(t, buf.String(), "<tr><td>GET /bar</td><td>no</td></tr>")
+}
@@ -9,8 +9,9 @@ import (
 )
 
 type jsonResultWriter struct {
-	writer       io.Writer
-	apiConverage apispec.APIConverage
+	writer        io.Writer
+	apiConverage  apispec.APIConverage
+	resourceUsage *ResourceUsage
 }
 
 // NewJSONResultWriter creates a new jsonResultWriter
@@ -18,9 +19,17 @@ func NewJSONResultWriter(writer io.Writer) ReportResultWriter {
 	return &jsonResultWriter{writer: writer}
 }
 
-// Output writes the HTML base report to target writer
+// Output writes the JSON base report to target writer, the results and the API coverage are
+// in an object if the API coverage is set
 func (w *jsonResultWriter) Output(result []ReportResult) (err error) {
-	jsonData, err := json.Marshal(result)
+	var report interface{} = result
+	if coverage := NewAPICoverage(result, w.apiConverage); coverage != nil {
+		report = map[string]interface{}{
+			"results":  result,
+			"coverage": coverage,
+		}
+	}
+	jsonData, err := json.Marshal(report)
 	if err != nil {
 		return err
 	}
@@ -33,3 +42,9 @@ func (w *jsonResultWriter) WithAPIConverage(apiConverage apispec.APIConverage) R
 	w.apiConverage = apiConverage
 	return w
 }
+
+// WithResourceUsage sets the resource usage of the runner
+func (w *jsonResultWriter) WithResourceUsage(usage *ResourceUsage) ReportResultWriter {
+	w.resourceUsage = usage
+	return w
+}
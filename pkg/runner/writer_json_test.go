@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/linuxsuren/api-testing/pkg/apispec"
 	"github.com/linuxsuren/api-testing/pkg/runner"
 	"github.com/stretchr/testify/assert"
 )
@@ -33,3 +34,15 @@ func TestJSONResultWriter(t *testing.T) {
 		"[{\"API\":\"api\",\"Count\":3,\"Average\":3,\"Max\":4,\"Min\":2,\"QPS\":0,\"Error\":0,\"LastErrorMessage\":\"\"},{\"API\":\"api\",\"Count\":3,\"Average\":3,\"Max\":4,\"Min\":2,\"QPS\":0,\"Error\":0,\"LastErrorMessage\":\"\"}]",
 		buf.String())
 }
+
+func TestJSONResultWriterWithCoverage(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := runner.NewJSONResultWriter(buf)
+	writer.WithAPIConverage(apispec.NewFakeAPISpec([][]string{{"/api", "GET"}, {"/api", "DELETE"}}))
+
+	err := writer.Output([]runner.ReportResult{{API: "/api", Count: 1}})
+	assert.Nil(t, err)
+	assert.Equal(t,
+		`{"coverage":{"covered":[{"method":"GET","path":"/api"}],"missed":[{"method":"DELETE","path":"/api"}],"total":2,"percent":50},"results":[{"API":"/api","Count":1,"Average":0,"Max":0,"Min":0,"QPS":0,"Error":0,"LastErrorMessage":""}]}`,
+		buf.String())
+}
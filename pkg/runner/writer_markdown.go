@@ -2,6 +2,7 @@ package runner
 
 import (
 	_ "embed"
+	"fmt"
 	"io"
 
 	"github.com/linuxsuren/api-testing/pkg/apispec"
@@ -9,8 +10,9 @@ import (
 )
 
 type markdownResultWriter struct {
-	writer       io.Writer
-	apiConverage apispec.APIConverage
+	writer        io.Writer
+	apiConverage  apispec.APIConverage
+	resourceUsage *ResourceUsage
 }
 
 // NewMarkdownResultWriter creates the Markdown writer
@@ -20,7 +22,22 @@ func NewMarkdownResultWriter(writer io.Writer) ReportResultWriter {
 
 // Output writes the Markdown based report to target writer
 func (w *markdownResultWriter) Output(result []ReportResult) (err error) {
-	return render.RenderThenPrint("md-report", markdownReport, result, w.writer)
+	if err = render.RenderThenPrint("md-report", markdownReport, result, w.writer); err == nil && hasFindings(result) {
+		err = w.section("md-security", markdownSecurity, result)
+	}
+	if coverage := NewAPICoverage(result, w.apiConverage); err == nil && coverage != nil {
+		err = w.section("md-coverage", markdownCoverage, coverage)
+	}
+	if err == nil && w.resourceUsage != nil {
+		err = w.section("md-resource-usage", markdownResourceUsage, w.resourceUsage)
+	}
+	return
+}
+
+// section renders a table which is separated from the previous one by a blank line
+func (w *markdownResultWriter) section(name, text string, ctx interface{}) error {
+	fmt.Fprint(w.writer, "\n\n")
+	return render.RenderThenPrint(name, text, ctx, w.writer)
 }
 
 // WithAPIConverage sets the api coverage
@@ -29,5 +46,20 @@ func (w *markdownResultWriter) WithAPIConverage(apiConverage apispec.APIConverag
 	return w
 }
 
+// WithResourceUsage sets the resource usage of the runner
+func (w *markdownResultWriter) WithResourceUsage(usage *ResourceUsage) ReportResultWriter {
+	w.resourceUsage = usage
+	return w
+}
+
 //go:embed data/report.md
 var markdownReport string
+
+//go:embed data/security.md
+var markdownSecurity string
+
+//go:embed data/coverage.md
+var markdownCoverage string
+
+//go:embed data/resource-usage.md
+var markdownResourceUsage string
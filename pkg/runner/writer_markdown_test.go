@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/linuxsuren/api-testing/pkg/apispec"
 	"github.com/linuxsuren/api-testing/pkg/runner"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,6 +19,10 @@ func TestMarkdownWriter(t *testing.T) {
 		Average: 3,
 		Max:     4,
 		Min:     2,
+		P50:     3,
+		P90:     4,
+		P95:     4,
+		P99:     4,
 		Count:   3,
 		Error:   0,
 	}, {
@@ -25,12 +30,60 @@ func TestMarkdownWriter(t *testing.T) {
 		Average: 3,
 		Max:     4,
 		Min:     2,
+		P50:     3,
+		P90:     4,
+		P95:     4,
+		P99:     4,
 		Count:   3,
 		Error:   0,
 	}})
 	assert.Nil(t, err)
-	assert.Equal(t, `| API | Average | Max | Min | Count | Error |
-|---|---|---|---|---|---|
-| api | 3ns | 4ns | 2ns | 3 | 0 |
-| api | 3ns | 4ns | 2ns | 3 | 0 |`, buf.String())
+	assert.Equal(t, `| API | Average | Max | Min | P50 | P90 | P95 | P99 | Count | Error |
+|---|---|---|---|---|---|---|---|---|---|
+| api | 3ns | 4ns | 2ns | 3ns | 4ns | 4ns | 4ns | 3 | 0 |
+| api | 3ns | 4ns | 2ns | 3ns | 4ns | 4ns | 4ns | 3 | 0 |`, buf.String())
+}
+
+func TestMarkdownWriterWithFindings(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := runner.NewMarkdownResultWriter(buf)
+
+	err := writer.Output([]runner.ReportResult{{
+		API:   "api",
+		Count: 1,
+		Findings: []runner.SecurityFinding{{
+			Check:   "hsts",
+			Message: "missing the header Strict-Transport-Security",
+		}},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, `| API | Average | Max | Min | P50 | P90 | P95 | P99 | Count | Error |
+|---|---|---|---|---|---|---|---|---|---|
+| api | 0s | 0s | 0s | 0s | 0s | 0s | 0s | 1 | 0 |
+
+| API | Security check | Finding |
+|---|---|---|
+| api | hsts | missing the header Strict-Transport-Security |`, buf.String())
+}
+
+func TestMarkdownWriterWithCoverage(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := runner.NewMarkdownResultWriter(buf)
+	writer.WithAPIConverage(apispec.NewFakeAPISpec([][]string{{"/users/{name}", "get"}, {"/users", "post"}}))
+
+	err := writer.Output([]runner.ReportResult{{
+		API:   "GET http://localhost/api/users/linuxsuren",
+		Count: 1,
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, `| API | Average | Max | Min | P50 | P90 | P95 | P99 | Count | Error |
+|---|---|---|---|---|---|---|---|---|---|
+| GET http://localhost/api/users/linuxsuren | 0s | 0s | 0s | 0s | 0s | 0s | 0s | 1 | 0 |
+
+API coverage: 1/2 (50%)
+
+| API | Covered |
+|---|---|
+| GET /users/{name} | yes |
+| POST /users | no |`, buf.String())
 }
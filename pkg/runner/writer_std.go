@@ -9,8 +9,9 @@ import (
 )
 
 type stdResultWriter struct {
-	writer       io.Writer
-	apiConverage apispec.APIConverage
+	writer        io.Writer
+	apiConverage  apispec.APIConverage
+	resourceUsage *ResourceUsage
 }
 
 // NewResultWriter creates a result writer with the specific io.Writer
@@ -26,10 +27,10 @@ func NewDiscardResultWriter() ReportResultWriter {
 // Output writer the report to target writer
 func (w *stdResultWriter) Output(results []ReportResult) error {
 	var errResults []ReportResult
-	fmt.Fprintf(w.writer, "API Average Max Min QPS Count Error\n")
+	fmt.Fprintf(w.writer, "API Average Max Min P50 P90 P95 P99 QPS Count Error\n")
 	for _, r := range results {
-		fmt.Fprintf(w.writer, "%s %v %v %v %d %d %d\n", r.API, r.Average, r.Max,
-			r.Min, r.QPS, r.Count, r.Error)
+		fmt.Fprintf(w.writer, "%s %v %v %v %v %v %v %v %.2f %d %d\n", r.API, r.Average, r.Max,
+			r.Min, r.P50, r.P90, r.P95, r.P99, r.QPS, r.Count, r.Error)
 		if r.Error > 0 && r.LastErrorMessage != "" {
 			errResults = append(errResults, r)
 		}
@@ -38,8 +39,24 @@ func (w *stdResultWriter) Output(results []ReportResult) error {
 	for _, r := range errResults {
 		fmt.Fprintf(w.writer, "%s error: %s\n", r.API, r.LastErrorMessage)
 	}
+	for _, r := range results {
+		if r.Retries > 0 {
+			fmt.Fprintf(w.writer, "%s retries: %d\n", r.API, r.Retries)
+		}
+		if r.Timeout > 0 {
+			fmt.Fprintf(w.writer, "%s timeouts: %d\n", r.API, r.Timeout)
+		}
+		if r.Slow > 0 {
+			fmt.Fprintf(w.writer, "%s slow responses: %d\n", r.API, r.Slow)
+		}
+		if r.Skipped > 0 {
+			fmt.Fprintf(w.writer, "%s skipped: %d\n", r.API, r.Skipped)
+		}
+	}
 
+	securityFindingsPrint(results, w.writer)
 	apiConveragePrint(results, w.apiConverage, w.writer)
+	resourceUsagePrint(w.resourceUsage, w.writer)
 	return nil
 }
 
@@ -49,16 +66,70 @@ func (w *stdResultWriter) WithAPIConverage(apiConverage apispec.APIConverage) Re
 	return w
 }
 
+// WithResourceUsage sets the resource usage of the runner
+func (w *stdResultWriter) WithResourceUsage(usage *ResourceUsage) ReportResultWriter {
+	w.resourceUsage = usage
+	return w
+}
+
 func apiConveragePrint(result []ReportResult, apiConverage apispec.APIConverage, w io.Writer) {
-	if apiConverage == nil {
+	coverage := NewAPICoverage(result, apiConverage)
+	if coverage == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "\nAPI Coverage: %s\n", coverage.Summary())
+	for _, api := range coverage.Covered {
+		fmt.Fprintf(w, "covered: %s\n", api)
+	}
+	for _, api := range coverage.Missed {
+		fmt.Fprintf(w, "missed: %s\n", api)
+	}
+}
+
+func securityFindingsPrint(results []ReportResult, w io.Writer) {
+	if !hasFindings(results) {
 		return
 	}
 
-	var covered int
-	for _, item := range result {
-		if apiConverage.HaveAPI(item.API, "GET") {
-			covered++
+	fmt.Fprintf(w, "\nSecurity findings:\n")
+	for _, r := range results {
+		for _, finding := range r.Findings {
+			fmt.Fprintf(w, "%s [%s] %s\n", r.API, finding.Check, finding.Message)
+		}
+	}
+}
+
+func hasFindings(results []ReportResult) bool {
+	for _, r := range results {
+		if len(r.Findings) > 0 {
+			return true
 		}
 	}
-	fmt.Fprintf(w, "\nAPI Coverage: %d/%d\n", covered, apiConverage.APICount())
+	return false
+}
+
+func resourceUsagePrint(usage *ResourceUsage, w io.Writer) {
+	if usage == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "\nRunner CPU: %v (%.1f%% of %d CPUs)\n", usage.CPUTime, usage.CPUPercent, usage.NumCPU)
+	fmt.Fprintf(w, "Runner memory: max heap %s, total allocated %s\n", formatBytes(usage.MaxHeapAlloc), formatBytes(usage.TotalAlloc))
+	fmt.Fprintf(w, "Runner GC: %d times, paused %v\n", usage.NumGC, usage.GCPauseTotal)
+	fmt.Fprintf(w, "Runner goroutines: max %d\n", usage.MaxGoroutines)
+}
+
+// formatBytes returns the human readable size, such as: 1.5MiB
+func formatBytes(size uint64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
 }
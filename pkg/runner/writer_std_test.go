@@ -20,7 +20,7 @@ func TestNewStdResultWriter(t *testing.T) {
 		name:    "result is nil",
 		buf:     new(bytes.Buffer),
 		results: nil,
-		expect: `API Average Max Min QPS Count Error
+		expect: `API Average Max Min P50 P90 P95 P99 QPS Count Error
 `,
 	}, {
 		name: "have one item",
@@ -37,10 +37,11 @@ func TestNewStdResultWriter(t *testing.T) {
 			Count:   1,
 			Error:   0,
 		}},
-		expect: `API Average Max Min QPS Count Error
-/api 1ns 1ns 1ns 10 1 0
+		expect: `API Average Max Min P50 P90 P95 P99 QPS Count Error
+/api 1ns 1ns 1ns 0s 0s 0s 0s 10.00 1 0
 
-API Coverage: 1/1
+API Coverage: 1/1 (100%)
+covered: GET /api
 `,
 	}, {
 		name: "have errors",
@@ -55,8 +56,8 @@ API Coverage: 1/1
 			Error:            1,
 			LastErrorMessage: "error",
 		}},
-		expect: `API Average Max Min QPS Count Error
-api 1ns 1ns 1ns 10 1 1
+		expect: `API Average Max Min P50 P90 P95 P99 QPS Count Error
+api 1ns 1ns 1ns 0s 0s 0s 0s 10.00 1 1
 api error: error
 `,
 	}, {
@@ -72,8 +73,50 @@ api error: error
 			Error:            0,
 			LastErrorMessage: "message",
 		}},
-		expect: `API Average Max Min QPS Count Error
-api 1ns 1ns 1ns 10 1 0
+		expect: `API Average Max Min P50 P90 P95 P99 QPS Count Error
+api 1ns 1ns 1ns 0s 0s 0s 0s 10.00 1 0
+`,
+	}, {
+		name: "have retries, timeouts, and slow responses",
+		buf:  new(bytes.Buffer),
+		results: []runner.ReportResult{{
+			API:     "api",
+			Average: 1,
+			Max:     1,
+			Min:     1,
+			QPS:     10,
+			Count:   1,
+			Error:   1,
+			Retries: 2,
+			Timeout: 1,
+			Slow:    1,
+		}},
+		expect: `API Average Max Min P50 P90 P95 P99 QPS Count Error
+api 1ns 1ns 1ns 0s 0s 0s 0s 10.00 1 1
+api retries: 2
+api timeouts: 1
+api slow responses: 1
+`,
+	}, {
+		name: "have security findings",
+		buf:  new(bytes.Buffer),
+		results: []runner.ReportResult{{
+			API:     "api",
+			Average: 1,
+			Max:     1,
+			Min:     1,
+			QPS:     10,
+			Count:   1,
+			Findings: []runner.SecurityFinding{{
+				Check:   "csp",
+				Message: "missing the header Content-Security-Policy",
+			}},
+		}},
+		expect: `API Average Max Min P50 P90 P95 P99 QPS Count Error
+api 1ns 1ns 1ns 0s 0s 0s 0s 10.00 1 0
+
+Security findings:
+api [csp] missing the header Content-Security-Policy
 `,
 	}}
 	for _, tt := range tests {
@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+)
+
+type tapResultWriter struct {
+	writer        io.Writer
+	apiConverage  apispec.APIConverage
+	resourceUsage *ResourceUsage
+}
+
+// NewTAPResultWriter creates a writer which outputs the report in TAP version 13 (Test Anything Protocol).
+// Each API is a test point, it's not ok if any request of it failed
+func NewTAPResultWriter(writer io.Writer) ReportResultWriter {
+	return &tapResultWriter{writer: writer}
+}
+
+// Output writes the TAP report to target writer
+func (w *tapResultWriter) Output(results []ReportResult) error {
+	fmt.Fprintln(w.writer, "TAP version 13")
+	fmt.Fprintf(w.writer, "1..%d\n", len(results))
+	for i, r := range results {
+		status := "ok"
+		if r.Error > 0 {
+			status = "not ok"
+		}
+		directive := ""
+		if r.Error == 0 && r.Skipped > 0 && r.Skipped == r.Count {
+			directive = " # SKIP"
+		}
+		fmt.Fprintf(w.writer, "%s %d - %s%s\n", status, i+1, escapeTAPDescription(r.API), directive)
+
+		// the YAML diagnostic block of the test point
+		fmt.Fprintln(w.writer, "  ---")
+		if r.Error > 0 && r.LastErrorMessage != "" {
+			fmt.Fprintf(w.writer, "  message: %s\n", strconv.Quote(r.LastErrorMessage))
+		}
+		fmt.Fprintf(w.writer, "  count: %d\n", r.Count)
+		fmt.Fprintf(w.writer, "  errors: %d\n", r.Error)
+		fmt.Fprintf(w.writer, "  average: %s\n", r.Average)
+		fmt.Fprintf(w.writer, "  max: %s\n", r.Max)
+		fmt.Fprintf(w.writer, "  min: %s\n", r.Min)
+		if r.Retries > 0 {
+			fmt.Fprintf(w.writer, "  retries: %d\n", r.Retries)
+		}
+		if r.Timeout > 0 {
+			fmt.Fprintf(w.writer, "  timeouts: %d\n", r.Timeout)
+		}
+		if r.Slow > 0 {
+			fmt.Fprintf(w.writer, "  slow: %d\n", r.Slow)
+		}
+		if r.Skipped > 0 {
+			fmt.Fprintf(w.writer, "  skipped: %d\n", r.Skipped)
+		}
+		if len(r.Findings) > 0 {
+			fmt.Fprintln(w.writer, "  findings:")
+			for _, finding := range r.Findings {
+				fmt.Fprintf(w.writer, "    - %s\n", strconv.Quote(fmt.Sprintf("[%s] %s", finding.Check, finding.Message)))
+			}
+		}
+		fmt.Fprintln(w.writer, "  ...")
+	}
+
+	if coverage := NewAPICoverage(results, w.apiConverage); coverage != nil {
+		fmt.Fprintf(w.writer, "# API Coverage: %s\n", coverage.Summary())
+		for _, api := range coverage.Missed {
+			fmt.Fprintf(w.writer, "# missed: %s\n", api)
+		}
+	}
+	return nil
+}
+
+// WithAPIConverage sets the api coverage
+func (w *tapResultWriter) WithAPIConverage(apiConverage apispec.APIConverage) ReportResultWriter {
+	w.apiConverage = apiConverage
+	return w
+}
+
+// WithResourceUsage sets the resource usage of the runner
+func (w *tapResultWriter) WithResourceUsage(usage *ResourceUsage) ReportResultWriter {
+	w.resourceUsage = usage
+	return w
+}
+
+// escapeTAPDescription escapes the characters which have special meanings in the description of a test point
+func escapeTAPDescription(description string) string {
+	return strings.NewReplacer(`\`, `\\`, "#", `\#`, "\n", " ").Replace(description)
+}
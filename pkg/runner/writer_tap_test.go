@@ -0,0 +1,93 @@
+package runner_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTAPResultWriter(t *testing.T) {
+	t.Run("no results", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := runner.NewTAPResultWriter(buf).Output(nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "TAP version 13\n1..0\n", buf.String())
+	})
+
+	t.Run("have results", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := runner.NewTAPResultWriter(buf)
+		writer.WithAPIConverage(apispec.NewFakeAPISpec([][]string{{"/api", "GET"}, {"/fake", "GET"}}))
+		writer.WithResourceUsage(nil)
+
+		err := writer.Output([]runner.ReportResult{{
+			API:     "/api",
+			Average: 3,
+			Max:     4,
+			Min:     2,
+			Count:   3,
+		}, {
+			API:              "GET /foo#bar",
+			Average:          1,
+			Max:              1,
+			Min:              1,
+			Count:            2,
+			Error:            1,
+			Retries:          1,
+			Timeout:          1,
+			LastErrorMessage: "unexpected status\ncode",
+			Findings:         []runner.SecurityFinding{{Check: "csp", Message: "missing"}},
+		}})
+		assert.Nil(t, err)
+		assert.Equal(t, `TAP version 13
+1..2
+ok 1 - /api
+  ---
+  count: 3
+  errors: 0
+  average: 3ns
+  max: 4ns
+  min: 2ns
+  ...
+not ok 2 - GET /foo\#bar
+  ---
+  message: "unexpected status\ncode"
+  count: 2
+  errors: 1
+  average: 1ns
+  max: 1ns
+  min: 1ns
+  retries: 1
+  timeouts: 1
+  findings:
+    - "[csp] missing"
+  ...
+# API Coverage: 1/2 (50%)
+# missed: GET /fake
+`, buf.String())
+	})
+	t.Run("skipped", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := runner.NewTAPResultWriter(buf).Output([]runner.ReportResult{{
+			API:     "GET /api",
+			Count:   1,
+			Skipped: 1,
+		}})
+		assert.Nil(t, err)
+		assert.Equal(t, `TAP version 13
+1..1
+ok 1 - GET /api # SKIP
+  ---
+  count: 1
+  errors: 0
+  average: 0s
+  max: 0s
+  min: 0s
+  skipped: 1
+  ...
+`, buf.String())
+	})
+}
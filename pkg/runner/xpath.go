@@ -0,0 +1,382 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+// xmlNode is an element or a text of the XML document, the root is the document itself
+type xmlNode struct {
+	name     string
+	text     string
+	isText   bool
+	attrs    map[string]string
+	children []*xmlNode
+}
+
+// xpathStep selects the elements by the name and the predicates, the last step could select the attributes or the texts
+type xpathStep struct {
+	descendant bool
+	name       string
+	attr       string
+	text       bool
+	predicates []string
+}
+
+// isXMLResponse checks if the body should be parsed as XML instead of JSON
+func isXMLResponse(expect *testing.Response, contentType string) bool {
+	return len(expect.XMLFields) > 0 || strings.Contains(contentType, "xml")
+}
+
+// verifyXMLBody checks the body and the XPath fields of the XML response, the output is the body as it is
+func verifyXMLBody(name string, expect testing.Response, body []byte) (output interface{}, err error) {
+	output = string(body)
+	if err = expectBody(name, expect, string(body)); err != nil {
+		return
+	}
+	if len(expect.XMLFields) == 0 {
+		return
+	}
+
+	var root *xmlNode
+	if root, err = parseXML(body); err != nil {
+		return
+	}
+	err = verifyXMLFields(root, expect.XMLFields)
+	return
+}
+
+// verifyXMLFields compares the string values of the nodes, the expected value should be a list if the XPath matches more than one node
+func verifyXMLFields(root *xmlNode, fields map[string]interface{}) (err error) {
+	for key, expectVal := range fields {
+		var values []string
+		if values, err = queryXPath(root, key); err != nil {
+			return
+		} else if len(values) == 0 {
+			err = fmt.Errorf("not found XML field: %s", key)
+			return
+		}
+
+		var val interface{} = values[0]
+		if expectList, ok := expectVal.([]interface{}); ok {
+			expected := make([]string, len(expectList))
+			for i, item := range expectList {
+				expected[i] = fmt.Sprintf("%v", item)
+			}
+			expectVal, val = expected, values
+		} else if len(values) > 1 {
+			err = fmt.Errorf("the XPath %s matches %d nodes, expect a list of them", key, len(values))
+			return
+		} else {
+			expectVal = fmt.Sprintf("%v", expectVal)
+		}
+
+		if !reflect.DeepEqual(expectVal, val) {
+			err = fmt.Errorf("XML field[%s] expect value: %v, actual: %v", key, expectVal, val)
+			return
+		}
+	}
+	return
+}
+
+// parseXML builds the tree of the document, the namespaces are ignored
+func parseXML(data []byte) (root *xmlNode, err error) {
+	root = &xmlNode{}
+	stack := []*xmlNode{root}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		var token xml.Token
+		if token, err = decoder.Token(); err == io.EOF {
+			err = nil
+			break
+		} else if err != nil {
+			err = fmt.Errorf("invalid XML body: %v", err)
+			return
+		}
+
+		current := stack[len(stack)-1]
+		switch val := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: val.Name.Local, attrs: map[string]string{}}
+			for _, attr := range val.Attr {
+				node.attrs[attr.Name.Local] = attr.Value
+			}
+			current.children = append(current.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			current.children = append(current.children, &xmlNode{text: string(val), isText: true})
+		}
+	}
+
+	if len(root.elements("*")) == 0 {
+		err = fmt.Errorf("invalid XML body: no root element")
+	}
+	return
+}
+
+// queryXPath returns the trimmed string values of the matched nodes. It supports the absolute location paths
+// with the child (/) and the descendant (//) steps, the name tests (the prefixes are ignored), the wildcard,
+// the attributes (@name), the texts (text()), and the predicates: [1], [last()], [@name], [@name='value'],
+// [name='value'], [text()='value'], and the != of them
+func queryXPath(root *xmlNode, path string) (values []string, err error) {
+	var steps []xpathStep
+	if steps, err = parseXPath(path); err != nil {
+		return
+	}
+
+	nodes := []*xmlNode{root}
+	for i, step := range steps {
+		if (step.attr != "" || step.text) && i != len(steps)-1 {
+			err = fmt.Errorf("the attribute or the text should be the last step of XPath %s", path)
+			return
+		}
+
+		var parents []*xmlNode
+		for _, node := range nodes {
+			if step.descendant {
+				parents = node.descendantsOrSelf(parents)
+			} else {
+				parents = append(parents, node)
+			}
+		}
+
+		var selected []*xmlNode
+		for _, parent := range parents {
+			switch {
+			case step.attr != "":
+				if val, ok := parent.attrs[step.attr]; ok {
+					values = append(values, strings.TrimSpace(val))
+				}
+			case step.text:
+				if text := strings.TrimSpace(parent.directText()); text != "" {
+					values = append(values, text)
+				}
+			default:
+				var candidates []*xmlNode
+				if candidates, err = filterXMLNodes(parent.elements(step.name), step.predicates); err != nil {
+					err = fmt.Errorf("invalid XPath %s: %v", path, err)
+					return
+				}
+				selected = append(selected, candidates...)
+			}
+		}
+		nodes = selected
+	}
+
+	if last := steps[len(steps)-1]; last.attr == "" && !last.text {
+		for _, node := range nodes {
+			values = append(values, strings.TrimSpace(node.content()))
+		}
+	}
+	return
+}
+
+func parseXPath(path string) (steps []xpathStep, err error) {
+	if !strings.HasPrefix(path, "/") {
+		err = fmt.Errorf("the XPath should be absolute: %s", path)
+		return
+	}
+
+	for i := 0; i < len(path); {
+		step := xpathStep{}
+		if path[i] != '/' {
+			err = fmt.Errorf("invalid XPath %s at %d", path, i)
+			return
+		} else if strings.HasPrefix(path[i:], "//") {
+			step.descendant = true
+			i += 2
+		} else {
+			i++
+		}
+
+		end := i
+		for end < len(path) && path[end] != '/' && path[end] != '[' {
+			end++
+		}
+		name := path[i:end]
+		switch {
+		case name == "":
+			err = fmt.Errorf("the step is empty in XPath %s at %d", path, i)
+			return
+		case name == "text()":
+			step.text = true
+		case strings.HasPrefix(name, "@"):
+			step.attr = localName(name[1:])
+		default:
+			step.name = localName(name)
+		}
+
+		for i = end; i < len(path) && path[i] == '['; {
+			closing := closingBracket(path, i)
+			if closing < 0 {
+				err = fmt.Errorf("the bracket is not closed in XPath %s at %d", path, i)
+				return
+			}
+			step.predicates = append(step.predicates, strings.TrimSpace(path[i+1:closing]))
+			i = closing + 1
+		}
+		if len(step.predicates) > 0 && (step.attr != "" || step.text) {
+			err = fmt.Errorf("the predicates of the attribute or the text are not supported: %s", path)
+			return
+		}
+		steps = append(steps, step)
+	}
+	return
+}
+
+// filterXMLNodes applies the predicates one by one, the positions are relative to the result of the previous one
+func filterXMLNodes(nodes []*xmlNode, predicates []string) (result []*xmlNode, err error) {
+	result = nodes
+	for _, predicate := range predicates {
+		if predicate == "last()" {
+			if len(result) > 0 {
+				result = result[len(result)-1:]
+			}
+			continue
+		}
+		if position, atoiErr := strconv.Atoi(predicate); atoiErr == nil {
+			if position >= 1 && position <= len(result) {
+				result = result[position-1 : position]
+			} else {
+				result = nil
+			}
+			continue
+		}
+
+		var filtered []*xmlNode
+		for _, node := range result {
+			var matched bool
+			if matched, err = node.matches(predicate); err != nil {
+				return
+			} else if matched {
+				filtered = append(filtered, node)
+			}
+		}
+		result = filtered
+	}
+	return
+}
+
+// matches checks the existence of the operand, or compares the string value of it with the literal
+func (n *xmlNode) matches(predicate string) (matched bool, err error) {
+	operand, literal, negative, hasLiteral := predicate, "", false, false
+	if index := indexUnquoted(predicate, '='); index > 0 {
+		operand, literal, hasLiteral = predicate[:index], strings.TrimSpace(predicate[index+1:]), true
+		if strings.HasSuffix(operand, "!") {
+			operand, negative = strings.TrimSuffix(operand, "!"), true
+		}
+
+		// the literal is a quoted string or a number
+		if unquoted, unquoteErr := unquote(literal); unquoteErr == nil {
+			literal = unquoted
+		} else if _, err = strconv.ParseFloat(literal, 64); err != nil {
+			err = fmt.Errorf("invalid predicate: %s", predicate)
+			return
+		}
+	}
+	operand = strings.TrimSpace(operand)
+
+	var values []string
+	switch {
+	case operand == "" || strings.ContainsAny(operand, "/[]()") && operand != "text()":
+		err = fmt.Errorf("unsupported predicate: %s", predicate)
+		return
+	case operand == ".":
+		values = []string{n.content()}
+	case operand == "text()":
+		values = []string{n.directText()}
+	case strings.HasPrefix(operand, "@"):
+		if val, ok := n.attrs[localName(operand[1:])]; ok {
+			values = []string{val}
+		}
+	default:
+		for _, child := range n.elements(localName(operand)) {
+			values = append(values, child.content())
+		}
+	}
+
+	if !hasLiteral {
+		matched = len(values) > 0
+		return
+	}
+	for _, val := range values {
+		if (strings.TrimSpace(val) == literal) != negative {
+			matched = true
+			break
+		}
+	}
+	return
+}
+
+// elements returns the child elements which match the name, the * matches all of them
+func (n *xmlNode) elements(name string) (result []*xmlNode) {
+	for _, child := range n.children {
+		if !child.isText && (name == "*" || child.name == name) {
+			result = append(result, child)
+		}
+	}
+	return
+}
+
+func (n *xmlNode) descendantsOrSelf(result []*xmlNode) []*xmlNode {
+	result = append(result, n)
+	for _, child := range n.elements("*") {
+		result = child.descendantsOrSelf(result)
+	}
+	return result
+}
+
+// content returns the concatenation of all the texts in the node
+func (n *xmlNode) content() string {
+	if n.isText {
+		return n.text
+	}
+	builder := strings.Builder{}
+	for _, child := range n.children {
+		builder.WriteString(child.content())
+	}
+	return builder.String()
+}
+
+func (n *xmlNode) directText() string {
+	builder := strings.Builder{}
+	for _, child := range n.children {
+		if child.isText {
+			builder.WriteString(child.text)
+		}
+	}
+	return builder.String()
+}
+
+func localName(name string) string {
+	if index := strings.LastIndex(name, ":"); index >= 0 {
+		name = name[index+1:]
+	}
+	return name
+}
+
+func indexUnquoted(text string, target rune) int {
+	var quote rune
+	for i, c := range text {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == target:
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,178 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const xmlBodyForTest = `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+	<soap:Body>
+		<GetUsersResponse>
+			<user id="1" role="admin"><name>Rick</name><age>70</age></user>
+			<user id="2"><name>Morty</name><age>14</age></user>
+			<total>2</total>
+		</GetUsersResponse>
+	</soap:Body>
+</soap:Envelope>`
+
+func TestQueryXPath(t *testing.T) {
+	root, err := parseXML([]byte(xmlBodyForTest))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	tests := []struct {
+		path   string
+		expect []string
+		err    string
+	}{{
+		path:   "/Envelope/Body/GetUsersResponse/total",
+		expect: []string{"2"},
+	}, {
+		path:   "/soap:Envelope/soap:Body/*/total/text()",
+		expect: []string{"2"},
+	}, {
+		path:   "//user/name",
+		expect: []string{"Rick", "Morty"},
+	}, {
+		path:   "//user/@id",
+		expect: []string{"1", "2"},
+	}, {
+		path:   "//user[@id='2']/name",
+		expect: []string{"Morty"},
+	}, {
+		path:   "//user[2]/@id",
+		expect: []string{"2"},
+	}, {
+		path:   "//user[last()]/name",
+		expect: []string{"Morty"},
+	}, {
+		path:   "//user[@role]/name",
+		expect: []string{"Rick"},
+	}, {
+		path:   "//user[name!='Rick']/@id",
+		expect: []string{"2"},
+	}, {
+		path:   "//user[age=14]/name",
+		expect: []string{"Morty"},
+	}, {
+		path:   "//user[@id='3']/name",
+		expect: nil,
+	}, {
+		path: "user",
+		err:  "the XPath should be absolute: user",
+	}, {
+		path: "//user/",
+		err:  "the step is empty in XPath //user/",
+	}, {
+		path: "//user[1",
+		err:  "the bracket is not closed in XPath //user[1",
+	}, {
+		path: "//user/@id/name",
+		err:  "the attribute or the text should be the last step",
+	}, {
+		path: "//user[position()=1]",
+		err:  "unsupported predicate: position()=1",
+	}, {
+		path: "//user[@id=2a]",
+		err:  "invalid predicate: @id=2a",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			values, err := queryXPath(root, tt.path)
+			if tt.err == "" {
+				assert.Nil(t, err)
+				assert.Equal(t, tt.expect, values)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestXMLFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expect      atest.Response
+		err         string
+	}{{
+		name:        "the XPath fields",
+		contentType: "text/xml; charset=utf-8",
+		body:        xmlBodyForTest,
+		expect: atest.Response{
+			XMLFields: map[string]interface{}{
+				"//total":              2,
+				"//user[@id='1']/name": "Rick",
+				"//user/@id":           []interface{}{1, 2},
+			},
+		},
+	}, {
+		name:        "XML without the fields",
+		contentType: "application/xml",
+		body:        xmlBodyForTest,
+	}, {
+		name:        "different value",
+		contentType: "application/xml",
+		body:        xmlBodyForTest,
+		expect: atest.Response{
+			XMLFields: map[string]interface{}{"//total": 3},
+		},
+		err: "XML field[//total] expect value: 3, actual: 2",
+	}, {
+		name:        "multiple nodes",
+		contentType: "application/xml",
+		body:        xmlBodyForTest,
+		expect: atest.Response{
+			XMLFields: map[string]interface{}{"//user/name": "Rick"},
+		},
+		err: "the XPath //user/name matches 2 nodes, expect a list of them",
+	}, {
+		name:        "not found",
+		contentType: "application/xml",
+		body:        xmlBodyForTest,
+		expect: atest.Response{
+			XMLFields: map[string]interface{}{"//fake": "fake"},
+		},
+		err: "not found XML field: //fake",
+	}, {
+		name:        "invalid XML",
+		contentType: "application/json",
+		body:        `{"name": "linuxsuren"}`,
+		expect: atest.Response{
+			XMLFields: map[string]interface{}{"//name": "linuxsuren"},
+		},
+		err: "invalid XML body",
+	}, {
+		name:        "different body",
+		contentType: "application/xml",
+		body:        "<a>1</a>",
+		expect:      atest.Response{Body: "<a>2</a>"},
+		err:         "got different response body",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+			gock.New(urlLocalhost).Get("/foo").Reply(http.StatusOK).
+				SetHeader("Content-Type", tt.contentType).BodyString(tt.body)
+
+			output, err := NewSimpleTestCaseRunner().RunTestCase(&atest.TestCase{
+				Request: atest.Request{API: urlFoo},
+				Expect:  tt.expect,
+			}, nil, context.TODO())
+			if tt.err == "" {
+				assert.Nil(t, err)
+				assert.Equal(t, tt.body, output)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package secret provides the sources of the secrets which are referenced in the templates
+package secret
@@ -0,0 +1,219 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rules are the redaction rules which are applied besides the values of the used secrets
+type Rules struct {
+	// Headers are the names of the headers, the values of them are masked, case-insensitive
+	Headers []string `json:"headers,omitempty"`
+	// Fields are the paths of the JSON fields, such as: password, user.token, items[*].secret.
+	// A name without the dot matches the fields of any depth
+	Fields []string `json:"fields,omitempty"`
+	// Patterns are the regular expressions, the matched text is masked
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// DefaultRules returns the rules of the credential headers and the common sensitive fields
+func DefaultRules() Rules {
+	return Rules{
+		Headers: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-API-Key", "X-Auth-Token"},
+		Fields:  []string{"password", "access_token", "refresh_token", "id_token", "client_secret"},
+	}
+}
+
+type compiledRules struct {
+	fields   [][]string
+	headers  []string
+	patterns []*regexp.Regexp
+	// replacers mask the headers and the fields in the text which is not a JSON document
+	replacers []replacer
+}
+
+type replacer struct {
+	reg  *regexp.Regexp
+	repl string
+}
+
+var rules = mustCompileRules(DefaultRules())
+
+// SetRules replaces the redaction rules, it returns an error if any pattern is invalid
+func SetRules(items Rules) (err error) {
+	var compiled *compiledRules
+	if compiled, err = compileRules(items); err == nil {
+		lock.Lock()
+		rules = compiled
+		lock.Unlock()
+	}
+	return
+}
+
+func mustCompileRules(items Rules) *compiledRules {
+	compiled, err := compileRules(items)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
+
+func compileRules(items Rules) (compiled *compiledRules, err error) {
+	compiled = &compiledRules{}
+	for _, pattern := range items.Patterns {
+		var reg *regexp.Regexp
+		if reg, err = regexp.Compile(pattern); err != nil {
+			err = fmt.Errorf("invalid redaction pattern %q: %v", pattern, err)
+			return
+		}
+		compiled.patterns = append(compiled.patterns, reg)
+	}
+
+	for _, header := range items.Headers {
+		if header = strings.TrimSpace(header); header == "" {
+			continue
+		}
+		compiled.headers = append(compiled.headers, header)
+		name := regexp.QuoteMeta(header)
+		compiled.replacers = append(compiled.replacers,
+			// such as: "Authorization": ["Bearer token"] of the dumped headers
+			replacer{reg: regexp.MustCompile(`(?i)("` + name + `"\s*:\s*)\[\s*"(?:[^"\\]|\\.)*"(?:\s*,\s*"(?:[^"\\]|\\.)*")*\s*\]`), repl: `${1}["` + Mask + `"]`},
+			replacer{reg: regexp.MustCompile(`(?i)("` + name + `"\s*:\s*")(?:[^"\\]|\\.)*(")`), repl: "${1}" + Mask + "${2}"},
+			// such as: Authorization: Bearer token
+			replacer{reg: regexp.MustCompile(`(?im)(\b` + name + `:[ \t]*)[^\r\n]+`), repl: "${1}" + Mask},
+		)
+	}
+
+	for _, field := range items.Fields {
+		segments := splitFieldPath(field)
+		if len(segments) == 0 {
+			continue
+		}
+		compiled.fields = append(compiled.fields, segments)
+		if name := segments[len(segments)-1]; name != "*" {
+			quoted := regexp.QuoteMeta(name)
+			compiled.replacers = append(compiled.replacers,
+				replacer{reg: regexp.MustCompile(`("` + quoted + `"\s*:\s*")(?:[^"\\]|\\.)*(")`), repl: "${1}" + Mask + "${2}"},
+				// the JSON which is escaped in a string, such as the body in a JSON line
+				replacer{reg: regexp.MustCompile(`(\\"` + quoted + `\\"\s*:\s*\\")(.*?)(\\")`), repl: "${1}" + Mask + "${3}"},
+			)
+		}
+	}
+	return
+}
+
+// splitFieldPath splits the path into the segments, such as: $.items[*].token to items, *, token
+func splitFieldPath(path string) (segments []string) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	for _, segment := range strings.Split(path, ".") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return
+}
+
+// apply masks the text with the patterns, the fields and the headers
+func (r *compiledRules) apply(text string) string {
+	for _, reg := range r.patterns {
+		text = reg.ReplaceAllString(text, Mask)
+	}
+	text = r.applyJSON(text)
+	for _, item := range r.replacers {
+		text = item.reg.ReplaceAllString(text, item.repl)
+	}
+	return text
+}
+
+// applyJSON masks the fields and the headers of a JSON document, the text is kept as it is if nothing is masked
+func (r *compiledRules) applyJSON(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if (len(r.fields) == 0 && len(r.headers) == 0) || !(strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) {
+		return text
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil || decoder.More() {
+		return text
+	}
+	if !r.maskJSON(data, nil) {
+		return text
+	}
+
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return text
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// maskJSON replaces the values of the matched fields with the mask, it returns true if any one is masked
+func (r *compiledRules) maskJSON(data interface{}, path []string) (masked bool) {
+	mask := func(key string) bool {
+		return r.matchField(append(path, key))
+	}
+
+	switch val := data.(type) {
+	case map[string]interface{}:
+		for key, item := range val {
+			if mask(key) {
+				val[key] = Mask
+				masked = true
+			} else if r.maskJSON(item, append(path, key)) {
+				masked = true
+			}
+		}
+	case []interface{}:
+		for i, item := range val {
+			key := strconv.Itoa(i)
+			if mask(key) {
+				val[i] = Mask
+				masked = true
+			} else if r.maskJSON(item, append(path, key)) {
+				masked = true
+			}
+		}
+	}
+	return
+}
+
+func (r *compiledRules) matchField(path []string) bool {
+	last := path[len(path)-1]
+	for _, header := range r.headers {
+		if strings.EqualFold(header, last) {
+			return true
+		}
+	}
+
+	for _, field := range r.fields {
+		if len(field) == 1 {
+			if field[0] == last {
+				return true
+			}
+			continue
+		}
+		if len(field) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range field {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
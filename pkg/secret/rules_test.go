@@ -0,0 +1,74 @@
+package secret_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/secret"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules(t *testing.T) {
+	defer func() {
+		assert.NoError(t, secret.SetRules(secret.DefaultRules()))
+	}()
+
+	tests := []struct {
+		name   string
+		rules  secret.Rules
+		text   string
+		expect string
+	}{{
+		name:   "default header in the text",
+		rules:  secret.DefaultRules(),
+		text:   "GET /users\nauthorization: Bearer token\nAccept: */*",
+		expect: "GET /users\nauthorization: ******\nAccept: */*",
+	}, {
+		name:   "default header in JSON",
+		rules:  secret.DefaultRules(),
+		text:   `{"header":{"Accept":["*/*"],"Cookie":["session=1"]}}`,
+		expect: `{"header":{"Accept":["*/*"],"Cookie":"******"}}`,
+	}, {
+		name:   "default fields of any depth",
+		rules:  secret.DefaultRules(),
+		text:   `{"name":"a<b>","user":{"password":"p","tokens":[{"access_token":"t"}]}}`,
+		expect: `{"name":"a<b>","user":{"password":"******","tokens":[{"access_token":"******"}]}}`,
+	}, {
+		name:   "the JSON which is escaped in a string",
+		rules:  secret.DefaultRules(),
+		text:   `{"body":"{\"password\":\"p\",\"name\":\"a\"}"}`,
+		expect: `{"body":"{\"password\":\"******\",\"name\":\"a\"}"}`,
+	}, {
+		name:   "field in the text",
+		rules:  secret.DefaultRules(),
+		text:   `unexpected body: {"password": "p"`,
+		expect: `unexpected body: {"password": "******"`,
+	}, {
+		name:   "nothing is masked",
+		rules:  secret.DefaultRules(),
+		text:   `{"b": 1, "a": 2}`,
+		expect: `{"b": 1, "a": 2}`,
+	}, {
+		name:   "field paths",
+		rules:  secret.Rules{Fields: []string{"$.items[*].key", "user.id"}},
+		text:   `{"items":[{"key":"k","value":1}],"user":{"id":1},"id":2}`,
+		expect: `{"id":2,"items":[{"key":"******","value":1}],"user":{"id":"******"}}`,
+	}, {
+		name:   "patterns",
+		rules:  secret.Rules{Patterns: []string{`ghp_[a-zA-Z0-9]+`}},
+		text:   "token ghp_abc123 is used",
+		expect: "token ****** is used",
+	}, {
+		name:   "no rules",
+		rules:  secret.Rules{},
+		text:   `{"password":"p"}`,
+		expect: `{"password":"p"}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, secret.SetRules(tt.rules))
+			assert.Equal(t, tt.expect, secret.Redact(tt.text))
+		})
+	}
+
+	assert.Error(t, secret.SetRules(secret.Rules{Patterns: []string{"("}}))
+}
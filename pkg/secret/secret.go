@@ -0,0 +1,113 @@
+package secret
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Provider represents a source of the secrets
+type Provider interface {
+	// Get returns the value of the secret, the ok is false if it's not found
+	Get(name string) (value string, ok bool, err error)
+}
+
+// Mask is the replacement of the secrets in the logs and the reports
+const Mask = "******"
+
+// NewProvider creates a provider with the URI. Supported URI formats:
+//
+//	env or env://PREFIX_
+//	file://secrets.yaml
+//	vault://mount/path?address=https://vault:8200&kv=2
+func NewProvider(uri string) (provider Provider, err error) {
+	if uri == "env" {
+		provider = NewEnvProvider("")
+		return
+	}
+
+	var secretURL *url.URL
+	if secretURL, err = url.Parse(uri); err != nil {
+		return
+	}
+
+	switch secretURL.Scheme {
+	case "env":
+		provider = NewEnvProvider(secretURL.Host)
+	case "file":
+		provider = NewFileProvider(secretURL.Host + secretURL.Path)
+	case "vault":
+		query := secretURL.Query()
+		provider = NewVaultProvider(query.Get("address"), secretURL.Host,
+			strings.TrimPrefix(secretURL.Path, "/"), query.Get("kv") != "1")
+	default:
+		err = fmt.Errorf("not supported secret provider: '%s'", uri)
+	}
+	return
+}
+
+var (
+	providers = []Provider{NewEnvProvider("")}
+	redacted  = map[string]struct{}{}
+	lock      sync.RWMutex
+)
+
+// SetProviders replaces the providers, the secret is looked up in them one by one.
+// The environment variables are the only provider by default.
+func SetProviders(items ...Provider) {
+	lock.Lock()
+	defer lock.Unlock()
+	providers = items
+}
+
+// Get returns the value of the secret from the first provider which has it,
+// the value will be redacted from the logs and the reports
+func Get(name string) (value string, err error) {
+	lock.RLock()
+	items := providers
+	lock.RUnlock()
+
+	for _, provider := range items {
+		var ok bool
+		if value, ok, err = provider.Get(name); err != nil {
+			err = fmt.Errorf("failed to get secret %q: %v", name, err)
+			return
+		} else if ok {
+			addRedacted(value)
+			return
+		}
+	}
+	err = fmt.Errorf("secret %q is not found", name)
+	return
+}
+
+func addRedacted(value string) {
+	if value == "" {
+		return
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	redacted[value] = struct{}{}
+}
+
+// Redact replaces the values of the secrets which have been used with the mask, then applies the redaction rules
+func Redact(text string) string {
+	lock.RLock()
+	values := make([]string, 0, len(redacted))
+	for value := range redacted {
+		values = append(values, value)
+	}
+	current := rules
+	lock.RUnlock()
+
+	// the longer ones go first, in case a secret contains another one
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, value := range values {
+		text = strings.ReplaceAll(text, value, Mask)
+	}
+	return current.apply(text)
+}
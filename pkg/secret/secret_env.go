@@ -0,0 +1,18 @@
+package secret
+
+import "os"
+
+type envProvider struct {
+	prefix string
+}
+
+// NewEnvProvider creates a provider which reads the environment variables, the name of the variable is the prefix plus the secret name
+func NewEnvProvider(prefix string) Provider {
+	return &envProvider{prefix: prefix}
+}
+
+// Get returns the value of the environment variable
+func (p *envProvider) Get(name string) (value string, ok bool, err error) {
+	value, ok = os.LookupEnv(p.prefix + name)
+	return
+}
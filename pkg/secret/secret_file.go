@@ -0,0 +1,38 @@
+package secret
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ghodss/yaml"
+)
+
+type fileProvider struct {
+	path    string
+	secrets map[string]string
+	err     error
+	once    sync.Once
+}
+
+// NewFileProvider creates a provider which reads the secrets from a YAML or JSON file, the file is read once
+func NewFileProvider(path string) Provider {
+	return &fileProvider{path: path}
+}
+
+// Get returns the value of the key in the file
+func (p *fileProvider) Get(name string) (value string, ok bool, err error) {
+	p.once.Do(func() {
+		var data []byte
+		if data, p.err = os.ReadFile(p.path); p.err == nil {
+			if p.err = yaml.Unmarshal(data, &p.secrets); p.err != nil {
+				p.err = fmt.Errorf("invalid secret file %s: %v", p.path, p.err)
+			}
+		}
+	})
+
+	if err = p.err; err == nil {
+		value, ok = p.secrets[name]
+	}
+	return
+}
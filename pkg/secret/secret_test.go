@@ -0,0 +1,96 @@
+package secret_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/secret"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProvider(t *testing.T) {
+	for _, uri := range []string{"env", "env://ATEST_", "file://testdata/secrets.yaml", "vault://secret/atest?address=http://foo"} {
+		provider, err := secret.NewProvider(uri)
+		assert.NoError(t, err, uri)
+		assert.NotNil(t, provider, uri)
+	}
+
+	_, err := secret.NewProvider("fake://foo")
+	assert.Error(t, err)
+}
+
+func TestProviders(t *testing.T) {
+	os.Setenv("ATEST_TOKEN", "env-token")
+	defer os.Unsetenv("ATEST_TOKEN")
+	defer secret.SetProviders(secret.NewEnvProvider(""))
+
+	t.Run("env", func(t *testing.T) {
+		value, ok, err := secret.NewEnvProvider("ATEST_").Get("TOKEN")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "env-token", value)
+
+		_, ok, err = secret.NewEnvProvider("ATEST_").Get("FAKE")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		value, ok, err := secret.NewFileProvider("testdata/secrets.yaml").Get("password")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "p@ss", value)
+
+		_, _, err = secret.NewFileProvider("testdata/fake.yaml").Get("password")
+		assert.Error(t, err)
+	})
+
+	t.Run("vault", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Get("/v1/secret/data/atest").MatchHeader("X-Vault-Token", "root").
+			Reply(http.StatusOK).JSON(`{"data":{"data":{"token":"vault-token"},"metadata":{"version":1}}}`)
+		gock.New(urlFoo).Get("/v1/kv/atest").
+			Reply(http.StatusOK).JSON(`{"data":{"token":"v1-token"}}`)
+		gock.New(urlFoo).Get("/v1/secret/data/forbidden").Reply(http.StatusForbidden)
+
+		os.Setenv("VAULT_TOKEN", "root")
+		defer os.Unsetenv("VAULT_TOKEN")
+		provider := secret.NewVaultProvider(urlFoo, "secret", "atest", true)
+		value, ok, err := provider.Get("token")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "vault-token", value)
+
+		// the secret is read once
+		_, ok, err = provider.Get("fake")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		value, _, err = secret.NewVaultProvider(urlFoo, "kv", "atest", false).Get("token")
+		assert.NoError(t, err)
+		assert.Equal(t, "v1-token", value)
+
+		_, _, err = secret.NewVaultProvider(urlFoo, "secret", "forbidden", true).Get("token")
+		assert.ErrorContains(t, err, "unexpected status code 403")
+	})
+
+	t.Run("get and redact", func(t *testing.T) {
+		secret.SetProviders(secret.NewEnvProvider("ATEST_"), secret.NewFileProvider("testdata/secrets.yaml"))
+		value, err := secret.Get("TOKEN")
+		assert.NoError(t, err)
+		assert.Equal(t, "env-token", value)
+
+		value, err = secret.Get("token")
+		assert.NoError(t, err)
+		assert.Equal(t, "file-token", value)
+
+		_, err = secret.Get("fake")
+		assert.ErrorContains(t, err, `secret "fake" is not found`)
+
+		assert.Equal(t, "Bearer ****** and ******, but not p@ss", secret.Redact("Bearer env-token and file-token, but not p@ss"))
+	})
+}
+
+const urlFoo = "http://foo"
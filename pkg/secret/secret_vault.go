@@ -0,0 +1,101 @@
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+type vaultProvider struct {
+	address string
+	token   string
+	mount   string
+	path    string
+	kv2     bool
+	client  *http.Client
+	secrets map[string]interface{}
+	err     error
+	once    sync.Once
+}
+
+// NewVaultProvider creates a provider which reads the key/value secret of HashiCorp Vault, the secret is read once.
+// The address and the token come from the environment variables VAULT_ADDR and VAULT_TOKEN if they're empty.
+func NewVaultProvider(address, mount, path string, kv2 bool) Provider {
+	return &vaultProvider{
+		address: strings.TrimSuffix(emptyThenDefault(address, os.Getenv("VAULT_ADDR")), "/"),
+		token:   os.Getenv("VAULT_TOKEN"),
+		mount:   mount,
+		path:    path,
+		kv2:     kv2,
+		client:  http.DefaultClient,
+	}
+}
+
+// Get returns the value of the key in the secret
+func (p *vaultProvider) Get(name string) (value string, ok bool, err error) {
+	p.once.Do(func() {
+		p.secrets, p.err = p.read()
+	})
+
+	if err = p.err; err != nil {
+		return
+	}
+	var val interface{}
+	if val, ok = p.secrets[name]; ok {
+		value = fmt.Sprintf("%v", val)
+	}
+	return
+}
+
+func (p *vaultProvider) read() (secrets map[string]interface{}, err error) {
+	api := fmt.Sprintf("%s/v1/%s/%s", p.address, p.mount, p.path)
+	if p.kv2 {
+		api = fmt.Sprintf("%s/v1/%s/data/%s", p.address, p.mount, p.path)
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequest(http.MethodGet, api, nil); err != nil {
+		return
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	var resp *http.Response
+	if resp, err = p.client.Do(req); err != nil {
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var data []byte
+	if data, err = io.ReadAll(resp.Body); err != nil {
+		return
+	} else if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, api)
+		return
+	}
+
+	// the version 2 of the key/value engine wraps the secret with the metadata
+	result := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err = json.Unmarshal(data, &result); err != nil {
+		return
+	}
+	secrets = result.Data
+	if inner, ok := secrets["data"].(map[string]interface{}); ok && p.kv2 {
+		secrets = inner
+	}
+	return
+}
+
+func emptyThenDefault(val, defaultVal string) string {
+	if val == "" {
+		val = defaultVal
+	}
+	return val
+}
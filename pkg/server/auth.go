@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// AuthorizationKey is the metadata key of the bearer token
+	AuthorizationKey = "authorization"
+	// TeamKey is the metadata key of the team, it could be omitted if the user belongs to only one team
+	TeamKey = "x-atest-team"
+)
+
+// authorize authenticates the request, then checks the permission in the requested team.
+// The returned context carries the identity. Only the authentication is required if the permission is empty.
+func (s *server) authorize(ctx context.Context, perm auth.Permission) (context.Context, error) {
+	if s.authenticator == nil {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := strings.TrimSpace(strings.TrimPrefix(getMetadata(md, AuthorizationKey), "Bearer "))
+	if token == "" {
+		return ctx, status.Error(codes.Unauthenticated, "the bearer token is required")
+	}
+
+	user, err := s.authenticator.Authenticate(ctx, token)
+	if err != nil {
+		return ctx, status.Errorf(codes.Unauthenticated, "failed to authenticate: %v", err)
+	}
+	if perm == "" {
+		return auth.WithUser(ctx, user, ""), nil
+	}
+
+	var team string
+	if team, err = user.Team(getMetadata(md, TeamKey)); err != nil {
+		return ctx, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if !user.Can(team, perm) {
+		return ctx, status.Errorf(codes.PermissionDenied, "user '%s' has no '%s' permission in team '%s'", user.Name, perm, team)
+	}
+	return auth.WithUser(ctx, user, team), nil
+}
+
+func getMetadata(md metadata.MD, key string) (val string) {
+	if values := md.Get(key); len(values) > 0 {
+		val = values[0]
+	}
+	return
+}
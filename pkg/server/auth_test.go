@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/auth"
+	"github.com/linuxsuren/api-testing/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMultiTenantServer(t *testing.T) {
+	suiteStore := store.NewLocalStore(t.TempDir())
+	server := NewRemoteServerWithAuth(suiteStore, auth.NewTokenAuthenticator([]auth.TokenConfig{{
+		Token: "alice",
+		User:  "alice",
+		Teams: map[string]auth.Role{"team-a": auth.RoleEditor, "team-b": auth.RoleViewer},
+	}, {
+		Token: "bob",
+		User:  "bob",
+		Teams: map[string]auth.Role{"team-b": auth.RoleRunner},
+	}}))
+
+	withToken := func(token, team string) context.Context {
+		md := metadata.Pairs(AuthorizationKey, "Bearer "+token)
+		if team != "" {
+			md.Set(TeamKey, team)
+		}
+		return metadata.NewIncomingContext(context.TODO(), md)
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := server.GetVersion(context.TODO(), &Empty{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, err = server.Sample(withToken("fake", ""), &Empty{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, err = server.GetVersion(withToken("alice", ""), &Empty{})
+		assert.Nil(t, err)
+	})
+
+	t.Run("team is required", func(t *testing.T) {
+		_, err := server.ListTestSuite(withToken("alice", ""), &Empty{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = server.ListTestSuite(withToken("bob", "team-a"), &Empty{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		reply, err := server.SaveTestSuite(withToken("alice", "team-a"), &TestSuiteSource{Name: "simple.yaml", Data: simpleSuite})
+		assert.Nil(t, err)
+		assert.Empty(t, reply.Error)
+
+		_, err = server.SaveTestSuite(withToken("alice", "team-b"), &TestSuiteSource{Name: "simple.yaml", Data: simpleSuite})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = server.DeleteTestSuite(withToken("bob", ""), &TestSuiteIdentity{Name: "simple.yaml"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = server.Run(withToken("alice", "team-b"), &TestTask{Kind: "suiteInStore", Data: "simple.yaml"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		// the inline test suites need the edit permission
+		for _, kind := range []string{"suite", "testcase", "testcaseInSuite"} {
+			_, err = server.Run(withToken("bob", ""), &TestTask{Kind: kind, Data: simpleSuite})
+			assert.Equal(t, codes.PermissionDenied, status.Code(err), kind)
+		}
+	})
+
+	t.Run("namespaces", func(t *testing.T) {
+		names, err := suiteStore.List()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"team-a/simple.yaml"}, names)
+
+		suites, err := server.ListTestSuite(withToken("alice", "team-a"), &Empty{})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"simple.yaml"}, suites.Names)
+
+		suites, err = server.ListTestSuite(withToken("bob", ""), &Empty{})
+		assert.Nil(t, err)
+		assert.Empty(t, suites.Names)
+
+		_, err = server.GetTestSuite(withToken("bob", ""), &TestSuiteIdentity{Name: "simple.yaml"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("results", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Get("/").Reply(http.StatusOK).JSON(&server)
+		gock.New(urlFoo).Get("/").Reply(http.StatusOK).JSON(&server)
+		_, err := server.Run(withToken("alice", "team-a"), &TestTask{Kind: "suiteInStore", Data: "simple.yaml"})
+		assert.Nil(t, err)
+
+		_, err = server.Run(withToken("bob", ""), &TestTask{Kind: "suiteInStore", Data: "simple.yaml"})
+		assert.NotNil(t, err)
+
+		results, err := server.ListResults(withToken("alice", "team-a"), &Empty{})
+		if assert.Nil(t, err) && assert.Equal(t, 1, len(results.Items)) {
+			assert.Equal(t, "alice", results.Items[0].User)
+			assert.Empty(t, results.Items[0].Error)
+		}
+
+		results, err = server.ListResults(withToken("bob", ""), &Empty{})
+		if assert.Nil(t, err) && assert.Equal(t, 1, len(results.Items)) {
+			assert.Equal(t, "bob", results.Items[0].User)
+			assert.NotEmpty(t, results.Items[0].Error)
+		}
+	})
+}
+
+func TestResultHistory(t *testing.T) {
+	history := newResultHistory(2)
+	history.record(context.TODO(), nil, &HelloReply{Message: "1"}, nil)
+	history.record(context.TODO(), nil, &HelloReply{Message: "2"}, nil)
+	history.record(context.TODO(), nil, &HelloReply{Message: "3"}, nil)
+
+	items := history.list(context.TODO())
+	if assert.Equal(t, 2, len(items)) {
+		assert.Equal(t, "3", items[0].Message)
+		assert.Equal(t, "2", items[1].Message)
+	}
+
+	var empty *resultHistory
+	empty.record(context.TODO(), nil, nil, nil)
+	assert.Empty(t, empty.list(context.TODO()))
+}
@@ -5,24 +5,44 @@ import (
 	"bytes"
 	context "context"
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
+	"sync"
 
+	"github.com/linuxsuren/api-testing/pkg/apispec"
+	"github.com/linuxsuren/api-testing/pkg/auth"
+	"github.com/linuxsuren/api-testing/pkg/limit"
 	"github.com/linuxsuren/api-testing/pkg/render"
 	"github.com/linuxsuren/api-testing/pkg/runner"
+	"github.com/linuxsuren/api-testing/pkg/store"
 	"github.com/linuxsuren/api-testing/pkg/testing"
 	"github.com/linuxsuren/api-testing/pkg/version"
 	"github.com/linuxsuren/api-testing/sample"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
 )
 
 type server struct {
 	UnimplementedRunnerServer
+	store         store.Store
+	storeOnce     sync.Once
+	authenticator auth.Authenticator
+	results       *resultHistory
 }
 
-// NewRemoteServer creates a remote server instance
-func NewRemoteServer() RunnerServer {
-	return &server{}
+// NewRemoteServer creates a remote server instance, the test suites are kept in the store
+func NewRemoteServer(suiteStore store.Store) RunnerServer {
+	return NewRemoteServerWithAuth(suiteStore, nil)
+}
+
+// NewRemoteServerWithAuth creates a multi-tenant remote server instance. Every request needs a bearer token,
+// the test suites and results of each team are kept in its own namespace. The authentication is disabled if
+// the authenticator is nil.
+func NewRemoteServerWithAuth(suiteStore store.Store, authenticator auth.Authenticator) RunnerServer {
+	return &server{
+		store:         suiteStore,
+		authenticator: authenticator,
+		results:       newResultHistory(defaultResultLimit),
+	}
 }
 
 func withDefaultValue(old, defVal any) any {
@@ -34,23 +54,31 @@ func withDefaultValue(old, defVal any) any {
 
 // Run start to run the test task
 func (s *server) Run(ctx context.Context, task *TestTask) (reply *HelloReply, err error) {
-	task.Level = withDefaultValue(task.Level, "info").(string)
-	task.Env = withDefaultValue(task.Env, map[string]string{}).(map[string]string)
+	// the inline test suites could run any commands in the prepare steps and the hooks,
+	// so only the editors are allowed to run them. The stored ones are saved by the editors as well
+	permission := auth.PermissionRun
+	if task.Kind != "suiteInStore" {
+		permission = auth.PermissionEdit
+	}
+	if ctx, err = s.authorize(ctx, permission); err != nil {
+		return
+	}
 
 	var suite *testing.TestSuite
+	defer func() {
+		s.results.record(ctx, suite, reply, err)
+	}()
 
-	// TODO may not safe in multiple threads
-	oldEnv := map[string]string{}
-	for key, val := range task.Env {
-		oldEnv[key] = os.Getenv(key)
-		os.Setenv(key, val)
-	}
+	task.Level = withDefaultValue(task.Level, "info").(string)
 
-	defer func() {
-		for key, val := range oldEnv {
-			os.Setenv(key, val)
+	if task.Kind == "suiteInStore" {
+		var data []byte
+		if data, err = s.getStore(ctx).Load(task.Data); err != nil {
+			return
 		}
-	}()
+		task.Kind = "suite"
+		task.Data = string(data)
+	}
 
 	switch task.Kind {
 	case "suite":
@@ -65,6 +93,8 @@ func (s *server) Run(ctx context.Context, task *TestTask) (reply *HelloReply, er
 		if testCase, err = testing.ParseTestCaseFromData([]byte(task.Data)); err != nil {
 			return
 		}
+		// the dependencies are not available when running a single test case
+		testCase.DependsOn = nil
 		suite = &testing.TestSuite{
 			Items: []testing.TestCase{*testCase},
 		}
@@ -87,7 +117,7 @@ func (s *server) Run(ctx context.Context, task *TestTask) (reply *HelloReply, er
 		if targetTestcase != nil {
 			parentCases := findParentTestCases(targetTestcase, suite)
 			fmt.Printf("find %d parent cases\n", len(parentCases))
-			suite.Items = append(parentCases, *targetTestcase)
+			suite.Items = append(withDependencies(suite.Items, parentCases, task.CaseName), *targetTestcase)
 		} else {
 			err = fmt.Errorf("cannot found testcase %s", task.CaseName)
 			return
@@ -99,7 +129,15 @@ func (s *server) Run(ctx context.Context, task *TestTask) (reply *HelloReply, er
 
 	fmt.Printf("prepare to run: %s, with level: %s\n", suite.Name, task.Level)
 	fmt.Printf("task kind: %s, %d to run\n", task.Kind, len(suite.Items))
+	// the environment values of the task are referenced as {{.env.<key>}}, they're not visible to the other tasks
 	dataContext := map[string]interface{}{}
+	if len(task.Env) > 0 {
+		env := map[string]interface{}{}
+		for key, val := range task.Env {
+			env[key] = val
+		}
+		dataContext[testing.EnvKey] = env
+	}
 
 	var result string
 	if result, err = render.Render("base api", suite.API, dataContext); err == nil {
@@ -113,40 +151,202 @@ func (s *server) Run(ctx context.Context, task *TestTask) (reply *HelloReply, er
 
 	buf := new(bytes.Buffer)
 	reply = &HelloReply{}
+	ctx = runner.WithCookieJar(ctx)
+	if suite.RateLimit > 0 {
+		ctx = runner.WithRateLimiter(ctx, limit.NewDefaultRateLimiter(int32(suite.RateLimit), 1))
+	}
+	if suite.OpenAPI != "" {
+		spec, specErr := apispec.LoadOpenAPI(suite.OpenAPI, "")
+		if specErr != nil {
+			reply.Error = specErr.Error()
+			return
+		}
+		ctx = runner.WithOpenAPI(ctx, spec)
+	}
+
+	suiteRunner := runner.NewSuiteRunner(buf, task.Level, fakeruntime.DefaultExecer{})
+	defer func() {
+		if cleanErr := suiteRunner.Clean(ctx, suite, dataContext, reply.Error != ""); cleanErr != nil && reply.Error == "" {
+			reply.Error = cleanErr.Error()
+		}
+		reply.Message = buf.String()
+	}()
+	if prepareErr := suiteRunner.Prepare(ctx, suite, dataContext); prepareErr != nil {
+		reply.Error = prepareErr.Error()
+		return
+	}
+
+	var items []testing.TestCase
+	if items, err = testing.SortByDependencies(suite.Items); err != nil {
+		reply.Error = err.Error()
+		err = nil
+		return
+	}
+
+	// the cases run in the order of the dependencies. A failed case stops the run,
+	// or only skips the dependents of it if there are dependencies
+	explicit := testing.HasDependencies(items)
+	failed := map[string]bool{}
+	for _, testCase := range items {
+		if dependency := failedDependency(&testCase, failed); dependency != "" {
+			failed[testCase.Name] = true
+			fmt.Fprintf(buf, "skipped '%s' due to the failed dependency '%s'\n", testCase.Name, dependency)
+			continue
+		}
 
-	for _, testCase := range suite.Items {
 		simpleRunner := runner.NewSimpleTestCaseRunner()
 		simpleRunner.WithOutputWriter(buf)
 		simpleRunner.WithWriteLevel(task.Level)
 
-		// reuse the API prefix
-		if strings.HasPrefix(testCase.Request.API, "/") {
-			testCase.Request.API = fmt.Sprintf("%s%s", suite.API, testCase.Request.API)
+		suite.Inherit(&testCase)
+
+		thinkTime, thinkTimeErr := runner.ParseThinkTime(testCase.ThinkTime)
+		if thinkTimeErr != nil {
+			reply.Error = thinkTimeErr.Error()
+			break
 		}
 
 		if output, testErr := simpleRunner.RunTestCase(&testCase, dataContext, ctx); testErr == nil {
 			dataContext[testCase.Name] = output
 		} else {
-			reply.Error = testErr.Error()
-			break
+			failed[testCase.Name] = true
+			if reply.Error == "" {
+				reply.Error = testErr.Error()
+			}
+			if !explicit {
+				break
+			}
+		}
+		thinkTime.Pause(ctx, nil)
+	}
+	return
+}
+
+// failedDependency returns the first failed dependency of the test case
+func failedDependency(testCase *testing.TestCase, failed map[string]bool) string {
+	for _, dependency := range testCase.DependsOn {
+		if failed[dependency] {
+			return dependency
+		}
+	}
+	return ""
+}
+
+// withDependencies returns the parent cases with the dependencies of them and the target one, the target is not included
+func withDependencies(items, parentCases []testing.TestCase, target string) (cases []testing.TestCase) {
+	names := []string{target}
+	for _, item := range parentCases {
+		names = append(names, item.Name)
+	}
+	scope := testing.ExpandDependencies(items, names)
+
+	added := map[string]bool{target: true}
+	for _, item := range parentCases {
+		added[item.Name] = true
+	}
+	cases = parentCases
+	for _, item := range items {
+		if item.InScope(scope) && !added[item.Name] {
+			added[item.Name] = true
+			cases = append(cases, item)
 		}
 	}
-	reply.Message = buf.String()
 	return
 }
 
 // GetVersion returns the version
 func (s *server) GetVersion(ctx context.Context, in *Empty) (reply *HelloReply, err error) {
+	if _, err = s.authorize(ctx, ""); err != nil {
+		return
+	}
 	reply = &HelloReply{Message: version.GetVersion()}
 	return
 }
 
 // Sample returns a sample of the test task
 func (s *server) Sample(ctx context.Context, in *Empty) (reply *HelloReply, err error) {
+	if _, err = s.authorize(ctx, ""); err != nil {
+		return
+	}
 	reply = &HelloReply{Message: sample.TestSuiteGitLab}
 	return
 }
 
+// ListTestSuite returns the names of all the test suites in the store
+func (s *server) ListTestSuite(ctx context.Context, in *Empty) (reply *TestSuites, err error) {
+	if ctx, err = s.authorize(ctx, auth.PermissionRead); err != nil {
+		return
+	}
+
+	var names []string
+	if names, err = s.getStore(ctx).List(); err == nil {
+		reply = &TestSuites{Names: names}
+	}
+	return
+}
+
+// GetTestSuite returns the content of the test suite from the store
+func (s *server) GetTestSuite(ctx context.Context, in *TestSuiteIdentity) (reply *TestSuiteSource, err error) {
+	if ctx, err = s.authorize(ctx, auth.PermissionRead); err != nil {
+		return
+	}
+
+	var data []byte
+	if data, err = s.getStore(ctx).Load(in.Name); err == nil {
+		reply = &TestSuiteSource{Name: in.Name, Data: string(data)}
+	}
+	return
+}
+
+// SaveTestSuite validates the test suite then saves it into the store
+func (s *server) SaveTestSuite(ctx context.Context, in *TestSuiteSource) (reply *HelloReply, err error) {
+	if ctx, err = s.authorize(ctx, auth.PermissionEdit); err != nil {
+		return
+	}
+
+	reply = &HelloReply{}
+	if _, err = testing.Parse([]byte(in.Data)); err != nil {
+		reply.Error = err.Error()
+		err = nil
+		return
+	}
+
+	err = s.getStore(ctx).Save(in.Name, []byte(in.Data))
+	return
+}
+
+// DeleteTestSuite removes the test suite from the store
+func (s *server) DeleteTestSuite(ctx context.Context, in *TestSuiteIdentity) (reply *HelloReply, err error) {
+	if ctx, err = s.authorize(ctx, auth.PermissionEdit); err != nil {
+		return
+	}
+
+	reply = &HelloReply{}
+	err = s.getStore(ctx).Delete(in.Name)
+	return
+}
+
+// ListResults returns the recent results of the current team
+func (s *server) ListResults(ctx context.Context, in *Empty) (reply *TestResults, err error) {
+	if ctx, err = s.authorize(ctx, auth.PermissionRead); err == nil {
+		reply = &TestResults{Items: s.results.list(ctx)}
+	}
+	return
+}
+
+// getStore returns the store of the current team
+func (s *server) getStore(ctx context.Context) store.Store {
+	s.storeOnce.Do(func() {
+		if s.store == nil {
+			s.store = store.NewLocalStore(".")
+		}
+	})
+	if identity := auth.FromContext(ctx); identity != nil && identity.Team != "" {
+		return store.NewNamespaceStore(s.store, identity.Team)
+	}
+	return s.store
+}
+
 func findParentTestCases(testcase *testing.TestCase, suite *testing.TestSuite) (testcases []testing.TestCase) {
 	reg, matchErr := regexp.Compile(`(.*?\{\{.*\.\w*.*?\}\})`)
 	targetReg, targetErr := regexp.Compile(`\.\w*`)
@@ -3,11 +3,13 @@ package server
 import (
 	"context"
 	"net/http"
+	"os"
 	"testing"
 
 	_ "embed"
 
 	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/store"
 	atesting "github.com/linuxsuren/api-testing/pkg/testing"
 	"github.com/linuxsuren/api-testing/sample"
 	"github.com/stretchr/testify/assert"
@@ -18,7 +20,7 @@ const (
 )
 
 func TestRemoteServer(t *testing.T) {
-	server := NewRemoteServer()
+	server := NewRemoteServer(nil)
 	_, err := server.Run(context.TODO(), &TestTask{
 		Kind: "fake",
 	})
@@ -68,6 +70,113 @@ func TestRemoteServer(t *testing.T) {
 	assert.Equal(t, sample.TestSuiteGitLab, ver.Message)
 }
 
+func TestTestSuiteInStore(t *testing.T) {
+	server := NewRemoteServer(store.NewLocalStore(t.TempDir()))
+
+	reply, err := server.SaveTestSuite(context.TODO(), &TestSuiteSource{Name: "invalid.yaml", Data: "fake"})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, reply.Error)
+
+	reply, err = server.SaveTestSuite(context.TODO(), &TestSuiteSource{Name: "simple.yaml", Data: simpleSuite})
+	assert.Nil(t, err)
+	assert.Empty(t, reply.Error)
+
+	suites, err := server.ListTestSuite(context.TODO(), &Empty{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"simple.yaml"}, suites.Names)
+
+	source, err := server.GetTestSuite(context.TODO(), &TestSuiteIdentity{Name: "simple.yaml"})
+	assert.Nil(t, err)
+	assert.Equal(t, simpleSuite, source.Data)
+
+	gock.New(urlFoo).Get("/").Reply(http.StatusOK).JSON(&server)
+	gock.New(urlFoo).Get("/").Reply(http.StatusOK).JSON(&server)
+	_, err = server.Run(context.TODO(), &TestTask{Kind: "suiteInStore", Data: "simple.yaml"})
+	assert.Nil(t, err)
+
+	_, err = server.DeleteTestSuite(context.TODO(), &TestSuiteIdentity{Name: "simple.yaml"})
+	assert.Nil(t, err)
+
+	_, err = server.GetTestSuite(context.TODO(), &TestSuiteIdentity{Name: "simple.yaml"})
+	assert.NotNil(t, err)
+}
+
+func TestRunWithDependencies(t *testing.T) {
+	const suite = `name: dependencies
+api: http://foo
+items:
+- name: report
+  dependsOn: [create]
+  request:
+    api: /report
+- name: create
+  request:
+    api: /create
+    method: POST
+- name: list
+  request:
+    api: /list
+`
+	server := NewRemoteServer(nil)
+
+	t.Run("run in the order of the dependencies", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Post("/create").Reply(http.StatusOK).JSON(map[string]string{})
+		gock.New(urlFoo).Get("/report").Reply(http.StatusOK).JSON(map[string]string{})
+		gock.New(urlFoo).Get("/list").Reply(http.StatusOK).JSON(map[string]string{})
+
+		reply, err := server.Run(context.TODO(), &TestTask{Kind: "suite", Data: suite})
+		assert.Nil(t, err)
+		assert.Empty(t, reply.Error)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("skip the dependents of the failed case", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Post("/create").Reply(http.StatusInternalServerError)
+		gock.New(urlFoo).Get("/list").Reply(http.StatusOK).JSON(map[string]string{})
+
+		reply, err := server.Run(context.TODO(), &TestTask{Kind: "suite", Data: suite})
+		assert.Nil(t, err)
+		assert.Contains(t, reply.Error, "500")
+		assert.Contains(t, reply.Message, "skipped 'report' due to the failed dependency 'create'")
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("run the dependencies of the test case", func(t *testing.T) {
+		defer gock.Off()
+		gock.New(urlFoo).Post("/create").Reply(http.StatusOK).JSON(map[string]string{})
+		gock.New(urlFoo).Get("/report").Reply(http.StatusOK).JSON(map[string]string{})
+
+		reply, err := server.Run(context.TODO(), &TestTask{Kind: "testcaseInSuite", Data: suite, CaseName: "report"})
+		assert.Nil(t, err)
+		assert.Empty(t, reply.Error)
+		assert.True(t, gock.IsDone())
+	})
+}
+
+func TestRunWithEnv(t *testing.T) {
+	const suite = `name: env
+api: "{{.env.server}}"
+items:
+- name: users
+  request:
+    api: /users
+`
+	defer gock.Off()
+	gock.New(urlFoo).Get("/users").Reply(http.StatusOK).JSON(map[string]string{})
+
+	reply, err := NewRemoteServer(nil).Run(context.TODO(), &TestTask{
+		Kind: "suite",
+		Data: suite,
+		Env:  map[string]string{"server": urlFoo},
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, reply.Error)
+	assert.True(t, gock.IsDone())
+	assert.Empty(t, os.Getenv("server"))
+}
+
 func TestFindParentTestCases(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/linuxsuren/api-testing/pkg/auth"
+	"github.com/linuxsuren/api-testing/pkg/testing"
+)
+
+const defaultResultLimit = 100
+
+// resultHistory keeps the recent results of each team in memory
+type resultHistory struct {
+	lock    sync.Mutex
+	limit   int
+	results map[string][]*TestResult
+}
+
+func newResultHistory(limit int) *resultHistory {
+	return &resultHistory{limit: limit, results: map[string][]*TestResult{}}
+}
+
+func (h *resultHistory) record(ctx context.Context, suite *testing.TestSuite, reply *HelloReply, err error) {
+	if h == nil {
+		return
+	}
+
+	result := &TestResult{Time: time.Now().Format(time.RFC3339)}
+	if suite != nil {
+		result.Name = suite.Name
+	}
+	if reply != nil {
+		result.Message = reply.Message
+		result.Error = reply.Error
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	team := ""
+	if identity := auth.FromContext(ctx); identity != nil {
+		team = identity.Team
+		result.User = identity.User.Name
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	items := append(h.results[team], result)
+	if len(items) > h.limit {
+		items = items[len(items)-h.limit:]
+	}
+	h.results[team] = items
+}
+
+// list returns the results of the current team, the latest one comes first
+func (h *resultHistory) list(ctx context.Context) (items []*TestResult) {
+	if h == nil {
+		return
+	}
+
+	team := ""
+	if identity := auth.FromContext(ctx); identity != nil {
+		team = identity.Team
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	results := h.results[team]
+	for i := len(results) - 1; i >= 0; i-- {
+		items = append(items, results[i])
+	}
+	return
+}
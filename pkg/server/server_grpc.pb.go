@@ -25,6 +25,11 @@ type RunnerClient interface {
 	Run(ctx context.Context, in *TestTask, opts ...grpc.CallOption) (*HelloReply, error)
 	Sample(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*HelloReply, error)
 	GetVersion(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*HelloReply, error)
+	ListTestSuite(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TestSuites, error)
+	GetTestSuite(ctx context.Context, in *TestSuiteIdentity, opts ...grpc.CallOption) (*TestSuiteSource, error)
+	SaveTestSuite(ctx context.Context, in *TestSuiteSource, opts ...grpc.CallOption) (*HelloReply, error)
+	DeleteTestSuite(ctx context.Context, in *TestSuiteIdentity, opts ...grpc.CallOption) (*HelloReply, error)
+	ListResults(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TestResults, error)
 }
 
 type runnerClient struct {
@@ -62,6 +67,51 @@ func (c *runnerClient) GetVersion(ctx context.Context, in *Empty, opts ...grpc.C
 	return out, nil
 }
 
+func (c *runnerClient) ListTestSuite(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TestSuites, error) {
+	out := new(TestSuites)
+	err := c.cc.Invoke(ctx, "/server.Runner/ListTestSuite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runnerClient) GetTestSuite(ctx context.Context, in *TestSuiteIdentity, opts ...grpc.CallOption) (*TestSuiteSource, error) {
+	out := new(TestSuiteSource)
+	err := c.cc.Invoke(ctx, "/server.Runner/GetTestSuite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runnerClient) SaveTestSuite(ctx context.Context, in *TestSuiteSource, opts ...grpc.CallOption) (*HelloReply, error) {
+	out := new(HelloReply)
+	err := c.cc.Invoke(ctx, "/server.Runner/SaveTestSuite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runnerClient) DeleteTestSuite(ctx context.Context, in *TestSuiteIdentity, opts ...grpc.CallOption) (*HelloReply, error) {
+	out := new(HelloReply)
+	err := c.cc.Invoke(ctx, "/server.Runner/DeleteTestSuite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runnerClient) ListResults(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TestResults, error) {
+	out := new(TestResults)
+	err := c.cc.Invoke(ctx, "/server.Runner/ListResults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RunnerServer is the server API for Runner service.
 // All implementations must embed UnimplementedRunnerServer
 // for forward compatibility
@@ -69,6 +119,11 @@ type RunnerServer interface {
 	Run(context.Context, *TestTask) (*HelloReply, error)
 	Sample(context.Context, *Empty) (*HelloReply, error)
 	GetVersion(context.Context, *Empty) (*HelloReply, error)
+	ListTestSuite(context.Context, *Empty) (*TestSuites, error)
+	GetTestSuite(context.Context, *TestSuiteIdentity) (*TestSuiteSource, error)
+	SaveTestSuite(context.Context, *TestSuiteSource) (*HelloReply, error)
+	DeleteTestSuite(context.Context, *TestSuiteIdentity) (*HelloReply, error)
+	ListResults(context.Context, *Empty) (*TestResults, error)
 	mustEmbedUnimplementedRunnerServer()
 }
 
@@ -85,6 +140,21 @@ func (UnimplementedRunnerServer) Sample(context.Context, *Empty) (*HelloReply, e
 func (UnimplementedRunnerServer) GetVersion(context.Context, *Empty) (*HelloReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
 }
+func (UnimplementedRunnerServer) ListTestSuite(context.Context, *Empty) (*TestSuites, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTestSuite not implemented")
+}
+func (UnimplementedRunnerServer) GetTestSuite(context.Context, *TestSuiteIdentity) (*TestSuiteSource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTestSuite not implemented")
+}
+func (UnimplementedRunnerServer) SaveTestSuite(context.Context, *TestSuiteSource) (*HelloReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveTestSuite not implemented")
+}
+func (UnimplementedRunnerServer) DeleteTestSuite(context.Context, *TestSuiteIdentity) (*HelloReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTestSuite not implemented")
+}
+func (UnimplementedRunnerServer) ListResults(context.Context, *Empty) (*TestResults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListResults not implemented")
+}
 func (UnimplementedRunnerServer) mustEmbedUnimplementedRunnerServer() {}
 
 // UnsafeRunnerServer may be embedded to opt out of forward compatibility for this service.
@@ -152,6 +222,96 @@ func _Runner_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Runner_ListTestSuite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServer).ListTestSuite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/server.Runner/ListTestSuite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServer).ListTestSuite(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Runner_GetTestSuite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestSuiteIdentity)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServer).GetTestSuite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/server.Runner/GetTestSuite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServer).GetTestSuite(ctx, req.(*TestSuiteIdentity))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Runner_SaveTestSuite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestSuiteSource)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServer).SaveTestSuite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/server.Runner/SaveTestSuite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServer).SaveTestSuite(ctx, req.(*TestSuiteSource))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Runner_DeleteTestSuite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestSuiteIdentity)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServer).DeleteTestSuite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/server.Runner/DeleteTestSuite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServer).DeleteTestSuite(ctx, req.(*TestSuiteIdentity))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Runner_ListResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServer).ListResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/server.Runner/ListResults",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServer).ListResults(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Runner_ServiceDesc is the grpc.ServiceDesc for Runner service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -171,6 +331,26 @@ var Runner_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetVersion",
 			Handler:    _Runner_GetVersion_Handler,
 		},
+		{
+			MethodName: "ListTestSuite",
+			Handler:    _Runner_ListTestSuite_Handler,
+		},
+		{
+			MethodName: "GetTestSuite",
+			Handler:    _Runner_GetTestSuite_Handler,
+		},
+		{
+			MethodName: "SaveTestSuite",
+			Handler:    _Runner_SaveTestSuite_Handler,
+		},
+		{
+			MethodName: "DeleteTestSuite",
+			Handler:    _Runner_DeleteTestSuite_Handler,
+		},
+		{
+			MethodName: "ListResults",
+			Handler:    _Runner_ListResults_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/server/server.proto",
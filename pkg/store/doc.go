@@ -0,0 +1,2 @@
+// Package store provides the storage backends of the test suites
+package store
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"path"
+
+	"github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/linuxsuren/api-testing/pkg/util"
+)
+
+type storeLoader struct {
+	store Store
+	names []string
+	index int
+}
+
+// NewStoreLoader creates a test suite loader which reads from the store
+func NewStoreLoader(store Store) testing.Loader {
+	return &storeLoader{store: store, index: -1}
+}
+
+// HasMore returns if there are more test suites
+func (l *storeLoader) HasMore() bool {
+	l.index++
+	return l.index < len(l.names)
+}
+
+// Load returns the content of current test suite
+func (l *storeLoader) Load() ([]byte, error) {
+	return l.store.Load(l.names[l.index])
+}
+
+// Put adds the test suites which match the pattern
+func (l *storeLoader) Put(pattern string) (err error) {
+	var names []string
+	if names, err = l.store.List(); err != nil {
+		return
+	}
+
+	for _, item := range util.Expand(pattern) {
+		for _, name := range names {
+			var matched bool
+			if matched, err = path.Match(item, name); err != nil {
+				return
+			} else if !matched {
+				matched, _ = path.Match(item, path.Base(name))
+			}
+
+			if matched {
+				l.names = append(l.names, name)
+			}
+		}
+	}
+	return
+}
+
+// GetContext returns the context directory of current test suite
+func (l *storeLoader) GetContext() (ctx string) {
+	if contextStore, ok := l.store.(ContextStore); ok {
+		ctx = contextStore.GetContext(l.names[l.index])
+	}
+	return
+}
+
+// GetCount returns the count of test suites
+func (l *storeLoader) GetCount() int {
+	return len(l.names)
+}
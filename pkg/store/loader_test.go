@@ -0,0 +1,52 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreLoader(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		expect  int
+		hasErr  bool
+	}{{
+		name:    "all",
+		pattern: "*.yaml",
+		expect:  2,
+	}, {
+		name:    "brace expansion",
+		pattern: "{simple,nested}.yaml",
+		expect:  2,
+	}, {
+		name:    "specific",
+		pattern: "sub/nested.yaml",
+		expect:  1,
+	}, {
+		name:    "no match",
+		pattern: "fake.yaml",
+		expect:  0,
+	}, {
+		name:    "malformed pattern",
+		pattern: "[",
+		hasErr:  true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := store.NewStoreLoader(store.NewLocalStore("testdata"))
+			err := loader.Put(tt.pattern)
+			assert.Equal(t, tt.hasErr, err != nil, err)
+			assert.Equal(t, tt.expect, loader.GetCount())
+
+			for loader.HasMore() {
+				data, err := loader.Load()
+				assert.Nil(t, err)
+				assert.NotEmpty(t, data)
+				assert.NotEmpty(t, loader.GetContext())
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+)
+
+// Store represents a backend which holds the test suites
+type Store interface {
+	// List returns the names of all the test suites
+	List() ([]string, error)
+	// Load returns the content of the test suite
+	Load(name string) ([]byte, error)
+	// Save creates or updates the test suite
+	Save(name string, data []byte) error
+	// Delete removes the test suite
+	Delete(name string) error
+}
+
+// ContextStore represents a store which has a local context directory for a test suite,
+// it's useful for reading the request body from a file.
+type ContextStore interface {
+	GetContext(name string) string
+}
+
+// NewStore creates a store with the URI. Supported URI formats:
+//
+//	./suites or file://suites
+//	git+https://github.com/linuxsuren/api-testing.git#master
+//	s3://bucket/prefix?region=us-east-1&endpoint=http://localhost:9000
+//	configmap://namespace/name
+//	sql://driver?dsn=xxx&table=suites
+func NewStore(uri string, execer fakeruntime.Execer) (store Store, err error) {
+	if !strings.Contains(uri, "://") {
+		store = NewLocalStore(uri)
+		return
+	}
+
+	var storeURL *url.URL
+	if storeURL, err = url.Parse(uri); err != nil {
+		return
+	}
+
+	switch {
+	case storeURL.Scheme == "file":
+		store = NewLocalStore(storeURL.Host + storeURL.Path)
+	case strings.HasPrefix(storeURL.Scheme, "git+"):
+		branch := storeURL.Fragment
+		storeURL.Fragment = ""
+		store = NewGitStore(strings.TrimPrefix(storeURL.String(), "git+"), branch, execer)
+	case storeURL.Scheme == "s3":
+		query := storeURL.Query()
+		store = NewS3Store(storeURL.Host, strings.TrimPrefix(storeURL.Path, "/"),
+			query.Get("region"), query.Get("endpoint"))
+	case storeURL.Scheme == "configmap":
+		store = NewConfigMapStore(storeURL.Host, strings.TrimPrefix(storeURL.Path, "/"))
+	case storeURL.Scheme == "sql":
+		query := storeURL.Query()
+		store, err = NewDatabaseStore(storeURL.Host, query.Get("dsn"), query.Get("table"))
+	default:
+		err = fmt.Errorf("not supported store: '%s'", storeURL.Scheme)
+	}
+	return
+}
+
+func isSuiteFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+)
+
+type configMapStore struct {
+	namespace string
+	name      string
+	server    string
+	token     string
+}
+
+// NewConfigMapStore creates a store which is based on a Kubernetes ConfigMap, each key is a test suite.
+// Please set the environment variables KUBERNETES_SERVER and KUBERNETES_TOKEN before using it.
+func NewConfigMapStore(namespace, name string) Store {
+	return &configMapStore{
+		namespace: emptyThenDefault(namespace, "default"),
+		name:      name,
+		server:    strings.TrimSuffix(os.Getenv("KUBERNETES_SERVER"), "/"),
+		token:     os.Getenv("KUBERNETES_TOKEN"),
+	}
+}
+
+type configMap struct {
+	APIVersion string            `json:"apiVersion,omitempty"`
+	Kind       string            `json:"kind,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Data       map[string]string `json:"data"`
+}
+
+// List returns all the keys of the ConfigMap
+func (s *configMapStore) List() (names []string, err error) {
+	var cm *configMap
+	if cm, err = s.get(); err == nil {
+		for key := range cm.Data {
+			names = append(names, key)
+		}
+		sort.Strings(names)
+	}
+	return
+}
+
+// Load returns the value of the key
+func (s *configMapStore) Load(name string) (data []byte, err error) {
+	var cm *configMap
+	if cm, err = s.get(); err == nil {
+		if val, ok := cm.Data[name]; ok {
+			data = []byte(val)
+		} else {
+			err = fmt.Errorf("cannot find '%s' in ConfigMap %s/%s", name, s.namespace, s.name)
+		}
+	}
+	return
+}
+
+// Save puts the test suite into the ConfigMap, the ConfigMap will be created if it's not exist
+func (s *configMapStore) Save(name string, data []byte) (err error) {
+	if err = validConfigMapKey(name); err != nil {
+		return
+	}
+
+	var status int
+	patch := map[string]interface{}{"data": map[string]string{name: string(data)}}
+	if status, err = s.request(http.MethodPatch, s.resourceAPI(), patch, nil); err == nil && status == http.StatusNotFound {
+		cm := &configMap{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Metadata:   map[string]string{"name": s.name, "namespace": s.namespace},
+			Data:       map[string]string{name: string(data)},
+		}
+		status, err = s.request(http.MethodPost, s.collectionAPI(), cm, nil)
+	}
+	return checkStatus(status, err)
+}
+
+// Delete removes the key from the ConfigMap
+func (s *configMapStore) Delete(name string) error {
+	patch := map[string]interface{}{"data": map[string]interface{}{name: nil}}
+	return checkStatus(s.request(http.MethodPatch, s.resourceAPI(), patch, nil))
+}
+
+func (s *configMapStore) get() (cm *configMap, err error) {
+	cm = &configMap{}
+	err = checkStatus(s.request(http.MethodGet, s.resourceAPI(), nil, cm))
+	return
+}
+
+func (s *configMapStore) collectionAPI() string {
+	return fmt.Sprintf("%s/api/v1/namespaces/%s/configmaps", s.server, s.namespace)
+}
+
+func (s *configMapStore) resourceAPI() string {
+	return fmt.Sprintf("%s/%s", s.collectionAPI(), s.name)
+}
+
+func (s *configMapStore) request(method, api string, payload, result interface{}) (status int, err error) {
+	if s.server == "" || s.token == "" {
+		err = errors.New("KUBERNETES_SERVER and KUBERNETES_TOKEN are required")
+		return
+	}
+
+	var body io.Reader
+	if payload != nil {
+		var data []byte
+		if data, err = json.Marshal(payload); err != nil {
+			return
+		}
+		body = bytes.NewReader(data)
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequest(method, api, body); err != nil {
+		return
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.token))
+	if method == http.MethodPatch {
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+	} else {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	var resp *http.Response
+	if resp, err = kubernetes.GetClient().Do(req); err != nil {
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	status = resp.StatusCode
+	var data []byte
+	if data, err = io.ReadAll(resp.Body); err == nil && result != nil && status == http.StatusOK {
+		err = json.Unmarshal(data, result)
+	}
+	return
+}
+
+func checkStatus(status int, err error) error {
+	if err == nil && (status < http.StatusOK || status >= http.StatusMultipleChoices) {
+		err = fmt.Errorf("unexpected status code: %d", status)
+	}
+	return err
+}
+
+func validConfigMapKey(name string) (err error) {
+	for _, c := range name {
+		if !(c == '-' || c == '_' || c == '.' || (c >= '0' && c <= '9') ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
+			err = fmt.Errorf("invalid ConfigMap key '%s'", name)
+			break
+		}
+	}
+	return
+}
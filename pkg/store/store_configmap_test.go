@@ -0,0 +1,53 @@
+package store_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/runner/kubernetes"
+	"github.com/linuxsuren/api-testing/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigMapStore(t *testing.T) {
+	os.Setenv("KUBERNETES_SERVER", urlFoo)
+	os.Setenv("KUBERNETES_TOKEN", "token")
+	gock.InterceptClient(kubernetes.GetClient())
+	defer gock.RestoreClient(http.DefaultClient)
+	defer gock.Off()
+
+	const api = "/api/v1/namespaces/ns/configmaps/suites"
+	cmStore := store.NewConfigMapStore("ns", "suites")
+
+	gock.New(urlFoo).Get(api).
+		MatchHeader("Authorization", "Bearer token").
+		Reply(http.StatusOK).
+		JSON(`{"data":{"b.yaml":"name: b","a.yaml":"name: a"}}`)
+	names, err := cmStore.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.yaml", "b.yaml"}, names)
+
+	gock.New(urlFoo).Get(api).Reply(http.StatusOK).JSON(`{"data":{"a.yaml":"name: a"}}`)
+	data, err := cmStore.Load("a.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "name: a", string(data))
+
+	gock.New(urlFoo).Get(api).Reply(http.StatusOK).JSON(`{"data":{}}`)
+	_, err = cmStore.Load("a.yaml")
+	assert.NotNil(t, err)
+
+	gock.New(urlFoo).Patch(api).Reply(http.StatusNotFound)
+	gock.New(urlFoo).Post("/api/v1/namespaces/ns/configmaps").
+		Reply(http.StatusCreated)
+	err = cmStore.Save("a.yaml", []byte("name: a"))
+	assert.Nil(t, err)
+
+	err = cmStore.Save("sub/a.yaml", []byte("name: a"))
+	assert.NotNil(t, err)
+
+	gock.New(urlFoo).Patch(api).Reply(http.StatusOK)
+	err = cmStore.Delete("a.yaml")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+type databaseStore struct {
+	db     *sql.DB
+	driver string
+	table  string
+}
+
+// NewDatabaseStore creates a store which is based on a SQL database.
+// The drivers are registered by the command, see cmd/drivers.go
+func NewDatabaseStore(driver, dsn, table string) (store Store, err error) {
+	var db *sql.DB
+	if db, err = sql.Open(driver, dsn); err == nil {
+		store, err = NewDatabaseStoreWithDB(db, driver, table)
+	}
+	return
+}
+
+// NewDatabaseStoreWithDB creates a database store with an existing connection,
+// the table will be created if it's not exist.
+func NewDatabaseStoreWithDB(db *sql.DB, driver, table string) (store Store, err error) {
+	table = emptyThenDefault(table, "atest_suites")
+	if !regexp.MustCompile(`^\w+$`).MatchString(table) {
+		err = fmt.Errorf("invalid table name '%s'", table)
+		return
+	}
+
+	s := &databaseStore{db: db, driver: driver, table: table}
+	if _, err = db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (name VARCHAR(255) PRIMARY KEY, content TEXT)`, table)); err == nil {
+		store = s
+	}
+	return
+}
+
+// List returns all the names of the test suites
+func (s *databaseStore) List() (names []string, err error) {
+	var rows *sql.Rows
+	if rows, err = s.db.Query(fmt.Sprintf("SELECT name FROM %s ORDER BY name", s.table)); err != nil {
+		return
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return
+		}
+		names = append(names, name)
+	}
+	err = rows.Err()
+	return
+}
+
+// Load returns the content of the test suite
+func (s *databaseStore) Load(name string) (data []byte, err error) {
+	var content string
+	if err = s.db.QueryRow(s.sql("SELECT content FROM %s WHERE name = ?"), name).Scan(&content); err == nil {
+		data = []byte(content)
+	}
+	return
+}
+
+// Save replaces the test suite in a transaction
+func (s *databaseStore) Save(name string, data []byte) (err error) {
+	var tx *sql.Tx
+	if tx, err = s.db.Begin(); err != nil {
+		return
+	}
+
+	if _, err = tx.Exec(s.sql("DELETE FROM %s WHERE name = ?"), name); err == nil {
+		_, err = tx.Exec(s.sql("INSERT INTO %s (name, content) VALUES (?, ?)"), name, string(data))
+	}
+
+	if err == nil {
+		err = tx.Commit()
+	} else {
+		_ = tx.Rollback()
+	}
+	return
+}
+
+// Delete removes the test suite
+func (s *databaseStore) Delete(name string) (err error) {
+	_, err = s.db.Exec(s.sql("DELETE FROM %s WHERE name = ?"), name)
+	return
+}
+
+// sql fills the table name, and converts the placeholders for PostgreSQL
+func (s *databaseStore) sql(format string) (query string) {
+	query = fmt.Sprintf(format, s.table)
+	switch s.driver {
+	case "postgres", "pgx":
+		index := 0
+		query = regexp.MustCompile(`\?`).ReplaceAllStringFunc(query, func(string) string {
+			index++
+			return fmt.Sprintf("$%d", index)
+		})
+	}
+	return
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+)
+
+type gitStore struct {
+	repo   string
+	branch string
+	dir    string
+	execer fakeruntime.Execer
+	local  *localStore
+	synced bool
+}
+
+// NewGitStore creates a store which is based on a git repository.
+// The repository will be cloned into a temporary directory, and the changes will be pushed back.
+func NewGitStore(repo, branch string, execer fakeruntime.Execer) Store {
+	dir := filepath.Join(os.TempDir(), fmt.Sprintf("atest-git-%x", sha256.Sum256([]byte(repo+branch))))
+	return &gitStore{
+		repo:   repo,
+		branch: branch,
+		dir:    dir,
+		execer: execer,
+		local:  &localStore{dir: dir},
+	}
+}
+
+// List returns all the test suites in the repository
+func (s *gitStore) List() (names []string, err error) {
+	if err = s.sync(); err == nil {
+		names, err = s.local.List()
+	}
+	return
+}
+
+// Load reads the test suite from the repository
+func (s *gitStore) Load(name string) (data []byte, err error) {
+	if err = s.sync(); err == nil {
+		data, err = s.local.Load(name)
+	}
+	return
+}
+
+// Save commits the test suite then pushes it to the remote repository
+func (s *gitStore) Save(name string, data []byte) (err error) {
+	if err = s.sync(); err != nil {
+		return
+	}
+	if err = s.local.Save(name, data); err == nil {
+		err = s.commitAndPush(name, fmt.Sprintf("save test suite %s", name))
+	}
+	return
+}
+
+// Delete removes the test suite then pushes it to the remote repository
+func (s *gitStore) Delete(name string) (err error) {
+	if err = s.sync(); err != nil {
+		return
+	}
+	if err = s.local.Delete(name); err == nil {
+		err = s.commitAndPush(name, fmt.Sprintf("delete test suite %s", name))
+	}
+	return
+}
+
+// GetContext returns the parent directory of the test suite in the local clone
+func (s *gitStore) GetContext(name string) string {
+	return s.local.GetContext(name)
+}
+
+func (s *gitStore) sync() (err error) {
+	if s.synced {
+		return
+	}
+
+	if _, statErr := os.Stat(filepath.Join(s.dir, ".git")); statErr == nil {
+		_, err = s.git("pull")
+	} else {
+		args := []string{"clone", "--depth", "1"}
+		if s.branch != "" {
+			args = append(args, "--branch", s.branch)
+		}
+		_, err = s.execer.RunCommandAndReturn("git", "", append(args, s.repo, s.dir)...)
+	}
+	s.synced = err == nil
+	return
+}
+
+func (s *gitStore) commitAndPush(name, message string) (err error) {
+	if _, err = s.git("add", "--all", name); err == nil {
+		if _, err = s.git("commit", "-m", message); err == nil {
+			_, err = s.git("push")
+		}
+	}
+	return
+}
+
+func (s *gitStore) git(args ...string) (output string, err error) {
+	if output, err = s.execer.RunCommandAndReturn("git", s.dir, args...); err != nil {
+		err = fmt.Errorf("failed to run git %v, %v, %s", args, err, output)
+	}
+	return
+}
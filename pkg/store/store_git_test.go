@@ -0,0 +1,32 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/store"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitStore(t *testing.T) {
+	gitStore := store.NewGitStore("https://foo.com/bar.git", "fake-branch-for-testing",
+		fakeruntime.FakeExecer{ExpectError: errors.New("fake")})
+
+	_, err := gitStore.List()
+	assert.NotNil(t, err)
+
+	_, err = gitStore.Load("simple.yaml")
+	assert.NotNil(t, err)
+
+	err = gitStore.Save("simple.yaml", []byte("name: simple"))
+	assert.NotNil(t, err)
+
+	err = gitStore.Delete("simple.yaml")
+	assert.NotNil(t, err)
+
+	gitStore = store.NewGitStore("https://foo.com/bar.git", "fake-branch-for-testing", fakeruntime.FakeExecer{})
+	names, err := gitStore.List()
+	assert.Nil(t, err)
+	assert.Empty(t, names)
+}
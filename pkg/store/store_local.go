@@ -0,0 +1,101 @@
+package store
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStore struct {
+	dir string
+}
+
+// NewLocalStore creates a store which is based on the local file system
+func NewLocalStore(dir string) Store {
+	if dir == "" {
+		dir = "."
+	}
+	return &localStore{dir: dir}
+}
+
+// List returns all the YAML files under the directory
+func (s *localStore) List() (names []string, err error) {
+	err = filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if path == s.dir && os.IsNotExist(walkErr) {
+				walkErr = nil
+			}
+			return walkErr
+		} else if d.IsDir() || !isSuiteFile(path) {
+			return nil
+		}
+
+		name, relErr := filepath.Rel(s.dir, path)
+		if relErr == nil {
+			names = append(names, filepath.ToSlash(name))
+		}
+		return relErr
+	})
+	return
+}
+
+// Load reads the test suite file
+func (s *localStore) Load(name string) (data []byte, err error) {
+	var target string
+	if target, err = s.getPath(name); err == nil {
+		data, err = os.ReadFile(target)
+	}
+	return
+}
+
+// Save writes the test suite file, the parent directory will be created if it's not exist
+func (s *localStore) Save(name string, data []byte) (err error) {
+	var target string
+	if target, err = s.getSuitePath(name); err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(target), 0755); err == nil {
+		err = os.WriteFile(target, data, 0644)
+	}
+	return
+}
+
+// Delete removes the test suite file
+func (s *localStore) Delete(name string) (err error) {
+	var target string
+	if target, err = s.getSuitePath(name); err == nil {
+		err = os.Remove(target)
+	}
+	return
+}
+
+// GetContext returns the parent directory of the test suite file
+func (s *localStore) GetContext(name string) (dir string) {
+	if target, err := s.getPath(name); err == nil {
+		dir = filepath.Dir(target)
+	}
+	return
+}
+
+// getSuitePath returns the path of the test suite file, only the YAML files can be written or removed
+func (s *localStore) getSuitePath(name string) (target string, err error) {
+	if !isSuiteFile(name) {
+		err = fmt.Errorf("invalid name '%s', it should be a YAML file", name)
+		return
+	}
+	return s.getPath(name)
+}
+
+// getPath returns the path of the file, it's rejected if it's outside of the directory
+func (s *localStore) getPath(name string) (target string, err error) {
+	rel := filepath.Clean(filepath.FromSlash(name))
+	if name == "" || rel == "." || rel == ".." || filepath.IsAbs(rel) ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		err = fmt.Errorf("invalid name '%s'", name)
+		return
+	}
+	target = filepath.Join(s.dir, rel)
+	return
+}
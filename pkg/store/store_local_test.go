@@ -0,0 +1,62 @@
+package store_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	localStore := store.NewLocalStore(dir)
+
+	names, err := localStore.List()
+	assert.Nil(t, err)
+	assert.Empty(t, names)
+
+	err = localStore.Save("sub/simple.yaml", []byte("name: simple"))
+	assert.Nil(t, err)
+
+	names, err = localStore.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sub/simple.yaml"}, names)
+
+	data, err := localStore.Load("sub/simple.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "name: simple", string(data))
+
+	assert.Equal(t, filepath.Join(dir, "sub"), localStore.(store.ContextStore).GetContext("sub/simple.yaml"))
+
+	err = localStore.Delete("sub/simple.yaml")
+	assert.Nil(t, err)
+
+	_, err = localStore.Load("sub/simple.yaml")
+	assert.NotNil(t, err)
+}
+
+func TestLocalStoreInvalidName(t *testing.T) {
+	dir := t.TempDir()
+	outside := filepath.Join(dir, "outside.yaml")
+	assert.Nil(t, os.WriteFile(outside, []byte("name: outside"), 0644))
+	localStore := store.NewLocalStore(filepath.Join(dir, "store"))
+
+	for _, name := range []string{"", "../outside.yaml", "sub/../../outside.yaml", "/etc/outside.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := localStore.Load(name)
+			assert.NotNil(t, err)
+			assert.NotNil(t, localStore.Save(name, []byte("name: invalid")))
+			assert.NotNil(t, localStore.Delete(name))
+			assert.Empty(t, localStore.(store.ContextStore).GetContext(name))
+		})
+	}
+
+	data, err := os.ReadFile(outside)
+	assert.Nil(t, err)
+	assert.Equal(t, "name: outside", string(data))
+
+	assert.NotNil(t, localStore.Save("simple.json", []byte("{}")))
+	assert.NotNil(t, localStore.Delete("simple.json"))
+}
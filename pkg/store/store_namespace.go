@@ -0,0 +1,74 @@
+package store
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+type namespaceStore struct {
+	store     Store
+	namespace string
+}
+
+// NewNamespaceStore creates a store which keeps the test suites in a namespace (sub directory) of another store,
+// the names outside of the namespace are invisible.
+func NewNamespaceStore(store Store, namespace string) Store {
+	return &namespaceStore{store: store, namespace: strings.Trim(namespace, "/")}
+}
+
+// List returns the names under the namespace without the prefix
+func (s *namespaceStore) List() (names []string, err error) {
+	var all []string
+	if all, err = s.store.List(); err == nil {
+		prefix := s.namespace + "/"
+		for _, name := range all {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, strings.TrimPrefix(name, prefix))
+			}
+		}
+	}
+	return
+}
+
+// Load returns the content of the test suite in the namespace
+func (s *namespaceStore) Load(name string) (data []byte, err error) {
+	if name, err = s.getName(name); err == nil {
+		data, err = s.store.Load(name)
+	}
+	return
+}
+
+// Save writes the test suite into the namespace
+func (s *namespaceStore) Save(name string, data []byte) (err error) {
+	if name, err = s.getName(name); err == nil {
+		err = s.store.Save(name, data)
+	}
+	return
+}
+
+// Delete removes the test suite from the namespace
+func (s *namespaceStore) Delete(name string) (err error) {
+	if name, err = s.getName(name); err == nil {
+		err = s.store.Delete(name)
+	}
+	return
+}
+
+// GetContext returns the context of the underlying store
+func (s *namespaceStore) GetContext(name string) (dir string) {
+	if contextStore, ok := s.store.(ContextStore); ok {
+		if name, err := s.getName(name); err == nil {
+			dir = contextStore.GetContext(name)
+		}
+	}
+	return
+}
+
+func (s *namespaceStore) getName(name string) (result string, err error) {
+	result = path.Join(s.namespace, path.Clean("/"+name))
+	if name == "" || !strings.HasPrefix(result, s.namespace+"/") {
+		err = fmt.Errorf("invalid name '%s'", name)
+	}
+	return
+}
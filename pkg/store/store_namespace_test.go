@@ -0,0 +1,44 @@
+package store_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceStore(t *testing.T) {
+	dir := t.TempDir()
+	localStore := store.NewLocalStore(dir)
+	teamA := store.NewNamespaceStore(localStore, "team-a")
+	teamB := store.NewNamespaceStore(localStore, "/team-b/")
+
+	assert.Nil(t, teamA.Save("simple.yaml", []byte("name: a")))
+	assert.Nil(t, teamB.Save("sub/simple.yaml", []byte("name: b")))
+	assert.Nil(t, localStore.Save("root.yaml", []byte("name: root")))
+
+	names, err := teamA.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"simple.yaml"}, names)
+
+	names, err = teamB.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sub/simple.yaml"}, names)
+
+	data, err := teamA.Load("simple.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "name: a", string(data))
+
+	// cannot escape from the namespace
+	data, err = teamA.Load("../team-b/sub/simple.yaml")
+	assert.NotNil(t, err)
+	assert.Empty(t, data)
+	assert.NotNil(t, teamA.Save("", nil))
+
+	assert.Equal(t, filepath.Join(dir, "team-b", "sub"), teamB.(store.ContextStore).GetContext("sub/simple.yaml"))
+
+	assert.Nil(t, teamA.Delete("simple.yaml"))
+	_, err = teamA.Load("simple.yaml")
+	assert.NotNil(t, err)
+}
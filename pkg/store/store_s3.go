@@ -0,0 +1,237 @@
+package store
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+type s3Store struct {
+	bucket       string
+	prefix       string
+	region       string
+	endpoint     string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	client       *http.Client
+	now          func() time.Time
+}
+
+// NewS3Store creates a store which is based on an S3 compatible object storage.
+// The credentials come from the environment variables AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+func NewS3Store(bucket, prefix, region, endpoint string) Store {
+	if region == "" {
+		region = emptyThenDefault(os.Getenv("AWS_REGION"), "us-east-1")
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	return &s3Store{
+		bucket:       bucket,
+		prefix:       strings.Trim(prefix, "/"),
+		region:       region,
+		endpoint:     strings.TrimSuffix(endpoint, "/"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		client:       http.DefaultClient,
+		now:          time.Now,
+	}
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns all the test suites under the prefix
+func (s *s3Store) List() (names []string, err error) {
+	query := url.Values{}
+	query.Set("list-type", "2")
+	if s.prefix != "" {
+		query.Set("prefix", s.prefix+"/")
+	}
+
+	for {
+		var data []byte
+		if data, err = s.do(http.MethodGet, "", query, nil); err != nil {
+			return
+		}
+
+		result := &listBucketResult{}
+		if err = xml.Unmarshal(data, result); err != nil {
+			return
+		}
+
+		for _, item := range result.Contents {
+			if isSuiteFile(item.Key) {
+				names = append(names, strings.TrimPrefix(strings.TrimPrefix(item.Key, s.prefix), "/"))
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+	return
+}
+
+// Load downloads the test suite object
+func (s *s3Store) Load(name string) ([]byte, error) {
+	return s.do(http.MethodGet, s.key(name), nil, nil)
+}
+
+// Save uploads the test suite object
+func (s *s3Store) Save(name string, data []byte) (err error) {
+	_, err = s.do(http.MethodPut, s.key(name), nil, data)
+	return
+}
+
+// Delete removes the test suite object
+func (s *s3Store) Delete(name string) (err error) {
+	_, err = s.do(http.MethodDelete, s.key(name), nil, nil)
+	return
+}
+
+func (s *s3Store) key(name string) string {
+	return path.Join(s.prefix, name)
+}
+
+func (s *s3Store) do(method, key string, query url.Values, body []byte) (data []byte, err error) {
+	objectPath := "/" + s.bucket
+	if key != "" {
+		objectPath += "/" + key
+	}
+
+	api := s.endpoint + uriEncode(objectPath, false)
+	if len(query) > 0 {
+		api += "?" + canonicalQuery(query)
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequest(method, api, bytes.NewReader(body)); err != nil {
+		return
+	}
+	s.sign(req, objectPath, query, body)
+
+	var resp *http.Response
+	if resp, err = s.client.Do(req); err != nil {
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if data, err = io.ReadAll(resp.Body); err == nil && resp.StatusCode >= http.StatusMultipleChoices {
+		err = fmt.Errorf("unexpected status code %d from %s, %s", resp.StatusCode, api, string(data))
+	}
+	return
+}
+
+// sign signs the request with AWS Signature Version 4
+func (s *s3Store) sign(req *http.Request, objectPath string, query url.Values, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("x-amz-security-token", s.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for key := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(req.Header.Get(key))
+	}
+	var headerNames []string
+	for key := range headers {
+		headerNames = append(headerNames, key)
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, key := range headerNames {
+		canonicalHeaders.WriteString(key + ":" + headers[key] + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{req.Method, uriEncode(objectPath, false), canonicalQuery(query),
+		canonicalHeaders.String(), signedHeaders, payloadHash}, "\n")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.region)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	var keys []string
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(query.Get(key), true))
+	}
+	return strings.Join(pairs, "&")
+}
+
+func uriEncode(text string, encodeSlash bool) string {
+	var buf strings.Builder
+	for _, b := range []byte(text) {
+		switch {
+		case (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9'),
+			b == '-', b == '_', b == '.', b == '~':
+			buf.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			buf.WriteByte(b)
+		default:
+			buf.WriteString(fmt.Sprintf("%%%02X", b))
+		}
+	}
+	return buf.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func emptyThenDefault(val, defVal string) string {
+	if val == "" {
+		val = defVal
+	}
+	return val
+}
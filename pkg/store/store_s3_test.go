@@ -0,0 +1,48 @@
+package store_test
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/linuxsuren/api-testing/pkg/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3Store(t *testing.T) {
+	os.Setenv("AWS_ACCESS_KEY_ID", "id")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	defer gock.Off()
+
+	s3Store := store.NewS3Store("bucket", "suites", "us-east-1", urlFoo)
+
+	gock.New(urlFoo).Get("/bucket").
+		MatchParam("list-type", "2").
+		MatchParam("prefix", "suites/").
+		MatchHeader("Authorization", "AWS4-HMAC-SHA256 Credential=id/").
+		Reply(http.StatusOK).
+		BodyString(`<ListBucketResult><Contents><Key>suites/a.yaml</Key></Contents><Contents><Key>suites/b.txt</Key></Contents></ListBucketResult>`)
+	names, err := s3Store.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.yaml"}, names)
+
+	gock.New(urlFoo).Get("/bucket/suites/a.yaml").Reply(http.StatusOK).BodyString("name: a")
+	data, err := s3Store.Load("a.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "name: a", string(data))
+
+	gock.New(urlFoo).Put("/bucket/suites/a.yaml").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			return strings.Contains(req.Header.Get("Authorization"), "SignedHeaders=host;x-amz-content-sha256;x-amz-date"), nil
+		}).Reply(http.StatusOK)
+	err = s3Store.Save("a.yaml", []byte("name: a"))
+	assert.Nil(t, err)
+
+	gock.New(urlFoo).Delete("/bucket/suites/a.yaml").Reply(http.StatusForbidden)
+	err = s3Store.Delete("a.yaml")
+	assert.NotNil(t, err)
+}
+
+const urlFoo = "http://foo"
@@ -0,0 +1,47 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/linuxsuren/api-testing/pkg/store"
+	fakeruntime "github.com/linuxsuren/go-fake-runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStore(t *testing.T) {
+	tests := []struct {
+		name   string
+		uri    string
+		hasErr bool
+	}{{
+		name: "local directory",
+		uri:  "testdata",
+	}, {
+		name: "file scheme",
+		uri:  "file://testdata",
+	}, {
+		name: "git",
+		uri:  "git+https://github.com/linuxsuren/api-testing.git#master",
+	}, {
+		name: "s3",
+		uri:  "s3://bucket/prefix?region=us-east-1",
+	}, {
+		name: "configmap",
+		uri:  "configmap://default/suites",
+	}, {
+		name:   "unknown database driver",
+		uri:    "sql://fake?dsn=fake",
+		hasErr: true,
+	}, {
+		name:   "not supported",
+		uri:    "fake://fake",
+		hasErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := store.NewStore(tt.uri, fakeruntime.FakeExecer{})
+			assert.Equal(t, tt.hasErr, err != nil, err)
+			assert.Equal(t, tt.hasErr, s == nil)
+		})
+	}
+}
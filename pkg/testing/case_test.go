@@ -13,3 +13,133 @@ func TestInScope(t *testing.T) {
 	assert.True(t, testCase.InScope([]string{"foo"}))
 	assert.False(t, testCase.InScope([]string{"bar"}))
 }
+
+func TestInherit(t *testing.T) {
+	suite := &atesting.TestSuite{
+		API:       "http://localhost",
+		Security:  &atesting.Security{},
+		Auth:      &atesting.Auth{},
+		TLS:       &atesting.TLS{},
+		Proxy:     &atesting.Proxy{URL: "http://proxy:3128"},
+		Pool:      &atesting.Pool{MaxIdleConns: 10},
+		Chaos:     &atesting.Chaos{Rate: 0.5},
+		Resolve:   []string{"localhost:80:127.0.0.1"},
+		Protocol:  "http2",
+		ThinkTime: "1s",
+	}
+
+	t.Run("inherit from the suite", func(t *testing.T) {
+		testCase := &atesting.TestCase{Request: atesting.Request{API: "/users"}}
+		suite.Inherit(testCase)
+		assert.Equal(t, "http://localhost/users", testCase.Request.API)
+		assert.Same(t, suite.Security, testCase.Security)
+		assert.Same(t, suite.Auth, testCase.Request.Auth)
+		assert.Same(t, suite.TLS, testCase.Request.TLS)
+		assert.Same(t, suite.Proxy, testCase.Request.Proxy)
+		assert.Same(t, suite.Pool, testCase.Request.Pool)
+		assert.Same(t, suite.Chaos, testCase.Chaos)
+		assert.Equal(t, suite.Resolve, testCase.Request.Resolve)
+		assert.Equal(t, "http2", testCase.Request.Protocol)
+		assert.Equal(t, "1s", testCase.ThinkTime)
+	})
+
+	t.Run("keep the settings of the case", func(t *testing.T) {
+		proxy := &atesting.Proxy{URL: "socks5://proxy:1080"}
+		chaos := &atesting.Chaos{Rate: 1}
+		testCase := &atesting.TestCase{
+			Request: atesting.Request{
+				API:      "http://foo/users",
+				Proxy:    proxy,
+				Resolve:  []string{"foo:80:127.0.0.1"},
+				Protocol: "http1",
+			},
+			Chaos:     chaos,
+			ThinkTime: "2s",
+		}
+		suite.Inherit(testCase)
+		assert.Equal(t, "http://foo/users", testCase.Request.API)
+		assert.Same(t, proxy, testCase.Request.Proxy)
+		assert.Same(t, chaos, testCase.Chaos)
+		assert.Equal(t, []string{"foo:80:127.0.0.1"}, testCase.Request.Resolve)
+		assert.Equal(t, "http1", testCase.Request.Protocol)
+		assert.Equal(t, "2s", testCase.ThinkTime)
+	})
+
+	t.Run("suite API with the trailing slash", func(t *testing.T) {
+		testCase := &atesting.TestCase{Request: atesting.Request{API: "/users"}}
+		(&atesting.TestSuite{API: "http://localhost/"}).Inherit(testCase)
+		assert.Equal(t, "http://localhost/users", testCase.Request.API)
+	})
+}
+
+func TestCleanShouldRun(t *testing.T) {
+	tests := []struct {
+		policy    string
+		onSuccess bool
+		onFailure bool
+	}{
+		{policy: "", onSuccess: true, onFailure: true},
+		{policy: atesting.CleanPolicyAlways, onSuccess: true, onFailure: true},
+		{policy: atesting.CleanPolicyOnSuccess, onSuccess: true, onFailure: false},
+		{policy: atesting.CleanPolicyOnFailure, onSuccess: false, onFailure: true},
+		{policy: atesting.CleanPolicyNever, onSuccess: false, onFailure: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			clean := atesting.Clean{CleanPolicy: tt.policy}
+			assert.Equal(t, tt.onSuccess, clean.ShouldRun(false))
+			assert.Equal(t, tt.onFailure, clean.ShouldRun(true))
+		})
+	}
+}
+
+func TestKubernetesUnmarshal(t *testing.T) {
+	suite, err := atesting.Parse([]byte(`name: kubernetes
+items:
+- name: deploy
+  prepare:
+    kubernetes:
+    - deploy.yaml
+    - file: service.yaml
+      namespace: demo
+      rollout: [deployment/nginx]
+  request:
+    api: http://foo`))
+	if assert.Nil(t, err) {
+		assert.Equal(t, []atesting.Kubernetes{{
+			File: "deploy.yaml",
+		}, {
+			File:      "service.yaml",
+			Namespace: "demo",
+			Rollout:   []string{"deployment/nginx"},
+		}}, suite.Items[0].Prepare.Kubernetes)
+	}
+}
+
+func TestDockerComposeUnmarshal(t *testing.T) {
+	suite, err := atesting.Parse([]byte(`name: compose
+items:
+- name: up
+  prepare:
+    dockerCompose:
+    - compose.yaml
+    - file: db.yaml
+      policy:
+        timeout: 2m
+        retry: 3
+        backoff: 5s
+  request:
+    api: http://foo`))
+	if assert.Nil(t, err) {
+		assert.Equal(t, []atesting.DockerCompose{{
+			File: "compose.yaml",
+		}, {
+			File: "db.yaml",
+			Policy: &atesting.StepPolicy{
+				Timeout: "2m",
+				Retry:   3,
+				Backoff: "5s",
+			},
+		}}, suite.Items[0].Prepare.DockerCompose)
+	}
+}
@@ -0,0 +1,94 @@
+package testing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortByDependencies returns the test cases in the topological order of the dependsOn, the order of
+// the file is kept for the independent ones. It returns an error if a dependency is not found, or
+// there is a cycle of the dependencies.
+func SortByDependencies(items []TestCase) (sorted []TestCase, err error) {
+	names := make(map[string]bool, len(items))
+	for _, item := range items {
+		names[item.Name] = true
+	}
+	for _, item := range items {
+		for _, dependency := range item.DependsOn {
+			if !names[dependency] {
+				err = fmt.Errorf("the dependency '%s' of '%s' is not found", dependency, item.Name)
+				return
+			}
+		}
+	}
+
+	placed := make(map[string]bool, len(items))
+	sorted = make([]TestCase, 0, len(items))
+	for len(sorted) < len(items) {
+		progress := false
+		for _, item := range items {
+			if placed[item.Name] || !allPlaced(item.DependsOn, placed) {
+				continue
+			}
+			placed[item.Name] = true
+			sorted = append(sorted, item)
+			progress = true
+		}
+
+		if !progress {
+			var cycle []string
+			for _, item := range items {
+				if !placed[item.Name] {
+					cycle = append(cycle, item.Name)
+				}
+			}
+			err = fmt.Errorf("there is a cycle of the dependencies among: %s", strings.Join(cycle, ", "))
+			return
+		}
+	}
+	return
+}
+
+// ExpandDependencies returns the names with the dependencies of them, it's empty if the names are empty
+func ExpandDependencies(items []TestCase, names []string) (expanded []string) {
+	dependencies := make(map[string][]string, len(items))
+	for _, item := range items {
+		dependencies[item.Name] = item.DependsOn
+	}
+
+	visited := map[string]bool{}
+	var visit func(string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		expanded = append(expanded, name)
+		for _, dependency := range dependencies[name] {
+			visit(dependency)
+		}
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return
+}
+
+// HasDependencies returns true if any of the test cases declares the dependsOn
+func HasDependencies(items []TestCase) bool {
+	for _, item := range items {
+		if len(item.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func allPlaced(names []string, placed map[string]bool) bool {
+	for _, name := range names {
+		if !placed[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package testing_test
+
+import (
+	"testing"
+
+	atesting "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortByDependencies(t *testing.T) {
+	tests := []struct {
+		name   string
+		items  []atesting.TestCase
+		expect []string
+		err    string
+	}{{
+		name:   "no dependencies",
+		items:  []atesting.TestCase{{Name: "a"}, {Name: "b"}},
+		expect: []string{"a", "b"},
+	}, {
+		name: "dependencies",
+		items: []atesting.TestCase{
+			{Name: "order", DependsOn: []string{"login", "user"}},
+			{Name: "user", DependsOn: []string{"login"}},
+			{Name: "health"},
+			{Name: "login"},
+		},
+		expect: []string{"health", "login", "user", "order"},
+	}, {
+		name:  "dependency not found",
+		items: []atesting.TestCase{{Name: "a", DependsOn: []string{"fake"}}},
+		err:   "the dependency 'fake' of 'a' is not found",
+	}, {
+		name: "cycle",
+		items: []atesting.TestCase{
+			{Name: "a", DependsOn: []string{"b"}},
+			{Name: "b", DependsOn: []string{"a"}},
+			{Name: "c"},
+		},
+		err: "there is a cycle of the dependencies among: a, b",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := atesting.SortByDependencies(tt.items)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.Nil(t, err)
+			names := make([]string, 0, len(sorted))
+			for _, item := range sorted {
+				names = append(names, item.Name)
+			}
+			assert.Equal(t, tt.expect, names)
+		})
+	}
+}
+
+func TestExpandDependencies(t *testing.T) {
+	items := []atesting.TestCase{
+		{Name: "login"},
+		{Name: "user", DependsOn: []string{"login"}},
+		{Name: "order", DependsOn: []string{"user"}},
+		{Name: "health"},
+	}
+	assert.Nil(t, atesting.ExpandDependencies(items, nil))
+	assert.Equal(t, []string{"order", "user", "login"}, atesting.ExpandDependencies(items, []string{"order"}))
+	assert.Equal(t, []string{"health"}, atesting.ExpandDependencies(items, []string{"health"}))
+
+	assert.True(t, atesting.HasDependencies(items))
+	assert.False(t, atesting.HasDependencies(items[:1]))
+}
@@ -0,0 +1,46 @@
+package testing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+)
+
+// EnvKey is the key of the environment values in the template context, they're referenced as {{.env.<key>}}
+const EnvKey = "env"
+
+// EnvironmentFile returns the file of the named environment in the env directory of the test suite,
+// the extension is one of .yaml, .yml and .json
+func EnvironmentFile(dir, name string) string {
+	for _, ext := range []string{".yaml", ".yml", ".json"} {
+		file := filepath.Join(dir, "env", name+ext)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	return filepath.Join(dir, "env", name+".yaml")
+}
+
+// LoadEnvironment reads the key/values of the environment files, the latter ones override the former ones
+func LoadEnvironment(files ...string) (env map[string]interface{}, err error) {
+	env = map[string]interface{}{}
+	for _, file := range files {
+		var data []byte
+		if data, err = os.ReadFile(file); err != nil {
+			err = fmt.Errorf("failed to read the environment file: %v", err)
+			return
+		}
+
+		values := map[string]interface{}{}
+		if err = yaml.Unmarshal(data, &values); err != nil {
+			err = fmt.Errorf("invalid environment file %s: %v", file, err)
+			return
+		}
+		for key, val := range values {
+			env[key] = val
+		}
+	}
+	return
+}
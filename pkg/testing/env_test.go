@@ -0,0 +1,39 @@
+package testing_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	atest "github.com/linuxsuren/api-testing/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironmentFile(t *testing.T) {
+	assert.Equal(t, filepath.Join("testdata", "env", "dev.yaml"), atest.EnvironmentFile("testdata", "dev"))
+	assert.Equal(t, filepath.Join("testdata", "env", "prod.json"), atest.EnvironmentFile("testdata", "prod"))
+	assert.Equal(t, filepath.Join("testdata", "env", "fake.yaml"), atest.EnvironmentFile("testdata", "fake"))
+}
+
+func TestLoadEnvironment(t *testing.T) {
+	env, err := atest.LoadEnvironment("testdata/env/dev.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"api":  "http://localhost:8080",
+		"user": map[string]interface{}{"name": "admin"},
+	}, env)
+
+	// the latter file overrides the former one
+	env, err = atest.LoadEnvironment("testdata/env/dev.yaml", "testdata/env/prod.json")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"api":     "https://api.example.com",
+		"timeout": float64(30),
+		"user":    map[string]interface{}{"name": "admin"},
+	}, env)
+
+	_, err = atest.LoadEnvironment("testdata/env/fake.yaml")
+	assert.ErrorContains(t, err, "failed to read the environment file")
+
+	_, err = atest.LoadEnvironment("testdata/env/invalid.yaml")
+	assert.ErrorContains(t, err, "invalid environment file")
+}
@@ -2,13 +2,17 @@ package testing
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"os"
 	"path"
+	"sort"
 	"strings"
 
 	"github.com/ghodss/yaml"
@@ -60,6 +64,27 @@ func ParseFromData(data []byte) (testSuite *TestSuite, err error) {
 	return
 }
 
+// suiteNames is the minimal part of a test suite which only holds the names
+type suiteNames struct {
+	Name  string `json:"name"`
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+}
+
+// ParseNames parses the names of the test suite and its test cases only,
+// it skips the schema validation and the full decoding, such as for the shell completion
+func ParseNames(data []byte) (suiteName string, caseNames []string, err error) {
+	names := &suiteNames{}
+	if err = yaml.Unmarshal(data, names); err == nil {
+		suiteName = names.Name
+		for _, item := range names.Items {
+			caseNames = append(caseNames, item.Name)
+		}
+	}
+	return
+}
+
 // ParseTestCaseFromData parses the data to a test case
 func ParseTestCaseFromData(data []byte) (testCase *TestCase, err error) {
 	testCase = &TestCase{}
@@ -103,6 +128,70 @@ func (r *Request) Render(ctx interface{}, dataDir string) (err error) {
 		return
 	}
 
+	if r.GraphQL != nil {
+		if err = r.renderGraphQL(ctx); err != nil {
+			return
+		}
+	}
+	if r.JSONRPC != nil {
+		if err = r.renderJSONRPC(ctx); err != nil {
+			return
+		}
+	}
+	if r.SOAP != nil {
+		if err = r.renderSOAP(ctx); err != nil {
+			return
+		}
+	}
+
+	// template the messages of the WebSocket, the steps are copied since they're shared by the runs of a test case
+	if r.WebSocket != nil {
+		webSocket := *r.WebSocket
+		webSocket.Steps = make([]WebSocketStep, len(r.WebSocket.Steps))
+		for i, step := range r.WebSocket.Steps {
+			if step.Send, err = render.Render("websocket message", step.Send, ctx); err != nil {
+				return
+			}
+			webSocket.Steps[i] = step
+		}
+		r.WebSocket = &webSocket
+	}
+
+	// template the credential of the proxy, it's copied since it may be shared with the other test cases
+	if r.Proxy != nil {
+		proxy := *r.Proxy
+		for _, field := range []*string{&proxy.Username, &proxy.Password} {
+			if *field, err = render.Render("proxy", *field, ctx); err != nil {
+				return
+			}
+		}
+		r.Proxy = &proxy
+	}
+
+	// template the credential of the MQTT, it's copied since it's shared by the runs of a test case
+	if r.MQTT != nil {
+		mqtt := *r.MQTT
+		for _, field := range []*string{&mqtt.Username, &mqtt.Password} {
+			if *field, err = render.Render("mqtt", *field, ctx); err != nil {
+				return
+			}
+		}
+		r.MQTT = &mqtt
+	}
+
+	// resolve the paths of the form files, they're copied since they're shared by the runs of a test case
+	if len(r.FormFiles) > 0 {
+		files := make(map[string]FormFile, len(r.FormFiles))
+		for key, file := range r.FormFiles {
+			file.resolved = file.Path
+			if !path.IsAbs(file.Path) {
+				file.resolved = path.Join(dataDir, file.Path)
+			}
+			files[key] = file
+		}
+		r.FormFiles = files
+	}
+
 	// template the form
 	for key, val := range r.Form {
 		if result, err = render.Render("form", val, ctx); err == nil {
@@ -113,19 +202,204 @@ func (r *Request) Render(ctx interface{}, dataDir string) (err error) {
 	}
 
 	// setting default values
+	if _, ok := r.Header["Accept"]; !ok && r.SSE != nil {
+		if r.Header == nil {
+			r.Header = map[string]string{}
+		}
+		r.Header["Accept"] = "text/event-stream"
+	}
+	if _, ok := r.Header[util.ContentType]; !ok && len(r.FormFiles) > 0 {
+		if r.Header == nil {
+			r.Header = map[string]string{}
+		}
+		r.Header[util.ContentType] = util.MultiPartFormData
+	}
+	if r.Protobuf != nil {
+		if r.Header == nil {
+			r.Header = map[string]string{}
+		}
+		if _, ok := r.Header[util.ContentType]; !ok && r.Protobuf.Request != "" {
+			r.Header[util.ContentType] = util.Protobuf
+		}
+		if _, ok := r.Header["Accept"]; !ok && r.Protobuf.Response != "" {
+			r.Header["Accept"] = util.Protobuf
+		}
+	}
+	if r.GraphQL != nil || r.JSONRPC != nil || r.SOAP != nil {
+		r.Method = EmptyThenDefault(r.Method, http.MethodPost)
+	}
 	r.Method = EmptyThenDefault(r.Method, http.MethodGet)
 	return
 }
 
+// renderGraphQL renders the query and the variables, then takes the JSON payload of them as the body
+func (r *Request) renderGraphQL(ctx interface{}) (err error) {
+	payload := map[string]interface{}{}
+	if payload["query"], err = render.Render("graphql query", r.GraphQL.Query, ctx); err != nil {
+		return
+	}
+	if r.GraphQL.OperationName != "" {
+		payload["operationName"] = r.GraphQL.OperationName
+	}
+	if len(r.GraphQL.Variables) > 0 {
+		if payload["variables"], err = render.RenderValue("graphql variable", r.GraphQL.Variables, ctx); err != nil {
+			return
+		}
+	}
+
+	var data []byte
+	if data, err = json.Marshal(payload); err != nil {
+		return
+	}
+	r.Body = string(data)
+
+	if r.Header == nil {
+		r.Header = map[string]string{}
+	}
+	if _, ok := r.Header[util.ContentType]; !ok {
+		r.Header[util.ContentType] = util.JSON
+	}
+	return
+}
+
+// renderJSONRPC renders the params of the calls, then takes the JSON payload of them as the body.
+// The JSON-RPC is copied with the assigned ids since it's shared by the runs of a test case
+func (r *Request) renderJSONRPC(ctx interface{}) (err error) {
+	jsonRPC := *r.JSONRPC
+	var payload interface{}
+	if len(jsonRPC.Batch) == 0 {
+		call := JSONRPCCall{Method: jsonRPC.Method, Params: jsonRPC.Params, ID: jsonRPC.ID, Notification: jsonRPC.Notification}
+		if payload, err = renderJSONRPCCall(&call, 1, ctx); err != nil {
+			return
+		}
+		jsonRPC.ID = call.ID
+	} else {
+		calls := make([]interface{}, len(jsonRPC.Batch))
+		jsonRPC.Batch = make([]JSONRPCCall, len(r.JSONRPC.Batch))
+		for i, call := range r.JSONRPC.Batch {
+			if calls[i], err = renderJSONRPCCall(&call, i+1, ctx); err != nil {
+				return
+			}
+			jsonRPC.Batch[i] = call
+		}
+		payload = calls
+	}
+	r.JSONRPC = &jsonRPC
+
+	var data []byte
+	if data, err = json.Marshal(payload); err != nil {
+		return
+	}
+	r.Body = string(data)
+
+	if r.Header == nil {
+		r.Header = map[string]string{}
+	}
+	if _, ok := r.Header[util.ContentType]; !ok {
+		r.Header[util.ContentType] = util.JSON
+	}
+	return
+}
+
+// renderJSONRPCCall assigns the id of the call if it's empty, the notification doesn't have an id
+func renderJSONRPCCall(call *JSONRPCCall, id int, ctx interface{}) (payload map[string]interface{}, err error) {
+	if call.Method == "" {
+		err = fmt.Errorf("the method of the JSON-RPC call is empty")
+		return
+	}
+
+	payload = map[string]interface{}{"jsonrpc": "2.0", "method": call.Method}
+	if call.Params != nil {
+		if payload["params"], err = render.RenderValue("jsonrpc params", call.Params, ctx); err != nil {
+			return
+		}
+	}
+	if call.Notification {
+		call.ID = nil
+	} else {
+		if call.ID == nil {
+			call.ID = id
+		}
+		payload["id"] = call.ID
+	}
+	return
+}
+
+const (
+	// SOAP11 is the default version of the SOAP envelope
+	SOAP11 = "1.1"
+	// SOAP12 is the version which takes the action as a parameter of the content type
+	SOAP12 = "1.2"
+)
+
+var soapNamespaces = map[string]string{
+	SOAP11: "http://schemas.xmlsoap.org/soap/envelope/",
+	SOAP12: "http://www.w3.org/2003/05/soap-envelope",
+}
+
+// renderSOAP renders the header and the body, then wraps them with the envelope of the version as the body.
+// The content type and the action are set unless they're in the header of the request
+func (r *Request) renderSOAP(ctx interface{}) (err error) {
+	version := EmptyThenDefault(r.SOAP.Version, SOAP11)
+	namespace, ok := soapNamespaces[version]
+	if !ok {
+		err = fmt.Errorf("not supported SOAP version: %s, it should be %s or %s", version, SOAP11, SOAP12)
+		return
+	}
+
+	var header, body string
+	if header, err = render.Render("soap header", r.SOAP.Header, ctx); err != nil {
+		return
+	}
+	if body, err = render.Render("soap body", r.SOAP.Body, ctx); err != nil {
+		return
+	}
+
+	envelope := new(strings.Builder)
+	fmt.Fprintf(envelope, `<?xml version="1.0" encoding="UTF-8"?><soap:Envelope xmlns:soap="%s">`, namespace)
+	if header != "" {
+		fmt.Fprintf(envelope, "<soap:Header>%s</soap:Header>", header)
+	}
+	fmt.Fprintf(envelope, "<soap:Body>%s</soap:Body></soap:Envelope>", body)
+	r.Body = envelope.String()
+
+	if r.Header == nil {
+		r.Header = map[string]string{}
+	}
+	contentType := "text/xml; charset=utf-8"
+	if version == SOAP12 {
+		contentType = "application/soap+xml; charset=utf-8"
+		if r.SOAP.Action != "" {
+			contentType += fmt.Sprintf(`; action="%s"`, r.SOAP.Action)
+		}
+	} else if _, ok := r.Header["SOAPAction"]; !ok {
+		r.Header["SOAPAction"] = fmt.Sprintf(`"%s"`, r.SOAP.Action)
+	}
+	if _, ok := r.Header[util.ContentType]; !ok {
+		r.Header[util.ContentType] = contentType
+	}
+	return
+}
+
 // GetBody returns the request body
 func (r *Request) GetBody() (reader io.Reader, err error) {
-	if len(r.Form) > 0 {
-		if r.Header[util.ContentType] == util.MultiPartFormData {
+	if len(r.Form) > 0 || len(r.FormFiles) > 0 {
+		// the boundary is in the Content-Type if the body has been built
+		isMultipart := strings.HasPrefix(r.Header[util.ContentType], util.MultiPartFormData)
+		if len(r.FormFiles) > 0 && !isMultipart {
+			err = fmt.Errorf("the form files need the Content-Type %s, actual: %s", util.MultiPartFormData, r.Header[util.ContentType])
+			return
+		}
+
+		if isMultipart {
 			multiBody := &bytes.Buffer{}
 			writer := multipart.NewWriter(multiBody)
 			for key, val := range r.Form {
 				writer.WriteField(key, val)
 			}
+			if err = writeFormFiles(writer, r.FormFiles); err != nil {
+				return
+			}
 
 			_ = writer.Close()
 			reader = multiBody
@@ -148,6 +422,53 @@ func (r *Request) GetBody() (reader io.Reader, err error) {
 	return
 }
 
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// writeFormFiles writes the files into the parts in the order of the field names
+func writeFormFiles(writer *multipart.Writer, files map[string]FormFile) (err error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		file := files[name]
+		filePath := file.resolved
+		if filePath == "" {
+			filePath = file.Path
+		}
+
+		var data []byte
+		if data, err = os.ReadFile(filePath); err != nil {
+			return
+		}
+
+		fileName := file.FileName
+		if fileName == "" {
+			fileName = path.Base(file.Path)
+		}
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = EmptyThenDefault(mime.TypeByExtension(path.Ext(file.Path)), "application/octet-stream")
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(name), quoteEscaper.Replace(fileName)))
+		header.Set(util.ContentType, contentType)
+
+		var part io.Writer
+		if part, err = writer.CreatePart(header); err != nil {
+			return
+		}
+		if _, err = part.Write(data); err != nil {
+			return
+		}
+	}
+	return
+}
+
 // Render renders the response
 func (r *Response) Render(ctx interface{}) (err error) {
 	r.StatusCode = ZeroThenDefault(r.StatusCode, http.StatusOK)
@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	_ "embed"
@@ -61,6 +62,21 @@ func TestDuplicatedNames(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestParseNames(t *testing.T) {
+	data, err := os.ReadFile("../../sample/testsuite-gitlab.yaml")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	suiteName, caseNames, err := atest.ParseNames(data)
+	assert.Nil(t, err)
+	assert.Equal(t, "Gitlab", suiteName)
+	assert.Equal(t, []string{"projects", "project"}, caseNames)
+
+	_, _, err = atest.ParseNames([]byte("fake"))
+	assert.NotNil(t, err)
+}
+
 func TestRequestRender(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -160,6 +176,152 @@ func TestRequestRender(t *testing.T) {
 			assert.Equal(t, "linuxsuren", req.Header["key"])
 		},
 		hasErr: false,
+	}, {
+		name: "graphql",
+		request: &atest.Request{
+			GraphQL: &atest.GraphQL{
+				Query: "query { user(name: \"{{.Name}}\") { id } }",
+				Variables: map[string]interface{}{
+					"filter": map[string]interface{}{"names": []interface{}{"{{.Name}}"}},
+					"limit":  float64(10),
+				},
+			},
+		},
+		ctx: atest.TestCase{Name: "linuxsuren"},
+		verify: func(t *testing.T, req *atest.Request) {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, util.JSON, req.Header[util.ContentType])
+			assert.JSONEq(t, `{"query":"query { user(name: \"linuxsuren\") { id } }",
+				"variables":{"filter":{"names":["linuxsuren"]},"limit":10}}`, req.Body)
+		},
+	}, {
+		name: "jsonrpc",
+		request: &atest.Request{
+			JSONRPC: &atest.JSONRPC{
+				Method: "eth_getBalance",
+				Params: []interface{}{"{{.Name}}", "latest"},
+			},
+		},
+		ctx: atest.TestCase{Name: "linuxsuren"},
+		verify: func(t *testing.T, req *atest.Request) {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, util.JSON, req.Header[util.ContentType])
+			assert.Equal(t, 1, req.JSONRPC.ID)
+			assert.JSONEq(t, `{"jsonrpc":"2.0","method":"eth_getBalance","params":["linuxsuren","latest"],"id":1}`, req.Body)
+		},
+	}, {
+		name: "jsonrpc batch",
+		request: &atest.Request{
+			JSONRPC: &atest.JSONRPC{
+				Batch: []atest.JSONRPCCall{{
+					Method: "sum",
+					Params: map[string]interface{}{"a": float64(1)},
+				}, {
+					Method: "getUser",
+					ID:     "user",
+				}, {
+					Method:       "notify",
+					Notification: true,
+				}},
+			},
+		},
+		verify: func(t *testing.T, req *atest.Request) {
+			assert.Equal(t, 1, req.JSONRPC.Batch[0].ID)
+			assert.Equal(t, "user", req.JSONRPC.Batch[1].ID)
+			assert.Nil(t, req.JSONRPC.Batch[2].ID)
+			assert.JSONEq(t, `[{"jsonrpc":"2.0","method":"sum","params":{"a":1},"id":1},
+				{"jsonrpc":"2.0","method":"getUser","id":"user"},{"jsonrpc":"2.0","method":"notify"}]`, req.Body)
+		},
+	}, {
+		name: "jsonrpc without method",
+		request: &atest.Request{
+			JSONRPC: &atest.JSONRPC{},
+		},
+		hasErr: true,
+	}, {
+		name: "soap",
+		request: &atest.Request{
+			SOAP: &atest.SOAP{
+				Action: "urn:users#GetUser",
+				Body:   "<GetUser><Name>{{.Name}}</Name></GetUser>",
+			},
+		},
+		ctx: atest.TestCase{Name: "linuxsuren"},
+		verify: func(t *testing.T, req *atest.Request) {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "text/xml; charset=utf-8", req.Header[util.ContentType])
+			assert.Equal(t, `"urn:users#GetUser"`, req.Header["SOAPAction"])
+			assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">`+
+				`<soap:Body><GetUser><Name>linuxsuren</Name></GetUser></soap:Body></soap:Envelope>`, req.Body)
+		},
+	}, {
+		name: "soap 1.2",
+		request: &atest.Request{
+			SOAP: &atest.SOAP{
+				Action:  "urn:users#GetUser",
+				Version: atest.SOAP12,
+				Header:  "<Token>{{.Name}}</Token>",
+				Body:    "<GetUser/>",
+			},
+		},
+		ctx: atest.TestCase{Name: "linuxsuren"},
+		verify: func(t *testing.T, req *atest.Request) {
+			assert.Equal(t, `application/soap+xml; charset=utf-8; action="urn:users#GetUser"`, req.Header[util.ContentType])
+			assert.Empty(t, req.Header["SOAPAction"])
+			assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?><soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope">`+
+				`<soap:Header><Token>linuxsuren</Token></soap:Header><soap:Body><GetUser/></soap:Body></soap:Envelope>`, req.Body)
+		},
+	}, {
+		name: "protobuf",
+		request: &atest.Request{
+			Protobuf: &atest.Protobuf{Request: "example.v1.CreateUserRequest", Response: "example.v1.User"},
+		},
+		verify: func(t *testing.T, req *atest.Request) {
+			assert.Equal(t, util.Protobuf, req.Header[util.ContentType])
+			assert.Equal(t, util.Protobuf, req.Header["Accept"])
+		},
+	}, {
+		name: "protobuf with the JSON response",
+		request: &atest.Request{
+			Header:   map[string]string{"Accept": util.JSON},
+			Protobuf: &atest.Protobuf{Request: "example.v1.CreateUserRequest"},
+		},
+		verify: func(t *testing.T, req *atest.Request) {
+			assert.Equal(t, util.Protobuf, req.Header[util.ContentType])
+			assert.Equal(t, util.JSON, req.Header["Accept"])
+		},
+	}, {
+		name: "form files",
+		request: &atest.Request{
+			FormFiles: map[string]atest.FormFile{"file": {Path: "generic_body.json"}},
+		},
+		verify: func(t *testing.T, req *atest.Request) {
+			assert.Equal(t, util.MultiPartFormData, req.Header[util.ContentType])
+
+			// the path is relative to the directory of the test suite, it's resolved again in the repeated runs
+			assert.Nil(t, req.Render(nil, "testdata"))
+			assert.Nil(t, req.Render(nil, "testdata"))
+			reader, err := req.GetBody()
+			assert.Nil(t, err)
+			data, err := io.ReadAll(reader)
+			assert.Nil(t, err)
+			assert.Contains(t, string(data), `filename="generic_body.json"`)
+			assert.True(t, strings.HasPrefix(req.Header[util.ContentType], util.MultiPartFormData+"; boundary="))
+		},
+	}, {
+		name: "invalid soap version",
+		request: &atest.Request{
+			SOAP: &atest.SOAP{Version: "2.0"},
+		},
+		hasErr: true,
+	}, {
+		name: "invalid graphql variable",
+		request: &atest.Request{
+			GraphQL: &atest.GraphQL{
+				Variables: map[string]interface{}{"name": "{{.name}"},
+			},
+		},
+		hasErr: true,
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -271,6 +433,51 @@ func TestGetBody(t *testing.T) {
 			},
 		},
 		containBody: "name=\"key\"\r\n\r\nvalue\r\n",
+	}, {
+		name: "multipart form files",
+		req: &atest.Request{
+			Header: map[string]string{
+				util.ContentType: util.MultiPartFormData,
+			},
+			FormFiles: map[string]atest.FormFile{
+				"file": {Path: "testdata/generic_body.json"},
+			},
+		},
+		containBody: "Content-Disposition: form-data; name=\"file\"; filename=\"generic_body.json\"\r\n" +
+			"Content-Type: application/json\r\n\r\n{\"name\": \"{{.Name}}\"}",
+	}, {
+		name: "multipart form file with the content type",
+		req: &atest.Request{
+			Header: map[string]string{
+				util.ContentType: util.MultiPartFormData,
+			},
+			FormFiles: map[string]atest.FormFile{
+				"avatar": {Path: "testdata/generic_body.json", ContentType: "text/plain", FileName: "avatar.txt"},
+			},
+		},
+		containBody: "filename=\"avatar.txt\"\r\nContent-Type: text/plain\r\n",
+	}, {
+		name: "form files without multipart",
+		req: &atest.Request{
+			Header: map[string]string{
+				util.ContentType: util.Form,
+			},
+			FormFiles: map[string]atest.FormFile{
+				"file": {Path: "testdata/generic_body.json"},
+			},
+		},
+		expectErr: true,
+	}, {
+		name: "form file not found",
+		req: &atest.Request{
+			Header: map[string]string{
+				util.ContentType: util.MultiPartFormData,
+			},
+			FormFiles: map[string]atest.FormFile{
+				"file": {Path: "testdata/fake"},
+			},
+		},
+		expectErr: true,
 	}, {
 		name: "normal form",
 		req: &atest.Request{
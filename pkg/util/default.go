@@ -25,4 +25,6 @@ const (
 	ContentType       = "Content-Type"
 	MultiPartFormData = "multipart/form-data"
 	Form              = "application/x-www-form-urlencoded"
+	JSON              = "application/json"
+	Protobuf          = "application/x-protobuf"
 )